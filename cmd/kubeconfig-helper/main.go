@@ -0,0 +1,57 @@
+// rancher-kubeconfig-helper is the client-go exec credential plugin of kubeconfigs generated by Rancher in the Exec mode.
+// It prints an ExecCredential with a short-lived token for the kubeconfig, see the exechelper package.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/rancher/rancher/pkg/kubeconfig/exechelper"
+)
+
+var (
+	VERSION = "dev"
+)
+
+func main() {
+	var cfg exechelper.Config
+	flag.StringVar(&cfg.Server, "server", "", "URL of the Rancher server")
+	flag.StringVar(&cfg.Kubeconfig, "kubeconfig", "", "name of the kubeconfig to request tokens for")
+	flag.StringVar(&cfg.Cluster, "cluster", "", "name of the cluster with an authorized cluster endpoint to scope tokens to")
+	flag.StringVar(&cfg.AuthProvider, "auth-provider", "", "auth provider to log in with")
+	flag.StringVar(&cfg.CacheDir, "cache-dir", "", "directory to cache the session and tokens in")
+	version := flag.Bool("version", false, "print the version and exit")
+	flag.Parse()
+
+	if *version {
+		fmt.Println(VERSION)
+		return
+	}
+
+	cfg.CACerts = os.Getenv("RANCHER_CACERTS")
+	cfg.SessionToken = os.Getenv("RANCHER_TOKEN")
+
+	if err := run(cfg); err != nil {
+		fmt.Fprintf(os.Stderr, "rancher-kubeconfig-helper: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(cfg exechelper.Config) error {
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+
+	helper, err := exechelper.New(cfg)
+	if err != nil {
+		return err
+	}
+	credential, err := helper.Credential(ctx)
+	if err != nil {
+		return err
+	}
+	return json.NewEncoder(os.Stdout).Encode(credential)
+}
@@ -29,6 +29,7 @@
 func main() {
 	management.RegisterPasswordResetCommand()
 	management.RegisterEnsureDefaultAdminCommand()
+	management.RegisterSecretBackendMigrationCommand()
 	if reexec.Init() {
 		return
 	}
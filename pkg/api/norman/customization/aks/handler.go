@@ -23,6 +23,7 @@
 	"github.com/rancher/rancher/pkg/namespace"
 	"github.com/rancher/rancher/pkg/ref"
 	schema "github.com/rancher/rancher/pkg/schemas/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/secretbackend"
 	"github.com/rancher/rancher/pkg/types/config"
 	"github.com/sirupsen/logrus"
 )
@@ -244,6 +245,10 @@ func (h *handler) getCloudCredential(req *http.Request, cap *Capabilities, credI
 		logrus.Errorf("[AKS] error accessing cloud credential %s", credID)
 		return httperror.InvalidBodyContent.Status, fmt.Errorf("error accessing cloud credential %s", credID)
 	}
+	if cc, err = secretbackend.Resolve(req.Context(), cc); err != nil {
+		logrus.Errorf("[AKS] error reading cloud credential %s: %v", credID, err)
+		return httperror.ServerError.Status, fmt.Errorf("error reading cloud credential %s", credID)
+	}
 	cap.TenantID = string(cc.Data["azurecredentialConfig-tenantId"])
 	cap.SubscriptionID = string(cc.Data["azurecredentialConfig-subscriptionId"])
 	cap.ClientID = string(cc.Data["azurecredentialConfig-clientId"])
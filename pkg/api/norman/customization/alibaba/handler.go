@@ -25,6 +25,7 @@
 	v1 "github.com/rancher/rancher/pkg/generated/norman/core/v1"
 	"github.com/rancher/rancher/pkg/namespace"
 	"github.com/rancher/rancher/pkg/ref"
+	"github.com/rancher/rancher/pkg/secretbackend"
 	"github.com/rancher/rancher/pkg/types/config"
 	"github.com/sirupsen/logrus"
 	corev1 "k8s.io/api/core/v1"
@@ -366,6 +367,10 @@ func (h *handler) getCloudCredential(req *http.Request, credID string) (*corev1.
 		logrus.Errorf("[alibaba-handler] error accessing cloud credential %s", credID)
 		return nil, httperror.InvalidBodyContent.Status, fmt.Errorf("error accessing cloud credential %s", credID)
 	}
+	if cc, err = secretbackend.Resolve(req.Context(), cc); err != nil {
+		logrus.Errorf("[alibaba-handler] error reading cloud credential %s: %v", credID, err)
+		return nil, httperror.ServerError.Status, fmt.Errorf("error reading cloud credential %s", credID)
+	}
 
 	return cc, http.StatusOK, nil
 }
@@ -25,6 +25,7 @@
 	"github.com/rancher/rancher/pkg/kontainer-engine/service"
 	"github.com/rancher/rancher/pkg/ref"
 	mgmtSchema "github.com/rancher/rancher/pkg/schemas/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/secretbackend"
 	"github.com/sirupsen/logrus"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -427,13 +428,20 @@ func (v *Validator) eksAccountID(secretRef, region string) string {
 	if ns == "" || name == "" {
 		return ""
 	}
+	ctx, cancel := context.WithTimeout(context.Background(), stsAccountIDTimeout)
+	defer cancel()
 	secret, err := v.SecretLister.Get(ns, name)
 	if err != nil {
 		logrus.Warnf("EKS duplicate validation: failed to get cloud credential %s/%s: %v", ns, name, err)
 		return ""
 	}
-	accessKey := string(secret.Data["amazonec2credentialConfig-accessKey"])
-	secretKey := string(secret.Data["amazonec2credentialConfig-secretKey"])
+	data, err := secretbackend.Data(ctx, secret)
+	if err != nil {
+		logrus.Warnf("EKS duplicate validation: failed to read cloud credential %s/%s: %v", ns, name, err)
+		return ""
+	}
+	accessKey := string(data["amazonec2credentialConfig-accessKey"])
+	secretKey := string(data["amazonec2credentialConfig-secretKey"])
 	if accessKey == "" || secretKey == "" {
 		return ""
 	}
@@ -448,8 +456,6 @@ func (v *Validator) eksAccountID(secretRef, region string) string {
 		Region:      stsRegion,
 		Credentials: credentials.NewStaticCredentialsProvider(accessKey, secretKey, ""),
 	}
-	ctx, cancel := context.WithTimeout(context.Background(), stsAccountIDTimeout)
-	defer cancel()
 	out, err := sts.NewFromConfig(cfg).GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
 	if err != nil {
 		logrus.Warnf("EKS duplicate validation: STS GetCallerIdentity failed for %s: %v", secretRef, err)
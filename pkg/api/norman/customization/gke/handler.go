@@ -18,6 +18,7 @@
 	"github.com/rancher/rancher/pkg/namespace"
 	"github.com/rancher/rancher/pkg/ref"
 	schema "github.com/rancher/rancher/pkg/schemas/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/secretbackend"
 	"github.com/rancher/rancher/pkg/types/config"
 	"github.com/sirupsen/logrus"
 )
@@ -213,6 +214,10 @@ func (h *handler) getCloudCredential(req *http.Request, cap *Capabilities, credI
 		logrus.Errorf("[GKE] error accessing cloud credential %s", credID)
 		return httperror.InvalidBodyContent.Status, fmt.Errorf("error accessing cloud credential %s", credID)
 	}
+	if cc, err = secretbackend.Resolve(req.Context(), cc); err != nil {
+		logrus.Errorf("[GKE] error reading cloud credential %s: %v", credID, err)
+		return httperror.ServerError.Status, fmt.Errorf("error reading cloud credential %s", credID)
+	}
 	cap.Credentials = string(cc.Data["googlecredentialConfig-authEncodedJson"])
 
 	cap.ProjectID = req.URL.Query().Get("projectId")
@@ -19,6 +19,7 @@
 	"github.com/rancher/rancher/pkg/namespace"
 	"github.com/rancher/rancher/pkg/ref"
 	schema "github.com/rancher/rancher/pkg/schemas/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/secretbackend"
 	"github.com/rancher/rancher/pkg/types/config"
 	"github.com/sirupsen/logrus"
 )
@@ -172,6 +173,10 @@ func (handler *handler) extractCreds(req *http.Request, creds *Credentials) (int
 			logrus.Debugf("[oci-handler] error accessing cloud credential %s", credID)
 			return httperror.InvalidBodyContent.Status, fmt.Errorf("error accessing cloud credential %s", credID)
 		}
+		if cc, err = secretbackend.Resolve(req.Context(), cc); err != nil {
+			logrus.Debugf("[oci-handler] error reading cloud credential %s: %v", credID, err)
+			return httperror.ServerError.Status, fmt.Errorf("error reading cloud credential %s", credID)
+		}
 
 		creds.Tenancy = string(cc.Data[requiredDataFields["tenancyId"]])
 		creds.User = string(cc.Data[requiredDataFields["userId"]])
@@ -17,6 +17,7 @@
 	"github.com/rancher/rancher/pkg/namespace"
 	"github.com/rancher/rancher/pkg/ref"
 	schema "github.com/rancher/rancher/pkg/schemas/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/secretbackend"
 	"github.com/rancher/rancher/pkg/settings"
 	"github.com/rancher/rancher/pkg/types/config"
 	corev1 "k8s.io/api/core/v1"
@@ -207,6 +208,9 @@ func (v *handler) getCloudCredential(id string, req *http.Request) (*corev1.Secr
 	if err != nil || cc == nil {
 		return nil, httperror.InvalidBodyContent, fmt.Errorf("error getting cloud cred %s: %v", id, err)
 	}
+	if cc, err = secretbackend.Resolve(req.Context(), cc); err != nil {
+		return nil, httperror.ServerError, fmt.Errorf("error reading cloud cred %s: %v", id, err)
+	}
 
 	if len(cc.Data) == 0 {
 		return nil, httperror.InvalidBodyContent, fmt.Errorf("empty credential ID data %s", id)
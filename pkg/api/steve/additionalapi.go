@@ -4,6 +4,7 @@
 	"context"
 	"net/http"
 
+	"github.com/rancher/lasso/pkg/controller"
 	"github.com/rancher/rancher/pkg/api/steve/aggregation"
 	"github.com/rancher/rancher/pkg/api/steve/catalog"
 	"github.com/rancher/rancher/pkg/api/steve/github"
@@ -46,7 +47,7 @@ func AdditionalAPIsPreMCM(config *wrangler.Context) func(http.Handler) http.Hand
 	}
 }
 
-func AdditionalAPIs(ctx context.Context, config *wrangler.Context, steve *steve.Server) (func(http.Handler) http.Handler, error) {
+func AdditionalAPIs(ctx context.Context, config *wrangler.Context, normanControllerFactory controller.SharedControllerFactory, steve *steve.Server) (func(http.Handler) http.Handler, error) {
 	clusterAPI, err := projects.Projects(ctx, config, steve)
 	if err != nil {
 		return nil, err
@@ -67,7 +68,11 @@ func AdditionalAPIs(ctx context.Context, config *wrangler.Context, steve *steve.
 	mux.Handle("/v1/github/{path...}", githubHandler)
 	mux.Handle("/v3/connect", Tunnel(config))
 
-	health.Register(mux)
+	readyChecks, externalChecks, err := health.ReadyChecks(ctx, config, normanControllerFactory)
+	if err != nil {
+		return nil, err
+	}
+	health.Register(mux, readyChecks, externalChecks)
 
 	if features.OIDCProvider.Enabled() {
 		p, err := provider.NewProvider(ctx, exttokenstore.NewSystemFromWrangler(config),
@@ -1,16 +1,37 @@
 package health
 
 import (
+	"fmt"
 	"net/http"
 
+	"github.com/sirupsen/logrus"
 	"k8s.io/apiserver/pkg/server/healthz"
 )
 
-func Register(router *http.ServeMux) {
+// Register installs the /healthz, /readyz and /ping handlers. The ready checks are the checks of the /readyz
+// endpoint, while the external checks can only be queried on their own at /readyz/<name>, so that an outage of an
+// external service doesn't make Rancher unready.
+func Register(router *http.ServeMux, readyChecks, externalChecks []healthz.HealthChecker) {
 	healthz.InstallHandler(router)
+	healthz.InstallReadyzHandler(router, readyChecks...)
+	for _, check := range externalChecks {
+		router.Handle(fmt.Sprintf("/readyz/%s", check.Name()), checkHandler(check))
+	}
 	router.Handle("/ping", Pong())
 }
 
+// checkHandler serves the result of a single check, withholding the reason of failures like the /readyz handler.
+func checkHandler(check healthz.HealthChecker) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if err := check.Check(req); err != nil {
+			logrus.Debugf("[readyz] Check %s failed: %v", check.Name(), err)
+			http.Error(rw, fmt.Sprintf("%s check failed", check.Name()), http.StatusInternalServerError)
+			return
+		}
+		fmt.Fprint(rw, "ok")
+	})
+}
+
 func Pong() http.Handler {
 	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
 		rw.Write([]byte("pong"))
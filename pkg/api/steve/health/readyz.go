@@ -0,0 +1,480 @@
+package health
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rancher/lasso/pkg/controller"
+	apiv3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	mgmtv3 "github.com/rancher/rancher/pkg/generated/controllers/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/metrics"
+	"github.com/rancher/rancher/pkg/peermanager"
+	"github.com/rancher/rancher/pkg/wrangler"
+	admissionregcontrollers "github.com/rancher/wrangler/v3/pkg/generated/controllers/admissionregistration.k8s.io/v1"
+	"github.com/rancher/wrangler/v3/pkg/ticker"
+	"github.com/sirupsen/logrus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/server/healthz"
+	"k8s.io/client-go/dynamic"
+	coordinationv1 "k8s.io/client-go/kubernetes/typed/coordination/v1"
+)
+
+// Names of the checks of the /readyz endpoint. A check can be queried on its own at /readyz/<name> or skipped with
+// /readyz?exclude=<name>. The leader-election, webhook and auth-providers checks are external and only queried on their
+// own.
+const (
+	CachesCheck         = "caches"
+	PeerManagerCheck    = "peer-manager"
+	LeaderElectionCheck = "leader-election"
+	WebhookCheck        = "webhook"
+	AuthProvidersCheck  = "auth-providers"
+	TunnelSessionsCheck = "tunnel-sessions"
+)
+
+const (
+	// monitorInterval is how often the checks that reach out to other services are run.
+	monitorInterval = 15 * time.Second
+	// dialTimeout is how long the checks that reach out to other services wait for a connection.
+	dialTimeout = 3 * time.Second
+
+	leaseNamespace = "kube-system"
+	leaseName      = "cattle-controllers"
+
+	webhookConfigurationName = "rancher.cattle.io"
+)
+
+// probe is a named readiness check.
+type probe struct {
+	name  string
+	check func(ctx context.Context) error
+	// background probes reach out to other services, so they are run periodically by the monitor rather than on every
+	// request, and requests get their last result.
+	background bool
+	// external probes check services that all servers depend on, so their failure would make every server unready at
+	// once. They are reported in metrics and at /readyz/<name>, but aren't part of /readyz.
+	external bool
+}
+
+// monitor runs the probes of the /readyz endpoint and records their results.
+type monitor struct {
+	probes []probe
+
+	mu      sync.RWMutex
+	results map[string]error
+}
+
+// ReadyChecks returns the checks of the /readyz endpoint, for the controllers of the wrangler context and of the
+// norman controller factory, and the checks of external services. The checks that reach out to other services are run
+// in the background until ctx is done.
+func ReadyChecks(ctx context.Context, w *wrangler.Context, normanControllerFactory controller.SharedControllerFactory) (ready, external []healthz.HealthChecker, err error) {
+	dynamicClient, err := dynamic.NewForConfig(w.RESTConfig)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create dynamic client: %w", err)
+	}
+	hostname, err := os.Hostname()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get hostname: %w", err)
+	}
+
+	caches := &cachesProbe{factories: []cacheFactory{
+		{name: "wrangler", factory: w.ControllerFactory, required: true},
+		{name: "norman", factory: normanControllerFactory},
+	}}
+	leaderElection := &leaderElectionProbe{
+		leases:   w.K8s.CoordinationV1(),
+		identity: hostname,
+		now:      time.Now,
+	}
+	webhook := &webhookProbe{
+		webhookConfigurationCache: w.Admission.ValidatingWebhookConfiguration().Cache(),
+		dialTLS:                   dialTLS,
+	}
+	authProviders := &authProvidersProbe{
+		authConfigCache: w.Mgmt.AuthConfig().Cache(),
+		authConfigsUnstructured: dynamicClient.Resource(schema.GroupVersionResource{
+			Group:    "management.cattle.io",
+			Version:  "v3",
+			Resource: "authconfigs",
+		}),
+		dial:   (&net.Dialer{Timeout: dialTimeout}).DialContext,
+		client: &http.Client{Timeout: dialTimeout, Transport: &http.Transport{Proxy: http.ProxyFromEnvironment}},
+	}
+	tunnelSessions := func(context.Context) error {
+		// The number of sessions is only reported, as there is no number of sessions a server must have to be ready.
+		metrics.SetReadinessTunnelSessions(len(w.TunnelServer.ListClients()))
+		return nil
+	}
+
+	m := &monitor{
+		probes: []probe{
+			{name: CachesCheck, check: caches.check},
+			{name: PeerManagerCheck, check: newPeerManagerProbe(ctx, w.PeerManager).check},
+			{name: LeaderElectionCheck, check: leaderElection.check, background: true, external: true},
+			{name: WebhookCheck, check: webhook.check, background: true, external: true},
+			{name: AuthProvidersCheck, check: authProviders.check, background: true, external: true},
+			{name: TunnelSessionsCheck, check: tunnelSessions},
+		},
+		results: map[string]error{},
+	}
+	go m.run(ctx)
+
+	ready, external = m.checks()
+	return ready, external, nil
+}
+
+// run runs the background probes until ctx is done.
+func (m *monitor) run(ctx context.Context) {
+	m.runBackground(ctx)
+	for range ticker.Context(ctx, monitorInterval) {
+		m.runBackground(ctx)
+	}
+}
+
+func (m *monitor) runBackground(ctx context.Context) {
+	for _, p := range m.probes {
+		if !p.background {
+			continue
+		}
+		err := p.check(ctx)
+		if err != nil {
+			logrus.Debugf("[readyz] Check %s failed: %v", p.name, err)
+		}
+		m.mu.Lock()
+		m.results[p.name] = err
+		m.mu.Unlock()
+		metrics.SetReadinessCheck(p.name, err == nil)
+	}
+}
+
+// checks returns the probes as health checkers, split between the checks of /readyz and the external checks.
+func (m *monitor) checks() (ready, external []healthz.HealthChecker) {
+	for _, p := range m.probes {
+		check := healthz.NamedCheck(p.name, func(r *http.Request) error {
+			if p.background {
+				return m.result(p.name)
+			}
+			err := p.check(r.Context())
+			metrics.SetReadinessCheck(p.name, err == nil)
+			return err
+		})
+		if p.external {
+			external = append(external, check)
+		} else {
+			ready = append(ready, check)
+		}
+	}
+	return ready, external
+}
+
+// result returns the last result of a background probe.
+func (m *monitor) result(name string) error {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	err, ok := m.results[name]
+	if !ok {
+		return fmt.Errorf("not checked yet")
+	}
+	return err
+}
+
+// cacheFactory is a controller factory whose caches must be synced.
+type cacheFactory struct {
+	name    string
+	factory controller.SharedControllerFactory
+	// required factories must have started caches.
+	required bool
+}
+
+// cachesProbe checks that the started caches of controller factories have synced.
+type cachesProbe struct {
+	factories []cacheFactory
+}
+
+func (p *cachesProbe) check(context.Context) error {
+	// WaitForCacheSync checks the caches once before giving up on a done context.
+	done, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var errs []error
+	for _, f := range p.factories {
+		synced := f.factory.SharedCacheFactory().WaitForCacheSync(done)
+		if len(synced) == 0 && f.required {
+			errs = append(errs, fmt.Errorf("%s caches have not been started", f.name))
+			continue
+		}
+		var unsynced []string
+		for gvk, ok := range synced {
+			if !ok {
+				unsynced = append(unsynced, gvk.String())
+			}
+		}
+		if len(unsynced) > 0 {
+			slices.Sort(unsynced)
+			errs = append(errs, fmt.Errorf("%s caches not synced: %s", f.name, strings.Join(unsynced, ", ")))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// peerManagerProbe checks that the peer manager knows the peers of the server. It doesn't require the server to be a
+// ready endpoint of the peer service, because that in turn requires the server to be ready.
+type peerManagerProbe struct {
+	// enabled is false in single server mode, in which there are no peers.
+	enabled bool
+
+	mu     sync.Mutex
+	synced bool
+}
+
+func newPeerManagerProbe(ctx context.Context, m peermanager.PeerManager) *peerManagerProbe {
+	p := &peerManagerProbe{enabled: m != nil}
+	if m == nil {
+		return p
+	}
+
+	peersChan := make(chan peermanager.Peers, 100)
+	m.AddListener(peersChan)
+	go func() {
+		for {
+			select {
+			case peers := <-peersChan:
+				p.setPeers(peers)
+			case <-ctx.Done():
+				m.RemoveListener(peersChan)
+				close(peersChan)
+				//revive:disable:empty-block Until https://github.com/mgechev/revive/issues/386 is fixed
+				for range peersChan {
+					// drain channel
+				}
+				//revive:enable:empty-block
+				return
+			}
+		}
+	}()
+	return p
+}
+
+func (p *peerManagerProbe) setPeers(peers peermanager.Peers) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.synced = true
+	metrics.SetReadinessPeers(len(peers.IDs), peers.Ready)
+}
+
+func (p *peerManagerProbe) check(context.Context) error {
+	if !p.enabled {
+		return nil
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.synced {
+		return fmt.Errorf("peers have not been synced")
+	}
+	return nil
+}
+
+// leaderElectionProbe checks that the controllers leader election lease is held by a server. The lease is shared by all
+// servers, so it is an external probe.
+type leaderElectionProbe struct {
+	leases   coordinationv1.LeasesGetter
+	identity string
+	now      func() time.Time
+}
+
+func (p *leaderElectionProbe) check(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, dialTimeout)
+	defer cancel()
+
+	lease, err := p.leases.Leases(leaseNamespace).Get(ctx, leaseName, metav1.GetOptions{})
+	if err != nil {
+		metrics.SetReadinessLeader(false)
+		return fmt.Errorf("getting lease %s/%s: %w", leaseNamespace, leaseName, err)
+	}
+
+	holder := ""
+	if lease.Spec.HolderIdentity != nil {
+		holder = *lease.Spec.HolderIdentity
+	}
+	metrics.SetReadinessLeader(holder != "" && holder == p.identity)
+
+	if holder == "" {
+		return fmt.Errorf("lease %s/%s has no holder", leaseNamespace, leaseName)
+	}
+	if lease.Spec.RenewTime != nil && lease.Spec.LeaseDurationSeconds != nil {
+		expiration := lease.Spec.RenewTime.Add(time.Duration(*lease.Spec.LeaseDurationSeconds) * time.Second)
+		if p.now().After(expiration) {
+			return fmt.Errorf("lease %s/%s held by %s expired at %s", leaseNamespace, leaseName, holder, expiration.UTC().Format(time.RFC3339))
+		}
+	}
+	return nil
+}
+
+// webhookProbe checks that the services of the Rancher webhook accept TLS connections.
+type webhookProbe struct {
+	webhookConfigurationCache admissionregcontrollers.ValidatingWebhookConfigurationCache
+	dialTLS                   func(ctx context.Context, addr string, config *tls.Config) error
+}
+
+func (p *webhookProbe) check(ctx context.Context) error {
+	config, err := p.webhookConfigurationCache.Get(webhookConfigurationName)
+	if apierrors.IsNotFound(err) {
+		// The webhook isn't installed, so requests aren't blocked on it.
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("getting validating webhook configuration %s: %w", webhookConfigurationName, err)
+	}
+
+	checked := map[string]bool{}
+	for _, webhook := range config.Webhooks {
+		service := webhook.ClientConfig.Service
+		if service == nil {
+			continue
+		}
+		host := service.Name + "." + service.Namespace + ".svc"
+		port := int32(443)
+		if service.Port != nil {
+			port = *service.Port
+		}
+		addr := net.JoinHostPort(host, strconv.Itoa(int(port)))
+		if checked[addr] {
+			continue
+		}
+		checked[addr] = true
+
+		tlsConfig := &tls.Config{ServerName: host}
+		if len(webhook.ClientConfig.CABundle) > 0 {
+			tlsConfig.RootCAs = x509.NewCertPool()
+			if !tlsConfig.RootCAs.AppendCertsFromPEM(webhook.ClientConfig.CABundle) {
+				return fmt.Errorf("webhook %s has an invalid CA bundle", webhook.Name)
+			}
+		}
+		if err := p.dialTLS(ctx, addr, tlsConfig); err != nil {
+			return fmt.Errorf("connecting to webhook %s at %s: %w", webhook.Name, addr, err)
+		}
+	}
+	return nil
+}
+
+func dialTLS(ctx context.Context, addr string, config *tls.Config) error {
+	dialer := &tls.Dialer{
+		NetDialer: &net.Dialer{Timeout: dialTimeout},
+		Config:    config,
+	}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
+// authProvidersProbe checks that the servers of the enabled auth providers can be reached. LDAP servers are dialed
+// directly, while HTTP servers are requested through the configured proxy, and any response is accepted.
+type authProvidersProbe struct {
+	authConfigCache         mgmtv3.AuthConfigCache
+	authConfigsUnstructured dynamic.ResourceInterface
+	dial                    func(ctx context.Context, network, addr string) (net.Conn, error)
+	client                  *http.Client
+}
+
+func (p *authProvidersProbe) check(ctx context.Context) error {
+	authConfigs, err := p.authConfigCache.List(labels.Everything())
+	if err != nil {
+		return fmt.Errorf("listing authconfigs: %w", err)
+	}
+	slices.SortFunc(authConfigs, func(a, b *apiv3.AuthConfig) int { return strings.Compare(a.Name, b.Name) })
+
+	var errs []error
+	for _, authConfig := range authConfigs {
+		if !authConfig.Enabled {
+			continue
+		}
+		raw, err := p.authConfigsUnstructured.Get(ctx, authConfig.Name, metav1.GetOptions{})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("getting authconfig %s: %w", authConfig.Name, err))
+			continue
+		}
+		addrs, urls := authProviderServers(authConfig.Type, raw.Object)
+		for _, addr := range addrs {
+			if err := p.dialLDAP(ctx, addr); err != nil {
+				errs = append(errs, fmt.Errorf("authconfig %s: %w", authConfig.Name, err))
+			}
+		}
+		for _, url := range urls {
+			if err := p.request(ctx, url); err != nil {
+				errs = append(errs, fmt.Errorf("authconfig %s: %w", authConfig.Name, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (p *authProvidersProbe) dialLDAP(ctx context.Context, addr string) error {
+	conn, err := p.dial(ctx, "tcp", addr)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
+func (p *authProvidersProbe) request(ctx context.Context, url string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// authProviderServers returns the addresses of the LDAP servers and the URLs of the HTTP servers of an authconfig.
+func authProviderServers(authConfigType string, config map[string]any) (addrs []string, urls []string) {
+	if servers, ok := config["servers"].([]any); ok {
+		port := int64(389)
+		switch v := config["port"].(type) {
+		case int64:
+			port = v
+		case float64:
+			port = int64(v)
+		}
+		for _, server := range servers {
+			if s, ok := server.(string); ok && s != "" {
+				addrs = append(addrs, net.JoinHostPort(s, strconv.FormatInt(port, 10)))
+			}
+		}
+	}
+	// OIDC providers.
+	if issuer, ok := config["issuer"].(string); ok && issuer != "" {
+		urls = append(urls, issuer)
+	}
+	// Azure AD.
+	if endpoint, ok := config["endpoint"].(string); ok && endpoint != "" {
+		urls = append(urls, endpoint)
+	}
+	// GitHub and GitHub App.
+	if strings.HasPrefix(authConfigType, "github") {
+		if hostname, ok := config["hostname"].(string); ok && hostname != "" {
+			scheme := "https"
+			if tls, ok := config["tls"].(bool); ok && !tls {
+				scheme = "http"
+			}
+			urls = append(urls, scheme+"://"+hostname)
+		}
+	}
+	return addrs, urls
+}
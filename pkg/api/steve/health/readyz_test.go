@@ -0,0 +1,262 @@
+package health
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/rancher/rancher/pkg/peermanager"
+	"github.com/rancher/wrangler/v3/pkg/generic/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	admissionv1 "k8s.io/api/admissionregistration/v1"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/ptr"
+)
+
+func TestReadyz(t *testing.T) {
+	m := &monitor{
+		probes: []probe{
+			{name: "live", check: func(context.Context) error { return nil }},
+			{name: "failing", check: func(context.Context) error { return errors.New("broken") }},
+			{name: "background", check: func(context.Context) error { return nil }, background: true},
+			{name: "external", check: func(context.Context) error { return errors.New("unreachable") }, background: true, external: true},
+		},
+		results: map[string]error{},
+	}
+	mux := http.NewServeMux()
+	ready, external := m.checks()
+	Register(mux, ready, external)
+
+	get := func(path string) (int, string) {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		body, _ := io.ReadAll(rec.Body)
+		return rec.Code, string(body)
+	}
+
+	code, body := get("/readyz?verbose")
+	assert.Equal(t, http.StatusInternalServerError, code)
+	assert.Contains(t, body, "[+]live ok")
+	assert.Contains(t, body, "[-]failing failed: reason withheld")
+	assert.Contains(t, body, "[-]background failed: reason withheld")
+	assert.NotContains(t, body, "external")
+
+	// Background probes report their last result.
+	m.runBackground(context.Background())
+	code, _ = get("/readyz?exclude=failing")
+	assert.Equal(t, http.StatusOK, code)
+
+	code, _ = get("/readyz/live")
+	assert.Equal(t, http.StatusOK, code)
+	code, _ = get("/readyz/failing")
+	assert.Equal(t, http.StatusInternalServerError, code)
+
+	// External probes don't make the server unready, but can be queried on their own.
+	code, _ = get("/readyz?exclude=failing")
+	assert.Equal(t, http.StatusOK, code)
+	code, body = get("/readyz/external")
+	assert.Equal(t, http.StatusInternalServerError, code)
+	assert.Equal(t, "external check failed\n", body)
+
+	// The stock handlers are still installed.
+	code, _ = get("/healthz")
+	assert.Equal(t, http.StatusOK, code)
+	code, body = get("/ping")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "pong", body)
+}
+
+type fakePeerManager struct {
+	listeners map[chan<- peermanager.Peers]bool
+}
+
+func (f *fakePeerManager) IsLeader() bool { return false }
+func (f *fakePeerManager) Leader()        {}
+func (f *fakePeerManager) AddListener(l chan<- peermanager.Peers) {
+	f.listeners[l] = true
+}
+func (f *fakePeerManager) RemoveListener(l chan<- peermanager.Peers) {
+	delete(f.listeners, l)
+}
+
+func TestPeerManagerProbe(t *testing.T) {
+	assert.NoError(t, newPeerManagerProbe(context.Background(), nil).check(context.Background()), "single server mode")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	m := &fakePeerManager{listeners: map[chan<- peermanager.Peers]bool{}}
+	p := newPeerManagerProbe(ctx, m)
+	assert.ErrorContains(t, p.check(ctx), "peers have not been synced")
+
+	for l := range m.listeners {
+		l <- peermanager.Peers{SelfID: "10.0.0.1", IDs: []string{"10.0.0.2"}}
+	}
+	assert.Eventually(t, func() bool { return p.check(ctx) == nil }, time.Second, 10*time.Millisecond)
+
+	cancel()
+	assert.Eventually(t, func() bool { return len(m.listeners) == 0 }, time.Second, 10*time.Millisecond)
+}
+
+func TestLeaderElectionProbe(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		lease   *coordinationv1.Lease
+		wantErr string
+	}{
+		{
+			name: "held",
+			lease: &coordinationv1.Lease{Spec: coordinationv1.LeaseSpec{
+				HolderIdentity:       ptr.To("rancher-0"),
+				LeaseDurationSeconds: ptr.To(int32(45)),
+				RenewTime:            &metav1.MicroTime{Time: now.Add(-10 * time.Second)},
+			}},
+		},
+		{
+			name: "expired",
+			lease: &coordinationv1.Lease{Spec: coordinationv1.LeaseSpec{
+				HolderIdentity:       ptr.To("rancher-0"),
+				LeaseDurationSeconds: ptr.To(int32(45)),
+				RenewTime:            &metav1.MicroTime{Time: now.Add(-time.Minute)},
+			}},
+			wantErr: "lease kube-system/cattle-controllers held by rancher-0 expired at 2026-10-19T11:59:45Z",
+		},
+		{
+			name:    "no holder",
+			lease:   &coordinationv1.Lease{},
+			wantErr: "lease kube-system/cattle-controllers has no holder",
+		},
+		{
+			name:    "not found",
+			wantErr: `getting lease kube-system/cattle-controllers: leases.coordination.k8s.io "cattle-controllers" not found`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := k8sfake.NewClientset()
+			if tt.lease != nil {
+				tt.lease.Namespace = leaseNamespace
+				tt.lease.Name = leaseName
+				_, err := client.CoordinationV1().Leases(leaseNamespace).Create(context.Background(), tt.lease, metav1.CreateOptions{})
+				require.NoError(t, err)
+			}
+
+			p := &leaderElectionProbe{
+				leases:   client.CoordinationV1(),
+				identity: "rancher-0",
+				now:      func() time.Time { return now },
+			}
+			err := p.check(context.Background())
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestWebhookProbe(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	t.Run("not installed", func(t *testing.T) {
+		cache := fake.NewMockNonNamespacedCacheInterface[*admissionv1.ValidatingWebhookConfiguration](ctrl)
+		cache.EXPECT().Get(webhookConfigurationName).Return(nil, apierrors.NewNotFound(schema.GroupResource{}, webhookConfigurationName))
+
+		p := &webhookProbe{webhookConfigurationCache: cache}
+		assert.NoError(t, p.check(context.Background()))
+	})
+
+	t.Run("dials each service once", func(t *testing.T) {
+		cache := fake.NewMockNonNamespacedCacheInterface[*admissionv1.ValidatingWebhookConfiguration](ctrl)
+		service := &admissionv1.ServiceReference{Namespace: "cattle-system", Name: "rancher-webhook", Port: ptr.To(int32(8443))}
+		cache.EXPECT().Get(webhookConfigurationName).Return(&admissionv1.ValidatingWebhookConfiguration{
+			Webhooks: []admissionv1.ValidatingWebhook{
+				{Name: "rancher.cattle.io.clusters", ClientConfig: admissionv1.WebhookClientConfig{Service: service}},
+				{Name: "rancher.cattle.io.projects", ClientConfig: admissionv1.WebhookClientConfig{Service: service}},
+				{Name: "external", ClientConfig: admissionv1.WebhookClientConfig{URL: ptr.To("https://example.com")}},
+			},
+		}, nil).Times(2)
+
+		var dialed []string
+		p := &webhookProbe{
+			webhookConfigurationCache: cache,
+			dialTLS: func(_ context.Context, addr string, config *tls.Config) error {
+				dialed = append(dialed, addr)
+				assert.Equal(t, "rancher-webhook.cattle-system.svc", config.ServerName)
+				return nil
+			},
+		}
+		require.NoError(t, p.check(context.Background()))
+		assert.Equal(t, []string{"rancher-webhook.cattle-system.svc:8443"}, dialed)
+
+		p.dialTLS = func(context.Context, string, *tls.Config) error { return errors.New("connection refused") }
+		assert.EqualError(t, p.check(context.Background()), "connecting to webhook rancher.cattle.io.clusters at rancher-webhook.cattle-system.svc:8443: connection refused")
+	})
+}
+
+func TestAuthProviderServers(t *testing.T) {
+	tests := []struct {
+		name      string
+		typ       string
+		config    map[string]any
+		wantAddrs []string
+		wantURLs  []string
+	}{
+		{
+			name:      "ldap",
+			typ:       "openLdapConfig",
+			config:    map[string]any{"servers": []any{"ldap1.example.com", "ldap2.example.com"}, "port": int64(636)},
+			wantAddrs: []string{"ldap1.example.com:636", "ldap2.example.com:636"},
+		},
+		{
+			name:      "ldap default port",
+			typ:       "activeDirectoryConfig",
+			config:    map[string]any{"servers": []any{"ad.example.com"}},
+			wantAddrs: []string{"ad.example.com:389"},
+		},
+		{
+			name:     "oidc",
+			typ:      "genericOIDCConfig",
+			config:   map[string]any{"issuer": "https://idp.example.com"},
+			wantURLs: []string{"https://idp.example.com"},
+		},
+		{
+			name:     "azure ad",
+			typ:      "azureADConfig",
+			config:   map[string]any{"endpoint": "https://login.microsoftonline.com/"},
+			wantURLs: []string{"https://login.microsoftonline.com/"},
+		},
+		{
+			name:     "github",
+			typ:      "githubConfig",
+			config:   map[string]any{"hostname": "github.example.com", "tls": false},
+			wantURLs: []string{"http://github.example.com"},
+		},
+		{
+			name:   "saml",
+			typ:    "pingConfig",
+			config: map[string]any{"idpMetadataContent": "<xml/>"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			addrs, urls := authProviderServers(tt.typ, tt.config)
+			assert.Equal(t, tt.wantAddrs, addrs)
+			assert.Equal(t, tt.wantURLs, urls)
+		})
+	}
+}
@@ -0,0 +1,127 @@
+// Package supportbundles provides a HTTPHandler to download the archive of a succeeded SupportBundle operation. This
+// handler should be registered at Endpoint.
+package supportbundles
+
+import (
+	"fmt"
+	"net/http"
+
+	opv1alpha1 "github.com/rancher/rancher/pkg/apis/operation.cattle.io/v1alpha1"
+	"github.com/rancher/rancher/pkg/auth/util"
+	"github.com/rancher/rancher/pkg/controllers/operations/supportbundle"
+	operationcontrollers "github.com/rancher/rancher/pkg/generated/controllers/operation.cattle.io/v1alpha1"
+	"github.com/rancher/rancher/pkg/types/config"
+	corecontrollers "github.com/rancher/wrangler/v3/pkg/generated/controllers/core/v1"
+	"github.com/sirupsen/logrus"
+	authzv1 "k8s.io/api/authorization/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	authv1 "k8s.io/client-go/kubernetes/typed/authorization/v1"
+)
+
+const (
+	// Endpoint is the route this handler is accessible at.
+	Endpoint = "GET /v1/downloadSupportBundle/{namespace}/{name}"
+
+	gzipContentType = "application/gzip"
+	logPrefix       = "support-bundle-download"
+)
+
+// Handler implements http.Handler and serves the archive of a SupportBundle to users allowed to get it.
+type Handler struct {
+	SupportBundles       operationcontrollers.SupportBundleClient
+	Secrets              corecontrollers.SecretClient
+	SubjectAccessReviews authv1.SubjectAccessReviewInterface
+}
+
+// NewHandler creates a handler using the clients defined in scaledContext.
+func NewHandler(scaledContext *config.ScaledContext) *Handler {
+	return &Handler{
+		SupportBundles:       scaledContext.Wrangler.Operation.SupportBundle(),
+		Secrets:              scaledContext.Wrangler.Core.Secret(),
+		SubjectAccessReviews: scaledContext.K8sClient.AuthorizationV1().SubjectAccessReviews(),
+	}
+}
+
+// ServeHTTP implements http.Handler. It authorizes the user to get the SupportBundle and returns its archive once the
+// operation has succeeded.
+func (h *Handler) ServeHTTP(writer http.ResponseWriter, req *http.Request) {
+	namespace, name := req.PathValue("namespace"), req.PathValue("name")
+
+	authorized, err := h.authorize(namespace, name, req)
+	if err != nil {
+		logrus.Errorf("[%s] Failed to authorize user with error: %v", logPrefix, err)
+		util.ReturnHTTPError(writer, req, http.StatusForbidden, http.StatusText(http.StatusForbidden))
+		return
+	}
+	if !authorized {
+		util.ReturnHTTPError(writer, req, http.StatusForbidden, http.StatusText(http.StatusForbidden))
+		return
+	}
+
+	op, err := h.SupportBundles.Get(namespace, name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		util.ReturnHTTPError(writer, req, http.StatusNotFound, fmt.Sprintf("support bundle %s/%s not found", namespace, name))
+		return
+	} else if err != nil {
+		logrus.Errorf("[%s] Failed to get support bundle %s/%s: %v", logPrefix, namespace, name, err)
+		util.ReturnHTTPError(writer, req, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	if op.Status.Phase != opv1alpha1.OperationPhaseSucceeded || op.Status.Archive == nil {
+		util.ReturnHTTPError(writer, req, http.StatusConflict, fmt.Sprintf("support bundle %s/%s is not ready, current phase is %q", namespace, name, op.Status.Phase))
+		return
+	}
+
+	secret, err := h.Secrets.Get(namespace, op.Status.Archive.SecretName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		util.ReturnHTTPError(writer, req, http.StatusNotFound, fmt.Sprintf("archive of support bundle %s/%s not found", namespace, name))
+		return
+	} else if err != nil {
+		logrus.Errorf("[%s] Failed to get archive of support bundle %s/%s: %v", logPrefix, namespace, name, err)
+		util.ReturnHTTPError(writer, req, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	archive := secret.Data[supportbundle.ArchiveDataKey]
+	writer.Header().Set("Content-Type", gzipContentType)
+	writer.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"supportbundle_%s.tar.gz\"", name))
+	if _, err := writer.Write(archive); err != nil {
+		logrus.Warnf("[%s] Failed to write archive on http response writer: %v", logPrefix, err)
+	}
+}
+
+// authorize checks whether the user can get the named SupportBundle.
+func (h *Handler) authorize(namespace, name string, r *http.Request) (bool, error) {
+	userInfo, ok := request.UserFrom(r.Context())
+	if !ok {
+		return false, fmt.Errorf("unable to extract user info from context")
+	}
+
+	extra := map[string]authzv1.ExtraValue{}
+	for k, v := range userInfo.GetExtra() {
+		extra[k] = authzv1.ExtraValue(v)
+	}
+
+	response, err := h.SubjectAccessReviews.Create(r.Context(), &authzv1.SubjectAccessReview{
+		Spec: authzv1.SubjectAccessReviewSpec{
+			ResourceAttributes: &authzv1.ResourceAttributes{
+				Group:     opv1alpha1.SchemeGroupVersion.Group,
+				Resource:  opv1alpha1.SupportBundleResourceName,
+				Verb:      "get",
+				Name:      name,
+				Namespace: namespace,
+			},
+			User:   userInfo.GetName(),
+			Groups: userInfo.GetGroups(),
+			Extra:  extra,
+			UID:    userInfo.GetUID(),
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return false, fmt.Errorf("failed to create a SubjectAccessReview: %w", err)
+	}
+	return response.Status.Allowed, nil
+}
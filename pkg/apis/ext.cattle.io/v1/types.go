@@ -287,8 +287,25 @@ type KubeconfigSpec struct {
 	// When false, the entry is omitted.
 	// +optional
 	IncludeDefaultEntry *bool `json:"includeDefaultEntry,omitempty"`
+	// Mode controls how the generated kubeconfig authenticates. Can be "Token" or "Exec".
+	// In the default "Token" mode, bearer tokens are embedded in the kubeconfig.
+	// In the "Exec" mode, no tokens are created. Instead, the kubeconfig runs the rancher-kubeconfig-helper
+	// credential plugin, which requests short-lived tokens with KubeconfigTokenRequest as needed,
+	// and TTL limits the time during which such tokens can be requested.
+	// +optional
+	Mode KubeconfigMode `json:"mode,omitempty"`
 }
 
+// KubeconfigMode is the authentication mode of a Kubeconfig.
+type KubeconfigMode string
+
+const (
+	// KubeconfigModeToken embeds bearer tokens in the kubeconfig.
+	KubeconfigModeToken KubeconfigMode = "Token"
+	// KubeconfigModeExec uses an exec credential plugin to obtain short-lived tokens.
+	KubeconfigModeExec KubeconfigMode = "Exec"
+)
+
 // KubeconfigStatus defines the most recently observed status of the Kubeconfig.
 type KubeconfigStatus struct {
 	// Conditions indicate state for particular aspects of the Kubeconfig.
@@ -313,6 +330,45 @@ type KubeconfigStatus struct {
 // +genclient:nonNamespaced
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
+// KubeconfigTokenRequest is used to obtain a short-lived token for a Kubeconfig in the "Exec" mode.
+//
+// Create must be called by the owner of the Kubeconfig, authenticated with a Rancher token
+// that is not itself a kubeconfig token. The token is only returned in the Create response.
+type KubeconfigTokenRequest struct {
+	metav1.TypeMeta `json:",inline"`
+	// Standard object metadata; More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#metadata.
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	// Spec is the desired state of the KubeconfigTokenRequest.
+	// +optional
+	Spec KubeconfigTokenRequestSpec `json:"spec,omitempty"`
+	// Status is the most recently observed status of the KubeconfigTokenRequest.
+	// +optional
+	Status KubeconfigTokenRequestStatus `json:"status,omitempty"`
+}
+
+// KubeconfigTokenRequestSpec contains the data about the kubeconfig token request.
+type KubeconfigTokenRequestSpec struct {
+	// KubeconfigName is the name of the Kubeconfig the token is requested for.
+	KubeconfigName string `json:"kubeconfigName,omitempty"`
+	// ClusterName is the name of the cluster the token is scoped to.
+	// It is only set for clusters with the authorized cluster endpoint enabled.
+	// +optional
+	ClusterName string `json:"clusterName,omitempty"`
+}
+
+// KubeconfigTokenRequestStatus contains the requested token.
+type KubeconfigTokenRequestStatus struct {
+	// Token is the bearer token.
+	Token string `json:"token,omitempty"`
+	// ExpirationTimestamp is the time the token expires at.
+	ExpirationTimestamp metav1.Time `json:"expirationTimestamp,omitempty"`
+}
+
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
 // GroupMembershipRefreshRequest is used to initiate a user refresh action.
 type GroupMembershipRefreshRequest struct {
 	metav1.TypeMeta `json:",inline"`
@@ -396,3 +452,200 @@ type SelfUser struct {
 type SelfUserStatus struct {
 	UserID string `json:"userID,omitempty"`
 }
+
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// TokenUsage is the usage of a Token aggregated over time. It has the name of the token and is read-only.
+//
+// Storage is bounded: only the most recently seen source networks, user agents and clusters are kept,
+// and request counts per day are kept for a limited number of days.
+type TokenUsage struct {
+	metav1.TypeMeta `json:",inline"`
+	// Standard object metadata; More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#metadata.
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	// Status is the aggregated usage of the token.
+	// +optional
+	Status TokenUsageStatus `json:"status,omitempty"`
+}
+
+// TokenUsageStatus contains the aggregated usage of a token.
+type TokenUsageStatus struct {
+	// UserID is the id of the user owning the token.
+	// +optional
+	UserID string `json:"userID,omitempty"`
+	// FirstUsedAt is the time of the first recorded request made with the token.
+	// +optional
+	FirstUsedAt *metav1.Time `json:"firstUsedAt,omitempty"`
+	// LastUsedAt is the time of the last recorded request made with the token.
+	// +optional
+	LastUsedAt *metav1.Time `json:"lastUsedAt,omitempty"`
+	// RequestCount is the total number of recorded requests made with the token.
+	// +optional
+	RequestCount int64 `json:"requestCount,omitempty"`
+	// Days are the request counts per day (UTC), oldest first.
+	// +optional
+	// +listType=atomic
+	Days []TokenUsageDay `json:"days,omitempty"`
+	// Networks are the source networks of the requests, a /24 for IPv4 and a /64 for IPv6 addresses.
+	// +optional
+	// +listType=atomic
+	Networks []TokenUsageEntry `json:"networks,omitempty"`
+	// UserAgents are the user agents of the requests.
+	// +optional
+	// +listType=atomic
+	UserAgents []TokenUsageEntry `json:"userAgents,omitempty"`
+	// Clusters are the clusters accessed with the token.
+	// +optional
+	// +listType=atomic
+	Clusters []TokenUsageEntry `json:"clusters,omitempty"`
+}
+
+// TokenUsageDay is the number of requests made with a token on a day.
+type TokenUsageDay struct {
+	// Date is the day in the YYYY-MM-DD format.
+	Date string `json:"date"`
+	// RequestCount is the number of requests made on the day.
+	RequestCount int64 `json:"requestCount"`
+}
+
+// TokenUsageEntry is the usage of a token for a source network, user agent or cluster.
+type TokenUsageEntry struct {
+	// Value is the source network, user agent or cluster name.
+	Value string `json:"value"`
+	// RequestCount is the number of recorded requests.
+	RequestCount int64 `json:"requestCount"`
+	// FirstUsedAt is the time of the first recorded request.
+	FirstUsedAt metav1.Time `json:"firstUsedAt"`
+	// LastUsedAt is the time of the last recorded request.
+	LastUsedAt metav1.Time `json:"lastUsedAt"`
+	// Flagged is true if the source network was not seen before and the token was flagged for it.
+	// +optional
+	Flagged bool `json:"flagged,omitempty"`
+}
+
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// TokenRevocationRequest is used to revoke all the tokens, both ext and v3 Tokens, matching the selectors of the request.
+// All the selectors must match for a token to be revoked, and at least one selector must be set.
+type TokenRevocationRequest struct {
+	metav1.TypeMeta `json:",inline"`
+	// Standard object metadata; More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#metadata.
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	// Spec is the desired state of the TokenRevocationRequest.
+	// +optional
+	Spec TokenRevocationRequestSpec `json:"spec,omitempty"`
+	// Status is the most recently observed status of the TokenRevocationRequest.
+	// +optional
+	Status TokenRevocationRequestStatus `json:"status,omitempty"`
+}
+
+// TokenRevocationRequestSpec contains the selectors of the tokens to revoke.
+type TokenRevocationRequestSpec struct {
+	// UserID selects the tokens of the user.
+	// +optional
+	UserID string `json:"userID,omitempty"`
+	// AuthProvider selects the tokens issued by the auth provider, e.g. "github".
+	// +optional
+	AuthProvider string `json:"authProvider,omitempty"`
+	// Kind selects the tokens of a kind. It is "session" for login tokens, "derived" for all other tokens,
+	// or the kind of derived tokens, e.g. "kubeconfig".
+	// +optional
+	Kind string `json:"kind,omitempty"`
+	// ClusterName selects the tokens scoped to the cluster.
+	// +optional
+	ClusterName string `json:"clusterName,omitempty"`
+	// CreatedAfter selects the tokens created at or after the time.
+	// +optional
+	CreatedAfter *metav1.Time `json:"createdAfter,omitempty"`
+	// CreatedBefore selects the tokens created before the time.
+	// +optional
+	CreatedBefore *metav1.Time `json:"createdBefore,omitempty"`
+	// SetNotBefore persists the time of the request as a watermark on the user, if UserID is set, or else on the
+	// auth provider, if AuthProvider is set. All the tokens of the user or auth provider created before the watermark
+	// are rejected, regardless of the other selectors, so that tokens replayed from a cache can't be used either.
+	// +optional
+	SetNotBefore bool `json:"setNotBefore,omitempty"`
+}
+
+// TokenRevocationRequestStatus defines the most recently observed status of the TokenRevocationRequest.
+type TokenRevocationRequestStatus struct {
+	// MatchedCount is the number of tokens matching the selectors. Except for dry-run requests, they are recorded in
+	// a ConfigMap of the cattle-tokens namespace and revoked by a controller after the request returns. The
+	// controller retries the tokens which fail to be revoked, and deletes the ConfigMap once all of them are.
+	MatchedCount int64 `json:"matchedCount"`
+	// NotBefore is the watermark persisted when SetNotBefore is set.
+	// +optional
+	NotBefore *metav1.Time `json:"notBefore,omitempty"`
+	// Conditions indicate state for particular aspects of the TokenRevocationRequest.
+	// +optional
+	// +listType=atomic
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// Summary of the TokenRevocationRequest status.
+	// +optional
+	Summary string `json:"summary,omitempty"`
+}
+
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// UserRetentionReport is used to list the users the user retention job would disable or delete, without changing
+// any user. It evaluates the disable-inactive-user-after and delete-inactive-user-after settings, the
+// UserRetentionPolicies and the per-user overrides against the current state of the users.
+type UserRetentionReport struct {
+	metav1.TypeMeta `json:",inline"`
+	// Standard object metadata; More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#metadata.
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	// Spec is the desired state of the UserRetentionReport.
+	// +optional
+	Spec UserRetentionReportSpec `json:"spec,omitempty"`
+	// Status is the most recently observed status of the UserRetentionReport.
+	// +optional
+	Status UserRetentionReportStatus `json:"status,omitempty"`
+}
+
+// UserRetentionReportSpec contains the parameters of the report.
+type UserRetentionReportSpec struct {
+	// At is the time to evaluate the retention policies at. Defaults to the time of the request.
+	// +optional
+	At *metav1.Time `json:"at,omitempty"`
+}
+
+// UserRetentionReportStatus defines the most recently observed status of the UserRetentionReport.
+type UserRetentionReportStatus struct {
+	// Users are the users that would be disabled or deleted.
+	// +optional
+	// +listType=atomic
+	Users []UserRetentionReportEntry `json:"users,omitempty"`
+	// Conditions indicate state for particular aspects of the UserRetentionReport.
+	// +optional
+	// +listType=atomic
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// Summary of the UserRetentionReport status.
+	// +optional
+	Summary string `json:"summary,omitempty"`
+}
+
+// UserRetentionReportEntry is a user that would be disabled or deleted.
+type UserRetentionReportEntry struct {
+	// UserID is the name of the user.
+	UserID string `json:"userID"`
+	// Username is the username of the user, if any.
+	// +optional
+	Username string `json:"username,omitempty"`
+	// Action is what would be done to the user. It is either "Disable" or "Delete".
+	Action string `json:"action"`
+	// Policy is the name of the UserRetentionPolicy which applies to the user, if any.
+	// +optional
+	Policy string `json:"policy,omitempty"`
+	// LastLogin is the time the user last logged in, if known.
+	// +optional
+	LastLogin *metav1.Time `json:"lastLogin,omitempty"`
+}
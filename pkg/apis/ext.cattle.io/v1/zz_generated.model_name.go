@@ -61,6 +61,26 @@ func (in KubeconfigStatus) OpenAPIModelName() string {
 	return "ext.cattle.io.v1.KubeconfigStatus"
 }
 
+// OpenAPIModelName returns the OpenAPI model name for this type.
+func (in KubeconfigTokenRequest) OpenAPIModelName() string {
+	return "ext.cattle.io.v1.KubeconfigTokenRequest"
+}
+
+// OpenAPIModelName returns the OpenAPI model name for this type.
+func (in KubeconfigTokenRequestList) OpenAPIModelName() string {
+	return "ext.cattle.io.v1.KubeconfigTokenRequestList"
+}
+
+// OpenAPIModelName returns the OpenAPI model name for this type.
+func (in KubeconfigTokenRequestSpec) OpenAPIModelName() string {
+	return "ext.cattle.io.v1.KubeconfigTokenRequestSpec"
+}
+
+// OpenAPIModelName returns the OpenAPI model name for this type.
+func (in KubeconfigTokenRequestStatus) OpenAPIModelName() string {
+	return "ext.cattle.io.v1.KubeconfigTokenRequestStatus"
+}
+
 // OpenAPIModelName returns the OpenAPI model name for this type.
 func (in PasswordChangeRequest) OpenAPIModelName() string {
 	return "ext.cattle.io.v1.PasswordChangeRequest"
@@ -111,6 +131,26 @@ func (in TokenPrincipal) OpenAPIModelName() string {
 	return "ext.cattle.io.v1.TokenPrincipal"
 }
 
+// OpenAPIModelName returns the OpenAPI model name for this type.
+func (in TokenRevocationRequest) OpenAPIModelName() string {
+	return "ext.cattle.io.v1.TokenRevocationRequest"
+}
+
+// OpenAPIModelName returns the OpenAPI model name for this type.
+func (in TokenRevocationRequestList) OpenAPIModelName() string {
+	return "ext.cattle.io.v1.TokenRevocationRequestList"
+}
+
+// OpenAPIModelName returns the OpenAPI model name for this type.
+func (in TokenRevocationRequestSpec) OpenAPIModelName() string {
+	return "ext.cattle.io.v1.TokenRevocationRequestSpec"
+}
+
+// OpenAPIModelName returns the OpenAPI model name for this type.
+func (in TokenRevocationRequestStatus) OpenAPIModelName() string {
+	return "ext.cattle.io.v1.TokenRevocationRequestStatus"
+}
+
 // OpenAPIModelName returns the OpenAPI model name for this type.
 func (in TokenSpec) OpenAPIModelName() string {
 	return "ext.cattle.io.v1.TokenSpec"
@@ -121,6 +161,31 @@ func (in TokenStatus) OpenAPIModelName() string {
 	return "ext.cattle.io.v1.TokenStatus"
 }
 
+// OpenAPIModelName returns the OpenAPI model name for this type.
+func (in TokenUsage) OpenAPIModelName() string {
+	return "ext.cattle.io.v1.TokenUsage"
+}
+
+// OpenAPIModelName returns the OpenAPI model name for this type.
+func (in TokenUsageDay) OpenAPIModelName() string {
+	return "ext.cattle.io.v1.TokenUsageDay"
+}
+
+// OpenAPIModelName returns the OpenAPI model name for this type.
+func (in TokenUsageEntry) OpenAPIModelName() string {
+	return "ext.cattle.io.v1.TokenUsageEntry"
+}
+
+// OpenAPIModelName returns the OpenAPI model name for this type.
+func (in TokenUsageList) OpenAPIModelName() string {
+	return "ext.cattle.io.v1.TokenUsageList"
+}
+
+// OpenAPIModelName returns the OpenAPI model name for this type.
+func (in TokenUsageStatus) OpenAPIModelName() string {
+	return "ext.cattle.io.v1.TokenUsageStatus"
+}
+
 // OpenAPIModelName returns the OpenAPI model name for this type.
 func (in UserActivity) OpenAPIModelName() string {
 	return "ext.cattle.io.v1.UserActivity"
@@ -140,3 +205,28 @@ func (in UserActivitySpec) OpenAPIModelName() string {
 func (in UserActivityStatus) OpenAPIModelName() string {
 	return "ext.cattle.io.v1.UserActivityStatus"
 }
+
+// OpenAPIModelName returns the OpenAPI model name for this type.
+func (in UserRetentionReport) OpenAPIModelName() string {
+	return "ext.cattle.io.v1.UserRetentionReport"
+}
+
+// OpenAPIModelName returns the OpenAPI model name for this type.
+func (in UserRetentionReportEntry) OpenAPIModelName() string {
+	return "ext.cattle.io.v1.UserRetentionReportEntry"
+}
+
+// OpenAPIModelName returns the OpenAPI model name for this type.
+func (in UserRetentionReportList) OpenAPIModelName() string {
+	return "ext.cattle.io.v1.UserRetentionReportList"
+}
+
+// OpenAPIModelName returns the OpenAPI model name for this type.
+func (in UserRetentionReportSpec) OpenAPIModelName() string {
+	return "ext.cattle.io.v1.UserRetentionReportSpec"
+}
+
+// OpenAPIModelName returns the OpenAPI model name for this type.
+func (in UserRetentionReportStatus) OpenAPIModelName() string {
+	return "ext.cattle.io.v1.UserRetentionReportStatus"
+}
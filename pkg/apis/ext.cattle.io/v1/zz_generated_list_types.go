@@ -62,6 +62,23 @@ func NewKubeconfig(namespace, name string, obj Kubeconfig) *Kubeconfig {
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
+// KubeconfigTokenRequestList is a list of KubeconfigTokenRequest resources
+type KubeconfigTokenRequestList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []KubeconfigTokenRequest `json:"items"`
+}
+
+func NewKubeconfigTokenRequest(namespace, name string, obj KubeconfigTokenRequest) *KubeconfigTokenRequest {
+	obj.APIVersion, obj.Kind = SchemeGroupVersion.WithKind("KubeconfigTokenRequest").ToAPIVersionAndKind()
+	obj.Name = name
+	obj.Namespace = namespace
+	return &obj
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
 // PasswordChangeRequestList is a list of PasswordChangeRequest resources
 type PasswordChangeRequestList struct {
 	metav1.TypeMeta `json:",inline"`
@@ -113,6 +130,40 @@ func NewToken(namespace, name string, obj Token) *Token {
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
+// TokenRevocationRequestList is a list of TokenRevocationRequest resources
+type TokenRevocationRequestList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []TokenRevocationRequest `json:"items"`
+}
+
+func NewTokenRevocationRequest(namespace, name string, obj TokenRevocationRequest) *TokenRevocationRequest {
+	obj.APIVersion, obj.Kind = SchemeGroupVersion.WithKind("TokenRevocationRequest").ToAPIVersionAndKind()
+	obj.Name = name
+	obj.Namespace = namespace
+	return &obj
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// TokenUsageList is a list of TokenUsage resources
+type TokenUsageList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []TokenUsage `json:"items"`
+}
+
+func NewTokenUsage(namespace, name string, obj TokenUsage) *TokenUsage {
+	obj.APIVersion, obj.Kind = SchemeGroupVersion.WithKind("TokenUsage").ToAPIVersionAndKind()
+	obj.Name = name
+	obj.Namespace = namespace
+	return &obj
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
 // UserActivityList is a list of UserActivity resources
 type UserActivityList struct {
 	metav1.TypeMeta `json:",inline"`
@@ -127,3 +178,20 @@ func NewUserActivity(namespace, name string, obj UserActivity) *UserActivity {
 	obj.Namespace = namespace
 	return &obj
 }
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// UserRetentionReportList is a list of UserRetentionReport resources
+type UserRetentionReportList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []UserRetentionReport `json:"items"`
+}
+
+func NewUserRetentionReport(namespace, name string, obj UserRetentionReport) *UserRetentionReport {
+	obj.APIVersion, obj.Kind = SchemeGroupVersion.WithKind("UserRetentionReport").ToAPIVersionAndKind()
+	obj.Name = name
+	obj.Namespace = namespace
+	return &obj
+}
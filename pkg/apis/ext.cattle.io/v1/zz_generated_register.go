@@ -32,10 +32,14 @@
 var (
 	GroupMembershipRefreshRequestResourceName = "groupmembershiprefreshrequests"
 	KubeconfigResourceName                    = "kubeconfigs"
+	KubeconfigTokenRequestResourceName        = "kubeconfigtokenrequests"
 	PasswordChangeRequestResourceName         = "passwordchangerequests"
 	SelfUserResourceName                      = "selfusers"
 	TokenResourceName                         = "tokens"
+	TokenRevocationRequestResourceName        = "tokenrevocationrequests"
+	TokenUsageResourceName                    = "tokenusages"
 	UserActivityResourceName                  = "useractivities"
+	UserRetentionReportResourceName           = "userretentionreports"
 )
 
 // SchemeGroupVersion is group version used to register these objects
@@ -63,14 +67,22 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&GroupMembershipRefreshRequestList{},
 		&Kubeconfig{},
 		&KubeconfigList{},
+		&KubeconfigTokenRequest{},
+		&KubeconfigTokenRequestList{},
 		&PasswordChangeRequest{},
 		&PasswordChangeRequestList{},
 		&SelfUser{},
 		&SelfUserList{},
 		&Token{},
 		&TokenList{},
+		&TokenRevocationRequest{},
+		&TokenRevocationRequestList{},
+		&TokenUsage{},
+		&TokenUsageList{},
 		&UserActivity{},
 		&UserActivityList{},
+		&UserRetentionReport{},
+		&UserRetentionReportList{},
 	)
 	metav1.AddToGroupVersion(scheme, SchemeGroupVersion)
 	return nil
@@ -0,0 +1,121 @@
+package v3
+
+import metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+const (
+	// BreakGlassCredentialConditionIssued indicates the token of the credential was signed and stored in its Secret.
+	BreakGlassCredentialConditionIssued = "Issued"
+
+	// BreakGlassCredentialConditionUsed indicates the downstream cluster reported requests authenticated with the
+	// token of the credential.
+	BreakGlassCredentialConditionUsed = "Used"
+
+	// BreakGlassCredentialConditionUsageReviewed indicates whether the reported usage of the credential was
+	// acknowledged. It is False once the credential is used, until the BreakGlassUsageAcknowledgeAnnotation is set.
+	BreakGlassCredentialConditionUsageReviewed = "UsageReviewed"
+
+	// BreakGlassCredentialConditionExpired indicates the credential expired and its token was deleted.
+	BreakGlassCredentialConditionExpired = "Expired"
+
+	// BreakGlassUsageAcknowledgeAnnotation acknowledges the usage of a break-glass credential reported so far. It is
+	// removed once the acknowledgement is recorded in the status.
+	BreakGlassUsageAcknowledgeAnnotation = "cattle.io/break-glass-acknowledge-usage"
+)
+
+// +genclient
+// +genclient:nonNamespaced
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:printcolumn:name="Cluster",type="string",JSONPath=".spec.clusterName"
+// +kubebuilder:printcolumn:name="User",type="string",JSONPath=".spec.userName"
+// +kubebuilder:printcolumn:name="Expires",type="date",JSONPath=".status.expiresAt"
+// +kubebuilder:printcolumn:name="Last Used",type="date",JSONPath=".status.lastUsedAt"
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// BreakGlassCredential is an emergency credential giving a user access to a downstream cluster through the Authorized
+// Cluster Endpoint while Rancher is unavailable. Rancher issues a short-lived JWT for the user, carrying the groups
+// the user belonged to at issue time. Rancher publishes the public keys and the valid credentials of the cluster to
+// the cattle-system/break-glass ConfigMap, for an authenticator of the downstream cluster to verify tokens offline.
+// Requests the downstream cluster reports in ConfigMaps labeled cattle.io/break-glass-credential must be acknowledged.
+// Credentials are only issued when the break-glass-credentials feature is enabled.
+type BreakGlassCredential struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// Standard object metadata; More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#metadata.
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec is the specification of the credential.
+	Spec BreakGlassCredentialSpec `json:"spec"`
+
+	// Status is the most recently observed status of the credential.
+	// +optional
+	Status BreakGlassCredentialStatus `json:"status,omitempty"`
+}
+
+// BreakGlassCredentialSpec is the specification of a break-glass credential.
+type BreakGlassCredentialSpec struct {
+	// ClusterName is the name of the downstream cluster the credential gives access to. The cluster must have the
+	// Authorized Cluster Endpoint enabled.
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="clusterName is immutable"
+	ClusterName string `json:"clusterName"`
+
+	// UserName is the name of the user the credential authenticates as. The credential is only issued if its creator,
+	// recorded at admission in the field.cattle.io/creatorId annotation, is that user or is allowed to impersonate
+	// that user.
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="userName is immutable"
+	UserName string `json:"userName"`
+
+	// TTLSeconds is how long the credential is valid for after it is issued. It is capped by the
+	// break-glass-credential-max-ttl setting, which is also the default.
+	// +optional
+	// +kubebuilder:validation:Minimum=300
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="ttlSeconds is immutable"
+	TTLSeconds int64 `json:"ttlSeconds,omitempty"`
+
+	// Reason is a human readable description of why the credential exists.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+}
+
+// BreakGlassCredentialStatus is the most recently observed status of a break-glass credential.
+type BreakGlassCredentialStatus struct {
+	// IssuedAt is the time the token of the credential was signed at.
+	// +optional
+	IssuedAt *metav1.Time `json:"issuedAt,omitempty"`
+
+	// ExpiresAt is the time the token of the credential expires at.
+	// +optional
+	ExpiresAt *metav1.Time `json:"expiresAt,omitempty"`
+
+	// SecretName is the name of the Secret in the cattle-system namespace holding the token of the credential in its
+	// token entry. The Secret is deleted once the credential expires.
+	// +optional
+	SecretName string `json:"secretName,omitempty"`
+
+	// Groups are the group principals of the user at the time the credential was issued, which the token carries.
+	// +optional
+	Groups []string `json:"groups,omitempty"`
+
+	// FirstUsedAt is the time of the first request the downstream cluster reported for the credential.
+	// +optional
+	FirstUsedAt *metav1.Time `json:"firstUsedAt,omitempty"`
+
+	// LastUsedAt is the time of the latest request the downstream cluster reported for the credential.
+	// +optional
+	LastUsedAt *metav1.Time `json:"lastUsedAt,omitempty"`
+
+	// RequestCount is the number of requests the downstream cluster reported for the credential.
+	// +optional
+	RequestCount int64 `json:"requestCount,omitempty"`
+
+	// AcknowledgedRequestCount is the request count at the time the usage was last acknowledged.
+	// +optional
+	AcknowledgedRequestCount int64 `json:"acknowledgedRequestCount,omitempty"`
+
+	// Conditions are the conditions of the credential: Issued, Used, UsageReviewed and Expired.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
@@ -0,0 +1,154 @@
+package v3
+
+import metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+// CertificateAuthorityRotationPhase is the phase of a certificate authority rotation.
+type CertificateAuthorityRotationPhase string
+
+const (
+	// CertificateAuthorityRotationPhasePublishing indicates a new certificate authority is being generated and
+	// published in the cacerts setting alongside the current one.
+	CertificateAuthorityRotationPhasePublishing CertificateAuthorityRotationPhase = "Publishing"
+
+	// CertificateAuthorityRotationPhaseWaitingForAgents indicates the rotation is waiting for the cluster agents and
+	// system agents of every cluster to trust the certificate authorities bundle.
+	CertificateAuthorityRotationPhaseWaitingForAgents CertificateAuthorityRotationPhase = "WaitingForAgents"
+
+	// CertificateAuthorityRotationPhaseSwitching indicates the Rancher serving certificate is being re-issued by the
+	// new certificate authority.
+	CertificateAuthorityRotationPhaseSwitching CertificateAuthorityRotationPhase = "Switching"
+
+	// CertificateAuthorityRotationPhaseCompleted indicates the previous certificate authority has been dropped.
+	CertificateAuthorityRotationPhaseCompleted CertificateAuthorityRotationPhase = "Completed"
+
+	// CertificateAuthorityRotationPhaseFailed indicates the rotation cannot be performed.
+	CertificateAuthorityRotationPhaseFailed CertificateAuthorityRotationPhase = "Failed"
+)
+
+// CertificateAuthorityRotationClusterState is the state of a cluster during a certificate authority rotation.
+type CertificateAuthorityRotationClusterState string
+
+const (
+	// CertificateAuthorityRotationClusterPending indicates some agents of the cluster do not trust the new
+	// certificate authority yet.
+	CertificateAuthorityRotationClusterPending CertificateAuthorityRotationClusterState = "Pending"
+
+	// CertificateAuthorityRotationClusterTrusted indicates every agent of the cluster trusts the new certificate
+	// authority.
+	CertificateAuthorityRotationClusterTrusted CertificateAuthorityRotationClusterState = "Trusted"
+
+	// CertificateAuthorityRotationClusterSkipped indicates the cluster was unavailable and is not waited for.
+	CertificateAuthorityRotationClusterSkipped CertificateAuthorityRotationClusterState = "Skipped"
+)
+
+// +genclient
+// +genclient:nonNamespaced
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="Message",type="string",JSONPath=".status.message"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CertificateAuthorityRotation replaces the certificate authority generated by Rancher, which signs the Rancher
+// serving certificate and is published in the cacerts setting, without re-registering the downstream agents.
+// The rotation first publishes a bundle holding both the current and the new certificate authority, then waits until
+// every cluster agent and system agent trusts the bundle, and finally switches the serving certificate to the new
+// certificate authority and drops the previous one.
+// Only one rotation can be in progress at a time. Deleting a rotation before the serving certificate is switched
+// cancels it.
+type CertificateAuthorityRotation struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// Standard object metadata; More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#metadata.
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec is the specification of the rotation.
+	// +optional
+	Spec CertificateAuthorityRotationSpec `json:"spec,omitempty"`
+
+	// Status is the most recently observed status of the rotation.
+	// +optional
+	Status CertificateAuthorityRotationStatus `json:"status,omitempty"`
+}
+
+// CertificateAuthorityRotationSpec is the specification of a certificate authority rotation.
+type CertificateAuthorityRotationSpec struct {
+	// SkipUnavailableClusters makes the rotation proceed without waiting for the agents of clusters that are not
+	// ready. The agents of skipped clusters will not trust the Rancher serving certificate once it is switched, and
+	// must be re-registered.
+	// +optional
+	SkipUnavailableClusters bool `json:"skipUnavailableClusters,omitempty"`
+}
+
+// CertificateAuthorityRotationStatus is the most recently observed status of a certificate authority rotation.
+type CertificateAuthorityRotationStatus struct {
+	// Phase is the current phase of the rotation.
+	// +optional
+	Phase CertificateAuthorityRotationPhase `json:"phase,omitempty"`
+
+	// Message is a human-readable description of the current phase.
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// PreviousCAFingerprint is the SHA-256 fingerprint of the certificate authority being replaced.
+	// +optional
+	PreviousCAFingerprint string `json:"previousCAFingerprint,omitempty"`
+
+	// NewCAFingerprint is the SHA-256 fingerprint of the new certificate authority.
+	// +optional
+	NewCAFingerprint string `json:"newCAFingerprint,omitempty"`
+
+	// TrustBundleChecksum is the checksum of the cacerts setting holding both certificate authorities, as pinned by
+	// the agents through CATTLE_CA_CHECKSUM.
+	// +optional
+	TrustBundleChecksum string `json:"trustBundleChecksum,omitempty"`
+
+	// Clusters is the progress of every downstream cluster.
+	// +optional
+	// +listType=map
+	// +listMapKey=clusterName
+	Clusters []CertificateAuthorityRotationClusterStatus `json:"clusters,omitempty"`
+
+	// LastUpdated is the last time the phase changed.
+	// +optional
+	LastUpdated metav1.Time `json:"lastUpdated,omitempty"`
+}
+
+// CertificateAuthorityRotationClusterStatus is the progress of a downstream cluster in a certificate authority
+// rotation.
+type CertificateAuthorityRotationClusterStatus struct {
+	// ClusterName is the name of the management cluster.
+	ClusterName string `json:"clusterName"`
+
+	// State is whether every agent of the cluster trusts the new certificate authority.
+	State CertificateAuthorityRotationClusterState `json:"state"`
+
+	// ClusterAgentTrusted is whether the cluster agent trusts the new certificate authority.
+	// +optional
+	ClusterAgentTrusted bool `json:"clusterAgentTrusted,omitempty"`
+
+	// SystemAgents is the number of system agents of the cluster. It is only set for RKE2/K3s clusters provisioned
+	// by Rancher.
+	// +optional
+	SystemAgents int `json:"systemAgents,omitempty"`
+
+	// SystemAgentsTrusted is the number of system agents of the cluster that trust the new certificate authority.
+	// +optional
+	SystemAgentsTrusted int `json:"systemAgentsTrusted,omitempty"`
+
+	// Message describes what the cluster is waiting for.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// SetPhase sets the phase and the message of the rotation.
+func (s *CertificateAuthorityRotationStatus) SetPhase(phase CertificateAuthorityRotationPhase, message string) {
+	s.Message = message
+	if s.Phase == phase {
+		return
+	}
+	s.Phase = phase
+	s.LastUpdated = metav1.Now()
+}
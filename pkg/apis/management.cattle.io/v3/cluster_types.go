@@ -233,10 +233,72 @@ type ClusterStatus struct {
 	AppliedClusterAgentImagePullSecretsHash    string                          `json:"appliedClusterAgentImagePullSecretsHash,omitempty"`
 	AppliedWebhookDeploymentCustomization      *WebhookDeploymentCustomization `json:"appliedWebhookDeploymentCustomization,omitempty"`
 
+	// AgentCACertsChecksum is the SHA-256 checksum of the CA certificates the cluster agent verifies the Rancher server
+	// with, as reported by the agent. It is empty if the agent does not use CA certificates from Rancher.
+	AgentCACertsChecksum string `json:"agentCACertsChecksum,omitempty" norman:"nocreate,noupdate"`
+
 	// ReadyReconciling indicates that the cluster's readiness state is currently being managed by provisioning controller.
 	// Currently used only for v2prov clusters. When true, secondary health controllers (like HealthSyncer, Connected) should avoid updating Ready condition to prevent state flapping.
 	ReadyReconciling bool         `json:"readyReconciling,omitempty"`
 	Info             *ClusterInfo `json:"info,omitempty"`
+
+	// DriverProgress is the latest progress reported by the kontainer driver of the cluster while it creates, updates
+	// or upgrades the cluster.
+	DriverProgress *ClusterDriverProgress `json:"driverProgress,omitempty" norman:"nocreate,noupdate"`
+
+	// Drift reports the Rancher-managed resources of an imported cluster that were changed or deleted in the
+	// downstream cluster, as found by the latest drift scan.
+	Drift *ClusterDriftStatus `json:"drift,omitempty" norman:"nocreate,noupdate"`
+}
+
+// ClusterDriverProgress is a progress event reported by a kontainer driver.
+type ClusterDriverProgress struct {
+	// Phase is the call of the driver reporting the progress, either Provisioning or Updating.
+	Phase string `json:"phase,omitempty"`
+	// Step is the name of the current step of the call.
+	Step string `json:"step,omitempty"`
+	// Percent is the completion of the call, from 0 to 100.
+	Percent int `json:"percent,omitempty"`
+	// Message describes the progress of the step.
+	Message string `json:"message,omitempty"`
+	// Warning indicates that the message reports a problem the driver recovers from.
+	Warning bool `json:"warning,omitempty"`
+	// Resources are the cloud resources the step created or changed.
+	Resources []ClusterDriverResource `json:"resources,omitempty"`
+	// LastUpdateTime is the time the progress was reported at.
+	LastUpdateTime string `json:"lastUpdateTime,omitempty"`
+}
+
+// ClusterDriftStatus is the report of the drift scan of an imported cluster.
+type ClusterDriftStatus struct {
+	// Objects are the downstream objects that differ from the state Rancher manages them in.
+	Objects []ClusterDriftedObject `json:"objects,omitempty"`
+	// LastUpdateTime is the time the drifted objects last changed at.
+	LastUpdateTime string `json:"lastUpdateTime,omitempty"`
+}
+
+// ClusterDriftedObject is a downstream object that differs from the state Rancher manages it in.
+type ClusterDriftedObject struct {
+	// Kind is the kind of the object, such as ClusterRole, ClusterRoleBinding, RoleBinding or Deployment.
+	Kind string `json:"kind,omitempty"`
+	// Namespace is the namespace of the object, empty for cluster scoped objects.
+	Namespace string `json:"namespace,omitempty"`
+	// Name is the name of the object.
+	Name string `json:"name,omitempty"`
+	// Reason is either Missing, if the object was deleted, or Modified.
+	Reason string `json:"reason,omitempty"`
+	// Message describes the difference.
+	Message string `json:"message,omitempty"`
+	// Restored indicates that the object was restored to its desired state.
+	Restored bool `json:"restored,omitempty"`
+}
+
+// ClusterDriverResource identifies a cloud resource of a cluster created by a kontainer driver.
+type ClusterDriverResource struct {
+	// Type is the type of the resource, such as NodeGroup.
+	Type string `json:"type,omitempty"`
+	// ID is the identifier of the resource in the cloud provider.
+	ID string `json:"id,omitempty"`
 }
 
 // ClusterInfo provides aggregated cluster metadata for UI display.
@@ -32,6 +32,9 @@ type ManagedChartSpec struct {
 	TargetNamespace  string             `json:"namespace,omitempty"`
 	ServiceAccount   string             `json:"serviceAccount,omitempty"`
 	Diff             *fleet.DiffOptions `json:"diff,omitempty"`
+	// CorrectDrift enables Fleet to roll back resources of the chart that were modified in downstream clusters. When it
+	// is not enabled, drifted resources are only reported in the status.
+	CorrectDrift *fleet.CorrectDrift `json:"correctDrift,omitempty"`
 
 	RolloutStrategy *fleet.RolloutStrategy `json:"rolloutStrategy,omitempty"`
 	Targets         []fleet.BundleTarget   `json:"targets,omitempty"`
@@ -39,4 +42,35 @@ type ManagedChartSpec struct {
 
 type ManagedChartStatus struct {
 	fleet.BundleStatus
+
+	// Clusters is the deployment status of the chart in each targeted cluster.
+	// +nullable
+	Clusters []ManagedChartClusterStatus `json:"clusters,omitempty"`
+}
+
+// ManagedChartClusterStatus is the deployment status of a ManagedChart in a single downstream cluster, as reported by
+// the Fleet BundleDeployment of the cluster.
+type ManagedChartClusterStatus struct {
+	// ClusterName is the name of the Fleet cluster.
+	ClusterName string `json:"clusterName,omitempty"`
+	// ClusterNamespace is the namespace of the Fleet cluster.
+	ClusterNamespace string `json:"clusterNamespace,omitempty"`
+	// State is the Fleet bundle state of the chart in the cluster, e.g. Ready, Modified or ErrApplied.
+	State string `json:"state,omitempty"`
+	// Ready is true when all resources of the chart are ready in the cluster.
+	Ready bool `json:"ready,omitempty"`
+	// InSync is true when the desired version of the chart is installed and none of its resources drifted.
+	InSync bool `json:"inSync,omitempty"`
+	// DesiredVersion is the chart version the cluster should run.
+	DesiredVersion string `json:"desiredVersion,omitempty"`
+	// InstalledVersion is the chart version last installed in the cluster.
+	InstalledVersion string `json:"installedVersion,omitempty"`
+	// ModifiedResources are the resources of the chart that drifted from the desired state, with Fleet's diff.
+	// +nullable
+	ModifiedResources []fleet.ModifiedStatus `json:"modifiedResources,omitempty"`
+	// NonReadyResources are the resources of the chart that are not ready in the cluster.
+	// +nullable
+	NonReadyResources []fleet.NonReadyStatus `json:"nonReadyResources,omitempty"`
+	// IncompleteState is true when Fleet truncated the lists of modified and non-ready resources.
+	IncompleteState bool `json:"incompleteState,omitempty"`
 }
@@ -0,0 +1,235 @@
+package v3
+
+import metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+// ManagementRestorePhase is the phase of a management restore.
+type ManagementRestorePhase string
+
+const (
+	// ManagementRestorePhaseRestoring indicates the snapshot was validated and its objects are being restored.
+	ManagementRestorePhaseRestoring ManagementRestorePhase = "Restoring"
+
+	// ManagementRestorePhaseCompleted indicates every object of the snapshot was restored.
+	ManagementRestorePhaseCompleted ManagementRestorePhase = "Completed"
+
+	// ManagementRestorePhaseFailed indicates the snapshot cannot be restored, or some of its objects failed to restore.
+	ManagementRestorePhaseFailed ManagementRestorePhase = "Failed"
+)
+
+// +genclient
+// +genclient:nonNamespaced
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:printcolumn:name="Schedule",type="string",JSONPath=".spec.schedule"
+// +kubebuilder:printcolumn:name="Last Backup",type="date",JSONPath=".status.lastBackupTime"
+// +kubebuilder:printcolumn:name="Next Backup",type="date",JSONPath=".status.nextBackupTime"
+// +kubebuilder:printcolumn:name="Error",type="string",JSONPath=".status.lastError"
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ManagementBackup takes encrypted snapshots of the state of Rancher held in the local cluster: the objects of the
+// management.cattle.io, provisioning.cattle.io, rke.cattle.io and cluster.x-k8s.io API groups, the Secrets and
+// Namespaces they reference, the Secrets of the cattle-system namespace, the password hashes of local users, and the
+// state of the migrations Rancher ran. Snapshots are stored on a volume mounted in the Rancher pods or in an S3
+// compatible bucket, and are restored with a ManagementRestore.
+type ManagementBackup struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// Standard object metadata; More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#metadata.
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec is the specification of the backup.
+	Spec ManagementBackupSpec `json:"spec"`
+
+	// Status is the most recently observed status of the backup.
+	// +optional
+	Status ManagementBackupStatus `json:"status,omitempty"`
+}
+
+// ManagementBackupSpec is the specification of a management backup.
+type ManagementBackupSpec struct {
+	// Schedule is the cron expression, in the standard format and in UTC, snapshots are taken on. When empty, a single
+	// snapshot is taken once the backup is created.
+	// +optional
+	Schedule string `json:"schedule,omitempty"`
+
+	// Retention is the number of snapshots kept. The oldest snapshots are deleted from the target once a new snapshot
+	// is taken. Defaults to 10.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	Retention int `json:"retention,omitempty"`
+
+	// EncryptionSecretName is the name of the Secret in the cattle-system namespace holding the key snapshots are
+	// encrypted with, in its encryption-key entry.
+	EncryptionSecretName string `json:"encryptionSecretName"`
+
+	// Volume stores the snapshots on a volume mounted in the Rancher pods, such as a PersistentVolumeClaim.
+	// +optional
+	Volume *ManagementBackupVolume `json:"volume,omitempty"`
+
+	// S3 stores the snapshots in an S3 compatible bucket.
+	// +optional
+	S3 *ManagementBackupS3 `json:"s3,omitempty"`
+}
+
+// ManagementBackupVolume is a volume mounted in the Rancher pods snapshots are stored on.
+type ManagementBackupVolume struct {
+	// Path is the directory the volume is mounted at in the Rancher pods.
+	Path string `json:"path"`
+}
+
+// ManagementBackupS3 is an S3 compatible bucket snapshots are stored in.
+type ManagementBackupS3 struct {
+	// Endpoint is the host, and optionally the port, of the S3 API. Defaults to AWS S3.
+	// +optional
+	Endpoint string `json:"endpoint,omitempty"`
+
+	// EndpointCA is the PEM encoded certificate authority the endpoint certificate is verified with, in addition to
+	// the system ones.
+	// +optional
+	EndpointCA string `json:"endpointCA,omitempty"`
+
+	// Insecure connects to the endpoint over plain HTTP.
+	// +optional
+	Insecure bool `json:"insecure,omitempty"`
+
+	// Region is the region of the bucket.
+	// +optional
+	Region string `json:"region,omitempty"`
+
+	// Bucket is the name of the bucket.
+	Bucket string `json:"bucket"`
+
+	// Folder is the prefix of the snapshot objects in the bucket.
+	// +optional
+	Folder string `json:"folder,omitempty"`
+
+	// CredentialSecretName is the name of the Secret in the cattle-system namespace holding the accessKey and
+	// secretKey entries used to access the bucket. When empty, the credentials of the Rancher pods are used.
+	// +optional
+	CredentialSecretName string `json:"credentialSecretName,omitempty"`
+}
+
+// ManagementBackupStatus is the most recently observed status of a management backup.
+type ManagementBackupStatus struct {
+	// LastBackupTime is the time the latest snapshot was taken at.
+	// +optional
+	LastBackupTime *metav1.Time `json:"lastBackupTime,omitempty"`
+
+	// NextBackupTime is the time the next snapshot is scheduled at.
+	// +optional
+	NextBackupTime *metav1.Time `json:"nextBackupTime,omitempty"`
+
+	// LastError is the error the latest attempt to take a snapshot failed with, empty if it succeeded.
+	// +optional
+	LastError string `json:"lastError,omitempty"`
+
+	// Snapshots are the snapshots kept on the target, oldest first.
+	// +optional
+	Snapshots []ManagementBackupSnapshot `json:"snapshots,omitempty"`
+}
+
+// ManagementBackupSnapshot is a snapshot taken by a management backup.
+type ManagementBackupSnapshot struct {
+	// Name is the name of the snapshot file on the target.
+	Name string `json:"name"`
+
+	// Time is the time the snapshot was taken at.
+	Time metav1.Time `json:"time"`
+
+	// RancherVersion is the version of Rancher the snapshot was taken with.
+	// +optional
+	RancherVersion string `json:"rancherVersion,omitempty"`
+
+	// Objects is the number of objects in the snapshot.
+	// +optional
+	Objects int `json:"objects,omitempty"`
+
+	// Size is the size of the encrypted snapshot in bytes.
+	// +optional
+	Size int64 `json:"size,omitempty"`
+}
+
+// +genclient
+// +genclient:nonNamespaced
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:printcolumn:name="Backup",type="string",JSONPath=".spec.backupName"
+// +kubebuilder:printcolumn:name="Snapshot",type="string",JSONPath=".status.snapshotName"
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="Message",type="string",JSONPath=".status.message"
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ManagementRestore restores a snapshot taken by a ManagementBackup. Snapshots taken by a newer version of Rancher are
+// rejected. Namespaces, Secrets and the objects other objects depend on are restored first, and owner references are
+// updated to the restored owners. Objects that exist are updated, and objects missing from the snapshot are kept.
+// When the snapshot predates some of the migrations Rancher ran, they run again once Rancher restarts.
+type ManagementRestore struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// Standard object metadata; More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#metadata.
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec is the specification of the restore.
+	Spec ManagementRestoreSpec `json:"spec"`
+
+	// Status is the most recently observed status of the restore.
+	// +optional
+	Status ManagementRestoreStatus `json:"status,omitempty"`
+}
+
+// ManagementRestoreSpec is the specification of a management restore.
+type ManagementRestoreSpec struct {
+	// BackupName is the name of the ManagementBackup whose target and encryption key the snapshot is read with.
+	BackupName string `json:"backupName"`
+
+	// SnapshotName is the name of the snapshot to restore. Defaults to the latest snapshot of the backup.
+	// +optional
+	SnapshotName string `json:"snapshotName,omitempty"`
+}
+
+// ManagementRestoreStatus is the most recently observed status of a management restore.
+type ManagementRestoreStatus struct {
+	// Phase is the current phase of the restore.
+	// +optional
+	Phase ManagementRestorePhase `json:"phase,omitempty"`
+
+	// Message is a human-readable description of the current phase.
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// SnapshotName is the name of the snapshot being restored.
+	// +optional
+	SnapshotName string `json:"snapshotName,omitempty"`
+
+	// RancherVersion is the version of Rancher the snapshot was taken with.
+	// +optional
+	RancherVersion string `json:"rancherVersion,omitempty"`
+
+	// RestoredObjects is the number of objects restored.
+	// +optional
+	RestoredObjects int `json:"restoredObjects,omitempty"`
+
+	// FailedObjects are the objects that failed to restore, with their error.
+	// +optional
+	FailedObjects []string `json:"failedObjects,omitempty"`
+
+	// PendingMigrations are the migrations the snapshot predates, which run again once Rancher restarts.
+	// +optional
+	PendingMigrations []string `json:"pendingMigrations,omitempty"`
+
+	// LastUpdated is the last time the phase changed.
+	// +optional
+	LastUpdated metav1.Time `json:"lastUpdated,omitempty"`
+}
+
+// SetPhase sets the phase and the message of the restore.
+func (s *ManagementRestoreStatus) SetPhase(phase ManagementRestorePhase, message string) {
+	s.Message = message
+	if s.Phase == phase {
+		return
+	}
+	s.Phase = phase
+	s.LastUpdated = metav1.Now()
+}
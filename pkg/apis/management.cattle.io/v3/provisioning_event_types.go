@@ -0,0 +1,93 @@
+package v3
+
+import metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+const (
+	// ProvisioningEventSourceLabel is the label holding the source of a provisioning event, so that the timeline of
+	// a cluster can be filtered by source.
+	ProvisioningEventSourceLabel = "provisioning.cattle.io/event-source"
+	// ProvisioningEventSeverityLabel is the label holding the severity of a provisioning event.
+	ProvisioningEventSeverityLabel = "provisioning.cattle.io/event-severity"
+)
+
+// ProvisioningEventSeverity is the severity of a provisioning event.
+type ProvisioningEventSeverity string
+
+const (
+	// ProvisioningEventSeverityInfo indicates the event reports the progress of the provisioning.
+	ProvisioningEventSeverityInfo ProvisioningEventSeverity = "Info"
+	// ProvisioningEventSeverityWarning indicates the event reports a transient error the provisioning is retried on.
+	ProvisioningEventSeverityWarning ProvisioningEventSeverity = "Warning"
+	// ProvisioningEventSeverityError indicates the event reports a failure of the provisioning.
+	ProvisioningEventSeverityError ProvisioningEventSeverity = "Error"
+)
+
+// ProvisioningEventSource is the controller that recorded a provisioning event.
+type ProvisioningEventSource string
+
+const (
+	// ProvisioningEventSourcePlanner is the planner, which reports the provisioning and updates of the cluster.
+	ProvisioningEventSourcePlanner ProvisioningEventSource = "planner"
+	// ProvisioningEventSourceMachineProvision is the controller creating and deleting the machines of node pools.
+	ProvisioningEventSourceMachineProvision ProvisioningEventSource = "machineprovision"
+	// ProvisioningEventSourceEtcd is the controller taking and restoring etcd snapshots.
+	ProvisioningEventSourceEtcd ProvisioningEventSource = "etcd"
+	// ProvisioningEventSourceClusterDriver is the cluster driver provisioning a hosted or imported cluster.
+	ProvisioningEventSourceClusterDriver ProvisioningEventSource = "clusterdriver"
+	// ProvisioningEventSourceDrift is the drift scan of the Rancher-managed resources of imported clusters.
+	ProvisioningEventSourceDrift ProvisioningEventSource = "drift"
+)
+
+// +genclient
+// +kubebuilder:resource:scope=Namespaced
+// +kubebuilder:printcolumn:name="Time",type="date",JSONPath=".spec.timestamp"
+// +kubebuilder:printcolumn:name="Source",type="string",JSONPath=".spec.source"
+// +kubebuilder:printcolumn:name="Severity",type="string",JSONPath=".spec.severity"
+// +kubebuilder:printcolumn:name="Machine",type="string",JSONPath=".spec.machineName"
+// +kubebuilder:printcolumn:name="Message",type="string",JSONPath=".spec.message"
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ProvisioningEvent is an entry of the provisioning timeline of a cluster. Events are recorded in the namespace of the
+// management cluster and are never updated.
+// Event names start with the hexadecimal time they were recorded at, so that listing the events of a cluster, including
+// page by page, returns them in chronological order. Events are deleted once they are older than the
+// provisioning-event-retention-hours setting, or when a cluster holds more events than the
+// provisioning-event-limit setting.
+type ProvisioningEvent struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// Standard object metadata; More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#metadata.
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec is the content of the event.
+	Spec ProvisioningEventSpec `json:"spec"`
+}
+
+// ProvisioningEventSpec is the content of a provisioning event.
+type ProvisioningEventSpec struct {
+	// ClusterName is the name of the management cluster the event is about.
+	ClusterName string `json:"clusterName"`
+
+	// Timestamp is the time the event was recorded at.
+	Timestamp metav1.Time `json:"timestamp"`
+
+	// MachineName is the name of the machine the event is about, if any.
+	// +optional
+	MachineName string `json:"machineName,omitempty"`
+
+	// Phase is the step of the provisioning the event was recorded in, such as Provisioning, Updating, Create,
+	// Delete, SnapshotCreate or SnapshotRestore.
+	// +optional
+	Phase string `json:"phase,omitempty"`
+
+	// Severity is the severity of the event.
+	// +kubebuilder:validation:Enum=Info;Warning;Error
+	Severity ProvisioningEventSeverity `json:"severity"`
+
+	// Source is the controller that recorded the event.
+	Source ProvisioningEventSource `json:"source"`
+
+	// Message is the human-readable description of the event.
+	Message string `json:"message"`
+}
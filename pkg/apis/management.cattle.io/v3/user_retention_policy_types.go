@@ -0,0 +1,64 @@
+package v3
+
+import metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+// UserDisabledAtAnnotation records when the user retention process disabled a user. It is the start of the period
+// after which a UserRetentionPolicy with DeleteAfterDisable deletes the user.
+const UserDisabledAtAnnotation = "cattle.io/disabled-at"
+
+// +genclient
+// +genclient:nonNamespaced
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:printcolumn:name="Priority",type="integer",JSONPath=".spec.priority"
+// +kubebuilder:printcolumn:name="Disable After",type="string",JSONPath=".spec.disableAfter"
+// +kubebuilder:printcolumn:name="Delete After",type="string",JSONPath=".spec.deleteAfter"
+// +kubebuilder:printcolumn:name="Delete After Disable",type="string",JSONPath=".spec.deleteAfterDisable"
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// UserRetentionPolicy overrides the disable-inactive-user-after and delete-inactive-user-after settings for the users
+// it selects by auth provider and group principal. When more than one policy selects a user, the one with the highest
+// priority applies, with ties broken by name. Per-user overrides in the UserAttribute still take precedence.
+type UserRetentionPolicy struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// Standard object metadata; More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#metadata.
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec is the specification of the policy.
+	Spec UserRetentionPolicySpec `json:"spec"`
+}
+
+// UserRetentionPolicySpec is the specification of a user retention policy.
+type UserRetentionPolicySpec struct {
+	// Priority orders the policies selecting the same user. The policy with the highest priority applies.
+	// +optional
+	Priority int `json:"priority,omitempty"`
+
+	// AuthProviders selects users with a principal of any of the listed auth providers e.g. "local", "github",
+	// "activedirectory". A user is only considered a local user if it has no principal of another provider.
+	// If empty, users of all providers are selected.
+	// +optional
+	AuthProviders []string `json:"authProviders,omitempty"`
+
+	// GroupPrincipals selects users that are members of any of the listed groups, as last recorded in their
+	// UserAttribute e.g. "okta_group://contractors". If empty, group membership isn't considered.
+	// +optional
+	GroupPrincipals []string `json:"groupPrincipals,omitempty"`
+
+	// DisableAfter is the period of inactivity after which a user is disabled. Zero means never.
+	// If not set, the disable-inactive-user-after setting applies.
+	// +optional
+	DisableAfter *metav1.Duration `json:"disableAfter,omitempty"`
+
+	// DeleteAfter is the period of inactivity after which a user is deleted. Zero means never.
+	// If not set, the delete-inactive-user-after setting applies.
+	// +optional
+	DeleteAfter *metav1.Duration `json:"deleteAfter,omitempty"`
+
+	// DeleteAfterDisable is the period after the user retention process disabled a user after which it is deleted,
+	// regardless of its last login. Users disabled otherwise, such as by an admin, aren't deleted for having been
+	// disabled. If not set or zero, users aren't deleted for having been disabled.
+	// +optional
+	DeleteAfterDisable *metav1.Duration `json:"deleteAfterDisable,omitempty"`
+}
@@ -0,0 +1,73 @@
+package v3
+
+import metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+// +genclient
+// +genclient:nonNamespaced
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:printcolumn:name="Cluster",type="string",JSONPath=".spec.clusterName"
+// +kubebuilder:printcolumn:name="Namespace",type="string",JSONPath=".spec.serviceAccountNamespace"
+// +kubebuilder:printcolumn:name="Service Account",type="string",JSONPath=".spec.serviceAccountName"
+// +kubebuilder:printcolumn:name="User",type="string",JSONPath=".spec.userName"
+// +kubebuilder:printcolumn:name="Group",type="string",JSONPath=".spec.groupPrincipalName"
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// WorkloadIdentityBinding lets the workloads of a downstream cluster running as a ServiceAccount exchange their
+// projected ServiceAccount tokens for short-lived Rancher tokens, following OAuth 2.0 Token Exchange (RFC 8693).
+// The ServiceAccount tokens are verified with the issuer and the signing keys of the downstream cluster. The Rancher
+// tokens act as the user, or as a member of the group, the binding maps the ServiceAccount to. Tokens are only issued
+// if the creator of the binding, recorded at admission in the field.cattle.io/creatorId annotation, is that user or
+// may impersonate that user or group. Tokens scoped to a cluster also require that user or group to have access to
+// the cluster. Tokens issued for a binding are deleted with it.
+type WorkloadIdentityBinding struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// Standard object metadata; More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#metadata.
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec is the specification of the binding.
+	Spec WorkloadIdentityBindingSpec `json:"spec"`
+}
+
+// WorkloadIdentityBindingSpec is the specification of a workload identity binding.
+type WorkloadIdentityBindingSpec struct {
+	// ClusterName is the name of the management cluster the ServiceAccount is in.
+	// +kubebuilder:validation:MinLength=1
+	ClusterName string `json:"clusterName"`
+
+	// ServiceAccountNamespace is the namespace of the ServiceAccount.
+	// +kubebuilder:validation:MinLength=1
+	ServiceAccountNamespace string `json:"serviceAccountNamespace"`
+
+	// ServiceAccountName is the name of the ServiceAccount.
+	// +kubebuilder:validation:MinLength=1
+	ServiceAccountName string `json:"serviceAccountName"`
+
+	// Audience is the audience the ServiceAccount tokens must be issued for. Defaults to the server-url setting.
+	// +optional
+	Audience string `json:"audience,omitempty"`
+
+	// UserName is the name of the Rancher user issued tokens act as. Exactly one of UserName and GroupPrincipalName
+	// must be set.
+	// +optional
+	UserName string `json:"userName,omitempty"`
+
+	// GroupPrincipalName is the name of the group principal issued tokens are a member of, e.g.
+	// okta_group://ci-runners. The tokens act as a system user created for the binding, which has no permissions
+	// other than those granted to the group. Exactly one of UserName and GroupPrincipalName must be set.
+	// +optional
+	GroupPrincipalName string `json:"groupPrincipalName,omitempty"`
+
+	// TokenTTLSeconds is the lifetime of issued tokens in seconds. Defaults to 900. Tokens never outlive the
+	// auth-token-max-ttl-minutes setting.
+	// +optional
+	// +kubebuilder:validation:Minimum=60
+	// +kubebuilder:validation:Maximum=86400
+	TokenTTLSeconds int64 `json:"tokenTTLSeconds,omitempty"`
+
+	// TokenClusterName scopes issued tokens to the downstream cluster of that name, so that they can only be used
+	// to access that cluster through its authorized cluster endpoint. Issued tokens aren't scoped by default.
+	// +optional
+	TokenClusterName string `json:"tokenClusterName,omitempty"`
+}
@@ -128,6 +128,40 @@ func NewAzureADProvider(namespace, name string, obj AzureADProvider) *AzureADPro
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
+// BreakGlassCredentialList is a list of BreakGlassCredential resources
+type BreakGlassCredentialList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []BreakGlassCredential `json:"items"`
+}
+
+func NewBreakGlassCredential(namespace, name string, obj BreakGlassCredential) *BreakGlassCredential {
+	obj.APIVersion, obj.Kind = SchemeGroupVersion.WithKind("BreakGlassCredential").ToAPIVersionAndKind()
+	obj.Name = name
+	obj.Namespace = namespace
+	return &obj
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CertificateAuthorityRotationList is a list of CertificateAuthorityRotation resources
+type CertificateAuthorityRotationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []CertificateAuthorityRotation `json:"items"`
+}
+
+func NewCertificateAuthorityRotation(namespace, name string, obj CertificateAuthorityRotation) *CertificateAuthorityRotation {
+	obj.APIVersion, obj.Kind = SchemeGroupVersion.WithKind("CertificateAuthorityRotation").ToAPIVersionAndKind()
+	obj.Name = name
+	obj.Namespace = namespace
+	return &obj
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
 // CloudCredentialList is a list of CloudCredential resources
 type CloudCredentialList struct {
 	metav1.TypeMeta `json:",inline"`
@@ -502,6 +536,40 @@ func NewManagedChart(namespace, name string, obj ManagedChart) *ManagedChart {
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
+// ManagementBackupList is a list of ManagementBackup resources
+type ManagementBackupList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []ManagementBackup `json:"items"`
+}
+
+func NewManagementBackup(namespace, name string, obj ManagementBackup) *ManagementBackup {
+	obj.APIVersion, obj.Kind = SchemeGroupVersion.WithKind("ManagementBackup").ToAPIVersionAndKind()
+	obj.Name = name
+	obj.Namespace = namespace
+	return &obj
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ManagementRestoreList is a list of ManagementRestore resources
+type ManagementRestoreList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []ManagementRestore `json:"items"`
+}
+
+func NewManagementRestore(namespace, name string, obj ManagementRestore) *ManagementRestore {
+	obj.APIVersion, obj.Kind = SchemeGroupVersion.WithKind("ManagementRestore").ToAPIVersionAndKind()
+	obj.Name = name
+	obj.Namespace = namespace
+	return &obj
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
 // NodeList is a list of Node resources
 type NodeList struct {
 	metav1.TypeMeta `json:",inline"`
@@ -689,6 +757,23 @@ func NewProjectRoleTemplateBinding(namespace, name string, obj ProjectRoleTempla
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
+// ProvisioningEventList is a list of ProvisioningEvent resources
+type ProvisioningEventList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []ProvisioningEvent `json:"items"`
+}
+
+func NewProvisioningEvent(namespace, name string, obj ProvisioningEvent) *ProvisioningEvent {
+	obj.APIVersion, obj.Kind = SchemeGroupVersion.WithKind("ProvisioningEvent").ToAPIVersionAndKind()
+	obj.Name = name
+	obj.Namespace = namespace
+	return &obj
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
 // ProxyEndpointList is a list of ProxyEndpoint resources
 type ProxyEndpointList struct {
 	metav1.TypeMeta `json:",inline"`
@@ -839,3 +924,37 @@ func NewUserAttribute(namespace, name string, obj UserAttribute) *UserAttribute
 	obj.Namespace = namespace
 	return &obj
 }
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// UserRetentionPolicyList is a list of UserRetentionPolicy resources
+type UserRetentionPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []UserRetentionPolicy `json:"items"`
+}
+
+func NewUserRetentionPolicy(namespace, name string, obj UserRetentionPolicy) *UserRetentionPolicy {
+	obj.APIVersion, obj.Kind = SchemeGroupVersion.WithKind("UserRetentionPolicy").ToAPIVersionAndKind()
+	obj.Name = name
+	obj.Namespace = namespace
+	return &obj
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// WorkloadIdentityBindingList is a list of WorkloadIdentityBinding resources
+type WorkloadIdentityBindingList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []WorkloadIdentityBinding `json:"items"`
+}
+
+func NewWorkloadIdentityBinding(namespace, name string, obj WorkloadIdentityBinding) *WorkloadIdentityBinding {
+	obj.APIVersion, obj.Kind = SchemeGroupVersion.WithKind("WorkloadIdentityBinding").ToAPIVersionAndKind()
+	obj.Name = name
+	obj.Namespace = namespace
+	return &obj
+}
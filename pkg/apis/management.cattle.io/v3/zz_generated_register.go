@@ -34,6 +34,8 @@
 	AuthProviderResourceName                              = "authproviders"
 	AuthTokenResourceName                                 = "authtokens"
 	AzureADProviderResourceName                           = "azureadproviders"
+	BreakGlassCredentialResourceName                      = "breakglasscredentials"
+	CertificateAuthorityRotationResourceName              = "certificateauthorityrotations"
 	CloudCredentialResourceName                           = "cloudcredentials"
 	ClusterResourceName                                   = "clusters"
 	ClusterProxyConfigResourceName                        = "clusterproxyconfigs"
@@ -56,6 +58,8 @@
 	KontainerDriverResourceName                           = "kontainerdrivers"
 	LocalProviderResourceName                             = "localproviders"
 	ManagedChartResourceName                              = "managedcharts"
+	ManagementBackupResourceName                          = "managementbackups"
+	ManagementRestoreResourceName                         = "managementrestores"
 	NodeResourceName                                      = "nodes"
 	NodeDriverResourceName                                = "nodedrivers"
 	OIDCClientResourceName                                = "oidcclients"
@@ -67,6 +71,7 @@
 	ProjectResourceName                                   = "projects"
 	ProjectNetworkPolicyResourceName                      = "projectnetworkpolicies"
 	ProjectRoleTemplateBindingResourceName                = "projectroletemplatebindings"
+	ProvisioningEventResourceName                         = "provisioningevents"
 	ProxyEndpointResourceName                             = "proxyendpoints"
 	RancherUserNotificationResourceName                   = "rancherusernotifications"
 	RoleTemplateResourceName                              = "roletemplates"
@@ -76,6 +81,8 @@
 	TokenResourceName                                     = "tokens"
 	UserResourceName                                      = "users"
 	UserAttributeResourceName                             = "userattributes"
+	UserRetentionPolicyResourceName                       = "userretentionpolicies"
+	WorkloadIdentityBindingResourceName                   = "workloadidentitybindings"
 )
 
 // SchemeGroupVersion is group version used to register these objects
@@ -111,6 +118,10 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&AuthTokenList{},
 		&AzureADProvider{},
 		&AzureADProviderList{},
+		&BreakGlassCredential{},
+		&BreakGlassCredentialList{},
+		&CertificateAuthorityRotation{},
+		&CertificateAuthorityRotationList{},
 		&CloudCredential{},
 		&CloudCredentialList{},
 		&Cluster{},
@@ -155,6 +166,10 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&LocalProviderList{},
 		&ManagedChart{},
 		&ManagedChartList{},
+		&ManagementBackup{},
+		&ManagementBackupList{},
+		&ManagementRestore{},
+		&ManagementRestoreList{},
 		&Node{},
 		&NodeList{},
 		&NodeDriver{},
@@ -177,6 +192,8 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&ProjectNetworkPolicyList{},
 		&ProjectRoleTemplateBinding{},
 		&ProjectRoleTemplateBindingList{},
+		&ProvisioningEvent{},
+		&ProvisioningEventList{},
 		&ProxyEndpoint{},
 		&ProxyEndpointList{},
 		&RancherUserNotification{},
@@ -195,6 +212,10 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&UserList{},
 		&UserAttribute{},
 		&UserAttributeList{},
+		&UserRetentionPolicy{},
+		&UserRetentionPolicyList{},
+		&WorkloadIdentityBinding{},
+		&WorkloadIdentityBindingList{},
 	)
 	metav1.AddToGroupVersion(scheme, SchemeGroupVersion)
 	return nil
@@ -80,6 +80,17 @@
 	WaitingForEncryptionKeyRotationReason = "WaitingForEncryptionKeyRotation"
 
 	PreflightCheckFailedReason = "PreflightCheckFailed"
+
+	// ArchiveFailedReason surfaces when the collected support bundle could not be packaged, e.g.
+	// because it does not fit into a Secret even after truncating its files.
+	ArchiveFailedReason = "ArchiveFailed"
+
+	// CollectingReason surfaces while the support bundle is collected from the downstream cluster.
+	CollectingReason = "Collecting"
+
+	// NoMachinesSelectedReason surfaces when none of the machines of the cluster matches the
+	// selection of the operation.
+	NoMachinesSelectedReason = "NoMachinesSelected"
 )
 
 func WaitingForDelegateMessage(beacon *planv1alpha1.Beacon) string {
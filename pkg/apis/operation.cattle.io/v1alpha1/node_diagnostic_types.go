@@ -0,0 +1,212 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NodeDiagnosticCommandName is the name of an allow-listed diagnostic command.
+// +kubebuilder:validation:Enum=ContainerList;ServiceJournal;DiskUsage;EtcdMemberList
+type NodeDiagnosticCommandName string
+
+const (
+	// NodeDiagnosticContainerList lists all containers of the node through crictl.
+	NodeDiagnosticContainerList NodeDiagnosticCommandName = "ContainerList"
+
+	// NodeDiagnosticServiceJournal prints the journal of a rancher-system-agent or RKE2/K3s unit.
+	NodeDiagnosticServiceJournal NodeDiagnosticCommandName = "ServiceJournal"
+
+	// NodeDiagnosticDiskUsage prints the usage of the mounted filesystems and of the distribution data directory.
+	NodeDiagnosticDiskUsage NodeDiagnosticCommandName = "DiskUsage"
+
+	// NodeDiagnosticEtcdMemberList lists the etcd members as seen by the local etcd member. It only runs on etcd
+	// nodes.
+	NodeDiagnosticEtcdMemberList NodeDiagnosticCommandName = "EtcdMemberList"
+)
+
+// NodeDiagnosticUnit is the systemd unit whose journal is printed by the ServiceJournal command.
+// +kubebuilder:validation:Enum=SystemAgent;Server;Agent
+type NodeDiagnosticUnit string
+
+const (
+	// NodeDiagnosticUnitSystemAgent is the rancher-system-agent unit.
+	NodeDiagnosticUnitSystemAgent NodeDiagnosticUnit = "SystemAgent"
+
+	// NodeDiagnosticUnitServer is the rke2-server or k3s unit.
+	NodeDiagnosticUnitServer NodeDiagnosticUnit = "Server"
+
+	// NodeDiagnosticUnitAgent is the rke2-agent or k3s-agent unit.
+	NodeDiagnosticUnitAgent NodeDiagnosticUnit = "Agent"
+)
+
+// NodeDiagnosticCommand selects an allow-listed diagnostic command and its parameters. Commands are rendered by the
+// controller; no user-supplied string ever reaches the node.
+// +kubebuilder:validation:XValidation:rule="self.name == 'ServiceJournal' || (!has(self.unit) && !has(self.since) && !has(self.lines))",message="unit, since and lines are only valid for the ServiceJournal command"
+// +kubebuilder:validation:XValidation:rule="!has(self.since) || duration(self.since) <= duration('168h')",message="since must be at most 168h"
+type NodeDiagnosticCommand struct {
+	// Name is the name of the diagnostic command.
+	// +required
+	Name NodeDiagnosticCommandName `json:"name"`
+
+	// Unit is the unit whose journal is printed by the ServiceJournal command.
+	// Defaults to Server when unset.
+	// +optional
+	Unit NodeDiagnosticUnit `json:"unit,omitempty"`
+
+	// Since is how far back the journal is printed by the ServiceJournal command.
+	// Defaults to 1h when unset.
+	// +optional
+	Since *metav1.Duration `json:"since,omitempty"`
+
+	// Lines is the maximum number of most recent journal lines printed by the ServiceJournal command.
+	// Defaults to 500 when unset.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=10000
+	// +optional
+	Lines int `json:"lines,omitempty"`
+}
+
+// NodeDiagnosticRole is a machine role used to select the machines a diagnostic runs on.
+// +kubebuilder:validation:Enum=etcd;controlplane;worker
+type NodeDiagnosticRole string
+
+const (
+	// NodeDiagnosticRoleEtcd selects machines with the etcd role.
+	NodeDiagnosticRoleEtcd NodeDiagnosticRole = "etcd"
+
+	// NodeDiagnosticRoleControlPlane selects machines with the control plane role.
+	NodeDiagnosticRoleControlPlane NodeDiagnosticRole = "controlplane"
+
+	// NodeDiagnosticRoleWorker selects machines with the worker role.
+	NodeDiagnosticRoleWorker NodeDiagnosticRole = "worker"
+)
+
+// NodeDiagnosticSpec defines the desired state of NodeDiagnostic.
+type NodeDiagnosticSpec struct {
+	// OperationSpec is the shared spec common to all operations.
+	// +optional
+	OperationSpec `json:",inline"`
+
+	// Commands are the diagnostic commands run on every selected machine.
+	// +kubebuilder:validation:MinItems=1
+	// +kubebuilder:validation:MaxItems=8
+	// +required
+	Commands []NodeDiagnosticCommand `json:"commands"`
+
+	// MachineNames restricts the diagnostic to the named machines.
+	// When unset, every non-Windows machine matching Roles is selected.
+	// +kubebuilder:validation:MaxItems=50
+	// +listType=set
+	// +optional
+	MachineNames []string `json:"machineNames,omitempty"`
+
+	// Roles restricts the diagnostic to the machines with any of the given roles.
+	// When unset, machines of every role are selected.
+	// +listType=set
+	// +optional
+	Roles []NodeDiagnosticRole `json:"roles,omitempty"`
+
+	// MaxOutputBytes is the maximum size of the output recorded per machine and command. Output exceeding the limit is
+	// truncated to its most recent content. The limit is lowered further when needed to keep the status of the
+	// NodeDiagnostic within the object size limit.
+	// Defaults to 16384 when unset.
+	// +kubebuilder:validation:Minimum=1024
+	// +kubebuilder:validation:Maximum=65536
+	// +optional
+	MaxOutputBytes int `json:"maxOutputBytes,omitempty"`
+}
+
+// NodeDiagnosticStep is the step of the NodeDiagnostic operation.
+type NodeDiagnosticStep string
+
+const (
+	// NodeDiagnosticStepRun indicates the step is running the diagnostic commands on the selected machines.
+	NodeDiagnosticStepRun NodeDiagnosticStep = "Run"
+)
+
+// NodeDiagnosticResult is the captured output of a diagnostic command on a machine.
+type NodeDiagnosticResult struct {
+	// MachineName is the name of the machine the command ran on.
+	MachineName string `json:"machineName"`
+
+	// Command is the name of the diagnostic command.
+	Command NodeDiagnosticCommandName `json:"command"`
+
+	// Output is the combined stdout and stderr of the command. A non-zero exit code is reported on the last line.
+	// +optional
+	Output string `json:"output,omitempty"`
+
+	// Truncated indicates Output was shortened to its most recent content to fit the size limit.
+	// +optional
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// NodeDiagnosticStatus defines the observed state of NodeDiagnostic.
+type NodeDiagnosticStatus struct {
+	// Operation status is the shared status common to all operations.
+	OperationStatus `json:",inline"`
+
+	// Step is the current step of the operation.
+	// Step is typically only valid during the InProgress phase.
+	// +kubebuilder:validation:Enum=Run
+	// +optional
+	Step NodeDiagnosticStep `json:"step,omitempty"`
+
+	// MachinesTotal is the number of machines the diagnostic runs on.
+	// +optional
+	MachinesTotal int `json:"machinesTotal,omitempty"`
+
+	// MachinesCompleted is the number of machines the diagnostic has completed on so far.
+	// +optional
+	MachinesCompleted int `json:"machinesCompleted,omitempty"`
+
+	// Results is the captured output of every command on every selected machine, once the operation has succeeded.
+	// +optional
+	Results []NodeDiagnosticResult `json:"results,omitempty"`
+}
+
+func (s *NodeDiagnosticStatus) SetPhase(phase OperationPhase) {
+	if s.Phase == phase {
+		return
+	}
+	s.Phase = phase
+	s.LastUpdated = metav1.Now()
+}
+
+func (s *NodeDiagnosticStatus) SetStep(step NodeDiagnosticStep) {
+	if s.Step == step {
+		return
+	}
+	s.Step = step
+	s.LastUpdated = metav1.Now()
+}
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:path=nodediagnostics,scope=Namespaced,categories=operations
+// +kubebuilder:subresource:status
+// +kubebuilder:metadata:labels={"auth.cattle.io/cluster-indexed=true"}
+// +kubebuilder:printcolumn:name="Cluster",type=string,JSONPath=".spec.clusterRef.Name"
+// +kubebuilder:printcolumn:name="Paused",type=string,JSONPath=".spec.paused"
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="Step",type=string,JSONPath=".status.step"
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=".metadata.creationTimestamp"
+
+// NodeDiagnostic is the mechanism for running allow-listed diagnostic commands on the machines of an RKE2 or K3s
+// cluster through the system agent. The commands are delivered as one-time instructions while the operation holds the
+// beacon, so the desired plan of the cluster is left unchanged, and their output is recorded in the status.
+type NodeDiagnostic struct {
+	metav1.TypeMeta `json:",inline"`
+	// metadata is the standard object's metadata.
+	// More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec defines the desired state of the NodeDiagnostic.
+	// +required
+	Spec NodeDiagnosticSpec `json:"spec,omitempty"`
+
+	// Status is the observed state of the NodeDiagnostic.
+	// +optional
+	Status NodeDiagnosticStatus `json:"status,omitempty"`
+}
@@ -0,0 +1,138 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SupportBundleArgs contains parameters for collecting a support bundle.
+type SupportBundleArgs struct {
+	// JournalLines is the number of most recent journal lines collected from the rancher-system-agent and RKE2/K3s
+	// units of every node.
+	// Defaults to 1000 when unset.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=10000
+	// +optional
+	JournalLines int `json:"journalLines,omitempty"`
+
+	// PodLogLines is the number of most recent log lines collected from every container of the pods in the
+	// cattle-system namespace of the downstream cluster.
+	// Defaults to 1000 when unset.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=10000
+	// +optional
+	PodLogLines int64 `json:"podLogLines,omitempty"`
+}
+
+// SupportBundleSpec defines the desired state of SupportBundle.
+type SupportBundleSpec struct {
+	// OperationSpec is the shared spec common to all operations.
+	// +optional
+	OperationSpec `json:",inline"`
+
+	// Args contains parameters for collecting the support bundle.
+	// +optional
+	Args SupportBundleArgs `json:"args,omitempty"`
+}
+
+// SupportBundleStep is the step of the SupportBundle operation.
+type SupportBundleStep string
+
+const (
+	// SupportBundleStepJournal indicates the step is collecting journal excerpts from every node through plan
+	// instructions.
+	SupportBundleStepJournal SupportBundleStep = "Journal"
+
+	// SupportBundleStepCollect indicates the step is collecting pod logs, events and nodes from the downstream cluster
+	// and packaging the bundle.
+	SupportBundleStepCollect SupportBundleStep = "Collect"
+)
+
+// SupportBundleArchive describes the archive of a collected support bundle.
+type SupportBundleArchive struct {
+	// SecretName is the name of the Secret in the namespace of the SupportBundle which holds the gzip-compressed
+	// tarball. The Secret is owned by the SupportBundle and removed along with it.
+	SecretName string `json:"secretName,omitempty"`
+
+	// Size is the size of the compressed tarball in bytes.
+	Size int64 `json:"size,omitempty"`
+
+	// SHA256 is the hex-encoded sha256 checksum of the compressed tarball.
+	SHA256 string `json:"sha256,omitempty"`
+
+	// Truncated indicates that some files were shortened to fit the archive into a Secret. Truncated files are
+	// listed in the manifest of the archive.
+	// +optional
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// SupportBundleStatus defines the observed state of SupportBundle.
+type SupportBundleStatus struct {
+	// Operation status is the shared status common to all operations.
+	OperationStatus `json:",inline"`
+
+	// Step is the current step of the operation.
+	// Step is typically only valid during the InProgress phase.
+	// +kubebuilder:validation:Enum=Journal;Collect
+	// +optional
+	Step SupportBundleStep `json:"step,omitempty"`
+
+	// NodesTotal is the number of nodes journal excerpts are collected from.
+	// +optional
+	NodesTotal int `json:"nodesTotal,omitempty"`
+
+	// NodesCollected is the number of nodes journal excerpts have been collected from so far.
+	// +optional
+	NodesCollected int `json:"nodesCollected,omitempty"`
+
+	// Archive describes the collected support bundle once the operation has succeeded.
+	// +optional
+	Archive *SupportBundleArchive `json:"archive,omitempty"`
+}
+
+func (s *SupportBundleStatus) SetPhase(phase OperationPhase) {
+	if s.Phase == phase {
+		return
+	}
+	s.Phase = phase
+	s.LastUpdated = metav1.Now()
+}
+
+func (s *SupportBundleStatus) SetStep(step SupportBundleStep) {
+	if s.Step == step {
+		return
+	}
+	s.Step = step
+	s.LastUpdated = metav1.Now()
+}
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:path=supportbundles,scope=Namespaced,categories=operations
+// +kubebuilder:subresource:status
+// +kubebuilder:metadata:labels={"auth.cattle.io/cluster-indexed=true"}
+// +kubebuilder:printcolumn:name="Cluster",type=string,JSONPath=".spec.clusterRef.Name"
+// +kubebuilder:printcolumn:name="Paused",type=string,JSONPath=".spec.paused"
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="Step",type=string,JSONPath=".status.step"
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=".metadata.creationTimestamp"
+
+// SupportBundle is the mechanism for collecting a support bundle from an RKE2 or K3s downstream cluster. It collects
+// journal excerpts of every node through plan instructions, and cattle-system pod logs, events, node conditions and the
+// Rancher objects of the cluster through the cluster agent connection, into a tarball that can be downloaded once the
+// operation has succeeded.
+type SupportBundle struct {
+	metav1.TypeMeta `json:",inline"`
+	// metadata is the standard object's metadata.
+	// More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec defines the desired state of the SupportBundle.
+	// +required
+	Spec SupportBundleSpec `json:"spec,omitempty"`
+
+	// Status is the observed state of the SupportBundle.
+	// +optional
+	Status SupportBundleStatus `json:"status,omitempty"`
+}
@@ -74,3 +74,37 @@ func NewEncryptionKeyRotation(namespace, name string, obj EncryptionKeyRotation)
 	obj.Namespace = namespace
 	return &obj
 }
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// NodeDiagnosticList is a list of NodeDiagnostic resources
+type NodeDiagnosticList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []NodeDiagnostic `json:"items"`
+}
+
+func NewNodeDiagnostic(namespace, name string, obj NodeDiagnostic) *NodeDiagnostic {
+	obj.APIVersion, obj.Kind = SchemeGroupVersion.WithKind("NodeDiagnostic").ToAPIVersionAndKind()
+	obj.Name = name
+	obj.Namespace = namespace
+	return &obj
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// SupportBundleList is a list of SupportBundle resources
+type SupportBundleList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []SupportBundle `json:"items"`
+}
+
+func NewSupportBundle(namespace, name string, obj SupportBundle) *SupportBundle {
+	obj.APIVersion, obj.Kind = SchemeGroupVersion.WithKind("SupportBundle").ToAPIVersionAndKind()
+	obj.Name = name
+	obj.Namespace = namespace
+	return &obj
+}
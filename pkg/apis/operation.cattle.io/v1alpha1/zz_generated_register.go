@@ -31,6 +31,8 @@
 	ETCDSnapshotRestoreResourceName   = "etcdsnapshotrestores"
 	ETCDSnapshotSaveResourceName      = "etcdsnapshotsaves"
 	EncryptionKeyRotationResourceName = "encryptionkeyrotations"
+	NodeDiagnosticResourceName        = "nodediagnostics"
+	SupportBundleResourceName         = "supportbundles"
 )
 
 // SchemeGroupVersion is group version used to register these objects
@@ -60,6 +62,10 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&ETCDSnapshotSaveList{},
 		&EncryptionKeyRotation{},
 		&EncryptionKeyRotationList{},
+		&NodeDiagnostic{},
+		&NodeDiagnosticList{},
+		&SupportBundle{},
+		&SupportBundleList{},
 	)
 	metav1.AddToGroupVersion(scheme, SchemeGroupVersion)
 	return nil
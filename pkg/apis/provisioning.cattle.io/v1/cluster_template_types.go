@@ -0,0 +1,238 @@
+package v1
+
+import (
+	"github.com/rancher/wrangler/v3/pkg/genericcondition"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ClusterTemplateParameterType is the type of the value of a cluster
+// template parameter.
+// +kubebuilder:validation:Enum=string;int;boolean
+type ClusterTemplateParameterType string
+
+const (
+	// ClusterTemplateParameterTypeString sets the value of the parameter as
+	// a string.
+	ClusterTemplateParameterTypeString ClusterTemplateParameterType = "string"
+
+	// ClusterTemplateParameterTypeInt parses the value of the parameter as
+	// an integer.
+	ClusterTemplateParameterTypeInt ClusterTemplateParameterType = "int"
+
+	// ClusterTemplateParameterTypeBoolean parses the value of the parameter
+	// as a boolean.
+	ClusterTemplateParameterTypeBoolean ClusterTemplateParameterType = "boolean"
+)
+
+// +genclient
+// +kubebuilder:resource:path=clustertemplates,scope=Namespaced,categories=provisioning
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Display Name",type=string,JSONPath=".spec.displayName"
+// +kubebuilder:printcolumn:name="Latest Revision",type=integer,JSONPath=".status.latestRevision"
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=".metadata.creationTimestamp"
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ClusterTemplate holds versioned cluster spec skeletons that clusters in
+// the same namespace are created from.
+type ClusterTemplate struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec is the desired state of the cluster template.
+	// +optional
+	Spec ClusterTemplateSpec `json:"spec,omitempty"`
+	// Status is the observed state of the cluster template.
+	// +optional
+	Status ClusterTemplateStatus `json:"status,omitempty"`
+}
+
+// ClusterTemplateSpec is the desired state of a cluster template.
+type ClusterTemplateSpec struct {
+	// DisplayName is the human-readable name of the template.
+	// +optional
+	DisplayName string `json:"displayName,omitempty"`
+
+	// Description describes the clusters created from the template.
+	// +optional
+	Description string `json:"description,omitempty"`
+
+	// Revisions are the versions of the template. A cluster stays on the
+	// revision it references until it is upgraded to another one. Revisions
+	// are immutable: a revision changed after it was first validated is
+	// invalid and is not applied to clusters.
+	// +optional
+	// +listType=map
+	// +listMapKey=revision
+	Revisions []ClusterTemplateRevision `json:"revisions,omitempty"`
+}
+
+// ClusterTemplateRevision is a version of a cluster template.
+type ClusterTemplateRevision struct {
+	// Revision is the number of the revision.
+	// +kubebuilder:validation:Minimum=1
+	// +required
+	Revision int `json:"revision"`
+
+	// Description describes the changes of the revision.
+	// +optional
+	Description string `json:"description,omitempty"`
+
+	// ClusterSpec is the spec of the clusters created from the revision.
+	// Fields set on the cluster take precedence over the fields of the
+	// revision, unless they are locked or set by a parameter.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:validation:Type=object
+	// +optional
+	ClusterSpec ClusterSpec `json:"clusterSpec,omitempty"`
+
+	// Parameters are the fields of ClusterSpec that are set from the values
+	// given by the cluster.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	Parameters []ClusterTemplateParameter `json:"parameters,omitempty"`
+
+	// LockedFields are the paths of the fields of ClusterSpec that clusters
+	// cannot change, e.g. "rkeConfig.registries",
+	// "defaultPodSecurityAdmissionConfigurationTemplateName" or
+	// "rkeConfig.etcd.s3". Paths are the JSON field names separated by
+	// dots.
+	// +optional
+	LockedFields []string `json:"lockedFields,omitempty"`
+}
+
+// ClusterTemplateParameter is a field of the cluster spec that is set from
+// a value given by the cluster.
+type ClusterTemplateParameter struct {
+	// Name is the name of the parameter, used as the key of its value in
+	// the cluster template reference of the cluster.
+	// +kubebuilder:validation:MaxLength=63
+	// +required
+	Name string `json:"name"`
+
+	// Description describes the parameter.
+	// +optional
+	Description string `json:"description,omitempty"`
+
+	// Path is the path of the field of the cluster spec set by the
+	// parameter, e.g. "kubernetesVersion". Paths are the JSON field names
+	// separated by dots.
+	// +required
+	Path string `json:"path"`
+
+	// Type is the type of the value of the parameter. Defaults to string.
+	// +optional
+	Type ClusterTemplateParameterType `json:"type,omitempty"`
+
+	// Default is the value of the parameter when the cluster does not give
+	// one.
+	// +optional
+	Default string `json:"default,omitempty"`
+
+	// Required reflects whether the cluster must give a value for the
+	// parameter.
+	// +optional
+	Required bool `json:"required,omitempty"`
+}
+
+// ClusterTemplateStatus is the observed state of a cluster template.
+type ClusterTemplateStatus struct {
+	// LatestRevision is the highest revision of the template.
+	// +optional
+	LatestRevision int `json:"latestRevision,omitempty"`
+
+	// ObservedGeneration is the most recent generation of the template
+	// that was validated.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// RevisionDigests are the digests of the revisions when they were first
+	// validated. They are kept after a revision is removed, so that it
+	// cannot be added back with other content.
+	// +optional
+	// +listType=map
+	// +listMapKey=revision
+	RevisionDigests []ClusterTemplateRevisionDigest `json:"revisionDigests,omitempty"`
+
+	// Conditions is a representation of the template's current state.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []genericcondition.GenericCondition `json:"conditions,omitempty"`
+}
+
+// ClusterTemplateRevisionDigest is the digest of a revision of a cluster
+// template.
+type ClusterTemplateRevisionDigest struct {
+	// Revision is the number of the revision.
+	// +required
+	Revision int `json:"revision"`
+
+	// Digest is the SHA-256 digest of the revision.
+	// +required
+	Digest string `json:"digest"`
+}
+
+// ClusterTemplateReference references the cluster template revision a
+// cluster is created from.
+type ClusterTemplateReference struct {
+	// Name is the name of the cluster template, in the namespace of the
+	// cluster.
+	// +required
+	Name string `json:"name"`
+
+	// Revision is the revision of the template the cluster runs. Changing
+	// it upgrades the cluster to the revision.
+	// +kubebuilder:validation:Minimum=1
+	// +required
+	Revision int `json:"revision"`
+
+	// Values are the values of the parameters of the revision.
+	// +nullable
+	// +optional
+	Values map[string]string `json:"values,omitempty"`
+}
+
+// ClusterTemplateRevisionStatus is the observed state of the cluster
+// template revision of a cluster.
+type ClusterTemplateRevisionStatus struct {
+	// AppliedRevision is the revision of the template last applied to the
+	// cluster.
+	// +optional
+	AppliedRevision int `json:"appliedRevision,omitempty"`
+
+	// ObservedGeneration is the generation of the cluster the revision was
+	// last applied to. The cluster is not provisioned or updated until the
+	// revision is applied to its current generation, so that changes to
+	// locked fields are reverted before they take effect.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// LatestRevision is the highest revision of the template.
+	// +optional
+	LatestRevision int `json:"latestRevision,omitempty"`
+
+	// UpgradeChanges are the changes to the cluster spec that upgrading the
+	// cluster to LatestRevision would make.
+	// +optional
+	UpgradeChanges []ClusterTemplateFieldChange `json:"upgradeChanges,omitempty"`
+}
+
+// ClusterTemplateFieldChange is a change to a field of the cluster spec.
+type ClusterTemplateFieldChange struct {
+	// Path is the path of the field, with the JSON field names separated by
+	// dots.
+	// +required
+	Path string `json:"path"`
+
+	// Current is the JSON encoded current value of the field. It is empty
+	// when the field is not set.
+	// +optional
+	Current string `json:"current,omitempty"`
+
+	// Desired is the JSON encoded value of the field after the change. It
+	// is empty when the change removes the field.
+	// +optional
+	Desired string `json:"desired,omitempty"`
+}
@@ -102,6 +102,14 @@ type ClusterSpec struct {
 	// Rancher server can update the system-upgrade-controller plan.
 	// +optional
 	RedeploySystemAgentGeneration int64 `json:"redeploySystemAgentGeneration,omitempty"`
+
+	// ClusterTemplate references the cluster template revision the cluster
+	// is created from. Fields locked by the revision are kept in sync with
+	// it, and changing the revision upgrades the cluster to it. Once set, it
+	// cannot be removed or changed to another template.
+	// +nullable
+	// +optional
+	ClusterTemplate *ClusterTemplateReference `json:"clusterTemplate,omitempty"`
 }
 
 type ClusterAPIConfig struct {
@@ -173,6 +181,9 @@ type RKEConfig struct {
 // +kubebuilder:validation:XValidation:rule="!has(self.etcdRole) || !self.etcdRole || !has(self.autoscalingMinSize) || self.autoscalingMinSize > 0", message="AutoscalingMinSize must be greater than 0 when EtcdRole is true"
 // +kubebuilder:validation:XValidation:rule="!has(self.autoscalingMaxSize) || !has(self.autoscalingMinSize) || self.autoscalingMinSize <= self.autoscalingMaxSize", message="AutoscalingMinSize must be less than or equal to AutoscalingMaxSize when both are non-nil"
 // +kubebuilder:validation:XValidation:rule="(has(self.autoscalingMinSize) && has(self.autoscalingMaxSize)) || (!has(self.autoscalingMinSize) && !has(self.autoscalingMaxSize))", message="AutoscalingMinSize and AutoscalingMaxSize must both be set if enabling cluster-autoscaling"
+// +kubebuilder:validation:XValidation:rule="!has(self.schedules) || size(self.schedules) == 0 || ((!has(self.etcdRole) || !self.etcdRole) && (!has(self.controlPlaneRole) || !self.controlPlaneRole))", message="Schedules can not be set when EtcdRole or ControlPlaneRole is true"
+// +kubebuilder:validation:XValidation:rule="!has(self.schedules) || size(self.schedules) == 0 || !has(self.autoscalingMinSize)", message="Schedules can not be set if enabling cluster-autoscaling"
+// +kubebuilder:validation:XValidation:rule="!has(self.failureDomains) || size(self.failureDomains) == 0 || !has(self.autoscalingMinSize)", message="FailureDomains can not be set if enabling cluster-autoscaling"
 type RKEMachinePool struct {
 	rkev1.RKECommonNodeConfig `json:",inline"`
 
@@ -234,6 +245,22 @@ type RKEMachinePool struct {
 	// +required
 	NodeConfig *corev1.ObjectReference `json:"machineConfigRef,omitempty"`
 
+	// FailureDomains is a list of failure domains, such as availability
+	// zones, that the machines provisioned by this pool are spread across.
+	// When set, a machine deployment is generated for each failure domain
+	// using the machine config of the failure domain instead of NodeConfig,
+	// and Quantity is distributed evenly among them. The machine deployment
+	// of the first failure domain keeps the name of the machine deployment of
+	// the pool, while the others are suffixed with the failure domain name.
+	// NodeConfig must still be set, and all failure domains must reference
+	// machine configs of the same kind.
+	// +kubebuilder:validation:MaxItems=20
+	// +listType=map
+	// +listMapKey=name
+	// +nullable
+	// +optional
+	FailureDomains []RKEMachinePoolFailureDomain `json:"failureDomains,omitempty"`
+
 	// Name is the internal name of the machine pool.
 	// The generated CAPI machine deployment will be a concatenation of the
 	// cluster name and the machine pool name which, if over 63 characters is
@@ -256,6 +283,19 @@ type RKEMachinePool struct {
 	// +optional
 	Quantity *int32 `json:"quantity,omitempty"`
 
+	// Schedules is a list of time-based scaling windows for the machine
+	// pool. When a schedule takes effect, the Quantity of the machine pool
+	// is set to the quantity of the schedule. A manual change to Quantity
+	// is kept until the next schedule takes effect.
+	// Schedules can not be set on etcd or controlplane machine pools, or on
+	// machine pools using cluster-autoscaling.
+	// +kubebuilder:validation:MaxItems=100
+	// +listType=map
+	// +listMapKey=name
+	// +nullable
+	// +optional
+	Schedules []RKEMachinePoolSchedule `json:"schedules,omitempty"`
+
 	// RollingUpdate is the configuration for the rolling update of the
 	// generated machine deployment.
 	// +nullable
@@ -354,6 +394,50 @@ type RKEMachinePool struct {
 	HostnameLengthLimit int `json:"hostnameLengthLimit,omitempty"`
 }
 
+// RKEMachinePoolFailureDomain is a failure domain of a machine pool, backed by
+// its own machine config.
+type RKEMachinePoolFailureDomain struct {
+	// Name is the name of the failure domain, e.g. the availability zone.
+	// It is appended to the name of the generated machine deployment and set
+	// as the rke.cattle.io/failure-domain label of the generated machines.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=63
+	// +required
+	Name string `json:"name"`
+
+	// NodeConfig is a reference to a MachineConfig object that will be used
+	// to configure the machines provisioned in this failure domain.
+	// +required
+	NodeConfig *corev1.ObjectReference `json:"machineConfigRef"`
+}
+
+// RKEMachinePoolSchedule is a time-based scaling window for a machine pool.
+type RKEMachinePoolSchedule struct {
+	// Name is the name of the schedule, which must be unique within the
+	// machine pool.
+	// +kubebuilder:validation:MinLength=1
+	// +required
+	Name string `json:"name"`
+
+	// Cron is a standard five field cron expression describing when the
+	// schedule takes effect, e.g. "0 19 * * 1-5" for weekdays at 19:00.
+	// +kubebuilder:validation:MinLength=1
+	// +required
+	Cron string `json:"cron"`
+
+	// TimeZone is the IANA name of the time zone the cron expression is
+	// evaluated in, e.g. "Europe/Berlin".
+	// Defaults to UTC.
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
+
+	// Quantity is the desired number of machines in the machine pool once
+	// the schedule takes effect.
+	// +kubebuilder:validation:Minimum=0
+	// +required
+	Quantity int32 `json:"quantity"`
+}
+
 type RKEMachinePoolRollingUpdate struct {
 	// MaxUnavailable is the maximum number of machines that can be
 	// unavailable during the update.
@@ -564,6 +648,151 @@ type ClusterStatus struct {
 	// +listType=map
 	// +listMapKey=type
 	Conditions []genericcondition.GenericCondition `json:"conditions,omitempty"`
+
+	// MachinePoolSchedules reports the state of the scheduled scaling for
+	// each machine pool that has schedules.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	MachinePoolSchedules []MachinePoolScheduleStatus `json:"machinePoolSchedules,omitempty"`
+
+	// CertificateExpiry reports the expiration dates of the certificates of
+	// the nodes when spec.rkeConfig.certificateExpiry is enabled.
+	// +nullable
+	// +optional
+	CertificateExpiry *CertificateExpiryStatus `json:"certificateExpiry,omitempty"`
+
+	// ClusterTemplate reports the cluster template revision applied to the
+	// cluster when spec.clusterTemplate is set.
+	// +nullable
+	// +optional
+	ClusterTemplate *ClusterTemplateRevisionStatus `json:"clusterTemplate,omitempty"`
+}
+
+// CertificateType is the kind of a node certificate.
+type CertificateType string
+
+const (
+	// CertificateTypeServing is a certificate served by a Kubernetes
+	// component other than the kubelet.
+	CertificateTypeServing CertificateType = "Serving"
+
+	// CertificateTypeClient is a client certificate of a Kubernetes
+	// component other than the kubelet.
+	CertificateTypeClient CertificateType = "Client"
+
+	// CertificateTypeEtcd is a serving or client certificate of etcd.
+	CertificateTypeEtcd CertificateType = "Etcd"
+
+	// CertificateTypeEtcdPeer is a peer certificate of etcd.
+	CertificateTypeEtcdPeer CertificateType = "EtcdPeer"
+
+	// CertificateTypeKubelet is a serving or client certificate of the
+	// kubelet.
+	CertificateTypeKubelet CertificateType = "Kubelet"
+)
+
+// CertificateExpiryStatus is the observed expiration of the node
+// certificates of a cluster.
+type CertificateExpiryStatus struct {
+	// EarliestExpiration is the expiration date of the first certificate of
+	// the cluster to expire.
+	// +nullable
+	// +optional
+	EarliestExpiration *metav1.Time `json:"earliestExpiration,omitempty"`
+
+	// LastAutomaticRotation is the time at which a certificate rotation was
+	// last triggered because a certificate was about to expire.
+	// +nullable
+	// +optional
+	LastAutomaticRotation *metav1.Time `json:"lastAutomaticRotation,omitempty"`
+
+	// Nodes is the certificate expiry of every node that reported it.
+	// +optional
+	// +listType=map
+	// +listMapKey=machineName
+	Nodes []NodeCertificateExpiry `json:"nodes,omitempty"`
+}
+
+// NodeCertificateExpiry is the observed expiration of the certificates of a
+// node.
+type NodeCertificateExpiry struct {
+	// MachineName is the name of the machine of the node.
+	// +required
+	MachineName string `json:"machineName"`
+
+	// CollectedAt is the time at which the certificates were inspected.
+	// +nullable
+	// +optional
+	CollectedAt *metav1.Time `json:"collectedAt,omitempty"`
+
+	// Certificates holds the first certificate to expire of every type of
+	// certificate present on the node.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Certificates []CertificateExpiration `json:"certificates,omitempty"`
+}
+
+// CertificateExpiration is the expiration date of a node certificate.
+type CertificateExpiration struct {
+	// Type is the kind of the certificate.
+	// +required
+	Type CertificateType `json:"type"`
+
+	// Path is the path of the certificate file, relative to the data
+	// directory of the distribution.
+	// +required
+	Path string `json:"path"`
+
+	// ExpirationDate is the time at which the certificate expires.
+	// +required
+	ExpirationDate metav1.Time `json:"expirationDate"`
+}
+
+// MachinePoolScheduleStatus is the observed state of the scheduled scaling
+// of a machine pool.
+type MachinePoolScheduleStatus struct {
+	// Name is the name of the machine pool.
+	// +required
+	Name string `json:"name"`
+
+	// LastSchedule is the name of the schedule that most recently took
+	// effect.
+	// +optional
+	LastSchedule string `json:"lastSchedule,omitempty"`
+
+	// LastScheduleTime is the time at which LastSchedule took effect.
+	// +nullable
+	// +optional
+	LastScheduleTime *metav1.Time `json:"lastScheduleTime,omitempty"`
+
+	// AppliedQuantity is the quantity that was set on the machine pool when
+	// LastSchedule took effect.
+	// +nullable
+	// +optional
+	AppliedQuantity *int32 `json:"appliedQuantity,omitempty"`
+
+	// Overridden reflects whether the quantity of the machine pool was
+	// changed manually since LastSchedule took effect. The manual change is
+	// kept until the next schedule takes effect.
+	// +optional
+	Overridden bool `json:"overridden,omitempty"`
+
+	// NextSchedule is the name of the next schedule to take effect.
+	// +optional
+	NextSchedule string `json:"nextSchedule,omitempty"`
+
+	// NextScheduleTime is the time at which NextSchedule takes effect.
+	// +nullable
+	// +optional
+	NextScheduleTime *metav1.Time `json:"nextScheduleTime,omitempty"`
+
+	// NextQuantity is the quantity the machine pool will be scaled to when
+	// NextSchedule takes effect.
+	// +nullable
+	// +optional
+	NextQuantity *int32 `json:"nextQuantity,omitempty"`
 }
 
 // +genclient
@@ -583,6 +812,7 @@ type Cluster struct {
 	metav1.ObjectMeta `json:"metadata,omitempty"`
 
 	// Spec is the desired state of the cluster.
+	// +kubebuilder:validation:XValidation:rule="!has(oldSelf.clusterTemplate) || (has(self.clusterTemplate) && self.clusterTemplate.name == oldSelf.clusterTemplate.name)",message="clusterTemplate cannot be removed or changed to another template"
 	// +optional
 	Spec ClusterSpec `json:"spec,omitempty"`
 	// Status is the observed state of the cluster.
@@ -40,3 +40,20 @@ func NewCluster(namespace, name string, obj Cluster) *Cluster {
 	obj.Namespace = namespace
 	return &obj
 }
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ClusterTemplateList is a list of ClusterTemplate resources
+type ClusterTemplateList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []ClusterTemplate `json:"items"`
+}
+
+func NewClusterTemplate(namespace, name string, obj ClusterTemplate) *ClusterTemplate {
+	obj.APIVersion, obj.Kind = SchemeGroupVersion.WithKind("ClusterTemplate").ToAPIVersionAndKind()
+	obj.Name = name
+	obj.Namespace = namespace
+	return &obj
+}
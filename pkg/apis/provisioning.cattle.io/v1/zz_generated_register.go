@@ -28,7 +28,8 @@
 )
 
 var (
-	ClusterResourceName = "clusters"
+	ClusterResourceName         = "clusters"
+	ClusterTemplateResourceName = "clustertemplates"
 )
 
 // SchemeGroupVersion is group version used to register these objects
@@ -54,6 +55,8 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 	scheme.AddKnownTypes(SchemeGroupVersion,
 		&Cluster{},
 		&ClusterList{},
+		&ClusterTemplate{},
+		&ClusterTemplateList{},
 	)
 	metav1.AddToGroupVersion(scheme, SchemeGroupVersion)
 	return nil
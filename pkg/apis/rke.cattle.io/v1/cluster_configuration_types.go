@@ -76,6 +76,39 @@ type ClusterConfiguration struct {
 	// cluster, regardless of whether a reconciliation is required.
 	// +optional
 	ProvisionGeneration int `json:"provisionGeneration,omitempty"`
+
+	// CertificateExpiry contains the configuration for collecting the
+	// expiration dates of the certificates of the nodes.
+	// +nullable
+	// +optional
+	CertificateExpiry *CertificateExpiry `json:"certificateExpiry,omitempty"`
+}
+
+type CertificateExpiry struct {
+	// Enabled enables the periodic collection of the expiration dates of
+	// the serving, client, etcd and kubelet certificates of every Linux
+	// node. Enabling or disabling the collection updates the plan of every
+	// node.
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+
+	// IntervalSeconds is the interval at which the certificates are
+	// inspected on every node.
+	// Defaults to 3600 when unset.
+	// +kubebuilder:validation:Minimum=600
+	// +kubebuilder:validation:Maximum=86400
+	// +optional
+	IntervalSeconds int `json:"intervalSeconds,omitempty"`
+
+	// AutoRotateWindowDays is the number of days before the expiration of
+	// the first certificate of the cluster at which a certificate rotation
+	// is triggered automatically, by incrementing the generation of
+	// spec.rkeConfig.rotateCertificates. A value of 0 disables automatic
+	// rotation.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=365
+	// +optional
+	AutoRotateWindowDays int `json:"autoRotateWindowDays,omitempty"`
 }
 
 type ClusterUpgradeStrategy struct {
@@ -177,6 +210,7 @@ type DrainOptions struct {
 	PostDrainHooks []DrainHook `json:"postDrainHooks,omitempty"`
 }
 
+// +kubebuilder:validation:XValidation:rule="!has(self.webhook) || !has(self.annotation) || size(self.annotation) == 0",message="only one of annotation or webhook may be set"
 type DrainHook struct {
 	// Annotation that will need to be populated on the machine-plan secret
 	// with the value from the annotation "rke.cattle.io/pre-drain" before
@@ -187,6 +221,103 @@ type DrainHook struct {
 	// +nullable
 	// +optional
 	Annotation string `json:"annotation,omitempty"`
+
+	// Webhook is an HTTPS endpoint that Rancher calls before the planner
+	// continues to drain (pre-drain) or uncordon (post-drain) the specific
+	// node. The endpoint decides whether the drain proceeds, is retried
+	// later or is aborted.
+	// +nullable
+	// +optional
+	Webhook *DrainWebhook `json:"webhook,omitempty"`
+}
+
+// DrainWebhookFailurePolicy determines how a drain webhook that cannot be
+// reached, or keeps asking to be retried, is handled.
+// +kubebuilder:validation:Enum=Fail;Ignore
+type DrainWebhookFailurePolicy string
+
+const (
+	// DrainWebhookFailurePolicyFail aborts the drain.
+	DrainWebhookFailurePolicyFail DrainWebhookFailurePolicy = "Fail"
+
+	// DrainWebhookFailurePolicyIgnore lets the drain proceed.
+	DrainWebhookFailurePolicyIgnore DrainWebhookFailurePolicy = "Ignore"
+)
+
+// DrainWebhookDecision is the decision returned by a drain webhook.
+type DrainWebhookDecision string
+
+const (
+	// DrainWebhookDecisionProceed lets the drain proceed.
+	DrainWebhookDecisionProceed DrainWebhookDecision = "Proceed"
+
+	// DrainWebhookDecisionRetry calls the webhook again after the returned
+	// retry delay.
+	DrainWebhookDecisionRetry DrainWebhookDecision = "Retry"
+
+	// DrainWebhookDecisionAbort stops the drain. The machine is left
+	// cordoned until the drain options change or the
+	// "rke.cattle.io/drain-hook-status" annotation is removed from the
+	// machine-plan secret.
+	DrainWebhookDecisionAbort DrainWebhookDecision = "Abort"
+)
+
+// DrainWebhook is an HTTPS endpoint called for a drain hook.
+//
+// The endpoint receives a POST request with a JSON body containing the
+// fields "stage" ("pre-drain" or "post-drain"), "clusterNamespace",
+// "clusterName", "machineName", "nodeName" and "attempt". A 2xx response
+// with an empty body lets the drain proceed. Otherwise, the body must be a
+// JSON object with the field "decision" ("Proceed", "Retry" or "Abort")
+// and optionally the fields "message" and "retryAfterSeconds". Any other
+// response, as well as a connection error or timeout, is retried.
+type DrainWebhook struct {
+	// URL is the HTTPS URL of the endpoint.
+	// +kubebuilder:validation:MaxLength=2048
+	// +kubebuilder:validation:Pattern=`^https://`
+	// +required
+	URL string `json:"url"`
+
+	// CABundle is the PEM encoded CA chain used to verify the certificate
+	// of the endpoint. The system roots are used when unset.
+	// +nullable
+	// +optional
+	CABundle []byte `json:"caBundle,omitempty"`
+
+	// AuthSecretName is the name of the secret residing within the same
+	// namespace as the cluster that contains the credentials sent to the
+	// endpoint. The secret must be of type rke.cattle.io/drain-webhook-auth
+	// and list the cluster in its rke.cattle.io/object-authorized-for-clusters
+	// annotation.
+	// The accepted keys are as follows:
+	// - token, sent as a bearer token
+	// - username and password, sent as basic authentication
+	// +kubebuilder:validation:MaxLength=253
+	// +nullable
+	// +optional
+	AuthSecretName string `json:"authSecretName,omitempty"`
+
+	// TimeoutSeconds is the time to wait for a single call to the endpoint.
+	// Defaults to 10 when unset.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=60
+	// +optional
+	TimeoutSeconds int `json:"timeoutSeconds,omitempty"`
+
+	// RetryTimeoutSeconds is the time, counted from the first call, during
+	// which the webhook is retried before FailurePolicy applies.
+	// Defaults to 600 when unset.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=86400
+	// +optional
+	RetryTimeoutSeconds int `json:"retryTimeoutSeconds,omitempty"`
+
+	// FailurePolicy determines whether the drain is aborted (Fail) or
+	// proceeds (Ignore) when the webhook is still asking to be retried, or
+	// cannot be reached, after RetryTimeoutSeconds.
+	// Defaults to Fail when unset.
+	// +optional
+	FailurePolicy DrainWebhookFailurePolicy `json:"failurePolicy,omitempty"`
 }
 
 type RKESystemConfig struct {
@@ -13,6 +13,9 @@
 	PasswordAuthConfigSecretKey      = "password"
 	AuthAuthConfigSecretKey          = "auth"
 	IdentityTokenAuthConfigSecretKey = "identityToken"
+
+	// DrainWebhookAuthSecretType is the type of the secrets holding the credentials of drain webhooks.
+	DrainWebhookAuthSecretType = "rke.cattle.io/drain-webhook-auth"
 )
 
 type GenericMap struct {
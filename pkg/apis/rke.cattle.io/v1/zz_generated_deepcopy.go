@@ -29,6 +29,22 @@
 	v1beta2 "sigs.k8s.io/cluster-api/api/core/v1beta2"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertificateExpiry) DeepCopyInto(out *CertificateExpiry) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateExpiry.
+func (in *CertificateExpiry) DeepCopy() *CertificateExpiry {
+	if in == nil {
+		return nil
+	}
+	out := new(CertificateExpiry)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterConfiguration) DeepCopyInto(out *ClusterConfiguration) {
 	*out = *in
@@ -65,6 +81,11 @@ func (in *ClusterConfiguration) DeepCopyInto(out *ClusterConfiguration) {
 		**out = **in
 	}
 	out.DataDirectories = in.DataDirectories
+	if in.CertificateExpiry != nil {
+		in, out := &in.CertificateExpiry, &out.CertificateExpiry
+		*out = new(CertificateExpiry)
+		**out = **in
+	}
 	return
 }
 
@@ -219,6 +240,11 @@ func (in *DataDirectories) DeepCopy() *DataDirectories {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DrainHook) DeepCopyInto(out *DrainHook) {
 	*out = *in
+	if in.Webhook != nil {
+		in, out := &in.Webhook, &out.Webhook
+		*out = new(DrainWebhook)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -243,12 +269,16 @@ func (in *DrainOptions) DeepCopyInto(out *DrainOptions) {
 	if in.PreDrainHooks != nil {
 		in, out := &in.PreDrainHooks, &out.PreDrainHooks
 		*out = make([]DrainHook, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	if in.PostDrainHooks != nil {
 		in, out := &in.PostDrainHooks, &out.PostDrainHooks
 		*out = make([]DrainHook, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	return
 }
@@ -263,6 +293,27 @@ func (in *DrainOptions) DeepCopy() *DrainOptions {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DrainWebhook) DeepCopyInto(out *DrainWebhook) {
+	*out = *in
+	if in.CABundle != nil {
+		in, out := &in.CABundle, &out.CABundle
+		*out = make([]byte, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DrainWebhook.
+func (in *DrainWebhook) DeepCopy() *DrainWebhook {
+	if in == nil {
+		return nil
+	}
+	out := new(DrainWebhook)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ETCD) DeepCopyInto(out *ETCD) {
 	*out = *in
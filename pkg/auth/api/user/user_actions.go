@@ -3,13 +3,13 @@
 import (
 	"net/http"
 	"strings"
-	"unicode/utf8"
 
 	"github.com/pkg/errors"
 	"github.com/rancher/norman/httperror"
 	"github.com/rancher/norman/parse"
 	"github.com/rancher/norman/types"
 	"github.com/rancher/rancher/pkg/auth/providerrefresh"
+	"github.com/rancher/rancher/pkg/auth/providers/local/passwordpolicy"
 	client "github.com/rancher/rancher/pkg/client/generated/management/v3"
 	exttokenstore "github.com/rancher/rancher/pkg/ext/stores/tokens"
 	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
@@ -109,7 +109,7 @@ func (h *Handler) changePassword(request *types.APIContext) error {
 		return err
 	}
 
-	if err := validatePassword(user.Username, currentPass, newPass, settings.PasswordMinLength.GetInt()); err != nil {
+	if err := validatePassword(user.Username, currentPass, newPass, passwordpolicy.FromSettings()); err != nil {
 		return httperror.NewAPIError(httperror.InvalidBodyContent, err.Error())
 	}
 
@@ -155,7 +155,7 @@ func (h *Handler) setPassword(request *types.APIContext) error {
 	username, _ := usernameInt.(string)
 
 	// passing empty currentPass to validator since, this api call doesn't assume an existing password
-	if err := validatePassword(username, "", newPass, settings.PasswordMinLength.GetInt()); err != nil {
+	if err := validatePassword(username, "", newPass, passwordpolicy.FromSettings()); err != nil {
 		return httperror.NewAPIError(httperror.InvalidBodyContent, err.Error())
 	}
 
@@ -205,11 +205,11 @@ func (h *Handler) canDoUserAction(apiContext *types.APIContext, verb string) boo
 	return apiContext.AccessControl.CanDo(v3.UserGroupVersionKind.Group, v3.UserResource.Name, verb, apiContext, nil, apiContext.Schema) == nil
 }
 
-// validatePassword will ensure a password is at least the minimum required length in runes,
+// validatePassword will ensure a password complies with the password policy,
 // that the username and password do not match, and that the new password is not the same as the current password.
-func validatePassword(user string, currentPass string, pass string, minPassLen int) error {
-	if utf8.RuneCountInString(pass) < minPassLen {
-		return errors.Errorf("Password must be at least %v characters", minPassLen)
+func validatePassword(user string, currentPass string, pass string, policy passwordpolicy.Policy) error {
+	if err := policy.Validate(pass); err != nil {
+		return err
 	}
 
 	if user == pass {
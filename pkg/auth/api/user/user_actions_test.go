@@ -5,6 +5,7 @@
 	"testing"
 
 	"github.com/rancher/norman/types"
+	"github.com/rancher/rancher/pkg/auth/providers/local/passwordpolicy"
 	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
 	"github.com/stretchr/testify/assert"
 )
@@ -79,7 +80,7 @@ func TestValidatePassword(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := validatePassword(tt.username, tt.currentpass, tt.password, 12)
+			err := validatePassword(tt.username, tt.currentpass, tt.password, passwordpolicy.Policy{MinLength: 12})
 			switch {
 			case tt.expectsErr && tt.expectErrMsg != "":
 				assert.EqualError(t, err, tt.expectErrMsg)
@@ -0,0 +1,42 @@
+package audit
+
+import (
+	"context"
+	"maps"
+	"sync"
+)
+
+type annotationsKey struct{}
+
+// annotations are the key-value pairs handlers add to the audit log entry of a request.
+type annotations struct {
+	mu     sync.Mutex
+	values map[string]string
+}
+
+func withAnnotations(ctx context.Context) (context.Context, *annotations) {
+	a := &annotations{}
+	return context.WithValue(ctx, annotationsKey{}, a), a
+}
+
+// AddAnnotation adds an annotation to the audit log entry of the request of the given context. It does nothing if the
+// request isn't audited.
+func AddAnnotation(ctx context.Context, key, value string) {
+	a, ok := ctx.Value(annotationsKey{}).(*annotations)
+	if !ok {
+		return
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.values == nil {
+		a.values = map[string]string{}
+	}
+	a.values[key] = value
+}
+
+func (a *annotations) get() map[string]string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return maps.Clone(a.values)
+}
@@ -87,6 +87,9 @@ type logEntry struct {
 
 	RequestBody  map[string]any `json:"requestBody,omitempty"`
 	ResponseBody map[string]any `json:"responseBody,omitempty"`
+
+	// Annotations are added by the handler of the request with [AddAnnotation].
+	Annotations map[string]string `json:"annotations,omitempty"`
 }
 
 func copyReqBody(req *http.Request, keepBody bool) ([]byte, string) {
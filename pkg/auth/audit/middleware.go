@@ -18,7 +18,7 @@ func GetAuditLoggerMiddleware(auditLog *LoggingHandler) func(next http.Handler)
 
 			reqTimestamp := time.Now().Format(time.RFC3339)
 			user := getUserInfo(req)
-			context := context.WithValue(req.Context(), userKeyValue, user)
+			context, annotations := withAnnotations(context.WithValue(req.Context(), userKeyValue, user))
 			req = req.WithContext(context)
 			keepReqBody := auditLog.level >= auditlogv1.LevelRequest
 			rawReqBody, userName := copyReqBody(req, keepReqBody)
@@ -49,6 +49,7 @@ func GetAuditLoggerMiddleware(auditLog *LoggingHandler) func(next http.Handler)
 				auditUser.Group = nil
 			}
 			auditLogEntry := newLog(verbosityLevel, auditUser, req, wrappedRw, reqTimestamp, respTimestamp, rawReqBody, userName)
+			auditLogEntry.Annotations = annotations.get()
 			auditLog.Write(auditLogEntry)
 		})
 	}
@@ -3,6 +3,7 @@
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
@@ -133,6 +134,26 @@ func TestMiddlewareNilWriter(t *testing.T) {
 	assert.Equal(t, http.StatusOK, rw.Code)
 }
 
+// TestMiddlewareAnnotations tests that annotations added by the handler are written to the log entry
+func TestMiddlewareAnnotations(t *testing.T) {
+	writer, out := newTestAuditWriter(auditlogv1.LevelNull)
+	middleware := NewAuditLogMiddleware(writer)
+
+	handler := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		AddAnnotation(req.Context(), "meta-proxy.cattle.io/host", "api.example.com")
+		rw.WriteHeader(http.StatusOK)
+	})
+
+	middleware(handler).ServeHTTP(httptest.NewRecorder(), newTestRequest(http.MethodGet, "/meta/proxy/api.example.com", nil))
+
+	var entry logEntry
+	require.NoError(t, json.Unmarshal(out.Bytes(), &entry))
+	assert.Equal(t, map[string]string{"meta-proxy.cattle.io/host": "api.example.com"}, entry.Annotations)
+
+	// Annotations of requests that aren't audited are dropped.
+	AddAnnotation(context.Background(), "key", "value")
+}
+
 // =============================================================================
 // RESPONSE BODY BUFFERING TESTS
 // =============================================================================
@@ -0,0 +1,186 @@
+// Package creatorid records the user creating resources whose controllers act on behalf of their creator, and tells
+// whether the recorded creator can be trusted.
+//
+// The creator is recorded in the field.cattle.io/creatorId annotation by admission policies of the local cluster. A
+// mutating policy sets the annotation to the user making the create request, overwriting any value sent by the
+// client, on clusters serving mutating admission policies. A validating policy rejects creating resources recording
+// any other creator, and changing the recorded creator afterward.
+package creatorid
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/rancher/wrangler/v3/pkg/apply"
+	"github.com/sirupsen/logrus"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/discovery"
+	admissionregistrationclient "k8s.io/client-go/kubernetes/typed/admissionregistration/v1"
+	"k8s.io/utils/ptr"
+)
+
+const (
+	// Annotation is the annotation recording the user who created a resource.
+	Annotation = "field.cattle.io/creatorId"
+
+	// PolicyName is the name of the admission policies recording the creator, and of their bindings.
+	PolicyName = "rancher-creator-id"
+
+	// propagationDelay is how long the API server may take to enforce a new policy binding.
+	propagationDelay = 10 * time.Second
+)
+
+// Resources are the management.cattle.io resources whose creator is recorded.
+var Resources = []string{"breakglasscredentials", "workloadidentitybindings"}
+
+// ErrUntrusted is returned for resources whose creator wasn't recorded by the admission policies.
+var ErrUntrusted = errors.New("creator not recorded at admission")
+
+// creatorExpression is the CEL expression of the creator recorded in the given object, empty if there is none.
+func creatorExpression(object string) string {
+	return fmt.Sprintf("has(%[1]s.metadata.annotations) && '%[2]s' in %[1]s.metadata.annotations ? %[1]s.metadata.annotations['%[2]s'] : ''", object, Annotation)
+}
+
+func matchResources(operations ...admissionregistrationv1.OperationType) *admissionregistrationv1.MatchResources {
+	return &admissionregistrationv1.MatchResources{
+		ResourceRules: []admissionregistrationv1.NamedRuleWithOperations{{
+			RuleWithOperations: admissionregistrationv1.RuleWithOperations{
+				Operations: operations,
+				Rule: admissionregistrationv1.Rule{
+					APIGroups:   []string{"management.cattle.io"},
+					APIVersions: []string{"*"},
+					Resources:   Resources,
+				},
+			},
+		}},
+	}
+}
+
+// Policies returns the admission policies recording the creator of resources, and their bindings. The mutating
+// policy is only returned if the local cluster serves mutating admission policies.
+func Policies(mutating bool) []runtime.Object {
+	objs := []runtime.Object{
+		&admissionregistrationv1.ValidatingAdmissionPolicy{
+			TypeMeta:   metav1.TypeMeta{APIVersion: admissionregistrationv1.SchemeGroupVersion.String(), Kind: "ValidatingAdmissionPolicy"},
+			ObjectMeta: metav1.ObjectMeta{Name: PolicyName},
+			Spec: admissionregistrationv1.ValidatingAdmissionPolicySpec{
+				MatchConstraints: matchResources(admissionregistrationv1.Create, admissionregistrationv1.Update),
+				FailurePolicy:    ptr.To(admissionregistrationv1.Fail),
+				Variables: []admissionregistrationv1.Variable{
+					{Name: "creator", Expression: creatorExpression("object")},
+					{Name: "oldCreator", Expression: "request.operation == 'UPDATE' ? (" + creatorExpression("oldObject") + ") : ''"},
+				},
+				Validations: []admissionregistrationv1.Validation{
+					{
+						Expression: "request.operation != 'CREATE' || variables.creator == request.userInfo.username",
+						Message:    "the " + Annotation + " annotation must be the user creating the resource",
+					},
+					{
+						Expression: "request.operation != 'UPDATE' || variables.creator == variables.oldCreator",
+						Message:    "the " + Annotation + " annotation is immutable",
+					},
+				},
+			},
+		},
+		&admissionregistrationv1.ValidatingAdmissionPolicyBinding{
+			TypeMeta:   metav1.TypeMeta{APIVersion: admissionregistrationv1.SchemeGroupVersion.String(), Kind: "ValidatingAdmissionPolicyBinding"},
+			ObjectMeta: metav1.ObjectMeta{Name: PolicyName},
+			Spec: admissionregistrationv1.ValidatingAdmissionPolicyBindingSpec{
+				PolicyName:        PolicyName,
+				ValidationActions: []admissionregistrationv1.ValidationAction{admissionregistrationv1.Deny},
+			},
+		},
+	}
+	if !mutating {
+		return objs
+	}
+
+	return append(objs,
+		&admissionregistrationv1.MutatingAdmissionPolicy{
+			TypeMeta:   metav1.TypeMeta{APIVersion: admissionregistrationv1.SchemeGroupVersion.String(), Kind: "MutatingAdmissionPolicy"},
+			ObjectMeta: metav1.ObjectMeta{Name: PolicyName},
+			Spec: admissionregistrationv1.MutatingAdmissionPolicySpec{
+				MatchConstraints: matchResources(admissionregistrationv1.Create),
+				FailurePolicy:    ptr.To(admissionregistrationv1.Fail),
+				Mutations: []admissionregistrationv1.Mutation{{
+					PatchType: admissionregistrationv1.PatchTypeApplyConfiguration,
+					ApplyConfiguration: &admissionregistrationv1.ApplyConfiguration{
+						Expression: "Object{metadata: Object.metadata{annotations: {'" + Annotation + "': request.userInfo.username}}}",
+					},
+				}},
+				ReinvocationPolicy: admissionregistrationv1.NeverReinvocationPolicy,
+			},
+		},
+		&admissionregistrationv1.MutatingAdmissionPolicyBinding{
+			TypeMeta:   metav1.TypeMeta{APIVersion: admissionregistrationv1.SchemeGroupVersion.String(), Kind: "MutatingAdmissionPolicyBinding"},
+			ObjectMeta: metav1.ObjectMeta{Name: PolicyName},
+			Spec: admissionregistrationv1.MutatingAdmissionPolicyBindingSpec{
+				PolicyName: PolicyName,
+			},
+		},
+	)
+}
+
+// Ensure creates or updates the admission policies recording the creator of resources. Without mutating admission
+// policies, clients must set the annotation to their own user name when creating resources.
+func Ensure(apply apply.Apply, discovery discovery.DiscoveryInterface) error {
+	mutating, err := servesMutatingPolicies(discovery)
+	if err != nil {
+		return err
+	}
+	if !mutating {
+		logrus.Infof("[creatorid] mutating admission policies aren't served, the %s annotation must be set by clients", Annotation)
+	}
+	return apply.WithSetID(PolicyName).WithDynamicLookup().ApplyObjects(Policies(mutating)...)
+}
+
+func servesMutatingPolicies(discovery discovery.DiscoveryInterface) (bool, error) {
+	resources, err := discovery.ServerResourcesForGroupVersion(admissionregistrationv1.SchemeGroupVersion.String())
+	if err != nil {
+		return false, fmt.Errorf("discovering %s resources: %w", admissionregistrationv1.SchemeGroupVersion, err)
+	}
+	return slices.ContainsFunc(resources.APIResources, func(resource metav1.APIResource) bool {
+		return resource.Name == "mutatingadmissionpolicies"
+	}), nil
+}
+
+// Verifier returns the creator of resources recorded at admission.
+type Verifier struct {
+	bindings admissionregistrationclient.ValidatingAdmissionPolicyBindingInterface
+}
+
+// NewVerifier returns a Verifier looking up the validating policy binding with the given client.
+func NewVerifier(bindings admissionregistrationclient.ValidatingAdmissionPolicyBindingInterface) *Verifier {
+	return &Verifier{bindings: bindings}
+}
+
+// Creator returns the user who created a resource. It returns an error wrapping ErrUntrusted unless the resource was
+// created while the validating policy was enforced, as the client could then have set the annotation to any user.
+func (v *Verifier) Creator(ctx context.Context, obj metav1.Object) (string, error) {
+	creatorID := obj.GetAnnotations()[Annotation]
+	if creatorID == "" {
+		return "", fmt.Errorf("%s has no creator: %w", obj.GetName(), ErrUntrusted)
+	}
+
+	binding, err := v.bindings.Get(ctx, PolicyName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return "", fmt.Errorf("admission policy binding %s not found: %w", PolicyName, ErrUntrusted)
+	} else if err != nil {
+		return "", fmt.Errorf("getting admission policy binding %s: %w", PolicyName, err)
+	}
+	if !slices.Contains(binding.Spec.ValidationActions, admissionregistrationv1.Deny) {
+		return "", fmt.Errorf("admission policy binding %s doesn't deny requests: %w", PolicyName, ErrUntrusted)
+	}
+
+	enforcedAt := binding.CreationTimestamp.Add(propagationDelay)
+	if created := obj.GetCreationTimestamp(); created.Time.Before(enforcedAt) {
+		return "", fmt.Errorf("%s was created before admission policy %s was enforced: %w", obj.GetName(), PolicyName, ErrUntrusted)
+	}
+	return creatorID, nil
+}
@@ -0,0 +1,123 @@
+package creatorid
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestPolicies(t *testing.T) {
+	objs := Policies(false)
+	require.Len(t, objs, 2)
+	binding, ok := objs[1].(*admissionregistrationv1.ValidatingAdmissionPolicyBinding)
+	require.True(t, ok)
+	assert.Equal(t, []admissionregistrationv1.ValidationAction{admissionregistrationv1.Deny}, binding.Spec.ValidationActions)
+
+	objs = Policies(true)
+	require.Len(t, objs, 4)
+	policy, ok := objs[2].(*admissionregistrationv1.MutatingAdmissionPolicy)
+	require.True(t, ok)
+	// The creator is only set on create, it is immutable afterward.
+	assert.Equal(t, []admissionregistrationv1.OperationType{admissionregistrationv1.Create}, policy.Spec.MatchConstraints.ResourceRules[0].Operations)
+}
+
+func TestServesMutatingPolicies(t *testing.T) {
+	clientset := k8sfake.NewClientset()
+	discovery := clientset.Discovery().(*fakediscovery.FakeDiscovery)
+	discovery.Resources = []*metav1.APIResourceList{{
+		GroupVersion: admissionregistrationv1.SchemeGroupVersion.String(),
+		APIResources: []metav1.APIResource{{Name: "validatingadmissionpolicies"}},
+	}}
+
+	mutating, err := servesMutatingPolicies(discovery)
+	require.NoError(t, err)
+	assert.False(t, mutating)
+
+	discovery.Resources[0].APIResources = append(discovery.Resources[0].APIResources, metav1.APIResource{Name: "mutatingadmissionpolicies"})
+	mutating, err = servesMutatingPolicies(discovery)
+	require.NoError(t, err)
+	assert.True(t, mutating)
+}
+
+func TestCreator(t *testing.T) {
+	enforcedAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	newObject := func(creatorID string, created time.Time) *metav1.ObjectMeta {
+		obj := &metav1.ObjectMeta{Name: "binding", CreationTimestamp: metav1.NewTime(created)}
+		if creatorID != "" {
+			obj.Annotations = map[string]string{Annotation: creatorID}
+		}
+		return obj
+	}
+	newBinding := func(actions ...admissionregistrationv1.ValidationAction) *admissionregistrationv1.ValidatingAdmissionPolicyBinding {
+		return &admissionregistrationv1.ValidatingAdmissionPolicyBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: PolicyName, CreationTimestamp: metav1.NewTime(enforcedAt)},
+			Spec: admissionregistrationv1.ValidatingAdmissionPolicyBindingSpec{
+				PolicyName:        PolicyName,
+				ValidationActions: actions,
+			},
+		}
+	}
+
+	tests := map[string]struct {
+		obj       *metav1.ObjectMeta
+		binding   *admissionregistrationv1.ValidatingAdmissionPolicyBinding
+		want      string
+		untrusted bool
+	}{
+		"created after the policy was enforced": {
+			obj:     newObject("u-alice", enforcedAt.Add(time.Hour)),
+			binding: newBinding(admissionregistrationv1.Deny),
+			want:    "u-alice",
+		},
+		"no creator": {
+			obj:       newObject("", enforcedAt.Add(time.Hour)),
+			binding:   newBinding(admissionregistrationv1.Deny),
+			untrusted: true,
+		},
+		"no policy binding": {
+			obj:       newObject("u-alice", enforcedAt.Add(time.Hour)),
+			untrusted: true,
+		},
+		"policy binding not denying requests": {
+			obj:       newObject("u-alice", enforcedAt.Add(time.Hour)),
+			binding:   newBinding(admissionregistrationv1.Audit),
+			untrusted: true,
+		},
+		"created before the policy was enforced": {
+			obj:       newObject("u-alice", enforcedAt.Add(-time.Hour)),
+			binding:   newBinding(admissionregistrationv1.Deny),
+			untrusted: true,
+		},
+		"created while the policy was propagating": {
+			obj:       newObject("u-alice", enforcedAt.Add(propagationDelay/2)),
+			binding:   newBinding(admissionregistrationv1.Deny),
+			untrusted: true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			clientset := k8sfake.NewClientset()
+			if tt.binding != nil {
+				_, err := clientset.AdmissionregistrationV1().ValidatingAdmissionPolicyBindings().Create(context.Background(), tt.binding, metav1.CreateOptions{})
+				require.NoError(t, err)
+			}
+			verifier := NewVerifier(clientset.AdmissionregistrationV1().ValidatingAdmissionPolicyBindings())
+
+			creatorID, err := verifier.Creator(context.Background(), tt.obj)
+			if tt.untrusted {
+				assert.ErrorIs(t, err, ErrUntrusted)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, creatorID)
+		})
+	}
+}
@@ -1,10 +1,12 @@
 package common
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
 	"github.com/rancher/rancher/pkg/namespace"
+	"github.com/rancher/rancher/pkg/secretbackend"
 	wcorev1 "github.com/rancher/wrangler/v3/pkg/generated/controllers/core/v1"
 	v1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -28,6 +30,9 @@ func NameForSecret(s *v1.Secret) string {
 // In the event that the Secret already exists, if the .Data doesn't match the
 // desired state it is overwritten.
 //
+// When a secret backend is configured, the secretInfo is stored in the backend
+// and the Secret only holds a reference to it.
+//
 // It returns a string with the namespace:name of the created Secret.
 func CreateOrUpdateSecrets(secrets wcorev1.SecretController, secretInfo, field, authType string) (string, error) {
 	if secretInfo == "" {
@@ -48,13 +53,33 @@ func CreateOrUpdateSecrets(secrets wcorev1.SecretController, secretInfo, field,
 	if err != nil && !apierrors.IsNotFound(err) {
 		return "", fmt.Errorf("error getting secret for %s : %w", name, err)
 	}
+	exists := err == nil
+
+	backend, err := secretbackend.Configured()
+	if err != nil {
+		return "", err
+	}
+	if exists {
+		currData, err := secretbackend.Data(context.TODO(), curr)
+		if err != nil {
+			return "", err
+		}
+		if string(currData[field]) == secretInfo && secretbackend.IsReference(curr) == (backend != nil) {
+			return NameForSecret(secret), nil
+		}
+	}
+	if backend != nil {
+		if secret, err = secretbackend.Store(context.TODO(), backend, secret); err != nil {
+			return "", err
+		}
+	}
 
-	if err == nil && string(curr.Data[field]) != secretInfo {
+	if exists {
 		_, err = secrets.Update(secret)
 		if err != nil {
 			return "", fmt.Errorf("error updating secret %s: %w", name, err)
 		}
-	} else if apierrors.IsNotFound(err) {
+	} else {
 		_, err = secrets.Create(secret)
 		if err != nil && !apierrors.IsAlreadyExists(err) {
 			return "", fmt.Errorf("error creating secret %s %w", name, err)
@@ -87,7 +112,7 @@ func ReadFromSecretData(secrets wcorev1.SecretController, secretInfo string) (ma
 			if err != nil {
 				return nil, fmt.Errorf("error getting secret %s: %w", secretInfo, err)
 			}
-			return secret.Data, nil
+			return secretbackend.Data(context.TODO(), secret)
 		}
 	}
 	return nil, nil
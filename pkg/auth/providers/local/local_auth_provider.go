@@ -2,9 +2,11 @@
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/rancher/apiserver/pkg/apierror"
@@ -14,7 +16,9 @@
 	"github.com/rancher/rancher/pkg/auth/accessor"
 	"github.com/rancher/rancher/pkg/auth/providers/common"
 	"github.com/rancher/rancher/pkg/auth/providers/local/pbkdf2"
+	mgmtcontrollers "github.com/rancher/rancher/pkg/generated/controllers/management.cattle.io/v3"
 	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/settings"
 	"github.com/rancher/rancher/pkg/types/config"
 	"github.com/rancher/rancher/pkg/user"
 	"github.com/rancher/wrangler/v3/pkg/schemas/validation"
@@ -22,6 +26,7 @@
 	"golang.org/x/crypto/bcrypt"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	k8stypes "k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/tools/cache"
 )
@@ -37,20 +42,29 @@
 
 type PasswordVerifier interface {
 	VerifyPassword(user *apiv3.User, password string) error
+	PasswordChangedAt(userId string) (time.Time, error)
 }
 
 type Provider struct {
 	userLister  v3.UserLister
 	userIndexer cache.Indexer
 	pwdVerifier PasswordVerifier
+	// userClient is used to force users to change expired passwords. Password expiry is disabled when it is nil.
+	userClient         mgmtcontrollers.UserClient
+	passwordMaxAgeDays func() int
+	now                func() time.Time
 }
 
 func Configure(ctx context.Context, mgmtCtx *config.ScaledContext, _ user.Manager) common.AuthProvider {
-	return NewProvider(
+	provider := NewProvider(
 		mgmtCtx.Management.Users("").Controller().Informer(),
 		mgmtCtx.Management.Users("").Controller().Lister(),
 		pbkdf2.New(mgmtCtx.Wrangler.Core.Secret().Cache(), mgmtCtx.Wrangler.Core.Secret()),
 	)
+	provider.userClient = mgmtCtx.Wrangler.Mgmt.User()
+	provider.passwordMaxAgeDays = settings.PasswordMaxAgeDays.GetInt
+	provider.now = time.Now
+	return provider
 }
 
 // NewProvider returns a Provider backed by informer's user cache. It registers
@@ -140,6 +154,10 @@ func (l *Provider) AuthenticateUser(_ http.ResponseWriter, _ *http.Request, inpu
 		return apiv3.Principal{}, nil, "", authFailedError
 	}
 
+	if err := l.expirePassword(user); err != nil {
+		logrus.Errorf("Failed to check the password expiry of User [%s]: %v", username, err)
+	}
+
 	principalID := getLocalPrincipalID(user)
 	userPrincipal := l.toPrincipal("user", user.DisplayName, user.Username, principalID, nil)
 	userPrincipal.Me = true
@@ -147,6 +165,44 @@ func (l *Provider) AuthenticateUser(_ http.ResponseWriter, _ *http.Request, inpu
 	return userPrincipal, []apiv3.Principal{}, "", nil
 }
 
+// expirePassword forces the user to change their password at login when it is
+// older than the number of days of the password-max-age-days setting.
+func (l *Provider) expirePassword(user *apiv3.User) error {
+	if l.userClient == nil || user.MustChangePassword {
+		return nil
+	}
+	days := l.passwordMaxAgeDays()
+	if days <= 0 {
+		return nil
+	}
+
+	changedAt, err := l.pwdVerifier.PasswordChangedAt(user.Name)
+	if err != nil {
+		return err
+	}
+	if l.now().Before(changedAt.AddDate(0, 0, days)) {
+		return nil
+	}
+
+	patch, err := json.Marshal([]struct {
+		Op    string `json:"op"`
+		Path  string `json:"path"`
+		Value any    `json:"value"`
+	}{{
+		Op:    "add",
+		Path:  "/mustChangePassword",
+		Value: true,
+	}})
+	if err != nil {
+		return err
+	}
+	if _, err := l.userClient.Patch(user.Name, k8stypes.JSONPatchType, patch); err != nil {
+		return err
+	}
+	logrus.Infof("Password of User [%s] expired, the user must change it", user.Username)
+	return nil
+}
+
 // isLocalUser reports whether a User resource represents a user that can log
 // in locally. A user is local if it has a local login username, or if its only
 // principal is a local:// one. External-auth users (SAML, OAuth, SCIM) always
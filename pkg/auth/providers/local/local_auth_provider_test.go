@@ -3,12 +3,17 @@
 import (
 	"sort"
 	"testing"
+	"time"
 
 	ext "github.com/rancher/rancher/pkg/apis/ext.cattle.io/v1"
 	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/wrangler/v3/pkg/generic/fake"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	k8stypes "k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/cache"
 )
 
@@ -419,3 +424,67 @@ func TestProviderSearchPrincipalWhitespaceSearchKey(t *testing.T) {
 		require.Empty(t, got, "search key %q", searchKey)
 	}
 }
+
+type fakePasswordVerifier struct {
+	changedAt time.Time
+}
+
+func (f fakePasswordVerifier) VerifyPassword(_ *v3.User, _ string) error {
+	return nil
+}
+
+func (f fakePasswordVerifier) PasswordChangedAt(_ string) (time.Time, error) {
+	return f.changedAt, nil
+}
+
+func TestExpirePassword(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := map[string]struct {
+		changedAt          time.Time
+		maxAgeDays         int
+		mustChangePassword bool
+		wantPatch          bool
+	}{
+		"expiry disabled": {
+			changedAt: now.AddDate(-1, 0, 0),
+		},
+		"recent password": {
+			changedAt:  now.AddDate(0, 0, -89),
+			maxAgeDays: 90,
+		},
+		"expired password": {
+			changedAt:  now.AddDate(0, 0, -90),
+			maxAgeDays: 90,
+			wantPatch:  true,
+		},
+		"already forced to change": {
+			changedAt:          now.AddDate(-1, 0, 0),
+			maxAgeDays:         90,
+			mustChangePassword: true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			userClient := fake.NewMockNonNamespacedControllerInterface[*v3.User, *v3.UserList](ctrl)
+			if tt.wantPatch {
+				userClient.EXPECT().Patch("u-abcdef", k8stypes.JSONPatchType,
+					[]byte(`[{"op":"add","path":"/mustChangePassword","value":true}]`)).Return(nil, nil)
+			}
+			provider := &Provider{
+				pwdVerifier:        fakePasswordVerifier{changedAt: tt.changedAt},
+				userClient:         userClient,
+				passwordMaxAgeDays: func() int { return tt.maxAgeDays },
+				now:                func() time.Time { return now },
+			}
+
+			err := provider.expirePassword(&v3.User{
+				ObjectMeta:         metav1.ObjectMeta{Name: "u-abcdef"},
+				Username:           "alice",
+				MustChangePassword: tt.mustChangePassword,
+			})
+			assert.NoError(t, err)
+		})
+	}
+}
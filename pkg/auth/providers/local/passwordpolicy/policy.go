@@ -0,0 +1,246 @@
+// passwordpolicy implements the policy local user passwords must comply with.
+package passwordpolicy
+
+import (
+	"bufio"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/rancher/rancher/pkg/settings"
+)
+
+// Character classes of the password-required-character-classes setting.
+const (
+	ClassLower  = "lower"
+	ClassUpper  = "upper"
+	ClassDigit  = "digit"
+	ClassSymbol = "symbol"
+)
+
+// Policy is the policy local user passwords must comply with when they are set. The reuse of previous passwords and
+// password expiry are handled by the password store and the local auth provider.
+type Policy struct {
+	// MinLength is the minimum number of runes of passwords.
+	MinLength int
+	// RequiredClasses are the character classes passwords must contain.
+	RequiredClasses []string
+	// BlockedWords are the words passwords must not contain, case-insensitively.
+	BlockedWords []string
+	// BreachedHashesFile is the path to a file of SHA-1 hashes of breached passwords.
+	BreachedHashesFile string
+}
+
+// FromSettings returns the policy configured with the password settings.
+func FromSettings() Policy {
+	return Policy{
+		MinLength:          settings.PasswordMinLength.GetInt(),
+		RequiredClasses:    splitList(settings.PasswordRequiredCharacterClasses.Get()),
+		BlockedWords:       splitList(settings.PasswordBlockedWords.Get()),
+		BreachedHashesFile: strings.TrimSpace(settings.PasswordBreachedHashesFile.Get()),
+	}
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// Validate returns an error if the password doesn't comply with the policy.
+func (p Policy) Validate(password string) error {
+	if utf8.RuneCountInString(password) < p.MinLength {
+		return fmt.Errorf("password must be at least %d characters", p.MinLength)
+	}
+
+	for _, class := range p.RequiredClasses {
+		contains, err := containsClass(password, class)
+		if err != nil {
+			return err
+		}
+		if !contains {
+			return fmt.Errorf("password must contain at least one %s character", className(class))
+		}
+	}
+
+	lower := strings.ToLower(password)
+	for _, word := range p.BlockedWords {
+		if strings.Contains(lower, strings.ToLower(word)) {
+			return fmt.Errorf("password must not contain %q", word)
+		}
+	}
+
+	if p.BreachedHashesFile != "" {
+		breached, err := isBreached(p.BreachedHashesFile, password)
+		if err != nil {
+			return fmt.Errorf("unable to check password against breached passwords: %w", err)
+		}
+		if breached {
+			return fmt.Errorf("password was found in a list of breached passwords")
+		}
+	}
+
+	return nil
+}
+
+// Characters of generated passwords, by character class.
+var generatedCharacters = []string{
+	"abcdefghijklmnopqrstuvwxyz",
+	"ABCDEFGHIJKLMNOPQRSTUVWXYZ",
+	"0123456789",
+	"-_",
+}
+
+// generateAttempts is the number of passwords generated before giving up on finding one that isn't blocked or
+// breached.
+const generateAttempts = 100
+
+// Generate returns a random password of at least length characters complying with the policy. Generated passwords
+// contain characters of every class, so they comply with any required character classes.
+func (p Policy) Generate(length int) (string, error) {
+	length = max(length, p.MinLength, len(generatedCharacters))
+	all := strings.Join(generatedCharacters, "")
+
+	var err error
+	for range generateAttempts {
+		password := make([]byte, 0, length)
+		for _, characters := range generatedCharacters {
+			c, err := randomCharacter(characters)
+			if err != nil {
+				return "", err
+			}
+			password = append(password, c)
+		}
+		for len(password) < length {
+			c, err := randomCharacter(all)
+			if err != nil {
+				return "", err
+			}
+			password = append(password, c)
+		}
+		// Shuffle so that the characters of each class aren't always at the start.
+		for i := len(password) - 1; i > 0; i-- {
+			j, err := rand.Int(rand.Reader, big.NewInt(int64(i+1)))
+			if err != nil {
+				return "", err
+			}
+			password[i], password[j.Int64()] = password[j.Int64()], password[i]
+		}
+
+		if err = p.Validate(string(password)); err == nil {
+			return string(password), nil
+		}
+	}
+	return "", fmt.Errorf("unable to generate a password complying with the password policy: %w", err)
+}
+
+func randomCharacter(characters string) (byte, error) {
+	i, err := rand.Int(rand.Reader, big.NewInt(int64(len(characters))))
+	if err != nil {
+		return 0, err
+	}
+	return characters[i.Int64()], nil
+}
+
+func containsClass(password, class string) (bool, error) {
+	var in func(r rune) bool
+	switch strings.ToLower(class) {
+	case ClassLower:
+		in = unicode.IsLower
+	case ClassUpper:
+		in = unicode.IsUpper
+	case ClassDigit:
+		in = unicode.IsDigit
+	case ClassSymbol:
+		in = func(r rune) bool { return unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsSpace(r) }
+	default:
+		return false, fmt.Errorf("invalid character class %q in %s setting", class,
+			settings.PasswordRequiredCharacterClasses.Name)
+	}
+	return strings.IndexFunc(password, in) >= 0, nil
+}
+
+func className(class string) string {
+	switch strings.ToLower(class) {
+	case ClassLower:
+		return "lowercase"
+	case ClassUpper:
+		return "uppercase"
+	}
+	return strings.ToLower(class)
+}
+
+// isBreached looks the SHA-1 hash of the password up in the sorted file of breached password hashes. The file is
+// binary searched so that large files, such as the Have I Been Pwned downloads, don't need to be read entirely.
+func isBreached(path, password string) (bool, error) {
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+
+	f, err := os.Open(path)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return false, err
+	}
+	size := info.Size()
+
+	// Find the smallest offset at which the next line holds a hash which isn't before the hash of the password.
+	lo, hi := int64(0), size
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+		lineHash, err := hashAt(f, mid, size)
+		if err != nil {
+			return false, err
+		}
+		if lineHash == "" || lineHash >= hash {
+			hi = mid
+		} else {
+			lo = mid + 1
+		}
+	}
+	lineHash, err := hashAt(f, lo, size)
+	if err != nil {
+		return false, err
+	}
+	return lineHash == hash, nil
+}
+
+// hashAt returns the hash of the first line starting at or after offset, or an empty string at the end of the file.
+func hashAt(f *os.File, offset, size int64) (string, error) {
+	start := offset
+	if start > 0 {
+		// Start one byte earlier so that a line starting exactly at offset isn't skipped.
+		start--
+	}
+	r := bufio.NewReader(io.NewSectionReader(f, start, size-start))
+	if offset > 0 {
+		if _, err := r.ReadString('\n'); err == io.EOF {
+			return "", nil
+		} else if err != nil {
+			return "", err
+		}
+	}
+	line, err := r.ReadString('\n')
+	if err != nil && err != io.EOF {
+		return "", err
+	}
+	line = strings.TrimSpace(line)
+	if i := strings.IndexByte(line, ':'); i >= 0 {
+		line = line[:i]
+	}
+	return strings.ToUpper(line), nil
+}
@@ -0,0 +1,141 @@
+package passwordpolicy
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func sha1Hex(s string) string {
+	sum := sha1.Sum([]byte(s))
+	return strings.ToUpper(hex.EncodeToString(sum[:]))
+}
+
+func TestValidate(t *testing.T) {
+	var hashes []string
+	for i := 0; i < 100; i++ {
+		hashes = append(hashes, sha1Hex(fmt.Sprintf("breached-password-%d", i)))
+	}
+	sort.Strings(hashes)
+	var content strings.Builder
+	for i, hash := range hashes {
+		fmt.Fprintf(&content, "%s:%d\r\n", hash, i+1)
+	}
+	breachedFile := filepath.Join(t.TempDir(), "breached.txt")
+	require.NoError(t, os.WriteFile(breachedFile, []byte(content.String()), 0o600))
+
+	tests := map[string]struct {
+		policy   Policy
+		password string
+		wantErr  string
+	}{
+		"valid": {
+			policy:   Policy{MinLength: 12, RequiredClasses: []string{ClassLower, ClassUpper, ClassDigit, ClassSymbol}},
+			password: "Correct-Horse-42",
+		},
+		"too short": {
+			policy:   Policy{MinLength: 12},
+			password: "short",
+			wantErr:  "password must be at least 12 characters",
+		},
+		"runes are counted": {
+			policy:   Policy{MinLength: 12},
+			password: "абвгдеёжзий1",
+		},
+		"missing class": {
+			policy:   Policy{MinLength: 12, RequiredClasses: []string{ClassLower, ClassDigit}},
+			password: "no-digits-at-all",
+			wantErr:  "password must contain at least one digit character",
+		},
+		"missing uppercase": {
+			policy:   Policy{RequiredClasses: []string{"Upper"}},
+			password: "all lowercase",
+			wantErr:  "password must contain at least one uppercase character",
+		},
+		"invalid class": {
+			policy:   Policy{RequiredClasses: []string{"emoji"}},
+			password: "whatever",
+			wantErr:  `invalid character class "emoji" in password-required-character-classes setting`,
+		},
+		"blocked word": {
+			policy:   Policy{BlockedWords: []string{"rancher"}},
+			password: "my-RANCHER-password",
+			wantErr:  `password must not contain "rancher"`,
+		},
+		"breached": {
+			policy:   Policy{BreachedHashesFile: breachedFile},
+			password: "breached-password-42",
+			wantErr:  "password was found in a list of breached passwords",
+		},
+		"not breached": {
+			policy:   Policy{BreachedHashesFile: breachedFile},
+			password: "never-breached-password",
+		},
+		"missing breached file": {
+			policy:   Policy{BreachedHashesFile: filepath.Join(t.TempDir(), "missing.txt")},
+			password: "whatever",
+			wantErr:  "unable to check password against breached passwords",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := tt.policy.Validate(tt.password)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestIsBreachedAllHashes(t *testing.T) {
+	var hashes []string
+	passwords := map[string]string{}
+	for i := 0; i < 50; i++ {
+		password := fmt.Sprintf("password-%d", i)
+		hashes = append(hashes, sha1Hex(password))
+		passwords[sha1Hex(password)] = password
+	}
+	sort.Strings(hashes)
+	path := filepath.Join(t.TempDir(), "breached.txt")
+	require.NoError(t, os.WriteFile(path, []byte(strings.Join(hashes, "\n")), 0o600))
+
+	for _, hash := range hashes {
+		breached, err := isBreached(path, passwords[hash])
+		require.NoError(t, err)
+		assert.True(t, breached, passwords[hash])
+	}
+	breached, err := isBreached(path, "password-50")
+	require.NoError(t, err)
+	assert.False(t, breached)
+}
+
+func TestGenerate(t *testing.T) {
+	policy := Policy{
+		MinLength:       24,
+		RequiredClasses: []string{ClassLower, ClassUpper, ClassDigit, ClassSymbol},
+		BlockedWords:    []string{"a", "b", "c"},
+	}
+	for i := 0; i < 20; i++ {
+		password, err := policy.Generate(20)
+		require.NoError(t, err)
+		assert.Len(t, password, 24)
+		assert.NoError(t, policy.Validate(password))
+	}
+
+	password, err := Policy{}.Generate(20)
+	require.NoError(t, err)
+	assert.Len(t, password, 20)
+
+	_, err = Policy{BlockedWords: []string{"-", "_"}}.Generate(20)
+	assert.ErrorContains(t, err, "unable to generate a password complying with the password policy")
+}
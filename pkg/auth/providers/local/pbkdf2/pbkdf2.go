@@ -6,10 +6,13 @@
 	"crypto/rand"
 	"crypto/sha3"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/settings"
 	v1 "github.com/rancher/wrangler/v3/pkg/generated/controllers/core/v1"
 	"golang.org/x/crypto/bcrypt"
 	corev1 "k8s.io/api/core/v1"
@@ -22,10 +25,15 @@
 	iterations                  = 210000
 	keyLength                   = 32
 	passwordHashAnnotation      = "cattle.io/password-hash"
+	passwordChangedAtAnnotation = "cattle.io/password-changed-at"
+	historyField                = "history"
 	pbkdf2sha3512Hash           = "pbkdf2sha3512"
 	bcryptHash                  = "bcrypt"
 )
 
+// ErrPasswordReused is returned when a password is changed to one of the most recent passwords of the user.
+var ErrPasswordReused = errors.New("password was used recently and can't be reused")
+
 // Pbkdf2 handles password storage and hashing using PBKDF2.
 type Pbkdf2 struct {
 	secretLister  v1.SecretCache
@@ -33,6 +41,15 @@ type Pbkdf2 struct {
 	hashKey       func(password string, salt []byte, iter, keyLength int) ([]byte, error)
 	bcryptKey     func(password []byte, cost int) ([]byte, error)
 	saltGenerator func() ([]byte, error)
+	historySize   func() int
+	now           func() time.Time
+}
+
+// historyEntry is a previous password of a user, hashed the same way as the password it replaced.
+type historyEntry struct {
+	Algorithm string `json:"algorithm"`
+	Hash      []byte `json:"hash"`
+	Salt      []byte `json:"salt,omitempty"`
 }
 
 func New(secretLister v1.SecretCache, secretClient v1.SecretClient) *Pbkdf2 {
@@ -42,6 +59,8 @@ func New(secretLister v1.SecretCache, secretClient v1.SecretClient) *Pbkdf2 {
 		hashKey:       sha3512Key,
 		bcryptKey:     bcrypt.GenerateFromPassword,
 		saltGenerator: generateSalt,
+		historySize:   settings.PasswordHistorySize.GetInt,
+		now:           time.Now,
 	}
 }
 
@@ -61,7 +80,8 @@ func (p *Pbkdf2) CreatePassword(user *v3.User, password string) error {
 			Name:      user.Name,
 			Namespace: LocalUserPasswordsNamespace,
 			Annotations: map[string]string{
-				passwordHashAnnotation: pbkdf2sha3512Hash,
+				passwordHashAnnotation:      pbkdf2sha3512Hash,
+				passwordChangedAtAnnotation: p.now().UTC().Format(time.RFC3339),
 			},
 			OwnerReferences: []metav1.OwnerReference{
 				{
@@ -89,13 +109,19 @@ func (p *Pbkdf2) CreatePassword(user *v3.User, password string) error {
 // needed because the secret may not have been migrated to PBKDF2 at the time
 // the password is changed. This happens when an admin changes the password for
 // a user which has not logged in since the upgrade, leaving its secret to
-// contain a BCRYPT hash.
+// contain a BCRYPT hash. ErrPasswordReused is returned if the new password is
+// one of the most recent passwords of the user.
 func (p *Pbkdf2) UpdatePassword(userId string, newPassword string) error {
 	secret, err := p.secretLister.Get(LocalUserPasswordsNamespace, userId)
 	if err != nil {
 		return fmt.Errorf("failed to get password secret: %w", err)
 	}
 
+	history, err := p.checkHistory(secret, newPassword)
+	if err != nil {
+		return err
+	}
+
 	var value map[string][]byte
 	switch secret.Annotations[passwordHashAnnotation] {
 	case pbkdf2sha3512Hash:
@@ -126,15 +152,29 @@ func (p *Pbkdf2) UpdatePassword(userId string, newPassword string) error {
 		return fmt.Errorf("unsupported hashing algorithm %q", secret.Annotations[passwordHashAnnotation])
 	}
 
+	if len(history) > 0 {
+		value[historyField], err = json.Marshal(history)
+		if err != nil {
+			return fmt.Errorf("failed to marshal password history: %w", err)
+		}
+	}
+
 	patch, err := json.Marshal([]struct {
 		Op    string `json:"op"`
 		Path  string `json:"path"`
 		Value any    `json:"value"`
-	}{{
-		Op:    "replace",
-		Path:  "/data",
-		Value: value,
-	}})
+	}{
+		{
+			Op:    "replace",
+			Path:  "/data",
+			Value: value,
+		},
+		{
+			Op:    "add",
+			Path:  "/metadata/annotations/" + rfc6901PathEscape(passwordChangedAtAnnotation),
+			Value: p.now().UTC().Format(time.RFC3339),
+		},
+	})
 	if err != nil {
 		return fmt.Errorf("failed to marshal patch: %w", err)
 	}
@@ -147,6 +187,84 @@ func (p *Pbkdf2) UpdatePassword(userId string, newPassword string) error {
 	return nil
 }
 
+// checkHistory returns ErrPasswordReused if the password is one of the most
+// recent passwords of the user, the number of which is set by the
+// password-history-size setting. Otherwise it returns the previous passwords
+// to keep once the password is changed.
+func (p *Pbkdf2) checkHistory(secret *corev1.Secret, password string) ([]historyEntry, error) {
+	size := p.historySize()
+	if size <= 0 {
+		return nil, nil
+	}
+
+	var history []historyEntry
+	if data := secret.Data[historyField]; len(data) > 0 {
+		if err := json.Unmarshal(data, &history); err != nil {
+			return nil, fmt.Errorf("failed to parse password history: %w", err)
+		}
+	}
+	if hash := secret.Data["password"]; len(hash) > 0 {
+		current := historyEntry{
+			Algorithm: secret.Annotations[passwordHashAnnotation],
+			Hash:      hash,
+			Salt:      secret.Data["salt"],
+		}
+		history = append([]historyEntry{current}, history...)
+	}
+	if len(history) > size {
+		history = history[:size]
+	}
+
+	for _, entry := range history {
+		matches, err := p.matches(entry, password)
+		if err != nil {
+			return nil, err
+		}
+		if matches {
+			return nil, ErrPasswordReused
+		}
+	}
+
+	// The new password counts as one of the most recent passwords once set.
+	return history[:min(len(history), size-1)], nil
+}
+
+// matches returns true if the password is the one of the history entry.
+func (p *Pbkdf2) matches(entry historyEntry, password string) (bool, error) {
+	switch entry.Algorithm {
+	case pbkdf2sha3512Hash:
+		hashedPassword, err := p.hashKey(password, entry.Salt, iterations, keyLength)
+		if err != nil {
+			return false, fmt.Errorf("failed to hash password: %w", err)
+		}
+		return bytes.Equal(hashedPassword, entry.Hash), nil
+	case bcryptHash:
+		return bcrypt.CompareHashAndPassword(entry.Hash, []byte(password)) == nil, nil
+	default:
+		return false, nil
+	}
+}
+
+// PasswordChangedAt returns the time the password of the user was last set.
+// The creation time of the secret is returned for passwords set before the
+// time was recorded.
+func (p *Pbkdf2) PasswordChangedAt(userId string) (time.Time, error) {
+	secret, err := p.secretLister.Get(LocalUserPasswordsNamespace, userId)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to get password secret: %w", err)
+	}
+
+	if value, ok := secret.Annotations[passwordChangedAtAnnotation]; ok {
+		changedAt, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("failed to parse %s annotation: %w", passwordChangedAtAnnotation, err)
+		}
+		return changedAt, nil
+	}
+
+	return secret.CreationTimestamp.Time, nil
+}
+
 // VerifyAndUpdatePassword hashes the provided password using PBKDF2 and updates the secret associated with the specified user
 // if the currentPassword matches the password stored.
 func (p *Pbkdf2) VerifyAndUpdatePassword(userId string, currentPassword, newPassword string) error {
@@ -198,6 +316,13 @@ func (p *Pbkdf2) VerifyPassword(user *v3.User, password string) error {
 		if err != nil {
 			return fmt.Errorf("failed to hash password: %w", err)
 		}
+		value := map[string][]byte{
+			"password": hashedNewPassword,
+			"salt":     salt,
+		}
+		if history, ok := secret.Data[historyField]; ok {
+			value[historyField] = history
+		}
 
 		patch, err := json.Marshal([]struct {
 			Op    string `json:"op"`
@@ -205,12 +330,9 @@ func (p *Pbkdf2) VerifyPassword(user *v3.User, password string) error {
 			Value any    `json:"value"`
 		}{
 			{
-				Op:   "replace",
-				Path: "/data",
-				Value: map[string][]byte{
-					"password": hashedNewPassword,
-					"salt":     salt,
-				},
+				Op:    "replace",
+				Path:  "/data",
+				Value: value,
 			},
 			{
 				Op:    "replace",
@@ -4,6 +4,7 @@
 	"encoding/json"
 	"errors"
 	"testing"
+	"time"
 
 	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
 	"github.com/rancher/wrangler/v3/pkg/generic/fake"
@@ -15,6 +16,8 @@
 	"k8s.io/apimachinery/pkg/types"
 )
 
+var fakeNow = time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
 func TestCreatePassword(t *testing.T) {
 	ctlr := gomock.NewController(t)
 	fakeUserID := "fake-user-id"
@@ -60,7 +63,8 @@ func TestCreatePassword(t *testing.T) {
 						Name:      fakeUserID,
 						Namespace: LocalUserPasswordsNamespace,
 						Annotations: map[string]string{
-							passwordHashAnnotation: pbkdf2sha3512Hash,
+							passwordHashAnnotation:      pbkdf2sha3512Hash,
+							passwordChangedAtAnnotation: fakeNow.Format(time.RFC3339),
 						},
 						OwnerReferences: []metav1.OwnerReference{
 							{
@@ -138,7 +142,8 @@ func TestCreatePassword(t *testing.T) {
 						Name:      fakeUserID,
 						Namespace: LocalUserPasswordsNamespace,
 						Annotations: map[string]string{
-							passwordHashAnnotation: pbkdf2sha3512Hash,
+							passwordHashAnnotation:      pbkdf2sha3512Hash,
+							passwordChangedAtAnnotation: fakeNow.Format(time.RFC3339),
 						},
 						OwnerReferences: []metav1.OwnerReference{
 							{
@@ -167,6 +172,8 @@ func TestCreatePassword(t *testing.T) {
 				secretClient:  test.mockSecretClient(),
 				hashKey:       test.mockHashKey,
 				saltGenerator: test.mockSaltGenerator,
+				historySize:   func() int { return 0 },
+				now:           func() time.Time { return fakeNow },
 			}
 			err := p.CreatePassword(test.user, test.password)
 			if test.expectErrorMessage == "" {
@@ -233,6 +240,10 @@ func TestUpdatePassword(t *testing.T) {
 						"password": []byte(fakeNewPasswordHash),
 						"salt":     []byte(fakeNewPasswordSalt),
 					},
+				}, {
+					Op:    "add",
+					Path:  "/metadata/annotations/cattle.io~1password-changed-at",
+					Value: fakeNow.Format(time.RFC3339),
 				}})
 				mock.EXPECT().Patch(LocalUserPasswordsNamespace, fakeUserID, types.JSONPatchType, patch).Return(nil, nil)
 
@@ -275,6 +286,10 @@ func TestUpdatePassword(t *testing.T) {
 					Value: map[string][]byte{
 						"password": []byte(fakeNewPasswordBcryptHash),
 					},
+				}, {
+					Op:    "add",
+					Path:  "/metadata/annotations/cattle.io~1password-changed-at",
+					Value: fakeNow.Format(time.RFC3339),
 				}})
 				mock.EXPECT().Patch(LocalUserPasswordsNamespace, fakeUserID, types.JSONPatchType, patch).Return(nil, nil)
 
@@ -368,6 +383,10 @@ func TestUpdatePassword(t *testing.T) {
 						"password": []byte(fakeNewPasswordHash),
 						"salt":     []byte(fakeNewPasswordSalt),
 					},
+				}, {
+					Op:    "add",
+					Path:  "/metadata/annotations/cattle.io~1password-changed-at",
+					Value: fakeNow.Format(time.RFC3339),
 				}})
 				mock.EXPECT().Patch(LocalUserPasswordsNamespace, fakeUserID, types.JSONPatchType, patch).Return(nil, errors.New("unexpected error"))
 
@@ -389,6 +408,8 @@ func TestUpdatePassword(t *testing.T) {
 				hashKey:       test.mockHashKey,
 				bcryptKey:     test.mockBcryptKey,
 				saltGenerator: test.mockSaltGenerator,
+				historySize:   func() int { return 0 },
+				now:           func() time.Time { return fakeNow },
 			}
 			err := p.UpdatePassword(test.userID, test.password)
 			if test.expectErrorMessage == "" {
@@ -465,6 +486,10 @@ func TestVerifyAndUpdatePassword(t *testing.T) {
 						"password": []byte(fakeNewPasswordHash),
 						"salt":     []byte(fakeNewPasswordSalt),
 					},
+				}, {
+					Op:    "add",
+					Path:  "/metadata/annotations/cattle.io~1password-changed-at",
+					Value: fakeNow.Format(time.RFC3339),
 				}})
 				mock.EXPECT().Patch(LocalUserPasswordsNamespace, fakeUserID, types.JSONPatchType, patch).Return(nil, nil)
 				return mock
@@ -566,6 +591,10 @@ func TestVerifyAndUpdatePassword(t *testing.T) {
 						"password": []byte(fakeNewPasswordHash),
 						"salt":     []byte(fakeNewPasswordSalt),
 					},
+				}, {
+					Op:    "add",
+					Path:  "/metadata/annotations/cattle.io~1password-changed-at",
+					Value: fakeNow.Format(time.RFC3339),
 				}})
 				mock.EXPECT().Patch(LocalUserPasswordsNamespace, fakeUserID, types.JSONPatchType, patch).Return(nil, errors.New("unexpected error"))
 
@@ -586,6 +615,8 @@ func TestVerifyAndUpdatePassword(t *testing.T) {
 				secretLister:  test.mockSecretCache(),
 				hashKey:       test.mockHashKey,
 				saltGenerator: test.mockSaltGenerator,
+				historySize:   func() int { return 0 },
+				now:           func() time.Time { return fakeNow },
 			}
 			err := p.VerifyAndUpdatePassword(test.userID, test.currentPassword, test.newPassword)
 			if test.expectErrorMessage == "" {
@@ -790,3 +821,144 @@ func TestVerifyPassword(t *testing.T) {
 		})
 	}
 }
+
+func TestUpdatePasswordHistory(t *testing.T) {
+	fakeUserID := "fake-user-id"
+	// Hash passwords as themselves, salted, to keep the test fast.
+	hashKey := func(password string, salt []byte, _, _ int) ([]byte, error) {
+		return []byte(string(salt) + password), nil
+	}
+	history, err := json.Marshal([]historyEntry{
+		{Algorithm: pbkdf2sha3512Hash, Hash: []byte("salt-1previous-1"), Salt: []byte("salt-1")},
+		{Algorithm: pbkdf2sha3512Hash, Hash: []byte("salt-2previous-2"), Salt: []byte("salt-2")},
+	})
+	assert.NoError(t, err)
+
+	tests := map[string]struct {
+		password    string
+		historySize int
+		wantErr     error
+		wantHistory []historyEntry
+	}{
+		"current password": {
+			password:    "current",
+			historySize: 1,
+			wantErr:     ErrPasswordReused,
+		},
+		"previous password": {
+			password:    "previous-2",
+			historySize: 3,
+			wantErr:     ErrPasswordReused,
+		},
+		"password older than the history": {
+			password:    "previous-2",
+			historySize: 2,
+			wantHistory: []historyEntry{
+				{Algorithm: pbkdf2sha3512Hash, Hash: []byte("salt-0current"), Salt: []byte("salt-0")},
+			},
+		},
+		"new password": {
+			password:    "new",
+			historySize: 5,
+			wantHistory: []historyEntry{
+				{Algorithm: pbkdf2sha3512Hash, Hash: []byte("salt-0current"), Salt: []byte("salt-0")},
+				{Algorithm: pbkdf2sha3512Hash, Hash: []byte("salt-1previous-1"), Salt: []byte("salt-1")},
+				{Algorithm: pbkdf2sha3512Hash, Hash: []byte("salt-2previous-2"), Salt: []byte("salt-2")},
+			},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctlr := gomock.NewController(t)
+			secretCache := fake.NewMockCacheInterface[*v1.Secret](ctlr)
+			secretCache.EXPECT().Get(LocalUserPasswordsNamespace, fakeUserID).Return(&v1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        fakeUserID,
+					Namespace:   LocalUserPasswordsNamespace,
+					Annotations: map[string]string{passwordHashAnnotation: pbkdf2sha3512Hash},
+				},
+				Data: map[string][]byte{
+					"password":   []byte("salt-0current"),
+					"salt":       []byte("salt-0"),
+					historyField: history,
+				},
+			}, nil)
+			secretClient := fake.NewMockClientInterface[*v1.Secret, *v1.SecretList](ctlr)
+			if test.wantErr == nil {
+				secretClient.EXPECT().Patch(LocalUserPasswordsNamespace, fakeUserID, types.JSONPatchType, gomock.Any()).
+					DoAndReturn(func(_, _ string, _ types.PatchType, data []byte, _ ...string) (*v1.Secret, error) {
+						var patch []struct {
+							Value json.RawMessage `json:"value"`
+						}
+						assert.NoError(t, json.Unmarshal(data, &patch))
+						var value map[string][]byte
+						assert.NoError(t, json.Unmarshal(patch[0].Value, &value))
+						assert.Equal(t, []byte("salt-new"+test.password), value["password"])
+						var history []historyEntry
+						if len(value[historyField]) > 0 {
+							assert.NoError(t, json.Unmarshal(value[historyField], &history))
+						}
+						assert.Equal(t, test.wantHistory, history)
+						return nil, nil
+					})
+			}
+
+			p := Pbkdf2{
+				secretClient:  secretClient,
+				secretLister:  secretCache,
+				hashKey:       hashKey,
+				saltGenerator: func() ([]byte, error) { return []byte("salt-new"), nil },
+				historySize:   func() int { return test.historySize },
+				now:           func() time.Time { return fakeNow },
+			}
+			err := p.UpdatePassword(fakeUserID, test.password)
+			assert.ErrorIs(t, err, test.wantErr)
+		})
+	}
+}
+
+func TestPasswordChangedAt(t *testing.T) {
+	fakeUserID := "fake-user-id"
+	createdAt := fakeNow.Add(-365 * 24 * time.Hour)
+
+	tests := map[string]struct {
+		annotations map[string]string
+		want        time.Time
+		wantErr     string
+	}{
+		"recorded change time": {
+			annotations: map[string]string{passwordChangedAtAnnotation: fakeNow.Format(time.RFC3339)},
+			want:        fakeNow,
+		},
+		"creation time of the secret": {
+			want: createdAt,
+		},
+		"invalid change time": {
+			annotations: map[string]string{passwordChangedAtAnnotation: "yesterday"},
+			wantErr:     "failed to parse cattle.io/password-changed-at annotation",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctlr := gomock.NewController(t)
+			secretCache := fake.NewMockCacheInterface[*v1.Secret](ctlr)
+			secretCache.EXPECT().Get(LocalUserPasswordsNamespace, fakeUserID).Return(&v1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              fakeUserID,
+					Namespace:         LocalUserPasswordsNamespace,
+					Annotations:       test.annotations,
+					CreationTimestamp: metav1.NewTime(createdAt),
+				},
+			}, nil)
+
+			p := Pbkdf2{secretLister: secretCache}
+			changedAt, err := p.PasswordChangedAt(fakeUserID)
+			if test.wantErr != "" {
+				assert.ErrorContains(t, err, test.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.True(t, test.want.Equal(changedAt))
+		})
+	}
+}
@@ -8,6 +8,7 @@
 	normanapi "github.com/rancher/norman/api"
 	"github.com/rancher/norman/store/subtype"
 	"github.com/rancher/norman/types"
+	"github.com/rancher/rancher/pkg/auth/workloadidentity"
 	v3public "github.com/rancher/rancher/pkg/client/generated/management/v3public"
 	publicSchema "github.com/rancher/rancher/pkg/schemas/management.cattle.io/v3public"
 	"github.com/rancher/rancher/pkg/types/config"
@@ -22,11 +23,17 @@ func NewV1Handler(ctx context.Context, scaledContext *config.ScaledContext) (htt
 
 	authTokenStore := newV1AuthTokenStore(scaledContext.Wrangler)
 
+	tokenExchange, err := workloadidentity.NewHandler(ctx, scaledContext)
+	if err != nil {
+		return nil, fmt.Errorf("creating token exchange handler: %w", err)
+	}
+
 	r := http.NewServeMux()
 	r.HandleFunc("GET /v1-public/authproviders", providerStore.List)
 	r.HandleFunc("POST /v1-public/login", newV1LoginHandler(scaledContext).login)
 	r.HandleFunc("GET /v1-public/authtokens/{id}", authTokenStore.Get)
 	r.HandleFunc("DELETE /v1-public/authtokens/{id}", authTokenStore.Delete)
+	r.Handle("POST /v1-public/oauth/token", tokenExchange)
 
 	return r, nil
 }
@@ -20,6 +20,7 @@
 	"github.com/rancher/rancher/pkg/auth/providers/common"
 	"github.com/rancher/rancher/pkg/auth/tokens"
 	exttokenstore "github.com/rancher/rancher/pkg/ext/stores/tokens"
+	"github.com/rancher/rancher/pkg/ext/stores/tokenusage"
 	"github.com/rancher/rancher/pkg/features"
 	mgmtcontrollers "github.com/rancher/rancher/pkg/generated/controllers/management.cattle.io/v3"
 	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
@@ -91,8 +92,10 @@ type tokenAuthenticator struct {
 	refreshUser         func(userID string, force bool)
 	now                 func() time.Time // Make it easier to test.
 	extTokenStore       *exttokenstore.SystemStore
+	usageRecorder       *tokenusage.Recorder
 	keyGetter           publicKeyGetter
 	oidcClientCache     mgmtcontrollers.OIDCClientCache
+	authConfigCache     mgmtcontrollers.AuthConfigCache
 }
 
 // ToAuthMiddleware converts an Authenticator to an auth.Middleware.
@@ -125,6 +128,8 @@ func NewAuthenticator(ctx context.Context, clusterRouter ClusterRouter, mgmtCtx
 	providerRefresher := providerrefresh.NewUserAuthRefresher(mgmtCtx)
 
 	extTokenStore := exttokenstore.NewSystemFromWrangler(mgmtCtx.Wrangler)
+	usageRecorder := tokenusage.NewRecorder(mgmtCtx.Wrangler)
+	usageRecorder.Start(ctx.Done())
 
 	authenticator := &tokenAuthenticator{
 		ctx:                 ctx,
@@ -137,8 +142,10 @@ func NewAuthenticator(ctx context.Context, clusterRouter ClusterRouter, mgmtCtx
 		refreshUser: func(userID string, force bool) {
 			go providerRefresher.TriggerUserRefresh(userID, force)
 		},
-		now:           time.Now,
-		extTokenStore: extTokenStore,
+		now:             time.Now,
+		extTokenStore:   extTokenStore,
+		usageRecorder:   usageRecorder,
+		authConfigCache: mgmtCtx.Wrangler.Mgmt.AuthConfig().Cache(),
 	}
 
 	if features.OIDCProvider.Enabled() {
@@ -203,6 +210,22 @@ func (a *tokenAuthenticator) Authenticate(req *http.Request) (*AuthenticatorResp
 		return nil, errors.Wrap(ErrMustAuthenticate, "user is not enabled")
 	}
 
+	// Tokens created before a not-before watermark of the user or auth provider are revoked.
+	var authConfigAnnotations map[string]string
+	if a.authConfigCache != nil && token.GetAuthProvider() != "" {
+		authConfig, err := a.authConfigCache.Get(token.GetAuthProvider())
+		if err != nil && !apierrors.IsNotFound(err) {
+			return nil, errors.Wrapf(ErrMustAuthenticate,
+				"failed to retrieve auth config %s: %v", token.GetAuthProvider(), err)
+		}
+		if authConfig != nil {
+			authConfigAnnotations = authConfig.Annotations
+		}
+	}
+	if tokens.IsRevokedByWatermark(token, authUser.Annotations, authConfigAnnotations) {
+		return nil, errors.Wrap(ErrMustAuthenticate, "token was revoked")
+	}
+
 	var groups []string
 	hitProvider := false
 	if attribs != nil {
@@ -250,6 +273,10 @@ func (a *tokenAuthenticator) Authenticate(req *http.Request) (*AuthenticatorResp
 
 	logrus.Debugf("Extras returned %v", authResp.Extras)
 
+	if extToken, ok := token.(*ext.Token); ok && a.usageRecorder != nil {
+		a.usageRecorder.Record(extToken, req, cluster, a.now())
+	}
+
 	now := a.now().Truncate(time.Second) // Use the second precision.
 	lastUsed := token.GetLastUsedAt()
 	if lastUsed != nil {
@@ -19,6 +19,7 @@
 	"github.com/rancher/rancher/pkg/auth/providers"
 	"github.com/rancher/rancher/pkg/auth/providers/common"
 	"github.com/rancher/rancher/pkg/auth/providers/local"
+	"github.com/rancher/rancher/pkg/auth/tokens"
 	"github.com/rancher/rancher/pkg/auth/tokens/hashers"
 	"github.com/rancher/rancher/pkg/clusterrouter"
 	exttokenstore "github.com/rancher/rancher/pkg/ext/stores/tokens"
@@ -569,6 +570,29 @@ func TestTokenAuthenticatorAuthenticate(t *testing.T) {
 		assert.False(t, userRefresher.called)
 	})
 
+	t.Run("token created before the user's not-before watermark", func(t *testing.T) {
+		oldGetUserFunc := userLister.GetFunc
+		defer func() { userLister.GetFunc = oldGetUserFunc }()
+		userLister.GetFunc = func(_, _ string) (*v3.User, error) {
+			return &v3.User{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: userID,
+					Annotations: map[string]string{
+						tokens.NotBeforeAnnotation: now.Add(time.Hour).UTC().Format(time.RFC3339),
+					},
+				},
+				PrincipalIDs: []string{userPrincipalID},
+			}, nil
+		}
+
+		userRefresher.reset()
+
+		resp, err := authenticator.Authenticate(req)
+		require.ErrorIs(t, err, ErrMustAuthenticate)
+		require.Nil(t, resp)
+		assert.False(t, userRefresher.called)
+	})
+
 	t.Run("error getting userattribute", func(t *testing.T) {
 		oldGetUserAttributeFunc := userAttributeLister.GetFunc
 		defer func() { userAttributeLister.GetFunc = oldGetUserAttributeFunc }()
@@ -40,6 +40,32 @@ func SetTokenExpiresAt(token *apiv3.Token) {
 	}
 }
 
+// NotBeforeAnnotation holds a watermark set on Users and AuthConfigs by
+// TokenRevocationRequests. The tokens of the user, or issued by the auth
+// provider, created before the watermark are rejected.
+const NotBeforeAnnotation = "cattle.io/tokens-not-before"
+
+// IsRevokedByWatermark returns true if the token was created before the
+// not-before watermark in any of the annotations, i.e. those of its user or
+// auth config.
+func IsRevokedByWatermark(token accessor.TokenAccessor, annotations ...map[string]string) bool {
+	for _, annotation := range annotations {
+		value, ok := annotation[NotBeforeAnnotation]
+		if !ok {
+			continue
+		}
+		notBefore, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			logrus.Errorf("Invalid %s annotation: %v", NotBeforeAnnotation, err)
+			continue
+		}
+		if created := token.GetCreationTime(); created.Time.Before(notBefore) {
+			return true
+		}
+	}
+	return false
+}
+
 // IsExpired returns true if the token is expired.
 func IsExpired(token accessor.TokenAccessor) bool {
 	return token.GetIsExpired()
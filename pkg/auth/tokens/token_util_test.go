@@ -400,3 +400,20 @@ func TestIsIdleExpired(t *testing.T) {
 		})
 	}
 }
+
+func TestIsRevokedByWatermark(t *testing.T) {
+	t.Parallel()
+
+	created := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	token := &ext.Token{ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(created)}}
+	watermark := func(at time.Time) map[string]string {
+		return map[string]string{NotBeforeAnnotation: at.Format(time.RFC3339)}
+	}
+
+	assert.False(t, IsRevokedByWatermark(token))
+	assert.False(t, IsRevokedByWatermark(token, nil, map[string]string{}))
+	assert.False(t, IsRevokedByWatermark(token, watermark(created)))
+	assert.False(t, IsRevokedByWatermark(token, map[string]string{NotBeforeAnnotation: "invalid"}))
+	assert.True(t, IsRevokedByWatermark(token, watermark(created.Add(time.Second))))
+	assert.True(t, IsRevokedByWatermark(token, nil, watermark(created.Add(time.Hour))))
+}
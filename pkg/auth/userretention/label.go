@@ -14,6 +14,7 @@
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/client-go/util/retry"
+	"k8s.io/utils/pointer"
 )
 
 // UserLabeler sets user retention labels based on user attributes and settings.
@@ -32,7 +33,7 @@ func NewUserLabeler(ctx context.Context, wContext *wrangler.Context) *UserLabele
 		userCache:          wContext.Mgmt.User().Cache(),
 		users:              wContext.Mgmt.User(),
 		userAttributeCache: wContext.Mgmt.UserAttribute().Cache(),
-		readSettings:       readSettings,
+		readSettings:       withPolicies(readSettings, wContext.Mgmt.UserRetentionPolicy().Cache()),
 	}
 }
 
@@ -173,31 +174,34 @@ func setLabels(settings settings, user *mgmtv3.User, attribs *mgmtv3.UserAttribu
 		user.Labels = map[string]string{}
 	}
 
+	settings = settings.forUser(user, attribs)
+
 	lastLogin := lastLoginTime(settings, attribs)
 	updated := ensureLabel(lastLogin, LastLoginLabelKey, user)
 
 	var deleteAfterTime, disableAfterTime time.Time
 
-	if settings.ShouldDelete() && !user.IsDefaultAdmin() && !lastLogin.IsZero() {
-		if attribs.DeleteAfter != nil {
-			if userDeleteAfter := attribs.DeleteAfter.Duration; userDeleteAfter > 0 {
-				deleteAfterTime = lastLogin.Add(userDeleteAfter) // User-specific override.
-			}
-		} else {
-			deleteAfterTime = lastLogin.Add(settings.deleteAfter)
+	if !user.IsDefaultAdmin() && !lastLogin.IsZero() {
+		deleteAfterTime, disableAfterTime = retentionTimes(settings, lastLogin, attribs)
+		if !settings.ShouldDelete() {
+			deleteAfterTime = time.Time{}
+		}
+		if !settings.ShouldDisable() {
+			disableAfterTime = time.Time{}
 		}
 	}
-	updated = ensureLabel(deleteAfterTime, DeleteAfterLabelKey, user) || updated
 
-	if settings.ShouldDisable() && !user.IsDefaultAdmin() && !lastLogin.IsZero() {
-		if attribs.DisableAfter != nil {
-			if userDisableAfter := attribs.DisableAfter.Duration; userDisableAfter > 0 {
-				disableAfterTime = lastLogin.Add(userDisableAfter) // User-specific override.
+	if settings.deleteAfterDisable > 0 && !user.IsDefaultAdmin() && !pointer.BoolDeref(user.Enabled, true) {
+		if disabledAt := disabledAtTime(user); !disabledAt.IsZero() {
+			// The user is deleted after being disabled, unless it's deleted for being inactive first.
+			if afterDisable := disabledAt.Add(settings.deleteAfterDisable); deleteAfterTime.IsZero() || afterDisable.Before(deleteAfterTime) {
+				deleteAfterTime = afterDisable
 			}
-		} else {
-			disableAfterTime = lastLogin.Add(settings.disableAfter)
 		}
 	}
+
+	updated = ensureLabel(deleteAfterTime, DeleteAfterLabelKey, user) || updated
+
 	return ensureLabel(disableAfterTime, DisableAfterLabelKey, user) || updated
 }
 
@@ -0,0 +1,169 @@
+package userretention
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	mgmtcontrollers "github.com/rancher/rancher/pkg/generated/controllers/management.cattle.io/v3"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/utils/pointer"
+)
+
+const localProvider = "local"
+
+// withPolicies wraps readSettings to also read the user retention policies.
+func withPolicies(readSettings func() (settings, error), policyCache mgmtcontrollers.UserRetentionPolicyCache) func() (settings, error) {
+	return func() (settings, error) {
+		parsed, err := readSettings()
+		if err != nil {
+			return settings{}, err
+		}
+
+		policies, err := policyCache.List(labels.Everything())
+		if err != nil {
+			return settings{}, fmt.Errorf("error listing user retention policies: %w", err)
+		}
+
+		parsed.policies = sortPolicies(policies)
+
+		return parsed, nil
+	}
+}
+
+// sortPolicies returns the policies in the order they are evaluated: by descending priority, then by name.
+func sortPolicies(policies []*v3.UserRetentionPolicy) []*v3.UserRetentionPolicy {
+	sorted := slices.Clone(policies) // Don't reorder the cache's slice.
+	slices.SortFunc(sorted, func(a, b *v3.UserRetentionPolicy) int {
+		if c := cmp.Compare(b.Spec.Priority, a.Spec.Priority); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return sorted
+}
+
+// forUser returns the settings that apply to the user,
+// with the durations of the first user retention policy selecting the user, if any.
+func (s settings) forUser(user *v3.User, attribs *v3.UserAttribute) settings {
+	userSettings := s
+	userSettings.policies = nil
+
+	for _, policy := range s.policies {
+		if !selectsUser(policy, user, attribs) {
+			continue
+		}
+
+		userSettings.policy = policy.Name
+		if policy.Spec.DisableAfter != nil {
+			userSettings.disableAfter = policy.Spec.DisableAfter.Duration
+		}
+		if policy.Spec.DeleteAfter != nil {
+			userSettings.deleteAfter = policy.Spec.DeleteAfter.Duration
+		}
+		if policy.Spec.DeleteAfterDisable != nil {
+			userSettings.deleteAfterDisable = policy.Spec.DeleteAfterDisable.Duration
+		}
+
+		break
+	}
+
+	return userSettings
+}
+
+// selectsUser returns true if the user matches both the auth provider and the group principal selectors of the policy.
+func selectsUser(policy *v3.UserRetentionPolicy, user *v3.User, attribs *v3.UserAttribute) bool {
+	if len(policy.Spec.AuthProviders) > 0 &&
+		!slices.ContainsFunc(authProviders(user), func(provider string) bool {
+			return slices.Contains(policy.Spec.AuthProviders, provider)
+		}) {
+		return false
+	}
+
+	if len(policy.Spec.GroupPrincipals) > 0 {
+		if attribs == nil {
+			return false
+		}
+
+		for _, principals := range attribs.GroupPrincipals {
+			for _, principal := range principals.Items {
+				if slices.Contains(policy.Spec.GroupPrincipals, principal.Name) {
+					return true
+				}
+			}
+		}
+
+		return false
+	}
+
+	return true
+}
+
+// authProviders returns the auth providers of the user's principals e.g. "github" for "github_user://1234".
+// A user with no principal of an external auth provider is a local user.
+func authProviders(user *v3.User) []string {
+	var providers []string
+	for _, principalID := range user.PrincipalIDs {
+		scheme, _, found := strings.Cut(principalID, "://")
+		if !found {
+			continue
+		}
+
+		provider := strings.TrimSuffix(scheme, "_user")
+		if provider != localProvider && !slices.Contains(providers, provider) {
+			providers = append(providers, provider)
+		}
+	}
+
+	if len(providers) == 0 {
+		return []string{localProvider}
+	}
+
+	return providers
+}
+
+// disabledAtTime returns the time the user was disabled at, if known.
+func disabledAtTime(user *v3.User) time.Time {
+	value := user.Annotations[v3.UserDisabledAtAnnotation]
+	if value == "" {
+		return time.Time{}
+	}
+
+	disabledAt, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}
+	}
+
+	return disabledAt
+}
+
+// ensureDisabledAt records the time the user retention process disabled the user, if the user is to be deleted
+// after being disabled, and removes the record once the user is enabled again. Users disabled otherwise, such as by
+// an admin, aren't recorded so that they aren't deleted for having been disabled.
+// It returns true if the user was changed.
+func ensureDisabledAt(settings settings, user *v3.User, disabled bool, now time.Time) bool {
+	_, ok := user.Annotations[v3.UserDisabledAtAnnotation]
+
+	if pointer.BoolDeref(user.Enabled, true) {
+		if ok {
+			delete(user.Annotations, v3.UserDisabledAtAnnotation)
+			return true
+		}
+		return false
+	}
+
+	if ok || !disabled || settings.deleteAfterDisable <= 0 {
+		return false
+	}
+
+	if user.Annotations == nil {
+		user.Annotations = map[string]string{}
+	}
+	user.Annotations[v3.UserDisabledAtAnnotation] = now.UTC().Format(time.RFC3339)
+
+	return true
+}
@@ -0,0 +1,329 @@
+package userretention
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/wrangler/v3/pkg/generic/fake"
+	"go.uber.org/mock/gomock"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/pointer"
+)
+
+func duration(d time.Duration) *metav1.Duration {
+	return &metav1.Duration{Duration: d}
+}
+
+func TestAuthProviders(t *testing.T) {
+	tests := []struct {
+		desc         string
+		principalIDs []string
+		want         []string
+	}{
+		{
+			desc:         "local user",
+			principalIDs: []string{"local://u-cx7gc"},
+			want:         []string{"local"},
+		},
+		{
+			desc: "no principals",
+			want: []string{"local"},
+		},
+		{
+			desc:         "external user",
+			principalIDs: []string{"local://u-ckrl4grxg5", "github_user://1234", "okta_user://jdoe"},
+			want:         []string{"github", "okta"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			user := &v3.User{PrincipalIDs: test.principalIDs}
+			if want, got := test.want, authProviders(user); !reflect.DeepEqual(want, got) {
+				t.Errorf("Expected auth providers %v got %v", want, got)
+			}
+		})
+	}
+}
+
+func TestSettingsForUser(t *testing.T) {
+	contractors := &v3.UserRetentionPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "contractors"},
+		Spec: v3.UserRetentionPolicySpec{
+			Priority:        10,
+			GroupPrincipals: []string{"okta_group://contractors"},
+			DisableAfter:    duration(14 * 24 * time.Hour),
+		},
+	}
+	local := &v3.UserRetentionPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "local"},
+		Spec: v3.UserRetentionPolicySpec{
+			AuthProviders: []string{"local"},
+			DeleteAfter:   duration(0),
+		},
+	}
+	saml := &v3.UserRetentionPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "saml"},
+		Spec: v3.UserRetentionPolicySpec{
+			AuthProviders:      []string{"okta", "keycloak"},
+			DeleteAfterDisable: duration(180 * 24 * time.Hour),
+		},
+	}
+	global := settings{
+		disableAfter: 30 * 24 * time.Hour,
+		deleteAfter:  90 * 24 * time.Hour,
+		policies:     sortPolicies([]*v3.UserRetentionPolicy{saml, local, contractors}),
+	}
+
+	tests := []struct {
+		desc    string
+		user    *v3.User
+		attribs *v3.UserAttribute
+		want    settings
+	}{
+		{
+			desc:    "local user",
+			user:    &v3.User{PrincipalIDs: []string{"local://u-cx7gc"}},
+			attribs: &v3.UserAttribute{},
+			want: settings{
+				disableAfter: 30 * 24 * time.Hour,
+				policy:       "local",
+			},
+		},
+		{
+			desc:    "saml user",
+			user:    &v3.User{PrincipalIDs: []string{"local://u-ckrl4grxg5", "okta_user://jdoe"}},
+			attribs: &v3.UserAttribute{},
+			want: settings{
+				disableAfter:       30 * 24 * time.Hour,
+				deleteAfter:        90 * 24 * time.Hour,
+				deleteAfterDisable: 180 * 24 * time.Hour,
+				policy:             "saml",
+			},
+		},
+		{
+			desc: "contractor takes precedence",
+			user: &v3.User{PrincipalIDs: []string{"local://u-ckrl4grxg5", "okta_user://jdoe"}},
+			attribs: &v3.UserAttribute{
+				GroupPrincipals: map[string]v3.Principals{
+					"okta": {Items: []v3.Principal{{ObjectMeta: metav1.ObjectMeta{Name: "okta_group://contractors"}}}},
+				},
+			},
+			want: settings{
+				disableAfter: 14 * 24 * time.Hour,
+				deleteAfter:  90 * 24 * time.Hour,
+				policy:       "contractors",
+			},
+		},
+		{
+			desc:    "no policy applies",
+			user:    &v3.User{PrincipalIDs: []string{"github_user://1234"}},
+			attribs: &v3.UserAttribute{},
+			want: settings{
+				disableAfter: 30 * 24 * time.Hour,
+				deleteAfter:  90 * 24 * time.Hour,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			if want, got := test.want, global.forUser(test.user, test.attribs); !reflect.DeepEqual(want, got) {
+				t.Errorf("Expected settings %+v got %+v", want, got)
+			}
+		})
+	}
+}
+
+func TestSettingsIsEnabled(t *testing.T) {
+	if (&settings{}).IsEnabled() {
+		t.Error("Expected retention to be disabled")
+	}
+
+	neverDelete := &v3.UserRetentionPolicy{Spec: v3.UserRetentionPolicySpec{DeleteAfter: duration(0)}}
+	if (&settings{policies: []*v3.UserRetentionPolicy{neverDelete}}).IsEnabled() {
+		t.Error("Expected retention to be disabled")
+	}
+
+	disable := &v3.UserRetentionPolicy{Spec: v3.UserRetentionPolicySpec{DisableAfter: duration(time.Hour)}}
+	if !(&settings{policies: []*v3.UserRetentionPolicy{neverDelete, disable}}).IsEnabled() {
+		t.Error("Expected retention to be enabled")
+	}
+}
+
+func TestEvaluateDeleteAfterDisable(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	settings := settings{deleteAfterDisable: 24 * time.Hour}
+	attribs := &v3.UserAttribute{LastLogin: &metav1.Time{Time: now.Add(-time.Hour)}}
+
+	disabledAt := func(d time.Duration) *v3.User {
+		return &v3.User{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{v3.UserDisabledAtAnnotation: now.Add(d).Format(time.RFC3339)},
+			},
+			Enabled: pointer.Bool(false),
+		}
+	}
+
+	if want, got := ActionDelete, evaluate(settings, disabledAt(-25*time.Hour), attribs, now); want != got {
+		t.Errorf("Expected action %q got %q", want, got)
+	}
+
+	if want, got := ActionNone, evaluate(settings, disabledAt(-23*time.Hour), attribs, now); want != got {
+		t.Errorf("Expected action %q got %q", want, got)
+	}
+
+	if want, got := ActionNone, evaluate(settings, &v3.User{Enabled: pointer.Bool(false)}, attribs, now); want != got {
+		t.Errorf("Expected action %q got %q", want, got)
+	}
+}
+
+func TestEnsureDisabledAt(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	userSettings := settings{deleteAfterDisable: 24 * time.Hour}
+
+	user := &v3.User{Enabled: pointer.Bool(false)}
+	if !ensureDisabledAt(userSettings, user, true, now) {
+		t.Error("Expected the user to be updated")
+	}
+	if want, got := now.UTC().Format(time.RFC3339), user.Annotations[v3.UserDisabledAtAnnotation]; want != got {
+		t.Errorf("Expected disabled at %s got %s", want, got)
+	}
+
+	if ensureDisabledAt(userSettings, user, false, now.Add(time.Hour)) {
+		t.Error("Expected the user not to be updated")
+	}
+
+	user.Enabled = pointer.Bool(true)
+	if !ensureDisabledAt(userSettings, user, false, now) {
+		t.Error("Expected the user to be updated")
+	}
+	if _, ok := user.Annotations[v3.UserDisabledAtAnnotation]; ok {
+		t.Error("Expected disabled at annotation to be removed")
+	}
+
+	// Users aren't deleted after being disabled, so there is no need to record it.
+	user.Enabled = pointer.Bool(false)
+	if ensureDisabledAt(settings{deleteAfter: time.Hour}, user, true, now) {
+		t.Error("Expected the user not to be updated")
+	}
+}
+
+func TestEnsureDisabledAtManuallyDisabled(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	userSettings := settings{deleteAfterDisable: 24 * time.Hour}
+	attribs := &v3.UserAttribute{LastLogin: &metav1.Time{Time: now.Add(-time.Hour)}}
+
+	// The user was disabled by an admin, not by the user retention process.
+	user := &v3.User{Enabled: pointer.Bool(false)}
+	if ensureDisabledAt(userSettings, user, false, now) {
+		t.Error("Expected the user not to be updated")
+	}
+	if _, ok := user.Annotations[v3.UserDisabledAtAnnotation]; ok {
+		t.Error("Expected no disabled at annotation")
+	}
+
+	if want, got := ActionNone, evaluate(userSettings, user, attribs, now.Add(25*time.Hour)); want != got {
+		t.Errorf("Expected action %q got %q", want, got)
+	}
+}
+
+func TestRetentionReport(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+
+	users := []*v3.User{
+		{
+			ObjectMeta:   metav1.ObjectMeta{Name: "user-phs88"},
+			Username:     "admin",
+			PrincipalIDs: []string{"local://user-phs88"},
+		},
+		{
+			ObjectMeta:   metav1.ObjectMeta{Name: "u-local"},
+			PrincipalIDs: []string{"local://u-local"},
+			Enabled:      pointer.Bool(true),
+		},
+		{
+			ObjectMeta:   metav1.ObjectMeta{Name: "u-contractor"},
+			PrincipalIDs: []string{"local://u-contractor", "okta_user://contractor"},
+			Enabled:      pointer.Bool(true),
+		},
+		{
+			ObjectMeta:   metav1.ObjectMeta{Name: "u-employee"},
+			PrincipalIDs: []string{"local://u-employee", "okta_user://employee"},
+			Enabled:      pointer.Bool(true),
+		},
+		{
+			ObjectMeta:   metav1.ObjectMeta{Name: "u-new"},
+			PrincipalIDs: []string{"local://u-new"},
+			Enabled:      pointer.Bool(true),
+		},
+	}
+	lastLogin := now.Add(-20 * 24 * time.Hour)
+	userAttributes := map[string]*v3.UserAttribute{
+		"u-local":    {LastLogin: &metav1.Time{Time: now.Add(-100 * 24 * time.Hour)}},
+		"u-employee": {LastLogin: &metav1.Time{Time: lastLogin}},
+		"u-contractor": {
+			LastLogin: &metav1.Time{Time: lastLogin},
+			GroupPrincipals: map[string]v3.Principals{
+				"okta": {Items: []v3.Principal{{ObjectMeta: metav1.ObjectMeta{Name: "okta_group://contractors"}}}},
+			},
+		},
+	}
+
+	ctrl := gomock.NewController(t)
+	userCache := fake.NewMockNonNamespacedCacheInterface[*v3.User](ctrl)
+	userCache.EXPECT().List(labels.Everything()).Return(users, nil)
+	userAttributeCache := fake.NewMockNonNamespacedCacheInterface[*v3.UserAttribute](ctrl)
+	userAttributeCache.EXPECT().Get(gomock.Any()).DoAndReturn(func(name string) (*v3.UserAttribute, error) {
+		if attribs, ok := userAttributes[name]; ok {
+			return attribs, nil
+		}
+		return nil, apierrors.NewNotFound(schema.GroupResource{}, name)
+	}).AnyTimes()
+	policyCache := fake.NewMockNonNamespacedCacheInterface[*v3.UserRetentionPolicy](ctrl)
+	policyCache.EXPECT().List(labels.Everything()).Return([]*v3.UserRetentionPolicy{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "contractors"},
+			Spec: v3.UserRetentionPolicySpec{
+				GroupPrincipals: []string{"okta_group://contractors"},
+				DisableAfter:    duration(14 * 24 * time.Hour),
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "local"},
+			Spec: v3.UserRetentionPolicySpec{
+				AuthProviders: []string{"local"},
+				DeleteAfter:   duration(0),
+			},
+		},
+	}, nil)
+
+	r := Retention{
+		userCache:          userCache,
+		userAttributeCache: userAttributeCache,
+		readSettings: withPolicies(func() (settings, error) {
+			return settings{
+				disableAfter: 30 * 24 * time.Hour,
+				deleteAfter:  90 * 24 * time.Hour,
+			}, nil
+		}, policyCache),
+	}
+
+	entries, err := r.Report(now)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []ReportEntry{
+		{User: users[2], Action: ActionDisable, Policy: "contractors", LastLogin: lastLogin},
+		{User: users[1], Action: ActionDisable, Policy: "local", LastLogin: now.Add(-100 * 24 * time.Hour)},
+	}
+	if !reflect.DeepEqual(want, entries) {
+		t.Errorf("Expected report %+v got %+v", want, entries)
+	}
+}
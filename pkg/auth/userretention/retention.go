@@ -3,6 +3,8 @@
 import (
 	"context"
 	"fmt"
+	"slices"
+	"strings"
 	"time"
 
 	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
@@ -22,6 +24,15 @@
 	DeleteAfterLabelKey  = "cattle.io/delete-after"
 )
 
+// Action is what the user retention process does with a user.
+type Action string
+
+const (
+	ActionNone    Action = ""
+	ActionDisable Action = "Disable"
+	ActionDelete  Action = "Delete"
+)
+
 // Retention is the user retention process that disables or deletes inactive users
 // based on the last time the user was seen (logged in) that is stored as a user attribute.
 // Note: Disabling and deleting are independent of each other and are driven by the
@@ -31,6 +42,8 @@
 // - only disable users (disableAfter > 0 && deleteAfter == 0)
 // - progressively disable and delete users (0 < disableAfter < deleteAfter)
 // - only delete users (disableAfter == 0 && deleteAfter > 0 or 0 < deleteAfter < disableAfter)
+// UserRetentionPolicies override the settings for the users they select, and can also
+// delete users some time after they were disabled.
 type Retention struct {
 	userAttributeCache mgmtcontrollers.UserAttributeCache
 	userCache          mgmtcontrollers.UserCache
@@ -44,7 +57,7 @@ func New(wContext *wrangler.Context) *Retention {
 		userCache:          wContext.Mgmt.User().Cache(),
 		users:              wContext.Mgmt.User(),
 		userAttributeCache: wContext.Mgmt.UserAttribute().Cache(),
-		readSettings:       readSettings,
+		readSettings:       withPolicies(readSettings, wContext.Mgmt.UserRetentionPolicy().Cache()),
 	}
 }
 
@@ -62,14 +75,14 @@ func (r *Retention) Run(ctx context.Context) error {
 		return fmt.Errorf("error reading settings: %w, retention is disabled", err)
 	}
 
-	if !settings.ShouldDisable() && !settings.ShouldDelete() {
-		logrus.Info("userretention: nothing to do, neither DisableInactiveUserAfter nor DeleteInactiveUserAfter is set")
+	if !settings.IsEnabled() {
+		logrus.Info("userretention: nothing to do, neither DisableInactiveUserAfter nor DeleteInactiveUserAfter is set, and no user retention policy disables or deletes users")
 		return nil
 	}
 
 	logrus.Infof(
-		"userretention: started (disable-inactive-user-after %s, delete-inactive-user-after %s, user-last-login-default %s, user-retention-dry-run %t)",
-		settings.disableAfter, settings.deleteAfter, settings.FormatDefaultLastLogin(), settings.dryRun,
+		"userretention: started (disable-inactive-user-after %s, delete-inactive-user-after %s, user-last-login-default %s, user-retention-dry-run %t, user retention policies %d)",
+		settings.disableAfter, settings.deleteAfter, settings.FormatDefaultLastLogin(), settings.dryRun, len(settings.policies),
 	)
 
 	users, err := r.userCache.List(labels.Everything())
@@ -117,63 +130,40 @@ func (r *Retention) Run(ctx context.Context) error {
 			continue
 		}
 
-		var (
-			userDeleteAfter, userDisableAfter time.Duration
-			disableUser                       bool
-		)
-
 		lastLogin := lastLoginTime(settings, attribs)
-		if !lastLogin.IsZero() {
-			deleteAfterTime := lastLogin.Add(settings.deleteAfter)
-			if attribs.DeleteAfter != nil { // Apply user-specific override.
-				if userDeleteAfter = attribs.DeleteAfter.Duration; userDeleteAfter <= 0 {
-					deleteAfterTime = time.Time{} // The user shouldn't be considered for deletion.
-				} else {
-					deleteAfterTime = lastLogin.Add(userDeleteAfter)
-				}
-			}
-			deleteAfterTime = deleteAfterTime.Truncate(time.Second)
-
-			disableAfterTime := lastLogin.Add(settings.disableAfter)
-			if attribs.DisableAfter != nil { // Apply user-specific override.
-				if userDisableAfter = attribs.DisableAfter.Duration; userDisableAfter <= 0 {
-					disableAfterTime = time.Time{} // The user shouldn't be considered for being disabled.
-				} else {
-					disableAfterTime = lastLogin.Add(userDisableAfter)
-				}
-			}
-			disableAfterTime = disableAfterTime.Truncate(time.Second)
+		if !lastLogin.IsZero() &&
+			attribs.DeleteAfter != nil && attribs.DeleteAfter.Duration == 0 &&
+			attribs.DisableAfter != nil && attribs.DisableAfter.Duration == 0 {
+			skipped++ // This is to keep the counter updated.
+		}
 
-			if attribs.DeleteAfter != nil && userDeleteAfter == 0 &&
-				attribs.DisableAfter != nil && userDisableAfter == 0 {
-				skipped++ // This is to keep the counter updated.
-			}
+		userSettings := settings.forUser(user, attribs)
+		if userSettings.policy != "" {
+			logrus.Debugf("userretention: user retention policy %s applies to user %s", userSettings.policy, user.Name)
+		}
 
-			if settings.ShouldDelete() && !deleteAfterTime.IsZero() &&
-				now.After(deleteAfterTime) {
-				logrus.Infof("userretention: deleting user %s", user.Name)
+		var disableUser bool
 
-				if !settings.dryRun {
-					err := r.users.Delete(user.Name, &metav1.DeleteOptions{})
-					if err != nil && !apierrors.IsNotFound(err) && !apierrors.IsGone(err) {
-						logrus.Errorf("userretention: error deleting user %s: %v", user.Name, err)
-						errCount++
-						continue
-					}
-				}
-
-				deleted++
-				continue
+		switch evaluate(userSettings, user, attribs, now) {
+		case ActionDelete:
+			logrus.Infof("userretention: deleting user %s", user.Name)
 
+			if !settings.dryRun {
+				err := r.users.Delete(user.Name, &metav1.DeleteOptions{})
+				if err != nil && !apierrors.IsNotFound(err) && !apierrors.IsGone(err) {
+					logrus.Errorf("userretention: error deleting user %s: %v", user.Name, err)
+					errCount++
+					continue
+				}
 			}
 
-			if settings.ShouldDisable() && !disableAfterTime.IsZero() &&
-				now.After(disableAfterTime) && pointer.BoolDeref(user.Enabled, true) {
-				logrus.Infof("userretention: disabling user %s", user.Name)
-				// Flag the needed update but don't apply it as we may need to update retention labels too.
-				disableUser = true
-				disabled++
-			}
+			deleted++
+			continue
+		case ActionDisable:
+			logrus.Infof("userretention: disabling user %s", user.Name)
+			// Flag the needed update but don't apply it as we may need to update retention labels too.
+			disableUser = true
+			disabled++
 		}
 
 		var userGetTry int
@@ -202,11 +192,14 @@ func (r *Retention) Run(ctx context.Context) error {
 				user.Enabled = pointer.Bool(false)
 			}
 
+			// Record when the user was disabled if it's to be deleted after being disabled.
+			disabledAtUpdated := ensureDisabledAt(userSettings, user, disableUser, now)
+
 			// Update the retention labels if necessary.
 			labelsUpdated := setLabels(settings, user, attribs)
 
 			// No user updates; return early.
-			if !labelsUpdated && !disableUser {
+			if !labelsUpdated && !disableUser && !disabledAtUpdated {
 				return nil
 			}
 
@@ -231,6 +224,122 @@ func (r *Retention) Run(ctx context.Context) error {
 	return nil
 }
 
+// ReportEntry is a user the user retention process would disable or delete.
+type ReportEntry struct {
+	User      *v3.User
+	Action    Action
+	Policy    string
+	LastLogin time.Time
+}
+
+// Report returns the users the user retention process would disable or delete at the given time,
+// based on the current state of the users. It doesn't change any user.
+func (r *Retention) Report(at time.Time) ([]ReportEntry, error) {
+	settings, err := r.readSettings()
+	if err != nil {
+		return nil, fmt.Errorf("error reading settings: %w", err)
+	}
+
+	if !settings.IsEnabled() {
+		return nil, nil
+	}
+
+	users, err := r.userCache.List(labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("error listing users: %w", err)
+	}
+
+	var entries []ReportEntry
+	for _, user := range users {
+		if !isSubjectToRetention(user) {
+			continue
+		}
+
+		attribs, err := r.userAttributeCache.Get(user.Name)
+		if err != nil && !apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("error getting user attributes for %s: %w", user.Name, err)
+		}
+
+		if attribs == nil {
+			continue
+		}
+
+		userSettings := settings.forUser(user, attribs)
+		action := evaluate(userSettings, user, attribs, at)
+		if action == ActionNone {
+			continue
+		}
+
+		entries = append(entries, ReportEntry{
+			User:      user,
+			Action:    action,
+			Policy:    userSettings.policy,
+			LastLogin: lastLoginTime(settings, attribs),
+		})
+	}
+
+	slices.SortFunc(entries, func(a, b ReportEntry) int {
+		return strings.Compare(a.User.Name, b.User.Name)
+	})
+
+	return entries, nil
+}
+
+// evaluate returns what the user retention process should do with the user at the given time,
+// given the settings that apply to the user.
+func evaluate(settings settings, user *v3.User, attribs *v3.UserAttribute, now time.Time) Action {
+	enabled := pointer.BoolDeref(user.Enabled, true)
+
+	if settings.deleteAfterDisable > 0 && !enabled {
+		if disabledAt := disabledAtTime(user); !disabledAt.IsZero() && now.After(disabledAt.Add(settings.deleteAfterDisable)) {
+			return ActionDelete
+		}
+	}
+
+	lastLogin := lastLoginTime(settings, attribs)
+	if lastLogin.IsZero() {
+		return ActionNone
+	}
+
+	deleteAfterTime, disableAfterTime := retentionTimes(settings, lastLogin, attribs)
+
+	if settings.ShouldDelete() && !deleteAfterTime.IsZero() &&
+		now.After(deleteAfterTime.Truncate(time.Second)) {
+		return ActionDelete
+	}
+
+	if settings.ShouldDisable() && !disableAfterTime.IsZero() &&
+		now.After(disableAfterTime.Truncate(time.Second)) && enabled {
+		return ActionDisable
+	}
+
+	return ActionNone
+}
+
+// retentionTimes returns the times after which the user should be deleted and disabled,
+// applying the user-specific overrides. A zero time means never.
+func retentionTimes(settings settings, lastLogin time.Time, attribs *v3.UserAttribute) (deleteAfterTime, disableAfterTime time.Time) {
+	deleteAfterTime = lastLogin.Add(settings.deleteAfter)
+	if attribs.DeleteAfter != nil { // Apply user-specific override.
+		if userDeleteAfter := attribs.DeleteAfter.Duration; userDeleteAfter <= 0 {
+			deleteAfterTime = time.Time{} // The user shouldn't be considered for deletion.
+		} else {
+			deleteAfterTime = lastLogin.Add(userDeleteAfter)
+		}
+	}
+
+	disableAfterTime = lastLogin.Add(settings.disableAfter)
+	if attribs.DisableAfter != nil { // Apply user-specific override.
+		if userDisableAfter := attribs.DisableAfter.Duration; userDisableAfter <= 0 {
+			disableAfterTime = time.Time{} // The user shouldn't be considered for being disabled.
+		} else {
+			disableAfterTime = lastLogin.Add(userDisableAfter)
+		}
+	}
+
+	return deleteAfterTime, disableAfterTime
+}
+
 func isSubjectToRetention(user *v3.User) bool {
 	return !user.IsDefaultAdmin() && !user.IsSystem()
 }
@@ -560,3 +560,100 @@ func TestRetentionRunContextCancelled(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestRetentionRunDeleteAfterDisable(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+
+	userAttributes := map[string]*v3.UserAttribute{
+		"u-inactive": {
+			LastLogin: &metav1.Time{Time: now.Add(-2 * time.Hour)},
+		},
+		"u-manual": {
+			LastLogin: &metav1.Time{Time: now.Add(-time.Minute)},
+		},
+	}
+
+	users := map[string]*v3.User{
+		"u-inactive": {
+			ObjectMeta:   metav1.ObjectMeta{Name: "u-inactive"},
+			PrincipalIDs: []string{"local://u-inactive"},
+			Enabled:      pointer.Bool(true),
+		},
+		"u-manual": { // Disabled by an admin.
+			ObjectMeta:   metav1.ObjectMeta{Name: "u-manual"},
+			PrincipalIDs: []string{"local://u-manual"},
+			Enabled:      pointer.Bool(false),
+		},
+	}
+	var deleted []string
+
+	ctrl := gomock.NewController(t)
+
+	usersCacheClient := fake.NewMockNonNamespacedCacheInterface[*v3.User](ctrl)
+	usersCacheClient.EXPECT().List(gomock.Any()).AnyTimes().DoAndReturn(func(selector labels.Selector) ([]*v3.User, error) {
+		result := make([]*v3.User, 0, len(users))
+		for _, user := range users {
+			result = append(result, user.DeepCopy())
+		}
+		return result, nil
+	})
+
+	usersClient := fake.NewMockNonNamespacedControllerInterface[*v3.User, *v3.UserList](ctrl)
+	usersClient.EXPECT().Update(gomock.Any()).AnyTimes().DoAndReturn(func(user *v3.User) (*v3.User, error) {
+		users[user.Name] = user.DeepCopy()
+		return user, nil
+	})
+	usersClient.EXPECT().Delete(gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(func(name string, options *metav1.DeleteOptions) error {
+		deleted = append(deleted, name)
+		delete(users, name)
+		return nil
+	})
+
+	userAttributeCacheClient := fake.NewMockNonNamespacedCacheInterface[*v3.UserAttribute](ctrl)
+	userAttributeCacheClient.EXPECT().Get(gomock.Any()).AnyTimes().DoAndReturn(func(name string) (*v3.UserAttribute, error) {
+		if attr, ok := userAttributes[name]; ok {
+			return attr, nil
+		}
+		return nil, apierrors.NewNotFound(schema.GroupResource{}, name)
+	})
+
+	retention := Retention{
+		userAttributeCache: userAttributeCacheClient,
+		userCache:          usersCacheClient,
+		users:              usersClient,
+		readSettings: func() (settings, error) {
+			return settings{
+				disableAfter:       time.Hour,
+				deleteAfterDisable: 24 * time.Hour,
+			}, nil
+		},
+	}
+
+	if err := retention.Run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if want, got := false, pointer.BoolDeref(users["u-inactive"].Enabled, true); want != got {
+		t.Errorf("Expected enabled %t got %t", want, got)
+	}
+	if _, ok := users["u-inactive"].Annotations[v3.UserDisabledAtAnnotation]; !ok {
+		t.Error("Expected the user disabled by retention to have the disabled at annotation")
+	}
+	if _, ok := users["u-manual"].Annotations[v3.UserDisabledAtAnnotation]; ok {
+		t.Error("Expected the manually disabled user not to have the disabled at annotation")
+	}
+
+	// Both users have been disabled for longer than deleteAfterDisable.
+	users["u-inactive"].Annotations[v3.UserDisabledAtAnnotation] = now.Add(-25 * time.Hour).UTC().Format(time.RFC3339)
+
+	if err := retention.Run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if want, got := []string{"u-inactive"}, deleted; !reflect.DeepEqual(want, got) {
+		t.Errorf("Expected deleted\n%v\ngot\n%v", want, got)
+	}
+	if _, ok := users["u-manual"]; !ok {
+		t.Error("Expected the manually disabled user not to be deleted")
+	}
+}
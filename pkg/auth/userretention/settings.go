@@ -5,7 +5,9 @@
 	"strings"
 	"time"
 
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
 	appsettings "github.com/rancher/rancher/pkg/settings"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // settings control user retention process.
@@ -14,6 +16,12 @@ type settings struct {
 	deleteAfter      time.Duration
 	defaultLastLogin time.Time
 	dryRun           bool
+	// deleteAfterDisable is only set by a user retention policy.
+	deleteAfterDisable time.Duration
+	// policy is the name of the user retention policy the settings were taken from, if any.
+	policy string
+	// policies are the user retention policies, sorted in the order they are evaluated.
+	policies []*v3.UserRetentionPolicy
 }
 
 // ShouldDisable returns true if the user retention process should disable users.
@@ -26,6 +34,28 @@ func (s *settings) ShouldDelete() bool {
 	return s.deleteAfter != 0
 }
 
+// IsEnabled returns true if the user retention process should disable or delete users,
+// either based on the settings or on a user retention policy.
+func (s *settings) IsEnabled() bool {
+	if s.ShouldDisable() || s.ShouldDelete() || s.deleteAfterDisable != 0 {
+		return true
+	}
+
+	for _, policy := range s.policies {
+		for _, duration := range []*metav1.Duration{
+			policy.Spec.DisableAfter,
+			policy.Spec.DeleteAfter,
+			policy.Spec.DeleteAfterDisable,
+		} {
+			if duration != nil && duration.Duration != 0 {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
 // FormatDefaultLastLogin returns formatted value of the default last login.
 func (s *settings) FormatDefaultLastLogin() string {
 	if s.defaultLastLogin.IsZero() {
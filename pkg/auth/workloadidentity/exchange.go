@@ -0,0 +1,485 @@
+// Package workloadidentity implements OAuth 2.0 Token Exchange (RFC 8693) for the workloads of downstream clusters.
+// Workloads exchange their projected ServiceAccount tokens for short-lived Rancher tokens, as declared by
+// WorkloadIdentityBindings.
+package workloadidentity
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"github.com/golang-jwt/jwt/v5"
+	ext "github.com/rancher/rancher/pkg/apis/ext.cattle.io/v1"
+	apiv3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/auth/creatorid"
+	"github.com/rancher/rancher/pkg/clustermanager"
+	exttokens "github.com/rancher/rancher/pkg/ext/stores/tokens"
+	mgmtv3 "github.com/rancher/rancher/pkg/generated/controllers/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/settings"
+	"github.com/rancher/rancher/pkg/types/config"
+	"github.com/rancher/steve/pkg/auth"
+	"github.com/sirupsen/logrus"
+	authzv1 "k8s.io/api/authorization/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	k8suser "k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizerfactory"
+	authv1 "k8s.io/client-go/kubernetes/typed/authorization/v1"
+	"k8s.io/client-go/rest"
+)
+
+const (
+	// GrantTypeTokenExchange is the grant type of token exchange requests.
+	GrantTypeTokenExchange = "urn:ietf:params:oauth:grant-type:token-exchange"
+	// TokenTypeJWT is the type of the ServiceAccount tokens workloads present.
+	TokenTypeJWT = "urn:ietf:params:oauth:token-type:jwt"
+	// TokenTypeAccessToken is the type of the issued Rancher tokens.
+	TokenTypeAccessToken = "urn:ietf:params:oauth:token-type:access_token"
+
+	// BindingLabel is the label of the tokens and users created for a WorkloadIdentityBinding, set to its name.
+	BindingLabel = "authn.management.cattle.io/workload-identity-binding"
+
+	// tokenKind is the kind of the issued tokens, also used as the provider of the groups of the system users
+	// created for bindings to a group.
+	tokenKind = "workloadidentity"
+	// defaultTokenTTL is the lifetime of issued tokens if the binding doesn't set one.
+	defaultTokenTTL = 15 * time.Minute
+	// maxRequestSize is the maximum size of a token exchange request.
+	maxRequestSize = 64 * 1024
+
+	serviceAccountPrefix     = "system:serviceaccount:"
+	groupUserPrincipalPrefix = "system://workloadidentity/"
+	cattleAuthenticatedGroup = "system:cattle:authenticated"
+)
+
+// Error codes of RFC 6749 section 5.2.
+const (
+	errInvalidRequest       = "invalid_request"
+	errUnsupportedGrantType = "unsupported_grant_type"
+	errServerError          = "server_error"
+)
+
+// signingAlgs are the algorithms Kubernetes signs ServiceAccount tokens with.
+var signingAlgs = []string{oidc.RS256, oidc.RS384, oidc.RS512, oidc.ES256, oidc.ES384, oidc.ES512, oidc.PS256, oidc.PS384, oidc.PS512}
+
+// tokenCreator abstracts ext.Token creation on behalf of a principal.
+type tokenCreator interface {
+	CreateForPrincipal(ctx context.Context, token *ext.Token, principal ext.TokenPrincipal, userInfo k8suser.Info) (*ext.Token, error)
+}
+
+// creatorVerifier returns the creator of bindings recorded at admission.
+type creatorVerifier interface {
+	Creator(ctx context.Context, obj metav1.Object) (string, error)
+}
+
+// tokenResponse is the successful response of a token exchange.
+type tokenResponse struct {
+	AccessToken     string `json:"access_token"`
+	IssuedTokenType string `json:"issued_token_type"`
+	TokenType       string `json:"token_type"`
+	ExpiresIn       int64  `json:"expires_in"`
+}
+
+// errorResponse is the error response of a token exchange.
+type errorResponse struct {
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description,omitempty"`
+}
+
+// Handler serves token exchange requests.
+type Handler struct {
+	bindingCache         mgmtv3.WorkloadIdentityBindingCache
+	userCache            mgmtv3.UserCache
+	userAttributeCache   mgmtv3.UserAttributeCache
+	users                mgmtv3.UserClient
+	creators             creatorVerifier
+	subjectAccessReviews authv1.SubjectAccessReviewInterface
+	keys                 keySource
+	ensureUserAttribute  func(userID, provider string, groupPrincipals []apiv3.Principal, userExtraInfo map[string][]string, loginTime ...time.Time) error
+	tokens               tokenCreator
+	defaultAudience      func() string
+	now                  func() time.Time
+}
+
+// NewHandler returns a token exchange handler.
+func NewHandler(ctx context.Context, scaledContext *config.ScaledContext) (*Handler, error) {
+	wranglerContext := scaledContext.Wrangler
+	secretLister := scaledContext.Core.Secrets("").Controller().Lister()
+
+	// Scoped tokens are only issued to users allowed to access their cluster.
+	cfg := authorizerfactory.DelegatingAuthorizerConfig{
+		SubjectAccessReviewClient: scaledContext.K8sClient.AuthorizationV1(),
+		AllowCacheTTL:             time.Second * time.Duration(settings.AuthorizationCacheTTLSeconds.GetInt()),
+		DenyCacheTTL:              time.Second * time.Duration(settings.AuthorizationDenyCacheTTLSeconds.GetInt()),
+		WebhookRetryBackoff:       &auth.WebhookBackoff,
+	}
+	authorizer, err := cfg.New()
+	if err != nil {
+		return nil, fmt.Errorf("creating authorizer: %w", err)
+	}
+
+	return &Handler{
+		bindingCache:         wranglerContext.Mgmt.WorkloadIdentityBinding().Cache(),
+		userCache:            wranglerContext.Mgmt.User().Cache(),
+		userAttributeCache:   wranglerContext.Mgmt.UserAttribute().Cache(),
+		users:                wranglerContext.Mgmt.User(),
+		creators:             creatorid.NewVerifier(scaledContext.K8sClient.AdmissionregistrationV1().ValidatingAdmissionPolicyBindings()),
+		subjectAccessReviews: scaledContext.K8sClient.AuthorizationV1().SubjectAccessReviews(),
+		keys: &clusterKeySource{
+			ctx:          ctx,
+			clusterCache: wranglerContext.Mgmt.Cluster().Cache(),
+			restConfig: func(cluster *apiv3.Cluster) (*rest.Config, error) {
+				return clustermanager.ToRESTConfig(cluster, scaledContext, secretLister, false)
+			},
+			now:      time.Now,
+			clusters: map[string]*clusterKeys{},
+		},
+		ensureUserAttribute: scaledContext.UserManager.UserAttributeCreateOrUpdate,
+		tokens:              exttokens.NewSystemFromWrangler(wranglerContext, authorizer),
+		defaultAudience:     settings.ServerURL.Get,
+		now:                 time.Now,
+	}, nil
+}
+
+// ServeHTTP exchanges the ServiceAccount token of a token exchange request for a Rancher token.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxRequestSize)
+	if err := r.ParseForm(); err != nil {
+		writeError(w, http.StatusBadRequest, errInvalidRequest, "malformed request")
+		return
+	}
+
+	if grantType := r.PostForm.Get("grant_type"); grantType != GrantTypeTokenExchange {
+		writeError(w, http.StatusBadRequest, errUnsupportedGrantType, fmt.Sprintf("grant_type must be %s", GrantTypeTokenExchange))
+		return
+	}
+	if tokenType := r.PostForm.Get("subject_token_type"); tokenType != TokenTypeJWT {
+		writeError(w, http.StatusBadRequest, errInvalidRequest, fmt.Sprintf("subject_token_type must be %s", TokenTypeJWT))
+		return
+	}
+	if tokenType := r.PostForm.Get("requested_token_type"); tokenType != "" && tokenType != TokenTypeAccessToken {
+		writeError(w, http.StatusBadRequest, errInvalidRequest, fmt.Sprintf("requested_token_type must be %s", TokenTypeAccessToken))
+		return
+	}
+	if r.PostForm.Get("actor_token") != "" {
+		writeError(w, http.StatusBadRequest, errInvalidRequest, "delegation is not supported")
+		return
+	}
+	subjectToken := r.PostForm.Get("subject_token")
+	if subjectToken == "" {
+		writeError(w, http.StatusBadRequest, errInvalidRequest, "subject_token is required")
+		return
+	}
+
+	binding, err := h.verify(r.Context(), subjectToken)
+	if err != nil {
+		// The reason is only logged, as the caller isn't authenticated yet.
+		logrus.Debugf("[workloadidentity] Rejecting subject token: %v", err)
+		writeError(w, http.StatusBadRequest, errInvalidRequest, "subject_token is invalid")
+		return
+	}
+
+	token, err := h.issue(r.Context(), binding)
+	if err != nil {
+		if apierrors.IsBadRequest(err) {
+			writeError(w, http.StatusBadRequest, errInvalidRequest, err.Error())
+			return
+		}
+		logrus.Errorf("[workloadidentity] Error issuing token for binding %s: %v", binding.Name, err)
+		writeError(w, http.StatusInternalServerError, errServerError, "")
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Pragma", "no-cache")
+	writeJSON(w, http.StatusOK, tokenResponse{
+		AccessToken:     token.Status.BearerToken,
+		IssuedTokenType: TokenTypeAccessToken,
+		TokenType:       "Bearer",
+		ExpiresIn:       token.Spec.TTL / 1000,
+	})
+}
+
+// verify returns the binding the subject token is valid for. The token must be a ServiceAccount token signed by the
+// cluster of a binding of its ServiceAccount, issued for the audience of the binding.
+func (h *Handler) verify(ctx context.Context, subjectToken string) (*apiv3.WorkloadIdentityBinding, error) {
+	// The claims are only used to find the bindings to verify the token with.
+	claims := jwt.RegisteredClaims{}
+	if _, _, err := jwt.NewParser().ParseUnverified(subjectToken, &claims); err != nil {
+		return nil, fmt.Errorf("parsing token: %w", err)
+	}
+	namespace, name, ok := strings.Cut(strings.TrimPrefix(claims.Subject, serviceAccountPrefix), ":")
+	if !ok || !strings.HasPrefix(claims.Subject, serviceAccountPrefix) {
+		return nil, fmt.Errorf("subject %s is not a ServiceAccount", claims.Subject)
+	}
+
+	bindings, err := h.bindingCache.List(labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("listing bindings: %w", err)
+	}
+	slices.SortFunc(bindings, func(a, b *apiv3.WorkloadIdentityBinding) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	var errs []error
+	for _, binding := range bindings {
+		if binding.DeletionTimestamp != nil ||
+			binding.Spec.ServiceAccountNamespace != namespace ||
+			binding.Spec.ServiceAccountName != name {
+			continue
+		}
+		if err := h.verifyForBinding(ctx, binding, subjectToken); err != nil {
+			errs = append(errs, fmt.Errorf("binding %s: %w", binding.Name, err))
+			continue
+		}
+		return binding, nil
+	}
+	if len(errs) == 0 {
+		return nil, fmt.Errorf("no binding for ServiceAccount %s/%s", namespace, name)
+	}
+	return nil, errors.Join(errs...)
+}
+
+// verifyForBinding verifies the subject token with the issuer and the signing keys of the cluster of the binding.
+func (h *Handler) verifyForBinding(ctx context.Context, binding *apiv3.WorkloadIdentityBinding, subjectToken string) error {
+	audience := binding.Spec.Audience
+	if audience == "" {
+		audience = h.defaultAudience()
+	}
+	if audience == "" {
+		return errors.New("no audience")
+	}
+
+	issuer, keySet, err := h.keys.Keys(binding.Spec.ClusterName)
+	if err != nil {
+		return err
+	}
+
+	verifier := oidc.NewVerifier(issuer, keySet, &oidc.Config{
+		ClientID:             audience,
+		SupportedSigningAlgs: signingAlgs,
+		Now:                  h.now,
+	})
+	token, err := verifier.Verify(ctx, subjectToken)
+	if err != nil {
+		return err
+	}
+
+	subject := serviceAccountPrefix + binding.Spec.ServiceAccountNamespace + ":" + binding.Spec.ServiceAccountName
+	if token.Subject != subject {
+		return fmt.Errorf("subject %s doesn't match", token.Subject)
+	}
+
+	return nil
+}
+
+// issue creates a token for the user the binding maps its ServiceAccount to.
+// The token is owned by the binding, so that it is deleted with it.
+func (h *Handler) issue(ctx context.Context, binding *apiv3.WorkloadIdentityBinding) (*ext.Token, error) {
+	user, err := h.bindingUser(ctx, binding)
+	if err != nil {
+		return nil, err
+	}
+
+	ttl := defaultTokenTTL
+	if binding.Spec.TokenTTLSeconds > 0 {
+		ttl = time.Duration(binding.Spec.TokenTTLSeconds) * time.Second
+	}
+
+	token := &ext.Token{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: map[string]string{
+				BindingLabel: binding.Name,
+			},
+			OwnerReferences: []metav1.OwnerReference{bindingOwnerReference(binding)},
+		},
+		Spec: ext.TokenSpec{
+			UserID:      user.Name,
+			Kind:        tokenKind,
+			ClusterName: binding.Spec.TokenClusterName,
+			TTL:         ttl.Milliseconds(),
+			Description: fmt.Sprintf("Workload identity token for ServiceAccount %s/%s of cluster %s",
+				binding.Spec.ServiceAccountNamespace, binding.Spec.ServiceAccountName, binding.Spec.ClusterName),
+		},
+	}
+
+	userInfo, err := h.userInfo(binding, user)
+	if err != nil {
+		return nil, err
+	}
+
+	// Issued tokens act as the local principal of the user, as there is no login to the auth provider of the user.
+	return h.tokens.CreateForPrincipal(ctx, token, ext.TokenPrincipal{
+		Name:          "local://" + user.Name,
+		DisplayName:   user.DisplayName,
+		LoginName:     user.Username,
+		PrincipalType: "user",
+		Me:            true,
+		Provider:      "local",
+	}, userInfo)
+}
+
+// userInfo returns the user the issued token acts as, with its groups, to authorize its access to the cluster the
+// token is scoped to.
+func (h *Handler) userInfo(binding *apiv3.WorkloadIdentityBinding, user *apiv3.User) (k8suser.Info, error) {
+	groups := []string{k8suser.AllAuthenticated, cattleAuthenticatedGroup}
+	if binding.Spec.GroupPrincipalName != "" {
+		// The user attribute of the system user of the binding may not be cached yet.
+		groups = append(groups, binding.Spec.GroupPrincipalName)
+		return &k8suser.DefaultInfo{Name: user.Name, Groups: groups}, nil
+	}
+
+	attribute, err := h.userAttributeCache.Get(user.Name)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return nil, fmt.Errorf("getting user attribute %s: %w", user.Name, err)
+	}
+	if attribute != nil {
+		for _, principals := range attribute.GroupPrincipals {
+			for _, principal := range principals.Items {
+				groups = append(groups, principal.Name)
+			}
+		}
+	}
+	return &k8suser.DefaultInfo{Name: user.Name, Groups: groups}, nil
+}
+
+// authorizeCreator checks that the creator of the binding may act as the user or the group the binding maps its
+// ServiceAccount to, so that creating a binding doesn't grant more than its creator has. A binding to the creator's
+// own user is always allowed, any other user or group requires the creator to be allowed to impersonate it. The
+// creator must have been recorded at admission, as clients could otherwise name any user as the creator.
+func (h *Handler) authorizeCreator(ctx context.Context, binding *apiv3.WorkloadIdentityBinding, resource, name string) error {
+	creatorID, err := h.creators.Creator(ctx, binding)
+	if errors.Is(err, creatorid.ErrUntrusted) {
+		return apierrors.NewBadRequest(fmt.Sprintf("creator of binding %s: %v", binding.Name, err))
+	} else if err != nil {
+		return fmt.Errorf("getting the creator of binding %s: %w", binding.Name, err)
+	}
+	if resource == "users" && name == creatorID {
+		return nil
+	}
+
+	response, err := h.subjectAccessReviews.Create(ctx, &authzv1.SubjectAccessReview{
+		Spec: authzv1.SubjectAccessReviewSpec{
+			ResourceAttributes: &authzv1.ResourceAttributes{
+				Verb:     "impersonate",
+				Resource: resource,
+				Name:     name,
+			},
+			User: creatorID,
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to create a SubjectAccessReview: %w", err)
+	}
+	if !response.Status.Allowed {
+		return apierrors.NewBadRequest(fmt.Sprintf("creator %s of binding %s is not allowed to impersonate %s %s", creatorID, binding.Name, resource, name))
+	}
+
+	return nil
+}
+
+// bindingUser returns the user the binding maps its ServiceAccount to.
+func (h *Handler) bindingUser(ctx context.Context, binding *apiv3.WorkloadIdentityBinding) (*apiv3.User, error) {
+	switch {
+	case binding.Spec.UserName != "" && binding.Spec.GroupPrincipalName != "":
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("binding %s sets both a user and a group", binding.Name))
+	case binding.Spec.UserName != "":
+		if err := h.authorizeCreator(ctx, binding, "users", binding.Spec.UserName); err != nil {
+			return nil, err
+		}
+		user, err := h.userCache.Get(binding.Spec.UserName)
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				return nil, apierrors.NewBadRequest(fmt.Sprintf("user %s of binding %s not found", binding.Spec.UserName, binding.Name))
+			}
+			return nil, fmt.Errorf("getting user %s: %w", binding.Spec.UserName, err)
+		}
+		return user, nil
+	case binding.Spec.GroupPrincipalName != "":
+		if err := h.authorizeCreator(ctx, binding, "groups", binding.Spec.GroupPrincipalName); err != nil {
+			return nil, err
+		}
+		return h.ensureGroupUser(binding)
+	default:
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("binding %s sets neither a user nor a group", binding.Name))
+	}
+}
+
+// ensureGroupUser ensures the system user of a binding to a group exists and is a member of the group.
+// The user is owned by the binding, so that it is deleted with it. Unlike users logging in, it isn't granted the
+// default global roles of new users.
+func (h *Handler) ensureGroupUser(binding *apiv3.WorkloadIdentityBinding) (*apiv3.User, error) {
+	principalID := groupUserPrincipalPrefix + binding.Name
+	userName := userNameForPrincipal(principalID)
+
+	user, err := h.userCache.Get(userName)
+	if apierrors.IsNotFound(err) {
+		user, err = h.users.Create(&apiv3.User{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: userName,
+				Labels: map[string]string{
+					BindingLabel: binding.Name,
+				},
+				OwnerReferences: []metav1.OwnerReference{bindingOwnerReference(binding)},
+			},
+			DisplayName:  "Workload identity " + binding.Name,
+			PrincipalIDs: []string{principalID},
+		})
+		if apierrors.IsAlreadyExists(err) {
+			user, err = h.users.Get(userName, metav1.GetOptions{})
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ensuring user for binding %s: %w", binding.Name, err)
+	}
+
+	provider, _, _ := strings.Cut(binding.Spec.GroupPrincipalName, "://")
+	group := apiv3.Principal{
+		ObjectMeta:    metav1.ObjectMeta{Name: binding.Spec.GroupPrincipalName},
+		PrincipalType: "group",
+		Provider:      strings.TrimSuffix(provider, "_group"),
+	}
+	// The group is stored under a provider of its own, so that changing the group of the binding replaces it.
+	if err := h.ensureUserAttribute(user.Name, tokenKind, []apiv3.Principal{group}, nil); err != nil {
+		return nil, fmt.Errorf("updating groups of user %s: %w", user.Name, err)
+	}
+
+	return user, nil
+}
+
+func bindingOwnerReference(binding *apiv3.WorkloadIdentityBinding) metav1.OwnerReference {
+	return metav1.OwnerReference{
+		APIVersion: apiv3.SchemeGroupVersion.String(),
+		Kind:       "WorkloadIdentityBinding",
+		Name:       binding.Name,
+		UID:        binding.UID,
+	}
+}
+
+func userNameForPrincipal(principalID string) string {
+	hasher := sha256.New()
+	hasher.Write([]byte(principalID))
+	sha := base32.StdEncoding.WithPadding(-1).EncodeToString(hasher.Sum(nil))[:10]
+	return "u-" + strings.ToLower(sha)
+}
+
+func writeError(w http.ResponseWriter, status int, code, description string) {
+	writeJSON(w, status, errorResponse{Error: code, ErrorDescription: description})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		logrus.Errorf("[workloadidentity] Error writing response: %v", err)
+	}
+}
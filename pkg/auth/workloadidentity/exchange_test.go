@@ -0,0 +1,507 @@
+package workloadidentity
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"github.com/golang-jwt/jwt/v5"
+	ext "github.com/rancher/rancher/pkg/apis/ext.cattle.io/v1"
+	apiv3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/auth/creatorid"
+	"github.com/rancher/wrangler/v3/pkg/generic/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	authzv1 "k8s.io/api/authorization/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	k8suser "k8s.io/apiserver/pkg/authentication/user"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+const (
+	testIssuer   = "https://kubernetes.default.svc.cluster.local"
+	testAudience = "https://rancher.example.com"
+)
+
+type fakeKeySource map[string]crypto.PublicKey
+
+func (f fakeKeySource) Keys(clusterName string) (string, oidc.KeySet, error) {
+	key, ok := f[clusterName]
+	if !ok {
+		return "", nil, fmt.Errorf("cluster %s is not ready", clusterName)
+	}
+	return testIssuer, &oidc.StaticKeySet{PublicKeys: []crypto.PublicKey{key}}, nil
+}
+
+type fakeTokenCreator struct {
+	created    []*ext.Token
+	principals []ext.TokenPrincipal
+	userInfos  []k8suser.Info
+}
+
+func (f *fakeTokenCreator) CreateForPrincipal(_ context.Context, token *ext.Token, principal ext.TokenPrincipal, userInfo k8suser.Info) (*ext.Token, error) {
+	f.created = append(f.created, token.DeepCopy())
+	f.principals = append(f.principals, principal)
+	f.userInfos = append(f.userInfos, userInfo)
+	if token.Spec.UserID == "u-disabled" {
+		return nil, apierrors.NewBadRequest("operation references a disabled user")
+	}
+	result := token.DeepCopy()
+	result.Name = "token-abcde"
+	result.Status.BearerToken = "ext/token-abcde:secret"
+	return result, nil
+}
+
+func signToken(t *testing.T, key *rsa.PrivateKey, claims jwt.MapClaims) string {
+	t.Helper()
+	token, err := jwt.NewWithClaims(jwt.SigningMethodRS256, claims).SignedString(key)
+	require.NoError(t, err)
+	return token
+}
+
+func TestServeHTTP(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+
+	clusterKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	claims := func(modify func(jwt.MapClaims)) jwt.MapClaims {
+		claims := jwt.MapClaims{
+			"iss": testIssuer,
+			"sub": "system:serviceaccount:ci:runner",
+			"aud": []string{testAudience},
+			"iat": now.Add(-time.Minute).Unix(),
+			"exp": now.Add(time.Hour).Unix(),
+		}
+		if modify != nil {
+			modify(claims)
+		}
+		return claims
+	}
+	validToken := signToken(t, clusterKey, claims(nil))
+
+	binding := func(name string, modify func(*apiv3.WorkloadIdentityBindingSpec)) *apiv3.WorkloadIdentityBinding {
+		binding := &apiv3.WorkloadIdentityBinding{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				UID:               "binding-uid",
+				CreationTimestamp: metav1.NewTime(now.Add(-time.Minute)),
+				Annotations:       map[string]string{creatorid.Annotation: "u-abcde"},
+			},
+			Spec: apiv3.WorkloadIdentityBindingSpec{
+				ClusterName:             "c-m-abcde",
+				ServiceAccountNamespace: "ci",
+				ServiceAccountName:      "runner",
+				UserName:                "u-abcde",
+			},
+		}
+		if modify != nil {
+			modify(&binding.Spec)
+		}
+		return binding
+	}
+	createdBy := func(creatorID string, binding *apiv3.WorkloadIdentityBinding) *apiv3.WorkloadIdentityBinding {
+		if creatorID == "" {
+			delete(binding.Annotations, creatorid.Annotation)
+		} else {
+			binding.Annotations[creatorid.Annotation] = creatorID
+		}
+		return binding
+	}
+	// The creator of bindings is recorded at admission since an hour ago.
+	policyBinding := &admissionregistrationv1.ValidatingAdmissionPolicyBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: creatorid.PolicyName, CreationTimestamp: metav1.NewTime(now.Add(-time.Hour))},
+		Spec: admissionregistrationv1.ValidatingAdmissionPolicyBindingSpec{
+			PolicyName:        creatorid.PolicyName,
+			ValidationActions: []admissionregistrationv1.ValidationAction{admissionregistrationv1.Deny},
+		},
+	}
+	form := func(subjectToken string) url.Values {
+		return url.Values{
+			"grant_type":         {GrantTypeTokenExchange},
+			"subject_token_type": {TokenTypeJWT},
+			"subject_token":      {subjectToken},
+		}
+	}
+
+	tests := []struct {
+		name            string
+		form            url.Values
+		bindings        []*apiv3.WorkloadIdentityBinding
+		wantStatus      int
+		wantError       string
+		wantUser        string
+		wantTTL         time.Duration
+		wantClusterName string
+		wantGroups      []string
+		wantAttribute   *apiv3.Principal
+	}{
+		{
+			name:       "token for a user",
+			form:       form(validToken),
+			bindings:   []*apiv3.WorkloadIdentityBinding{binding("ci-runner", nil)},
+			wantStatus: http.StatusOK,
+			wantUser:   "u-abcde",
+			wantTTL:    defaultTokenTTL,
+		},
+		{
+			name: "scoped token with a custom lifetime",
+			form: form(validToken),
+			bindings: []*apiv3.WorkloadIdentityBinding{binding("ci-runner", func(spec *apiv3.WorkloadIdentityBindingSpec) {
+				spec.TokenTTLSeconds = 300
+				spec.TokenClusterName = "c-m-fghij"
+			})},
+			wantStatus:      http.StatusOK,
+			wantUser:        "u-abcde",
+			wantTTL:         5 * time.Minute,
+			wantClusterName: "c-m-fghij",
+			wantGroups:      []string{"system:authenticated", "system:cattle:authenticated", "github_team://ci"},
+		},
+		{
+			name:       "token for another user created by an admin",
+			form:       form(validToken),
+			bindings:   []*apiv3.WorkloadIdentityBinding{createdBy("user-admin", binding("ci-runner", nil))},
+			wantStatus: http.StatusOK,
+			wantUser:   "u-abcde",
+			wantTTL:    defaultTokenTTL,
+		},
+		{
+			name: "token for a group",
+			form: form(validToken),
+			bindings: []*apiv3.WorkloadIdentityBinding{createdBy("user-admin", binding("ci-runner", func(spec *apiv3.WorkloadIdentityBindingSpec) {
+				spec.UserName = ""
+				spec.GroupPrincipalName = "okta_group://ci-runners"
+			}))},
+			wantStatus: http.StatusOK,
+			wantUser:   userNameForPrincipal("system://workloadidentity/ci-runner"),
+			wantTTL:    defaultTokenTTL,
+			wantGroups: []string{"system:authenticated", "system:cattle:authenticated", "okta_group://ci-runners"},
+			wantAttribute: &apiv3.Principal{
+				ObjectMeta:    metav1.ObjectMeta{Name: "okta_group://ci-runners"},
+				PrincipalType: "group",
+				Provider:      "okta",
+			},
+		},
+		{
+			name: "binding with a custom audience",
+			form: form(signToken(t, clusterKey, claims(func(c jwt.MapClaims) { c["aud"] = []string{"rancher"} }))),
+			bindings: []*apiv3.WorkloadIdentityBinding{binding("ci-runner", func(spec *apiv3.WorkloadIdentityBindingSpec) {
+				spec.Audience = "rancher"
+			})},
+			wantStatus: http.StatusOK,
+			wantUser:   "u-abcde",
+			wantTTL:    defaultTokenTTL,
+		},
+		{
+			name: "token verified by the binding of its cluster",
+			form: form(validToken),
+			bindings: []*apiv3.WorkloadIdentityBinding{
+				binding("a-other-cluster", func(spec *apiv3.WorkloadIdentityBindingSpec) {
+					spec.ClusterName = "c-m-other"
+					spec.UserName = "u-other"
+				}),
+				binding("b-unready-cluster", func(spec *apiv3.WorkloadIdentityBindingSpec) {
+					spec.ClusterName = "c-m-unready"
+					spec.UserName = "u-other"
+				}),
+				binding("c-ci-runner", nil),
+			},
+			wantStatus: http.StatusOK,
+			wantUser:   "u-abcde",
+			wantTTL:    defaultTokenTTL,
+		},
+		{
+			name: "unsupported grant type",
+			form: url.Values{
+				"grant_type":    {"client_credentials"},
+				"subject_token": {validToken},
+			},
+			wantStatus: http.StatusBadRequest,
+			wantError:  errUnsupportedGrantType,
+		},
+		{
+			name: "unsupported subject token type",
+			form: url.Values{
+				"grant_type":         {GrantTypeTokenExchange},
+				"subject_token_type": {TokenTypeAccessToken},
+				"subject_token":      {validToken},
+			},
+			wantStatus: http.StatusBadRequest,
+			wantError:  errInvalidRequest,
+		},
+		{
+			name: "delegation",
+			form: func() url.Values {
+				form := form(validToken)
+				form.Set("actor_token", validToken)
+				return form
+			}(),
+			wantStatus: http.StatusBadRequest,
+			wantError:  errInvalidRequest,
+		},
+		{
+			name:       "missing subject token",
+			form:       form(""),
+			wantStatus: http.StatusBadRequest,
+			wantError:  errInvalidRequest,
+		},
+		{
+			name:       "malformed subject token",
+			form:       form("not-a-jwt"),
+			bindings:   []*apiv3.WorkloadIdentityBinding{binding("ci-runner", nil)},
+			wantStatus: http.StatusBadRequest,
+			wantError:  errInvalidRequest,
+		},
+		{
+			name:       "no binding for the ServiceAccount",
+			form:       form(signToken(t, clusterKey, claims(func(c jwt.MapClaims) { c["sub"] = "system:serviceaccount:ci:other" }))),
+			bindings:   []*apiv3.WorkloadIdentityBinding{binding("ci-runner", nil)},
+			wantStatus: http.StatusBadRequest,
+			wantError:  errInvalidRequest,
+		},
+		{
+			name:       "subject isn't a ServiceAccount",
+			form:       form(signToken(t, clusterKey, claims(func(c jwt.MapClaims) { c["sub"] = "ci:runner" }))),
+			bindings:   []*apiv3.WorkloadIdentityBinding{binding("ci-runner", nil)},
+			wantStatus: http.StatusBadRequest,
+			wantError:  errInvalidRequest,
+		},
+		{
+			name:       "token signed by another cluster",
+			form:       form(signToken(t, otherKey, claims(nil))),
+			bindings:   []*apiv3.WorkloadIdentityBinding{binding("ci-runner", nil)},
+			wantStatus: http.StatusBadRequest,
+			wantError:  errInvalidRequest,
+		},
+		{
+			name:       "token of another issuer",
+			form:       form(signToken(t, clusterKey, claims(func(c jwt.MapClaims) { c["iss"] = "https://issuer.example.com" }))),
+			bindings:   []*apiv3.WorkloadIdentityBinding{binding("ci-runner", nil)},
+			wantStatus: http.StatusBadRequest,
+			wantError:  errInvalidRequest,
+		},
+		{
+			name:       "token for another audience",
+			form:       form(signToken(t, clusterKey, claims(func(c jwt.MapClaims) { c["aud"] = []string{"https://kubernetes.default.svc"} }))),
+			bindings:   []*apiv3.WorkloadIdentityBinding{binding("ci-runner", nil)},
+			wantStatus: http.StatusBadRequest,
+			wantError:  errInvalidRequest,
+		},
+		{
+			name:       "expired token",
+			form:       form(signToken(t, clusterKey, claims(func(c jwt.MapClaims) { c["exp"] = now.Add(-time.Minute).Unix() }))),
+			bindings:   []*apiv3.WorkloadIdentityBinding{binding("ci-runner", nil)},
+			wantStatus: http.StatusBadRequest,
+			wantError:  errInvalidRequest,
+		},
+		{
+			name: "user not found",
+			form: form(validToken),
+			bindings: []*apiv3.WorkloadIdentityBinding{createdBy("user-admin", binding("ci-runner", func(spec *apiv3.WorkloadIdentityBindingSpec) {
+				spec.UserName = "u-missing"
+			}))},
+			wantStatus: http.StatusBadRequest,
+			wantError:  errInvalidRequest,
+		},
+		{
+			name: "disabled user",
+			form: form(validToken),
+			bindings: []*apiv3.WorkloadIdentityBinding{createdBy("user-admin", binding("ci-runner", func(spec *apiv3.WorkloadIdentityBindingSpec) {
+				spec.UserName = "u-disabled"
+			}))},
+			wantStatus: http.StatusBadRequest,
+			wantError:  errInvalidRequest,
+		},
+		{
+			name: "creator not allowed to impersonate the user",
+			form: form(validToken),
+			bindings: []*apiv3.WorkloadIdentityBinding{binding("ci-runner", func(spec *apiv3.WorkloadIdentityBindingSpec) {
+				spec.UserName = "user-admin"
+			})},
+			wantStatus: http.StatusBadRequest,
+			wantError:  errInvalidRequest,
+		},
+		{
+			name: "creator not allowed to impersonate the group",
+			form: form(validToken),
+			bindings: []*apiv3.WorkloadIdentityBinding{binding("ci-runner", func(spec *apiv3.WorkloadIdentityBindingSpec) {
+				spec.UserName = ""
+				spec.GroupPrincipalName = "okta_group://admins"
+			})},
+			wantStatus: http.StatusBadRequest,
+			wantError:  errInvalidRequest,
+		},
+		{
+			name:       "binding without a creator",
+			form:       form(validToken),
+			bindings:   []*apiv3.WorkloadIdentityBinding{createdBy("", binding("ci-runner", nil))},
+			wantStatus: http.StatusBadRequest,
+			wantError:  errInvalidRequest,
+		},
+		{
+			name: "binding created before the creator was recorded at admission",
+			form: form(validToken),
+			bindings: []*apiv3.WorkloadIdentityBinding{func() *apiv3.WorkloadIdentityBinding {
+				binding := binding("ci-runner", nil)
+				binding.CreationTimestamp = metav1.NewTime(now.Add(-2 * time.Hour))
+				return binding
+			}()},
+			wantStatus: http.StatusBadRequest,
+			wantError:  errInvalidRequest,
+		},
+		{
+			name: "binding to both a user and a group",
+			form: form(validToken),
+			bindings: []*apiv3.WorkloadIdentityBinding{binding("ci-runner", func(spec *apiv3.WorkloadIdentityBindingSpec) {
+				spec.GroupPrincipalName = "okta_group://ci-runners"
+			})},
+			wantStatus: http.StatusBadRequest,
+			wantError:  errInvalidRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			bindingCache := fake.NewMockNonNamespacedCacheInterface[*apiv3.WorkloadIdentityBinding](ctrl)
+			bindingCache.EXPECT().List(gomock.Any()).Return(tt.bindings, nil).AnyTimes()
+
+			users := map[string]*apiv3.User{
+				"u-abcde":    {ObjectMeta: metav1.ObjectMeta{Name: "u-abcde"}, DisplayName: "CI", Username: "ci"},
+				"u-other":    {ObjectMeta: metav1.ObjectMeta{Name: "u-other"}},
+				"user-admin": {ObjectMeta: metav1.ObjectMeta{Name: "user-admin"}},
+				"u-disabled": {ObjectMeta: metav1.ObjectMeta{Name: "u-disabled"}},
+			}
+			userCache := fake.NewMockNonNamespacedCacheInterface[*apiv3.User](ctrl)
+			userCache.EXPECT().Get(gomock.Any()).DoAndReturn(func(name string) (*apiv3.User, error) {
+				if user, ok := users[name]; ok {
+					return user, nil
+				}
+				return nil, apierrors.NewNotFound(schema.GroupResource{Resource: "users"}, name)
+			}).AnyTimes()
+			userAttributeCache := fake.NewMockNonNamespacedCacheInterface[*apiv3.UserAttribute](ctrl)
+			userAttributeCache.EXPECT().Get(gomock.Any()).DoAndReturn(func(name string) (*apiv3.UserAttribute, error) {
+				if name == "u-abcde" {
+					return &apiv3.UserAttribute{GroupPrincipals: map[string]apiv3.Principals{
+						"github": {Items: []apiv3.Principal{{ObjectMeta: metav1.ObjectMeta{Name: "github_team://ci"}}}},
+					}}, nil
+				}
+				return nil, apierrors.NewNotFound(schema.GroupResource{Resource: "userattributes"}, name)
+			}).AnyTimes()
+			userClient := fake.NewMockNonNamespacedClientInterface[*apiv3.User, *apiv3.UserList](ctrl)
+			var createdUser *apiv3.User
+			userClient.EXPECT().Create(gomock.Any()).DoAndReturn(func(user *apiv3.User) (*apiv3.User, error) {
+				createdUser = user.DeepCopy()
+				return user, nil
+			}).AnyTimes()
+
+			k8sClient := k8sfake.NewSimpleClientset(policyBinding)
+			k8sClient.PrependReactor("create", "subjectaccessreviews",
+				func(action k8stesting.Action) (bool, runtime.Object, error) {
+					review := action.(k8stesting.CreateAction).GetObject().(*authzv1.SubjectAccessReview)
+					assert.Equal(t, "impersonate", review.Spec.ResourceAttributes.Verb)
+					review.Status.Allowed = review.Spec.User == "user-admin"
+					return true, review, nil
+				},
+			)
+
+			var attributeGroups []apiv3.Principal
+			tokens := &fakeTokenCreator{}
+			h := &Handler{
+				bindingCache:         bindingCache,
+				userCache:            userCache,
+				userAttributeCache:   userAttributeCache,
+				users:                userClient,
+				creators:             creatorid.NewVerifier(k8sClient.AdmissionregistrationV1().ValidatingAdmissionPolicyBindings()),
+				subjectAccessReviews: k8sClient.AuthorizationV1().SubjectAccessReviews(),
+				keys: fakeKeySource{
+					"c-m-abcde": clusterKey.Public(),
+					"c-m-other": otherKey.Public(),
+				},
+				ensureUserAttribute: func(userID, provider string, groupPrincipals []apiv3.Principal, _ map[string][]string, _ ...time.Time) error {
+					assert.Equal(t, tokenKind, provider)
+					attributeGroups = groupPrincipals
+					return nil
+				},
+				tokens:          tokens,
+				defaultAudience: func() string { return testAudience },
+				now:             func() time.Time { return now },
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/v1-public/oauth/token", strings.NewReader(tt.form.Encode()))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			require.Equal(t, tt.wantStatus, rec.Code, rec.Body.String())
+			assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+			if tt.wantError != "" {
+				var resp errorResponse
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+				assert.Equal(t, tt.wantError, resp.Error)
+				return
+			}
+
+			var resp tokenResponse
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+			assert.Equal(t, tokenResponse{
+				AccessToken:     "ext/token-abcde:secret",
+				IssuedTokenType: TokenTypeAccessToken,
+				TokenType:       "Bearer",
+				ExpiresIn:       int64(tt.wantTTL.Seconds()),
+			}, resp)
+			assert.Equal(t, "no-store", rec.Header().Get("Cache-Control"))
+
+			require.Len(t, tokens.created, 1)
+			token := tokens.created[0]
+			assert.Equal(t, tt.wantUser, token.Spec.UserID)
+			assert.Equal(t, tokenKind, token.Spec.Kind)
+			assert.Equal(t, tt.wantTTL.Milliseconds(), token.Spec.TTL)
+			assert.Equal(t, tt.wantClusterName, token.Spec.ClusterName)
+			bindingName := tt.bindings[len(tt.bindings)-1].Name
+			assert.Equal(t, bindingName, token.Labels[BindingLabel])
+			assert.Equal(t, []metav1.OwnerReference{{
+				APIVersion: "management.cattle.io/v3",
+				Kind:       "WorkloadIdentityBinding",
+				Name:       bindingName,
+				UID:        "binding-uid",
+			}}, token.OwnerReferences)
+			assert.Equal(t, "local://"+tt.wantUser, tokens.principals[0].Name)
+			assert.Equal(t, "local", tokens.principals[0].Provider)
+			assert.Equal(t, tt.wantUser, tokens.userInfos[0].GetName())
+			if tt.wantGroups != nil {
+				assert.Equal(t, tt.wantGroups, tokens.userInfos[0].GetGroups())
+			}
+
+			if tt.wantAttribute == nil {
+				assert.Nil(t, createdUser)
+				assert.Nil(t, attributeGroups)
+				return
+			}
+			require.NotNil(t, createdUser)
+			assert.Equal(t, tt.wantUser, createdUser.Name)
+			assert.Equal(t, []string{"system://workloadidentity/" + bindingName}, createdUser.PrincipalIDs)
+			assert.Equal(t, token.OwnerReferences, createdUser.OwnerReferences)
+			assert.Equal(t, []apiv3.Principal{*tt.wantAttribute}, attributeGroups)
+		})
+	}
+}
@@ -0,0 +1,165 @@
+package workloadidentity
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	apiv3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	mgmtv3 "github.com/rancher/rancher/pkg/generated/controllers/management.cattle.io/v3"
+	"golang.org/x/sync/singleflight"
+	"k8s.io/client-go/rest"
+)
+
+const (
+	// discoveryTTL is how long the issuer of a cluster is cached for. Signing keys are fetched again as soon as
+	// tokens signed with an unknown key are presented.
+	discoveryTTL = 10 * time.Minute
+
+	discoveryPath = "/.well-known/openid-configuration"
+	jwksPath      = "/openid/v1/jwks"
+)
+
+// keySource returns the issuer and the signing keys of the ServiceAccount tokens of a cluster.
+type keySource interface {
+	Keys(clusterName string) (string, oidc.KeySet, error)
+}
+
+// clusterKeys are the discovered issuer and signing keys of a cluster.
+type clusterKeys struct {
+	issuer       string
+	keySet       oidc.KeySet
+	discoveredAt time.Time
+}
+
+// clusterKeySource discovers the issuer and the signing keys of the ServiceAccount tokens of downstream clusters
+// by querying their API servers through the tunnel. The signing keys are always fetched from the API server, even
+// if the discovery document points to an external location, so that they can be trusted.
+type clusterKeySource struct {
+	// ctx is the lifetime of the signing key caches.
+	ctx          context.Context
+	clusterCache mgmtv3.ClusterCache
+	restConfig   func(cluster *apiv3.Cluster) (*rest.Config, error)
+	now          func() time.Time
+
+	// discoveries makes concurrent discoveries of the same cluster wait for a single one, without holding mu, so that
+	// a slow or unreachable cluster doesn't hold up the exchanges of the other clusters.
+	discoveries singleflight.Group
+
+	mu       sync.Mutex
+	clusters map[string]*clusterKeys
+}
+
+// Keys implements [keySource].
+func (s *clusterKeySource) Keys(clusterName string) (string, oidc.KeySet, error) {
+	if keys := s.cached(clusterName); keys != nil {
+		return keys.issuer, keys.keySet, nil
+	}
+
+	v, err, _ := s.discoveries.Do(clusterName, func() (any, error) {
+		return s.discover(clusterName)
+	})
+	if err != nil {
+		return "", nil, err
+	}
+	keys := v.(*clusterKeys)
+
+	return keys.issuer, keys.keySet, nil
+}
+
+// cached returns the keys of the cluster if they were discovered less than discoveryTTL ago.
+func (s *clusterKeySource) cached(clusterName string) *clusterKeys {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if keys, ok := s.clusters[clusterName]; ok && s.now().Sub(keys.discoveredAt) < discoveryTTL {
+		return keys
+	}
+	return nil
+}
+
+// discover discovers the issuer of the cluster and caches it with its signing keys.
+func (s *clusterKeySource) discover(clusterName string) (*clusterKeys, error) {
+	cluster, err := s.clusterCache.Get(clusterName)
+	if err != nil {
+		return nil, fmt.Errorf("getting cluster %s: %w", clusterName, err)
+	}
+	restConfig, err := s.restConfig(cluster)
+	if err != nil {
+		return nil, fmt.Errorf("connecting to cluster %s: %w", clusterName, err)
+	}
+	if restConfig == nil {
+		return nil, fmt.Errorf("cluster %s is not ready", clusterName)
+	}
+	client, err := rest.HTTPClientFor(restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("creating client for cluster %s: %w", clusterName, err)
+	}
+	host := strings.TrimSuffix(restConfig.Host, "/")
+
+	issuer, err := discoverIssuer(s.ctx, client, host+discoveryPath)
+	if err != nil {
+		return nil, fmt.Errorf("discovering issuer of cluster %s: %w", clusterName, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	keys := &clusterKeys{
+		issuer:       issuer,
+		discoveredAt: s.now(),
+	}
+	// Keep the key set of the previous discovery if the issuer is the same, to avoid fetching the keys again.
+	if previous, ok := s.clusters[clusterName]; ok && previous.issuer == issuer {
+		keys.keySet = previous.keySet
+	} else {
+		keys.keySet = oidc.NewRemoteKeySet(oidc.ClientContext(s.ctx, client), host+jwksPath)
+	}
+	s.clusters[clusterName] = keys
+
+	return keys, nil
+}
+
+// discoverIssuer returns the issuer of the OpenID discovery document at the given URL.
+func discoverIssuer(ctx context.Context, client *http.Client, url string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	var discovery struct {
+		Issuer string `json:"issuer"`
+	}
+	if err := json.Unmarshal(body, &discovery); err != nil {
+		return "", fmt.Errorf("decoding discovery document: %w", err)
+	}
+	if discovery.Issuer == "" {
+		return "", fmt.Errorf("discovery document has no issuer")
+	}
+
+	return discovery.Issuer, nil
+}
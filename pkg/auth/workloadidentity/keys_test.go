@@ -0,0 +1,159 @@
+package workloadidentity
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"github.com/golang-jwt/jwt/v5"
+	apiv3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/wrangler/v3/pkg/generic/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+)
+
+func TestClusterKeySource(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	var discoveries atomic.Int32
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer cluster-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case discoveryPath:
+			discoveries.Add(1)
+			_ = json.NewEncoder(w).Encode(map[string]string{
+				"issuer": testIssuer,
+				// The keys are fetched from the API server regardless.
+				"jwks_uri": "https://oidc.example.com/keys",
+			})
+		case jwksPath:
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"keys": []map[string]string{{
+					"kty": "RSA",
+					"kid": "key",
+					"alg": "RS256",
+					"use": "sig",
+					"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+					"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+				}},
+			})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	ctrl := gomock.NewController(t)
+	clusterCache := fake.NewMockNonNamespacedCacheInterface[*apiv3.Cluster](ctrl)
+	clusterCache.EXPECT().Get(gomock.Any()).DoAndReturn(func(name string) (*apiv3.Cluster, error) {
+		return &apiv3.Cluster{ObjectMeta: metav1.ObjectMeta{Name: name}}, nil
+	}).AnyTimes()
+
+	now := time.Now()
+	source := &clusterKeySource{
+		ctx:          context.Background(),
+		clusterCache: clusterCache,
+		restConfig: func(cluster *apiv3.Cluster) (*rest.Config, error) {
+			if cluster.Name != "c-m-abcde" {
+				return nil, nil
+			}
+			return &rest.Config{
+				Host:        server.URL,
+				BearerToken: "cluster-token",
+				TLSClientConfig: rest.TLSClientConfig{
+					CAData: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}),
+				},
+			}, nil
+		},
+		now:      func() time.Time { return now },
+		clusters: map[string]*clusterKeys{},
+	}
+
+	issuer, keySet, err := source.Keys("c-m-abcde")
+	require.NoError(t, err)
+	assert.Equal(t, testIssuer, issuer)
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss": testIssuer,
+		"sub": "system:serviceaccount:ci:runner",
+		"aud": testAudience,
+		"exp": now.Add(time.Hour).Unix(),
+	})
+	token.Header["kid"] = "key"
+	signed, err := token.SignedString(key)
+	require.NoError(t, err)
+
+	verifier := oidc.NewVerifier(issuer, keySet, &oidc.Config{ClientID: testAudience, Now: source.now})
+	_, err = verifier.Verify(context.Background(), signed)
+	require.NoError(t, err)
+
+	// The issuer is cached until it is discovered again.
+	_, cachedKeySet, err := source.Keys("c-m-abcde")
+	require.NoError(t, err)
+	assert.Same(t, keySet, cachedKeySet)
+	assert.Equal(t, int32(1), discoveries.Load())
+
+	now = now.Add(discoveryTTL)
+	_, rediscoveredKeySet, err := source.Keys("c-m-abcde")
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), discoveries.Load())
+	assert.Same(t, keySet, rediscoveredKeySet)
+
+	_, _, err = source.Keys("c-m-unready")
+	assert.ErrorContains(t, err, "cluster c-m-unready is not ready")
+}
+
+func TestClusterKeySourceSlowCluster(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	clusterCache := fake.NewMockNonNamespacedCacheInterface[*apiv3.Cluster](ctrl)
+	clusterCache.EXPECT().Get(gomock.Any()).DoAndReturn(func(name string) (*apiv3.Cluster, error) {
+		return &apiv3.Cluster{ObjectMeta: metav1.ObjectMeta{Name: name}}, nil
+	}).AnyTimes()
+
+	connecting := make(chan struct{})
+	release := make(chan struct{})
+	source := &clusterKeySource{
+		ctx:          context.Background(),
+		clusterCache: clusterCache,
+		restConfig: func(cluster *apiv3.Cluster) (*rest.Config, error) {
+			if cluster.Name == "c-m-slow" {
+				close(connecting)
+				<-release
+			}
+			return nil, nil
+		},
+		now:      time.Now,
+		clusters: map[string]*clusterKeys{},
+	}
+
+	slow := make(chan error)
+	go func() {
+		_, _, err := source.Keys("c-m-slow")
+		slow <- err
+	}()
+	<-connecting
+
+	// The discovery of another cluster doesn't wait for the slow one.
+	_, _, err := source.Keys("c-m-unready")
+	assert.ErrorContains(t, err, "cluster c-m-unready is not ready")
+
+	close(release)
+	assert.ErrorContains(t, <-slow, "cluster c-m-slow is not ready")
+}
@@ -4,6 +4,7 @@
 	"context"
 	"crypto/sha256"
 	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"path"
@@ -44,6 +45,7 @@
 
 const (
 	AddressAnnotation                      = "rke.cattle.io/address"
+	AgentCACertsChecksumAnnotation         = "rke.cattle.io/agent-ca-checksum"
 	BootstrapTokenLastAccessTimeAnnotation = "rke.cattle.io/last-access-timestamp"
 	BootstrapTokenAnnotation               = "rke.cattle.io/bootstrap-token"
 	ClusterNameLabel                       = "rke.cattle.io/cluster-name"
@@ -53,7 +55,9 @@
 	DrainAnnotation                            = "rke.cattle.io/drain-options"
 	DrainDoneAnnotation                        = "rke.cattle.io/drain-done"
 	DrainErrorAnnotation                       = "rke.cattle.io/drain-error"
+	DrainHookStatusAnnotation                  = "rke.cattle.io/drain-hook-status"
 	EtcdRoleLabel                              = "rke.cattle.io/etcd-role"
+	FailureDomainLabel                         = "rke.cattle.io/failure-domain"
 	ForceRemoveEtcdAnnotation                  = "rke.cattle.io/etcd-force-remove"
 	HostnameLengthLimitAnnotation              = "rke.cattle.io/hostname-length-limit"
 	InitNodeLabel                              = "rke.cattle.io/init-node"
@@ -183,6 +187,24 @@
 	// Used on: provisioning.cattle.io/v1 Cluster
 	ClusterAutoscalerDeploymentReady = condition.Cond("ClusterAutoscalerDeploymentReady")
 
+	// EtcdFailureDomainsBalanced indicates whether the etcd machine pools spread across failure domains keep etcd quorum
+	// when any single failure domain is lost.
+	// Used on: provisioning.cattle.io/v1 Cluster
+	EtcdFailureDomainsBalanced = condition.Cond("EtcdFailureDomainsBalanced")
+
+	// MachinePoolSchedulesReady indicates whether the schedules of all machine pools are valid and being applied.
+	// Used on: provisioning.cattle.io/v1 Cluster
+	MachinePoolSchedulesReady = condition.Cond("MachinePoolSchedulesReady")
+
+	// ClusterTemplateApplied indicates whether the cluster template revision referenced by the cluster was applied to
+	// its spec.
+	// Used on: provisioning.cattle.io/v1 Cluster
+	ClusterTemplateApplied = condition.Cond("ClusterTemplateApplied")
+
+	// ClusterTemplateValid indicates whether all revisions of a cluster template are valid.
+	// Used on: provisioning.cattle.io/v1 ClusterTemplate
+	ClusterTemplateValid = condition.Cond("Valid")
+
 	// ClusterAutoscalerEnabledAnnotation is an annotation used to enable cluster autoscaling for a cluster.
 	// this is set on the CAPI Cluster object in order to trigger the controllers to set up the autoscaler
 	// dependencies and install the chart.
@@ -849,3 +871,59 @@ func SetCAPIResourceCondition(obj CAPIConditionSetter, condition metav1.Conditio
 
 	capiconditionsv1beta1.Set(obj, &v1beta1Cond)
 }
+
+// DrainWebhookResult is the result of the calls to a webhook drain hook of a machine.
+type DrainWebhookResult struct {
+	// Stage is either "pre-drain" or "post-drain".
+	Stage string `json:"stage"`
+	// Index is the index of the hook in the pre-drain or post-drain hooks of the drain options.
+	Index    int                        `json:"index"`
+	URL      string                     `json:"url"`
+	Decision rkev1.DrainWebhookDecision `json:"decision"`
+	Message  string                     `json:"message,omitempty"`
+	Attempts int                        `json:"attempts"`
+	// FirstAttempt is the time of the first call, from which the retry timeout of the hook is counted.
+	FirstAttempt metav1.Time `json:"firstAttempt"`
+	// NextAttempt is the time after which a hook that asked to be retried is called again.
+	NextAttempt metav1.Time `json:"nextAttempt,omitempty"`
+}
+
+// DrainHookStatus records the results of the webhook drain hooks of a machine as JSON in the
+// DrainHookStatusAnnotation of its machine-plan secret. The results are only valid for the drain options they were
+// recorded for, which are identified by the hash of the DrainAnnotation.
+type DrainHookStatus struct {
+	DrainOptionsHash string               `json:"drainOptionsHash"`
+	Results          []DrainWebhookResult `json:"results,omitempty"`
+}
+
+// Result returns the result of the hook with the given stage and index, or nil if the hook has not been called yet.
+func (s *DrainHookStatus) Result(stage string, index int) *DrainWebhookResult {
+	for i := range s.Results {
+		if s.Results[i].Stage == stage && s.Results[i].Index == index {
+			return &s.Results[i]
+		}
+	}
+	return nil
+}
+
+// SetResult adds or replaces the result of a hook.
+func (s *DrainHookStatus) SetResult(result DrainWebhookResult) {
+	if existing := s.Result(result.Stage, result.Index); existing != nil {
+		*existing = result
+		return
+	}
+	s.Results = append(s.Results, result)
+}
+
+// GetDrainHookStatus returns the webhook drain hook status recorded in the given machine-plan secret annotations for
+// the current drain options. A status recorded for previous drain options, or one that cannot be parsed, is discarded.
+func GetDrainHookStatus(annotations map[string]string) DrainHookStatus {
+	digest := sha256.Sum256([]byte(annotations[DrainAnnotation]))
+	hash := hex.EncodeToString(digest[:])
+
+	var status DrainHookStatus
+	if err := json.Unmarshal([]byte(annotations[DrainHookStatusAnnotation]), &status); err != nil || status.DrainOptionsHash != hash {
+		return DrainHookStatus{DrainOptionsHash: hash}
+	}
+	return status
+}
@@ -19,6 +19,7 @@
 	rkecontroller "github.com/rancher/rancher/pkg/generated/controllers/rke.cattle.io/v1"
 	"github.com/rancher/rancher/pkg/serviceaccounttoken"
 	"github.com/rancher/rancher/pkg/settings"
+	"github.com/rancher/rancher/pkg/systemtemplate"
 	"github.com/rancher/rancher/pkg/tls"
 	"github.com/rancher/rancher/pkg/wrangler"
 	corecontrollers "github.com/rancher/wrangler/v3/pkg/generated/controllers/core/v1"
@@ -31,6 +32,7 @@
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/clientcmd"
 	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	"k8s.io/client-go/util/retry"
 	capi "sigs.k8s.io/cluster-api/api/core/v1beta2"
 )
 
@@ -144,6 +146,31 @@ func (r *RKE2ConfigServer) ServeHTTP(rw http.ResponseWriter, req *http.Request)
 	}
 }
 
+// recordAgentCACerts records on the plan secret the checksum of the CA certificates handed to the system agent, which
+// it verifies the Rancher server with until it retrieves its connection info again.
+func (r *RKE2ConfigServer) recordAgentCACerts(namespace, planSecretName string, ca []byte) {
+	checksum := systemtemplate.CACertsChecksum(string(ca))
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		planSecret, err := r.secrets.Get(namespace, planSecretName, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		if current, ok := planSecret.Annotations[capr.AgentCACertsChecksumAnnotation]; ok && current == checksum {
+			return nil
+		}
+		planSecret = planSecret.DeepCopy()
+		if planSecret.Annotations == nil {
+			planSecret.Annotations = map[string]string{}
+		}
+		planSecret.Annotations[capr.AgentCACertsChecksumAnnotation] = checksum
+		_, err = r.secrets.Update(planSecret)
+		return err
+	})
+	if err != nil {
+		logrus.Errorf("[rke2configserver] failed to record the CA certificates checksum on plan secret %s/%s: %v", namespace, planSecretName, err)
+	}
+}
+
 func (r *RKE2ConfigServer) connectAgent(planSecret string, secret *corev1.Secret, rw http.ResponseWriter, req *http.Request) {
 	var ca []byte
 	url, pem := settings.ServerURL.Get(), settings.CACerts.Get()
@@ -164,6 +191,8 @@ func (r *RKE2ConfigServer) connectAgent(planSecret string, secret *corev1.Secret
 		}
 	}
 
+	r.recordAgentCACerts(secret.Namespace, planSecret, ca)
+
 	kubeConfig, err := clientcmd.Write(clientcmdapi.Config{
 		Clusters: map[string]*clientcmdapi.Cluster{
 			"agent": {
@@ -0,0 +1,55 @@
+package planner
+
+import (
+	"fmt"
+	"path"
+
+	rkev1 "github.com/rancher/rancher/pkg/apis/rke.cattle.io/v1"
+	"github.com/rancher/rancher/pkg/apis/rke.cattle.io/v1/plan"
+	"github.com/rancher/rancher/pkg/capr"
+	planapi "github.com/rancher/rancher/pkg/plan"
+)
+
+const (
+	// CertificateExpiryInstructionName is the name of the periodic instruction that prints the certificates of a node.
+	CertificateExpiryInstructionName = "certificate-expiry"
+
+	// CertificateExpiryFileMarker prefixes the line holding the path of each certificate file, relative to the data
+	// directory of the distribution, in the output of the certificate expiry instruction.
+	CertificateExpiryFileMarker = "# "
+
+	defaultCertificateExpiryIntervalSeconds = 3600
+)
+
+// certificateExpiryEnabled returns whether the certificate expiry instruction should be delivered to the machine.
+func certificateExpiryEnabled(controlPlane *rkev1.RKEControlPlane, entry *planEntry) bool {
+	return controlPlane.Spec.CertificateExpiry != nil && controlPlane.Spec.CertificateExpiry.Enabled && !windows(entry)
+}
+
+// addCertificateExpiryPeriodicInstruction adds a periodic instruction that prints the first PEM block of every
+// certificate of the node, preceded by its path relative to the data directory. The certificates are parsed in
+// Rancher, as openssl is not guaranteed to be available on the node.
+func addCertificateExpiryPeriodicInstruction(nodePlan plan.NodePlan, controlPlane *rkev1.RKEControlPlane) plan.NodePlan {
+	interval := controlPlane.Spec.CertificateExpiry.IntervalSeconds
+	if interval == 0 {
+		interval = defaultCertificateExpiryIntervalSeconds
+	}
+
+	dataDir := capr.GetDistroDataDir(controlPlane)
+	script := fmt.Sprintf(`cd %s || exit 0
+for f in server/tls/*.crt server/tls/etcd/*.crt agent/*.crt; do
+  [ -f "$f" ] || continue
+  echo "%s$f"
+  sed -n '1,/-----END CERTIFICATE-----/p' "$f"
+done`, path.Clean(dataDir), CertificateExpiryFileMarker)
+
+	nodePlan.PeriodicInstructions = append(nodePlan.PeriodicInstructions, plan.PeriodicInstruction{
+		CommonInstruction: planapi.CommonInstruction{
+			Name:    CertificateExpiryInstructionName,
+			Command: "sh",
+			Args:    []string{"-c", script},
+		},
+		PeriodSeconds: interval,
+	})
+	return nodePlan
+}
@@ -0,0 +1,43 @@
+package planner
+
+import (
+	"testing"
+
+	rkev1 "github.com/rancher/rancher/pkg/apis/rke.cattle.io/v1"
+	"github.com/rancher/rancher/pkg/apis/rke.cattle.io/v1/plan"
+	"github.com/rancher/rancher/pkg/capr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddCertificateExpiryPeriodicInstruction(t *testing.T) {
+	controlPlane := &rkev1.RKEControlPlane{}
+	controlPlane.Spec.KubernetesVersion = "v1.30.4+rke2r1"
+	controlPlane.Spec.CertificateExpiry = &rkev1.CertificateExpiry{Enabled: true}
+
+	nodePlan := addCertificateExpiryPeriodicInstruction(plan.NodePlan{}, controlPlane)
+	require.Len(t, nodePlan.PeriodicInstructions, 1)
+	instruction := nodePlan.PeriodicInstructions[0]
+	assert.Equal(t, CertificateExpiryInstructionName, instruction.Name)
+	assert.Equal(t, defaultCertificateExpiryIntervalSeconds, instruction.PeriodSeconds)
+	require.Len(t, instruction.Args, 2)
+	assert.Contains(t, instruction.Args[1], "cd /var/lib/rancher/rke2 || exit 0")
+
+	controlPlane.Spec.CertificateExpiry.IntervalSeconds = 600
+	controlPlane.Spec.DataDirectories.K8sDistro = "/opt/k3s/"
+	nodePlan = addCertificateExpiryPeriodicInstruction(plan.NodePlan{}, controlPlane)
+	assert.Equal(t, 600, nodePlan.PeriodicInstructions[0].PeriodSeconds)
+	assert.Contains(t, nodePlan.PeriodicInstructions[0].Args[1], "cd /opt/k3s || exit 0")
+}
+
+func TestCertificateExpiryEnabled(t *testing.T) {
+	controlPlane := &rkev1.RKEControlPlane{}
+	linux := &planEntry{Metadata: &plan.Metadata{Labels: map[string]string{}}}
+	assert.False(t, certificateExpiryEnabled(controlPlane, linux))
+
+	controlPlane.Spec.CertificateExpiry = &rkev1.CertificateExpiry{Enabled: true}
+	assert.True(t, certificateExpiryEnabled(controlPlane, linux))
+
+	windows := &planEntry{Metadata: &plan.Metadata{Labels: map[string]string{capr.CattleOSLabel: capr.WindowsMachineOS}}}
+	assert.False(t, certificateExpiryEnabled(controlPlane, windows))
+}
@@ -119,3 +119,23 @@ func checkForDrainError(entry *planEntry, drainStep string) error {
 	}
 	return nil
 }
+
+// drainHookMessage returns a message describing the webhook drain hooks of the entry that are waiting to be called
+// again, so that the results of the hooks are visible in the conditions of the machine. It returns an empty string if
+// no webhook is being retried.
+func drainHookMessage(entry *planEntry) string {
+	status := capr.GetDrainHookStatus(entry.Metadata.Annotations)
+
+	var messages []string
+	for _, result := range status.Results {
+		if result.Decision != rkev1.DrainWebhookDecisionRetry {
+			continue
+		}
+		msg := fmt.Sprintf("waiting for %s webhook %s (attempt %d)", result.Stage, result.URL, result.Attempts)
+		if result.Message != "" {
+			msg += ": " + result.Message
+		}
+		messages = append(messages, msg)
+	}
+	return strings.Join(messages, ", ")
+}
@@ -0,0 +1,60 @@
+package planner
+
+import (
+	"encoding/json"
+	"testing"
+
+	rkev1 "github.com/rancher/rancher/pkg/apis/rke.cattle.io/v1"
+	"github.com/rancher/rancher/pkg/apis/rke.cattle.io/v1/plan"
+	"github.com/rancher/rancher/pkg/capr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDrainHookMessage(t *testing.T) {
+	const drainOptions = `{"enabled":true}`
+
+	currentHash := capr.GetDrainHookStatus(map[string]string{capr.DrainAnnotation: drainOptions}).DrainOptionsHash
+	status := func(hash string) string {
+		data, err := json.Marshal(capr.DrainHookStatus{
+			DrainOptionsHash: hash,
+			Results: []capr.DrainWebhookResult{
+				{Stage: "pre-drain", Index: 0, URL: "https://lb.example.com/evacuate", Decision: rkev1.DrainWebhookDecisionProceed, Attempts: 1},
+				{Stage: "pre-drain", Index: 1, URL: "https://pager.example.com/drain", Decision: rkev1.DrainWebhookDecisionRetry, Message: "on-call has not acknowledged", Attempts: 3},
+			},
+		})
+		require.NoError(t, err)
+		return string(data)
+	}
+
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		expected    string
+	}{
+		{
+			name:        "no status",
+			annotations: map[string]string{capr.DrainAnnotation: drainOptions},
+		},
+		{
+			name:        "retrying webhook",
+			annotations: map[string]string{capr.DrainAnnotation: drainOptions, capr.DrainHookStatusAnnotation: status(currentHash)},
+			expected:    "waiting for pre-drain webhook https://pager.example.com/drain (attempt 3): on-call has not acknowledged",
+		},
+		{
+			name:        "status of previous drain options",
+			annotations: map[string]string{capr.DrainAnnotation: drainOptions, capr.DrainHookStatusAnnotation: status("stale")},
+		},
+		{
+			name:        "invalid status",
+			annotations: map[string]string{capr.DrainAnnotation: drainOptions, capr.DrainHookStatusAnnotation: "{"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry := &planEntry{Metadata: &plan.Metadata{Annotations: tt.annotations}}
+			assert.Equal(t, tt.expected, drainHookMessage(entry))
+		})
+	}
+}
@@ -968,6 +968,8 @@ func (p *Planner) reconcile(controlPlane *rkev1.RKEControlPlane, cluster *capi.C
 					draining = append(draining, r.entry.Machine.Name)
 					if err != nil {
 						messages[r.entry.Machine.Name] = append(messages[r.entry.Machine.Name], err.Error())
+					} else if msg := drainHookMessage(r.entry); msg != "" {
+						messages[r.entry.Machine.Name] = append(messages[r.entry.Machine.Name], msg)
 					} else {
 						messages[r.entry.Machine.Name] = append(messages[r.entry.Machine.Name], "draining node")
 					}
@@ -983,6 +985,8 @@ func (p *Planner) reconcile(controlPlane *rkev1.RKEControlPlane, cluster *capi.C
 			uncordoned = append(uncordoned, r.entry.Machine.Name)
 			if err != nil {
 				messages[r.entry.Machine.Name] = append(messages[r.entry.Machine.Name], err.Error())
+			} else if msg := drainHookMessage(r.entry); msg != "" {
+				messages[r.entry.Machine.Name] = append(messages[r.entry.Machine.Name], msg)
 			} else {
 				messages[r.entry.Machine.Name] = append(messages[r.entry.Machine.Name], "waiting for uncordon to finish")
 			}
@@ -1134,6 +1138,10 @@ func (p *Planner) desiredPlan(controlPlane *rkev1.RKEControlPlane, cluster *capi
 		}
 	}
 
+	if certificateExpiryEnabled(controlPlane, entry) {
+		nodePlan = addCertificateExpiryPeriodicInstruction(nodePlan, controlPlane)
+	}
+
 	if windows(entry) {
 		// We need to wait for the controlPlane to be ready before sending this plan
 		// to ensure that the initial installation has fully completed
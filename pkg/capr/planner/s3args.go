@@ -1,6 +1,7 @@
 package planner
 
 import (
+	"context"
 	"encoding/base64"
 	"fmt"
 	"strings"
@@ -8,6 +9,7 @@
 	rkev1 "github.com/rancher/rancher/pkg/apis/rke.cattle.io/v1"
 	"github.com/rancher/rancher/pkg/apis/rke.cattle.io/v1/plan"
 	"github.com/rancher/rancher/pkg/controllers/capr/machineprovision"
+	"github.com/rancher/rancher/pkg/secretbackend"
 	corecontrollers "github.com/rancher/wrangler/v3/pkg/generated/controllers/core/v1"
 	"github.com/rancher/wrangler/v3/pkg/kv"
 	"github.com/rancher/wrangler/v3/pkg/name"
@@ -172,8 +174,13 @@ func getS3Credential(secretCache corecontrollers.SecretCache, namespace, name st
 		return result, fmt.Errorf("failed to lookup etcdSnapshotCloudCredentialName: %w", err)
 	}
 
+	secretData, err := secretbackend.Data(context.TODO(), secret)
+	if err != nil {
+		return result, fmt.Errorf("failed to lookup etcdSnapshotCloudCredentialName: %w", err)
+	}
+
 	data := map[string][]byte{}
-	for k, v := range secret.Data {
+	for k, v := range secretData {
 		_, k = kv.RSplit(k, "-")
 		data[k] = v
 	}
@@ -46,7 +46,9 @@
 	ClusterFieldDesiredAssetsImage                                   = "desiredAssetsImage"
 	ClusterFieldDesiredAuthImage                                     = "desiredAuthImage"
 	ClusterFieldDockerRootDir                                        = "dockerRootDir"
+	ClusterFieldDrift                                                = "drift"
 	ClusterFieldDriver                                               = "driver"
+	ClusterFieldDriverProgress                                       = "driverProgress"
 	ClusterFieldEKSConfig                                            = "eksConfig"
 	ClusterFieldEKSStatus                                            = "eksStatus"
 	ClusterFieldEnableNetworkPolicy                                  = "enableNetworkPolicy"
@@ -132,7 +134,9 @@ type Cluster struct {
 	DesiredAssetsImage                                   string                          `json:"desiredAssetsImage,omitempty" yaml:"desiredAssetsImage,omitempty"`
 	DesiredAuthImage                                     string                          `json:"desiredAuthImage,omitempty" yaml:"desiredAuthImage,omitempty"`
 	DockerRootDir                                        string                          `json:"dockerRootDir,omitempty" yaml:"dockerRootDir,omitempty"`
+	Drift                                                *ClusterDriftStatus             `json:"drift,omitempty" yaml:"drift,omitempty"`
 	Driver                                               string                          `json:"driver,omitempty" yaml:"driver,omitempty"`
+	DriverProgress                                       *ClusterDriverProgress          `json:"driverProgress,omitempty" yaml:"driverProgress,omitempty"`
 	EKSConfig                                            *EKSClusterConfigSpec           `json:"eksConfig,omitempty" yaml:"eksConfig,omitempty"`
 	EKSStatus                                            *EKSStatus                      `json:"eksStatus,omitempty" yaml:"eksStatus,omitempty"`
 	EnableNetworkPolicy                                  *bool                           `json:"enableNetworkPolicy,omitempty" yaml:"enableNetworkPolicy,omitempty"`
@@ -0,0 +1,12 @@
+package client
+
+const (
+	ClusterDriftStatusType                = "clusterDriftStatus"
+	ClusterDriftStatusFieldLastUpdateTime = "lastUpdateTime"
+	ClusterDriftStatusFieldObjects        = "objects"
+)
+
+type ClusterDriftStatus struct {
+	LastUpdateTime string                 `json:"lastUpdateTime,omitempty" yaml:"lastUpdateTime,omitempty"`
+	Objects        []ClusterDriftedObject `json:"objects,omitempty" yaml:"objects,omitempty"`
+}
@@ -0,0 +1,20 @@
+package client
+
+const (
+	ClusterDriftedObjectType           = "clusterDriftedObject"
+	ClusterDriftedObjectFieldKind      = "kind"
+	ClusterDriftedObjectFieldMessage   = "message"
+	ClusterDriftedObjectFieldName      = "name"
+	ClusterDriftedObjectFieldNamespace = "namespace"
+	ClusterDriftedObjectFieldReason    = "reason"
+	ClusterDriftedObjectFieldRestored  = "restored"
+)
+
+type ClusterDriftedObject struct {
+	Kind      string `json:"kind,omitempty" yaml:"kind,omitempty"`
+	Message   string `json:"message,omitempty" yaml:"message,omitempty"`
+	Name      string `json:"name,omitempty" yaml:"name,omitempty"`
+	Namespace string `json:"namespace,omitempty" yaml:"namespace,omitempty"`
+	Reason    string `json:"reason,omitempty" yaml:"reason,omitempty"`
+	Restored  bool   `json:"restored,omitempty" yaml:"restored,omitempty"`
+}
@@ -0,0 +1,22 @@
+package client
+
+const (
+	ClusterDriverProgressType                = "clusterDriverProgress"
+	ClusterDriverProgressFieldLastUpdateTime = "lastUpdateTime"
+	ClusterDriverProgressFieldMessage        = "message"
+	ClusterDriverProgressFieldPercent        = "percent"
+	ClusterDriverProgressFieldPhase          = "phase"
+	ClusterDriverProgressFieldResources      = "resources"
+	ClusterDriverProgressFieldStep           = "step"
+	ClusterDriverProgressFieldWarning        = "warning"
+)
+
+type ClusterDriverProgress struct {
+	LastUpdateTime string                  `json:"lastUpdateTime,omitempty" yaml:"lastUpdateTime,omitempty"`
+	Message        string                  `json:"message,omitempty" yaml:"message,omitempty"`
+	Percent        int64                   `json:"percent,omitempty" yaml:"percent,omitempty"`
+	Phase          string                  `json:"phase,omitempty" yaml:"phase,omitempty"`
+	Resources      []ClusterDriverResource `json:"resources,omitempty" yaml:"resources,omitempty"`
+	Step           string                  `json:"step,omitempty" yaml:"step,omitempty"`
+	Warning        bool                    `json:"warning,omitempty" yaml:"warning,omitempty"`
+}
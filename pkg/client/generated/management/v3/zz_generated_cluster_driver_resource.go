@@ -0,0 +1,12 @@
+package client
+
+const (
+	ClusterDriverResourceType      = "clusterDriverResource"
+	ClusterDriverResourceFieldID   = "id"
+	ClusterDriverResourceFieldType = "type"
+)
+
+type ClusterDriverResource struct {
+	ID   string `json:"id,omitempty" yaml:"id,omitempty"`
+	Type string `json:"type,omitempty" yaml:"type,omitempty"`
+}
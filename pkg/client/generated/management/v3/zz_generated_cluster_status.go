@@ -6,6 +6,7 @@
 	ClusterStatusFieldAADClientSecret                            = "aadClientSecret"
 	ClusterStatusFieldAKSStatus                                  = "aksStatus"
 	ClusterStatusFieldAPIEndpoint                                = "apiEndpoint"
+	ClusterStatusFieldAgentCACertsChecksum                       = "agentCACertsChecksum"
 	ClusterStatusFieldAgentFeatures                              = "agentFeatures"
 	ClusterStatusFieldAgentImage                                 = "agentImage"
 	ClusterStatusFieldAliStatus                                  = "aliStatus"
@@ -25,7 +26,9 @@
 	ClusterStatusFieldComponentStatuses                          = "componentStatuses"
 	ClusterStatusFieldConditions                                 = "conditions"
 	ClusterStatusFieldCurrentCisRunName                          = "currentCisRunName"
+	ClusterStatusFieldDrift                                      = "drift"
 	ClusterStatusFieldDriver                                     = "driver"
+	ClusterStatusFieldDriverProgress                             = "driverProgress"
 	ClusterStatusFieldEKSStatus                                  = "eksStatus"
 	ClusterStatusFieldFailedSpec                                 = "failedSpec"
 	ClusterStatusFieldGKEStatus                                  = "gkeStatus"
@@ -54,6 +57,7 @@ type ClusterStatus struct {
 	AADClientSecret                            string                          `json:"aadClientSecret,omitempty" yaml:"aadClientSecret,omitempty"`
 	AKSStatus                                  *AKSStatus                      `json:"aksStatus,omitempty" yaml:"aksStatus,omitempty"`
 	APIEndpoint                                string                          `json:"apiEndpoint,omitempty" yaml:"apiEndpoint,omitempty"`
+	AgentCACertsChecksum                       string                          `json:"agentCACertsChecksum,omitempty" yaml:"agentCACertsChecksum,omitempty"`
 	AgentFeatures                              map[string]bool                 `json:"agentFeatures,omitempty" yaml:"agentFeatures,omitempty"`
 	AgentImage                                 string                          `json:"agentImage,omitempty" yaml:"agentImage,omitempty"`
 	AliStatus                                  *AliStatus                      `json:"aliStatus,omitempty" yaml:"aliStatus,omitempty"`
@@ -73,7 +77,9 @@ type ClusterStatus struct {
 	ComponentStatuses                          []ClusterComponentStatus        `json:"componentStatuses,omitempty" yaml:"componentStatuses,omitempty"`
 	Conditions                                 []ClusterCondition              `json:"conditions,omitempty" yaml:"conditions,omitempty"`
 	CurrentCisRunName                          string                          `json:"currentCisRunName,omitempty" yaml:"currentCisRunName,omitempty"`
+	Drift                                      *ClusterDriftStatus             `json:"drift,omitempty" yaml:"drift,omitempty"`
 	Driver                                     string                          `json:"driver,omitempty" yaml:"driver,omitempty"`
+	DriverProgress                             *ClusterDriverProgress          `json:"driverProgress,omitempty" yaml:"driverProgress,omitempty"`
 	EKSStatus                                  *EKSStatus                      `json:"eksStatus,omitempty" yaml:"eksStatus,omitempty"`
 	FailedSpec                                 *ClusterSpec                    `json:"failedSpec,omitempty" yaml:"failedSpec,omitempty"`
 	GKEStatus                                  *GKEStatus                      `json:"gkeStatus,omitempty" yaml:"gkeStatus,omitempty"`
@@ -0,0 +1,104 @@
+package clusterprovisioninglogger
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	mgmtcontrollers "github.com/rancher/rancher/pkg/generated/controllers/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/settings"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// Recorder records the provisioning timeline of clusters as ProvisioningEvents in the namespace of the management
+// cluster.
+type Recorder struct {
+	events     mgmtcontrollers.ProvisioningEventClient
+	eventCache mgmtcontrollers.ProvisioningEventCache
+	now        func() time.Time
+}
+
+func NewRecorder(events mgmtcontrollers.ProvisioningEventController) *Recorder {
+	return &Recorder{
+		events:     events,
+		eventCache: events.Cache(),
+		now:        time.Now,
+	}
+}
+
+// EventNamePrefix returns the prefix of the name of an event recorded at the given time. Names sort in the order the
+// events were recorded in, so that the API server lists them chronologically.
+func EventNamePrefix(t time.Time) string {
+	return fmt.Sprintf("%016x-", t.UnixNano())
+}
+
+// Record appends the event to the timeline of the cluster, unless it repeats the latest event recorded by the same
+// source for the same machine. The oldest events of the cluster are deleted once it holds more than the
+// provisioning-event-limit setting.
+func (r *Recorder) Record(clusterName string, spec v3.ProvisioningEventSpec) error {
+	if r == nil || clusterName == "" || spec.Message == "" {
+		return nil
+	}
+	if spec.Severity == "" {
+		spec.Severity = v3.ProvisioningEventSeverityInfo
+	}
+
+	events, err := r.eventCache.List(clusterName, labels.SelectorFromSet(labels.Set{
+		v3.ProvisioningEventSourceLabel: string(spec.Source),
+	}))
+	if err != nil {
+		return err
+	}
+	var latest *v3.ProvisioningEvent
+	for _, event := range events {
+		if event.Spec.MachineName == spec.MachineName && (latest == nil || event.Name > latest.Name) {
+			latest = event
+		}
+	}
+	if latest != nil && latest.Spec.Phase == spec.Phase && latest.Spec.Severity == spec.Severity && latest.Spec.Message == spec.Message {
+		return nil
+	}
+
+	now := r.now()
+	spec.ClusterName = clusterName
+	spec.Timestamp = metav1.NewTime(now)
+	_, err = r.events.Create(&v3.ProvisioningEvent{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: EventNamePrefix(now),
+			Namespace:    clusterName,
+			Labels: map[string]string{
+				v3.ProvisioningEventSourceLabel:   string(spec.Source),
+				v3.ProvisioningEventSeverityLabel: string(spec.Severity),
+			},
+		},
+		Spec: spec,
+	})
+	if err != nil {
+		return fmt.Errorf("recording provisioning event for cluster %s: %w", clusterName, err)
+	}
+	return r.trim(clusterName)
+}
+
+// trim deletes the oldest events of the cluster beyond the provisioning-event-limit setting.
+func (r *Recorder) trim(clusterName string) error {
+	limit := settings.ProvisioningEventLimit.GetInt()
+	if limit <= 0 {
+		return nil
+	}
+	events, err := r.eventCache.List(clusterName, labels.Everything())
+	if err != nil || len(events) <= limit {
+		return err
+	}
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].Name < events[j].Name
+	})
+	for _, event := range events[:len(events)-limit] {
+		if err := r.events.Delete(event.Namespace, event.Name, &metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
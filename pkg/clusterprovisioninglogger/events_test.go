@@ -0,0 +1,126 @@
+package clusterprovisioninglogger
+
+import (
+	"testing"
+	"time"
+
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/settings"
+	"github.com/rancher/wrangler/v3/pkg/generic/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+func TestEventNamePrefix(t *testing.T) {
+	earlier := EventNamePrefix(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	later := EventNamePrefix(time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC))
+	assert.Less(t, earlier, later, "names should sort in the order events were recorded in")
+	assert.Len(t, earlier, 17)
+}
+
+func TestRecord(t *testing.T) {
+	now := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	event := func(name, machine, message string) *v3.ProvisioningEvent {
+		return &v3.ProvisioningEvent{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "c-m-abc",
+				Labels:    map[string]string{v3.ProvisioningEventSourceLabel: string(v3.ProvisioningEventSourceMachineProvision)},
+			},
+			Spec: v3.ProvisioningEventSpec{
+				MachineName: machine,
+				Phase:       "Create",
+				Severity:    v3.ProvisioningEventSeverityInfo,
+				Source:      v3.ProvisioningEventSourceMachineProvision,
+				Message:     message,
+			},
+		}
+	}
+	spec := v3.ProvisioningEventSpec{
+		MachineName: "pool-a",
+		Phase:       "Create",
+		Source:      v3.ProvisioningEventSourceMachineProvision,
+		Message:     "creating server",
+	}
+
+	tests := []struct {
+		name     string
+		existing []*v3.ProvisioningEvent
+		limit    string
+		created  bool
+		deleted  []string
+	}{
+		{
+			name:    "first event",
+			created: true,
+		},
+		{
+			name:     "repeats the latest event",
+			existing: []*v3.ProvisioningEvent{event("a", "pool-a", "waiting"), event("b", "pool-a", "creating server")},
+		},
+		{
+			name:     "repeats an earlier event",
+			existing: []*v3.ProvisioningEvent{event("a", "pool-a", "creating server"), event("b", "pool-a", "waiting")},
+			created:  true,
+		},
+		{
+			name:     "repeats the latest event of another machine",
+			existing: []*v3.ProvisioningEvent{event("a", "pool-b", "creating server")},
+			created:  true,
+		},
+		{
+			name:     "limit exceeded",
+			existing: []*v3.ProvisioningEvent{event("c", "pool-b", "c"), event("a", "pool-b", "a"), event("b", "pool-b", "b")},
+			limit:    "1",
+			created:  true,
+			deleted:  []string{"a", "b"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.limit != "" {
+				require.NoError(t, settings.ProvisioningEventLimit.Set(tt.limit))
+				t.Cleanup(func() { _ = settings.ProvisioningEventLimit.Set(settings.ProvisioningEventLimit.Default) })
+			}
+
+			ctrl := gomock.NewController(t)
+			events := fake.NewMockControllerInterface[*v3.ProvisioningEvent, *v3.ProvisioningEventList](ctrl)
+			cache := fake.NewMockCacheInterface[*v3.ProvisioningEvent](ctrl)
+			cache.EXPECT().List("c-m-abc", gomock.Any()).DoAndReturn(func(_ string, selector labels.Selector) ([]*v3.ProvisioningEvent, error) {
+				var result []*v3.ProvisioningEvent
+				for _, event := range tt.existing {
+					if selector.Matches(labels.Set(event.Labels)) {
+						result = append(result, event)
+					}
+				}
+				return result, nil
+			}).AnyTimes()
+			if tt.created {
+				events.EXPECT().Create(gomock.Any()).DoAndReturn(func(event *v3.ProvisioningEvent) (*v3.ProvisioningEvent, error) {
+					assert.Equal(t, EventNamePrefix(now), event.GenerateName)
+					assert.Equal(t, "c-m-abc", event.Namespace)
+					assert.Equal(t, "machineprovision", event.Labels[v3.ProvisioningEventSourceLabel])
+					assert.Equal(t, "Info", event.Labels[v3.ProvisioningEventSeverityLabel])
+					assert.Equal(t, "c-m-abc", event.Spec.ClusterName)
+					assert.Equal(t, metav1.NewTime(now), event.Spec.Timestamp)
+					return event, nil
+				})
+			}
+			for _, name := range tt.deleted {
+				events.EXPECT().Delete("c-m-abc", name, gomock.Any()).Return(nil)
+			}
+
+			r := &Recorder{events: events, eventCache: cache, now: func() time.Time { return now }}
+			require.NoError(t, r.Record("c-m-abc", spec))
+		})
+	}
+}
+
+func TestRecordNilRecorder(t *testing.T) {
+	var r *Recorder
+	assert.NoError(t, r.Record("c-m-abc", v3.ProvisioningEventSpec{Message: "message"}))
+}
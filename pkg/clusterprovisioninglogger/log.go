@@ -3,19 +3,23 @@
 import (
 	"bytes"
 	"context"
+	"fmt"
 	"io"
 	"sync"
 	"time"
 
 	"github.com/rancher/norman/condition"
+	apimgmtv3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
 	v1 "github.com/rancher/rancher/pkg/generated/norman/core/v1"
 	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
 	"github.com/rancher/rancher/pkg/kontainer-engine/logstream"
+	v2 "github.com/rancher/rancher/pkg/kontainer-engine/types/v2"
 	"github.com/sirupsen/logrus"
 	"google.golang.org/grpc/metadata"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	v12 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
 )
 
 const (
@@ -24,16 +28,23 @@
 
 type logger struct {
 	Cluster    *v3.Cluster
+	Clusters   v3.ClusterInterface
 	ConfigMaps v1.ConfigMapInterface
+	Events     *Recorder
 	done       chan struct{}
 	buffer     bytes.Buffer
 	bufferLock sync.Mutex
 }
 
-func NewLogger(configMaps v1.ConfigMapInterface, cluster *v3.Cluster, cond condition.Cond) (context.Context, io.Closer) {
+// NewLogger returns a context on which the log and the progress of the kontainer driver calls are saved to the
+// provisioning log of the cluster and recorded as provisioning events. The latest progress is also reported on the
+// status of the cluster.
+func NewLogger(clusters v3.ClusterInterface, configMaps v1.ConfigMapInterface, events *Recorder, cluster *v3.Cluster, cond condition.Cond) (context.Context, io.Closer) {
 	l := &logger{
 		Cluster:    cluster,
+		Clusters:   clusters,
 		ConfigMaps: configMaps,
+		Events:     events,
 		done:       make(chan struct{}),
 	}
 
@@ -109,15 +120,101 @@ func (p *logger) logEvent(cluster *v3.Cluster, event logstream.LogEvent, cond co
 	}
 	p.buffer.WriteString(event.Message)
 	p.buffer.WriteString("\n")
+
+	severity := apimgmtv3.ProvisioningEventSeverityInfo
+	if event.Error {
+		severity = apimgmtv3.ProvisioningEventSeverityError
+	}
+	if err := p.Events.Record(cluster.Name, apimgmtv3.ProvisioningEventSpec{
+		Phase:    phase(cond),
+		Severity: severity,
+		Source:   apimgmtv3.ProvisioningEventSourceClusterDriver,
+		Message:  event.Message,
+	}); err != nil {
+		logrus.Errorf("Failed to record provisioning event for cluster [%s]: %v", cluster.Name, err)
+	}
 	return cluster
 }
 
+// progressEvent records the progress reported by the driver as a provisioning event, and reports it on the status of
+// the cluster.
+func (p *logger) progressEvent(progress *v2.Progress, cond condition.Cond) {
+	message := fmt.Sprintf("[%d%%] %s", progress.Percent, progress.Step)
+	if progress.Message != "" {
+		message += ": " + progress.Message
+	}
+	p.bufferLock.Lock()
+	p.buffer.WriteString(time.Now().Format(time.RFC3339))
+	if progress.Warning {
+		p.buffer.WriteString(" [WARN ] ")
+	} else {
+		p.buffer.WriteString(" [INFO ] ")
+	}
+	p.buffer.WriteString(message)
+	p.buffer.WriteString("\n")
+	p.bufferLock.Unlock()
+
+	severity := apimgmtv3.ProvisioningEventSeverityInfo
+	if progress.Warning {
+		severity = apimgmtv3.ProvisioningEventSeverityWarning
+	}
+	if err := p.Events.Record(p.Cluster.Name, apimgmtv3.ProvisioningEventSpec{
+		Phase:    phase(cond),
+		Severity: severity,
+		Source:   apimgmtv3.ProvisioningEventSourceClusterDriver,
+		Message:  message,
+	}); err != nil {
+		logrus.Errorf("Failed to record provisioning event for cluster [%s]: %v", p.Cluster.Name, err)
+	}
+
+	status := &apimgmtv3.ClusterDriverProgress{
+		Phase:          phase(cond),
+		Step:           progress.Step,
+		Percent:        int(progress.Percent),
+		Message:        progress.Message,
+		Warning:        progress.Warning,
+		LastUpdateTime: time.Now().UTC().Format(time.RFC3339),
+	}
+	for _, resource := range progress.Resources {
+		status.Resources = append(status.Resources, apimgmtv3.ClusterDriverResource{Type: resource.Type, ID: resource.Id})
+	}
+	if err := p.setProgress(status); err != nil {
+		logrus.Errorf("Failed to report driver progress of cluster [%s]: %v", p.Cluster.Name, err)
+	}
+}
+
+func (p *logger) setProgress(progress *apimgmtv3.ClusterDriverProgress) error {
+	if p.Clusters == nil {
+		return nil
+	}
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		cluster, err := p.Clusters.Get(p.Cluster.Name, v12.GetOptions{})
+		if err != nil {
+			return err
+		}
+		cluster = cluster.DeepCopy()
+		cluster.Status.DriverProgress = progress
+		_, err = p.Clusters.UpdateStatus(cluster)
+		return err
+	})
+}
+
+func phase(cond condition.Cond) string {
+	if cond == apimgmtv3.ClusterConditionUpdated {
+		return "Updating"
+	}
+	return "Provisioning"
+}
+
 func (p *logger) getCtx(cluster *v3.Cluster, cond condition.Cond) (string, context.Context, io.Closer) {
 	logger := logstream.NewLogStream()
 	logID := logger.ID()
 	ctx := metadata.NewOutgoingContext(context.Background(), metadata.New(map[string]string{
 		"log-id": logID,
 	}))
+	ctx = v2.SetProgress(ctx, func(progress *v2.Progress) {
+		p.progressEvent(progress, cond)
+	})
 	wg := sync.WaitGroup{}
 	wg.Add(1)
 
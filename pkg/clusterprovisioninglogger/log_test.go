@@ -0,0 +1,70 @@
+package clusterprovisioninglogger
+
+import (
+	"testing"
+	"time"
+
+	apimgmtv3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3/fakes"
+	v2 "github.com/rancher/rancher/pkg/kontainer-engine/types/v2"
+	"github.com/rancher/wrangler/v3/pkg/generic/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestProgressEvent(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	events := fake.NewMockControllerInterface[*apimgmtv3.ProvisioningEvent, *apimgmtv3.ProvisioningEventList](ctrl)
+	cache := fake.NewMockCacheInterface[*apimgmtv3.ProvisioningEvent](ctrl)
+	cache.EXPECT().List("c-abc", gomock.Any()).Return(nil, nil).AnyTimes()
+	events.EXPECT().Create(gomock.Any()).DoAndReturn(func(event *apimgmtv3.ProvisioningEvent) (*apimgmtv3.ProvisioningEvent, error) {
+		assert.Equal(t, "Updating", event.Spec.Phase)
+		assert.Equal(t, apimgmtv3.ProvisioningEventSeverityWarning, event.Spec.Severity)
+		assert.Equal(t, apimgmtv3.ProvisioningEventSourceClusterDriver, event.Spec.Source)
+		assert.Equal(t, "[60%] Upgrading node pools: node pool np-1 is being drained", event.Spec.Message)
+		return event, nil
+	})
+
+	cluster := &apimgmtv3.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "c-abc"}}
+	var updated *apimgmtv3.Cluster
+	clusters := &fakes.ClusterInterfaceMock{
+		GetFunc: func(name string, opts metav1.GetOptions) (*apimgmtv3.Cluster, error) {
+			return cluster, nil
+		},
+		UpdateFunc: func(cluster *apimgmtv3.Cluster) (*apimgmtv3.Cluster, error) {
+			// The status subresource of clusters ignores status changes made with Update.
+			t.Error("driver progress must be saved with UpdateStatus")
+			return cluster, nil
+		},
+		UpdateStatusFunc: func(cluster *apimgmtv3.Cluster) (*apimgmtv3.Cluster, error) {
+			updated = cluster
+			return cluster, nil
+		},
+	}
+
+	l := &logger{
+		Cluster:  cluster,
+		Clusters: clusters,
+		Events:   &Recorder{events: events, eventCache: cache, now: time.Now},
+	}
+	l.progressEvent(&v2.Progress{
+		Step:      "Upgrading node pools",
+		Percent:   60,
+		Message:   "node pool np-1 is being drained",
+		Warning:   true,
+		Resources: []*v2.Resource{{Type: "NodeGroup", Id: "np-1"}},
+	}, apimgmtv3.ClusterConditionUpdated)
+
+	assert.Contains(t, l.buffer.String(), "[WARN ] [60%] Upgrading node pools: node pool np-1 is being drained")
+	require.NotNil(t, updated)
+	require.NotNil(t, updated.Status.DriverProgress)
+	assert.Nil(t, cluster.Status.DriverProgress, "the cached cluster is not modified")
+	progress := updated.Status.DriverProgress
+	assert.Equal(t, "Updating", progress.Phase)
+	assert.Equal(t, 60, progress.Percent)
+	assert.True(t, progress.Warning)
+	assert.Equal(t, []apimgmtv3.ClusterDriverResource{{Type: "NodeGroup", ID: "np-1"}}, progress.Resources)
+	assert.NotEmpty(t, progress.LastUpdateTime)
+}
@@ -107,6 +107,7 @@ func main() {
 			"fleet.cattle.io": {
 				Types: []interface{}{
 					fleet.Bundle{},
+					fleet.BundleDeployment{},
 					fleet.Cluster{},
 					fleet.ClusterGroup{},
 					fleet.HelmOp{},
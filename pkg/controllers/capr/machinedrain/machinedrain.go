@@ -30,7 +30,7 @@ type handler struct {
 	restMapper   meta.RESTMapper
 	clusterCache capicontrollers.ClusterCache
 	machineCache capicontrollers.MachineCache
-	secrets      corecontrollers.SecretClient
+	secrets      corecontrollers.SecretController
 	secretCache  corecontrollers.SecretCache
 }
 
@@ -150,6 +150,11 @@ func (h *handler) unDrain(secret *corev1.Secret, machine *capi.Machine, drainDat
 		return secret, err
 	}
 
+	secret, ok, err := h.runWebhookHooks(secret, machine, postDrainStage, drainOpts.PostDrainHooks)
+	if err != nil || !ok {
+		return secret, err
+	}
+
 	checkPostDrainHooks := checkHookAnnotations(drainData, drainOpts.PostDrainHooks)
 	if len(drainOpts.PostDrainHooks) > 0 {
 		postDrainAnnDoesNotHaveValue := secretAnnotationDoesNotHaveValue(capr.PostDrainAnnotation, drainData)
@@ -183,6 +188,11 @@ func (h *handler) drain(secret *corev1.Secret, machine *capi.Machine, drainData
 		return secret, err
 	}
 
+	secret, ok, err := h.runWebhookHooks(secret, machine, preDrainStage, drainOpts.PreDrainHooks)
+	if err != nil || !ok {
+		return secret, err
+	}
+
 	checkPreDrainHooks := checkHookAnnotations(drainData, drainOpts.PreDrainHooks)
 	if len(drainOpts.PreDrainHooks) > 0 {
 		preDrainAnnDoesNotHaveValue := secretAnnotationDoesNotHaveValue(capr.PreDrainAnnotation, drainData)
@@ -289,6 +299,7 @@ func (h *handler) cleanSecretAnnotationsIfCheckTrue(secret *corev1.Secret, drain
 		delete(secret.Annotations, capr.PostDrainAnnotation)
 		delete(secret.Annotations, capr.DrainAnnotation)
 		delete(secret.Annotations, capr.DrainDoneAnnotation)
+		delete(secret.Annotations, capr.DrainHookStatusAnnotation)
 		delete(secret.Annotations, capr.UnCordonAnnotation)
 		for _, hook := range drainOpts.PreDrainHooks {
 			delete(secret.Annotations, hook.Annotation)
@@ -0,0 +1,307 @@
+package machinedrain
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	rkev1 "github.com/rancher/rancher/pkg/apis/rke.cattle.io/v1"
+	"github.com/rancher/rancher/pkg/capr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+	capi "sigs.k8s.io/cluster-api/api/core/v1beta2"
+)
+
+const (
+	preDrainStage  = "pre-drain"
+	postDrainStage = "post-drain"
+
+	defaultWebhookTimeout      = 10 * time.Second
+	defaultWebhookRetryTimeout = 600 * time.Second
+	defaultWebhookRetryDelay   = 10 * time.Second
+	maxWebhookRetryDelay       = 10 * time.Minute
+
+	maxWebhookResponseBytes = 64 * 1024
+	maxWebhookMessageLength = 256
+)
+
+// webhookRequest is the body sent to a drain webhook.
+type webhookRequest struct {
+	Stage            string `json:"stage"`
+	ClusterNamespace string `json:"clusterNamespace"`
+	ClusterName      string `json:"clusterName"`
+	MachineName      string `json:"machineName"`
+	NodeName         string `json:"nodeName"`
+	Attempt          int    `json:"attempt"`
+}
+
+// webhookResponse is the body returned by a drain webhook.
+type webhookResponse struct {
+	Decision          rkev1.DrainWebhookDecision `json:"decision"`
+	Message           string                     `json:"message,omitempty"`
+	RetryAfterSeconds int                        `json:"retryAfterSeconds,omitempty"`
+}
+
+// runWebhookHooks calls the webhook drain hooks of the given stage in order and records their results in the
+// DrainHookStatusAnnotation of the machine-plan secret. It returns true once every webhook has decided to proceed. A
+// webhook that asked to be retried is called again once its retry delay has passed, and a webhook that aborted the
+// drain results in an error until the drain options change or the status annotation is removed.
+func (h *handler) runWebhookHooks(secret *corev1.Secret, machine *capi.Machine, stage string, hooks []rkev1.DrainHook) (*corev1.Secret, bool, error) {
+	for i, hook := range hooks {
+		if hook.Webhook == nil {
+			continue
+		}
+
+		status := capr.GetDrainHookStatus(secret.Annotations)
+		result := status.Result(stage, i)
+		now := time.Now()
+
+		if result != nil {
+			switch result.Decision {
+			case rkev1.DrainWebhookDecisionProceed:
+				continue
+			case rkev1.DrainWebhookDecisionAbort:
+				return secret, false, fmt.Errorf("%s webhook %s aborted the drain: %s", stage, result.URL, result.Message)
+			case rkev1.DrainWebhookDecisionRetry:
+				if wait := result.NextAttempt.Sub(now); wait > 0 {
+					h.secrets.EnqueueAfter(secret.Namespace, secret.Name, wait)
+					return secret, false, nil
+				}
+			}
+		}
+
+		resp, err := h.callWebhook(secret, hook.Webhook, webhookRequest{
+			Stage:            stage,
+			ClusterNamespace: machine.Namespace,
+			ClusterName:      machine.Spec.ClusterName,
+			MachineName:      machine.Name,
+			NodeName:         machine.Status.NodeRef.Name,
+			Attempt:          attempts(result) + 1,
+		})
+		next := nextResult(result, hook.Webhook, stage, i, resp, err, now)
+		status.SetResult(next)
+
+		if secret, err = h.updateDrainHookStatus(secret, status); err != nil {
+			return secret, false, err
+		}
+
+		switch next.Decision {
+		case rkev1.DrainWebhookDecisionAbort:
+			return secret, false, fmt.Errorf("%s webhook %s aborted the drain: %s", stage, next.URL, next.Message)
+		case rkev1.DrainWebhookDecisionRetry:
+			h.secrets.EnqueueAfter(secret.Namespace, secret.Name, next.NextAttempt.Sub(now))
+			return secret, false, nil
+		}
+	}
+
+	return secret, true, nil
+}
+
+// nextResult returns the result of a hook after a call to its webhook, given the previous result of the hook if any.
+// A call that failed is retried like a call that returned the Retry decision. A hook that is still retrying after its
+// retry timeout is aborted or proceeds according to its failure policy.
+func nextResult(previous *capr.DrainWebhookResult, webhook *rkev1.DrainWebhook, stage string, index int, resp *webhookResponse, callErr error, now time.Time) capr.DrainWebhookResult {
+	result := capr.DrainWebhookResult{
+		Stage:        stage,
+		Index:        index,
+		URL:          webhook.URL,
+		Attempts:     attempts(previous) + 1,
+		FirstAttempt: metav1.NewTime(now),
+	}
+	if previous != nil {
+		result.FirstAttempt = previous.FirstAttempt
+	}
+
+	retryDelay := defaultWebhookRetryDelay
+	if callErr != nil {
+		result.Decision = rkev1.DrainWebhookDecisionRetry
+		result.Message = callErr.Error()
+	} else {
+		result.Decision = resp.Decision
+		result.Message = resp.Message
+		if resp.RetryAfterSeconds > 0 {
+			retryDelay = min(time.Duration(resp.RetryAfterSeconds)*time.Second, maxWebhookRetryDelay)
+		}
+	}
+	if len(result.Message) > maxWebhookMessageLength {
+		result.Message = result.Message[:maxWebhookMessageLength]
+	}
+
+	if result.Decision != rkev1.DrainWebhookDecisionRetry {
+		return result
+	}
+
+	retryTimeout := defaultWebhookRetryTimeout
+	if webhook.RetryTimeoutSeconds > 0 {
+		retryTimeout = time.Duration(webhook.RetryTimeoutSeconds) * time.Second
+	}
+	if now.Sub(result.FirstAttempt.Time) < retryTimeout {
+		result.NextAttempt = metav1.NewTime(now.Add(retryDelay))
+		return result
+	}
+
+	if webhook.FailurePolicy == rkev1.DrainWebhookFailurePolicyIgnore {
+		result.Decision = rkev1.DrainWebhookDecisionProceed
+		result.Message = fmt.Sprintf("retry timeout of %s exceeded, ignoring: %s", retryTimeout, result.Message)
+	} else {
+		result.Decision = rkev1.DrainWebhookDecisionAbort
+		result.Message = fmt.Sprintf("retry timeout of %s exceeded: %s", retryTimeout, result.Message)
+	}
+	result.NextAttempt = metav1.Time{}
+	return result
+}
+
+func attempts(result *capr.DrainWebhookResult) int {
+	if result == nil {
+		return 0
+	}
+	return result.Attempts
+}
+
+// callWebhook sends the request to the webhook and returns its decision. Any response other than a 2xx status code
+// with an empty body or a valid decision is returned as an error.
+func (h *handler) callWebhook(planSecret *corev1.Secret, webhook *rkev1.DrainWebhook, request webhookRequest) (*webhookResponse, error) {
+	client, err := webhookClient(webhook)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(h.ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	if webhook.AuthSecretName != "" {
+		authSecret, err := h.secretCache.Get(planSecret.Namespace, webhook.AuthSecretName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get auth secret %s/%s: %w", planSecret.Namespace, webhook.AuthSecretName, err)
+		}
+		if err := checkAuthSecret(authSecret, request.ClusterName); err != nil {
+			return nil, err
+		}
+		setAuth(req, authSecret)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxWebhookResponseBytes))
+	if err != nil {
+		return nil, err
+	}
+
+	return parseWebhookResponse(resp.StatusCode, data)
+}
+
+func webhookClient(webhook *rkev1.DrainWebhook) (*http.Client, error) {
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if len(webhook.CABundle) > 0 {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(webhook.CABundle) {
+			return nil, fmt.Errorf("failed to parse the CA bundle of webhook %s", webhook.URL)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	timeout := defaultWebhookTimeout
+	if webhook.TimeoutSeconds > 0 {
+		timeout = time.Duration(webhook.TimeoutSeconds) * time.Second
+	}
+
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			Proxy:           http.ProxyFromEnvironment,
+			TLSClientConfig: tlsConfig,
+		},
+		// The endpoint is called with credentials, which must not be sent anywhere else.
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}, nil
+}
+
+// checkAuthSecret checks that the auth secret is meant to be sent to the drain webhooks of the cluster. The webhook URL
+// is chosen by the cluster's editors, so any other secret of the namespace, such as the cloud credentials and
+// kubeconfigs of other clusters, must not be sent.
+func checkAuthSecret(authSecret *corev1.Secret, clusterName string) error {
+	if authSecret.Type != rkev1.DrainWebhookAuthSecretType {
+		return fmt.Errorf("auth secret %s/%s must be of type %s", authSecret.Namespace, authSecret.Name, rkev1.DrainWebhookAuthSecretType)
+	}
+	if !slices.Contains(strings.Split(authSecret.Annotations[capr.AuthorizedObjectAnnotation], ","), clusterName) {
+		return fmt.Errorf("auth secret %s/%s is not authorized for cluster %s by the %s annotation", authSecret.Namespace, authSecret.Name, clusterName, capr.AuthorizedObjectAnnotation)
+	}
+	return nil
+}
+
+func setAuth(req *http.Request, authSecret *corev1.Secret) {
+	if token := authSecret.Data["token"]; len(token) > 0 {
+		req.Header.Set("Authorization", "Bearer "+string(token))
+	} else if username := authSecret.Data["username"]; len(username) > 0 {
+		req.SetBasicAuth(string(username), string(authSecret.Data["password"]))
+	}
+}
+
+func parseWebhookResponse(statusCode int, data []byte) (*webhookResponse, error) {
+	if statusCode < 200 || statusCode > 299 {
+		return nil, fmt.Errorf("webhook returned status code %d", statusCode)
+	}
+
+	if len(bytes.TrimSpace(data)) == 0 {
+		return &webhookResponse{Decision: rkev1.DrainWebhookDecisionProceed}, nil
+	}
+
+	resp := &webhookResponse{}
+	if err := json.Unmarshal(data, resp); err != nil {
+		return nil, fmt.Errorf("failed to decode webhook response: %w", err)
+	}
+
+	switch resp.Decision {
+	case rkev1.DrainWebhookDecisionProceed, rkev1.DrainWebhookDecisionRetry, rkev1.DrainWebhookDecisionAbort:
+		return resp, nil
+	}
+	return nil, fmt.Errorf("webhook returned unknown decision %q", resp.Decision)
+}
+
+func (h *handler) updateDrainHookStatus(secret *corev1.Secret, status capr.DrainHookStatus) (*corev1.Secret, error) {
+	data, err := json.Marshal(status)
+	if err != nil {
+		return secret, err
+	}
+
+	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		current, err := h.secrets.Get(secret.Namespace, secret.Name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		if current.Annotations[capr.DrainHookStatusAnnotation] == string(data) {
+			secret = current
+			return nil
+		}
+		current = current.DeepCopy()
+		current.Annotations[capr.DrainHookStatusAnnotation] = string(data)
+		if current, err = h.secrets.Update(current); err != nil {
+			return err
+		}
+		secret = current
+		return nil
+	})
+	return secret, err
+}
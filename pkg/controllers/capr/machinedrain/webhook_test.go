@@ -0,0 +1,249 @@
+package machinedrain
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	rkev1 "github.com/rancher/rancher/pkg/apis/rke.cattle.io/v1"
+	"github.com/rancher/rancher/pkg/capr"
+	"github.com/rancher/wrangler/v3/pkg/generic/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestNextResult(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	webhook := &rkev1.DrainWebhook{URL: "https://lb.example.com/evacuate", RetryTimeoutSeconds: 300}
+	ignore := &rkev1.DrainWebhook{URL: "https://lb.example.com/evacuate", RetryTimeoutSeconds: 300, FailurePolicy: rkev1.DrainWebhookFailurePolicyIgnore}
+	retrying := &capr.DrainWebhookResult{
+		Stage:        preDrainStage,
+		URL:          webhook.URL,
+		Decision:     rkev1.DrainWebhookDecisionRetry,
+		Attempts:     2,
+		FirstAttempt: metav1.NewTime(now.Add(-10 * time.Minute)),
+	}
+
+	tests := []struct {
+		name     string
+		previous *capr.DrainWebhookResult
+		webhook  *rkev1.DrainWebhook
+		resp     *webhookResponse
+		err      error
+		want     capr.DrainWebhookResult
+	}{
+		{
+			name:    "proceed",
+			webhook: webhook,
+			resp:    &webhookResponse{Decision: rkev1.DrainWebhookDecisionProceed, Message: "evacuated"},
+			want: capr.DrainWebhookResult{
+				Stage: preDrainStage, URL: webhook.URL, Decision: rkev1.DrainWebhookDecisionProceed, Message: "evacuated",
+				Attempts: 1, FirstAttempt: metav1.NewTime(now),
+			},
+		},
+		{
+			name:    "retry after the returned delay",
+			webhook: webhook,
+			resp:    &webhookResponse{Decision: rkev1.DrainWebhookDecisionRetry, RetryAfterSeconds: 30},
+			want: capr.DrainWebhookResult{
+				Stage: preDrainStage, URL: webhook.URL, Decision: rkev1.DrainWebhookDecisionRetry,
+				Attempts: 1, FirstAttempt: metav1.NewTime(now), NextAttempt: metav1.NewTime(now.Add(30 * time.Second)),
+			},
+		},
+		{
+			name:    "call error is retried",
+			webhook: webhook,
+			err:     errors.New("connection refused"),
+			want: capr.DrainWebhookResult{
+				Stage: preDrainStage, URL: webhook.URL, Decision: rkev1.DrainWebhookDecisionRetry, Message: "connection refused",
+				Attempts: 1, FirstAttempt: metav1.NewTime(now), NextAttempt: metav1.NewTime(now.Add(defaultWebhookRetryDelay)),
+			},
+		},
+		{
+			name:    "abort",
+			webhook: webhook,
+			resp:    &webhookResponse{Decision: rkev1.DrainWebhookDecisionAbort, Message: "node owns the last replica"},
+			want: capr.DrainWebhookResult{
+				Stage: preDrainStage, URL: webhook.URL, Decision: rkev1.DrainWebhookDecisionAbort, Message: "node owns the last replica",
+				Attempts: 1, FirstAttempt: metav1.NewTime(now),
+			},
+		},
+		{
+			name:     "retry timeout exceeded fails",
+			previous: retrying,
+			webhook:  webhook,
+			err:      errors.New("timeout"),
+			want: capr.DrainWebhookResult{
+				Stage: preDrainStage, URL: webhook.URL, Decision: rkev1.DrainWebhookDecisionAbort, Message: "retry timeout of 5m0s exceeded: timeout",
+				Attempts: 3, FirstAttempt: retrying.FirstAttempt,
+			},
+		},
+		{
+			name:     "retry timeout exceeded is ignored",
+			previous: retrying,
+			webhook:  ignore,
+			resp:     &webhookResponse{Decision: rkev1.DrainWebhookDecisionRetry, Message: "busy"},
+			want: capr.DrainWebhookResult{
+				Stage: preDrainStage, URL: webhook.URL, Decision: rkev1.DrainWebhookDecisionProceed, Message: "retry timeout of 5m0s exceeded, ignoring: busy",
+				Attempts: 3, FirstAttempt: retrying.FirstAttempt,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, nextResult(tt.previous, tt.webhook, preDrainStage, 0, tt.resp, tt.err, now))
+		})
+	}
+}
+
+func TestParseWebhookResponse(t *testing.T) {
+	resp, err := parseWebhookResponse(http.StatusNoContent, nil)
+	require.NoError(t, err)
+	assert.Equal(t, rkev1.DrainWebhookDecisionProceed, resp.Decision)
+
+	resp, err = parseWebhookResponse(http.StatusOK, []byte(`{"decision":"Retry","message":"draining connections","retryAfterSeconds":15}`))
+	require.NoError(t, err)
+	assert.Equal(t, &webhookResponse{Decision: rkev1.DrainWebhookDecisionRetry, Message: "draining connections", RetryAfterSeconds: 15}, resp)
+
+	_, err = parseWebhookResponse(http.StatusOK, []byte(`{"decision":"Maybe"}`))
+	assert.EqualError(t, err, `webhook returned unknown decision "Maybe"`)
+
+	_, err = parseWebhookResponse(http.StatusOK, []byte(`not json`))
+	assert.Error(t, err)
+
+	_, err = parseWebhookResponse(http.StatusInternalServerError, []byte(`{"decision":"Proceed"}`))
+	assert.EqualError(t, err, "webhook returned status code 500")
+}
+
+func TestCallWebhook(t *testing.T) {
+	var got webhookRequest
+	var auth string
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		_, _ = w.Write([]byte(`{"decision":"Proceed"}`))
+	}))
+	defer server.Close()
+
+	caBundle := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	request := webhookRequest{Stage: postDrainStage, ClusterNamespace: "fleet-default", ClusterName: "prod", MachineName: "prod-pool1-abc", NodeName: "node-1", Attempt: 1}
+	h := &handler{ctx: context.Background()}
+
+	t.Run("trusted with CA bundle", func(t *testing.T) {
+		resp, err := h.callWebhook(&corev1.Secret{}, &rkev1.DrainWebhook{URL: server.URL, CABundle: caBundle}, request)
+		require.NoError(t, err)
+		assert.Equal(t, rkev1.DrainWebhookDecisionProceed, resp.Decision)
+		assert.Equal(t, request, got)
+		assert.Empty(t, auth)
+	})
+
+	t.Run("untrusted without CA bundle", func(t *testing.T) {
+		_, err := h.callWebhook(&corev1.Secret{}, &rkev1.DrainWebhook{URL: server.URL}, request)
+		assert.Error(t, err)
+	})
+
+	t.Run("invalid CA bundle", func(t *testing.T) {
+		_, err := h.callWebhook(&corev1.Secret{}, &rkev1.DrainWebhook{URL: server.URL, CABundle: []byte("garbage")}, request)
+		assert.ErrorContains(t, err, "failed to parse the CA bundle")
+	})
+
+	t.Run("auth secret", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		secretCache := fake.NewMockCacheInterface[*corev1.Secret](ctrl)
+		secretCache.EXPECT().Get("fleet-default", "drain-auth").Return(&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "drain-auth",
+				Namespace:   "fleet-default",
+				Annotations: map[string]string{capr.AuthorizedObjectAnnotation: "prod"},
+			},
+			Type: rkev1.DrainWebhookAuthSecretType,
+			Data: map[string][]byte{"token": []byte("abc")},
+		}, nil)
+		secretCache.EXPECT().Get("fleet-default", "prod-kubeconfig").Return(&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "prod-kubeconfig", Namespace: "fleet-default"},
+			Data:       map[string][]byte{"token": []byte("kubeconfig-token")},
+		}, nil)
+		h := &handler{ctx: context.Background(), secretCache: secretCache}
+		planSecret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "fleet-default"}}
+
+		_, err := h.callWebhook(planSecret, &rkev1.DrainWebhook{URL: server.URL, CABundle: caBundle, AuthSecretName: "drain-auth"}, request)
+		require.NoError(t, err)
+		assert.Equal(t, "Bearer abc", auth)
+
+		auth = ""
+		_, err = h.callWebhook(planSecret, &rkev1.DrainWebhook{URL: server.URL, CABundle: caBundle, AuthSecretName: "prod-kubeconfig"}, request)
+		assert.EqualError(t, err, "auth secret fleet-default/prod-kubeconfig must be of type rke.cattle.io/drain-webhook-auth")
+		assert.Empty(t, auth)
+	})
+}
+
+func TestCheckAuthSecret(t *testing.T) {
+	tests := []struct {
+		name       string
+		secretType corev1.SecretType
+		authorized string
+		wantErr    string
+	}{
+		{
+			name:       "authorized",
+			secretType: rkev1.DrainWebhookAuthSecretType,
+			authorized: "dev,prod",
+		},
+		{
+			name:       "wrong type",
+			secretType: corev1.SecretTypeOpaque,
+			authorized: "prod",
+			wantErr:    "auth secret fleet-default/drain-auth must be of type rke.cattle.io/drain-webhook-auth",
+		},
+		{
+			name:       "not authorized",
+			secretType: rkev1.DrainWebhookAuthSecretType,
+			authorized: "dev",
+			wantErr:    "auth secret fleet-default/drain-auth is not authorized for cluster prod by the rke.cattle.io/object-authorized-for-clusters annotation",
+		},
+		{
+			name:       "no annotation",
+			secretType: rkev1.DrainWebhookAuthSecretType,
+			wantErr:    "auth secret fleet-default/drain-auth is not authorized for cluster prod by the rke.cattle.io/object-authorized-for-clusters annotation",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "drain-auth", Namespace: "fleet-default"},
+				Type:       tt.secretType,
+			}
+			if tt.authorized != "" {
+				secret.Annotations = map[string]string{capr.AuthorizedObjectAnnotation: tt.authorized}
+			}
+			err := checkAuthSecret(secret, "prod")
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestSetAuth(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "https://lb.example.com", nil)
+	setAuth(req, &corev1.Secret{Data: map[string][]byte{"token": []byte("abc")}})
+	assert.Equal(t, "Bearer abc", req.Header.Get("Authorization"))
+
+	req = httptest.NewRequest(http.MethodPost, "https://lb.example.com", nil)
+	setAuth(req, &corev1.Secret{Data: map[string][]byte{"username": []byte("user"), "password": []byte("pass")}})
+	username, password, ok := req.BasicAuth()
+	assert.True(t, ok)
+	assert.Equal(t, "user", username)
+	assert.Equal(t, "pass", password)
+}
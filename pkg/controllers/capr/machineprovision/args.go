@@ -1,6 +1,7 @@
 package machineprovision
 
 import (
+	"context"
 	"crypto/sha256"
 	"encoding/hex"
 	"fmt"
@@ -36,6 +37,7 @@
 	"github.com/rancher/rancher/pkg/capr"
 	"github.com/rancher/rancher/pkg/controllers/management/drivers"
 	"github.com/rancher/rancher/pkg/namespace"
+	"github.com/rancher/rancher/pkg/secretbackend"
 	"github.com/rancher/rancher/pkg/settings"
 )
 
@@ -253,8 +255,12 @@ func (h *handler) getSecretData(machine *capi.Machine, obj data.Object, create b
 		if err != nil {
 			return "", "", nil, err
 		}
+		credentialData, err := secretbackend.Data(context.TODO(), secret)
+		if err != nil {
+			return "", "", nil, err
+		}
 
-		for k, v := range secret.Data {
+		for k, v := range credentialData {
 			result[k] = string(v)
 		}
 	}
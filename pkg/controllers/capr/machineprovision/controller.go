@@ -11,6 +11,7 @@
 	"github.com/rancher/lasso/pkg/dynamic"
 	rkev1 "github.com/rancher/rancher/pkg/apis/rke.cattle.io/v1"
 	"github.com/rancher/rancher/pkg/capr"
+	"github.com/rancher/rancher/pkg/clusterprovisioninglogger"
 	"github.com/rancher/rancher/pkg/controllers/management/drivers/nodedriver"
 	capicontrollers "github.com/rancher/rancher/pkg/generated/controllers/cluster.x-k8s.io/v1beta2"
 	mgmtcontrollers "github.com/rancher/rancher/pkg/generated/controllers/management.cattle.io/v3"
@@ -110,6 +111,7 @@ type handler struct {
 	rancherClusterCache ranchercontrollers.ClusterCache
 	kubeconfigManager   *kubeconfig.Manager
 	client              client.Client
+	events              *clusterprovisioninglogger.Recorder
 }
 
 func Register(ctx context.Context, clients *wrangler.CAPIContext, kubeconfigManager *kubeconfig.Manager) {
@@ -134,6 +136,7 @@ func Register(ctx context.Context, clients *wrangler.CAPIContext, kubeconfigMana
 		rancherClusterCache: clients.Provisioning.Cluster().Cache(),
 		kubeconfigManager:   kubeconfigManager,
 		client:              clients.Client,
+		events:              clusterprovisioninglogger.NewRecorder(clients.Mgmt.ProvisioningEvent()),
 	}
 
 	removeHandler := generic.NewRemoveHandler("machine-provision-remove", clients.Dynamic.Update, h.OnRemove)
@@ -189,6 +192,10 @@ func (h *handler) OnJobChange(_ string, job *batchv1.Job) (*batchv1.Job, error)
 		return job, err
 	}
 
+	if err := h.recordJobEvent(job, infra); err != nil {
+		logrus.Errorf("[machineprovision] %s/%s: error recording provisioning event: %v", infra.meta.GetNamespace(), infra.meta.GetName(), err)
+	}
+
 	// Re-evaluate the infra-machine after this
 	if err = h.dynamic.Enqueue(infraMachine.GetObjectKind().GroupVersionKind(),
 		infra.meta.GetNamespace(), infra.meta.GetName()); err != nil {
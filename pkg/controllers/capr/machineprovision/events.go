@@ -0,0 +1,65 @@
+package machineprovision
+
+import (
+	"fmt"
+
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	rkev1 "github.com/rancher/rancher/pkg/apis/rke.cattle.io/v1"
+	"github.com/rancher/rancher/pkg/capr"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	capi "sigs.k8s.io/cluster-api/api/core/v1beta2"
+)
+
+// recordJobEvent records the progress of the machine provision job in the provisioning timeline of the cluster.
+func (h *handler) recordJobEvent(job *batchv1.Job, infra *infraObject) error {
+	cluster, err := h.rancherClusterCache.Get(infra.meta.GetNamespace(), infra.meta.GetLabels()[capi.ClusterNameLabel])
+	if apierrors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	status, err := h.getMachineStatus(job)
+	if err != nil {
+		return err
+	}
+
+	machineName := infra.meta.GetLabels()[CapiMachineName]
+	if machineName == "" {
+		machineName = infra.meta.GetName()
+	}
+	return h.events.Record(cluster.Status.ClusterName, jobEvent(job, machineName, status))
+}
+
+// jobEvent returns the provisioning event reporting the status of the machine provision job.
+func jobEvent(job *batchv1.Job, machineName string, status rkev1.RKEMachineStatus) v3.ProvisioningEventSpec {
+	event := v3.ProvisioningEventSpec{
+		MachineName: machineName,
+		Phase:       "Create",
+		Severity:    v3.ProvisioningEventSeverityInfo,
+		Source:      v3.ProvisioningEventSourceMachineProvision,
+	}
+	verb := "created"
+	if job.Spec.Template.Labels[InfraJobRemove] == "true" {
+		event.Phase = "Delete"
+		verb = "deleted"
+	}
+
+	for _, cond := range status.Conditions {
+		if cond.Type != string(capr.Ready) {
+			continue
+		}
+		switch {
+		case status.FailureReason != "":
+			event.Severity = v3.ProvisioningEventSeverityError
+			event.Message = cond.Message
+		case cond.Status == corev1.ConditionTrue:
+			event.Message = fmt.Sprintf("%s server for machine %s in infrastructure provider", verb, machineName)
+		default:
+			event.Message = cond.Message
+		}
+	}
+	return event
+}
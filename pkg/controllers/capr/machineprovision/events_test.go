@@ -0,0 +1,75 @@
+package machineprovision
+
+import (
+	"testing"
+
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	rkev1 "github.com/rancher/rancher/pkg/apis/rke.cattle.io/v1"
+	"github.com/rancher/rancher/pkg/capr"
+	"github.com/rancher/wrangler/v3/pkg/genericcondition"
+	"github.com/stretchr/testify/assert"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestJobEvent(t *testing.T) {
+	job := func(remove bool) *batchv1.Job {
+		job := &batchv1.Job{}
+		if remove {
+			job.Spec.Template.Labels = map[string]string{InfraJobRemove: "true"}
+		}
+		return job
+	}
+	ready := func(status corev1.ConditionStatus, message string) []genericcondition.GenericCondition {
+		return []genericcondition.GenericCondition{{Type: string(capr.Ready), Status: status, Message: message}}
+	}
+
+	tests := []struct {
+		name     string
+		job      *batchv1.Job
+		status   rkev1.RKEMachineStatus
+		expected v3.ProvisioningEventSpec
+	}{
+		{
+			name:   "creating",
+			job:    job(false),
+			status: rkev1.RKEMachineStatus{Conditions: ready(corev1.ConditionFalse, "creating server")},
+			expected: v3.ProvisioningEventSpec{
+				Phase:    "Create",
+				Severity: v3.ProvisioningEventSeverityInfo,
+				Message:  "creating server",
+			},
+		},
+		{
+			name:   "created",
+			job:    job(false),
+			status: rkev1.RKEMachineStatus{Conditions: ready(corev1.ConditionTrue, "")},
+			expected: v3.ProvisioningEventSpec{
+				Phase:    "Create",
+				Severity: v3.ProvisioningEventSeverityInfo,
+				Message:  "created server for machine pool-abc in infrastructure provider",
+			},
+		},
+		{
+			name: "deletion failed",
+			job:  job(true),
+			status: rkev1.RKEMachineStatus{
+				Conditions:    ready(corev1.ConditionFalse, "failed deleting server"),
+				FailureReason: "DeleteError",
+			},
+			expected: v3.ProvisioningEventSpec{
+				Phase:    "Delete",
+				Severity: v3.ProvisioningEventSeverityError,
+				Message:  "failed deleting server",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.expected.MachineName = "pool-abc"
+			tt.expected.Source = v3.ProvisioningEventSourceMachineProvision
+			assert.Equal(t, tt.expected, jobEvent(tt.job, "pool-abc", tt.status))
+		})
+	}
+}
@@ -9,6 +9,7 @@
 	rkev1 "github.com/rancher/rancher/pkg/apis/rke.cattle.io/v1"
 	"github.com/rancher/rancher/pkg/capr"
 	caprplanner "github.com/rancher/rancher/pkg/capr/planner"
+	"github.com/rancher/rancher/pkg/clusterprovisioninglogger"
 	operationcontrollers "github.com/rancher/rancher/pkg/generated/controllers/operation.cattle.io/v1alpha1"
 	rkecontrollers "github.com/rancher/rancher/pkg/generated/controllers/rke.cattle.io/v1"
 	plancontrollers "github.com/rancher/rancher/pkg/plan/generated/controllers/plan.cattle.io/v1alpha1"
@@ -39,6 +40,7 @@ type handler struct {
 	beacons       plancontrollers.BeaconClient
 
 	etcdsnapshotsaves operationcontrollers.ETCDSnapshotSaveClient
+	events            *clusterprovisioninglogger.Recorder
 }
 
 func Register(ctx context.Context, clients *wrangler.CAPIContext, planner *caprplanner.Planner) {
@@ -47,6 +49,7 @@ func Register(ctx context.Context, clients *wrangler.CAPIContext, planner *caprp
 		controlPlanes:     clients.RKE.RKEControlPlane(),
 		beacons:           clients.Plan.Beacon(),
 		etcdsnapshotsaves: clients.Operation.ETCDSnapshotSave(),
+		events:            clusterprovisioninglogger.NewRecorder(clients.Mgmt.ProvisioningEvent()),
 	}
 	rkecontrollers.RegisterRKEControlPlaneStatusHandler(ctx, clients.RKE.RKEControlPlane(), "", "planner", h.OnChange)
 	relatedresource.Watch(ctx, "planner", func(namespace, name string, obj runtime.Object) ([]relatedresource.Key, error) {
@@ -155,6 +158,7 @@ func (h *handler) OnChange(cp *rkev1.RKEControlPlane, status rkev1.RKEControlPla
 
 	logrus.Debugf("[planner] rkecluster %s/%s: calling planner process", cp.Namespace, cp.Name)
 	status, err = h.planner.Process(cp, status)
+	h.recordEtcdEvents(cp, status)
 	if err != nil {
 		// planner.Process can encounter 3 types of errors:
 		// * planner.errWaiting - This is an error that indicates we are waiting for something, and will not re-enqueue the object
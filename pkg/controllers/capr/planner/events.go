@@ -0,0 +1,48 @@
+package planner
+
+import (
+	"fmt"
+
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	rkev1 "github.com/rancher/rancher/pkg/apis/rke.cattle.io/v1"
+	"github.com/sirupsen/logrus"
+)
+
+// recordEtcdEvents records the etcd snapshot create and restore phases the planner moved the control plane to in the
+// provisioning timeline of the cluster.
+func (h *handler) recordEtcdEvents(cp *rkev1.RKEControlPlane, status rkev1.RKEControlPlaneStatus) {
+	for _, event := range etcdEvents(cp.Status, status) {
+		if err := h.events.Record(cp.Spec.ManagementClusterName, event); err != nil {
+			logrus.Errorf("[planner] rkecluster %s/%s: error recording provisioning event: %v", cp.Namespace, cp.Name, err)
+		}
+	}
+}
+
+// etcdEvents returns the provisioning events reporting the changes of the etcd snapshot create and restore phases.
+func etcdEvents(previous, status rkev1.RKEControlPlaneStatus) []v3.ProvisioningEventSpec {
+	var events []v3.ProvisioningEventSpec
+	if phase := status.ETCDSnapshotCreatePhase; phase != "" && phase != previous.ETCDSnapshotCreatePhase {
+		events = append(events, etcdEvent("SnapshotCreate", phase, "etcd snapshot create"))
+	}
+	if phase := status.ETCDSnapshotRestorePhase; phase != "" && phase != previous.ETCDSnapshotRestorePhase {
+		operation := "etcd snapshot restore"
+		if status.ETCDSnapshotRestore != nil && status.ETCDSnapshotRestore.Name != "" {
+			operation = fmt.Sprintf("etcd snapshot restore of %s", status.ETCDSnapshotRestore.Name)
+		}
+		events = append(events, etcdEvent("SnapshotRestore", phase, operation))
+	}
+	return events
+}
+
+func etcdEvent(eventPhase string, phase rkev1.ETCDSnapshotPhase, operation string) v3.ProvisioningEventSpec {
+	event := v3.ProvisioningEventSpec{
+		Phase:    eventPhase,
+		Severity: v3.ProvisioningEventSeverityInfo,
+		Source:   v3.ProvisioningEventSourceEtcd,
+		Message:  fmt.Sprintf("%s: %s", operation, phase),
+	}
+	if phase == rkev1.ETCDSnapshotPhaseFailed {
+		event.Severity = v3.ProvisioningEventSeverityError
+	}
+	return event
+}
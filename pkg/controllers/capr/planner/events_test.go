@@ -0,0 +1,62 @@
+package planner
+
+import (
+	"testing"
+
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	rkev1 "github.com/rancher/rancher/pkg/apis/rke.cattle.io/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEtcdEvents(t *testing.T) {
+	restore := &rkev1.ETCDSnapshotRestore{Name: "snapshot-abc"}
+
+	tests := []struct {
+		name     string
+		previous rkev1.RKEControlPlaneStatus
+		status   rkev1.RKEControlPlaneStatus
+		expected []v3.ProvisioningEventSpec
+	}{
+		{
+			name:   "no operation",
+			status: rkev1.RKEControlPlaneStatus{},
+		},
+		{
+			name:     "phase unchanged",
+			previous: rkev1.RKEControlPlaneStatus{ETCDSnapshotCreatePhase: rkev1.ETCDSnapshotPhaseFinished},
+			status:   rkev1.RKEControlPlaneStatus{ETCDSnapshotCreatePhase: rkev1.ETCDSnapshotPhaseFinished},
+		},
+		{
+			name:     "operation reset",
+			previous: rkev1.RKEControlPlaneStatus{ETCDSnapshotCreatePhase: rkev1.ETCDSnapshotPhaseFinished},
+		},
+		{
+			name:     "snapshot create started",
+			previous: rkev1.RKEControlPlaneStatus{ETCDSnapshotCreatePhase: rkev1.ETCDSnapshotPhaseFinished},
+			status:   rkev1.RKEControlPlaneStatus{ETCDSnapshotCreatePhase: rkev1.ETCDSnapshotPhaseStarted},
+			expected: []v3.ProvisioningEventSpec{{
+				Phase:    "SnapshotCreate",
+				Severity: v3.ProvisioningEventSeverityInfo,
+				Source:   v3.ProvisioningEventSourceEtcd,
+				Message:  "etcd snapshot create: Started",
+			}},
+		},
+		{
+			name:     "snapshot restore failed",
+			previous: rkev1.RKEControlPlaneStatus{ETCDSnapshotRestore: restore, ETCDSnapshotRestorePhase: rkev1.ETCDSnapshotPhaseRestore},
+			status:   rkev1.RKEControlPlaneStatus{ETCDSnapshotRestore: restore, ETCDSnapshotRestorePhase: rkev1.ETCDSnapshotPhaseFailed},
+			expected: []v3.ProvisioningEventSpec{{
+				Phase:    "SnapshotRestore",
+				Severity: v3.ProvisioningEventSeverityError,
+				Source:   v3.ProvisioningEventSourceEtcd,
+				Message:  "etcd snapshot restore of snapshot-abc: Failed",
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, etcdEvents(tt.previous, tt.status))
+		})
+	}
+}
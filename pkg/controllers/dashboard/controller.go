@@ -12,6 +12,7 @@
 	"github.com/rancher/rancher/pkg/controllers/dashboard/helm"
 	"github.com/rancher/rancher/pkg/controllers/dashboard/hostedcluster"
 	"github.com/rancher/rancher/pkg/controllers/dashboard/kubernetesprovider"
+	"github.com/rancher/rancher/pkg/controllers/dashboard/managementbackup"
 	"github.com/rancher/rancher/pkg/controllers/dashboard/mcmagent"
 	"github.com/rancher/rancher/pkg/controllers/dashboard/privateregistry"
 	"github.com/rancher/rancher/pkg/controllers/dashboard/scaleavailable"
@@ -111,8 +112,15 @@ func Register(ctx context.Context, clients *wrangler.Context, embedded bool, reg
 
 // RegisterPostMigration registers controllers that should only start after boot-time
 // migrations (see pkg/rancher/migrations.go) have completed. This avoids redundant or conflicting work on startup.
-func RegisterPostMigration(ctx context.Context, clients *wrangler.Context) {
+// migrationConfigMaps are the config maps recording the migrations, which management backups include.
+func RegisterPostMigration(ctx context.Context, clients *wrangler.Context, migrationConfigMaps []string) error {
 	if features.ProvisioningV2.Enabled() || features.MCM.Enabled() {
 		clusterregistrationtoken.Register(ctx, clients)
 	}
+	if features.MCM.Enabled() {
+		if err := managementbackup.Register(ctx, clients, migrationConfigMaps); err != nil {
+			return err
+		}
+	}
+	return nil
 }
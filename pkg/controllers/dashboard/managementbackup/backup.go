@@ -0,0 +1,164 @@
+package managementbackup
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	mgmtcontrollers "github.com/rancher/rancher/pkg/generated/controllers/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/settings"
+	corecontrollers "github.com/rancher/wrangler/v3/pkg/generated/controllers/core/v1"
+	"github.com/robfig/cron"
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	defaultRetention = 10
+	snapshotSuffix   = ".json.gz.enc"
+	snapshotTime     = "20060102T150405Z"
+)
+
+type backupHandler struct {
+	ctx         context.Context
+	backups     mgmtcontrollers.ManagementBackupController
+	secretCache corecontrollers.SecretCache
+	collect     func(context.Context) (*snapshot, error)
+	newStore    func(*v3.ManagementBackup) (store, error)
+	now         func() time.Time
+}
+
+// onChange takes a snapshot once the backup is due and requeues the backup until the next scheduled snapshot.
+// Snapshots are kept on the target when the backup is deleted.
+func (h *backupHandler) onChange(_ string, backup *v3.ManagementBackup) (*v3.ManagementBackup, error) {
+	if backup == nil || backup.DeletionTimestamp != nil {
+		return backup, nil
+	}
+
+	now := time.Now()
+	if h.now != nil {
+		now = h.now()
+	}
+	// times are stored with a precision of a second
+	now = now.UTC().Truncate(time.Second)
+
+	status := backup.Status.DeepCopy()
+	var schedule cron.Schedule
+	if backup.Spec.Schedule != "" {
+		var err error
+		if schedule, err = cron.ParseStandard(backup.Spec.Schedule); err != nil {
+			// retrying does not help with an invalid schedule, the backup is handled again once it is fixed
+			status.LastError = fmt.Sprintf("invalid schedule: %v", err)
+			status.NextBackupTime = nil
+			return h.updateStatus(backup, status)
+		}
+	}
+
+	var snapshotErr error
+	if due(backup, schedule, now) {
+		if snapshotErr = h.takeSnapshot(backup, status, now); snapshotErr != nil {
+			status.LastError = snapshotErr.Error()
+		} else {
+			status.LastError = ""
+		}
+	}
+
+	status.NextBackupTime = nil
+	if schedule != nil {
+		next := metav1.NewTime(schedule.Next(lastRun(backup, status)))
+		status.NextBackupTime = &next
+	}
+
+	backup, err := h.updateStatus(backup, status)
+	if err != nil {
+		return backup, err
+	}
+	if snapshotErr != nil {
+		return backup, snapshotErr
+	}
+	if status.NextBackupTime != nil {
+		h.backups.EnqueueAfter(backup.Name, status.NextBackupTime.Sub(now))
+	}
+	return backup, nil
+}
+
+func (h *backupHandler) updateStatus(backup *v3.ManagementBackup, status *v3.ManagementBackupStatus) (*v3.ManagementBackup, error) {
+	if equality.Semantic.DeepEqual(backup.Status, *status) {
+		return backup, nil
+	}
+	backup = backup.DeepCopy()
+	backup.Status = *status
+	return h.backups.UpdateStatus(backup)
+}
+
+// due returns whether a snapshot of the backup is due. A backup without schedule takes a single snapshot.
+func due(backup *v3.ManagementBackup, schedule cron.Schedule, now time.Time) bool {
+	if schedule == nil {
+		return backup.Status.LastBackupTime == nil
+	}
+	return !now.Before(schedule.Next(lastRun(backup, &backup.Status)))
+}
+
+// lastRun returns the time the latest snapshot was taken at, or the creation time of the backup if none was.
+func lastRun(backup *v3.ManagementBackup, status *v3.ManagementBackupStatus) time.Time {
+	if status.LastBackupTime != nil {
+		return status.LastBackupTime.UTC()
+	}
+	return backup.CreationTimestamp.UTC()
+}
+
+// takeSnapshot writes a new snapshot to the target of the backup, then deletes the oldest snapshots beyond the
+// retention.
+func (h *backupHandler) takeSnapshot(backup *v3.ManagementBackup, status *v3.ManagementBackupStatus, now time.Time) error {
+	key, err := encryptionKey(backup, h.secretCache)
+	if err != nil {
+		return err
+	}
+	target, err := h.newStore(backup)
+	if err != nil {
+		return err
+	}
+
+	s, err := h.collect(h.ctx)
+	if err != nil {
+		return err
+	}
+	s.RancherVersion = settings.ServerVersion.Get()
+	s.Time = metav1.NewTime(now)
+	data, err := encode(s, key)
+	if err != nil {
+		return fmt.Errorf("encoding snapshot: %w", err)
+	}
+
+	name := backup.Name + "-" + now.Format(snapshotTime) + snapshotSuffix
+	if err := target.Put(name, data); err != nil {
+		return fmt.Errorf("storing snapshot %s: %w", name, err)
+	}
+	logrus.Infof("[management-backup] backup %s took snapshot %s of %d objects", backup.Name, name, s.objectCount())
+
+	status.Snapshots = append(status.Snapshots, v3.ManagementBackupSnapshot{
+		Name:           name,
+		Time:           s.Time,
+		RancherVersion: s.RancherVersion,
+		Objects:        s.objectCount(),
+		Size:           int64(len(data)),
+	})
+	status.LastBackupTime = &s.Time
+
+	retention := backup.Spec.Retention
+	if retention <= 0 {
+		retention = defaultRetention
+	}
+	for len(status.Snapshots) > retention {
+		oldest := status.Snapshots[0].Name
+		if err := target.Delete(oldest); err != nil {
+			// the snapshot is kept in the status, so that deleting it is attempted again after the next snapshot
+			logrus.Warnf("[management-backup] backup %s failed to delete snapshot %s: %v", backup.Name, oldest, err)
+			break
+		}
+		status.Snapshots = status.Snapshots[1:]
+	}
+	return nil
+}
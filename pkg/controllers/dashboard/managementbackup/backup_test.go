@@ -0,0 +1,166 @@
+package managementbackup
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/settings"
+	"github.com/rancher/wrangler/v3/pkg/generic/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestBackupOnChange(t *testing.T) {
+	prev := settings.ServerVersion.Get()
+	require.NoError(t, settings.ServerVersion.Set("v2.12.0"))
+	t.Cleanup(func() { _ = settings.ServerVersion.Set(prev) })
+
+	now := time.Date(2026, 3, 2, 10, 30, 0, 0, time.UTC)
+	at := func(d time.Duration) *metav1.Time {
+		t := metav1.NewTime(now.Add(d))
+		return &t
+	}
+	backup := func(schedule string, retention int, status v3.ManagementBackupStatus) *v3.ManagementBackup {
+		return &v3.ManagementBackup{
+			ObjectMeta: metav1.ObjectMeta{Name: "daily", CreationTimestamp: metav1.NewTime(now.Add(-48 * time.Hour))},
+			Spec: v3.ManagementBackupSpec{
+				Schedule:             schedule,
+				Retention:            retention,
+				EncryptionSecretName: "backup-key",
+			},
+			Status: status,
+		}
+	}
+
+	tests := []struct {
+		name             string
+		backup           *v3.ManagementBackup
+		existing         []string
+		wantSnapshots    []string
+		wantFiles        []string
+		wantNext         *metav1.Time
+		wantLastError    string
+		wantEnqueueAfter time.Duration
+	}{
+		{
+			name:          "one-shot backup takes a snapshot",
+			backup:        backup("", 0, v3.ManagementBackupStatus{}),
+			wantSnapshots: []string{"daily-20260302T103000Z.json.gz.enc"},
+			wantFiles:     []string{"daily-20260302T103000Z.json.gz.enc"},
+		},
+		{
+			name:          "one-shot backup takes a single snapshot",
+			backup:        backup("", 0, v3.ManagementBackupStatus{LastBackupTime: at(-time.Hour), Snapshots: []v3.ManagementBackupSnapshot{{Name: "old"}}}),
+			wantSnapshots: []string{"old"},
+		},
+		{
+			name:             "scheduled backup not due",
+			backup:           backup("0 12 * * *", 0, v3.ManagementBackupStatus{LastBackupTime: at(-90 * time.Minute)}),
+			wantNext:         at(90 * time.Minute),
+			wantEnqueueAfter: 90 * time.Minute,
+		},
+		{
+			name: "scheduled backup due deletes snapshots beyond retention",
+			backup: backup("0 * * * *", 2, v3.ManagementBackupStatus{
+				LastBackupTime: at(-90 * time.Minute),
+				LastError:      "previous error",
+				Snapshots:      []v3.ManagementBackupSnapshot{{Name: "first"}, {Name: "second"}},
+			}),
+			existing:         []string{"first", "second"},
+			wantSnapshots:    []string{"second", "daily-20260302T103000Z.json.gz.enc"},
+			wantFiles:        []string{"daily-20260302T103000Z.json.gz.enc", "second"},
+			wantNext:         at(30 * time.Minute),
+			wantEnqueueAfter: 30 * time.Minute,
+		},
+		{
+			name:          "invalid schedule",
+			backup:        backup("every day", 0, v3.ManagementBackupStatus{}),
+			wantLastError: "invalid schedule: Expected exactly 5 fields, found 2: every day",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			dir := t.TempDir()
+			for _, name := range tt.existing {
+				require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte("snapshot"), 0o600))
+			}
+
+			secretCache := fake.NewMockCacheInterface[*corev1.Secret](ctrl)
+			secretCache.EXPECT().Get("cattle-system", "backup-key").Return(&corev1.Secret{
+				Data: map[string][]byte{"encryption-key": []byte("passphrase")},
+			}, nil).AnyTimes()
+			backups := fake.NewMockNonNamespacedControllerInterface[*v3.ManagementBackup, *v3.ManagementBackupList](ctrl)
+			var updated *v3.ManagementBackup
+			backups.EXPECT().UpdateStatus(gomock.Any()).DoAndReturn(func(obj *v3.ManagementBackup) (*v3.ManagementBackup, error) {
+				updated = obj
+				return obj, nil
+			}).MaxTimes(1)
+			if tt.wantEnqueueAfter > 0 {
+				backups.EXPECT().EnqueueAfter("daily", tt.wantEnqueueAfter)
+			}
+
+			h := &backupHandler{
+				ctx:         context.Background(),
+				backups:     backups,
+				secretCache: secretCache,
+				collect: func(context.Context) (*snapshot, error) {
+					return &snapshot{Resources: []snapshotResource{{
+						Group:    "management.cattle.io",
+						Version:  "v3",
+						Resource: "clusters",
+						Objects:  []unstructured.Unstructured{*newObject("management.cattle.io/v3", "Cluster", "", "local")},
+					}}}, nil
+				},
+				newStore: func(*v3.ManagementBackup) (store, error) {
+					return &volumeStore{dir: dir}, nil
+				},
+				now: func() time.Time { return now },
+			}
+			_, err := h.onChange("daily", tt.backup)
+			require.NoError(t, err)
+
+			status := tt.backup.Status
+			if updated != nil {
+				status = updated.Status
+			}
+			var snapshots []string
+			for _, s := range status.Snapshots {
+				snapshots = append(snapshots, s.Name)
+			}
+			assert.Equal(t, tt.wantSnapshots, snapshots)
+			assert.Equal(t, tt.wantNext, status.NextBackupTime)
+			assert.Equal(t, tt.wantLastError, status.LastError)
+
+			entries, err := os.ReadDir(dir)
+			require.NoError(t, err)
+			var files []string
+			for _, entry := range entries {
+				files = append(files, entry.Name())
+			}
+			if len(tt.existing) > 0 || len(tt.wantFiles) > 0 {
+				assert.Equal(t, tt.wantFiles, files)
+			}
+
+			if len(tt.wantFiles) > 0 {
+				latest := status.Snapshots[len(status.Snapshots)-1]
+				assert.Equal(t, "v2.12.0", latest.RancherVersion)
+				assert.Equal(t, 1, latest.Objects)
+				assert.Equal(t, now, latest.Time.UTC())
+				data, err := os.ReadFile(filepath.Join(dir, latest.Name))
+				require.NoError(t, err)
+				s, err := decode(data, []byte("passphrase"))
+				require.NoError(t, err)
+				assert.Equal(t, "v2.12.0", s.RancherVersion)
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package managementbackup
+
+import (
+	"context"
+	"fmt"
+
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/wrangler"
+	"k8s.io/client-go/dynamic"
+)
+
+// Register registers the controllers taking and restoring the snapshots of management backups. migrationConfigMaps are
+// the names of the config maps in the cattle-system namespace recording the migrations Rancher ran, which are included
+// in the snapshots so that a restore can tell the migrations to run again.
+func Register(ctx context.Context, wContext *wrangler.Context, migrationConfigMaps []string) error {
+	dynamicClient, err := dynamic.NewForConfig(wContext.RESTConfig)
+	if err != nil {
+		return fmt.Errorf("creating dynamic client: %w", err)
+	}
+	secretCache := wContext.Core.Secret().Cache()
+	newStoreFunc := func(backup *v3.ManagementBackup) (store, error) {
+		return newStore(backup, secretCache)
+	}
+
+	c := &collector{
+		discovery:           wContext.K8s.Discovery(),
+		dynamic:             dynamicClient,
+		migrationConfigMaps: migrationConfigMaps,
+	}
+	b := &backupHandler{
+		ctx:         ctx,
+		backups:     wContext.Mgmt.ManagementBackup(),
+		secretCache: secretCache,
+		collect:     c.collect,
+		newStore:    newStoreFunc,
+	}
+	r := &restoreHandler{
+		ctx:                 ctx,
+		restores:            wContext.Mgmt.ManagementRestore(),
+		backupCache:         wContext.Mgmt.ManagementBackup().Cache(),
+		secretCache:         secretCache,
+		dynamic:             dynamicClient,
+		newStore:            newStoreFunc,
+		migrationConfigMaps: migrationConfigMaps,
+	}
+
+	wContext.Mgmt.ManagementBackup().OnChange(ctx, "management-backup", b.onChange)
+	wContext.Mgmt.ManagementRestore().OnChange(ctx, "management-restore", r.onChange)
+	return nil
+}
@@ -0,0 +1,391 @@
+package managementbackup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	mgmtcontrollers "github.com/rancher/rancher/pkg/generated/controllers/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/namespace"
+	"github.com/rancher/rancher/pkg/settings"
+	corecontrollers "github.com/rancher/wrangler/v3/pkg/generated/controllers/core/v1"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/mod/semver"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/dynamic"
+)
+
+const (
+	managementGroup   = "management.cattle.io"
+	provisioningGroup = "provisioning.cattle.io"
+	rkeGroup          = "rke.cattle.io"
+	capiGroup         = "cluster.x-k8s.io"
+
+	// maxFailedObjects bounds the failed objects reported in the status of a restore.
+	maxFailedObjects = 20
+)
+
+type restoreHandler struct {
+	ctx                 context.Context
+	restores            mgmtcontrollers.ManagementRestoreController
+	backupCache         mgmtcontrollers.ManagementBackupCache
+	secretCache         corecontrollers.SecretCache
+	dynamic             dynamic.Interface
+	newStore            func(*v3.ManagementBackup) (store, error)
+	migrationConfigMaps []string
+}
+
+// onChange validates the snapshot of the restore, then restores it. A restore runs once: completed and failed
+// restores are left as is.
+func (h *restoreHandler) onChange(_ string, restore *v3.ManagementRestore) (*v3.ManagementRestore, error) {
+	if restore == nil || restore.DeletionTimestamp != nil {
+		return restore, nil
+	}
+
+	status := restore.Status.DeepCopy()
+	var err error
+	switch status.Phase {
+	case "":
+		err = h.validate(restore, status)
+	case v3.ManagementRestorePhaseRestoring:
+		err = h.restore(restore, status)
+	default:
+		return restore, nil
+	}
+	if err != nil {
+		return restore, err
+	}
+
+	if !equality.Semantic.DeepEqual(restore.Status, *status) {
+		restore = restore.DeepCopy()
+		restore.Status = *status
+		return h.restores.UpdateStatus(restore)
+	}
+	return restore, nil
+}
+
+// validate resolves the snapshot of the restore and checks it can be restored by the running version of Rancher.
+func (h *restoreHandler) validate(restore *v3.ManagementRestore, status *v3.ManagementRestoreStatus) error {
+	name := restore.Spec.SnapshotName
+	if name == "" {
+		backup, err := h.backupCache.Get(restore.Spec.BackupName)
+		if err != nil {
+			status.SetPhase(v3.ManagementRestorePhaseFailed, fmt.Sprintf("getting backup: %v", err))
+			return nil
+		}
+		if len(backup.Status.Snapshots) == 0 {
+			status.SetPhase(v3.ManagementRestorePhaseFailed, fmt.Sprintf("backup %s has no snapshots", backup.Name))
+			return nil
+		}
+		name = backup.Status.Snapshots[len(backup.Status.Snapshots)-1].Name
+	}
+
+	s, err := h.load(restore.Spec.BackupName, name)
+	if err != nil {
+		status.SetPhase(v3.ManagementRestorePhaseFailed, err.Error())
+		return nil
+	}
+	status.SnapshotName = name
+	status.RancherVersion = s.RancherVersion
+	if err := checkVersion(s.RancherVersion, settings.ServerVersion.Get()); err != nil {
+		status.SetPhase(v3.ManagementRestorePhaseFailed, err.Error())
+		return nil
+	}
+	status.SetPhase(v3.ManagementRestorePhaseRestoring, fmt.Sprintf("restoring %d objects", s.objectCount()))
+	return nil
+}
+
+// load reads and decrypts a snapshot of a backup.
+func (h *restoreHandler) load(backupName, snapshotName string) (*snapshot, error) {
+	backup, err := h.backupCache.Get(backupName)
+	if err != nil {
+		return nil, fmt.Errorf("getting backup: %w", err)
+	}
+	key, err := encryptionKey(backup, h.secretCache)
+	if err != nil {
+		return nil, err
+	}
+	target, err := h.newStore(backup)
+	if err != nil {
+		return nil, err
+	}
+	data, err := target.Get(snapshotName)
+	if err != nil {
+		return nil, fmt.Errorf("reading snapshot %s: %w", snapshotName, err)
+	}
+	return decode(data, key)
+}
+
+// checkVersion rejects snapshots taken by a newer version of Rancher, or by another major version, since the objects
+// they hold may not be understood by the running version. Development builds are not checked.
+func checkVersion(snapshotVersion, serverVersion string) error {
+	snapshotSemver, serverSemver := canonicalVersion(snapshotVersion), canonicalVersion(serverVersion)
+	if !semver.IsValid(snapshotSemver) || !semver.IsValid(serverSemver) {
+		return nil
+	}
+	if semver.Major(snapshotSemver) != semver.Major(serverSemver) {
+		return fmt.Errorf("snapshot was taken by Rancher %s, which is not the same major version as Rancher %s", snapshotVersion, serverVersion)
+	}
+	if semver.Compare(snapshotSemver, serverSemver) > 0 {
+		return fmt.Errorf("snapshot was taken by Rancher %s, which is newer than Rancher %s", snapshotVersion, serverVersion)
+	}
+	return nil
+}
+
+func canonicalVersion(version string) string {
+	if !strings.HasPrefix(version, "v") {
+		version = "v" + version
+	}
+	return version
+}
+
+// restore restores the objects of the snapshot, then the config maps recording the migrations.
+func (h *restoreHandler) restore(restore *v3.ManagementRestore, status *v3.ManagementRestoreStatus) error {
+	s, err := h.load(restore.Spec.BackupName, status.SnapshotName)
+	if err != nil {
+		// the snapshot was readable when validated, so the error is likely transient
+		return err
+	}
+
+	restored, failed := h.restoreObjects(s)
+	pending, err := h.restoreMigrations(s)
+	if err != nil {
+		failed = append(failed, fmt.Sprintf("migrations: %v", err))
+	}
+
+	status.RestoredObjects = restored
+	status.PendingMigrations = pending
+	status.FailedObjects = failed
+	if len(failed) > maxFailedObjects {
+		status.FailedObjects = append(failed[:maxFailedObjects:maxFailedObjects], fmt.Sprintf("and %d more", len(failed)-maxFailedObjects))
+	}
+
+	message := fmt.Sprintf("restored %d objects", restored)
+	if len(pending) > 0 {
+		message += ", restart Rancher to run the migrations the snapshot predates again"
+	}
+	if len(failed) > 0 {
+		status.SetPhase(v3.ManagementRestorePhaseFailed, fmt.Sprintf("%s, %d failed", message, len(failed)))
+	} else {
+		status.SetPhase(v3.ManagementRestorePhaseCompleted, message)
+	}
+	logrus.Infof("[management-restore] restore %s of snapshot %s: %s", restore.Name, status.SnapshotName, status.Message)
+	return nil
+}
+
+// restoreEntry is an object of a snapshot to restore.
+type restoreEntry struct {
+	resource *snapshotResource
+	object   *unstructured.Unstructured
+}
+
+func (e restoreEntry) String() string {
+	name := e.object.GetName()
+	if ns := e.object.GetNamespace(); ns != "" {
+		name = ns + "/" + name
+	}
+	return e.resource.gvr().GroupResource().String() + " " + name
+}
+
+// restoreObjects restores the objects of the snapshot in the order of their priority. Objects owned by other objects
+// of the snapshot are restored once their owners are, with their owner references updated to the UID of the restored
+// owners. It returns the number of restored objects and the objects that failed to restore.
+func (h *restoreHandler) restoreObjects(s *snapshot) (int, []string) {
+	var pending []restoreEntry
+	snapshotUIDs := sets.New[types.UID]()
+	for i := range s.Resources {
+		r := &s.Resources[i]
+		for j := range r.Objects {
+			pending = append(pending, restoreEntry{resource: r, object: &r.Objects[j]})
+			snapshotUIDs.Insert(r.Objects[j].GetUID())
+		}
+	}
+	sort.SliceStable(pending, func(i, j int) bool {
+		return priority(pending[i].resource) < priority(pending[j].resource)
+	})
+
+	var (
+		restored int
+		failed   []string
+		uids     = map[types.UID]types.UID{}
+	)
+	for len(pending) > 0 {
+		var waiting []restoreEntry
+		for _, e := range pending {
+			if !ownersRestored(e.object, snapshotUIDs, uids) {
+				waiting = append(waiting, e)
+				continue
+			}
+			uid, err := h.restoreObject(e, uids)
+			if err != nil {
+				failed = append(failed, fmt.Sprintf("%s: %v", e, err))
+				continue
+			}
+			uids[e.object.GetUID()] = uid
+			restored++
+		}
+		if len(waiting) == len(pending) {
+			for _, e := range waiting {
+				failed = append(failed, fmt.Sprintf("%s: owner was not restored", e))
+			}
+			break
+		}
+		pending = waiting
+	}
+	return restored, failed
+}
+
+// priority orders the resources so that objects are restored after the objects they depend on: Namespaces and
+// Secrets first, then the cluster scoped management objects such as clusters, users and role templates, then the
+// namespaced management objects such as projects, then the role bindings, and finally the provisioning objects.
+func priority(r *snapshotResource) int {
+	switch {
+	case r.Group == "" && r.Resource == "namespaces":
+		return 0
+	case r.Group == "":
+		return 1
+	case r.Group == managementGroup:
+		switch r.Resource {
+		case "clusterroletemplatebindings", "projectroletemplatebindings", "globalrolebindings":
+			return 4
+		}
+		if !r.Namespaced {
+			return 2
+		}
+		return 3
+	case r.Group == provisioningGroup:
+		return 5
+	case r.Group == rkeGroup:
+		return 6
+	case r.Group == capiGroup:
+		return 7
+	default:
+		return 8
+	}
+}
+
+// ownersRestored returns whether the owners of the object that are in the snapshot were restored.
+func ownersRestored(obj *unstructured.Unstructured, snapshotUIDs sets.Set[types.UID], uids map[types.UID]types.UID) bool {
+	for _, ref := range obj.GetOwnerReferences() {
+		if _, ok := uids[ref.UID]; snapshotUIDs.Has(ref.UID) && !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// restoreObject creates the object, or updates it if it exists, then restores its status. Namespaces that exist are
+// left as is. It returns the UID of the restored object.
+func (h *restoreHandler) restoreObject(e restoreEntry, uids map[types.UID]types.UID) (types.UID, error) {
+	obj := e.object.DeepCopy()
+	obj.SetUID("")
+	obj.SetResourceVersion("")
+	obj.SetCreationTimestamp(metav1.Time{})
+	obj.SetGeneration(0)
+	obj.SetManagedFields(nil)
+	obj.SetDeletionTimestamp(nil)
+	obj.SetDeletionGracePeriodSeconds(nil)
+	refs := obj.GetOwnerReferences()
+	for i := range refs {
+		if uid, ok := uids[refs[i].UID]; ok {
+			refs[i].UID = uid
+		}
+	}
+	obj.SetOwnerReferences(refs)
+	status, hasStatus := obj.Object["status"]
+
+	var client dynamic.ResourceInterface = h.dynamic.Resource(e.resource.gvr())
+	if e.resource.Namespaced {
+		client = h.dynamic.Resource(e.resource.gvr()).Namespace(obj.GetNamespace())
+	}
+
+	existing, err := client.Get(h.ctx, obj.GetName(), metav1.GetOptions{})
+	switch {
+	case apierrors.IsNotFound(err):
+		existing, err = client.Create(h.ctx, obj, metav1.CreateOptions{})
+		if err != nil {
+			return "", err
+		}
+	case err != nil:
+		return "", err
+	case e.resource.Group == "" && e.resource.Resource == "namespaces":
+		return existing.GetUID(), nil
+	default:
+		obj.SetResourceVersion(existing.GetResourceVersion())
+		existing, err = client.Update(h.ctx, obj, metav1.UpdateOptions{})
+		if err != nil {
+			return "", err
+		}
+	}
+
+	if hasStatus && e.resource.Group != "" {
+		existing.Object["status"] = status
+		if _, err := client.UpdateStatus(h.ctx, existing, metav1.UpdateOptions{}); err != nil &&
+			!apierrors.IsNotFound(err) && !apierrors.IsMethodNotSupported(err) {
+			return "", fmt.Errorf("restoring status: %w", err)
+		}
+	}
+	return existing.GetUID(), nil
+}
+
+// restoreMigrations restores the config maps recording the migrations Rancher ran to their content in the snapshot.
+// Config maps missing from the snapshot are deleted, so that their migrations run again. It returns the migrations
+// whose record changed.
+func (h *restoreHandler) restoreMigrations(s *snapshot) ([]string, error) {
+	client := h.dynamic.Resource(configMaps).Namespace(namespace.System)
+
+	var (
+		pending []string
+		errs    []error
+	)
+	for _, name := range h.migrationConfigMaps {
+		data, inSnapshot := s.Migrations[name]
+		existing, err := client.Get(h.ctx, name, metav1.GetOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			errs = append(errs, err)
+			continue
+		}
+
+		switch {
+		case existing == nil || apierrors.IsNotFound(err):
+			if !inSnapshot {
+				continue
+			}
+			cm := &unstructured.Unstructured{}
+			cm.SetAPIVersion("v1")
+			cm.SetKind("ConfigMap")
+			cm.SetNamespace(namespace.System)
+			cm.SetName(name)
+			if err := unstructured.SetNestedStringMap(cm.Object, data, "data"); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			_, err = client.Create(h.ctx, cm, metav1.CreateOptions{})
+		case !inSnapshot:
+			err = client.Delete(h.ctx, name, metav1.DeleteOptions{})
+		default:
+			current, _, _ := unstructured.NestedStringMap(existing.Object, "data")
+			if reflect.DeepEqual(current, data) || (len(current) == 0 && len(data) == 0) {
+				continue
+			}
+			if err = unstructured.SetNestedStringMap(existing.Object, data, "data"); err == nil {
+				_, err = client.Update(h.ctx, existing, metav1.UpdateOptions{})
+			}
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("config map %s: %w", name, err))
+			continue
+		}
+		pending = append(pending, name)
+	}
+	return pending, errors.Join(errs...)
+}
@@ -0,0 +1,212 @@
+package managementbackup
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/rancher/rancher/pkg/auth/providers/local/pbkdf2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestCheckVersion(t *testing.T) {
+	tests := []struct {
+		name            string
+		snapshotVersion string
+		serverVersion   string
+		wantErr         string
+	}{
+		{name: "same version", snapshotVersion: "v2.12.0", serverVersion: "v2.12.0"},
+		{name: "older snapshot", snapshotVersion: "v2.11.3", serverVersion: "v2.12.0"},
+		{name: "newer snapshot", snapshotVersion: "v2.12.1", serverVersion: "v2.12.0", wantErr: "newer than Rancher v2.12.0"},
+		{name: "other major version", snapshotVersion: "v1.6.30", serverVersion: "v2.12.0", wantErr: "not the same major version"},
+		{name: "version without prefix", snapshotVersion: "2.13.0", serverVersion: "v2.12.0", wantErr: "newer than"},
+		{name: "development build", snapshotVersion: "v2.13.0", serverVersion: "dev"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkVersion(tt.snapshotVersion, tt.serverVersion)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestRestoreObjects(t *testing.T) {
+	mgmtCluster := newObject("management.cattle.io/v3", "Cluster", "", "c-abcde")
+	mgmtCluster.SetUID("old-mgmt-cluster")
+	mgmtCluster.Object["spec"] = map[string]any{"displayName": "restored"}
+	mgmtCluster.Object["status"] = map[string]any{"driver": "imported"}
+	provCluster := newObject("provisioning.cattle.io/v1", "Cluster", "fleet-default", "prod")
+	provCluster.SetUID("old-prov-cluster")
+	// the kubeconfig secret is restored before its owner, so that it waits for the provisioning cluster
+	kubeconfig := newObject("v1", "Secret", "fleet-default", "prod-kubeconfig")
+	kubeconfig.SetUID("old-kubeconfig")
+	kubeconfig.SetOwnerReferences([]metav1.OwnerReference{
+		{APIVersion: "provisioning.cattle.io/v1", Kind: "Cluster", Name: "prod", UID: "old-prov-cluster"},
+		{APIVersion: "v1", Kind: "Pod", Name: "unknown", UID: "not-in-snapshot"},
+	})
+	failing := newObject("provisioning.cattle.io/v1", "Cluster", "fleet-default", "failing")
+	failing.SetUID("old-failing")
+	orphan := newObject("v1", "Secret", "fleet-default", "failing-kubeconfig")
+	orphan.SetOwnerReferences([]metav1.OwnerReference{{Name: "failing", UID: "old-failing"}})
+
+	s := &snapshot{Resources: []snapshotResource{
+		{Group: "provisioning.cattle.io", Version: "v1", Resource: "clusters", Namespaced: true,
+			Objects: []unstructured.Unstructured{*provCluster, *failing}},
+		{Group: "management.cattle.io", Version: "v3", Resource: "clusters",
+			Objects: []unstructured.Unstructured{*mgmtCluster}},
+		{Version: "v1", Resource: "secrets", Namespaced: true,
+			Objects: []unstructured.Unstructured{*kubeconfig, *orphan}},
+		{Version: "v1", Resource: "namespaces",
+			Objects: []unstructured.Unstructured{*newObject("v1", "Namespace", "", "fleet-default")}},
+	}}
+
+	existingCluster := newObject("management.cattle.io/v3", "Cluster", "", "c-abcde")
+	existingCluster.SetUID("current-mgmt-cluster")
+	existingCluster.Object["spec"] = map[string]any{"displayName": "current"}
+	existingNamespace := newObject("v1", "Namespace", "", "fleet-default")
+	existingNamespace.SetLabels(map[string]string{"current": "true"})
+	dynamicClient := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), existingCluster, existingNamespace)
+
+	var created []string
+	dynamicClient.PrependReactor("create", "*", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		obj := action.(k8stesting.CreateAction).GetObject().(*unstructured.Unstructured)
+		if obj.GetName() == "failing" {
+			return true, nil, errors.New("denied by webhook")
+		}
+		created = append(created, action.GetResource().Resource+"/"+obj.GetName())
+		obj.SetUID(types.UID("new-" + obj.GetName()))
+		return false, nil, nil
+	})
+
+	h := &restoreHandler{ctx: context.Background(), dynamic: dynamicClient}
+	restored, failed := h.restoreObjects(s)
+
+	assert.Equal(t, 4, restored)
+	assert.Equal(t, []string{
+		"clusters.provisioning.cattle.io fleet-default/failing: denied by webhook",
+		"secrets fleet-default/failing-kubeconfig: owner was not restored",
+	}, failed)
+	assert.Equal(t, []string{"clusters/prod", "secrets/prod-kubeconfig"}, created)
+
+	namespace, err := dynamicClient.Resource(namespaces).Get(context.Background(), "fleet-default", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"current": "true"}, namespace.GetLabels())
+
+	cluster, err := dynamicClient.Resource(schema.GroupVersionResource{Group: "management.cattle.io", Version: "v3", Resource: "clusters"}).
+		Get(context.Background(), "c-abcde", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"displayName": "restored"}, cluster.Object["spec"])
+	assert.Equal(t, map[string]any{"driver": "imported"}, cluster.Object["status"])
+
+	secret, err := dynamicClient.Resource(secrets).Namespace("fleet-default").Get(context.Background(), "prod-kubeconfig", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, types.UID("new-prod"), secret.GetOwnerReferences()[0].UID)
+	assert.Equal(t, types.UID("not-in-snapshot"), secret.GetOwnerReferences()[1].UID)
+}
+
+func TestRestoreLocalUsers(t *testing.T) {
+	users := schema.GroupVersionResource{Group: "management.cattle.io", Version: "v3", Resource: "users"}
+	clientset := fake.NewSimpleClientset()
+	clientset.Discovery().(*fakediscovery.FakeDiscovery).Resources = []*metav1.APIResourceList{{
+		GroupVersion: "management.cattle.io/v3",
+		APIResources: []metav1.APIResource{{Name: "users", Kind: "User", Verbs: metav1.Verbs{"create", "get", "list"}}},
+	}}
+
+	admin := newObject("management.cattle.io/v3", "User", "", "user-admin")
+	admin.SetUID("old-admin")
+	admin.Object["username"] = "admin"
+	password := newObject("v1", "Secret", pbkdf2.LocalUserPasswordsNamespace, "user-admin")
+	password.SetOwnerReferences([]metav1.OwnerReference{{APIVersion: "management.cattle.io/v3", Kind: "User", Name: "user-admin", UID: "old-admin"}})
+	password.Object["data"] = map[string]any{"password": "aGFzaA==", "salt": "c2FsdA=="}
+	bootstrap := newObject("v1", "Secret", "cattle-system", "bootstrap-secret")
+	bootstrap.Object["data"] = map[string]any{"bootstrapPassword": "Ym9vdHN0cmFw"}
+	source := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{users: "UserList", secrets: "SecretList"},
+		admin, password, bootstrap,
+		newObject("v1", "Namespace", "", pbkdf2.LocalUserPasswordsNamespace),
+		newObject("v1", "Namespace", "", "cattle-system"),
+	)
+	c := &collector{discovery: clientset.Discovery(), dynamic: source}
+	s, err := c.collect(context.Background())
+	require.NoError(t, err)
+	data, err := encode(s, []byte("passphrase"))
+	require.NoError(t, err)
+	s, err = decode(data, []byte("passphrase"))
+	require.NoError(t, err)
+
+	// The snapshot is restored to a new local cluster.
+	target := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme())
+	target.PrependReactor("create", "*", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		obj := action.(k8stesting.CreateAction).GetObject().(*unstructured.Unstructured)
+		obj.SetUID(types.UID("new-" + obj.GetName()))
+		return false, nil, nil
+	})
+	h := &restoreHandler{ctx: context.Background(), dynamic: target}
+	restored, failed := h.restoreObjects(s)
+	assert.Empty(t, failed)
+	assert.Equal(t, 5, restored)
+
+	user, err := target.Resource(users).Get(context.Background(), "user-admin", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "admin", user.Object["username"])
+	secret, err := target.Resource(secrets).Namespace(pbkdf2.LocalUserPasswordsNamespace).Get(context.Background(), "user-admin", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"password": "aGFzaA==", "salt": "c2FsdA=="}, secret.Object["data"])
+	require.Len(t, secret.GetOwnerReferences(), 1)
+	assert.Equal(t, user.GetUID(), secret.GetOwnerReferences()[0].UID)
+	secret, err = target.Resource(secrets).Namespace("cattle-system").Get(context.Background(), "bootstrap-secret", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"bootstrapPassword": "Ym9vdHN0cmFw"}, secret.Object["data"])
+}
+
+func TestRestoreMigrations(t *testing.T) {
+	configMap := func(name string, data map[string]any) *unstructured.Unstructured {
+		obj := newObject("v1", "ConfigMap", "cattle-system", name)
+		obj.Object["data"] = data
+		return obj
+	}
+	dynamicClient := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(),
+		configMap("unchanged", map[string]any{"rancherVersion": "v2.12.0"}),
+		configMap("changed", map[string]any{"rancherVersion": "v2.12.0"}),
+		configMap("newer", map[string]any{"rancherVersion": "v2.12.0"}),
+	)
+	h := &restoreHandler{
+		ctx:                 context.Background(),
+		dynamic:             dynamicClient,
+		migrationConfigMaps: []string{"unchanged", "changed", "newer", "removed", "unknown"},
+	}
+
+	pending, err := h.restoreMigrations(&snapshot{Migrations: map[string]map[string]string{
+		"unchanged": {"rancherVersion": "v2.12.0"},
+		"changed":   {"rancherVersion": "v2.11.0"},
+		"removed":   {"rancherVersion": "v2.11.0"},
+	}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"changed", "newer", "removed"}, pending)
+
+	client := dynamicClient.Resource(configMaps).Namespace("cattle-system")
+	changed, err := client.Get(context.Background(), "changed", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"rancherVersion": "v2.11.0"}, changed.Object["data"])
+	_, err = client.Get(context.Background(), "newer", metav1.GetOptions{})
+	assert.True(t, apierrors.IsNotFound(err))
+	removed, err := client.Get(context.Background(), "removed", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"rancherVersion": "v2.11.0"}, removed.Object["data"])
+}
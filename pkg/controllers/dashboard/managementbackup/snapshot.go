@@ -0,0 +1,353 @@
+package managementbackup
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/rancher/rancher/pkg/auth/providers/local/pbkdf2"
+	"github.com/rancher/rancher/pkg/namespace"
+	"golang.org/x/crypto/scrypt"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+)
+
+var (
+	// backedUpGroups are the API groups holding the state of Rancher.
+	backedUpGroups = []string{"management.cattle.io", "provisioning.cattle.io", "rke.cattle.io", "cluster.x-k8s.io"}
+
+	// excludedResources are not backed up: the backups and restores themselves, and provisioning events, which record
+	// the history of provisioning rather than state.
+	excludedResources = sets.New(
+		schema.GroupResource{Group: "management.cattle.io", Resource: "managementbackups"},
+		schema.GroupResource{Group: "management.cattle.io", Resource: "managementrestores"},
+		schema.GroupResource{Group: "management.cattle.io", Resource: "provisioningevents"},
+	)
+
+	// secretNamespaces hold the Secrets referenced by cluster scoped objects, such as cloud credentials, the password
+	// hashes of local users, and the Secrets of Rancher itself, such as its certificates and the bootstrap password.
+	// The Secrets of the namespaces of backed up objects are backed up too.
+	secretNamespaces = []string{
+		namespace.GlobalNamespace,
+		namespace.NodeTemplateGlobalNamespace,
+		pbkdf2.LocalUserPasswordsNamespace,
+		namespace.System,
+	}
+
+	// excludedSecretTypes are the types of Secrets generated by Kubernetes and Helm, which are not Rancher state.
+	excludedSecretTypes = sets.New(string(corev1.SecretTypeServiceAccountToken), "helm.sh/release.v1")
+
+	namespaces = schema.GroupVersionResource{Version: "v1", Resource: "namespaces"}
+	secrets    = schema.GroupVersionResource{Version: "v1", Resource: "secrets"}
+	configMaps = schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+)
+
+// snapshot is the content of a snapshot file, before it is compressed and encrypted.
+type snapshot struct {
+	// RancherVersion is the version of Rancher the snapshot was taken with.
+	RancherVersion string `json:"rancherVersion"`
+	// Time is the time the snapshot was taken at.
+	Time metav1.Time `json:"time"`
+	// Migrations is the data of the config maps recording the migrations Rancher ran, by name.
+	Migrations map[string]map[string]string `json:"migrations,omitempty"`
+	// Resources are the backed up objects, by resource.
+	Resources []snapshotResource `json:"resources"`
+}
+
+// snapshotResource holds the objects of a resource.
+type snapshotResource struct {
+	Group      string                      `json:"group,omitempty"`
+	Version    string                      `json:"version"`
+	Resource   string                      `json:"resource"`
+	Namespaced bool                        `json:"namespaced,omitempty"`
+	Objects    []unstructured.Unstructured `json:"objects"`
+}
+
+func (r *snapshotResource) gvr() schema.GroupVersionResource {
+	return schema.GroupVersionResource{Group: r.Group, Version: r.Version, Resource: r.Resource}
+}
+
+// objectCount returns the number of objects in the snapshot.
+func (s *snapshot) objectCount() int {
+	count := 0
+	for _, r := range s.Resources {
+		count += len(r.Objects)
+	}
+	return count
+}
+
+// collector lists the objects backed up in a snapshot.
+type collector struct {
+	discovery           discovery.DiscoveryInterface
+	dynamic             dynamic.Interface
+	migrationConfigMaps []string
+}
+
+// collect lists the objects of the backed up API groups, then the Namespaces of the namespaced objects and their
+// Secrets, and the migration config maps. Each resource is read in a single list, so that its objects are consistent
+// with each other. Objects being deleted are skipped.
+func (c *collector) collect(ctx context.Context) (*snapshot, error) {
+	resources, err := c.resources()
+	if err != nil {
+		return nil, err
+	}
+
+	s := &snapshot{}
+	objectNamespaces := sets.New(secretNamespaces...)
+	for _, r := range resources {
+		objects, err := c.list(ctx, r.gvr(), "")
+		if err != nil {
+			return nil, fmt.Errorf("listing %s: %w", r.gvr().GroupResource(), err)
+		}
+		for _, obj := range objects {
+			if ns := obj.GetNamespace(); ns != "" {
+				objectNamespaces.Insert(ns)
+			}
+		}
+		r.Objects = objects
+		s.Resources = append(s.Resources, r)
+	}
+
+	nsResource := snapshotResource{Version: namespaces.Version, Resource: namespaces.Resource}
+	secretResource := snapshotResource{Version: secrets.Version, Resource: secrets.Resource, Namespaced: true}
+	for _, ns := range sets.List(objectNamespaces) {
+		obj, err := c.dynamic.Resource(namespaces).Get(ctx, ns, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return nil, fmt.Errorf("getting namespace %s: %w", ns, err)
+		}
+		cleanup(obj)
+		nsResource.Objects = append(nsResource.Objects, *obj)
+
+		objects, err := c.list(ctx, secrets, ns)
+		if err != nil {
+			return nil, fmt.Errorf("listing secrets of namespace %s: %w", ns, err)
+		}
+		for _, obj := range objects {
+			secretType, _, _ := unstructured.NestedString(obj.Object, "type")
+			if !excludedSecretTypes.Has(secretType) {
+				secretResource.Objects = append(secretResource.Objects, obj)
+			}
+		}
+	}
+	s.Resources = append([]snapshotResource{nsResource, secretResource}, s.Resources...)
+
+	s.Migrations = map[string]map[string]string{}
+	for _, name := range c.migrationConfigMaps {
+		obj, err := c.dynamic.Resource(configMaps).Namespace(namespace.System).Get(ctx, name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return nil, fmt.Errorf("getting config map %s: %w", name, err)
+		}
+		data, _, err := unstructured.NestedStringMap(obj.Object, "data")
+		if err != nil {
+			return nil, fmt.Errorf("reading config map %s: %w", name, err)
+		}
+		s.Migrations[name] = data
+	}
+	return s, nil
+}
+
+// resources returns the preferred version of the resources of the backed up API groups that can be listed and
+// created.
+func (c *collector) resources() ([]snapshotResource, error) {
+	lists, err := discovery.ServerPreferredResources(c.discovery)
+	if err != nil {
+		var groupErr *discovery.ErrGroupDiscoveryFailed
+		if !errors.As(err, &groupErr) {
+			return nil, err
+		}
+		for gv, err := range groupErr.Groups {
+			if slices.Contains(backedUpGroups, gv.Group) {
+				return nil, fmt.Errorf("discovering %s: %w", gv, err)
+			}
+		}
+	}
+
+	var result []snapshotResource
+	for _, list := range lists {
+		gv, err := schema.ParseGroupVersion(list.GroupVersion)
+		if err != nil || !slices.Contains(backedUpGroups, gv.Group) {
+			continue
+		}
+		for _, r := range list.APIResources {
+			if strings.Contains(r.Name, "/") || excludedResources.Has(gv.WithResource(r.Name).GroupResource()) ||
+				!slices.Contains(r.Verbs, "list") || !slices.Contains(r.Verbs, "create") {
+				continue
+			}
+			result = append(result, snapshotResource{
+				Group:      gv.Group,
+				Version:    gv.Version,
+				Resource:   r.Name,
+				Namespaced: r.Namespaced,
+			})
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].gvr().String() < result[j].gvr().String()
+	})
+	return result, nil
+}
+
+func (c *collector) list(ctx context.Context, gvr schema.GroupVersionResource, ns string) ([]unstructured.Unstructured, error) {
+	list, err := c.dynamic.Resource(gvr).Namespace(ns).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	objects := make([]unstructured.Unstructured, 0, len(list.Items))
+	for _, obj := range list.Items {
+		if obj.GetDeletionTimestamp() != nil {
+			continue
+		}
+		cleanup(&obj)
+		objects = append(objects, obj)
+	}
+	return objects, nil
+}
+
+// cleanup drops the metadata that is not restored. The UID is kept, since owner references are restored with it.
+func cleanup(obj *unstructured.Unstructured) {
+	obj.SetManagedFields(nil)
+	obj.SetResourceVersion("")
+	obj.SetGeneration(0)
+}
+
+// fileHeader starts snapshot files. It records the parameters of the scrypt key derivation of the encryption key,
+// and is authenticated along with the encrypted snapshot.
+type fileHeader struct {
+	Magic [4]byte
+	// LogN is the base 2 logarithm of the scrypt cost parameter.
+	LogN uint8
+	R    uint32
+	P    uint32
+	Salt [16]byte
+}
+
+var snapshotMagic = [4]byte{'R', 'M', 'B', '1'}
+
+// Key derivation parameters of new snapshots, and the bounds of the parameters of the snapshots that are read.
+const (
+	scryptLogN    = 15
+	scryptR       = 8
+	scryptP       = 1
+	maxScryptLogN = 20
+	maxScryptRP   = 1 << 10
+)
+
+// deriveKey derives the AES-256 key of a snapshot from the encryption key, which can be a passphrase of any length.
+func deriveKey(key []byte, header *fileHeader) ([]byte, error) {
+	if len(key) == 0 {
+		return nil, errors.New("encryption key is empty")
+	}
+	if header.LogN == 0 || header.LogN > maxScryptLogN || header.R == 0 || header.P == 0 ||
+		uint64(header.R)*uint64(header.P) > maxScryptRP {
+		return nil, fmt.Errorf("unsupported key derivation parameters N=2^%d, r=%d, p=%d", header.LogN, header.R, header.P)
+	}
+	return scrypt.Key(key, header.Salt[:], 1<<header.LogN, int(header.R), int(header.P), 32)
+}
+
+// encode serializes, compresses and encrypts a snapshot.
+func encode(s *snapshot, key []byte) ([]byte, error) {
+	header := fileHeader{Magic: snapshotMagic, LogN: scryptLogN, R: scryptR, P: scryptP}
+	if _, err := rand.Read(header.Salt[:]); err != nil {
+		return nil, err
+	}
+	gcm, err := newGCM(key, &header)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	if err := json.NewEncoder(gz).Encode(s); err != nil {
+		return nil, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+
+	var out bytes.Buffer
+	if err := binary.Write(&out, binary.BigEndian, &header); err != nil {
+		return nil, err
+	}
+	headerBytes := out.Bytes()
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	out.Write(nonce)
+	return gcm.Seal(out.Bytes(), nonce, buf.Bytes(), headerBytes), nil
+}
+
+// decode decrypts, decompresses and deserializes a snapshot.
+func decode(data, key []byte) (*snapshot, error) {
+	var header fileHeader
+	headerSize := binary.Size(&header)
+	if len(data) < headerSize {
+		return nil, errors.New("snapshot is truncated")
+	}
+	if err := binary.Read(bytes.NewReader(data), binary.BigEndian, &header); err != nil {
+		return nil, err
+	}
+	if header.Magic != snapshotMagic {
+		return nil, errors.New("not a snapshot file")
+	}
+	gcm, err := newGCM(key, &header)
+	if err != nil {
+		return nil, err
+	}
+	headerBytes, data := data[:headerSize], data[headerSize:]
+	if len(data) < gcm.NonceSize() {
+		return nil, errors.New("snapshot is truncated")
+	}
+	plain, err := gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], headerBytes)
+	if err != nil {
+		return nil, fmt.Errorf("decrypting snapshot: %w", err)
+	}
+
+	gz, err := gzip.NewReader(bytes.NewReader(plain))
+	if err != nil {
+		return nil, err
+	}
+	content, err := io.ReadAll(gz)
+	if err != nil {
+		return nil, err
+	}
+	s := &snapshot{}
+	if err := json.Unmarshal(content, s); err != nil {
+		return nil, fmt.Errorf("reading snapshot: %w", err)
+	}
+	return s, nil
+}
+
+// newGCM returns the AES-256-GCM cipher of the key derived from the encryption key with the parameters of the header.
+func newGCM(key []byte, header *fileHeader) (cipher.AEAD, error) {
+	derived, err := deriveKey(key, header)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(derived)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
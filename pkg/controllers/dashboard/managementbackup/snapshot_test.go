@@ -0,0 +1,177 @@
+package managementbackup
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func newObject(apiVersion, kind, ns, name string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion(apiVersion)
+	obj.SetKind(kind)
+	obj.SetNamespace(ns)
+	obj.SetName(name)
+	return obj
+}
+
+func names(r snapshotResource) []string {
+	var result []string
+	for _, obj := range r.Objects {
+		name := obj.GetName()
+		if obj.GetNamespace() != "" {
+			name = obj.GetNamespace() + "/" + name
+		}
+		result = append(result, name)
+	}
+	return result
+}
+
+func TestCollect(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+	clientset.Discovery().(*fakediscovery.FakeDiscovery).Resources = []*metav1.APIResourceList{
+		{
+			GroupVersion: "management.cattle.io/v3",
+			APIResources: []metav1.APIResource{
+				{Name: "clusters", Kind: "Cluster", Verbs: metav1.Verbs{"create", "get", "list"}},
+				{Name: "clusters/status", Kind: "Cluster", Verbs: metav1.Verbs{"get", "update"}},
+				{Name: "projects", Kind: "Project", Namespaced: true, Verbs: metav1.Verbs{"create", "get", "list"}},
+				{Name: "managementbackups", Kind: "ManagementBackup", Verbs: metav1.Verbs{"create", "get", "list"}},
+			},
+		},
+		{
+			GroupVersion: "apps/v1",
+			APIResources: []metav1.APIResource{
+				{Name: "deployments", Kind: "Deployment", Namespaced: true, Verbs: metav1.Verbs{"create", "get", "list"}},
+			},
+		},
+	}
+
+	cluster := newObject("management.cattle.io/v3", "Cluster", "", "c-abcde")
+	cluster.SetResourceVersion("10")
+	cluster.SetManagedFields([]metav1.ManagedFieldsEntry{{Manager: "rancher"}})
+	project := newObject("management.cattle.io/v3", "Project", "c-abcde", "p-abcde")
+	deleting := newObject("management.cattle.io/v3", "Project", "c-abcde", "p-fghij")
+	deleting.SetFinalizers([]string{"controller.cattle.io/project-precan-alert-controller"})
+	deleting.SetDeletionTimestamp(&metav1.Time{Time: time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)})
+	secret := newObject("v1", "Secret", "c-abcde", "registration")
+	tokenSecret := newObject("v1", "Secret", "c-abcde", "token")
+	tokenSecret.Object["type"] = "kubernetes.io/service-account-token"
+	credential := newObject("v1", "Secret", "cattle-global-data", "cc-abcde")
+	migration := newObject("v1", "ConfigMap", "cattle-system", "forceupgradelogout")
+	migration.Object["data"] = map[string]any{"rancherVersion": "v2.12.0"}
+
+	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{
+			{Group: "management.cattle.io", Version: "v3", Resource: "clusters"}:          "ClusterList",
+			{Group: "management.cattle.io", Version: "v3", Resource: "projects"}:          "ProjectList",
+			{Group: "management.cattle.io", Version: "v3", Resource: "managementbackups"}: "ManagementBackupList",
+			secrets: "SecretList",
+		},
+		cluster, project, deleting, secret, tokenSecret, credential, migration,
+		newObject("v1", "Namespace", "", "c-abcde"),
+		newObject("v1", "Namespace", "", "cattle-global-data"),
+		newObject("management.cattle.io/v3", "ManagementBackup", "", "daily"),
+	)
+
+	c := &collector{
+		discovery:           clientset.Discovery(),
+		dynamic:             dynamicClient,
+		migrationConfigMaps: []string{"forceupgradelogout", "rkecleanupmigration"},
+	}
+	s, err := c.collect(context.Background())
+	require.NoError(t, err)
+
+	require.Len(t, s.Resources, 4)
+	assert.Equal(t, "namespaces", s.Resources[0].Resource)
+	assert.Equal(t, []string{"c-abcde", "cattle-global-data"}, names(s.Resources[0]))
+	assert.Equal(t, "secrets", s.Resources[1].Resource)
+	assert.Equal(t, []string{"c-abcde/registration", "cattle-global-data/cc-abcde"}, names(s.Resources[1]))
+	assert.Equal(t, "clusters", s.Resources[2].Resource)
+	assert.Equal(t, []string{"c-abcde"}, names(s.Resources[2]))
+	assert.Empty(t, s.Resources[2].Objects[0].GetResourceVersion())
+	assert.Empty(t, s.Resources[2].Objects[0].GetManagedFields())
+	assert.Equal(t, "projects", s.Resources[3].Resource)
+	assert.True(t, s.Resources[3].Namespaced)
+	assert.Equal(t, []string{"c-abcde/p-abcde"}, names(s.Resources[3]))
+	assert.Equal(t, map[string]map[string]string{"forceupgradelogout": {"rancherVersion": "v2.12.0"}}, s.Migrations)
+	assert.Equal(t, 6, s.objectCount())
+}
+
+func TestEncodeDecode(t *testing.T) {
+	s := &snapshot{
+		RancherVersion: "v2.12.0",
+		Migrations:     map[string]map[string]string{"forceupgradelogout": {"rancherVersion": "v2.12.0"}},
+		Resources: []snapshotResource{{
+			Group:    "management.cattle.io",
+			Version:  "v3",
+			Resource: "clusters",
+			Objects:  []unstructured.Unstructured{*newObject("management.cattle.io/v3", "Cluster", "", "c-abcde")},
+		}},
+	}
+
+	data, err := encode(s, []byte("passphrase"))
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "c-abcde")
+
+	decoded, err := decode(data, []byte("passphrase"))
+	require.NoError(t, err)
+	assert.Equal(t, s.RancherVersion, decoded.RancherVersion)
+	assert.Equal(t, s.Migrations, decoded.Migrations)
+	assert.Equal(t, s.Resources, decoded.Resources)
+
+	_, err = decode(data, []byte("other"))
+	assert.ErrorContains(t, err, "decrypting snapshot")
+
+	// The key derivation parameters are recorded in the header, which is authenticated.
+	var header fileHeader
+	require.NoError(t, binary.Read(bytes.NewReader(data), binary.BigEndian, &header))
+	assert.Equal(t, snapshotMagic, header.Magic)
+	assert.Equal(t, uint8(scryptLogN), header.LogN)
+	assert.Equal(t, uint32(scryptR), header.R)
+	assert.Equal(t, uint32(scryptP), header.P)
+	tampered := bytes.Clone(data)
+	tampered[binary.Size(&header)-1] ^= 0xff
+	_, err = decode(tampered, []byte("passphrase"))
+	assert.ErrorContains(t, err, "decrypting snapshot")
+	tampered = bytes.Clone(data)
+	tampered[4] = maxScryptLogN + 1
+	_, err = decode(tampered, []byte("passphrase"))
+	assert.ErrorContains(t, err, "unsupported key derivation parameters")
+	_, err = decode([]byte("not a snapshot but long enough for a header"), []byte("passphrase"))
+	assert.ErrorContains(t, err, "not a snapshot file")
+
+	_, err = encode(s, nil)
+	assert.ErrorContains(t, err, "encryption key is empty")
+}
+
+func TestVolumeStore(t *testing.T) {
+	dir := t.TempDir()
+	v := &volumeStore{dir: filepath.Join(dir, "snapshots")}
+
+	// Names are confined to the directory of the store.
+	require.NoError(t, v.Put("../escaped.tar.gz.enc", []byte("data")))
+	_, err := os.Stat(filepath.Join(dir, "escaped.tar.gz.enc"))
+	assert.True(t, os.IsNotExist(err))
+	data, err := v.Get("escaped.tar.gz.enc")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("data"), data)
+
+	require.NoError(t, v.Delete("../escaped.tar.gz.enc"))
+	_, err = v.Get("escaped.tar.gz.enc")
+	assert.True(t, os.IsNotExist(err))
+}
@@ -0,0 +1,194 @@
+package managementbackup
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/namespace"
+	corecontrollers "github.com/rancher/wrangler/v3/pkg/generated/controllers/core/v1"
+)
+
+const (
+	// encryptionKeyEntry is the entry of the encryption Secret holding the key snapshots are encrypted with.
+	encryptionKeyEntry = "encryption-key"
+
+	accessKeyEntry = "accessKey"
+	secretKeyEntry = "secretKey"
+	defaultRegion  = "us-east-1"
+)
+
+// store is a target snapshots are stored on.
+type store interface {
+	Put(name string, data []byte) error
+	Get(name string) ([]byte, error)
+	Delete(name string) error
+}
+
+// newStore returns the store of the target of a backup.
+func newStore(backup *v3.ManagementBackup, secrets corecontrollers.SecretCache) (store, error) {
+	switch {
+	case backup.Spec.Volume != nil && backup.Spec.S3 != nil:
+		return nil, errors.New("only one of volume and s3 can be set")
+	case backup.Spec.Volume != nil:
+		if backup.Spec.Volume.Path == "" {
+			return nil, errors.New("volume path is required")
+		}
+		return &volumeStore{dir: backup.Spec.Volume.Path}, nil
+	case backup.Spec.S3 != nil:
+		return newS3Store(backup.Spec.S3, secrets)
+	default:
+		return nil, errors.New("one of volume and s3 is required")
+	}
+}
+
+// encryptionKey returns the key the snapshots of a backup are encrypted with.
+func encryptionKey(backup *v3.ManagementBackup, secrets corecontrollers.SecretCache) ([]byte, error) {
+	if backup.Spec.EncryptionSecretName == "" {
+		return nil, errors.New("encryption secret name is required")
+	}
+	secret, err := secrets.Get(namespace.System, backup.Spec.EncryptionSecretName)
+	if err != nil {
+		return nil, fmt.Errorf("getting encryption secret: %w", err)
+	}
+	key := secret.Data[encryptionKeyEntry]
+	if len(key) == 0 {
+		return nil, fmt.Errorf("encryption secret %s has no %s entry", backup.Spec.EncryptionSecretName, encryptionKeyEntry)
+	}
+	return key, nil
+}
+
+// volumeStore stores snapshots in a directory.
+type volumeStore struct {
+	dir string
+}
+
+// Put writes the snapshot to a temporary file first, so that a partially written snapshot is never mistaken for a
+// complete one.
+func (v *volumeStore) Put(name string, data []byte) error {
+	if err := os.MkdirAll(v.dir, 0o700); err != nil {
+		return err
+	}
+	tmp := filepath.Join(v.dir, "."+filepath.Base(name)+".tmp")
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, v.path(name))
+}
+
+func (v *volumeStore) Get(name string) ([]byte, error) {
+	return os.ReadFile(v.path(name))
+}
+
+func (v *volumeStore) Delete(name string) error {
+	err := os.Remove(v.path(name))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+// path returns the path of a snapshot. Only the base of the name is used, so that snapshots can't be written or
+// read outside of the directory.
+func (v *volumeStore) path(name string) string {
+	return filepath.Join(v.dir, filepath.Base(name))
+}
+
+// s3Store stores snapshots in an S3 compatible bucket.
+type s3Store struct {
+	client *s3.S3
+	bucket string
+	folder string
+}
+
+func newS3Store(spec *v3.ManagementBackupS3, secrets corecontrollers.SecretCache) (*s3Store, error) {
+	if spec.Bucket == "" {
+		return nil, errors.New("s3 bucket is required")
+	}
+
+	config := aws.NewConfig().
+		WithRegion(spec.Region).
+		WithS3ForcePathStyle(spec.Endpoint != "").
+		WithDisableSSL(spec.Insecure)
+	if spec.Region == "" {
+		config = config.WithRegion(defaultRegion)
+	}
+	if spec.Endpoint != "" {
+		config = config.WithEndpoint(spec.Endpoint)
+	}
+	if spec.EndpointCA != "" {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM([]byte(spec.EndpointCA)) {
+			return nil, errors.New("s3 endpoint CA is not a valid PEM encoded certificate")
+		}
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+		config = config.WithHTTPClient(&http.Client{Transport: transport})
+	}
+	if spec.CredentialSecretName != "" {
+		secret, err := secrets.Get(namespace.System, spec.CredentialSecretName)
+		if err != nil {
+			return nil, fmt.Errorf("getting s3 credential secret: %w", err)
+		}
+		config = config.WithCredentials(credentials.NewStaticCredentials(
+			string(secret.Data[accessKeyEntry]), string(secret.Data[secretKeyEntry]), ""))
+	}
+
+	sess, err := session.NewSession(config)
+	if err != nil {
+		return nil, fmt.Errorf("creating s3 session: %w", err)
+	}
+	return &s3Store{
+		client: s3.New(sess),
+		bucket: spec.Bucket,
+		folder: spec.Folder,
+	}, nil
+}
+
+func (s *s3Store) key(name string) string {
+	return path.Join(s.folder, name)
+}
+
+func (s *s3Store) Put(name string, data []byte) error {
+	_, err := s.client.PutObject(&s3.PutObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(s.key(name)),
+		Body:   bytes.NewReader(data),
+	})
+	return err
+}
+
+func (s *s3Store) Get(name string) ([]byte, error) {
+	out, err := s.client.GetObject(&s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(s.key(name)),
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer out.Body.Close()
+	return io.ReadAll(out.Body)
+}
+
+func (s *s3Store) Delete(name string) error {
+	_, err := s.client.DeleteObject(&s3.DeleteObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(s.key(name)),
+	})
+	return err
+}
@@ -91,10 +91,13 @@ func crtTokenReaderRoleBindingName(namespace string, subject v1.Subject) string
 	"nodes":                       "management.cattle.io",
 	"nodepools":                   "management.cattle.io",
 	"projects":                    "management.cattle.io",
+	"provisioningevents":          "management.cattle.io",
 	"etcdsnapshots":               "rke.cattle.io",
 	"etcdsnapshotsaves":           "operation.cattle.io",
 	"etcdsnapshotrestores":        "operation.cattle.io",
 	"encryptionkeyrotations":      "operation.cattle.io",
+	"supportbundles":              "operation.cattle.io",
+	"nodediagnostics":             "operation.cattle.io",
 }
 
 type crtbLifecycle struct {
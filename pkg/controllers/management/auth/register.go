@@ -103,6 +103,7 @@ func RegisterEarly(ctx context.Context, management *config.ManagementContext, cl
 	management.Management.AuthConfigs("").AddHandler(ctx, authConfigControllerName, ac.sync)
 	management.Management.UserAttributes("").AddHandler(ctx, userAttributeController, ua.sync)
 	management.Management.Settings("").AddHandler(ctx, authSettingController, s.sync)
+	management.Wrangler.Mgmt.UserRetentionPolicy().OnChange(ctx, userRetentionPolicyController, s.syncUserRetentionPolicy)
 	globalroles.Register(ctx, management, clusterManager)
 
 	// Register aggregated-roletemplate controllers
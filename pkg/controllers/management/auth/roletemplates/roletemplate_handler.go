@@ -28,6 +28,7 @@
 		"nodes":                       "management.cattle.io",
 		"nodepools":                   "management.cattle.io",
 		"projects":                    "management.cattle.io",
+		"provisioningevents":          "management.cattle.io",
 		"etcdsnapshots":               "rke.cattle.io",
 	}
 	projectManagementPlaneResources = map[string]string{
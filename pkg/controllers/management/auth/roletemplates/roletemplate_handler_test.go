@@ -723,6 +723,11 @@ func TestGetManagementPlaneRules(t *testing.T) {
 					APIGroups: []string{"management.cattle.io"},
 					Verbs:     []string{"*"},
 				},
+				{
+					Resources: []string{"provisioningevents"},
+					APIGroups: []string{"management.cattle.io"},
+					Verbs:     []string{"*"},
+				},
 				{
 					Resources: []string{"etcdsnapshots"},
 					APIGroups: []string{"rke.cattle.io"},
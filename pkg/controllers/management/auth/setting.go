@@ -3,6 +3,7 @@
 import (
 	"context"
 
+	apiv3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
 	"github.com/rancher/rancher/pkg/auth/providerrefresh"
 	"github.com/rancher/rancher/pkg/auth/providers/azure"
 	"github.com/rancher/rancher/pkg/auth/userretention"
@@ -14,7 +15,10 @@
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
-const authSettingController = "mgmt-auth-settings-controller"
+const (
+	authSettingController         = "mgmt-auth-settings-controller"
+	userRetentionPolicyController = "mgmt-auth-user-retention-policy-controller"
+)
 
 type SettingController struct {
 	ensureUserRetentionLabels func() error
@@ -32,6 +36,14 @@ func newAuthSettingController(ctx context.Context, mgmt *config.ManagementContex
 	}
 }
 
+// syncUserRetentionPolicy updates the retention labels of all users when a user retention policy changes.
+func (c *SettingController) syncUserRetentionPolicy(_ string, obj *apiv3.UserRetentionPolicy) (*apiv3.UserRetentionPolicy, error) {
+	if err := c.ensureUserRetentionLabels(); err != nil {
+		logrus.Errorf("error updating retention labels for users: %v", err)
+	}
+	return obj, nil
+}
+
 // sync is called periodically and on real updates
 func (c *SettingController) sync(key string, obj *v3.Setting) (runtime.Object, error) {
 	if obj == nil || obj.DeletionTimestamp != nil {
@@ -3,6 +3,7 @@
 import (
 	"testing"
 
+	apiv3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
 	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
 	"github.com/rancher/rancher/pkg/settings"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -60,3 +61,27 @@ func TestSettingsSyncScheduleUserRetention(t *testing.T) {
 		t.Fatalf("Expected scheduleRetentionCalledTimes: %d got %d", want, got)
 	}
 }
+
+func TestSyncUserRetentionPolicyEnsureUserRetentionLabels(t *testing.T) {
+	var ensureLabelsCalledTimes int
+	controller := &SettingController{
+		ensureUserRetentionLabels: func() error {
+			ensureLabelsCalledTimes++
+			return nil
+		},
+	}
+
+	// Labels are also updated when a policy is deleted.
+	for _, policy := range []*apiv3.UserRetentionPolicy{
+		{ObjectMeta: metav1.ObjectMeta{Name: "contractors"}},
+		nil,
+	} {
+		if _, err := controller.syncUserRetentionPolicy("contractors", policy); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if want, got := 2, ensureLabelsCalledTimes; want != got {
+		t.Fatalf("Expected ensureLabelsCalledTimes: %d got %d", want, got)
+	}
+}
@@ -0,0 +1,360 @@
+package breakglass
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/auth/creatorid"
+	"github.com/rancher/rancher/pkg/controllers/managementuser/clusterauthtoken/common"
+	mgmtcontrollers "github.com/rancher/rancher/pkg/generated/controllers/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/settings"
+	"github.com/rancher/rancher/pkg/wrangler"
+	corecontrollers "github.com/rancher/wrangler/v3/pkg/generated/controllers/core/v1"
+	"github.com/rancher/wrangler/v3/pkg/name"
+	"github.com/sirupsen/logrus"
+	authzv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	authv1 "k8s.io/client-go/kubernetes/typed/authorization/v1"
+)
+
+const (
+	// TokenField is the entry of the Secret of a credential holding its token.
+	TokenField = "token"
+
+	tokenSecretPrefix = "break-glass"
+)
+
+type creatorVerifier interface {
+	Creator(ctx context.Context, obj metav1.Object) (string, error)
+}
+
+type handler struct {
+	ctx                  context.Context
+	credentials          mgmtcontrollers.BreakGlassCredentialController
+	credentialCache      mgmtcontrollers.BreakGlassCredentialCache
+	clusterCache         mgmtcontrollers.ClusterCache
+	userCache            mgmtcontrollers.UserCache
+	userAttributeCache   mgmtcontrollers.UserAttributeCache
+	secretCache          corecontrollers.SecretCache
+	secretClient         corecontrollers.SecretClient
+	subjectAccessReviews authv1.SubjectAccessReviewInterface
+	creators             creatorVerifier
+	now                  func() time.Time
+}
+
+// Register registers the controller issuing the tokens of break-glass credentials, expiring them, and recording the
+// acknowledgement of their usage.
+func Register(ctx context.Context, wContext *wrangler.Context) {
+	h := &handler{
+		ctx:                  ctx,
+		credentials:          wContext.Mgmt.BreakGlassCredential(),
+		credentialCache:      wContext.Mgmt.BreakGlassCredential().Cache(),
+		clusterCache:         wContext.Mgmt.Cluster().Cache(),
+		userCache:            wContext.Mgmt.User().Cache(),
+		userAttributeCache:   wContext.Mgmt.UserAttribute().Cache(),
+		secretCache:          wContext.Core.Secret().Cache(),
+		secretClient:         wContext.Core.Secret(),
+		subjectAccessReviews: wContext.K8s.AuthorizationV1().SubjectAccessReviews(),
+		creators:             creatorid.NewVerifier(wContext.K8s.AdmissionregistrationV1().ValidatingAdmissionPolicyBindings()),
+		now:                  time.Now,
+	}
+	wContext.Mgmt.BreakGlassCredential().OnChange(ctx, "break-glass-credential", h.onChange)
+}
+
+func (h *handler) onChange(_ string, credential *v3.BreakGlassCredential) (*v3.BreakGlassCredential, error) {
+	if credential == nil || credential.DeletionTimestamp != nil {
+		return credential, nil
+	}
+
+	now := h.now()
+	status := credential.Status.DeepCopy()
+	switch {
+	case meta.IsStatusConditionTrue(status.Conditions, v3.BreakGlassCredentialConditionExpired):
+	case status.IssuedAt == nil:
+		if err := h.issue(credential, status, now); err != nil {
+			return credential, err
+		}
+	case !now.Before(status.ExpiresAt.Time):
+		if err := h.expire(status); err != nil {
+			return credential, err
+		}
+	}
+
+	_, acknowledge := credential.Annotations[v3.BreakGlassUsageAcknowledgeAnnotation]
+	if acknowledge {
+		status.AcknowledgedRequestCount = status.RequestCount
+	}
+	setUsageConditions(status)
+
+	if !equality.Semantic.DeepEqual(credential.Status, *status) {
+		credential = credential.DeepCopy()
+		credential.Status = *status
+		var err error
+		if credential, err = h.credentials.UpdateStatus(credential); err != nil {
+			return credential, err
+		}
+	}
+
+	if acknowledge {
+		logrus.Infof("[break-glass] usage of credential %s for user %s on cluster %s acknowledged: %d requests",
+			credential.Name, credential.Spec.UserName, credential.Spec.ClusterName, status.AcknowledgedRequestCount)
+		credential = credential.DeepCopy()
+		delete(credential.Annotations, v3.BreakGlassUsageAcknowledgeAnnotation)
+		var err error
+		if credential, err = h.credentials.Update(credential); err != nil {
+			return credential, err
+		}
+		// Credentials of the cluster may be waiting for the usage to be reviewed to be issued.
+		if err := h.enqueuePending(credential.Spec.ClusterName); err != nil {
+			return credential, err
+		}
+	}
+
+	if status.IssuedAt != nil && !meta.IsStatusConditionTrue(status.Conditions, v3.BreakGlassCredentialConditionExpired) {
+		h.credentials.EnqueueAfter(credential.Name, status.ExpiresAt.Sub(now))
+	}
+	return credential, nil
+}
+
+// issue signs the token of a credential and stores it in the Secret of the credential. Credentials which can't be
+// issued get a False Issued condition.
+func (h *handler) issue(credential *v3.BreakGlassCredential, status *v3.BreakGlassCredentialStatus, now time.Time) error {
+	reason, message, err := h.checkIssuable(credential)
+	if err != nil {
+		return err
+	}
+	if reason != "" {
+		meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+			Type:    v3.BreakGlassCredentialConditionIssued,
+			Status:  metav1.ConditionFalse,
+			Reason:  reason,
+			Message: message,
+		})
+		return nil
+	}
+
+	var groups []string
+	userAttribute, err := h.userAttributeCache.Get(credential.Spec.UserName)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	if userAttribute != nil {
+		for _, gp := range userAttribute.GroupPrincipals {
+			for i := range gp.Items {
+				groups = append(groups, gp.Items[i].Name)
+			}
+		}
+		sort.Strings(groups)
+	}
+
+	ttl := settings.BreakGlassCredentialMaxTTL.GetDuration()
+	if credential.Spec.TTLSeconds > 0 && time.Duration(credential.Spec.TTLSeconds)*time.Second < ttl {
+		ttl = time.Duration(credential.Spec.TTLSeconds) * time.Second
+	}
+	issuedAt := now.Truncate(time.Second)
+	expiresAt := issuedAt.Add(ttl)
+
+	key, kid, err := ensureSigningKey(h.secretCache, h.secretClient)
+	if err != nil {
+		return err
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, common.BreakGlassClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    common.BreakGlassIssuer,
+			Subject:   credential.Spec.UserName,
+			Audience:  jwt.ClaimStrings{credential.Spec.ClusterName},
+			ID:        string(credential.UID),
+			IssuedAt:  jwt.NewNumericDate(issuedAt),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+		Groups: groups,
+	})
+	token.Header["kid"] = kid
+	signed, err := token.SignedString(key)
+	if err != nil {
+		return fmt.Errorf("signing token: %w", err)
+	}
+
+	secretName := name.SafeConcatName(tokenSecretPrefix, credential.Name)
+	if err := h.storeToken(credential, secretName, signed); err != nil {
+		return err
+	}
+
+	logrus.Infof("[break-glass] issued credential %s for user %s on cluster %s, expiring at %s",
+		credential.Name, credential.Spec.UserName, credential.Spec.ClusterName, expiresAt.UTC().Format(time.RFC3339))
+	status.IssuedAt = &metav1.Time{Time: issuedAt}
+	status.ExpiresAt = &metav1.Time{Time: expiresAt}
+	status.SecretName = secretName
+	status.Groups = groups
+	meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+		Type:    v3.BreakGlassCredentialConditionIssued,
+		Status:  metav1.ConditionTrue,
+		Reason:  "Issued",
+		Message: fmt.Sprintf("token stored in secret %s/%s", SigningKeySecretNamespace, secretName),
+	})
+	return nil
+}
+
+// checkIssuable returns the reason and message of the Issued condition of a credential which can't be issued, or an
+// empty reason if it can.
+func (h *handler) checkIssuable(credential *v3.BreakGlassCredential) (string, string, error) {
+	cluster, err := h.clusterCache.Get(credential.Spec.ClusterName)
+	if apierrors.IsNotFound(err) {
+		return "ClusterNotFound", fmt.Sprintf("cluster %s not found", credential.Spec.ClusterName), nil
+	} else if err != nil {
+		return "", "", err
+	}
+	if !cluster.Spec.LocalClusterAuthEndpoint.Enabled {
+		return "AuthorizedClusterEndpointDisabled", fmt.Sprintf("cluster %s doesn't have the authorized cluster endpoint enabled", cluster.Name), nil
+	}
+
+	user, err := h.userCache.Get(credential.Spec.UserName)
+	if apierrors.IsNotFound(err) {
+		return "UserNotFound", fmt.Sprintf("user %s not found", credential.Spec.UserName), nil
+	} else if err != nil {
+		return "", "", err
+	}
+	if !user.GetEnabled() {
+		return "UserDisabled", fmt.Sprintf("user %s is disabled", user.Name), nil
+	}
+
+	// Creating a credential must not give access to the cluster as a user its creator can't act as.
+	creatorID, err := h.creators.Creator(h.ctx, credential)
+	if errors.Is(err, creatorid.ErrUntrusted) {
+		return "CreatorNotRecorded", err.Error(), nil
+	} else if err != nil {
+		return "", "", err
+	}
+	if creatorID != user.Name {
+		response, err := h.subjectAccessReviews.Create(h.ctx, &authzv1.SubjectAccessReview{
+			Spec: authzv1.SubjectAccessReviewSpec{
+				ResourceAttributes: &authzv1.ResourceAttributes{
+					Verb:     "impersonate",
+					Resource: "users",
+					Name:     user.Name,
+				},
+				User: creatorID,
+			},
+		}, metav1.CreateOptions{})
+		if err != nil {
+			return "", "", fmt.Errorf("failed to create a SubjectAccessReview: %w", err)
+		}
+		if !response.Status.Allowed {
+			return "CreatorNotAllowed", fmt.Sprintf("creator %s is not allowed to impersonate user %s", creatorID, user.Name), nil
+		}
+	}
+
+	// The usage of break-glass credentials must be reviewed before more are issued for the cluster.
+	credentials, err := h.credentialCache.List(labels.Everything())
+	if err != nil {
+		return "", "", err
+	}
+	for _, other := range credentials {
+		if other.Spec.ClusterName == credential.Spec.ClusterName &&
+			meta.IsStatusConditionFalse(other.Status.Conditions, v3.BreakGlassCredentialConditionUsageReviewed) {
+			return "UsageNotReviewed", fmt.Sprintf("usage of credential %s must be acknowledged first", other.Name), nil
+		}
+	}
+	return "", "", nil
+}
+
+func (h *handler) storeToken(credential *v3.BreakGlassCredential, secretName, token string) error {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      secretName,
+			Namespace: SigningKeySecretNamespace,
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: v3.SchemeGroupVersion.String(),
+				Kind:       "BreakGlassCredential",
+				Name:       credential.Name,
+				UID:        credential.UID,
+			}},
+		},
+		Data: map[string][]byte{TokenField: []byte(token)},
+	}
+	_, err := h.secretClient.Create(secret)
+	if apierrors.IsAlreadyExists(err) {
+		// A previous attempt stored a token but failed to update the status.
+		existing, err := h.secretClient.Get(secret.Namespace, secret.Name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		existing = existing.DeepCopy()
+		existing.OwnerReferences = secret.OwnerReferences
+		existing.Data = secret.Data
+		_, err = h.secretClient.Update(existing)
+		return err
+	}
+	return err
+}
+
+// expire deletes the token of a credential once it expired.
+func (h *handler) expire(status *v3.BreakGlassCredentialStatus) error {
+	if status.SecretName != "" {
+		err := h.secretClient.Delete(SigningKeySecretNamespace, status.SecretName, &metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+	}
+	status.SecretName = ""
+	meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+		Type:    v3.BreakGlassCredentialConditionExpired,
+		Status:  metav1.ConditionTrue,
+		Reason:  "Expired",
+		Message: "the credential expired and its token was deleted",
+	})
+	return nil
+}
+
+// enqueuePending enqueues the credentials of a cluster which were not issued yet.
+func (h *handler) enqueuePending(clusterName string) error {
+	credentials, err := h.credentialCache.List(labels.Everything())
+	if err != nil {
+		return err
+	}
+	for _, credential := range credentials {
+		if credential.Spec.ClusterName == clusterName && credential.Status.IssuedAt == nil {
+			h.credentials.Enqueue(credential.Name)
+		}
+	}
+	return nil
+}
+
+// setUsageConditions sets the Used and UsageReviewed conditions from the usage reported by the downstream cluster.
+func setUsageConditions(status *v3.BreakGlassCredentialStatus) {
+	if status.RequestCount == 0 {
+		return
+	}
+	meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+		Type:    v3.BreakGlassCredentialConditionUsed,
+		Status:  metav1.ConditionTrue,
+		Reason:  "Used",
+		Message: fmt.Sprintf("%d requests reported by the cluster", status.RequestCount),
+	})
+	if status.AcknowledgedRequestCount >= status.RequestCount {
+		meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+			Type:    v3.BreakGlassCredentialConditionUsageReviewed,
+			Status:  metav1.ConditionTrue,
+			Reason:  "Acknowledged",
+			Message: "the usage of the credential was acknowledged",
+		})
+		return
+	}
+	meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+		Type:   v3.BreakGlassCredentialConditionUsageReviewed,
+		Status: metav1.ConditionFalse,
+		Reason: "NotAcknowledged",
+		Message: fmt.Sprintf("%d requests must be acknowledged with the %s annotation",
+			status.RequestCount-status.AcknowledgedRequestCount, v3.BreakGlassUsageAcknowledgeAnnotation),
+	})
+}
@@ -0,0 +1,375 @@
+package breakglass
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/auth/creatorid"
+	"github.com/rancher/rancher/pkg/controllers/managementuser/clusterauthtoken/common"
+	"github.com/rancher/wrangler/v3/pkg/generic/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	authzv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// policyEnforcedAt is when the admission policy recording the creator of credentials was bound.
+var policyEnforcedAt = time.Date(2026, 2, 1, 12, 0, 0, 0, time.UTC)
+
+type mocks struct {
+	credentials        *fake.MockNonNamespacedControllerInterface[*v3.BreakGlassCredential, *v3.BreakGlassCredentialList]
+	credentialCache    *fake.MockNonNamespacedCacheInterface[*v3.BreakGlassCredential]
+	clusterCache       *fake.MockNonNamespacedCacheInterface[*v3.Cluster]
+	userCache          *fake.MockNonNamespacedCacheInterface[*v3.User]
+	userAttributeCache *fake.MockNonNamespacedCacheInterface[*v3.UserAttribute]
+	secretCache        *fake.MockCacheInterface[*corev1.Secret]
+	secretClient       *fake.MockClientInterface[*corev1.Secret, *corev1.SecretList]
+}
+
+func newTestHandler(t *testing.T, now time.Time) (*handler, *mocks) {
+	ctrl := gomock.NewController(t)
+	m := &mocks{
+		credentials:        fake.NewMockNonNamespacedControllerInterface[*v3.BreakGlassCredential, *v3.BreakGlassCredentialList](ctrl),
+		credentialCache:    fake.NewMockNonNamespacedCacheInterface[*v3.BreakGlassCredential](ctrl),
+		clusterCache:       fake.NewMockNonNamespacedCacheInterface[*v3.Cluster](ctrl),
+		userCache:          fake.NewMockNonNamespacedCacheInterface[*v3.User](ctrl),
+		userAttributeCache: fake.NewMockNonNamespacedCacheInterface[*v3.UserAttribute](ctrl),
+		secretCache:        fake.NewMockCacheInterface[*corev1.Secret](ctrl),
+		secretClient:       fake.NewMockClientInterface[*corev1.Secret, *corev1.SecretList](ctrl),
+	}
+	k8sClient := k8sfake.NewSimpleClientset(&admissionregistrationv1.ValidatingAdmissionPolicyBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: creatorid.PolicyName, CreationTimestamp: metav1.NewTime(policyEnforcedAt)},
+		Spec: admissionregistrationv1.ValidatingAdmissionPolicyBindingSpec{
+			PolicyName:        creatorid.PolicyName,
+			ValidationActions: []admissionregistrationv1.ValidationAction{admissionregistrationv1.Deny},
+		},
+	})
+	k8sClient.PrependReactor("create", "subjectaccessreviews",
+		func(action k8stesting.Action) (bool, runtime.Object, error) {
+			review := action.(k8stesting.CreateAction).GetObject().(*authzv1.SubjectAccessReview)
+			assert.Equal(t, "impersonate", review.Spec.ResourceAttributes.Verb)
+			assert.Equal(t, "users", review.Spec.ResourceAttributes.Resource)
+			review.Status.Allowed = review.Spec.User == "user-admin"
+			return true, review, nil
+		},
+	)
+	return &handler{
+		ctx:                  context.Background(),
+		credentials:          m.credentials,
+		credentialCache:      m.credentialCache,
+		clusterCache:         m.clusterCache,
+		userCache:            m.userCache,
+		userAttributeCache:   m.userAttributeCache,
+		secretCache:          m.secretCache,
+		secretClient:         m.secretClient,
+		subjectAccessReviews: k8sClient.AuthorizationV1().SubjectAccessReviews(),
+		creators:             creatorid.NewVerifier(k8sClient.AdmissionregistrationV1().ValidatingAdmissionPolicyBindings()),
+		now:                  func() time.Time { return now },
+	}, m
+}
+
+func newSigningKeySecret(t *testing.T) *corev1.Secret {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	publicKeyDER, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: SigningKeySecretName, Namespace: SigningKeySecretNamespace},
+		Data: map[string][]byte{
+			"key2.pem": pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}),
+			"key2.pub": pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicKeyDER}),
+		},
+	}
+}
+
+func newCredential() *v3.BreakGlassCredential {
+	return &v3.BreakGlassCredential{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "emergency",
+			UID:               "uid",
+			CreationTimestamp: metav1.NewTime(policyEnforcedAt.Add(24 * time.Hour)),
+			Annotations:       map[string]string{creatorid.Annotation: "u-alice"},
+		},
+		Spec: v3.BreakGlassCredentialSpec{
+			ClusterName: "c-m-abc",
+			UserName:    "u-alice",
+			TTLSeconds:  3600,
+		},
+	}
+}
+
+func createdBy(creatorID string) *v3.BreakGlassCredential {
+	credential := newCredential()
+	credential.Annotations = map[string]string{}
+	if creatorID != "" {
+		credential.Annotations[creatorid.Annotation] = creatorID
+	}
+	return credential
+}
+
+func newCluster(aceEnabled bool) *v3.Cluster {
+	cluster := &v3.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "c-m-abc"}}
+	cluster.Spec.LocalClusterAuthEndpoint.Enabled = aceEnabled
+	return cluster
+}
+
+func TestOnChangeIssues(t *testing.T) {
+	// Credentials are issued for their creator, or for users their creator may impersonate.
+	for _, creatorID := range []string{"u-alice", "user-admin"} {
+		t.Run(creatorID, func(t *testing.T) {
+			now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+			h, m := newTestHandler(t, now)
+			keySecret := newSigningKeySecret(t)
+
+			m.clusterCache.EXPECT().Get("c-m-abc").Return(newCluster(true), nil)
+			m.userCache.EXPECT().Get("u-alice").Return(&v3.User{ObjectMeta: metav1.ObjectMeta{Name: "u-alice"}}, nil)
+			m.credentialCache.EXPECT().List(labels.Everything()).Return(nil, nil)
+			m.userAttributeCache.EXPECT().Get("u-alice").Return(&v3.UserAttribute{
+				GroupPrincipals: map[string]v3.Principals{
+					"github": {Items: []v3.Principal{
+						{ObjectMeta: metav1.ObjectMeta{Name: "github_team://2"}},
+						{ObjectMeta: metav1.ObjectMeta{Name: "github_team://1"}},
+					}},
+				},
+			}, nil)
+			m.secretCache.EXPECT().Get(SigningKeySecretNamespace, SigningKeySecretName).Return(keySecret, nil)
+
+			var token string
+			m.secretClient.EXPECT().Create(gomock.Any()).DoAndReturn(func(secret *corev1.Secret) (*corev1.Secret, error) {
+				assert.Equal(t, "break-glass-emergency", secret.Name)
+				assert.Equal(t, SigningKeySecretNamespace, secret.Namespace)
+				require.Len(t, secret.OwnerReferences, 1)
+				assert.Equal(t, "BreakGlassCredential", secret.OwnerReferences[0].Kind)
+				token = string(secret.Data[TokenField])
+				return secret, nil
+			})
+			m.credentials.EXPECT().UpdateStatus(gomock.Any()).DoAndReturn(func(credential *v3.BreakGlassCredential) (*v3.BreakGlassCredential, error) {
+				status := credential.Status
+				assert.Equal(t, now, status.IssuedAt.Time)
+				assert.Equal(t, now.Add(time.Hour), status.ExpiresAt.Time)
+				assert.Equal(t, "break-glass-emergency", status.SecretName)
+				assert.Equal(t, []string{"github_team://1", "github_team://2"}, status.Groups)
+				assert.True(t, meta.IsStatusConditionTrue(status.Conditions, v3.BreakGlassCredentialConditionIssued))
+				return credential, nil
+			})
+			m.credentials.EXPECT().EnqueueAfter("emergency", time.Hour)
+
+			_, err := h.onChange("", createdBy(creatorID))
+			require.NoError(t, err)
+
+			publicKey, err := jwt.ParseRSAPublicKeyFromPEM(keySecret.Data["key2.pub"])
+			require.NoError(t, err)
+			claims := &common.BreakGlassClaims{}
+			_, err = jwt.ParseWithClaims(token, claims, func(*jwt.Token) (any, error) { return publicKey, nil },
+				jwt.WithValidMethods([]string{jwt.SigningMethodRS256.Alg()}),
+				jwt.WithTimeFunc(func() time.Time { return now.Add(time.Minute) }),
+				jwt.WithIssuer(common.BreakGlassIssuer),
+				jwt.WithAudience("c-m-abc"),
+			)
+			require.NoError(t, err)
+			assert.Equal(t, "u-alice", claims.Subject)
+			assert.Equal(t, "uid", claims.ID)
+			assert.Equal(t, []string{"github_team://1", "github_team://2"}, claims.Groups)
+		})
+	}
+}
+
+func TestOnChangeNotIssuable(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	notFound := apierrors.NewNotFound(schema.GroupResource{}, "")
+
+	tests := map[string]struct {
+		credential *v3.BreakGlassCredential
+		setup      func(m *mocks)
+		wantReason string
+	}{
+		"cluster not found": {
+			setup: func(m *mocks) {
+				m.clusterCache.EXPECT().Get("c-m-abc").Return(nil, notFound)
+			},
+			wantReason: "ClusterNotFound",
+		},
+		"authorized cluster endpoint disabled": {
+			setup: func(m *mocks) {
+				m.clusterCache.EXPECT().Get("c-m-abc").Return(newCluster(false), nil)
+			},
+			wantReason: "AuthorizedClusterEndpointDisabled",
+		},
+		"user disabled": {
+			setup: func(m *mocks) {
+				m.clusterCache.EXPECT().Get("c-m-abc").Return(newCluster(true), nil)
+				enabled := false
+				m.userCache.EXPECT().Get("u-alice").Return(&v3.User{ObjectMeta: metav1.ObjectMeta{Name: "u-alice"}, Enabled: &enabled}, nil)
+			},
+			wantReason: "UserDisabled",
+		},
+		"credential without a creator": {
+			credential: createdBy(""),
+			setup: func(m *mocks) {
+				m.clusterCache.EXPECT().Get("c-m-abc").Return(newCluster(true), nil)
+				m.userCache.EXPECT().Get("u-alice").Return(&v3.User{ObjectMeta: metav1.ObjectMeta{Name: "u-alice"}}, nil)
+			},
+			wantReason: "CreatorNotRecorded",
+		},
+		"credential created before the creator was recorded at admission": {
+			credential: func() *v3.BreakGlassCredential {
+				credential := newCredential()
+				credential.CreationTimestamp = metav1.NewTime(policyEnforcedAt.Add(-time.Hour))
+				return credential
+			}(),
+			setup: func(m *mocks) {
+				m.clusterCache.EXPECT().Get("c-m-abc").Return(newCluster(true), nil)
+				m.userCache.EXPECT().Get("u-alice").Return(&v3.User{ObjectMeta: metav1.ObjectMeta{Name: "u-alice"}}, nil)
+			},
+			wantReason: "CreatorNotRecorded",
+		},
+		"creator not allowed to impersonate the user": {
+			credential: createdBy("u-mallory"),
+			setup: func(m *mocks) {
+				m.clusterCache.EXPECT().Get("c-m-abc").Return(newCluster(true), nil)
+				m.userCache.EXPECT().Get("u-alice").Return(&v3.User{ObjectMeta: metav1.ObjectMeta{Name: "u-alice"}}, nil)
+			},
+			wantReason: "CreatorNotAllowed",
+		},
+		"usage of another credential not reviewed": {
+			setup: func(m *mocks) {
+				m.clusterCache.EXPECT().Get("c-m-abc").Return(newCluster(true), nil)
+				m.userCache.EXPECT().Get("u-alice").Return(&v3.User{ObjectMeta: metav1.ObjectMeta{Name: "u-alice"}}, nil)
+				used := newCredential()
+				used.Name = "used"
+				meta.SetStatusCondition(&used.Status.Conditions, metav1.Condition{
+					Type:   v3.BreakGlassCredentialConditionUsageReviewed,
+					Status: metav1.ConditionFalse,
+					Reason: "NotAcknowledged",
+				})
+				m.credentialCache.EXPECT().List(labels.Everything()).Return([]*v3.BreakGlassCredential{used}, nil)
+			},
+			wantReason: "UsageNotReviewed",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			h, m := newTestHandler(t, now)
+			tt.setup(m)
+			m.credentials.EXPECT().UpdateStatus(gomock.Any()).DoAndReturn(func(credential *v3.BreakGlassCredential) (*v3.BreakGlassCredential, error) {
+				assert.Nil(t, credential.Status.IssuedAt)
+				condition := meta.FindStatusCondition(credential.Status.Conditions, v3.BreakGlassCredentialConditionIssued)
+				require.NotNil(t, condition)
+				assert.Equal(t, metav1.ConditionFalse, condition.Status)
+				assert.Equal(t, tt.wantReason, condition.Reason)
+				return credential, nil
+			})
+
+			credential := tt.credential
+			if credential == nil {
+				credential = newCredential()
+			}
+			_, err := h.onChange("", credential)
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestOnChangeExpires(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	h, m := newTestHandler(t, now)
+
+	credential := newCredential()
+	credential.Status.IssuedAt = &metav1.Time{Time: now.Add(-time.Hour)}
+	credential.Status.ExpiresAt = &metav1.Time{Time: now}
+	credential.Status.SecretName = "break-glass-emergency"
+
+	m.secretClient.EXPECT().Delete(SigningKeySecretNamespace, "break-glass-emergency", gomock.Any()).Return(nil)
+	m.credentials.EXPECT().UpdateStatus(gomock.Any()).DoAndReturn(func(credential *v3.BreakGlassCredential) (*v3.BreakGlassCredential, error) {
+		assert.Empty(t, credential.Status.SecretName)
+		assert.True(t, meta.IsStatusConditionTrue(credential.Status.Conditions, v3.BreakGlassCredentialConditionExpired))
+		return credential, nil
+	})
+
+	_, err := h.onChange("", credential)
+	require.NoError(t, err)
+}
+
+func TestOnChangeUsage(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	used := func() *v3.BreakGlassCredential {
+		credential := newCredential()
+		credential.Status.IssuedAt = &metav1.Time{Time: now.Add(-time.Hour)}
+		credential.Status.ExpiresAt = &metav1.Time{Time: now.Add(time.Hour)}
+		credential.Status.RequestCount = 3
+		return credential
+	}
+
+	t.Run("not reviewed", func(t *testing.T) {
+		h, m := newTestHandler(t, now)
+		m.credentials.EXPECT().UpdateStatus(gomock.Any()).DoAndReturn(func(credential *v3.BreakGlassCredential) (*v3.BreakGlassCredential, error) {
+			assert.True(t, meta.IsStatusConditionTrue(credential.Status.Conditions, v3.BreakGlassCredentialConditionUsed))
+			assert.True(t, meta.IsStatusConditionFalse(credential.Status.Conditions, v3.BreakGlassCredentialConditionUsageReviewed))
+			return credential, nil
+		})
+		m.credentials.EXPECT().EnqueueAfter("emergency", time.Hour)
+
+		_, err := h.onChange("", used())
+		require.NoError(t, err)
+	})
+
+	t.Run("acknowledged", func(t *testing.T) {
+		h, m := newTestHandler(t, now)
+		credential := used()
+		credential.Annotations = map[string]string{v3.BreakGlassUsageAcknowledgeAnnotation: "true"}
+		pending := newCredential()
+		pending.Name = "pending"
+
+		m.credentials.EXPECT().UpdateStatus(gomock.Any()).DoAndReturn(func(credential *v3.BreakGlassCredential) (*v3.BreakGlassCredential, error) {
+			assert.Equal(t, int64(3), credential.Status.AcknowledgedRequestCount)
+			assert.True(t, meta.IsStatusConditionTrue(credential.Status.Conditions, v3.BreakGlassCredentialConditionUsageReviewed))
+			return credential, nil
+		})
+		m.credentials.EXPECT().Update(gomock.Any()).DoAndReturn(func(credential *v3.BreakGlassCredential) (*v3.BreakGlassCredential, error) {
+			assert.NotContains(t, credential.Annotations, v3.BreakGlassUsageAcknowledgeAnnotation)
+			return credential, nil
+		})
+		m.credentialCache.EXPECT().List(labels.Everything()).Return([]*v3.BreakGlassCredential{credential, pending}, nil)
+		m.credentials.EXPECT().Enqueue("pending")
+		m.credentials.EXPECT().EnqueueAfter("emergency", time.Hour)
+
+		_, err := h.onChange("", credential)
+		require.NoError(t, err)
+	})
+}
+
+func TestPublicKeys(t *testing.T) {
+	secret := newSigningKeySecret(t)
+	small, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(&small.PublicKey)
+	require.NoError(t, err)
+	secret.Data["small.pub"] = pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+
+	keys, err := PublicKeys(secret)
+	require.NoError(t, err)
+	require.Len(t, keys.Keys, 1)
+	assert.Equal(t, "key2", keys.Keys[0].Kid)
+
+	secret.Data["invalid.pub"] = []byte("invalid")
+	_, err = PublicKeys(secret)
+	assert.ErrorContains(t, err, "failed to decode PEM block of invalid.pub")
+}
@@ -0,0 +1,114 @@
+package breakglass
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/rancher/rancher/pkg/controllers/managementuser/clusterauthtoken/common"
+	corecontrollers "github.com/rancher/wrangler/v3/pkg/generated/controllers/core/v1"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	keyBits = 3072
+
+	// SigningKeySecretNamespace is the namespace of the Secret holding the keys break-glass tokens are signed with.
+	SigningKeySecretNamespace = "cattle-system"
+	// SigningKeySecretName is the name of the Secret holding the keys break-glass tokens are signed with. As for the
+	// OIDC provider, the Secret holds a single private key in a <kid>.pem entry, which tokens are signed with, and
+	// the public keys published to downstream clusters in <kid>.pub entries. Keeping the public key of the previous
+	// private key rotates the signing key without invalidating the tokens already issued.
+	SigningKeySecretName = "break-glass-signing-key"
+)
+
+// ensureSigningKey returns the private key tokens are signed with and its key ID, creating a key if there is none.
+func ensureSigningKey(secretCache corecontrollers.SecretCache, secretClient corecontrollers.SecretClient) (*rsa.PrivateKey, string, error) {
+	secret, err := secretCache.Get(SigningKeySecretNamespace, SigningKeySecretName)
+	if apierrors.IsNotFound(err) {
+		secret, err = createSigningKey(secretClient)
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("getting signing key: %w", err)
+	}
+	for name, value := range secret.Data {
+		if !strings.HasSuffix(name, ".pem") {
+			continue
+		}
+		block, _ := pem.Decode(value)
+		if block == nil || block.Type != "RSA PRIVATE KEY" {
+			return nil, "", fmt.Errorf("failed to decode PEM block of %s", name)
+		}
+		key, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to parse RSA private key: %w", err)
+		}
+		return key, strings.TrimSuffix(name, ".pem"), nil
+	}
+	return nil, "", fmt.Errorf("signing key not found")
+}
+
+func createSigningKey(secretClient corecontrollers.SecretClient) (*corev1.Secret, error) {
+	logrus.Infof("[break-glass] creating a new signing key")
+	key, err := rsa.GenerateKey(rand.Reader, keyBits)
+	if err != nil {
+		return nil, err
+	}
+	publicKeyDER, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal public key: %w", err)
+	}
+
+	secret, err := secretClient.Create(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      SigningKeySecretName,
+			Namespace: SigningKeySecretNamespace,
+		},
+		Data: map[string][]byte{
+			"key.pem": pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}),
+			"key.pub": pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicKeyDER}),
+		},
+	})
+	if apierrors.IsAlreadyExists(err) {
+		return secretClient.Get(SigningKeySecretNamespace, SigningKeySecretName, metav1.GetOptions{})
+	}
+	return secret, err
+}
+
+// PublicKeys returns the JSON Web Key Set of the public keys of the signing key Secret. Keys of less than 2048 bits
+// are ignored.
+func PublicKeys(secret *corev1.Secret) (common.BreakGlassKeySet, error) {
+	keys := common.BreakGlassKeySet{Keys: []common.BreakGlassKey{}}
+	for name, value := range secret.Data {
+		if !strings.HasSuffix(name, ".pub") {
+			continue
+		}
+		block, _ := pem.Decode(value)
+		if block == nil || block.Type != "PUBLIC KEY" {
+			return keys, fmt.Errorf("failed to decode PEM block of %s", name)
+		}
+		publicKey, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return keys, fmt.Errorf("failed to parse public key %s: %w", name, err)
+		}
+		rsaKey, ok := publicKey.(*rsa.PublicKey)
+		if !ok {
+			return keys, fmt.Errorf("%s is not an RSA public key", name)
+		}
+		if rsaKey.N.BitLen() < 2048 {
+			logrus.Warnf("[break-glass] ignoring key %s because the size is less than 2048 bits", name)
+			continue
+		}
+		keys.Keys = append(keys.Keys, common.NewBreakGlassKey(strings.TrimSuffix(name, ".pub"), rsaKey))
+	}
+	// Secret data is a map, sort the keys so that the published key set doesn't change needlessly.
+	sort.Slice(keys.Keys, func(i, j int) bool { return keys.Keys[i].Kid < keys.Keys[j].Kid })
+	return keys, nil
+}
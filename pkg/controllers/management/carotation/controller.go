@@ -0,0 +1,547 @@
+package carotation
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/rancher/dynamiclistener/cert"
+	"github.com/rancher/dynamiclistener/factory"
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	provv1 "github.com/rancher/rancher/pkg/apis/provisioning.cattle.io/v1"
+	"github.com/rancher/rancher/pkg/capr"
+	"github.com/rancher/rancher/pkg/features"
+	mgmtcontrollers "github.com/rancher/rancher/pkg/generated/controllers/management.cattle.io/v3"
+	provcontrollers "github.com/rancher/rancher/pkg/generated/controllers/provisioning.cattle.io/v1"
+	"github.com/rancher/rancher/pkg/namespace"
+	"github.com/rancher/rancher/pkg/settings"
+	"github.com/rancher/rancher/pkg/systemtemplate"
+	"github.com/rancher/rancher/pkg/tls"
+	"github.com/rancher/rancher/pkg/wrangler"
+	appscontrollers "github.com/rancher/wrangler/v3/pkg/generated/controllers/apps/v1"
+	corecontrollers "github.com/rancher/wrangler/v3/pkg/generated/controllers/core/v1"
+	"github.com/sirupsen/logrus"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+const (
+	rancherDeploymentName = "rancher"
+	// caRotationAnnotation is set on the pod template of the Rancher deployment to roll it out once the generated
+	// certificate authority is switched, so that every replica regenerates its serving certificate.
+	caRotationAnnotation = "management.cattle.io/ca-rotation"
+	// certManagerIssuerAnnotation is set by cert-manager on the certificates it issues.
+	certManagerIssuerAnnotation = "cert-manager.io/issuer-name"
+	// rancherIssuerName is the name of the cert-manager issuer signing with the generated certificate authority.
+	rancherIssuerName = "rancher"
+	byClusterName     = "ca-rotation-by-cluster-name"
+
+	waitingForAgentsInterval = 30 * time.Second
+	switchingInterval        = 15 * time.Second
+)
+
+type handler struct {
+	rotations        mgmtcontrollers.CertificateAuthorityRotationController
+	rotationCache    mgmtcontrollers.CertificateAuthorityRotationCache
+	clusterCache     mgmtcontrollers.ClusterCache
+	provClusterCache provcontrollers.ClusterCache
+	secrets          corecontrollers.SecretClient
+	secretCache      corecontrollers.SecretCache
+	deployments      appscontrollers.DeploymentClient
+}
+
+func Register(ctx context.Context, wContext *wrangler.Context) {
+	h := &handler{
+		rotations:     wContext.Mgmt.CertificateAuthorityRotation(),
+		rotationCache: wContext.Mgmt.CertificateAuthorityRotation().Cache(),
+		clusterCache:  wContext.Mgmt.Cluster().Cache(),
+		secrets:       wContext.Core.Secret(),
+		secretCache:   wContext.Core.Secret().Cache(),
+		deployments:   wContext.Apps.Deployment(),
+	}
+	if features.ProvisioningV2.Enabled() {
+		h.provClusterCache = wContext.Provisioning.Cluster().Cache()
+		h.provClusterCache.AddIndexer(byClusterName, func(obj *provv1.Cluster) ([]string, error) {
+			if obj.Status.ClusterName == "" {
+				return nil, nil
+			}
+			return []string{obj.Status.ClusterName}, nil
+		})
+	}
+
+	wContext.Mgmt.CertificateAuthorityRotation().OnChange(ctx, "ca-rotation", h.onChange)
+	wContext.Mgmt.CertificateAuthorityRotation().OnRemove(ctx, "ca-rotation-remove", h.onRemove)
+}
+
+// onChange moves the rotation through its phases, requeueing it while it waits for the agents or for Rancher to serve
+// a certificate signed by the new certificate authority.
+func (h *handler) onChange(_ string, rotation *v3.CertificateAuthorityRotation) (*v3.CertificateAuthorityRotation, error) {
+	if rotation == nil || rotation.DeletionTimestamp != nil {
+		return rotation, nil
+	}
+
+	status := rotation.Status.DeepCopy()
+	var (
+		requeue time.Duration
+		err     error
+	)
+	switch status.Phase {
+	case "":
+		err = h.start(rotation, status)
+	case v3.CertificateAuthorityRotationPhasePublishing:
+		err = h.publish(status)
+	case v3.CertificateAuthorityRotationPhaseWaitingForAgents:
+		requeue, err = h.waitForAgents(rotation, status)
+	case v3.CertificateAuthorityRotationPhaseSwitching:
+		requeue, err = h.switchCA(status)
+	}
+	if err != nil {
+		return rotation, err
+	}
+
+	if !equality.Semantic.DeepEqual(rotation.Status, *status) {
+		rotation = rotation.DeepCopy()
+		rotation.Status = *status
+		if rotation, err = h.rotations.UpdateStatus(rotation); err != nil {
+			return rotation, err
+		}
+	}
+	if requeue > 0 {
+		h.rotations.EnqueueAfter(rotation.Name, requeue)
+	}
+	return rotation, nil
+}
+
+// onRemove cancels a rotation that has not switched the serving certificate yet by dropping the new certificate
+// authority from the cacerts setting.
+func (h *handler) onRemove(_ string, rotation *v3.CertificateAuthorityRotation) (*v3.CertificateAuthorityRotation, error) {
+	switch rotation.Status.Phase {
+	case v3.CertificateAuthorityRotationPhasePublishing, v3.CertificateAuthorityRotationPhaseWaitingForAgents:
+		if err := h.deleteSecret(tls.NextCASecretName); err != nil {
+			return rotation, err
+		}
+		if err := h.deleteSecret(tls.TrustedCAsSecretName); err != nil {
+			return rotation, err
+		}
+		current, err := h.currentCA()
+		if err != nil {
+			return rotation, err
+		}
+		if err := setCACerts(tls.CABundle(current)); err != nil {
+			return rotation, err
+		}
+		logrus.Infof("[ca-rotation] rotation %s cancelled", rotation.Name)
+	case v3.CertificateAuthorityRotationPhaseSwitching:
+		logrus.Warnf("[ca-rotation] rotation %s removed while switching the serving certificate, both certificate authorities remain trusted until a new rotation completes", rotation.Name)
+	}
+	return rotation, nil
+}
+
+// start checks that no other rotation is in progress and that the certificate authority generated by Rancher is the
+// one agents are given.
+func (h *handler) start(rotation *v3.CertificateAuthorityRotation, status *v3.CertificateAuthorityRotationStatus) error {
+	rotations, err := h.rotationCache.List(labels.Everything())
+	if err != nil {
+		return err
+	}
+	for _, other := range rotations {
+		if other.Name != rotation.Name && inProgressBefore(other, rotation) {
+			status.SetPhase(v3.CertificateAuthorityRotationPhaseFailed, fmt.Sprintf("rotation %s is already in progress", other.Name))
+			return nil
+		}
+	}
+
+	current, err := h.currentCA()
+	if apierrors.IsNotFound(err) {
+		status.SetPhase(v3.CertificateAuthorityRotationPhaseFailed, "Rancher does not use a generated certificate authority")
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if !trusts(settings.CACerts.Get(), current) {
+		status.SetPhase(v3.CertificateAuthorityRotationPhaseFailed, fmt.Sprintf("the %s setting does not hold the certificate authority generated by Rancher, only generated certificate authorities can be rotated", settings.CACerts.Name))
+		return nil
+	}
+
+	status.PreviousCAFingerprint = tls.Fingerprint(current)
+	status.SetPhase(v3.CertificateAuthorityRotationPhasePublishing, "Publishing the new certificate authority")
+	return nil
+}
+
+// publish generates the new certificate authority and publishes it in the cacerts setting alongside the current one.
+func (h *handler) publish(status *v3.CertificateAuthorityRotationStatus) error {
+	current, err := h.currentCA()
+	if err != nil {
+		return err
+	}
+	if tls.Fingerprint(current) != status.PreviousCAFingerprint {
+		status.SetPhase(v3.CertificateAuthorityRotationPhaseFailed, "the certificate authority generated by Rancher changed during the rotation")
+		return nil
+	}
+
+	next, err := h.nextCA()
+	if err != nil {
+		return err
+	}
+	bundle := tls.CABundle(current, next)
+	if err := h.applySecret(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      tls.TrustedCAsSecretName,
+			Namespace: namespace.System,
+		},
+		Data: map[string][]byte{
+			tls.TrustedCAsKey: []byte(bundle),
+		},
+	}); err != nil {
+		return err
+	}
+	if err := setCACerts(bundle); err != nil {
+		return err
+	}
+
+	status.NewCAFingerprint = tls.Fingerprint(next)
+	status.TrustBundleChecksum = systemtemplate.CACertsChecksum(bundle)
+	status.SetPhase(v3.CertificateAuthorityRotationPhaseWaitingForAgents, "Waiting for the agents to trust the new certificate authority")
+	return nil
+}
+
+// waitForAgents reports the progress of every cluster and moves on once the agents of every cluster trust both
+// certificate authorities.
+func (h *handler) waitForAgents(rotation *v3.CertificateAuthorityRotation, status *v3.CertificateAuthorityRotationStatus) (time.Duration, error) {
+	clusters, err := h.clusterCache.List(labels.Everything())
+	if err != nil {
+		return 0, err
+	}
+
+	var clusterStatuses []v3.CertificateAuthorityRotationClusterStatus
+	pending := 0
+	for _, cluster := range clusters {
+		if cluster.Spec.Internal || !v3.ClusterConditionAgentDeployed.IsTrue(cluster) {
+			continue
+		}
+		planSecrets, err := h.planSecrets(cluster.Name)
+		if err != nil {
+			return 0, err
+		}
+		clusterStatus := clusterProgress(cluster, planSecrets, status.TrustBundleChecksum, rotation.Spec.SkipUnavailableClusters)
+		if clusterStatus.State == v3.CertificateAuthorityRotationClusterPending {
+			pending++
+		}
+		clusterStatuses = append(clusterStatuses, clusterStatus)
+	}
+	sort.Slice(clusterStatuses, func(i, j int) bool {
+		return clusterStatuses[i].ClusterName < clusterStatuses[j].ClusterName
+	})
+	status.Clusters = clusterStatuses
+
+	if pending > 0 {
+		status.SetPhase(v3.CertificateAuthorityRotationPhaseWaitingForAgents,
+			fmt.Sprintf("Waiting for %d of %d clusters to trust the new certificate authority", pending, len(clusterStatuses)))
+		return waitingForAgentsInterval, nil
+	}
+	status.SetPhase(v3.CertificateAuthorityRotationPhaseSwitching, "Switching the Rancher serving certificate to the new certificate authority")
+	return 0, nil
+}
+
+// switchCA replaces the generated certificate authority with the new one, rolls out Rancher so that it regenerates
+// its serving certificate and drops the previous certificate authority once every certificate is re-issued.
+func (h *handler) switchCA(status *v3.CertificateAuthorityRotationStatus) (time.Duration, error) {
+	caSecret, err := h.secrets.Get(namespace.System, tls.CASecretName, metav1.GetOptions{})
+	if err != nil {
+		return 0, err
+	}
+	newCA, err := parseCA(caSecret)
+	if err != nil {
+		return 0, err
+	}
+	if tls.Fingerprint(newCA) != status.NewCAFingerprint {
+		nextSecret, err := h.secrets.Get(namespace.System, tls.NextCASecretName, metav1.GetOptions{})
+		if err != nil {
+			return 0, err
+		}
+		if newCA, err = parseCA(nextSecret); err != nil {
+			return 0, err
+		}
+		caSecret = caSecret.DeepCopy()
+		caSecret.Data = map[string][]byte{
+			corev1.TLSCertKey:       nextSecret.Data[corev1.TLSCertKey],
+			corev1.TLSPrivateKeyKey: nextSecret.Data[corev1.TLSPrivateKeyKey],
+		}
+		if _, err := h.secrets.Update(caSecret); err != nil {
+			return 0, err
+		}
+		logrus.Infof("[ca-rotation] switched the generated certificate authority to %s", status.NewCAFingerprint)
+	}
+
+	ingressReissued, err := h.reissueIngressCert(newCA)
+	if err != nil {
+		return 0, err
+	}
+	rolledOut, err := h.rolloutRancher(status.NewCAFingerprint)
+	if err != nil {
+		return 0, err
+	}
+	servingCert, err := h.secrets.Get(namespace.System, tls.ServingCertSecretName, metav1.GetOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return 0, err
+	}
+	servingCertReissued := servingCert == nil || apierrors.IsNotFound(err) || tls.SignedBy(servingCert.Data[corev1.TLSCertKey], newCA)
+
+	switch {
+	case !rolledOut:
+		status.SetPhase(v3.CertificateAuthorityRotationPhaseSwitching, "Waiting for the Rancher deployment to roll out")
+		return switchingInterval, nil
+	case !servingCertReissued:
+		status.SetPhase(v3.CertificateAuthorityRotationPhaseSwitching, "Waiting for the Rancher serving certificate to be signed by the new certificate authority, restart Rancher if it does not run as a deployment")
+		return switchingInterval, nil
+	case !ingressReissued:
+		status.SetPhase(v3.CertificateAuthorityRotationPhaseSwitching, "Waiting for cert-manager to re-issue the Rancher ingress certificate")
+		return switchingInterval, nil
+	}
+
+	if err := h.deleteSecret(tls.TrustedCAsSecretName); err != nil {
+		return 0, err
+	}
+	if err := h.deleteSecret(tls.NextCASecretName); err != nil {
+		return 0, err
+	}
+	if err := setCACerts(tls.CABundle(newCA)); err != nil {
+		return 0, err
+	}
+	status.SetPhase(v3.CertificateAuthorityRotationPhaseCompleted, "")
+	return 0, nil
+}
+
+// reissueIngressCert deletes the Rancher ingress certificate issued by cert-manager from the previous certificate
+// authority so that cert-manager re-issues it, and returns whether it is signed by the new certificate authority.
+func (h *handler) reissueIngressCert(newCA *x509.Certificate) (bool, error) {
+	secret, err := h.secrets.Get(namespace.System, tls.IngressCertSecretName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return true, nil
+	} else if err != nil {
+		return false, err
+	}
+	if tls.SignedBy(secret.Data[corev1.TLSCertKey], newCA) {
+		return true, nil
+	}
+	if secret.Annotations[certManagerIssuerAnnotation] != rancherIssuerName {
+		// The ingress certificate is not issued from the generated certificate authority.
+		return true, nil
+	}
+	return false, h.deleteSecret(tls.IngressCertSecretName)
+}
+
+// rolloutRancher rolls out the Rancher deployment and returns whether every replica runs with the new certificate
+// authority. It returns true when Rancher does not run as a deployment.
+func (h *handler) rolloutRancher(fingerprint string) (bool, error) {
+	deployment, err := h.deployments.Get(namespace.System, rancherDeploymentName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return true, nil
+	} else if err != nil {
+		return false, err
+	}
+	if deployment.Spec.Template.Annotations[caRotationAnnotation] != fingerprint {
+		deployment = deployment.DeepCopy()
+		if deployment.Spec.Template.Annotations == nil {
+			deployment.Spec.Template.Annotations = map[string]string{}
+		}
+		deployment.Spec.Template.Annotations[caRotationAnnotation] = fingerprint
+		_, err := h.deployments.Update(deployment)
+		return false, err
+	}
+	return rolledOut(deployment), nil
+}
+
+// currentCA returns the certificate authority generated by Rancher.
+func (h *handler) currentCA() (*x509.Certificate, error) {
+	secret, err := h.secrets.Get(namespace.System, tls.CASecretName, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return parseCA(secret)
+}
+
+// nextCA returns the certificate authority the rotation switches to, generating it on first use.
+func (h *handler) nextCA() (*x509.Certificate, error) {
+	secret, err := h.secrets.Get(namespace.System, tls.NextCASecretName, metav1.GetOptions{})
+	if err == nil {
+		return parseCA(secret)
+	} else if !apierrors.IsNotFound(err) {
+		return nil, err
+	}
+
+	caCert, caKey, err := factory.GenCA()
+	if err != nil {
+		return nil, err
+	}
+	certPEM, keyPEM, err := factory.Marshal(caCert, caKey)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := h.secrets.Create(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      tls.NextCASecretName,
+			Namespace: namespace.System,
+		},
+		Data: map[string][]byte{
+			corev1.TLSCertKey:       certPEM,
+			corev1.TLSPrivateKeyKey: keyPEM,
+		},
+		Type: corev1.SecretTypeTLS,
+	}); err != nil {
+		return nil, err
+	}
+	return caCert, nil
+}
+
+// planSecrets returns the machine plan secrets of the cluster, which is empty when the cluster is not an RKE2/K3s
+// cluster provisioned by Rancher.
+func (h *handler) planSecrets(clusterName string) ([]*corev1.Secret, error) {
+	if h.provClusterCache == nil {
+		return nil, nil
+	}
+	provClusters, err := h.provClusterCache.GetByIndex(byClusterName, clusterName)
+	if err != nil {
+		return nil, err
+	}
+	var planSecrets []*corev1.Secret
+	for _, provCluster := range provClusters {
+		if provCluster.Spec.RKEConfig == nil {
+			continue
+		}
+		secrets, err := h.secretCache.List(provCluster.Namespace, labels.SelectorFromSet(map[string]string{
+			capr.ClusterNameLabel: provCluster.Name,
+		}))
+		if err != nil {
+			return nil, err
+		}
+		for _, secret := range secrets {
+			if secret.Type == capr.SecretTypeMachinePlan && secret.Labels[capr.MachineNameLabel] != "" {
+				planSecrets = append(planSecrets, secret)
+			}
+		}
+	}
+	return planSecrets, nil
+}
+
+func (h *handler) applySecret(secret *corev1.Secret) error {
+	existing, err := h.secrets.Get(secret.Namespace, secret.Name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		_, err = h.secrets.Create(secret)
+		return err
+	} else if err != nil {
+		return err
+	}
+	if equality.Semantic.DeepEqual(existing.Data, secret.Data) {
+		return nil
+	}
+	existing = existing.DeepCopy()
+	existing.Data = secret.Data
+	_, err = h.secrets.Update(existing)
+	return err
+}
+
+func (h *handler) deleteSecret(name string) error {
+	err := h.secrets.Delete(namespace.System, name, &metav1.DeleteOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+// clusterProgress returns whether the cluster agent and the system agents of the cluster trust the certificate
+// authorities bundle with the given checksum.
+func clusterProgress(cluster *v3.Cluster, planSecrets []*corev1.Secret, checksum string, skipUnavailable bool) v3.CertificateAuthorityRotationClusterStatus {
+	status := v3.CertificateAuthorityRotationClusterStatus{
+		ClusterName:         cluster.Name,
+		ClusterAgentTrusted: cluster.Status.AgentCACertsChecksum == checksum,
+		SystemAgents:        len(planSecrets),
+	}
+	for _, secret := range planSecrets {
+		if secret.Annotations[capr.AgentCACertsChecksumAnnotation] == checksum {
+			status.SystemAgentsTrusted++
+		}
+	}
+
+	switch {
+	case status.ClusterAgentTrusted && status.SystemAgentsTrusted == status.SystemAgents:
+		status.State = v3.CertificateAuthorityRotationClusterTrusted
+	case skipUnavailable && !v3.ClusterConditionReady.IsTrue(cluster):
+		status.State = v3.CertificateAuthorityRotationClusterSkipped
+		status.Message = "cluster is not ready"
+	case !status.ClusterAgentTrusted:
+		status.State = v3.CertificateAuthorityRotationClusterPending
+		status.Message = "waiting for the cluster agent"
+	default:
+		status.State = v3.CertificateAuthorityRotationClusterPending
+		status.Message = fmt.Sprintf("waiting for %d of %d system agents", status.SystemAgents-status.SystemAgentsTrusted, status.SystemAgents)
+	}
+	return status
+}
+
+// inProgressBefore returns whether the other rotation is in progress, or was created before the rotation and has not
+// started yet.
+func inProgressBefore(other, rotation *v3.CertificateAuthorityRotation) bool {
+	switch other.Status.Phase {
+	case v3.CertificateAuthorityRotationPhasePublishing,
+		v3.CertificateAuthorityRotationPhaseWaitingForAgents,
+		v3.CertificateAuthorityRotationPhaseSwitching:
+		return true
+	case "":
+		if other.DeletionTimestamp != nil {
+			return false
+		}
+		if other.CreationTimestamp.Equal(&rotation.CreationTimestamp) {
+			return other.Name < rotation.Name
+		}
+		return other.CreationTimestamp.Before(&rotation.CreationTimestamp)
+	}
+	return false
+}
+
+// trusts returns whether the PEM bundle holds the certificate authority.
+func trusts(bundle string, ca *x509.Certificate) bool {
+	certs, err := cert.ParseCertsPEM([]byte(bundle))
+	if err != nil {
+		return false
+	}
+	fingerprint := tls.Fingerprint(ca)
+	for _, c := range certs {
+		if tls.Fingerprint(c) == fingerprint {
+			return true
+		}
+	}
+	return false
+}
+
+func rolledOut(deployment *appsv1.Deployment) bool {
+	replicas := int32(1)
+	if deployment.Spec.Replicas != nil {
+		replicas = *deployment.Spec.Replicas
+	}
+	return deployment.Status.ObservedGeneration >= deployment.Generation &&
+		deployment.Status.UpdatedReplicas == replicas &&
+		deployment.Status.Replicas == replicas &&
+		deployment.Status.AvailableReplicas == replicas
+}
+
+func parseCA(secret *corev1.Secret) (*x509.Certificate, error) {
+	certs, err := cert.ParseCertsPEM(secret.Data[corev1.TLSCertKey])
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse certificate authority %s/%s: %w", secret.Namespace, secret.Name, err)
+	}
+	return certs[0], nil
+}
+
+func setCACerts(bundle string) error {
+	if settings.CACerts.Get() == bundle {
+		return nil
+	}
+	return settings.CACerts.Set(bundle)
+}
@@ -0,0 +1,232 @@
+package carotation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rancher/dynamiclistener/cert"
+	"github.com/rancher/dynamiclistener/factory"
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/capr"
+	"github.com/rancher/rancher/pkg/namespace"
+	"github.com/rancher/rancher/pkg/settings"
+	"github.com/rancher/rancher/pkg/tls"
+	"github.com/rancher/wrangler/v3/pkg/generic/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/utils/ptr"
+)
+
+func TestClusterProgress(t *testing.T) {
+	const checksum = "new"
+	planSecret := func(checksum string) *corev1.Secret {
+		return &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{capr.AgentCACertsChecksumAnnotation: checksum}}}
+	}
+	cluster := func(agentChecksum string, ready bool) *v3.Cluster {
+		cluster := &v3.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "c-abc"}}
+		cluster.Status.AgentCACertsChecksum = agentChecksum
+		if ready {
+			v3.ClusterConditionReady.True(cluster)
+		} else {
+			v3.ClusterConditionReady.False(cluster)
+		}
+		return cluster
+	}
+
+	tests := []struct {
+		name            string
+		cluster         *v3.Cluster
+		planSecrets     []*corev1.Secret
+		skipUnavailable bool
+		expected        v3.CertificateAuthorityRotationClusterStatus
+	}{
+		{
+			name:    "imported cluster trusted",
+			cluster: cluster(checksum, true),
+			expected: v3.CertificateAuthorityRotationClusterStatus{
+				ClusterName:         "c-abc",
+				State:               v3.CertificateAuthorityRotationClusterTrusted,
+				ClusterAgentTrusted: true,
+			},
+		},
+		{
+			name:        "all agents trusted",
+			cluster:     cluster(checksum, true),
+			planSecrets: []*corev1.Secret{planSecret(checksum), planSecret(checksum)},
+			expected: v3.CertificateAuthorityRotationClusterStatus{
+				ClusterName:         "c-abc",
+				State:               v3.CertificateAuthorityRotationClusterTrusted,
+				ClusterAgentTrusted: true,
+				SystemAgents:        2,
+				SystemAgentsTrusted: 2,
+			},
+		},
+		{
+			name:        "cluster agent not trusted",
+			cluster:     cluster("old", true),
+			planSecrets: []*corev1.Secret{planSecret(checksum)},
+			expected: v3.CertificateAuthorityRotationClusterStatus{
+				ClusterName:         "c-abc",
+				State:               v3.CertificateAuthorityRotationClusterPending,
+				SystemAgents:        1,
+				SystemAgentsTrusted: 1,
+				Message:             "waiting for the cluster agent",
+			},
+		},
+		{
+			name:        "system agents not trusted",
+			cluster:     cluster(checksum, true),
+			planSecrets: []*corev1.Secret{planSecret(checksum), planSecret("old"), {}},
+			expected: v3.CertificateAuthorityRotationClusterStatus{
+				ClusterName:         "c-abc",
+				State:               v3.CertificateAuthorityRotationClusterPending,
+				ClusterAgentTrusted: true,
+				SystemAgents:        3,
+				SystemAgentsTrusted: 1,
+				Message:             "waiting for 2 of 3 system agents",
+			},
+		},
+		{
+			name:    "unavailable cluster not skipped",
+			cluster: cluster("old", false),
+			expected: v3.CertificateAuthorityRotationClusterStatus{
+				ClusterName: "c-abc",
+				State:       v3.CertificateAuthorityRotationClusterPending,
+				Message:     "waiting for the cluster agent",
+			},
+		},
+		{
+			name:            "unavailable cluster skipped",
+			cluster:         cluster("old", false),
+			skipUnavailable: true,
+			expected: v3.CertificateAuthorityRotationClusterStatus{
+				ClusterName: "c-abc",
+				State:       v3.CertificateAuthorityRotationClusterSkipped,
+				Message:     "cluster is not ready",
+			},
+		},
+		{
+			name:            "ready cluster not skipped",
+			cluster:         cluster("old", true),
+			skipUnavailable: true,
+			expected: v3.CertificateAuthorityRotationClusterStatus{
+				ClusterName: "c-abc",
+				State:       v3.CertificateAuthorityRotationClusterPending,
+				Message:     "waiting for the cluster agent",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, clusterProgress(tt.cluster, tt.planSecrets, checksum, tt.skipUnavailable))
+		})
+	}
+}
+
+func TestInProgressBefore(t *testing.T) {
+	created := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	rotation := func(name string, phase v3.CertificateAuthorityRotationPhase, createdAt time.Time) *v3.CertificateAuthorityRotation {
+		return &v3.CertificateAuthorityRotation{
+			ObjectMeta: metav1.ObjectMeta{Name: name, CreationTimestamp: metav1.NewTime(createdAt)},
+			Status:     v3.CertificateAuthorityRotationStatus{Phase: phase},
+		}
+	}
+	current := rotation("b", "", created)
+
+	assert.True(t, inProgressBefore(rotation("a", v3.CertificateAuthorityRotationPhaseWaitingForAgents, created.Add(time.Hour)), current))
+	assert.True(t, inProgressBefore(rotation("c", "", created.Add(-time.Hour)), current))
+	assert.True(t, inProgressBefore(rotation("a", "", created), current))
+	assert.False(t, inProgressBefore(rotation("c", "", created), current))
+	assert.False(t, inProgressBefore(rotation("a", "", created.Add(time.Hour)), current))
+	assert.False(t, inProgressBefore(rotation("a", v3.CertificateAuthorityRotationPhaseCompleted, created), current))
+	assert.False(t, inProgressBefore(rotation("a", v3.CertificateAuthorityRotationPhaseFailed, created), current))
+}
+
+func TestRolledOut(t *testing.T) {
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Generation: 2},
+		Spec:       appsv1.DeploymentSpec{Replicas: ptr.To[int32](3)},
+		Status: appsv1.DeploymentStatus{
+			ObservedGeneration: 2,
+			Replicas:           3,
+			UpdatedReplicas:    3,
+			AvailableReplicas:  3,
+		},
+	}
+	assert.True(t, rolledOut(deployment))
+
+	rollingOut := deployment.DeepCopy()
+	rollingOut.Status.Replicas = 4
+	assert.False(t, rolledOut(rollingOut))
+
+	notObserved := deployment.DeepCopy()
+	notObserved.Generation = 3
+	assert.False(t, rolledOut(notObserved))
+}
+
+func TestStart(t *testing.T) {
+	caCert, caKey, err := factory.GenCA()
+	require.NoError(t, err)
+	certPEM, keyPEM, err := factory.Marshal(caCert, caKey)
+	require.NoError(t, err)
+	caSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: tls.CASecretName, Namespace: namespace.System},
+		Data:       map[string][]byte{corev1.TLSCertKey: certPEM, corev1.TLSPrivateKeyKey: keyPEM},
+	}
+	otherCA, _, err := factory.GenCA()
+	require.NoError(t, err)
+
+	tests := []struct {
+		name          string
+		caCerts       string
+		others        []*v3.CertificateAuthorityRotation
+		expectedPhase v3.CertificateAuthorityRotationPhase
+	}{
+		{
+			name:          "generated certificate authority",
+			caCerts:       tls.CABundle(caCert),
+			expectedPhase: v3.CertificateAuthorityRotationPhasePublishing,
+		},
+		{
+			name:          "user provided certificate authority",
+			caCerts:       string(cert.EncodeCertPEM(otherCA)),
+			expectedPhase: v3.CertificateAuthorityRotationPhaseFailed,
+		},
+		{
+			name:    "rotation in progress",
+			caCerts: tls.CABundle(caCert),
+			others: []*v3.CertificateAuthorityRotation{{
+				ObjectMeta: metav1.ObjectMeta{Name: "other"},
+				Status:     v3.CertificateAuthorityRotationStatus{Phase: v3.CertificateAuthorityRotationPhaseSwitching},
+			}},
+			expectedPhase: v3.CertificateAuthorityRotationPhaseFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			rotationCache := fake.NewMockNonNamespacedCacheInterface[*v3.CertificateAuthorityRotation](ctrl)
+			secrets := fake.NewMockClientInterface[*corev1.Secret, *corev1.SecretList](ctrl)
+			rotation := &v3.CertificateAuthorityRotation{ObjectMeta: metav1.ObjectMeta{Name: "rotation"}}
+			rotationCache.EXPECT().List(labels.Everything()).Return(append(tt.others, rotation), nil)
+			secrets.EXPECT().Get(namespace.System, tls.CASecretName, gomock.Any()).Return(caSecret, nil).AnyTimes()
+			require.NoError(t, settings.CACerts.Set(tt.caCerts))
+			t.Cleanup(func() { _ = settings.CACerts.Set("") })
+
+			h := &handler{rotationCache: rotationCache, secrets: secrets}
+			status := rotation.Status.DeepCopy()
+			require.NoError(t, h.start(rotation, status))
+			assert.Equal(t, tt.expectedPhase, status.Phase)
+			if tt.expectedPhase == v3.CertificateAuthorityRotationPhasePublishing {
+				assert.Equal(t, tls.Fingerprint(caCert), status.PreviousCAFingerprint)
+			}
+		})
+	}
+}
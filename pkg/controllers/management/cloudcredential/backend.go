@@ -0,0 +1,120 @@
+package cloudcredential
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	apimgmtv3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/controllers/management/cluster"
+	"github.com/rancher/rancher/pkg/namespace"
+	"github.com/rancher/rancher/pkg/secretbackend"
+	"github.com/rancher/wrangler/v3/pkg/relatedresource"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const harvesterKubeconfigKey = "harvestercredentialConfig-kubeconfigContent"
+
+// IsCloudCredential returns whether the data of the secret can be stored in the secret backend as a cloud credential.
+// Harvester cloud credentials are kept in Kubernetes, as the tokens of their kubeconfig are managed by Rancher.
+func IsCloudCredential(secret *v1.Secret) bool {
+	if secret.Namespace != namespace.GlobalNamespace || !configExists(secret.Data) {
+		return false
+	}
+	_, harvester := secret.Data[harvesterKubeconfigKey]
+	return !harvester
+}
+
+// hostedClusterCredentials enqueues the cloud credential of a hosted cluster, so that it is restored from the secret
+// backend.
+func hostedClusterCredentials(_, _ string, obj runtime.Object) ([]relatedresource.Key, error) {
+	c, ok := obj.(*apimgmtv3.Cluster)
+	if !ok {
+		return nil, nil
+	}
+	credentials, err := cluster.ByCloudCredentialIndexer(c)
+	if err != nil {
+		return nil, err
+	}
+	var keys []relatedresource.Key
+	for _, credential := range credentials {
+		ns, name, ok := strings.Cut(credential, ":")
+		if !ok {
+			ns, name = namespace.GlobalNamespace, credential
+		}
+		keys = append(keys, relatedresource.Key{Namespace: ns, Name: name})
+	}
+	return keys, nil
+}
+
+// syncSecretBackend stores the data of cloud credentials in the secret backend when one is configured, and deletes the
+// data of secrets stored in the backend when they are deleted.
+//
+// The operators of hosted clusters read their cloud credential from Kubernetes, so credentials used by hosted clusters
+// are restored from the backend instead.
+func (c *Controller) syncSecretBackend(key string, secret *v1.Secret) (*v1.Secret, error) {
+	if secret == nil {
+		return nil, nil
+	}
+
+	if secret.DeletionTimestamp != nil {
+		if !slices.Contains(secret.Finalizers, secretbackend.CleanupFinalizer) {
+			return secret, nil
+		}
+		if err := secretbackend.Delete(context.TODO(), secret); err != nil {
+			return nil, err
+		}
+		secret = secret.DeepCopy()
+		secret.Finalizers = slices.DeleteFunc(secret.Finalizers, func(s string) bool {
+			return s == secretbackend.CleanupFinalizer
+		})
+		return c.secretClient.Update(secret)
+	}
+
+	if !IsCloudCredential(secret) {
+		return secret, nil
+	}
+
+	hosted, err := c.usedByHostedCluster(secret)
+	if err != nil {
+		return nil, err
+	}
+
+	if secretbackend.IsReference(secret) {
+		if !hosted {
+			return secret, nil
+		}
+		restored, err := secretbackend.Restore(context.TODO(), secret)
+		if err != nil {
+			return nil, err
+		}
+		updated, err := c.secretClient.Update(restored)
+		if err != nil {
+			return nil, fmt.Errorf("failed to restore cloud credential %s used by a hosted cluster from the secret backend: %w", key, err)
+		}
+		return updated, secretbackend.Delete(context.TODO(), secret)
+	}
+
+	if hosted {
+		return secret, nil
+	}
+	backend, err := secretbackend.Configured()
+	if err != nil || backend == nil {
+		return secret, err
+	}
+	stored, err := secretbackend.Store(context.TODO(), backend, secret)
+	if err != nil {
+		return nil, err
+	}
+	return c.secretClient.Update(stored)
+}
+
+func (c *Controller) usedByHostedCluster(secret *v1.Secret) (bool, error) {
+	clusters, err := c.clusterCache.GetByIndex(cluster.ByCloudCredential, secret.Namespace+":"+secret.Name)
+	if err != nil {
+		return false, err
+	}
+	return len(clusters) > 0, nil
+}
@@ -0,0 +1,102 @@
+package cloudcredential
+
+import (
+	"testing"
+	"time"
+
+	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/controllers/management/cluster"
+	"github.com/rancher/rancher/pkg/secretbackend"
+	"github.com/rancher/wrangler/v3/pkg/generic/fake"
+	"github.com/rancher/wrangler/v3/pkg/relatedresource"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+func TestIsCloudCredential(t *testing.T) {
+	secret := func(ns string, data map[string][]byte) *corev1.Secret {
+		return &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: "cc-abc"}, Data: data}
+	}
+
+	assert.True(t, IsCloudCredential(secret("cattle-global-data", map[string][]byte{"amazonec2credentialConfig-secretKey": {}})))
+	assert.False(t, IsCloudCredential(secret("default", map[string][]byte{"amazonec2credentialConfig-secretKey": {}})))
+	assert.False(t, IsCloudCredential(secret("cattle-global-data", map[string][]byte{"token": {}})))
+	assert.False(t, IsCloudCredential(secret("cattle-global-data", map[string][]byte{harvesterKubeconfigKey: {}})))
+}
+
+func TestHostedClusterCredentials(t *testing.T) {
+	keys, err := hostedClusterCredentials("", "c-abc", &v3.Cluster{})
+	require.NoError(t, err)
+	assert.Empty(t, keys)
+
+	c := &v3.Cluster{}
+	c.Spec.EKSConfig = &eksv1.EKSClusterConfigSpec{AmazonCredentialSecret: "cattle-global-data:cc-abc"}
+	keys, err = hostedClusterCredentials("", "c-abc", c)
+	require.NoError(t, err)
+	assert.Equal(t, []relatedresource.Key{{Namespace: "cattle-global-data", Name: "cc-abc"}}, keys)
+}
+
+func TestSyncSecretBackend(t *testing.T) {
+	credential := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "cattle-global-data", Name: "cc-abc"},
+		Data:       map[string][]byte{"amazonec2credentialConfig-secretKey": []byte("secret")},
+	}
+	reference := credential.DeepCopy()
+	reference.Annotations = map[string]string{
+		secretbackend.BackendAnnotation: secretbackend.Vault,
+		secretbackend.PathAnnotation:    "cattle-global-data/cc-abc",
+	}
+	reference.Finalizers = []string{secretbackend.CleanupFinalizer}
+
+	t.Run("not a cloud credential", func(t *testing.T) {
+		h := &Controller{}
+		secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "cattle-global-data", Name: "other"}}
+		got, err := h.syncSecretBackend("cattle-global-data/other", secret)
+		require.NoError(t, err)
+		assert.Same(t, secret, got)
+	})
+
+	t.Run("no backend configured", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		clusterCache := fake.NewMockNonNamespacedCacheInterface[*v3.Cluster](ctrl)
+		clusterCache.EXPECT().GetByIndex(cluster.ByCloudCredential, "cattle-global-data:cc-abc").Return(nil, nil)
+
+		h := &Controller{clusterCache: clusterCache}
+		got, err := h.syncSecretBackend("cattle-global-data/cc-abc", credential)
+		require.NoError(t, err)
+		assert.Same(t, credential, got)
+	})
+
+	t.Run("reference not used by a hosted cluster", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		clusterCache := fake.NewMockNonNamespacedCacheInterface[*v3.Cluster](ctrl)
+		clusterCache.EXPECT().GetByIndex(cluster.ByCloudCredential, "cattle-global-data:cc-abc").Return(nil, nil)
+
+		h := &Controller{clusterCache: clusterCache}
+		got, err := h.syncSecretBackend("cattle-global-data/cc-abc", reference)
+		require.NoError(t, err)
+		assert.Same(t, reference, got)
+	})
+
+	t.Run("deleted without finalizer", func(t *testing.T) {
+		h := &Controller{}
+		deleted := credential.DeepCopy()
+		deleted.DeletionTimestamp = ptr.To(metav1.NewTime(time.Now()))
+		got, err := h.syncSecretBackend("cattle-global-data/cc-abc", deleted)
+		require.NoError(t, err)
+		assert.Same(t, deleted, got)
+	})
+
+	t.Run("deleted with backend not configured", func(t *testing.T) {
+		h := &Controller{}
+		deleted := reference.DeepCopy()
+		deleted.DeletionTimestamp = ptr.To(metav1.NewTime(time.Now()))
+		_, err := h.syncSecretBackend("cattle-global-data/cc-abc", deleted)
+		assert.Error(t, err, "the finalizer should not be removed while the data cannot be deleted from the backend")
+	})
+}
@@ -19,6 +19,7 @@
 	"github.com/rancher/rancher/pkg/wrangler"
 	"github.com/rancher/wrangler/v3/pkg/apply"
 	corecontrollers "github.com/rancher/wrangler/v3/pkg/generated/controllers/core/v1"
+	"github.com/rancher/wrangler/v3/pkg/relatedresource"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -35,6 +36,7 @@ type Controller struct {
 	managementContext *config.ManagementContext
 	secretClient      corecontrollers.SecretClient
 	tokenClient       mgmtcontrollers.TokenClient
+	clusterCache      mgmtcontrollers.ClusterCache
 	apply             apply.Apply
 }
 
@@ -43,6 +45,7 @@ func Register(ctx context.Context, management *config.ManagementContext, clients
 		managementContext: management,
 		secretClient:      clients.Core.Secret(),
 		tokenClient:       clients.Mgmt.Token(),
+		clusterCache:      clients.Mgmt.Cluster().Cache(),
 		apply: clients.Apply.WithCacheTypes(
 			clients.Core.Secret(),
 			clients.Mgmt.Token(),
@@ -52,6 +55,8 @@ func Register(ctx context.Context, management *config.ManagementContext, clients
 	if features.Harvester.Enabled() {
 		clients.Core.Secret().OnChange(ctx, "harvester-cloud-credential-token", m.syncHarvesterToken)
 	}
+	clients.Core.Secret().OnChange(ctx, "cloud-credential-secret-backend", m.syncSecretBackend)
+	relatedresource.Watch(ctx, "cloud-credential-secret-backend", hostedClusterCredentials, clients.Core.Secret(), clients.Mgmt.Cluster())
 }
 
 // syncHarvesterToken will extend the ttl of a token owned by a harvester cloud credential to infinite, as well as
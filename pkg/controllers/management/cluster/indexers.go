@@ -8,10 +8,11 @@
 const ByCloudCredential = "byCloudCredential"
 
 func RegisterIndexers(context *config.ScaledContext) {
-	context.Wrangler.Mgmt.Cluster().Cache().AddIndexer(ByCloudCredential, byCloudCredentialIndexer)
+	context.Wrangler.Mgmt.Cluster().Cache().AddIndexer(ByCloudCredential, ByCloudCredentialIndexer)
 }
 
-func byCloudCredentialIndexer(obj *v3.Cluster) ([]string, error) {
+// ByCloudCredentialIndexer returns the cloud credential of a hosted cluster, as namespace:name.
+func ByCloudCredentialIndexer(obj *v3.Cluster) ([]string, error) {
 	switch {
 	case obj.Spec.EKSConfig != nil:
 		if obj.Spec.EKSConfig.AmazonCredentialSecret != "" {
@@ -45,6 +45,7 @@
 const (
 	AgentForceDeployAnn        = "io.cattle.agent.force.deploy"
 	AgentRegistrationTokenHash = "io.cattle.agent.registration-token-hash"
+	AgentCAChecksum            = "io.cattle.agent.ca-checksum"
 	clusterImage               = "clusterImage"
 )
 
@@ -545,8 +546,14 @@ func (cd *clusterDeploy) deployAgent(cluster *apimgmtv3.Cluster) error {
 		return err
 	}
 
+	caChanged := caChecksumChanged(cluster)
+	if _, ok := cluster.Annotations[AgentCAChecksum]; !ok {
+		// record the checksum of agents deployed before it was tracked, without redeploying them
+		updateCAChecksum(cluster)
+	}
+
 	shouldRedeployAgent := redeployAgent(cluster, desiredAgent, desiredAuth, desiredCharts, desiredFeatures, desiredTaints)
-	agentManifestChanged := shouldRedeployAgent || pcDeleted || pcCreated || hashChanged || tokenHashChanged
+	agentManifestChanged := shouldRedeployAgent || pcDeleted || pcCreated || hashChanged || tokenHashChanged || caChanged
 
 	if !agentManifestChanged && !pcChanged {
 		return nil
@@ -656,6 +663,7 @@ func (cd *clusterDeploy) deployAgent(cluster *apimgmtv3.Cluster) error {
 	if tokenHashChanged {
 		cd.updateRegistrationTokenHash(cluster)
 	}
+	updateCAChecksum(cluster)
 
 	cluster.Status.AppliedAgentEnvVars = append(settings.DefaultAgentSettingsAsEnvVars(), cluster.Spec.AgentEnvVars...)
 	cluster.Status.AppliedClusterAgentImagePullSecretsHash = pullSecretHash
@@ -906,3 +914,27 @@ func (cd *clusterDeploy) updateRegistrationTokenHash(cluster *apimgmtv3.Cluster)
 	}
 	cluster.Annotations[AgentRegistrationTokenHash] = hex.EncodeToString(hash[:])[:10]
 }
+
+// caChecksumChanged returns whether the CA certificates published in the cacerts setting changed since the agent was
+// last deployed, for instance during a certificate authority rotation. The agent pins them through CATTLE_CA_CHECKSUM
+// and must be redeployed to trust the new ones.
+func caChecksumChanged(cluster *apimgmtv3.Cluster) bool {
+	current, ok := cluster.Annotations[AgentCAChecksum]
+	if !ok {
+		return false
+	}
+	desired := systemtemplate.CAChecksum()
+	if current == desired {
+		return false
+	}
+
+	logrus.Infof("clusterDeploy: CA checksum changed for cluster [%s]: was [%s], now [%s]", cluster.Name, current, desired)
+	return true
+}
+
+func updateCAChecksum(cluster *apimgmtv3.Cluster) {
+	if cluster.Annotations == nil {
+		cluster.Annotations = map[string]string{}
+	}
+	cluster.Annotations[AgentCAChecksum] = systemtemplate.CAChecksum()
+}
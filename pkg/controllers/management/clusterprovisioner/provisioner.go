@@ -11,6 +11,7 @@
 	"github.com/rancher/norman/types/convert"
 	"github.com/rancher/norman/types/values"
 	apimgmtv3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/clusterprovisioninglogger"
 	"github.com/rancher/rancher/pkg/controllers/management/imported"
 	"github.com/rancher/rancher/pkg/controllers/management/secretmigrator"
 	v1 "github.com/rancher/rancher/pkg/generated/norman/apps/v1"
@@ -36,6 +37,7 @@ type Provisioner struct {
 	ClusterController     v3.ClusterController
 	Clusters              v3.ClusterInterface
 	ConfigMaps            corev1.ConfigMapInterface
+	Events                *clusterprovisioninglogger.Recorder
 	NodeLister            v3.NodeLister
 	engineService         *service.EngineService
 	backoff               *flowcontrol.Backoff
@@ -51,6 +53,7 @@ func Register(ctx context.Context, management *config.ManagementContext) {
 		engineService:         service.NewEngineService(NewPersistentStore(management.Core.Namespaces(""), management.Core, management.Management.Clusters(""))),
 		Clusters:              management.Management.Clusters(""),
 		ConfigMaps:            management.Core.ConfigMaps(""),
+		Events:                clusterprovisioninglogger.NewRecorder(management.Wrangler.Mgmt.ProvisioningEvent()),
 		ClusterController:     management.Management.Clusters("").Controller(),
 		NodeLister:            management.Management.Nodes("").Controller().Lister(),
 		backoff:               flowcontrol.NewBackOff(30*time.Second, 10*time.Minute),
@@ -27,6 +27,7 @@
 	mgmtv3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
 	"github.com/rancher/rancher/pkg/kontainer-engine/drivers/util"
 	"github.com/rancher/rancher/pkg/namespace"
+	"github.com/rancher/rancher/pkg/secretbackend"
 	"github.com/rancher/rancher/pkg/systemaccount"
 	"github.com/rancher/rancher/pkg/types/config"
 	"github.com/rancher/rancher/pkg/wrangler"
@@ -580,9 +581,14 @@ func (e *eksOperatorController) getAWSSession(cluster *mgmtv3.Cluster) (*session
 			return nil, fmt.Errorf("error getting secret %s/%s: %w", ns, id, err)
 		}
 
-		accessKeyBytes := secret.Data["amazonec2credentialConfig-accessKey"]
-		secretKeyBytes := secret.Data["amazonec2credentialConfig-secretKey"]
-		if accessKeyBytes == nil || secretKeyBytes == nil {
+		data, err := secretbackend.Data(context.TODO(), secret)
+		if err != nil {
+			return nil, fmt.Errorf("error reading secret %s/%s: %w", ns, id, err)
+		}
+
+		accessKeyBytes := data["amazonec2credentialConfig-accessKey"]
+		secretKeyBytes := data["amazonec2credentialConfig-secretKey"]
+		if len(accessKeyBytes) == 0 || len(secretKeyBytes) == 0 {
 			return nil, fmt.Errorf("invalid aws cloud credential")
 		}
 
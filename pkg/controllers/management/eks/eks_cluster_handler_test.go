@@ -7,7 +7,17 @@
 	"github.com/rancher/rancher/pkg/capr"
 
 	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/aws/aws-sdk-go/aws"
+	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
 	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/controllers/management/clusteroperator"
+	"github.com/rancher/rancher/pkg/secretbackend"
+	"github.com/rancher/wrangler/v3/pkg/generic/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 const (
@@ -297,3 +307,41 @@ func Test_generateSATokenWithPublicAPI(t *testing.T) {
 func Test_getRestConfig(t *testing.T) {
 	t.Skip("not implemented: requires EKS controller")
 }
+
+func Test_getAWSSession(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	secretsCache := fake.NewMockCacheInterface[*corev1.Secret](ctrl)
+	e := &eksOperatorController{OperatorController: clusteroperator.OperatorController{SecretsCache: secretsCache}}
+	cluster := &v3.Cluster{Spec: v3.ClusterSpec{EKSConfig: &eksv1.EKSClusterConfigSpec{
+		Region:                 "us-west-2",
+		AmazonCredentialSecret: "cattle-global-data:cc-abc",
+	}}}
+
+	secretsCache.EXPECT().Get("cattle-global-data", "cc-abc").Return(&corev1.Secret{
+		Data: map[string][]byte{
+			"amazonec2credentialConfig-accessKey": []byte("access"),
+			"amazonec2credentialConfig-secretKey": []byte("secret"),
+		},
+	}, nil)
+	sess, err := e.getAWSSession(cluster)
+	require.NoError(t, err)
+	value, err := sess.Config.Credentials.Get()
+	require.NoError(t, err)
+	assert.Equal(t, "access", value.AccessKeyID)
+	assert.Equal(t, "us-west-2", aws.StringValue(sess.Config.Region))
+
+	// The data of credentials stored in a secret backend is read from the backend, not from the stub secret.
+	secretsCache.EXPECT().Get("cattle-global-data", "cc-abc").Return(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "cc-abc",
+			Namespace:   "cattle-global-data",
+			Annotations: map[string]string{secretbackend.BackendAnnotation: secretbackend.Vault},
+		},
+		Data: map[string][]byte{
+			"amazonec2credentialConfig-accessKey": {},
+			"amazonec2credentialConfig-secretKey": {},
+		},
+	}, nil)
+	_, err = e.getAWSSession(cluster)
+	assert.ErrorContains(t, err, "secret backend, which is not configured")
+}
@@ -0,0 +1,95 @@
+package tokenrevocation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/rancher/rancher/pkg/ext/stores/tokenrevocationrequest"
+	exttokens "github.com/rancher/rancher/pkg/ext/stores/tokens"
+	mgmtcontrollers "github.com/rancher/rancher/pkg/generated/controllers/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/wrangler"
+	corecontrollers "github.com/rancher/wrangler/v3/pkg/generated/controllers/core/v1"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const controllerName = "token-revocation"
+
+// extTokenStore abstracts the ext token store operations needed to revoke tokens.
+type extTokenStore interface {
+	Delete(name string, options *metav1.DeleteOptions) error
+}
+
+type handler struct {
+	configMaps corecontrollers.ConfigMapClient
+	v3Tokens   mgmtcontrollers.TokenClient
+	extTokens  extTokenStore
+}
+
+// Register registers the controller revoking the tokens recorded by TokenRevocationRequests.
+func Register(ctx context.Context, wContext *wrangler.Context) {
+	h := &handler{
+		configMaps: wContext.Core.ConfigMap(),
+		v3Tokens:   wContext.Mgmt.Token(),
+		extTokens:  exttokens.NewSystemFromWrangler(wContext),
+	}
+	wContext.Core.ConfigMap().OnChange(ctx, controllerName, h.onChange)
+}
+
+// onChange deletes the tokens recorded in a ConfigMap by a TokenRevocationRequest. The tokens which failed to be
+// deleted are kept in the ConfigMap and retried, and the ConfigMap is deleted once all the tokens are revoked.
+func (h *handler) onChange(_ string, configMap *corev1.ConfigMap) (*corev1.ConfigMap, error) {
+	if configMap == nil || configMap.DeletionTimestamp != nil || configMap.Namespace != exttokens.TokenNamespace ||
+		configMap.Labels[exttokens.SecretKindLabel] != tokenrevocationrequest.KindLabelValue {
+		return configMap, nil
+	}
+
+	pending, err := tokenrevocationrequest.ParsePending(configMap)
+	if err != nil {
+		// The ConfigMap can't be fixed by retrying.
+		logrus.Errorf("[%s] %v", controllerName, err)
+		return configMap, nil
+	}
+
+	var (
+		remaining tokenrevocationrequest.Pending
+		errs      []error
+	)
+	for _, name := range pending.V3Tokens {
+		if err := h.v3Tokens.Delete(name, &metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			errs = append(errs, fmt.Errorf("token %s: %w", name, err))
+			remaining.V3Tokens = append(remaining.V3Tokens, name)
+		}
+	}
+	for _, name := range pending.ExtTokens {
+		if err := h.extTokens.Delete(name, &metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			errs = append(errs, fmt.Errorf("token %s: %w", name, err))
+			remaining.ExtTokens = append(remaining.ExtTokens, name)
+		}
+	}
+
+	if remaining.Count() == 0 {
+		logrus.Infof("[%s] revoked %d tokens of %s", controllerName, pending.Count(), configMap.Name)
+		err := h.configMaps.Delete(configMap.Namespace, configMap.Name, &metav1.DeleteOptions{})
+		if apierrors.IsNotFound(err) {
+			return configMap, nil
+		}
+		return configMap, err
+	}
+
+	logrus.Errorf("[%s] failed to revoke %d of %d tokens of %s, retrying", controllerName, remaining.Count(), pending.Count(), configMap.Name)
+	// Record the progress, so that the tokens which were revoked aren't deleted again.
+	if remaining.Count() < pending.Count() {
+		configMap = configMap.DeepCopy()
+		if err := tokenrevocationrequest.SetPending(configMap, &remaining); err != nil {
+			return configMap, err
+		}
+		if configMap, err = h.configMaps.Update(configMap); err != nil {
+			return configMap, err
+		}
+	}
+	return configMap, errors.Join(errs...)
+}
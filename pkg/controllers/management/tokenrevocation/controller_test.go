@@ -0,0 +1,89 @@
+package tokenrevocation
+
+import (
+	"errors"
+	"testing"
+
+	apiv3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/ext/stores/tokenrevocationrequest"
+	exttokens "github.com/rancher/rancher/pkg/ext/stores/tokens"
+	"github.com/rancher/wrangler/v3/pkg/generic/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+type fakeExtTokens struct {
+	deleted []string
+	failing map[string]bool
+}
+
+func (f *fakeExtTokens) Delete(name string, _ *metav1.DeleteOptions) error {
+	if f.failing[name] {
+		return errors.New("unavailable")
+	}
+	f.deleted = append(f.deleted, name)
+	return nil
+}
+
+func newConfigMap(t *testing.T, pending *tokenrevocationrequest.Pending) *corev1.ConfigMap {
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "token-revocation-abcde",
+			Namespace: exttokens.TokenNamespace,
+			Labels:    map[string]string{exttokens.SecretKindLabel: tokenrevocationrequest.KindLabelValue},
+		},
+	}
+	require.NoError(t, tokenrevocationrequest.SetPending(configMap, pending))
+	return configMap
+}
+
+func TestOnChange(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	configMaps := fake.NewMockControllerInterface[*corev1.ConfigMap, *corev1.ConfigMapList](ctrl)
+	v3Tokens := fake.NewMockNonNamespacedControllerInterface[*apiv3.Token, *apiv3.TokenList](ctrl)
+	extTokens := &fakeExtTokens{failing: map[string]bool{"ext-b": true}}
+	h := &handler{configMaps: configMaps, v3Tokens: v3Tokens, extTokens: extTokens}
+
+	configMap := newConfigMap(t, &tokenrevocationrequest.Pending{
+		V3Tokens:  []string{"token-a", "token-gone"},
+		ExtTokens: []string{"ext-a", "ext-b"},
+	})
+
+	// The tokens which failed to be deleted are kept and retried.
+	v3Tokens.EXPECT().Delete("token-a", gomock.Any()).Return(nil)
+	v3Tokens.EXPECT().Delete("token-gone", gomock.Any()).Return(apierrors.NewNotFound(schema.GroupResource{}, "token-gone"))
+	configMaps.EXPECT().Update(gomock.Any()).DoAndReturn(func(configMap *corev1.ConfigMap) (*corev1.ConfigMap, error) {
+		pending, err := tokenrevocationrequest.ParsePending(configMap)
+		require.NoError(t, err)
+		assert.Equal(t, &tokenrevocationrequest.Pending{ExtTokens: []string{"ext-b"}}, pending)
+		return configMap, nil
+	})
+	configMap, err := h.onChange("", configMap)
+	assert.ErrorContains(t, err, "token ext-b: unavailable")
+	assert.Equal(t, []string{"ext-a"}, extTokens.deleted)
+
+	// The ConfigMap is deleted once all the tokens are revoked.
+	delete(extTokens.failing, "ext-b")
+	configMaps.EXPECT().Delete(exttokens.TokenNamespace, "token-revocation-abcde", gomock.Any()).Return(nil)
+	_, err = h.onChange("", configMap)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ext-a", "ext-b"}, extTokens.deleted)
+}
+
+func TestOnChangeIgnoresOtherConfigMaps(t *testing.T) {
+	h := &handler{}
+	configMap := newConfigMap(t, &tokenrevocationrequest.Pending{V3Tokens: []string{"token-a"}})
+	configMap.Labels = nil
+	_, err := h.onChange("", configMap)
+	require.NoError(t, err)
+
+	configMap = newConfigMap(t, &tokenrevocationrequest.Pending{V3Tokens: []string{"token-a"}})
+	configMap.Namespace = "default"
+	_, err = h.onChange("", configMap)
+	require.NoError(t, err)
+}
@@ -0,0 +1,165 @@
+package tokenusage
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	ext "github.com/rancher/rancher/pkg/apis/ext.cattle.io/v1"
+	exttokens "github.com/rancher/rancher/pkg/ext/stores/tokens"
+	"github.com/rancher/rancher/pkg/ext/stores/tokenusage"
+	"github.com/rancher/rancher/pkg/settings"
+	"github.com/rancher/rancher/pkg/wrangler"
+	corecontrollers "github.com/rancher/wrangler/v3/pkg/generated/controllers/core/v1"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// ActionDisable is the value of the token-usage-anomaly-action setting which disables flagged tokens.
+	ActionDisable = "disable"
+
+	// learningPeriod is the time after the first usage of a token during which the networks it is used from are
+	// considered known.
+	learningPeriod = 7 * 24 * time.Hour
+
+	dormantController = "token-usage-dormant"
+	networkController = "token-usage-network"
+)
+
+// tokenStore abstracts the ext token store operations needed by the controller.
+type tokenStore interface {
+	Get(name, authTokenID string, options *metav1.GetOptions) (*ext.Token, error)
+	AddLabel(name, key, value string) error
+	RemoveLabel(name, key string) error
+	Disable(name string) error
+}
+
+type handler struct {
+	tokens      tokenStore
+	secrets     corecontrollers.SecretController
+	configMaps  corecontrollers.ConfigMapClient
+	dormantDays func() int
+	action      func() string
+	now         func() time.Time
+}
+
+// Register registers the controllers flagging ext tokens which are dormant or used from previously unseen networks,
+// and disabling them if the token-usage-anomaly-action setting says so.
+func Register(ctx context.Context, wContext *wrangler.Context) {
+	h := &handler{
+		tokens:      exttokens.NewSystemFromWrangler(wContext),
+		secrets:     wContext.Core.Secret(),
+		configMaps:  wContext.Core.ConfigMap(),
+		dormantDays: settings.TokenDormantDays.GetInt,
+		action:      settings.TokenUsageAnomalyAction.Get,
+		now:         time.Now,
+	}
+	wContext.Core.Secret().OnChange(ctx, dormantController, h.onToken)
+	wContext.Core.ConfigMap().OnChange(ctx, networkController, h.onUsage)
+}
+
+// onToken flags a token when it was not used for the number of days of the token-dormant-days setting, and removes
+// the flag when it is used again. A token is flagged only once, so that a disabled token can be re-enabled. Removing
+// the label of a token which is still dormant flags it again.
+func (h *handler) onToken(_ string, secret *corev1.Secret) (*corev1.Secret, error) {
+	if secret == nil || secret.DeletionTimestamp != nil || secret.Namespace != exttokens.TokenNamespace ||
+		secret.Labels[exttokens.SecretKindLabel] != exttokens.SecretKindLabelValue {
+		return secret, nil
+	}
+	days := h.dormantDays()
+	if days <= 0 {
+		return secret, nil
+	}
+
+	token, err := h.tokens.Get(secret.Name, "", nil)
+	if apierrors.IsNotFound(err) {
+		return secret, nil
+	} else if err != nil {
+		return secret, err
+	}
+	if token.GetIsExpired() {
+		return secret, nil
+	}
+
+	lastUsedAt := token.CreationTimestamp.Time
+	if token.Status.LastUsedAt != nil {
+		lastUsedAt = token.Status.LastUsedAt.Time
+	}
+	_, flagged := token.Labels[tokenusage.DormantLabel]
+	if dormantIn := lastUsedAt.AddDate(0, 0, days).Sub(h.now()); dormantIn > 0 {
+		if flagged {
+			if err := h.tokens.RemoveLabel(token.Name, tokenusage.DormantLabel); err != nil {
+				return secret, err
+			}
+		}
+		h.secrets.EnqueueAfter(secret.Namespace, secret.Name, dormantIn)
+		return secret, nil
+	}
+	if flagged {
+		return secret, nil
+	}
+	return secret, h.flag(dormantController, token, tokenusage.DormantLabel,
+		"was not used since "+lastUsedAt.UTC().Format(time.RFC3339))
+}
+
+// onUsage flags a token when it is used from a network which was first seen after the learning period. Each network
+// is flagged only once. As only the most recently seen networks are kept, a network which was not seen for a long
+// time can be flagged again.
+func (h *handler) onUsage(_ string, configMap *corev1.ConfigMap) (*corev1.ConfigMap, error) {
+	if configMap == nil || configMap.DeletionTimestamp != nil || configMap.Namespace != exttokens.TokenNamespace ||
+		configMap.Labels[exttokens.SecretKindLabel] != tokenusage.KindLabelValue {
+		return configMap, nil
+	}
+
+	usage, err := tokenusage.ParseUsage(configMap)
+	if err != nil || usage.FirstUsedAt == nil {
+		return configMap, err
+	}
+	knownUntil := usage.FirstUsedAt.Add(learningPeriod)
+	var unseen []string
+	for i := range usage.Networks {
+		network := &usage.Networks[i]
+		if !network.Flagged && network.FirstUsedAt.After(knownUntil) {
+			network.Flagged = true
+			unseen = append(unseen, network.Value)
+		}
+	}
+	if len(unseen) == 0 {
+		return configMap, nil
+	}
+
+	token, err := h.tokens.Get(configMap.Name, "", nil)
+	if apierrors.IsNotFound(err) {
+		return configMap, nil
+	} else if err != nil {
+		return configMap, err
+	}
+	if err := h.flag(networkController, token, tokenusage.UnseenNetworkLabel,
+		"was used from previously unseen networks "+strings.Join(unseen, ", ")); err != nil {
+		return configMap, err
+	}
+
+	configMap = configMap.DeepCopy()
+	if err := tokenusage.SetUsage(configMap, usage); err != nil {
+		return configMap, err
+	}
+	return h.configMaps.Update(configMap)
+}
+
+// flag labels a token and disables it if the token-usage-anomaly-action setting says so.
+func (h *handler) flag(controller string, token *ext.Token, label, reason string) error {
+	logrus.Warnf("[%s] token %s of user %s %s", controller, token.Name, token.GetUserID(), reason)
+	if strings.EqualFold(h.action(), ActionDisable) && token.GetIsEnabled() {
+		if err := h.tokens.Disable(token.Name); err != nil {
+			return err
+		}
+		logrus.Warnf("[%s] disabled token %s of user %s", controller, token.Name, token.GetUserID())
+	}
+	if _, ok := token.Labels[label]; ok {
+		return nil
+	}
+	return h.tokens.AddLabel(token.Name, label, "true")
+}
@@ -0,0 +1,188 @@
+package tokenusage
+
+import (
+	"testing"
+	"time"
+
+	ext "github.com/rancher/rancher/pkg/apis/ext.cattle.io/v1"
+	exttokens "github.com/rancher/rancher/pkg/ext/stores/tokens"
+	"github.com/rancher/rancher/pkg/ext/stores/tokenusage"
+	"github.com/rancher/wrangler/v3/pkg/generic/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type fakeTokenStore struct {
+	token    *ext.Token
+	added    []string
+	removed  []string
+	disabled bool
+}
+
+func (f *fakeTokenStore) Get(_, _ string, _ *metav1.GetOptions) (*ext.Token, error) {
+	return f.token, nil
+}
+
+func (f *fakeTokenStore) AddLabel(_, key, _ string) error {
+	f.added = append(f.added, key)
+	return nil
+}
+
+func (f *fakeTokenStore) RemoveLabel(_, key string) error {
+	f.removed = append(f.removed, key)
+	return nil
+}
+
+func (f *fakeTokenStore) Disable(_ string) error {
+	f.disabled = true
+	return nil
+}
+
+func TestOnToken(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	enabled := true
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "token-abc",
+			Namespace: exttokens.TokenNamespace,
+			Labels:    map[string]string{exttokens.SecretKindLabel: exttokens.SecretKindLabelValue},
+		},
+	}
+	token := func(lastUsedAt time.Time, labels map[string]string) *ext.Token {
+		return &ext.Token{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "token-abc",
+				Labels:            labels,
+				CreationTimestamp: metav1.NewTime(now.Add(-365 * 24 * time.Hour)),
+			},
+			Spec:   ext.TokenSpec{UserID: "u-alice", Enabled: &enabled},
+			Status: ext.TokenStatus{LastUsedAt: &metav1.Time{Time: lastUsedAt}},
+		}
+	}
+	dormant := map[string]string{tokenusage.DormantLabel: "true"}
+
+	tests := map[string]struct {
+		token        *ext.Token
+		action       string
+		wantAdded    []string
+		wantRemoved  []string
+		wantDisabled bool
+		wantEnqueue  time.Duration
+	}{
+		"recently used": {
+			token:       token(now.Add(-24*time.Hour), nil),
+			action:      "flag",
+			wantEnqueue: 89 * 24 * time.Hour,
+		},
+		"used again": {
+			token:       token(now.Add(-24*time.Hour), dormant),
+			action:      "flag",
+			wantRemoved: []string{tokenusage.DormantLabel},
+			wantEnqueue: 89 * 24 * time.Hour,
+		},
+		"dormant": {
+			token:     token(now.Add(-90*24*time.Hour), nil),
+			action:    "flag",
+			wantAdded: []string{tokenusage.DormantLabel},
+		},
+		"dormant and disabled": {
+			token:        token(now.Add(-90*24*time.Hour), nil),
+			action:       ActionDisable,
+			wantAdded:    []string{tokenusage.DormantLabel},
+			wantDisabled: true,
+		},
+		"already flagged": {
+			token:  token(now.Add(-90*24*time.Hour), dormant),
+			action: ActionDisable,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			secrets := fake.NewMockControllerInterface[*corev1.Secret, *corev1.SecretList](ctrl)
+			tokens := &fakeTokenStore{token: tt.token}
+			h := &handler{
+				tokens:      tokens,
+				secrets:     secrets,
+				dormantDays: func() int { return 90 },
+				action:      func() string { return tt.action },
+				now:         func() time.Time { return now },
+			}
+			if tt.wantEnqueue != 0 {
+				secrets.EXPECT().EnqueueAfter(exttokens.TokenNamespace, "token-abc", tt.wantEnqueue)
+			}
+
+			_, err := h.onToken("", secret)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantAdded, tokens.added)
+			assert.Equal(t, tt.wantRemoved, tokens.removed)
+			assert.Equal(t, tt.wantDisabled, tokens.disabled)
+		})
+	}
+}
+
+func TestOnUsage(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	firstUsedAt := metav1.NewTime(now.Add(-30 * 24 * time.Hour))
+	enabled := true
+
+	usageConfigMap := func(networks ...ext.TokenUsageEntry) *corev1.ConfigMap {
+		configMap := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "token-abc",
+				Namespace: exttokens.TokenNamespace,
+				Labels:    map[string]string{exttokens.SecretKindLabel: tokenusage.KindLabelValue},
+			},
+		}
+		require.NoError(t, tokenusage.SetUsage(configMap, &ext.TokenUsageStatus{
+			FirstUsedAt:  &firstUsedAt,
+			LastUsedAt:   &metav1.Time{Time: now},
+			RequestCount: 10,
+			Networks:     networks,
+		}))
+		return configMap
+	}
+	known := ext.TokenUsageEntry{Value: "10.0.0.0/24", FirstUsedAt: firstUsedAt, LastUsedAt: metav1.NewTime(now)}
+	unseen := ext.TokenUsageEntry{Value: "192.168.1.0/24", FirstUsedAt: metav1.NewTime(now), LastUsedAt: metav1.NewTime(now)}
+	flagged := unseen
+	flagged.Flagged = true
+
+	t.Run("known networks", func(t *testing.T) {
+		tokens := &fakeTokenStore{}
+		h := &handler{tokens: tokens}
+
+		_, err := h.onUsage("", usageConfigMap(known, flagged))
+		require.NoError(t, err)
+		assert.Empty(t, tokens.added)
+	})
+
+	t.Run("unseen network", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		configMaps := fake.NewMockControllerInterface[*corev1.ConfigMap, *corev1.ConfigMapList](ctrl)
+		tokens := &fakeTokenStore{token: &ext.Token{
+			ObjectMeta: metav1.ObjectMeta{Name: "token-abc"},
+			Spec:       ext.TokenSpec{UserID: "u-alice", Enabled: &enabled},
+		}}
+		h := &handler{
+			tokens:     tokens,
+			configMaps: configMaps,
+			action:     func() string { return ActionDisable },
+		}
+		configMaps.EXPECT().Update(gomock.Any()).DoAndReturn(func(configMap *corev1.ConfigMap) (*corev1.ConfigMap, error) {
+			usage, err := tokenusage.ParseUsage(configMap)
+			require.NoError(t, err)
+			require.Len(t, usage.Networks, 2)
+			assert.False(t, usage.Networks[0].Flagged)
+			assert.True(t, usage.Networks[1].Flagged)
+			return configMap, nil
+		})
+
+		_, err := h.onUsage("", usageConfigMap(known, unseen))
+		require.NoError(t, err)
+		assert.Equal(t, []string{tokenusage.UnseenNetworkLabel}, tokens.added)
+		assert.True(t, tokens.disabled)
+	})
+}
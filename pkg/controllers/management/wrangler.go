@@ -7,12 +7,16 @@
 	"github.com/rancher/rancher/pkg/controllers/management/aks"
 	"github.com/rancher/rancher/pkg/controllers/management/alibaba"
 	"github.com/rancher/rancher/pkg/controllers/management/authprovisioningv2"
+	"github.com/rancher/rancher/pkg/controllers/management/breakglass"
+	"github.com/rancher/rancher/pkg/controllers/management/carotation"
 	"github.com/rancher/rancher/pkg/controllers/management/clusterupstreamrefresher"
 	"github.com/rancher/rancher/pkg/controllers/management/eks"
 	"github.com/rancher/rancher/pkg/controllers/management/feature"
 	"github.com/rancher/rancher/pkg/controllers/management/gke"
 	"github.com/rancher/rancher/pkg/controllers/management/k3sbasedupgrade"
 	"github.com/rancher/rancher/pkg/controllers/management/oidcprovider"
+	"github.com/rancher/rancher/pkg/controllers/management/tokenrevocation"
+	"github.com/rancher/rancher/pkg/controllers/management/tokenusage"
 	"github.com/rancher/rancher/pkg/features"
 	"github.com/rancher/rancher/pkg/types/config"
 	"github.com/rancher/rancher/pkg/wrangler"
@@ -25,6 +29,9 @@ func RegisterWrangler(ctx context.Context, wranglerContext *wrangler.Context, ma
 	gke.Register(ctx, wranglerContext, management)
 	alibaba.Register(ctx, wranglerContext, management)
 	clusterupstreamrefresher.Register(ctx, wranglerContext)
+	carotation.Register(ctx, wranglerContext)
+	tokenusage.Register(ctx, wranglerContext)
+	tokenrevocation.Register(ctx, wranglerContext)
 
 	feature.Register(ctx, management, wranglerContext)
 
@@ -38,5 +45,9 @@ func RegisterWrangler(ctx context.Context, wranglerContext *wrangler.Context, ma
 		oidcprovider.Register(ctx, wranglerContext)
 	}
 
+	if features.BreakGlassCredentials.Enabled() {
+		breakglass.Register(ctx, wranglerContext)
+	}
+
 	return nil
 }
@@ -9,6 +9,7 @@
 	"github.com/rancher/rancher/pkg/controllers"
 	mgmtv3controllers "github.com/rancher/rancher/pkg/generated/controllers/management.cattle.io/v3"
 	"github.com/rancher/rancher/pkg/namespace"
+	"github.com/rancher/rancher/pkg/systemtemplate"
 	"github.com/rancher/rancher/pkg/types/config"
 	"github.com/rancher/wrangler/v3/pkg/condition"
 	corecontrollers "github.com/rancher/wrangler/v3/pkg/generated/controllers/core/v1"
@@ -66,6 +67,10 @@ func (c *CertificateAuthorityValidator) onStvAggregationSecret(key string, obj *
 		return nil, nil
 	}
 
+	// The agent downloads the CA certificates, validates them against CATTLE_CA_CHECKSUM and stores them in ca.crt,
+	// so their checksum tells which CA certificates the agent trusts.
+	caChecksum := systemtemplate.CACertsChecksum(string(obj.Data["ca.crt"]))
+
 	return obj, retry.RetryOnConflict(retry.DefaultRetry, func() error {
 		mgmtCluster, err := c.clusterCache.Get(c.clusterName)
 		if err != nil {
@@ -73,23 +78,29 @@ func (c *CertificateAuthorityValidator) onStvAggregationSecret(key string, obj *
 		}
 		mgmtCluster = mgmtCluster.DeepCopy()
 
+		changed := mgmtCluster.Status.AgentCACertsChecksum != caChecksum
+		mgmtCluster.Status.AgentCACertsChecksum = caChecksum
+
 		if string(obj.Data[CacertsValid]) == "true" && len(obj.Data["ca.crt"]) != 0 {
-			if CertificateAuthorityValid.IsTrue(mgmtCluster) {
-				return nil
+			if !CertificateAuthorityValid.IsTrue(mgmtCluster) {
+				CertificateAuthorityValid.True(mgmtCluster)
+				changed = true
 			}
-			CertificateAuthorityValid.True(mgmtCluster)
 		} else if string(obj.Data[CacertsValid]) == "false" {
-			if CertificateAuthorityValid.IsFalse(mgmtCluster) {
-				return nil
+			if !CertificateAuthorityValid.IsFalse(mgmtCluster) {
+				CertificateAuthorityValid.False(mgmtCluster)
+				changed = true
 			}
-			CertificateAuthorityValid.False(mgmtCluster)
 		} else {
-			if CertificateAuthorityValid.IsUnknown(mgmtCluster) {
-				return nil
+			if !CertificateAuthorityValid.IsUnknown(mgmtCluster) {
+				CertificateAuthorityValid.Unknown(mgmtCluster)
+				changed = true
 			}
-			CertificateAuthorityValid.Unknown(mgmtCluster)
 		}
 
+		if !changed {
+			return nil
+		}
 		_, err = c.clusters.UpdateStatus(mgmtCluster)
 		return err
 	})
@@ -5,6 +5,7 @@
 
 	mgmtv3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
 	"github.com/rancher/rancher/pkg/namespace"
+	"github.com/rancher/rancher/pkg/systemtemplate"
 	"github.com/rancher/wrangler/v3/pkg/generic/fake"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -144,3 +145,48 @@ type args struct {
 		})
 	}
 }
+
+func TestCAValidator_agentCACertsChecksum(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "stv-aggregation",
+			Namespace: namespace.System,
+		},
+		Data: map[string][]byte{
+			CacertsValid: []byte("true"),
+			"ca.crt":     []byte("test"),
+		},
+	}
+	checksum := systemtemplate.CACertsChecksum("test")
+
+	t.Run("checksum is recorded", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		cluster := &mgmtv3.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "c-cluster"}}
+		CertificateAuthorityValid.True(cluster)
+		clusterCache := fake.NewMockNonNamespacedCacheInterface[*mgmtv3.Cluster](ctrl)
+		clusterCache.EXPECT().Get(cluster.Name).Return(cluster, nil)
+		clusters := fake.NewMockNonNamespacedClientInterface[*mgmtv3.Cluster, *mgmtv3.ClusterList](ctrl)
+		clusters.EXPECT().UpdateStatus(gomock.Any()).DoAndReturn(func(cluster *mgmtv3.Cluster) (*mgmtv3.Cluster, error) {
+			assert.Equal(t, checksum, cluster.Status.AgentCACertsChecksum)
+			return cluster, nil
+		})
+
+		cav := &CertificateAuthorityValidator{clusterName: cluster.Name, clusterCache: clusterCache, clusters: clusters}
+		_, err := cav.onStvAggregationSecret(secret.Namespace+"/"+secret.Name, secret)
+		assert.NoError(t, err)
+	})
+
+	t.Run("unchanged status is not updated", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		cluster := &mgmtv3.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "c-cluster"}}
+		cluster.Status.AgentCACertsChecksum = checksum
+		CertificateAuthorityValid.True(cluster)
+		clusterCache := fake.NewMockNonNamespacedCacheInterface[*mgmtv3.Cluster](ctrl)
+		clusterCache.EXPECT().Get(cluster.Name).Return(cluster, nil)
+		clusters := fake.NewMockNonNamespacedClientInterface[*mgmtv3.Cluster, *mgmtv3.ClusterList](ctrl)
+
+		cav := &CertificateAuthorityValidator{clusterName: cluster.Name, clusterCache: clusterCache, clusters: clusters}
+		_, err := cav.onStvAggregationSecret(secret.Namespace+"/"+secret.Name, secret)
+		assert.NoError(t, err)
+	})
+}
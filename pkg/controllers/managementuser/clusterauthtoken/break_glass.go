@@ -0,0 +1,150 @@
+package clusterauthtoken
+
+import (
+	"fmt"
+	"maps"
+	"sort"
+	"time"
+
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/controllers/management/breakglass"
+	"github.com/rancher/rancher/pkg/controllers/managementuser/clusterauthtoken/common"
+	mgmtcontrollers "github.com/rancher/rancher/pkg/generated/controllers/management.cattle.io/v3"
+	wcore "github.com/rancher/wrangler/v3/pkg/generated/controllers/core/v1"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// breakGlassHandler publishes the configuration break-glass tokens are verified with to the downstream cluster, and
+// records the usage of break-glass credentials the downstream cluster reports back.
+type breakGlassHandler struct {
+	namespace              string
+	clusterName            string
+	credentials            mgmtcontrollers.BreakGlassCredentialClient
+	credentialCache        mgmtcontrollers.BreakGlassCredentialCache
+	secretCache            wcore.SecretCache
+	clusterConfigMap       wcore.ConfigMapClient
+	clusterConfigMapLister wcore.ConfigMapCache
+	now                    func() time.Time
+}
+
+func (h *breakGlassHandler) onCredentialChange(_ string, credential *v3.BreakGlassCredential) (*v3.BreakGlassCredential, error) {
+	// Deleted credentials can't be attributed to a cluster, so they are removed from the configuration of every
+	// cluster.
+	if credential != nil && credential.Spec.ClusterName != h.clusterName {
+		return credential, nil
+	}
+	return credential, h.publish()
+}
+
+func (h *breakGlassHandler) onSigningKeyChange(key string, secret *corev1.Secret) (*corev1.Secret, error) {
+	if key != breakglass.SigningKeySecretNamespace+"/"+breakglass.SigningKeySecretName {
+		return secret, nil
+	}
+	return secret, h.publish()
+}
+
+// publish creates or updates the break-glass ConfigMap of the downstream cluster with the public signing keys and the
+// credentials of the cluster which are issued and not expired.
+func (h *breakGlassHandler) publish() error {
+	config := &common.BreakGlassConfig{Keys: common.BreakGlassKeySet{Keys: []common.BreakGlassKey{}}}
+
+	secret, err := h.secretCache.Get(breakglass.SigningKeySecretNamespace, breakglass.SigningKeySecretName)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	if secret != nil {
+		if config.Keys, err = breakglass.PublicKeys(secret); err != nil {
+			return err
+		}
+	}
+
+	credentials, err := h.credentialCache.List(labels.Everything())
+	if err != nil {
+		return err
+	}
+	now := h.now()
+	for _, credential := range credentials {
+		if credential.Spec.ClusterName != h.clusterName ||
+			credential.Status.ExpiresAt == nil ||
+			!now.Before(credential.Status.ExpiresAt.Time) ||
+			meta.IsStatusConditionTrue(credential.Status.Conditions, v3.BreakGlassCredentialConditionExpired) {
+			continue
+		}
+		config.Credentials = append(config.Credentials, common.BreakGlassCredential{
+			Name:      credential.Name,
+			UID:       string(credential.UID),
+			UserName:  credential.Spec.UserName,
+			ExpiresAt: credential.Status.ExpiresAt.UTC(),
+		})
+	}
+	sort.Slice(config.Credentials, func(i, j int) bool { return config.Credentials[i].Name < config.Credentials[j].Name })
+
+	data, err := config.Data()
+	if err != nil {
+		return err
+	}
+	configMap, err := h.clusterConfigMapLister.Get(h.namespace, common.BreakGlassConfigMapName)
+	if apierrors.IsNotFound(err) {
+		_, err = h.clusterConfigMap.Create(&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      common.BreakGlassConfigMapName,
+				Namespace: h.namespace,
+			},
+			Data: data,
+		})
+		return err
+	} else if err != nil {
+		return err
+	}
+	if maps.Equal(configMap.Data, data) {
+		return nil
+	}
+	configMap = configMap.DeepCopy()
+	configMap.Data = data
+	_, err = h.clusterConfigMap.Update(configMap)
+	return err
+}
+
+// onUsageChange records the usage of a break-glass credential reported by the downstream cluster in the status of the
+// credential.
+func (h *breakGlassHandler) onUsageChange(_ string, configMap *corev1.ConfigMap) (*corev1.ConfigMap, error) {
+	if configMap == nil || configMap.Namespace != h.namespace || configMap.Labels[common.BreakGlassUsageLabel] == "" {
+		return configMap, nil
+	}
+
+	credential, err := h.credentialCache.Get(configMap.Labels[common.BreakGlassUsageLabel])
+	if apierrors.IsNotFound(err) {
+		return configMap, nil
+	} else if err != nil {
+		return configMap, err
+	}
+	// A cluster can only report the usage of its own credentials.
+	if credential.Spec.ClusterName != h.clusterName {
+		logrus.Warnf("[%s] ignoring usage reported by cluster %s for break-glass credential %s of cluster %s",
+			breakGlassUsageController, h.clusterName, credential.Name, credential.Spec.ClusterName)
+		return configMap, nil
+	}
+
+	usage, err := common.ParseBreakGlassUsage(configMap.Data)
+	if err != nil {
+		return configMap, fmt.Errorf("parsing usage of break-glass credential %s: %w", credential.Name, err)
+	}
+	if usage.RequestCount <= credential.Status.RequestCount {
+		return configMap, nil
+	}
+
+	logrus.Warnf("[%s] break-glass credential %s was used by user %s on cluster %s: %d requests between %s and %s",
+		breakGlassUsageController, credential.Name, credential.Spec.UserName, h.clusterName, usage.RequestCount,
+		usage.FirstUsedAt.UTC().Format(time.RFC3339), usage.LastUsedAt.UTC().Format(time.RFC3339))
+	credential = credential.DeepCopy()
+	credential.Status.FirstUsedAt = &metav1.Time{Time: usage.FirstUsedAt}
+	credential.Status.LastUsedAt = &metav1.Time{Time: usage.LastUsedAt}
+	credential.Status.RequestCount = usage.RequestCount
+	_, err = h.credentials.UpdateStatus(credential)
+	return configMap, err
+}
@@ -0,0 +1,167 @@
+package clusterauthtoken
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/controllers/management/breakglass"
+	"github.com/rancher/rancher/pkg/controllers/managementuser/clusterauthtoken/common"
+	"github.com/rancher/wrangler/v3/pkg/generic/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestBreakGlassPublish(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	credentialCache := fake.NewMockNonNamespacedCacheInterface[*v3.BreakGlassCredential](ctrl)
+	secretCache := fake.NewMockCacheInterface[*corev1.Secret](ctrl)
+	configMaps := fake.NewMockClientInterface[*corev1.ConfigMap, *corev1.ConfigMapList](ctrl)
+	configMapCache := fake.NewMockCacheInterface[*corev1.ConfigMap](ctrl)
+
+	h := &breakGlassHandler{
+		namespace:              common.DefaultNamespace,
+		clusterName:            "c-m-abc",
+		credentialCache:        credentialCache,
+		secretCache:            secretCache,
+		clusterConfigMap:       configMaps,
+		clusterConfigMapLister: configMapCache,
+		now:                    func() time.Time { return now },
+	}
+
+	credential := func(name, clusterName string, expiresAt time.Time) *v3.BreakGlassCredential {
+		return &v3.BreakGlassCredential{
+			ObjectMeta: metav1.ObjectMeta{Name: name, UID: types.UID("uid-" + name)},
+			Spec:       v3.BreakGlassCredentialSpec{ClusterName: clusterName, UserName: "u-alice"},
+			Status:     v3.BreakGlassCredentialStatus{ExpiresAt: &metav1.Time{Time: expiresAt}},
+		}
+	}
+	expired := credential("expired", "c-m-abc", now.Add(time.Hour))
+	meta.SetStatusCondition(&expired.Status.Conditions, metav1.Condition{
+		Type:   v3.BreakGlassCredentialConditionExpired,
+		Status: metav1.ConditionTrue,
+		Reason: "Expired",
+	})
+	pending := credential("pending", "c-m-abc", time.Time{})
+	pending.Status.ExpiresAt = nil
+
+	credentialCache.EXPECT().List(labels.Everything()).Return([]*v3.BreakGlassCredential{
+		credential("valid", "c-m-abc", now.Add(time.Hour)),
+		credential("other-cluster", "c-m-def", now.Add(time.Hour)),
+		credential("past", "c-m-abc", now.Add(-time.Second)),
+		expired,
+		pending,
+	}, nil)
+	secretCache.EXPECT().Get(breakglass.SigningKeySecretNamespace, breakglass.SigningKeySecretName).
+		Return(nil, apierrors.NewNotFound(schema.GroupResource{}, breakglass.SigningKeySecretName))
+	configMapCache.EXPECT().Get(common.DefaultNamespace, common.BreakGlassConfigMapName).
+		Return(nil, apierrors.NewNotFound(schema.GroupResource{}, common.BreakGlassConfigMapName))
+
+	configMaps.EXPECT().Create(gomock.Any()).DoAndReturn(func(configMap *corev1.ConfigMap) (*corev1.ConfigMap, error) {
+		assert.Equal(t, common.DefaultNamespace, configMap.Namespace)
+		assert.Equal(t, common.BreakGlassConfigMapName, configMap.Name)
+		var keys common.BreakGlassKeySet
+		require.NoError(t, json.Unmarshal([]byte(configMap.Data[common.BreakGlassKeysField]), &keys))
+		assert.Empty(t, keys.Keys)
+		var credentials []common.BreakGlassCredential
+		require.NoError(t, json.Unmarshal([]byte(configMap.Data[common.BreakGlassCredentialsField]), &credentials))
+		require.Len(t, credentials, 1)
+		assert.Equal(t, common.BreakGlassCredential{
+			Name:      "valid",
+			UID:       "uid-valid",
+			UserName:  "u-alice",
+			ExpiresAt: now.Add(time.Hour),
+		}, credentials[0])
+		return configMap, nil
+	})
+
+	_, err := h.onCredentialChange("", credential("valid", "c-m-abc", now.Add(time.Hour)))
+	require.NoError(t, err)
+
+	// Credentials of other clusters don't change the configuration.
+	_, err = h.onCredentialChange("", credential("other-cluster", "c-m-def", now.Add(time.Hour)))
+	require.NoError(t, err)
+}
+
+func TestBreakGlassUsage(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	usageConfigMap := func(credentialName string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "break-glass-usage-" + credentialName,
+				Namespace: common.DefaultNamespace,
+				Labels:    map[string]string{common.BreakGlassUsageLabel: credentialName},
+			},
+			Data: map[string]string{
+				"firstUsedAt":  now.Add(-time.Hour).Format(time.RFC3339),
+				"lastUsedAt":   now.Format(time.RFC3339),
+				"requestCount": "5",
+			},
+		}
+	}
+
+	tests := map[string]struct {
+		credential *v3.BreakGlassCredential
+		wantUpdate bool
+	}{
+		"usage is recorded": {
+			credential: &v3.BreakGlassCredential{
+				ObjectMeta: metav1.ObjectMeta{Name: "emergency"},
+				Spec:       v3.BreakGlassCredentialSpec{ClusterName: "c-m-abc", UserName: "u-alice"},
+				Status:     v3.BreakGlassCredentialStatus{RequestCount: 2},
+			},
+			wantUpdate: true,
+		},
+		"usage already recorded": {
+			credential: &v3.BreakGlassCredential{
+				ObjectMeta: metav1.ObjectMeta{Name: "emergency"},
+				Spec:       v3.BreakGlassCredentialSpec{ClusterName: "c-m-abc", UserName: "u-alice"},
+				Status:     v3.BreakGlassCredentialStatus{RequestCount: 5},
+			},
+		},
+		"credential of another cluster": {
+			credential: &v3.BreakGlassCredential{
+				ObjectMeta: metav1.ObjectMeta{Name: "emergency"},
+				Spec:       v3.BreakGlassCredentialSpec{ClusterName: "c-m-def", UserName: "u-alice"},
+			},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			credentials := fake.NewMockNonNamespacedClientInterface[*v3.BreakGlassCredential, *v3.BreakGlassCredentialList](ctrl)
+			credentialCache := fake.NewMockNonNamespacedCacheInterface[*v3.BreakGlassCredential](ctrl)
+			h := &breakGlassHandler{
+				namespace:       common.DefaultNamespace,
+				clusterName:     "c-m-abc",
+				credentials:     credentials,
+				credentialCache: credentialCache,
+			}
+
+			credentialCache.EXPECT().Get("emergency").Return(tt.credential, nil)
+			if tt.wantUpdate {
+				credentials.EXPECT().UpdateStatus(gomock.Any()).DoAndReturn(func(credential *v3.BreakGlassCredential) (*v3.BreakGlassCredential, error) {
+					assert.Equal(t, int64(5), credential.Status.RequestCount)
+					assert.True(t, now.Add(-time.Hour).Equal(credential.Status.FirstUsedAt.Time))
+					assert.True(t, now.Equal(credential.Status.LastUsedAt.Time))
+					return credential, nil
+				})
+			}
+
+			_, err := h.onUsageChange("", usageConfigMap("emergency"))
+			require.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package common
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strconv"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	// BreakGlassConfigMapName is the name of the ConfigMap in the DefaultNamespace of downstream clusters holding the
+	// configuration break-glass tokens are verified with.
+	BreakGlassConfigMapName = "break-glass"
+	// BreakGlassKeysField is the entry of the break-glass ConfigMap holding the JSON Web Key Set of the public keys
+	// break-glass tokens are signed with.
+	BreakGlassKeysField = "jwks.json"
+	// BreakGlassCredentialsField is the entry of the break-glass ConfigMap holding the credentials of the cluster
+	// which are neither expired nor deleted. Tokens of other credentials are rejected.
+	BreakGlassCredentialsField = "credentials.json"
+	// BreakGlassIssuer is the issuer of break-glass tokens.
+	BreakGlassIssuer = "rancher"
+
+	// BreakGlassUsageLabel labels the ConfigMaps in the DefaultNamespace of downstream clusters recording the usage
+	// of a break-glass credential. Its value is the name of the credential. The usage is held by the firstUsedAt and
+	// lastUsedAt RFC 3339 times and the requestCount entries.
+	BreakGlassUsageLabel = "cattle.io/break-glass-credential"
+
+	breakGlassFirstUsedAtField  = "firstUsedAt"
+	breakGlassLastUsedAtField   = "lastUsedAt"
+	breakGlassRequestCountField = "requestCount"
+)
+
+// BreakGlassClaims are the claims of break-glass tokens. The subject is the name of the user, the audience the name
+// of the cluster and the ID the UID of the credential.
+type BreakGlassClaims struct {
+	jwt.RegisteredClaims
+	// Groups are the group principals of the user when the token was issued.
+	Groups []string `json:"groups,omitempty"`
+}
+
+// BreakGlassKey is a JSON Web Key of a public key break-glass tokens are signed with.
+type BreakGlassKey struct {
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	Kid string `json:"kid"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+// NewBreakGlassKey returns the JSON Web Key of an RSA public key.
+func NewBreakGlassKey(kid string, key *rsa.PublicKey) BreakGlassKey {
+	return BreakGlassKey{
+		Kty: "RSA",
+		Use: "sig",
+		Kid: kid,
+		N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}
+}
+
+// BreakGlassKeySet is a JSON Web Key Set.
+type BreakGlassKeySet struct {
+	Keys []BreakGlassKey `json:"keys"`
+}
+
+// BreakGlassCredential is a credential break-glass tokens are accepted for.
+type BreakGlassCredential struct {
+	Name      string    `json:"name"`
+	UID       string    `json:"uid"`
+	UserName  string    `json:"userName"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// BreakGlassConfig is the configuration the authenticator of downstream clusters verifies break-glass tokens with.
+// Tokens must be signed with one of the keys, and be issued for one of the credentials and its user. Neither the token
+// nor the credential may be expired.
+type BreakGlassConfig struct {
+	Keys        BreakGlassKeySet
+	Credentials []BreakGlassCredential
+}
+
+// Data returns the data of the break-glass ConfigMap holding the configuration.
+func (c *BreakGlassConfig) Data() (map[string]string, error) {
+	keys, err := json.Marshal(c.Keys)
+	if err != nil {
+		return nil, err
+	}
+	credentials := c.Credentials
+	if credentials == nil {
+		credentials = []BreakGlassCredential{}
+	}
+	creds, err := json.Marshal(credentials)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]string{
+		BreakGlassKeysField:        string(keys),
+		BreakGlassCredentialsField: string(creds),
+	}, nil
+}
+
+// BreakGlassUsage is the usage of a break-glass credential recorded by a downstream cluster.
+type BreakGlassUsage struct {
+	FirstUsedAt  time.Time
+	LastUsedAt   time.Time
+	RequestCount int64
+}
+
+// ParseBreakGlassUsage returns the usage held by the data of a break-glass usage ConfigMap.
+func ParseBreakGlassUsage(data map[string]string) (BreakGlassUsage, error) {
+	var usage BreakGlassUsage
+	var err error
+	if usage.FirstUsedAt, err = time.Parse(time.RFC3339, data[breakGlassFirstUsedAtField]); err != nil {
+		return usage, fmt.Errorf("parsing %s: %w", breakGlassFirstUsedAtField, err)
+	}
+	if usage.LastUsedAt, err = time.Parse(time.RFC3339, data[breakGlassLastUsedAtField]); err != nil {
+		return usage, fmt.Errorf("parsing %s: %w", breakGlassLastUsedAtField, err)
+	}
+	if usage.RequestCount, err = strconv.ParseInt(data[breakGlassRequestCountField], 10, 64); err != nil {
+		return usage, fmt.Errorf("parsing %s: %w", breakGlassRequestCountField, err)
+	}
+	return usage, nil
+}
@@ -0,0 +1,53 @@
+package common
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBreakGlassConfigData(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	expiresAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	config := &BreakGlassConfig{
+		Keys:        BreakGlassKeySet{Keys: []BreakGlassKey{NewBreakGlassKey("key", &key.PublicKey)}},
+		Credentials: []BreakGlassCredential{{Name: "emergency", UID: "uid", UserName: "u-alice", ExpiresAt: expiresAt}},
+	}
+	data, err := config.Data()
+	require.NoError(t, err)
+
+	var keys BreakGlassKeySet
+	require.NoError(t, json.Unmarshal([]byte(data[BreakGlassKeysField]), &keys))
+	assert.Equal(t, config.Keys, keys)
+	var credentials []BreakGlassCredential
+	require.NoError(t, json.Unmarshal([]byte(data[BreakGlassCredentialsField]), &credentials))
+	assert.Equal(t, config.Credentials, credentials)
+
+	// Clusters without credentials get an empty list rather than null.
+	data, err = (&BreakGlassConfig{}).Data()
+	require.NoError(t, err)
+	assert.Equal(t, "[]", data[BreakGlassCredentialsField])
+}
+
+func TestParseBreakGlassUsage(t *testing.T) {
+	usage, err := ParseBreakGlassUsage(map[string]string{
+		"firstUsedAt":  "2026-01-02T03:04:05Z",
+		"lastUsedAt":   "2026-01-02T03:05:05Z",
+		"requestCount": "2",
+	})
+	require.NoError(t, err)
+	first := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	assert.True(t, first.Equal(usage.FirstUsedAt))
+	assert.True(t, first.Add(time.Minute).Equal(usage.LastUsedAt))
+	assert.Equal(t, int64(2), usage.RequestCount)
+
+	_, err = ParseBreakGlassUsage(map[string]string{})
+	assert.ErrorContains(t, err, "parsing firstUsedAt")
+}
@@ -3,6 +3,7 @@
 import (
 	"context"
 	"fmt"
+	"time"
 
 	lassocache "github.com/rancher/lasso/pkg/cache"
 	"github.com/rancher/lasso/pkg/client"
@@ -11,6 +12,7 @@
 	"github.com/rancher/rancher/pkg/controllers"
 	"github.com/rancher/rancher/pkg/controllers/managementuser/clusterauthtoken/common"
 	extstore "github.com/rancher/rancher/pkg/ext/stores/tokens"
+	"github.com/rancher/rancher/pkg/features"
 	ext "github.com/rancher/rancher/pkg/generated/controllers/ext.cattle.io/v1"
 	managementv3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
 	"github.com/rancher/rancher/pkg/types/config"
@@ -32,6 +34,9 @@
 	userAttributeController        = "cat-user-attribute-controller"
 	clusterUserAttributeController = "cat-cluster-user-attribute-controller"
 	clusterAuthTokenController     = "cat-cluster-auth-token-controller"
+	breakGlassController           = "cat-break-glass-controller"
+	breakGlassKeyController        = "cat-break-glass-key-controller"
+	breakGlassUsageController      = "cat-break-glass-usage-controller"
 )
 
 // RegisterExtIndexers adds indexing of ext tokens by user and cluster to their
@@ -54,8 +59,8 @@ func RegisterIndexers(scaledContext *config.ScaledContext) error {
 		})
 }
 
-// RegisterFactory creates the dedicated namespace-scoped secrets cache for
-// clusterauthtoken handlers and registers it with the UserContext so it is
+// RegisterFactory creates the dedicated namespace-scoped secrets cache and
+// config maps controller for clusterauthtoken handlers and registers it with the UserContext so it is
 // started by UserContext.Start() as part of the cluster controller's normal
 // factory-start sequence.
 //
@@ -65,20 +70,22 @@ func RegisterIndexers(scaledContext *config.ScaledContext) error {
 // if it fails, the error surfaces to the cluster manager, which logs the
 // failure, marks the cluster unavailable, and retries.
 //
-// Returns the SecretCache to pass to Register() for handler wiring.
-func RegisterFactory(cluster *config.UserContext) (corecontrollers.SecretCache, error) {
+// Returns the SecretCache and the ConfigMap controller to pass to Register()
+// for handler wiring.
+func RegisterFactory(cluster *config.UserContext) (corecontrollers.SecretCache, corecontrollers.ConfigMapController, error) {
 	clientFactory := cluster.ControllerFactory.SharedCacheFactory().SharedClientFactory()
-	secretsCache, controllerFactory := newDedicatedSecretsCache(clientFactory, common.DefaultNamespace)
+	core, controllerFactory := newDedicatedFactory(clientFactory, common.DefaultNamespace)
 	// startContext is nil here: factory is added to extraControllerFactories
 	// without being started. UserContext.Start() starts it in its factory loop.
 	if err := cluster.RegisterExtraControllerFactory("clusterauthtoken", controllerFactory); err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	return secretsCache, nil
+	return core.Secret().Cache(), core.ConfigMap(), nil
 }
 
-// Register wires up clusterauthtoken event handlers. secretsCache must be the
-// value returned by RegisterFactory on the same UserContext.
+// Register wires up clusterauthtoken event handlers. secretsCache and
+// configMaps must be the values returned by RegisterFactory on the same
+// UserContext.
 //
 // v3 Token handlers are registered immediately — they have no EXT API
 // dependency and are active as soon as the management informers deliver events,
@@ -86,7 +93,7 @@ func RegisterFactory(cluster *config.UserContext) (corecontrollers.SecretCache,
 //
 // ext Token handlers are registered once the EXT API is ready, since they
 // require the EXT API client context.
-func Register(ctx context.Context, cluster *config.UserContext, secretsCache corecontrollers.SecretCache) {
+func Register(ctx context.Context, cluster *config.UserContext, secretsCache corecontrollers.SecretCache, configMaps corecontrollers.ConfigMapController) {
 	namespace := common.DefaultNamespace
 	clusterName := cluster.ClusterName
 
@@ -151,6 +158,22 @@ func Register(ctx context.Context, cluster *config.UserContext, secretsCache cor
 		clusterUserAttribute,
 	}).Sync)
 
+	if features.BreakGlassCredentials.Enabled() {
+		breakGlass := &breakGlassHandler{
+			namespace:              namespace,
+			clusterName:            clusterName,
+			credentials:            cluster.Management.Wrangler.Mgmt.BreakGlassCredential(),
+			credentialCache:        cluster.Management.Wrangler.Mgmt.BreakGlassCredential().Cache(),
+			secretCache:            cluster.Management.Wrangler.Core.Secret().Cache(),
+			clusterConfigMap:       clusterConfigMap,
+			clusterConfigMapLister: clusterConfigMapLister,
+			now:                    time.Now,
+		}
+		cluster.Management.Wrangler.Mgmt.BreakGlassCredential().OnChange(ctx, breakGlassController, breakGlass.onCredentialChange)
+		cluster.Management.Wrangler.Core.Secret().OnChange(ctx, breakGlassKeyController, breakGlass.onSigningKeyChange)
+		configMaps.OnChange(ctx, breakGlassUsageController, breakGlass.onUsageChange)
+	}
+
 	cluster.Management.Wrangler.DeferredEXTAPIRegistration.DeferFunc(func(w *wrangler.EXTAPIContext) {
 		extToken := w.Client.Token()
 		handler.extTokenIndexer.Store(extToken.Informer().GetIndexer())
@@ -166,14 +189,15 @@ func Register(ctx context.Context, cluster *config.UserContext, secretsCache cor
 	})
 }
 
-func newDedicatedSecretsCache(clientFactory client.SharedClientFactory, namespace string) (corecontrollers.SecretCache, controller.SharedControllerFactory) {
+func newDedicatedFactory(clientFactory client.SharedClientFactory, namespace string) (corecontrollers.Interface, controller.SharedControllerFactory) {
 	cacheFactory := lassocache.NewSharedCachedFactory(clientFactory, &lassocache.SharedCacheFactoryOptions{
 		KindNamespace: map[schema.GroupVersionKind]string{
-			corev1.SchemeGroupVersion.WithKind("Secret"): namespace,
+			corev1.SchemeGroupVersion.WithKind("Secret"):    namespace,
+			corev1.SchemeGroupVersion.WithKind("ConfigMap"): namespace,
 		},
 	})
 	controllerFactory := controller.NewSharedControllerFactory(cacheFactory, controllers.GetOptsFromEnv(controllers.User))
-	return corecontrollers.New(controllerFactory).Secret().Cache(), controllerFactory
+	return corecontrollers.New(controllerFactory), controllerFactory
 }
 
 func tokenUserClusterKey(token *managementv3.Token) string {
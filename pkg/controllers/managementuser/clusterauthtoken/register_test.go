@@ -21,11 +21,12 @@ func TestRegisterFactoryDuplicate(t *testing.T) {
 	t.Parallel()
 
 	cluster := buildMinimalUserContext(t)
-	cache, err := RegisterFactory(cluster)
+	cache, configMaps, err := RegisterFactory(cluster)
 	require.NoError(t, err)
 	assert.NotNil(t, cache)
+	assert.NotNil(t, configMaps)
 
-	_, err = RegisterFactory(cluster)
+	_, _, err = RegisterFactory(cluster)
 	require.Error(t, err)
 	assert.ErrorContains(t, err, "duplicate")
 }
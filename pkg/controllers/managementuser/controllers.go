@@ -9,6 +9,7 @@
 	"github.com/rancher/rancher/pkg/controllers/managementlegacy/compose/common"
 	"github.com/rancher/rancher/pkg/controllers/managementuser/cavalidator"
 	"github.com/rancher/rancher/pkg/controllers/managementuser/clusterauthtoken"
+	"github.com/rancher/rancher/pkg/controllers/managementuser/drift"
 	"github.com/rancher/rancher/pkg/controllers/managementuser/healthsyncer"
 	"github.com/rancher/rancher/pkg/controllers/managementuser/machinerole"
 	"github.com/rancher/rancher/pkg/controllers/managementuser/networkpolicy"
@@ -35,6 +36,7 @@ func Register(ctx context.Context, mgmt *config.ScaledContext, cluster *config.U
 		return err
 	}
 	healthsyncer.Register(ctx, cluster)
+	drift.Register(ctx, cluster)
 	networkpolicy.Register(ctx, cluster)
 
 	secret.Register(ctx, mgmt, cluster, clusterRec)
@@ -87,11 +89,11 @@ func Register(ctx context.Context, mgmt *config.ScaledContext, cluster *config.U
 		if err != nil {
 			return err
 		}
-		secretsCache, err := clusterauthtoken.RegisterFactory(cluster)
+		secretsCache, configMaps, err := clusterauthtoken.RegisterFactory(cluster)
 		if err != nil {
 			return fmt.Errorf("registering clusterauthtoken factory: %w", err)
 		}
-		clusterauthtoken.Register(ctx, cluster, secretsCache)
+		clusterauthtoken.Register(ctx, cluster, secretsCache, configMaps)
 	}
 
 	return managementuserlegacy.Register(ctx, mgmt, cluster, clusterRec, kubeConfigGetter)
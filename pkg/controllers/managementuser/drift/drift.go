@@ -0,0 +1,627 @@
+// Package drift periodically checks the Rancher-managed resources of imported clusters for changes made in the
+// downstream cluster. The RBAC objects created for cluster and project role template bindings, and the scheduling
+// customization of the cluster agent, are compared with the state Rancher manages them in. The differences are
+// reported in the status of the cluster and recorded as provisioning events, and are restored if the
+// imported-cluster-drift-restore setting is enabled.
+package drift
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	util "github.com/rancher/rancher/pkg/cluster"
+	"github.com/rancher/rancher/pkg/clusterprovisioninglogger"
+	"github.com/rancher/rancher/pkg/controllers/managementuser/rbac"
+	"github.com/rancher/rancher/pkg/features"
+	mgmtcontrollers "github.com/rancher/rancher/pkg/generated/controllers/management.cattle.io/v3"
+	mgmtv3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
+	nsutils "github.com/rancher/rancher/pkg/namespace"
+	pkgrbac "github.com/rancher/rancher/pkg/rbac"
+	"github.com/rancher/rancher/pkg/settings"
+	"github.com/rancher/rancher/pkg/types/config"
+	corecontrollers "github.com/rancher/wrangler/v3/pkg/generated/controllers/core/v1"
+	rbaccontrollers "github.com/rancher/wrangler/v3/pkg/generated/controllers/rbac/v1"
+	"github.com/sirupsen/logrus"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	typedappsv1 "k8s.io/client-go/kubernetes/typed/apps/v1"
+	"k8s.io/client-go/util/retry"
+)
+
+const (
+	agentNamespace  = "cattle-system"
+	agentDeployment = "cattle-cluster-agent"
+	agentContainer  = "cluster-register"
+	// agentForceDeployAnn has the clusterdeploy controller redeploy the agent. The clusterdeploy package depends on
+	// the managementuser controllers, and can't be imported.
+	agentForceDeployAnn = "io.cattle.agent.force.deploy"
+
+	// the labels and annotations set by the rbac controllers of pkg/controllers/managementuser/rbac
+	rtbOwnerLabel    = "authz.cluster.cattle.io/rtb-owner-updated"
+	clusterRoleOwner = "authz.cluster.cattle.io/clusterrole-owner"
+
+	reasonMissing  = "Missing"
+	reasonModified = "Modified"
+
+	driftPhase = "DriftScan"
+
+	// disabledInterval is the interval the scan interval setting is checked at while the scan is disabled.
+	disabledInterval = time.Minute
+)
+
+// drift is a drifted object, and the function restoring it to its desired state.
+type drift struct {
+	object  v3.ClusterDriftedObject
+	restore func() error
+}
+
+type scanner struct {
+	ctx                 context.Context
+	clusterName         string
+	clusterLister       mgmtv3.ClusterLister
+	clusters            mgmtv3.ClusterInterface
+	crtbCache           mgmtcontrollers.ClusterRoleTemplateBindingCache
+	prtbCache           mgmtcontrollers.ProjectRoleTemplateBindingCache
+	rtCache             mgmtcontrollers.RoleTemplateCache
+	namespaceCache      corecontrollers.NamespaceCache
+	clusterRoles        rbaccontrollers.ClusterRoleController
+	clusterRoleBindings rbaccontrollers.ClusterRoleBindingController
+	roleBindings        rbaccontrollers.RoleBindingController
+	deployments         typedappsv1.DeploymentsGetter
+	events              *clusterprovisioninglogger.Recorder
+	now                 func() time.Time
+}
+
+// Register starts the periodic drift scan of the downstream cluster. The scan is skipped for clusters that are not
+// imported, since the resources of provisioned clusters are managed by their provisioning.
+func Register(ctx context.Context, workload *config.UserContext) {
+	s := &scanner{
+		ctx:                 ctx,
+		clusterName:         workload.ClusterName,
+		clusterLister:       workload.Management.Management.Clusters("").Controller().Lister(),
+		clusters:            workload.Management.Management.Clusters(""),
+		crtbCache:           workload.Management.Wrangler.Mgmt.ClusterRoleTemplateBinding().Cache(),
+		prtbCache:           workload.Management.Wrangler.Mgmt.ProjectRoleTemplateBinding().Cache(),
+		rtCache:             workload.Management.Wrangler.Mgmt.RoleTemplate().Cache(),
+		namespaceCache:      workload.Corew.Namespace().Cache(),
+		clusterRoles:        workload.RBACw.ClusterRole(),
+		clusterRoleBindings: workload.RBACw.ClusterRoleBinding(),
+		roleBindings:        workload.RBACw.RoleBinding(),
+		deployments:         workload.K8sClient.AppsV1(),
+		events:              clusterprovisioninglogger.NewRecorder(workload.Management.Wrangler.Mgmt.ProvisioningEvent()),
+		now:                 time.Now,
+	}
+
+	go s.run(ctx)
+}
+
+func (s *scanner) run(ctx context.Context) {
+	for {
+		interval := settings.ImportedClusterDriftScanInterval.GetDuration()
+		enabled := interval > 0
+		if !enabled {
+			interval = disabledInterval
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+		if !enabled {
+			continue
+		}
+		if err := s.scan(); err != nil {
+			logrus.Errorf("[drift] failed to scan cluster %s: %v", s.clusterName, err)
+		}
+	}
+}
+
+// isImported returns whether the cluster was imported, rather than provisioned by Rancher or a hosted provider.
+func isImported(cluster *v3.Cluster) bool {
+	if cluster.Name == "local" || cluster.Annotations["provisioning.cattle.io/administrated"] == "true" {
+		return false
+	}
+	switch cluster.Status.Driver {
+	case v3.ClusterDriverImported, v3.ClusterDriverK3s, v3.ClusterDriverRke2:
+		return true
+	}
+	return false
+}
+
+// scan compares the Rancher-managed resources of the downstream cluster with their desired state, restores them if
+// enabled, and reports the drifted objects.
+func (s *scanner) scan() error {
+	cluster, err := s.clusterLister.Get("", s.clusterName)
+	if err != nil {
+		return err
+	}
+	if !isImported(cluster) {
+		return nil
+	}
+
+	drifts, err := s.rbacDrift()
+	if err != nil {
+		return err
+	}
+	agentDrift, err := s.agentDrift(cluster)
+	if err != nil {
+		return err
+	}
+	drifts = append(drifts, agentDrift...)
+	sort.Slice(drifts, func(i, j int) bool {
+		return key(drifts[i].object) < key(drifts[j].object)
+	})
+
+	previous := map[string]v3.ClusterDriftedObject{}
+	if cluster.Status.Drift != nil {
+		for _, object := range cluster.Status.Drift.Objects {
+			previous[key(object)] = object
+		}
+	}
+
+	restore := settings.ImportedClusterDriftRestore.Get() == "true"
+	objects := make([]v3.ClusterDriftedObject, 0, len(drifts))
+	for _, d := range drifts {
+		object := d.object
+		if restore {
+			if err := d.restore(); err != nil {
+				logrus.Errorf("[drift] failed to restore %s of cluster %s: %v", describe(object), s.clusterName, err)
+			} else {
+				object.Restored = true
+			}
+		}
+		// drift still found in the downstream cluster was already recorded by a previous scan
+		if p, ok := previous[key(object)]; !ok || p.Restored || object.Restored {
+			s.record(object)
+		}
+		objects = append(objects, object)
+	}
+
+	return s.updateStatus(cluster, objects)
+}
+
+// record records a provisioning event for a drifted object.
+func (s *scanner) record(object v3.ClusterDriftedObject) {
+	spec := v3.ProvisioningEventSpec{
+		Phase:    driftPhase,
+		Severity: v3.ProvisioningEventSeverityWarning,
+		Source:   v3.ProvisioningEventSourceDrift,
+		Message:  fmt.Sprintf("%s is %s in the downstream cluster: %s", describe(object), strings.ToLower(object.Reason), object.Message),
+	}
+	if object.Restored {
+		spec.Severity = v3.ProvisioningEventSeverityInfo
+		spec.Message = fmt.Sprintf("Restored %s %s in the downstream cluster: %s", strings.ToLower(object.Reason), describe(object), object.Message)
+	}
+	if err := s.events.Record(s.clusterName, spec); err != nil {
+		logrus.Errorf("[drift] failed to record event for %s of cluster %s: %v", describe(object), s.clusterName, err)
+	}
+}
+
+func (s *scanner) updateStatus(cluster *v3.Cluster, objects []v3.ClusterDriftedObject) error {
+	if cluster.Status.Drift == nil && len(objects) == 0 {
+		return nil
+	}
+	if cluster.Status.Drift != nil && equality.Semantic.DeepEqual(cluster.Status.Drift.Objects, objects) {
+		return nil
+	}
+	if len(objects) == 0 {
+		objects = nil
+	}
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		cluster, err := s.clusters.Get(s.clusterName, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		cluster = cluster.DeepCopy()
+		cluster.Status.Drift = &v3.ClusterDriftStatus{
+			Objects:        objects,
+			LastUpdateTime: s.now().UTC().Format(time.RFC3339),
+		}
+		_, err = s.clusters.UpdateStatus(cluster)
+		return err
+	})
+}
+
+// rbacDrift returns the drifted ClusterRoles, ClusterRoleBindings and RoleBindings the rbac controllers create in the
+// downstream cluster for the role template bindings of the cluster. Aggregated role templates are reconciled by their
+// own controllers, and are not checked.
+func (s *scanner) rbacDrift() ([]drift, error) {
+	if features.AggregatedRoleTemplates.Enabled() {
+		return nil, nil
+	}
+
+	var drifts []drift
+	roles := map[string]*v3.RoleTemplate{}
+	// Bindings granting the same role to the same subject share a ClusterRoleBinding or RoleBinding.
+	crbs := map[string]*desiredBinding{}
+	rbs := map[string]*desiredBinding{}
+
+	crtbs, err := s.crtbCache.List(s.clusterName, labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	for _, crtb := range crtbs {
+		if crtb.DeletionTimestamp != nil || (crtb.UserName == "" && crtb.GroupPrincipalName == "" && crtb.GroupName == "") {
+			continue
+		}
+		bindingRoles, subject, ok := s.bindingRoles(crtb, crtb.RoleTemplateName)
+		if !ok {
+			continue
+		}
+		for name, rt := range bindingRoles {
+			roles[name] = rt
+			roleRef := rbacv1.RoleRef{Kind: "ClusterRole", Name: name}
+			addOwner(crbs, pkgrbac.NameForClusterRoleBinding(roleRef, subject), "", roleRef, subject, crtb.ObjectMeta)
+		}
+	}
+
+	prtbs, err := s.prtbCache.List("", labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	for _, prtb := range prtbs {
+		if !strings.HasPrefix(prtb.ProjectName, s.clusterName+":") || prtb.DeletionTimestamp != nil ||
+			(prtb.UserName == "" && prtb.GroupPrincipalName == "" && prtb.GroupName == "") {
+			continue
+		}
+		bindingRoles, subject, ok := s.bindingRoles(prtb, prtb.RoleTemplateName)
+		if !ok {
+			continue
+		}
+		namespaces, err := s.namespaceCache.GetByIndex(nsutils.NsByProjectIndex, prtb.ProjectName)
+		if err != nil {
+			return nil, err
+		}
+		for name, rt := range bindingRoles {
+			roles[name] = rt
+			roleRef := rbacv1.RoleRef{Kind: "ClusterRole", Name: name}
+			for _, ns := range namespaces {
+				if ns.DeletionTimestamp != nil {
+					continue
+				}
+				addOwner(rbs, pkgrbac.NameForRoleBinding(ns.Name, roleRef, subject), ns.Name, roleRef, subject, prtb.ObjectMeta)
+			}
+		}
+	}
+
+	for _, binding := range sortedBindings(crbs) {
+		d, err := s.clusterRoleBindingDrift(binding)
+		if err != nil {
+			return nil, err
+		}
+		drifts = append(drifts, d...)
+	}
+	for _, binding := range sortedBindings(rbs) {
+		d, err := s.roleBindingDrift(binding)
+		if err != nil {
+			return nil, err
+		}
+		drifts = append(drifts, d...)
+	}
+
+	for _, rt := range roles {
+		if rt.External {
+			continue
+		}
+		d, err := s.clusterRoleDrift(rt)
+		if err != nil {
+			return nil, err
+		}
+		drifts = append(drifts, d...)
+	}
+	return drifts, nil
+}
+
+// bindingRoles returns the role templates a binding grants, including the inherited ones, and the subject of the
+// binding. Bindings the rbac controllers skip are skipped too.
+func (s *scanner) bindingRoles(binding metav1.Object, roleTemplateName string) (map[string]*v3.RoleTemplate, rbacv1.Subject, bool) {
+	if roleTemplateName == "" {
+		return nil, rbacv1.Subject{}, false
+	}
+	subject, err := pkgrbac.BuildSubjectFromRTB(binding)
+	if err != nil {
+		logrus.Debugf("[drift] skipping binding %s/%s: %v", binding.GetNamespace(), binding.GetName(), err)
+		return nil, rbacv1.Subject{}, false
+	}
+	roles := map[string]*v3.RoleTemplate{}
+	if err := s.gatherRoles(roleTemplateName, roles); err != nil {
+		logrus.Debugf("[drift] skipping binding %s/%s: %v", binding.GetNamespace(), binding.GetName(), err)
+		return nil, rbacv1.Subject{}, false
+	}
+	return roles, subject, true
+}
+
+func (s *scanner) gatherRoles(name string, roles map[string]*v3.RoleTemplate) error {
+	if _, ok := roles[name]; ok {
+		return nil
+	}
+	rt, err := s.rtCache.Get(name)
+	if err != nil {
+		return fmt.Errorf("couldn't get role template %s: %w", name, err)
+	}
+	roles[name] = rt
+	for _, inherited := range rt.RoleTemplateNames {
+		if err := s.gatherRoles(inherited, roles); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *scanner) clusterRoleDrift(rt *v3.RoleTemplate) ([]drift, error) {
+	// the rbac controllers lowercase the resources and verbs of the rules
+	rts := map[string]*v3.RoleTemplate{rt.Name: rt}
+	rbac.ToLowerRoleTemplates(rts)
+	rules := rts[rt.Name].Rules
+
+	object := v3.ClusterDriftedObject{Kind: "ClusterRole", Name: rt.Name}
+	clusterRole, err := s.clusterRoles.Cache().Get(rt.Name)
+	if apierrors.IsNotFound(err) {
+		object.Reason = reasonMissing
+		object.Message = fmt.Sprintf("the cluster role of role template %s was deleted", rt.Name)
+		return []drift{{object: object, restore: func() error {
+			_, err := s.clusterRoles.Create(&rbacv1.ClusterRole{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        rt.Name,
+					Annotations: map[string]string{clusterRoleOwner: rt.Name},
+				},
+				Rules: rules,
+			})
+			return err
+		}}}, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if equality.Semantic.DeepEqual(clusterRole.Rules, rules) {
+		return nil, nil
+	}
+	object.Reason = reasonModified
+	object.Message = fmt.Sprintf("the rules differ from the rules of role template %s", rt.Name)
+	return []drift{{object: object, restore: func() error {
+		clusterRole := clusterRole.DeepCopy()
+		clusterRole.Rules = rules
+		_, err := s.clusterRoles.Update(clusterRole)
+		return err
+	}}}, nil
+}
+
+// desiredBinding is a ClusterRoleBinding or RoleBinding the rbac controllers create for the role template bindings
+// granting a role to a subject.
+type desiredBinding struct {
+	namespace string
+	name      string
+	roleRef   rbacv1.RoleRef
+	subject   rbacv1.Subject
+	// ownerLabels are the values of the owner label of the role template bindings, sorted.
+	ownerLabels []string
+	// owners are the namespaced names of the role template bindings, sorted.
+	owners []string
+}
+
+// addOwner adds a role template binding to the owners of the desired binding.
+func addOwner(bindings map[string]*desiredBinding, name, namespace string, roleRef rbacv1.RoleRef, subject rbacv1.Subject, owner metav1.ObjectMeta) {
+	key := namespace + "/" + name
+	binding, ok := bindings[key]
+	if !ok {
+		binding = &desiredBinding{namespace: namespace, name: name, roleRef: roleRef, subject: subject}
+		bindings[key] = binding
+	}
+	binding.ownerLabels = append(binding.ownerLabels, pkgrbac.GetRTBLabel(owner))
+	binding.owners = append(binding.owners, owner.Namespace+"/"+owner.Name)
+}
+
+// sortedBindings returns the desired bindings sorted by key, with their owners sorted.
+func sortedBindings(bindings map[string]*desiredBinding) []*desiredBinding {
+	keys := make([]string, 0, len(bindings))
+	for key := range bindings {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	sorted := make([]*desiredBinding, 0, len(keys))
+	for _, key := range keys {
+		binding := bindings[key]
+		sort.Strings(binding.ownerLabels)
+		sort.Strings(binding.owners)
+		sorted = append(sorted, binding)
+	}
+	return sorted
+}
+
+func (s *scanner) clusterRoleBindingDrift(binding *desiredBinding) ([]drift, error) {
+	object := v3.ClusterDriftedObject{Kind: "ClusterRoleBinding", Name: binding.name}
+	crb, err := s.clusterRoleBindings.Cache().Get(binding.name)
+	if apierrors.IsNotFound(err) {
+		object.Reason = reasonMissing
+		object.Message = fmt.Sprintf("the binding of cluster role %s created for %s was deleted", binding.roleRef.Name, strings.Join(binding.owners, ", "))
+		return []drift{{object: object, restore: func() error {
+			_, err := s.clusterRoleBindings.Create(&rbacv1.ClusterRoleBinding{
+				ObjectMeta: metav1.ObjectMeta{Name: binding.name, Labels: map[string]string{rtbOwnerLabel: binding.ownerLabels[0]}},
+				Subjects:   []rbacv1.Subject{binding.subject},
+				RoleRef:    binding.roleRef,
+			})
+			return err
+		}}}, nil
+	} else if err != nil {
+		return nil, err
+	}
+	message := bindingDifference(crb.Labels, crb.Subjects, binding)
+	if message == "" {
+		return nil, nil
+	}
+	object.Reason = reasonModified
+	object.Message = message
+	return []drift{{object: object, restore: func() error {
+		crb := crb.DeepCopy()
+		crb.Subjects = []rbacv1.Subject{binding.subject}
+		crb.Labels = restoreOwnerLabel(crb.Labels, binding)
+		_, err := s.clusterRoleBindings.Update(crb)
+		return err
+	}}}, nil
+}
+
+func (s *scanner) roleBindingDrift(binding *desiredBinding) ([]drift, error) {
+	object := v3.ClusterDriftedObject{Kind: "RoleBinding", Namespace: binding.namespace, Name: binding.name}
+	rb, err := s.roleBindings.Cache().Get(binding.namespace, binding.name)
+	if apierrors.IsNotFound(err) {
+		object.Reason = reasonMissing
+		object.Message = fmt.Sprintf("the binding of cluster role %s created for %s was deleted", binding.roleRef.Name, strings.Join(binding.owners, ", "))
+		return []drift{{object: object, restore: func() error {
+			_, err := s.roleBindings.Create(&rbacv1.RoleBinding{
+				ObjectMeta: metav1.ObjectMeta{Name: binding.name, Namespace: binding.namespace, Labels: map[string]string{rtbOwnerLabel: binding.ownerLabels[0]}},
+				Subjects:   []rbacv1.Subject{binding.subject},
+				RoleRef:    binding.roleRef,
+			})
+			return err
+		}}}, nil
+	} else if err != nil {
+		return nil, err
+	}
+	message := bindingDifference(rb.Labels, rb.Subjects, binding)
+	if message == "" {
+		return nil, nil
+	}
+	object.Reason = reasonModified
+	object.Message = message
+	return []drift{{object: object, restore: func() error {
+		rb := rb.DeepCopy()
+		rb.Subjects = []rbacv1.Subject{binding.subject}
+		rb.Labels = restoreOwnerLabel(rb.Labels, binding)
+		_, err := s.roleBindings.Update(rb)
+		return err
+	}}}, nil
+}
+
+// bindingDifference describes how the subjects and owner label of a binding differ from the desired ones, and returns
+// an empty string if they don't. The owner label holds one of the role template bindings sharing the binding.
+func bindingDifference(bindingLabels map[string]string, subjects []rbacv1.Subject, binding *desiredBinding) string {
+	if !reflect.DeepEqual(subjects, []rbacv1.Subject{binding.subject}) {
+		return fmt.Sprintf("the subjects differ from %s %s", binding.subject.Kind, binding.subject.Name)
+	}
+	if !slices.Contains(binding.ownerLabels, bindingLabels[rtbOwnerLabel]) {
+		return fmt.Sprintf("the %s label was changed", rtbOwnerLabel)
+	}
+	return ""
+}
+
+// restoreOwnerLabel sets the owner label of a binding to one of its role template bindings, unless it already holds one.
+func restoreOwnerLabel(bindingLabels map[string]string, binding *desiredBinding) map[string]string {
+	if bindingLabels == nil {
+		bindingLabels = map[string]string{}
+	}
+	if !slices.Contains(binding.ownerLabels, bindingLabels[rtbOwnerLabel]) {
+		bindingLabels[rtbOwnerLabel] = binding.ownerLabels[0]
+	}
+	return bindingLabels
+}
+
+// agentDrift returns the cluster agent deployment if it was deleted, or if its affinity, tolerations or resources
+// differ from the agent customization of the cluster. The agent is restored by redeploying it.
+func (s *scanner) agentDrift(cluster *v3.Cluster) ([]drift, error) {
+	object := v3.ClusterDriftedObject{Kind: "Deployment", Namespace: agentNamespace, Name: agentDeployment}
+	restore := func() error {
+		return s.redeployAgent()
+	}
+
+	deployment, err := s.deployments.Deployments(agentNamespace).Get(s.ctx, agentDeployment, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		object.Reason = reasonMissing
+		object.Message = "the cluster agent was deleted"
+		return []drift{{object: object, restore: restore}}, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	messages, err := agentDifferences(cluster, deployment)
+	if err != nil || len(messages) == 0 {
+		return nil, err
+	}
+	object.Reason = reasonModified
+	object.Message = strings.Join(messages, ", ")
+	return []drift{{object: object, restore: restore}}, nil
+}
+
+func agentDifferences(cluster *v3.Cluster, deployment *appsv1.Deployment) ([]string, error) {
+	var messages []string
+	podSpec := deployment.Spec.Template.Spec
+
+	affinity, err := util.GetClusterAgentAffinity(cluster)
+	if err != nil {
+		return nil, err
+	}
+	if !equality.Semantic.DeepEqual(podSpec.Affinity, affinity) {
+		messages = append(messages, "the affinity differs from the agent customization")
+	}
+
+	for _, toleration := range util.GetClusterAgentTolerations(cluster) {
+		if !hasToleration(podSpec.Tolerations, toleration) {
+			messages = append(messages, fmt.Sprintf("the toleration of %s is missing", tolerationKey(toleration)))
+		}
+	}
+
+	if resources := util.GetClusterAgentResourceRequirements(cluster); resources != nil {
+		for _, container := range podSpec.Containers {
+			if container.Name == agentContainer && !equality.Semantic.DeepEqual(container.Resources, *resources) {
+				messages = append(messages, "the resource requirements differ from the agent customization")
+			}
+		}
+	}
+	return messages, nil
+}
+
+func hasToleration(tolerations []corev1.Toleration, toleration corev1.Toleration) bool {
+	for _, t := range tolerations {
+		if equality.Semantic.DeepEqual(t, toleration) {
+			return true
+		}
+	}
+	return false
+}
+
+func tolerationKey(toleration corev1.Toleration) string {
+	if toleration.Key == "" {
+		return "all taints"
+	}
+	return "taint " + toleration.Key
+}
+
+// redeployAgent has the clusterdeploy controller redeploy the cluster agent.
+func (s *scanner) redeployAgent() error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		cluster, err := s.clusters.Get(s.clusterName, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		if cluster.Annotations[agentForceDeployAnn] == "true" {
+			return nil
+		}
+		cluster = cluster.DeepCopy()
+		if cluster.Annotations == nil {
+			cluster.Annotations = map[string]string{}
+		}
+		cluster.Annotations[agentForceDeployAnn] = "true"
+		_, err = s.clusters.Update(cluster)
+		return err
+	})
+}
+
+func key(object v3.ClusterDriftedObject) string {
+	return object.Kind + "/" + object.Namespace + "/" + object.Name
+}
+
+func describe(object v3.ClusterDriftedObject) string {
+	if object.Namespace == "" {
+		return object.Kind + " " + object.Name
+	}
+	return object.Kind + " " + object.Namespace + "/" + object.Name
+}
@@ -0,0 +1,301 @@
+package drift
+
+import (
+	"testing"
+	"time"
+
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/clusterprovisioninglogger"
+	"github.com/rancher/rancher/pkg/features"
+	"github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3/fakes"
+	pkgrbac "github.com/rancher/rancher/pkg/rbac"
+	"github.com/rancher/rancher/pkg/settings"
+	"github.com/rancher/wrangler/v3/pkg/generic/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+)
+
+var (
+	rules = []rbacv1.PolicyRule{{APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: []string{"get", "list"}}}
+
+	crtb = &v3.ClusterRoleTemplateBinding{
+		ObjectMeta:       metav1.ObjectMeta{Name: "crtb-1", Namespace: "c-abc"},
+		ClusterName:      "c-abc",
+		RoleTemplateName: "rt-1",
+		UserName:         "u-1",
+	}
+
+	subject = rbacv1.Subject{Kind: "User", APIGroup: rbacv1.GroupName, Name: "u-1"}
+
+	crbName = pkgrbac.NameForClusterRoleBinding(rbacv1.RoleRef{Kind: "ClusterRole", Name: "rt-1"}, subject)
+
+	toleration = corev1.Toleration{Key: "dedicated", Operator: corev1.TolerationOpEqual, Value: "rancher", Effect: corev1.TaintEffectNoSchedule}
+)
+
+func newCluster() *v3.Cluster {
+	return &v3.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "c-abc"},
+		Spec: v3.ClusterSpec{
+			ClusterSpecBase: v3.ClusterSpecBase{
+				ClusterAgentDeploymentCustomization: &v3.AgentDeploymentCustomization{
+					AppendTolerations: []corev1.Toleration{toleration},
+				},
+			},
+		},
+		Status: v3.ClusterStatus{Driver: v3.ClusterDriverImported},
+	}
+}
+
+func newScanner(t *testing.T, cluster *v3.Cluster, clusterRole *rbacv1.ClusterRole, crb *rbacv1.ClusterRoleBinding) (*scanner, *gomock.Controller) {
+	ctrl := gomock.NewController(t)
+
+	crtbCache := fake.NewMockCacheInterface[*v3.ClusterRoleTemplateBinding](ctrl)
+	crtbCache.EXPECT().List("c-abc", gomock.Any()).Return([]*v3.ClusterRoleTemplateBinding{crtb}, nil)
+	prtbCache := fake.NewMockCacheInterface[*v3.ProjectRoleTemplateBinding](ctrl)
+	prtbCache.EXPECT().List("", gomock.Any()).Return(nil, nil)
+	rtCache := fake.NewMockNonNamespacedCacheInterface[*v3.RoleTemplate](ctrl)
+	rtCache.EXPECT().Get("rt-1").Return(&v3.RoleTemplate{ObjectMeta: metav1.ObjectMeta{Name: "rt-1"}, Rules: rules}, nil).AnyTimes()
+
+	notFound := func(resource, name string) error {
+		return apierrors.NewNotFound(schema.GroupResource{Group: rbacv1.GroupName, Resource: resource}, name)
+	}
+	clusterRoleCache := fake.NewMockNonNamespacedCacheInterface[*rbacv1.ClusterRole](ctrl)
+	clusterRoleCache.EXPECT().Get("rt-1").DoAndReturn(func(name string) (*rbacv1.ClusterRole, error) {
+		if clusterRole == nil {
+			return nil, notFound("clusterroles", name)
+		}
+		return clusterRole, nil
+	})
+	clusterRoles := fake.NewMockNonNamespacedControllerInterface[*rbacv1.ClusterRole, *rbacv1.ClusterRoleList](ctrl)
+	clusterRoles.EXPECT().Cache().Return(clusterRoleCache).AnyTimes()
+	crbCache := fake.NewMockNonNamespacedCacheInterface[*rbacv1.ClusterRoleBinding](ctrl)
+	crbCache.EXPECT().Get(crbName).DoAndReturn(func(name string) (*rbacv1.ClusterRoleBinding, error) {
+		if crb == nil {
+			return nil, notFound("clusterrolebindings", name)
+		}
+		return crb, nil
+	})
+	crbs := fake.NewMockNonNamespacedControllerInterface[*rbacv1.ClusterRoleBinding, *rbacv1.ClusterRoleBindingList](ctrl)
+	crbs.EXPECT().Cache().Return(crbCache).AnyTimes()
+
+	events := fake.NewMockControllerInterface[*v3.ProvisioningEvent, *v3.ProvisioningEventList](ctrl)
+	eventCache := fake.NewMockCacheInterface[*v3.ProvisioningEvent](ctrl)
+	eventCache.EXPECT().List("c-abc", gomock.Any()).Return(nil, nil).AnyTimes()
+	events.EXPECT().Cache().Return(eventCache)
+
+	deployments := k8sfake.NewSimpleClientset(&appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: agentDeployment, Namespace: agentNamespace},
+		Spec: appsv1.DeploymentSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: agentContainer}},
+				},
+			},
+		},
+	})
+
+	return &scanner{
+		ctx:         t.Context(),
+		clusterName: "c-abc",
+		clusterLister: &fakes.ClusterListerMock{
+			GetFunc: func(namespace, name string) (*v3.Cluster, error) {
+				return cluster, nil
+			},
+		},
+		crtbCache:           crtbCache,
+		prtbCache:           prtbCache,
+		rtCache:             rtCache,
+		clusterRoles:        clusterRoles,
+		clusterRoleBindings: crbs,
+		deployments:         deployments.AppsV1(),
+		events:              clusterprovisioninglogger.NewRecorder(events),
+		now:                 time.Now,
+	}, ctrl
+}
+
+func setRestore(t *testing.T, restore string) {
+	require.NoError(t, settings.ImportedClusterDriftRestore.Set(restore))
+	t.Cleanup(func() {
+		_ = settings.ImportedClusterDriftRestore.Set(settings.ImportedClusterDriftRestore.Default)
+	})
+}
+
+func TestScan(t *testing.T) {
+	prev := features.AggregatedRoleTemplates.Enabled()
+	features.AggregatedRoleTemplates.Set(false)
+	t.Cleanup(func() { features.AggregatedRoleTemplates.Set(prev) })
+
+	modifiedRole := &rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: "rt-1"}, Rules: append(rules, rbacv1.PolicyRule{Verbs: []string{"*"}, Resources: []string{"*"}})}
+
+	t.Run("drift is reported", func(t *testing.T) {
+		setRestore(t, "false")
+		cluster := newCluster()
+		s, _ := newScanner(t, cluster, modifiedRole, nil)
+
+		var recorded []string
+		events := fake.NewMockControllerInterface[*v3.ProvisioningEvent, *v3.ProvisioningEventList](gomock.NewController(t))
+		events.EXPECT().Create(gomock.Any()).DoAndReturn(func(event *v3.ProvisioningEvent) (*v3.ProvisioningEvent, error) {
+			assert.Equal(t, v3.ProvisioningEventSourceDrift, event.Spec.Source)
+			assert.Equal(t, v3.ProvisioningEventSeverityWarning, event.Spec.Severity)
+			recorded = append(recorded, event.Spec.Message)
+			return event, nil
+		}).Times(3)
+		eventCache := fake.NewMockCacheInterface[*v3.ProvisioningEvent](gomock.NewController(t))
+		eventCache.EXPECT().List("c-abc", gomock.Any()).Return(nil, nil).AnyTimes()
+		events.EXPECT().Cache().Return(eventCache)
+		s.events = clusterprovisioninglogger.NewRecorder(events)
+
+		var updated *v3.Cluster
+		s.clusters = &fakes.ClusterInterfaceMock{
+			GetFunc: func(name string, opts metav1.GetOptions) (*v3.Cluster, error) {
+				return cluster, nil
+			},
+			UpdateStatusFunc: func(cluster *v3.Cluster) (*v3.Cluster, error) {
+				updated = cluster
+				return cluster, nil
+			},
+		}
+
+		require.NoError(t, s.scan())
+		require.NotNil(t, updated)
+		require.NotNil(t, updated.Status.Drift)
+		assert.NotEmpty(t, updated.Status.Drift.LastUpdateTime)
+		objects := updated.Status.Drift.Objects
+		require.Len(t, objects, 3)
+		assert.Equal(t, v3.ClusterDriftedObject{Kind: "ClusterRole", Name: "rt-1", Reason: reasonModified, Message: "the rules differ from the rules of role template rt-1"}, objects[0])
+		assert.Equal(t, v3.ClusterDriftedObject{Kind: "ClusterRoleBinding", Name: crbName, Reason: reasonMissing, Message: "the binding of cluster role rt-1 created for c-abc/crtb-1 was deleted"}, objects[1])
+		assert.Equal(t, "Deployment", objects[2].Kind)
+		assert.Equal(t, reasonModified, objects[2].Reason)
+		// the default affinity of the agent differs from the empty affinity of the deployment
+		assert.Equal(t, "the affinity differs from the agent customization, the toleration of taint dedicated is missing", objects[2].Message)
+		assert.Contains(t, recorded, "ClusterRole rt-1 is modified in the downstream cluster: the rules differ from the rules of role template rt-1")
+		assert.Empty(t, updated.Annotations[agentForceDeployAnn])
+
+		// drift already reported is not recorded again
+		cluster.Status.Drift = updated.Status.Drift
+		s2, _ := newScanner(t, cluster, modifiedRole, nil)
+		s2.clusters = &fakes.ClusterInterfaceMock{}
+		require.NoError(t, s2.scan(), "neither events nor the status are updated")
+	})
+
+	t.Run("drift is restored", func(t *testing.T) {
+		setRestore(t, "true")
+		cluster := newCluster()
+		s, ctrl := newScanner(t, cluster, modifiedRole, nil)
+
+		clusterRoles := s.clusterRoles.(*fake.MockNonNamespacedControllerInterface[*rbacv1.ClusterRole, *rbacv1.ClusterRoleList])
+		clusterRoles.EXPECT().Update(gomock.Any()).DoAndReturn(func(clusterRole *rbacv1.ClusterRole) (*rbacv1.ClusterRole, error) {
+			assert.Equal(t, rules, clusterRole.Rules)
+			return clusterRole, nil
+		})
+		crbs := s.clusterRoleBindings.(*fake.MockNonNamespacedControllerInterface[*rbacv1.ClusterRoleBinding, *rbacv1.ClusterRoleBindingList])
+		crbs.EXPECT().Create(gomock.Any()).DoAndReturn(func(crb *rbacv1.ClusterRoleBinding) (*rbacv1.ClusterRoleBinding, error) {
+			assert.Equal(t, crbName, crb.Name)
+			assert.Equal(t, []rbacv1.Subject{subject}, crb.Subjects)
+			assert.Equal(t, "rt-1", crb.RoleRef.Name)
+			assert.Equal(t, pkgrbac.GetRTBLabel(crtb.ObjectMeta), crb.Labels[rtbOwnerLabel])
+			return crb, nil
+		})
+
+		events := fake.NewMockControllerInterface[*v3.ProvisioningEvent, *v3.ProvisioningEventList](ctrl)
+		events.EXPECT().Create(gomock.Any()).DoAndReturn(func(event *v3.ProvisioningEvent) (*v3.ProvisioningEvent, error) {
+			assert.Equal(t, v3.ProvisioningEventSeverityInfo, event.Spec.Severity)
+			return event, nil
+		}).Times(3)
+		eventCache := fake.NewMockCacheInterface[*v3.ProvisioningEvent](ctrl)
+		eventCache.EXPECT().List("c-abc", gomock.Any()).Return(nil, nil).AnyTimes()
+		events.EXPECT().Cache().Return(eventCache)
+		s.events = clusterprovisioninglogger.NewRecorder(events)
+
+		current := cluster
+		s.clusters = &fakes.ClusterInterfaceMock{
+			GetFunc: func(name string, opts metav1.GetOptions) (*v3.Cluster, error) {
+				return current, nil
+			},
+			UpdateFunc: func(cluster *v3.Cluster) (*v3.Cluster, error) {
+				// like the status subresource, status changes made with Update are dropped
+				cluster = cluster.DeepCopy()
+				cluster.Status = current.Status
+				current = cluster
+				return cluster, nil
+			},
+			UpdateStatusFunc: func(cluster *v3.Cluster) (*v3.Cluster, error) {
+				current = cluster
+				return cluster, nil
+			},
+		}
+
+		require.NoError(t, s.scan())
+		assert.Equal(t, "true", current.Annotations[agentForceDeployAnn], "the agent is redeployed")
+		require.NotNil(t, current.Status.Drift)
+		require.Len(t, current.Status.Drift.Objects, 3)
+		for _, object := range current.Status.Drift.Objects {
+			assert.True(t, object.Restored, object.Kind)
+		}
+	})
+
+	t.Run("no drift", func(t *testing.T) {
+		setRestore(t, "true")
+		cluster := newCluster()
+		cluster.Spec.ClusterAgentDeploymentCustomization = &v3.AgentDeploymentCustomization{OverrideAffinity: &corev1.Affinity{}}
+		s, _ := newScanner(t, cluster, &rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: "rt-1"}, Rules: rules}, &rbacv1.ClusterRoleBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: crbName, Labels: map[string]string{rtbOwnerLabel: pkgrbac.GetRTBLabel(crtb.ObjectMeta)}},
+			Subjects:   []rbacv1.Subject{subject},
+		})
+		deployment, err := s.deployments.Deployments(agentNamespace).Get(t.Context(), agentDeployment, metav1.GetOptions{})
+		require.NoError(t, err)
+		deployment.Spec.Template.Spec.Affinity = &corev1.Affinity{}
+		_, err = s.deployments.Deployments(agentNamespace).Update(t.Context(), deployment, metav1.UpdateOptions{})
+		require.NoError(t, err)
+		s.clusters = &fakes.ClusterInterfaceMock{}
+
+		require.NoError(t, s.scan(), "the status is not updated")
+	})
+
+	t.Run("provisioned cluster", func(t *testing.T) {
+		cluster := newCluster()
+		cluster.Annotations = map[string]string{"provisioning.cattle.io/administrated": "true"}
+		s := &scanner{
+			clusterName: "c-abc",
+			clusterLister: &fakes.ClusterListerMock{
+				GetFunc: func(namespace, name string) (*v3.Cluster, error) {
+					return cluster, nil
+				},
+			},
+		}
+		require.NoError(t, s.scan())
+	})
+}
+
+func TestBindingDifference(t *testing.T) {
+	other := metav1.ObjectMeta{Name: "crtb-2", Namespace: "c-abc"}
+	crbs := map[string]*desiredBinding{}
+	roleRef := rbacv1.RoleRef{Kind: "ClusterRole", Name: "rt-1"}
+	addOwner(crbs, crbName, "", roleRef, subject, other)
+	addOwner(crbs, crbName, "", roleRef, subject, crtb.ObjectMeta)
+	bindings := sortedBindings(crbs)
+	require.Len(t, bindings, 1)
+	binding := bindings[0]
+	assert.Equal(t, []string{"c-abc/crtb-1", "c-abc/crtb-2"}, binding.owners)
+
+	// the binding is shared, and the owner label holds either of the role template bindings
+	for _, owner := range []metav1.ObjectMeta{crtb.ObjectMeta, other} {
+		bindingLabels := map[string]string{rtbOwnerLabel: pkgrbac.GetRTBLabel(owner)}
+		assert.Empty(t, bindingDifference(bindingLabels, []rbacv1.Subject{subject}, binding))
+		assert.Equal(t, bindingLabels, restoreOwnerLabel(bindingLabels, binding), "the owner label is kept")
+	}
+
+	bindingLabels := map[string]string{rtbOwnerLabel: "c-abc_crtb-3"}
+	assert.Equal(t, "the "+rtbOwnerLabel+" label was changed", bindingDifference(bindingLabels, []rbacv1.Subject{subject}, binding))
+	assert.Equal(t, pkgrbac.GetRTBLabel(crtb.ObjectMeta), restoreOwnerLabel(bindingLabels, binding)[rtbOwnerLabel])
+
+	assert.Equal(t, "the subjects differ from User u-1", bindingDifference(nil, nil, binding))
+}
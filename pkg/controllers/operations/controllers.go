@@ -6,6 +6,8 @@
 	"github.com/rancher/rancher/pkg/controllers/operations/encryptionkeyrotation"
 	"github.com/rancher/rancher/pkg/controllers/operations/etcdsnapshotrestore"
 	"github.com/rancher/rancher/pkg/controllers/operations/etcdsnapshotsave"
+	"github.com/rancher/rancher/pkg/controllers/operations/nodediagnostic"
+	"github.com/rancher/rancher/pkg/controllers/operations/supportbundle"
 	"github.com/rancher/rancher/pkg/wrangler"
 )
 
@@ -13,4 +15,6 @@ func Register(ctx context.Context, clients *wrangler.CAPIContext) {
 	encryptionkeyrotation.Register(ctx, clients)
 	etcdsnapshotsave.Register(ctx, clients)
 	etcdsnapshotrestore.Register(ctx, clients)
+	supportbundle.Register(ctx, clients)
+	nodediagnostic.Register(ctx, clients)
 }
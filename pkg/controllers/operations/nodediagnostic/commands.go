@@ -0,0 +1,131 @@
+package nodediagnostic
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	opv1alpha1 "github.com/rancher/rancher/pkg/apis/operation.cattle.io/v1alpha1"
+	"github.com/rancher/rancher/pkg/capr"
+	ops "github.com/rancher/rancher/pkg/operations"
+	"github.com/rancher/rancher/pkg/plan"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	defaultJournalSince = time.Hour
+	defaultJournalLines = 500
+
+	defaultMaxOutputBytes = 16 * 1024
+
+	// maxStatusOutputBytes is the combined size of the output of all results recorded in the status, which keeps the
+	// NodeDiagnostic well below the etcd request size limit of 1.5MiB.
+	maxStatusOutputBytes = 768 * 1024
+
+	truncatedMarker = "[truncated]\n"
+)
+
+// instructionName returns the name of the one-time instruction of the i-th command of the spec.
+func instructionName(i int) string {
+	return fmt.Sprintf("diagnostic-%d", i)
+}
+
+// appliesTo returns whether the command runs on the machine of the given machine-plan secret.
+func appliesTo(command opv1alpha1.NodeDiagnosticCommand, secret *corev1.Secret) bool {
+	if command.Name == opv1alpha1.NodeDiagnosticEtcdMemberList {
+		return ops.IsEtcd(secret)
+	}
+	return true
+}
+
+// renderInstructions renders the one-time instructions of the commands that apply to the machine of the given
+// machine-plan secret. The commands are rendered from the allow-list below; only validated numbers and enums taken
+// from the spec are interpolated. Each instruction captures stderr along with stdout and reports a non-zero exit code
+// on its last line instead of failing, so that one failing command does not prevent the others from being recorded.
+func renderInstructions(a ops.Adapter, secret *corev1.Secret, commands []opv1alpha1.NodeDiagnosticCommand) []plan.OneTimeInstruction {
+	var instructions []plan.OneTimeInstruction
+	for i, command := range commands {
+		if !appliesTo(command, secret) {
+			continue
+		}
+		script, env := renderCommand(a, secret, command)
+		instructions = append(instructions, plan.OneTimeInstruction{
+			CommonInstruction: plan.CommonInstruction{
+				Name:    instructionName(i),
+				Command: "/bin/sh",
+				Args:    []string{"-c", fmt.Sprintf(`{ %s; } 2>&1 || echo "exit status $?"`, script)},
+				Env:     env,
+			},
+			SaveOutput: true,
+		})
+	}
+	return instructions
+}
+
+// renderCommand returns the shell script and environment of a diagnostic command.
+func renderCommand(a ops.Adapter, secret *corev1.Secret, command opv1alpha1.NodeDiagnosticCommand) (string, []string) {
+	dataDir := a.DistroDataDirectory(secret)
+	runtime := a.RuntimeCommand()
+
+	switch command.Name {
+	case opv1alpha1.NodeDiagnosticContainerList:
+		if runtime == capr.RuntimeK3S {
+			return "k3s crictl ps -a", nil
+		}
+		return path.Join(dataDir, "bin", "crictl") + " ps -a", []string{"CRI_CONFIG_FILE=" + path.Join(dataDir, "agent", "etc", "crictl.yaml")}
+	case opv1alpha1.NodeDiagnosticServiceJournal:
+		since := defaultJournalSince
+		if command.Since != nil {
+			since = command.Since.Duration
+		}
+		lines := command.Lines
+		if lines == 0 {
+			lines = defaultJournalLines
+		}
+		return fmt.Sprintf("journalctl --no-pager -o short-iso -u %s --since -%ds -n %d", journalUnit(a, command.Unit), int64(since.Seconds()), lines), nil
+	case opv1alpha1.NodeDiagnosticDiskUsage:
+		return "df -hP && du -sh " + dataDir, nil
+	case opv1alpha1.NodeDiagnosticEtcdMemberList:
+		tlsDir := path.Join(dataDir, "server", "tls", "etcd")
+		return strings.Join([]string{
+			"curl -sS -X POST -d '{}'",
+			"--cacert", path.Join(tlsDir, "server-ca.crt"),
+			"--cert", path.Join(tlsDir, "server-client.crt"),
+			"--key", path.Join(tlsDir, "server-client.key"),
+			fmt.Sprintf("https://%s:2379/v3/cluster/member/list", a.LoopbackAddress(secret)),
+		}, " "), nil
+	}
+	// unreachable for objects admitted by the CRD schema
+	return fmt.Sprintf("echo 'unknown diagnostic command %s'; exit 1", strings.ReplaceAll(string(command.Name), "'", "")), nil
+}
+
+func journalUnit(a ops.Adapter, unit opv1alpha1.NodeDiagnosticUnit) string {
+	switch unit {
+	case opv1alpha1.NodeDiagnosticUnitSystemAgent:
+		return "rancher-system-agent"
+	case opv1alpha1.NodeDiagnosticUnitAgent:
+		return a.RuntimeCommand() + "-agent"
+	}
+	return a.ServerUnit()
+}
+
+// outputLimit returns the maximum size of the output recorded per result, which is the configured limit lowered so
+// that the given number of results fits into maxStatusOutputBytes.
+func outputLimit(maxOutputBytes, results int) int {
+	if maxOutputBytes == 0 {
+		maxOutputBytes = defaultMaxOutputBytes
+	}
+	if results > 0 && maxStatusOutputBytes/results < maxOutputBytes {
+		return maxStatusOutputBytes / results
+	}
+	return maxOutputBytes
+}
+
+// truncate keeps the most recent limit bytes of output.
+func truncate(output []byte, limit int) (string, bool) {
+	if len(output) <= limit {
+		return string(output), false
+	}
+	return truncatedMarker + string(output[len(output)-limit:]), true
+}
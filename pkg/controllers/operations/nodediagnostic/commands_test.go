@@ -0,0 +1,124 @@
+package nodediagnostic
+
+import (
+	"testing"
+	"time"
+
+	opv1alpha1 "github.com/rancher/rancher/pkg/apis/operation.cattle.io/v1alpha1"
+	"github.com/rancher/rancher/pkg/capr"
+	ops "github.com/rancher/rancher/pkg/operations"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// stubAdapter implements the adapter methods used to render diagnostic commands. The embedded
+// interface is nil, so any other method panics if called.
+type stubAdapter struct {
+	ops.Adapter
+	runtime    string
+	serverUnit string
+	dataDir    string
+}
+
+func (a *stubAdapter) RuntimeCommand() string                      { return a.runtime }
+func (a *stubAdapter) ServerUnit() string                          { return a.serverUnit }
+func (a *stubAdapter) DistroDataDirectory(_ *corev1.Secret) string { return a.dataDir }
+func (a *stubAdapter) LoopbackAddress(_ *corev1.Secret) string     { return "127.0.0.1" }
+
+var (
+	rke2Adapter = &stubAdapter{runtime: capr.RuntimeRKE2, serverUnit: "rke2-server", dataDir: "/var/lib/rancher/rke2"}
+	k3sAdapter  = &stubAdapter{runtime: capr.RuntimeK3S, serverUnit: "k3s", dataDir: "/var/lib/rancher/k3s"}
+)
+
+func planSecret(name string, roles ...string) *corev1.Secret {
+	labels := map[string]string{capr.MachineNameLabel: name}
+	for _, role := range roles {
+		labels[role] = "true"
+	}
+	return &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: name + "-machine-plan", Labels: labels}}
+}
+
+func TestRenderInstructions(t *testing.T) {
+	commands := []opv1alpha1.NodeDiagnosticCommand{
+		{Name: opv1alpha1.NodeDiagnosticContainerList},
+		{Name: opv1alpha1.NodeDiagnosticServiceJournal},
+		{Name: opv1alpha1.NodeDiagnosticServiceJournal, Unit: opv1alpha1.NodeDiagnosticUnitSystemAgent, Since: &metav1.Duration{Duration: 10 * time.Minute}, Lines: 50},
+		{Name: opv1alpha1.NodeDiagnosticDiskUsage},
+		{Name: opv1alpha1.NodeDiagnosticEtcdMemberList},
+	}
+
+	tests := []struct {
+		name    string
+		adapter ops.Adapter
+		secret  *corev1.Secret
+		want    map[string]string
+		wantEnv []string
+	}{
+		{
+			name:    "rke2 etcd node",
+			adapter: rke2Adapter,
+			secret:  planSecret("etcd-1", capr.EtcdRoleLabel),
+			want: map[string]string{
+				"diagnostic-0": `{ /var/lib/rancher/rke2/bin/crictl ps -a; } 2>&1 || echo "exit status $?"`,
+				"diagnostic-1": `{ journalctl --no-pager -o short-iso -u rke2-server --since -3600s -n 500; } 2>&1 || echo "exit status $?"`,
+				"diagnostic-2": `{ journalctl --no-pager -o short-iso -u rancher-system-agent --since -600s -n 50; } 2>&1 || echo "exit status $?"`,
+				"diagnostic-3": `{ df -hP && du -sh /var/lib/rancher/rke2; } 2>&1 || echo "exit status $?"`,
+				"diagnostic-4": `{ curl -sS -X POST -d '{}' --cacert /var/lib/rancher/rke2/server/tls/etcd/server-ca.crt ` +
+					`--cert /var/lib/rancher/rke2/server/tls/etcd/server-client.crt --key /var/lib/rancher/rke2/server/tls/etcd/server-client.key ` +
+					`https://127.0.0.1:2379/v3/cluster/member/list; } 2>&1 || echo "exit status $?"`,
+			},
+			wantEnv: []string{"CRI_CONFIG_FILE=/var/lib/rancher/rke2/agent/etc/crictl.yaml"},
+		},
+		{
+			name:    "k3s worker node",
+			adapter: k3sAdapter,
+			secret:  planSecret("worker-1", capr.WorkerRoleLabel),
+			want: map[string]string{
+				"diagnostic-0": `{ k3s crictl ps -a; } 2>&1 || echo "exit status $?"`,
+				"diagnostic-1": `{ journalctl --no-pager -o short-iso -u k3s --since -3600s -n 500; } 2>&1 || echo "exit status $?"`,
+				"diagnostic-2": `{ journalctl --no-pager -o short-iso -u rancher-system-agent --since -600s -n 50; } 2>&1 || echo "exit status $?"`,
+				"diagnostic-3": `{ df -hP && du -sh /var/lib/rancher/k3s; } 2>&1 || echo "exit status $?"`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			instructions := renderInstructions(tt.adapter, tt.secret, commands)
+			require.Len(t, instructions, len(tt.want))
+
+			for _, instruction := range instructions {
+				assert.Equal(t, "/bin/sh", instruction.Command)
+				assert.True(t, instruction.SaveOutput)
+				require.Len(t, instruction.Args, 2)
+				assert.Equal(t, tt.want[instruction.Name], instruction.Args[1], instruction.Name)
+			}
+			assert.Equal(t, tt.wantEnv, instructions[0].Env)
+		})
+	}
+}
+
+func TestJournalUnit(t *testing.T) {
+	assert.Equal(t, "rke2-server", journalUnit(rke2Adapter, ""))
+	assert.Equal(t, "rke2-agent", journalUnit(rke2Adapter, opv1alpha1.NodeDiagnosticUnitAgent))
+	assert.Equal(t, "k3s-agent", journalUnit(k3sAdapter, opv1alpha1.NodeDiagnosticUnitAgent))
+	assert.Equal(t, "rancher-system-agent", journalUnit(k3sAdapter, opv1alpha1.NodeDiagnosticUnitSystemAgent))
+}
+
+func TestOutputLimit(t *testing.T) {
+	assert.Equal(t, defaultMaxOutputBytes, outputLimit(0, 3))
+	assert.Equal(t, 4096, outputLimit(4096, 3))
+	assert.Equal(t, maxStatusOutputBytes/400, outputLimit(65536, 400))
+}
+
+func TestTruncate(t *testing.T) {
+	output, truncated := truncate([]byte("0123456789"), 20)
+	assert.Equal(t, "0123456789", output)
+	assert.False(t, truncated)
+
+	output, truncated = truncate([]byte("0123456789"), 4)
+	assert.Equal(t, truncatedMarker+"6789", output)
+	assert.True(t, truncated)
+}
@@ -0,0 +1,651 @@
+package nodediagnostic
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	opv1alpha1 "github.com/rancher/rancher/pkg/apis/operation.cattle.io/v1alpha1"
+	"github.com/rancher/rancher/pkg/capr"
+	operationcontrollers "github.com/rancher/rancher/pkg/generated/controllers/operation.cattle.io/v1alpha1"
+	ops "github.com/rancher/rancher/pkg/operations"
+	"github.com/rancher/rancher/pkg/plan"
+	planv1alpha1 "github.com/rancher/rancher/pkg/plan/api/plan.cattle.io/v1alpha1"
+	plancontrollers "github.com/rancher/rancher/pkg/plan/generated/controllers/plan.cattle.io/v1alpha1"
+	"github.com/rancher/rancher/pkg/wrangler"
+	"github.com/rancher/wrangler/v3/pkg/condition"
+	corecontrollers "github.com/rancher/wrangler/v3/pkg/generated/controllers/core/v1"
+	"github.com/rancher/wrangler/v3/pkg/generic"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	// ControllerOwnerKey is the value used to identify the node-diagnostic handler currently owns the beacon.
+	ControllerOwnerKey = "node-diagnostic"
+
+	// RunStepHookLabelPrefix gates the Run step before reconcileRun assigns the diagnostic plan to
+	// the selected machine-plan secrets. It follows the shared label semantics documented on
+	// planv1alpha1's phase-hook label constants.
+	RunStepHookLabelPrefix = "run.step.hook.operation.cattle.io/"
+)
+
+// stepHookPrefixFor returns the step-hook label prefix for the given node-diagnostic step, or ""
+// for an unknown / empty step.
+func stepHookPrefixFor(step opv1alpha1.NodeDiagnosticStep) string {
+	if step == opv1alpha1.NodeDiagnosticStepRun {
+		return RunStepHookLabelPrefix
+	}
+	return ""
+}
+
+// dynamicResolver is the subset of *dynamic.Controller this handler needs. It's an interface so
+// tests can substitute a stub — *dynamic.Controller satisfies it directly.
+type dynamicResolver interface {
+	Get(gvk schema.GroupVersionKind, namespace, name string) (runtime.Object, error)
+}
+
+// handler is the per-cluster reconciliation state for the NodeDiagnostic controller. All fields
+// are populated at Register time; the handler itself is stateless across reconciles.
+type handler struct {
+	nodediagnostics operationcontrollers.NodeDiagnosticController
+
+	beacons     plancontrollers.BeaconClient
+	beaconCache plancontrollers.BeaconCache
+
+	secrets corecontrollers.SecretClient
+
+	store *plan.Store
+
+	dynamic dynamicResolver
+
+	clients *wrangler.CAPIContext
+}
+
+// Register wires the NodeDiagnostic controller into the given wrangler context. It must be
+// called exactly once per process; subsequent calls would clobber the registered status handler.
+func Register(ctx context.Context, clients *wrangler.CAPIContext) {
+	h := &handler{
+		nodediagnostics: clients.Operation.NodeDiagnostic(),
+		beacons:         clients.Plan.Beacon(),
+		beaconCache:     clients.Plan.Beacon().Cache(),
+		secrets:         clients.Core.Secret(),
+		dynamic:         clients.Dynamic,
+		store:           plan.NewStore(clients.Core.Secret()),
+		clients:         clients,
+	}
+
+	operationcontrollers.RegisterNodeDiagnosticStatusHandler(ctx, clients.Operation.NodeDiagnostic(), "", "node-diagnostic-handler", h.OnChange)
+}
+
+// OnChange is the status handler entrypoint invoked by the wrangler-registered controller. It
+// delegates the phase-specific work to onChange, then runs the common condition refresh through
+// updateStatus. When the status did not change, the operation is either deleted once expired or
+// re-enqueued to poll the plan secrets.
+func (h *handler) OnChange(op *opv1alpha1.NodeDiagnostic, status opv1alpha1.NodeDiagnosticStatus) (opv1alpha1.NodeDiagnosticStatus, error) {
+	status, err := h.onChange(op, status)
+	if err != nil {
+		return status, err
+	}
+	status = updateStatus(op, status)
+
+	if equality.Semantic.DeepEqual(op.Status, status) {
+		if ops.IsTerminal(status.Phase) &&
+			ops.IsExpired(&op.Spec.OperationSpec, &status.OperationStatus) &&
+			!planv1alpha1.HasActiveLifecycleHook(op) {
+			err = h.nodediagnostics.Delete(op.Namespace, op.Name, &metav1.DeleteOptions{})
+			if err != nil {
+				return status, err
+			}
+			return status, generic.ErrSkip
+		}
+
+		h.nodediagnostics.EnqueueAfter(op.Namespace, op.Name, 5*time.Second)
+	}
+	return status, nil
+}
+
+// scope bundles the per-reconcile values derived from the operation, parent cluster, and beacon.
+type scope struct {
+	ownerKey string
+
+	op        *opv1alpha1.NodeDiagnostic
+	namespace string
+
+	beacon     *planv1alpha1.Beacon
+	clusterObj *unstructured.Unstructured
+	adapter    ops.Adapter
+}
+
+// onChange resolves the parent cluster reference, locates the cluster's beacon, builds an Adapter
+// for the cluster kind, and dispatches to the phase-specific handler.
+func (h *handler) onChange(op *opv1alpha1.NodeDiagnostic, status opv1alpha1.NodeDiagnosticStatus) (opv1alpha1.NodeDiagnosticStatus, error) {
+	if op == nil {
+		return status, nil
+	}
+
+	if op.DeletionTimestamp != nil {
+		return status, nil
+	}
+
+	if ops.IsPaused(&op.Spec.OperationSpec) {
+		logrus.Debugf("[nodediagnostic] %s/%s: skipping paused operation", op.Namespace, op.Name)
+		return status, nil
+	}
+
+	if status.Phase == "" {
+		status.SetPhase(opv1alpha1.OperationPhasePending)
+	}
+
+	gvk := schema.FromAPIVersionAndKind(op.Spec.ClusterRef.APIVersion, op.Spec.ClusterRef.Kind)
+	ref, err := h.dynamic.Get(gvk, op.Spec.ClusterRef.Namespace, op.Spec.ClusterRef.Name)
+	if apierrors.IsNotFound(err) {
+		key := fmt.Sprintf("apiVersion=%s, kind=%s", op.Spec.ClusterRef.APIVersion, op.Spec.ClusterRef.Kind)
+		if op.Spec.ClusterRef.Namespace != "" {
+			key += fmt.Sprintf(", namespace=%s", op.Spec.ClusterRef.Namespace)
+		}
+		key += fmt.Sprintf(", name=%s", op.Spec.ClusterRef.Name)
+		logrus.Errorf("[nodediagnostic]: %s/%s failed to find cluster for %s", op.Namespace, op.Name, key)
+
+		opv1alpha1.FailedCondition.True(&status)
+		opv1alpha1.FailedCondition.Reason(&status, opv1alpha1.ClusterNotFoundReason)
+		opv1alpha1.FailedCondition.Message(&status, fmt.Sprintf("cluster %s not found", key))
+
+		status.SetPhase(opv1alpha1.OperationPhaseFailed)
+		return status, nil
+	}
+	if err != nil {
+		return status, err
+	}
+
+	ustrMap, err := runtime.DefaultUnstructuredConverter.ToUnstructured(ref)
+	if err != nil {
+		return status, err
+	}
+
+	ustr := unstructured.Unstructured{Object: ustrMap}
+
+	a, err := ops.NewAdapter(h.clients, &ustr)
+	if err != nil {
+		return status, err
+	}
+
+	clusterObj, err := a.ClusterObject()
+	if err != nil {
+		return status, err
+	}
+
+	namespace, beaconName := a.BeaconRef()
+
+	beacon, err := h.beacons.Get(namespace, beaconName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) && status.Phase == opv1alpha1.OperationPhasePending {
+		logrus.Warnf("[nodediagnostic]: %s/%s failed to find beacon %s/%s (clusterRef apiVersion=%s kind=%s name=%s)",
+			op.Namespace, op.Name, namespace, beaconName, ustr.GetAPIVersion(), ustr.GetKind(), ustr.GetName())
+
+		opv1alpha1.PendingCondition.True(&status)
+		opv1alpha1.PendingCondition.Reason(&status, opv1alpha1.WaitingForBeaconReason)
+		opv1alpha1.PendingCondition.Message(&status, "waiting for beacon creation")
+
+		return status, nil
+	} else if err != nil {
+		return status, err
+	}
+
+	s := &scope{
+		ownerKey:   plan.ControllerOwnerKey(op, ControllerOwnerKey),
+		op:         op,
+		beacon:     beacon,
+		namespace:  namespace,
+		clusterObj: clusterObj,
+		adapter:    a,
+	}
+
+	switch status.Phase {
+	case opv1alpha1.OperationPhasePending:
+		return h.handlePending(s, status)
+	case opv1alpha1.OperationPhaseInProgress:
+		return h.handleInProgress(s, status)
+	case opv1alpha1.OperationPhaseCanceled:
+		return h.handleTerminal(s, status, opv1alpha1.CanceledCondition, planv1alpha1.CanceledPhaseHookLabelPrefix)
+	case opv1alpha1.OperationPhaseFailed:
+		return h.handleTerminal(s, status, opv1alpha1.FailedCondition, planv1alpha1.FailedPhaseHookLabelPrefix)
+	case opv1alpha1.OperationPhaseSucceeded:
+		return h.handleTerminal(s, status, opv1alpha1.SucceededCondition, planv1alpha1.SucceededPhaseHookLabelPrefix)
+	}
+
+	status.SetPhase(opv1alpha1.OperationPhaseFailed)
+
+	opv1alpha1.FailedCondition.True(&status)
+	opv1alpha1.FailedCondition.Reason(&status, opv1alpha1.UnknownPhaseReason)
+	opv1alpha1.FailedCondition.Message(&status, fmt.Sprintf("unknown phase [%s]", op.Status.Phase))
+
+	return status, nil
+}
+
+func (h *handler) lifecycleHookDelegate(s *scope, prefix string) (string, string) {
+	for k, v := range s.op.Labels {
+		if strings.HasPrefix(k, prefix) {
+			return strings.TrimPrefix(k, prefix), v
+		}
+	}
+
+	return "", ""
+}
+
+func (h *handler) delegate(s *scope, name, delegate string) error {
+	logrus.Tracef("[nodediagnostic] %s/%s: delegating ownership of beacon to %s on behalf of %s", s.op.Namespace, s.op.Name, delegate, name)
+
+	if plan.IsInDelegateChain(s.beacon, delegate) {
+		return nil
+	}
+
+	beacon, err := plan.PushDelegate(s.beacon, delegate, h.beacons)
+	if err != nil {
+		return err
+	}
+
+	s.beacon = beacon
+
+	return nil
+}
+
+func (h *handler) handleHook(s *scope, prefix string) (bool, error) {
+	if name, delegate := h.lifecycleHookDelegate(s, prefix); delegate != "" {
+		err := h.delegate(s, name, delegate)
+		return true, err
+	}
+
+	return false, nil
+}
+
+// handlePending acquires the cluster's beacon and waits for every expected system-agent to
+// register a machine-plan secret, then transitions the operation to InProgress at the Run step.
+func (h *handler) handlePending(s *scope, status opv1alpha1.NodeDiagnosticStatus) (opv1alpha1.NodeDiagnosticStatus, error) {
+	logrus.Tracef("[nodediagnostic] %s/%s: handling pending", s.op.Namespace, s.op.Name)
+
+	if !plan.IsInDelegateChain(s.beacon, s.ownerKey) {
+		acquired, err := plan.AcquireBeacon(s.beacon, h.beacons, s.ownerKey)
+		if err != nil {
+			return status, err
+		}
+		if acquired == nil {
+			opv1alpha1.PendingCondition.True(&status)
+			opv1alpha1.PendingCondition.Reason(&status, opv1alpha1.WaitingForBeaconReason)
+			opv1alpha1.PendingCondition.Message(&status, "waiting for beacon creation")
+			return status, nil
+		}
+		s.beacon = acquired
+	}
+
+	delegated, err := h.handleHook(s, planv1alpha1.PendingPhaseHookLabelPrefix)
+	if err != nil {
+		return status, err
+	} else if delegated {
+		opv1alpha1.PendingCondition.True(&status)
+		opv1alpha1.PendingCondition.Reason(&status, opv1alpha1.WaitingForDelegateReason)
+		opv1alpha1.PendingCondition.Message(&status, fmt.Sprintf("Waiting for delegates to finish: %v", opv1alpha1.WaitingForDelegateMessage(s.beacon)))
+		return status, nil
+	}
+
+	if ok, err := s.adapter.WaitForRegister(); err != nil {
+		return status, err
+	} else if !ok {
+		logrus.Infof("[nodediagnostic] %s/%s: waiting for system-agents to connect", s.op.Namespace, s.op.Name)
+		opv1alpha1.PendingCondition.True(&status)
+		opv1alpha1.PendingCondition.Reason(&status, opv1alpha1.WaitingForRegistrationReason)
+		opv1alpha1.PendingCondition.Message(&status, "waiting for system-agents to connect")
+		return status, nil
+	}
+
+	logrus.Infof("[nodediagnostic] %s/%s: transitioning to run", s.op.Namespace, s.op.Name)
+
+	status.SetPhase(opv1alpha1.OperationPhaseInProgress)
+	status.SetStep(opv1alpha1.NodeDiagnosticStepRun)
+
+	opv1alpha1.InProgressCondition.True(&status)
+	opv1alpha1.InProgressCondition.Reason(&status, opv1alpha1.InProgressReason)
+	return status, nil
+}
+
+// handleInProgress re-verifies beacon ownership, marks the beacon active so the system-agent will
+// keep polling, and then dispatches to the step-specific reconciler.
+func (h *handler) handleInProgress(s *scope, status opv1alpha1.NodeDiagnosticStatus) (opv1alpha1.NodeDiagnosticStatus, error) {
+	logrus.Tracef("[nodediagnostic] %s/%s: handling in-progress", s.op.Namespace, s.op.Name)
+
+	stepPrefix := stepHookPrefixFor(s.op.Status.Step)
+
+	if !plan.IsOwningBeaconHolder(s.beacon, s.ownerKey) && !plan.IsInDelegateChain(s.beacon, s.ownerKey) {
+		if planv1alpha1.HasStepHookLabel(s.op, stepPrefix) {
+			return waitingForDelegate(s, status), nil
+		}
+		logrus.Errorf("[nodediagnostic] %s/%s: beacon reassigned, aborting", s.op.Namespace, s.op.Name)
+		status.SetPhase(opv1alpha1.OperationPhaseFailed)
+
+		opv1alpha1.FailedCondition.True(&status)
+		opv1alpha1.FailedCondition.Reason(&status, opv1alpha1.BeaconLostReason)
+		opv1alpha1.FailedCondition.Message(&status, "beacon reassigned, aborting")
+
+		return status, nil
+	}
+
+	var err error
+	s.beacon, err = plan.ToggleBeacon(s.beacon, true, h.beacons)
+	if err != nil {
+		return status, err
+	}
+
+	delegated, err := h.handleHook(s, planv1alpha1.InProgressPhaseHookLabelPrefix)
+	if err != nil {
+		return status, err
+	} else if delegated {
+		return waitingForDelegate(s, status), nil
+	}
+
+	if !plan.AuthorizedForBeacon(s.beacon, s.ownerKey) {
+		if planv1alpha1.HasStepHookLabel(s.op, stepPrefix) {
+			return waitingForDelegate(s, status), nil
+		}
+		logrus.Errorf("[nodediagnostic] %s/%s: beacon lost, aborting", s.op.Namespace, s.op.Name)
+		status.SetPhase(opv1alpha1.OperationPhaseFailed)
+
+		opv1alpha1.FailedCondition.True(&status)
+		opv1alpha1.FailedCondition.Reason(&status, opv1alpha1.BeaconLostReason)
+		opv1alpha1.FailedCondition.Message(&status, "Beacon acquired by another controller, aborting")
+
+		return status, nil
+	}
+
+	switch s.op.Status.Step {
+	case opv1alpha1.NodeDiagnosticStepRun:
+		return h.reconcileRun(s, status)
+	default:
+		status.SetPhase(opv1alpha1.OperationPhaseFailed)
+
+		opv1alpha1.FailedCondition.True(&status)
+		opv1alpha1.FailedCondition.Reason(&status, opv1alpha1.UnknownStepReason)
+		opv1alpha1.FailedCondition.Message(&status, fmt.Sprintf(
+			"current step [\"%s\"] is unknown, expected one of: [\"%s\"]",
+			status.Step,
+			opv1alpha1.NodeDiagnosticStepRun))
+	}
+
+	return status, nil
+}
+
+func waitingForDelegate(s *scope, status opv1alpha1.NodeDiagnosticStatus) opv1alpha1.NodeDiagnosticStatus {
+	opv1alpha1.InProgressCondition.True(&status)
+	opv1alpha1.InProgressCondition.Reason(&status, opv1alpha1.WaitingForDelegateReason)
+	opv1alpha1.InProgressCondition.Message(&status, fmt.Sprintf("Waiting for delegates to finish: %v", opv1alpha1.WaitingForDelegateMessage(s.beacon)))
+	return status
+}
+
+// handleTerminal runs the phase hook of a terminal phase and then releases the beacon, so the
+// next operation in line can acquire it.
+func (h *handler) handleTerminal(s *scope, status opv1alpha1.NodeDiagnosticStatus, cond condition.Cond, prefix string) (opv1alpha1.NodeDiagnosticStatus, error) {
+	logrus.Tracef("[nodediagnostic] %s/%s: handling operation %s", s.op.Namespace, s.op.Name, status.Phase)
+
+	delegated, err := h.handleHook(s, prefix)
+	if err != nil {
+		return status, err
+	} else if delegated {
+		cond.True(&status)
+		cond.Reason(&status, opv1alpha1.WaitingForDelegateReason)
+		cond.Message(&status, fmt.Sprintf("Waiting for delegates to finish: %v", opv1alpha1.WaitingForDelegateMessage(s.beacon)))
+		return status, nil
+	}
+
+	if plan.IsOwningBeaconHolder(s.beacon, s.ownerKey) || plan.IsInDelegateChain(s.beacon, s.ownerKey) {
+		if err := plan.ReleaseBeacon(s.beacon, h.beacons, s.ownerKey); err != nil {
+			return status, err
+		}
+	}
+
+	return status, nil
+}
+
+// updateStatus updates the conditions of the operation based on the current status.
+// This function also updates the ObservedGeneration.
+func updateStatus(op *opv1alpha1.NodeDiagnostic, status opv1alpha1.NodeDiagnosticStatus) opv1alpha1.NodeDiagnosticStatus {
+	status.ObservedGeneration = op.Generation
+	if op.Spec.Paused {
+		opv1alpha1.PausedCondition.True(&status)
+		opv1alpha1.PausedCondition.Reason(&status, opv1alpha1.PausedReason)
+		opv1alpha1.PausedCondition.Message(&status, "Operation is paused")
+	} else {
+		opv1alpha1.PausedCondition.False(&status)
+		opv1alpha1.PausedCondition.Reason(&status, opv1alpha1.NotPausedReason)
+		opv1alpha1.PausedCondition.Message(&status, "")
+	}
+
+	if status.Phase == opv1alpha1.OperationPhasePending {
+		opv1alpha1.PendingCondition.True(&status)
+	} else if status.Phase == opv1alpha1.OperationPhaseInProgress {
+		opv1alpha1.PendingCondition.False(&status)
+		opv1alpha1.PendingCondition.Reason(&status, opv1alpha1.InProgressReason)
+		opv1alpha1.PendingCondition.Message(&status, "Operation now in progress")
+	} else if status.Phase == opv1alpha1.OperationPhaseSucceeded {
+		opv1alpha1.PendingCondition.False(&status)
+		opv1alpha1.PendingCondition.Reason(&status, opv1alpha1.FinishedReason)
+		opv1alpha1.PendingCondition.Message(&status, "Operation completed successfully")
+		opv1alpha1.InProgressCondition.False(&status)
+		opv1alpha1.InProgressCondition.Reason(&status, opv1alpha1.FinishedReason)
+		opv1alpha1.InProgressCondition.Message(&status, "Operation completed successfully")
+		opv1alpha1.FailedCondition.False(&status)
+		opv1alpha1.FailedCondition.Reason(&status, opv1alpha1.NotFailedReason)
+		opv1alpha1.FailedCondition.Message(&status, "Operation completed successfully")
+	} else if status.Phase == opv1alpha1.OperationPhaseFailed {
+		opv1alpha1.PendingCondition.False(&status)
+		opv1alpha1.PendingCondition.Reason(&status, opv1alpha1.FinishedReason)
+		opv1alpha1.PendingCondition.Message(&status, "Operation failed")
+		opv1alpha1.InProgressCondition.False(&status)
+		opv1alpha1.InProgressCondition.Reason(&status, opv1alpha1.FinishedReason)
+		opv1alpha1.InProgressCondition.Message(&status, "Operation failed")
+		opv1alpha1.SucceededCondition.False(&status)
+		opv1alpha1.SucceededCondition.Reason(&status, opv1alpha1.NotSuccessfulReason)
+		opv1alpha1.SucceededCondition.Message(&status, "Operation failed")
+	}
+
+	return status
+}
+
+// machineName returns the name of the machine of a machine-plan secret.
+func machineName(secret *corev1.Secret) string {
+	if name := secret.Labels[capr.MachineNameLabel]; name != "" {
+		return name
+	}
+	return secret.Name
+}
+
+// hasRole returns whether the machine of a machine-plan secret has the given role.
+func hasRole(secret *corev1.Secret, role opv1alpha1.NodeDiagnosticRole) bool {
+	switch role {
+	case opv1alpha1.NodeDiagnosticRoleEtcd:
+		return ops.IsEtcd(secret)
+	case opv1alpha1.NodeDiagnosticRoleControlPlane:
+		return ops.IsControlPlane(secret)
+	case opv1alpha1.NodeDiagnosticRoleWorker:
+		return secret.Labels[capr.WorkerRoleLabel] == "true"
+	}
+	return false
+}
+
+// selectMachines returns the machine-plan secrets of the machines selected by the spec.
+func selectMachines(secrets []*corev1.Secret, spec opv1alpha1.NodeDiagnosticSpec) []*corev1.Secret {
+	var selected []*corev1.Secret
+	for _, secret := range secrets {
+		if len(spec.MachineNames) > 0 && !slices.Contains(spec.MachineNames, machineName(secret)) {
+			continue
+		}
+		if len(spec.Roles) > 0 && !slices.ContainsFunc(spec.Roles, func(role opv1alpha1.NodeDiagnosticRole) bool {
+			return hasRole(secret, role)
+		}) {
+			continue
+		}
+		selected = append(selected, secret)
+	}
+	return selected
+}
+
+// reconcileRun assigns the diagnostic plan to every selected machine at once, as the commands are
+// read-only. The plan only consists of one-time instructions, so the desired plan of the cluster
+// is restored by the planner once the beacon is released. Once every machine has applied the
+// plan, the captured output is recorded in the status and the operation is marked Succeeded.
+func (h *handler) reconcileRun(s *scope, status opv1alpha1.NodeDiagnosticStatus) (opv1alpha1.NodeDiagnosticStatus, error) {
+	logrus.Debugf("[nodediagnostic] %s/%s: handling run", s.op.Namespace, s.op.Name)
+
+	delegated, err := h.handleHook(s, RunStepHookLabelPrefix)
+	if err != nil {
+		return status, err
+	} else if delegated {
+		return waitingForDelegate(s, status), nil
+	}
+
+	secrets, err := plan.NewCollector(h.secrets, s.clusterObj, s.namespace).
+		WithSorter(plan.DefaultSorter()).
+		WithFilter(plan.FilterFunc(ops.Not(ops.IsWindows))).
+		WithValidator(plan.AtLeast(1, "")).
+		Collect()
+	if plan.IsTransient(err) {
+		return status, err
+	} else if err != nil {
+		logrus.Errorf("[nodediagnostic] %s/%s: marking operation as failed: encountered terminal error collecting machine-plan secrets: %v", s.op.Namespace, s.op.Name, err)
+
+		status.SetPhase(opv1alpha1.OperationPhaseFailed)
+
+		opv1alpha1.FailedCondition.True(&status)
+		opv1alpha1.FailedCondition.Reason(&status, opv1alpha1.PlanFailedReason)
+		opv1alpha1.FailedCondition.Message(&status, fmt.Sprintf("encountered terminal error collecting machine-plan secrets: %v", err))
+		return status, nil
+	}
+
+	secrets = selectMachines(secrets, s.op.Spec)
+	if len(secrets) == 0 {
+		status.SetPhase(opv1alpha1.OperationPhaseFailed)
+
+		opv1alpha1.FailedCondition.True(&status)
+		opv1alpha1.FailedCondition.Reason(&status, opv1alpha1.NoMachinesSelectedReason)
+		opv1alpha1.FailedCondition.Message(&status, "no machine matches the selected machine names and roles")
+		return status, nil
+	}
+
+	results := make([]plan.PlanStatus, 0, len(secrets))
+	failed := map[string]bool{}
+	completed := 0
+	for _, secret := range secrets {
+		nodePlan := &plan.Plan{
+			OneTimeInstructions: renderInstructions(s.adapter, secret, s.op.Spec.Commands),
+		}
+		if len(nodePlan.OneTimeInstructions) == 0 {
+			completed++
+			continue
+		}
+
+		planStatus, err := h.store.AssignPlan(secret, nodePlan, 1, -1)
+		if err != nil {
+			return status, err
+		}
+
+		results = append(results, *planStatus)
+
+		if planStatus.Waiting() {
+			logrus.Debugf("[nodediagnostic] %s/%s: waiting for diagnostics for %s/%s", s.op.Namespace, s.op.Name, secret.Namespace, secret.Name)
+			continue
+		}
+		if planStatus.Failure() {
+			logrus.Warnf("[nodediagnostic] %s/%s: diagnostics failed to apply for %s/%s", s.op.Namespace, s.op.Name, secret.Namespace, secret.Name)
+			failed[secret.Name] = true
+		}
+		completed++
+	}
+
+	status.MachinesTotal = len(secrets)
+	status.MachinesCompleted = completed
+
+	if completed < len(secrets) {
+		msg := plan.Message(results)
+		opv1alpha1.InProgressCondition.True(&status)
+		opv1alpha1.InProgressCondition.Reason(&status, opv1alpha1.WaitingForPlanAppliedReason)
+		opv1alpha1.InProgressCondition.Message(&status, fmt.Sprintf("Waiting in step %s: %s", status.Step, msg))
+
+		return status, nil
+	}
+
+	diagnosticResults, ready, err := collectResults(secrets, failed, s.op.Spec)
+	if err != nil {
+		return status, err
+	}
+	if !ready {
+		// Output not yet in cache; wait for the next reconcile.
+		opv1alpha1.InProgressCondition.True(&status)
+		opv1alpha1.InProgressCondition.Reason(&status, opv1alpha1.WaitingForPlanAppliedReason)
+		opv1alpha1.InProgressCondition.Message(&status, "waiting for diagnostic output")
+		return status, nil
+	}
+	status.Results = diagnosticResults
+
+	logrus.Infof("[nodediagnostic] %s/%s: marking as success", s.op.Namespace, s.op.Name)
+
+	status.SetPhase(opv1alpha1.OperationPhaseSucceeded)
+
+	opv1alpha1.SucceededCondition.True(&status)
+	opv1alpha1.SucceededCondition.Reason(&status, opv1alpha1.FinishedReason)
+	opv1alpha1.SucceededCondition.Message(&status, "Operation completed successfully")
+
+	return status, nil
+}
+
+// collectResults reads the captured output of every command from the machine-plan secrets, truncated to the output
+// limit. Returns false when the output of a machine that applied the plan is not available yet.
+func collectResults(secrets []*corev1.Secret, failed map[string]bool, spec opv1alpha1.NodeDiagnosticSpec) ([]opv1alpha1.NodeDiagnosticResult, bool, error) {
+	count := 0
+	for _, secret := range secrets {
+		for _, command := range spec.Commands {
+			if appliesTo(command, secret) {
+				count++
+			}
+		}
+	}
+	limit := outputLimit(spec.MaxOutputBytes, count)
+
+	results := make([]opv1alpha1.NodeDiagnosticResult, 0, count)
+	for _, secret := range secrets {
+		var output map[string][]byte
+		if !failed[secret.Name] {
+			var err error
+			output, err = plan.ReadAppliedOutput(secret)
+			if err != nil {
+				return nil, false, err
+			}
+			if output == nil && slices.ContainsFunc(spec.Commands, func(command opv1alpha1.NodeDiagnosticCommand) bool {
+				return appliesTo(command, secret)
+			}) {
+				return nil, false, nil
+			}
+		}
+
+		for i, command := range spec.Commands {
+			if !appliesTo(command, secret) {
+				continue
+			}
+			result := opv1alpha1.NodeDiagnosticResult{
+				MachineName: machineName(secret),
+				Command:     command.Name,
+			}
+			if failed[secret.Name] {
+				result.Output = "diagnostic plan failed to apply on the machine"
+			} else {
+				result.Output, result.Truncated = truncate(output[instructionName(i)], limit)
+			}
+			results = append(results, result)
+		}
+	}
+	return results, true, nil
+}
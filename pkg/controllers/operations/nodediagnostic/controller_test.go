@@ -0,0 +1,117 @@
+package nodediagnostic
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"testing"
+
+	opv1alpha1 "github.com/rancher/rancher/pkg/apis/operation.cattle.io/v1alpha1"
+	"github.com/rancher/rancher/pkg/capr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestSelectMachines(t *testing.T) {
+	secrets := []*corev1.Secret{
+		planSecret("etcd-1", capr.EtcdRoleLabel),
+		planSecret("cp-1", capr.ControlPlaneRoleLabel),
+		planSecret("worker-1", capr.WorkerRoleLabel),
+		planSecret("worker-2", capr.WorkerRoleLabel),
+	}
+
+	names := func(secrets []*corev1.Secret) []string {
+		var result []string
+		for _, secret := range secrets {
+			result = append(result, machineName(secret))
+		}
+		return result
+	}
+
+	tests := []struct {
+		name string
+		spec opv1alpha1.NodeDiagnosticSpec
+		want []string
+	}{
+		{
+			name: "all machines",
+			want: []string{"etcd-1", "cp-1", "worker-1", "worker-2"},
+		},
+		{
+			name: "by role",
+			spec: opv1alpha1.NodeDiagnosticSpec{Roles: []opv1alpha1.NodeDiagnosticRole{opv1alpha1.NodeDiagnosticRoleEtcd, opv1alpha1.NodeDiagnosticRoleControlPlane}},
+			want: []string{"etcd-1", "cp-1"},
+		},
+		{
+			name: "by name",
+			spec: opv1alpha1.NodeDiagnosticSpec{MachineNames: []string{"worker-2", "missing"}},
+			want: []string{"worker-2"},
+		},
+		{
+			name: "by name and role",
+			spec: opv1alpha1.NodeDiagnosticSpec{MachineNames: []string{"worker-2"}, Roles: []opv1alpha1.NodeDiagnosticRole{opv1alpha1.NodeDiagnosticRoleEtcd}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, names(selectMachines(secrets, tt.spec)))
+		})
+	}
+}
+
+func withAppliedOutput(t *testing.T, secret *corev1.Secret, output map[string][]byte) *corev1.Secret {
+	t.Helper()
+
+	data, err := json.Marshal(output)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	_, err = gw.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, gw.Close())
+
+	secret.Data = map[string][]byte{"applied-output": buf.Bytes()}
+	return secret
+}
+
+func TestCollectResults(t *testing.T) {
+	spec := opv1alpha1.NodeDiagnosticSpec{
+		Commands: []opv1alpha1.NodeDiagnosticCommand{
+			{Name: opv1alpha1.NodeDiagnosticDiskUsage},
+			{Name: opv1alpha1.NodeDiagnosticEtcdMemberList},
+		},
+		MaxOutputBytes: 1024,
+	}
+
+	etcd := withAppliedOutput(t, planSecret("etcd-1", capr.EtcdRoleLabel), map[string][]byte{
+		"diagnostic-0": []byte("Filesystem Size"),
+		"diagnostic-1": bytes.Repeat([]byte("m"), 2048),
+	})
+	worker := withAppliedOutput(t, planSecret("worker-1", capr.WorkerRoleLabel), map[string][]byte{
+		"diagnostic-0": []byte("Filesystem Size"),
+	})
+	failed := planSecret("worker-2", capr.WorkerRoleLabel)
+
+	results, ready, err := collectResults([]*corev1.Secret{etcd, worker, failed}, map[string]bool{failed.Name: true}, spec)
+	require.NoError(t, err)
+	assert.True(t, ready)
+	assert.Equal(t, []opv1alpha1.NodeDiagnosticResult{
+		{MachineName: "etcd-1", Command: opv1alpha1.NodeDiagnosticDiskUsage, Output: "Filesystem Size"},
+		{MachineName: "etcd-1", Command: opv1alpha1.NodeDiagnosticEtcdMemberList, Output: truncatedMarker + string(bytes.Repeat([]byte("m"), 1024)), Truncated: true},
+		{MachineName: "worker-1", Command: opv1alpha1.NodeDiagnosticDiskUsage, Output: "Filesystem Size"},
+		{MachineName: "worker-2", Command: opv1alpha1.NodeDiagnosticDiskUsage, Output: "diagnostic plan failed to apply on the machine"},
+	}, results)
+}
+
+func TestCollectResultsWaitsForOutput(t *testing.T) {
+	spec := opv1alpha1.NodeDiagnosticSpec{
+		Commands: []opv1alpha1.NodeDiagnosticCommand{{Name: opv1alpha1.NodeDiagnosticDiskUsage}},
+	}
+
+	_, ready, err := collectResults([]*corev1.Secret{planSecret("worker-1", capr.WorkerRoleLabel)}, nil, spec)
+	require.NoError(t, err)
+	assert.False(t, ready)
+}
@@ -0,0 +1,134 @@
+package supportbundle
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+)
+
+const (
+	// manifestFileName is the name of the manifest at the root of the archive.
+	manifestFileName = "manifest.json"
+
+	// maxArchiveSize is the maximum size of the compressed archive. The archive is stored in a Secret, which is bound
+	// by the etcd request size limit of 1.5MiB, so leave headroom for the Secret itself.
+	maxArchiveSize = 1024 * 1024
+
+	// minFileSize is the size below which files are no longer truncated to make the archive fit.
+	minFileSize = 4 * 1024
+
+	// truncatedMarker is prepended to files that were truncated to make the archive fit.
+	truncatedMarker = "[truncated]\n"
+)
+
+// manifest describes the contents of a support bundle archive.
+type manifest struct {
+	Cluster        string         `json:"cluster"`
+	Operation      string         `json:"operation"`
+	RancherVersion string         `json:"rancherVersion"`
+	CreatedAt      time.Time      `json:"createdAt"`
+	Files          []manifestFile `json:"files"`
+	// Errors lists the items that could not be collected. A support bundle is still produced when parts of the
+	// downstream cluster can not be reached, as that is usually what is being debugged.
+	Errors []string `json:"errors,omitempty"`
+}
+
+// manifestFile describes a single file of a support bundle archive.
+type manifestFile struct {
+	Name string `json:"name"`
+	// Size is the size of the file before truncation.
+	Size      int  `json:"size"`
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// buildArchive writes the given files and a manifest describing them into a gzip-compressed tarball. When the
+// compressed tarball exceeds limit, files are truncated to their most recent content, which for logs is the most
+// relevant, by halving the maximum file size until it fits. Returns the archive and whether any file was truncated.
+func buildArchive(m manifest, files map[string][]byte, limit int) ([]byte, bool, error) {
+	names := make([]string, 0, len(files))
+	largest := 0
+	for name, data := range files {
+		names = append(names, name)
+		largest = max(largest, len(data))
+	}
+	sort.Strings(names)
+
+	maxFileSize := largest
+	for {
+		archive, truncated, err := writeArchive(m, names, files, maxFileSize)
+		if err != nil {
+			return nil, false, err
+		}
+		if len(archive) <= limit {
+			return archive, truncated, nil
+		}
+		if maxFileSize <= minFileSize {
+			return nil, false, fmt.Errorf("support bundle of %d bytes exceeds the maximum size of %d bytes", len(archive), limit)
+		}
+		maxFileSize = max(maxFileSize/2, minFileSize)
+	}
+}
+
+// writeArchive writes the named files, each truncated to at most maxFileSize bytes, and the manifest into a
+// gzip-compressed tarball.
+func writeArchive(m manifest, names []string, files map[string][]byte, maxFileSize int) ([]byte, bool, error) {
+	var (
+		buf       bytes.Buffer
+		truncated bool
+	)
+	gw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gw)
+
+	m.Files = make([]manifestFile, 0, len(names))
+	contents := make([][]byte, 0, len(names))
+	for _, name := range names {
+		data := files[name]
+		file := manifestFile{Name: name, Size: len(data)}
+		if len(data) > maxFileSize {
+			data = append([]byte(truncatedMarker), data[len(data)-maxFileSize:]...)
+			file.Truncated = true
+			truncated = true
+		}
+		m.Files = append(m.Files, file)
+		contents = append(contents, data)
+	}
+
+	manifestData, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return nil, false, err
+	}
+	if err := writeFile(tw, manifestFileName, manifestData, m.CreatedAt); err != nil {
+		return nil, false, err
+	}
+	for i, name := range names {
+		if err := writeFile(tw, name, contents[i], m.CreatedAt); err != nil {
+			return nil, false, err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return nil, false, err
+	}
+	if err := gw.Close(); err != nil {
+		return nil, false, err
+	}
+	return buf.Bytes(), truncated, nil
+}
+
+func writeFile(tw *tar.Writer, name string, data []byte, modTime time.Time) error {
+	hdr := &tar.Header{
+		Name:    name,
+		Mode:    0600,
+		Size:    int64(len(data)),
+		ModTime: modTime,
+	}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	_, err := tw.Write(data)
+	return err
+}
@@ -0,0 +1,110 @@
+package supportbundle
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/rand"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// readArchive returns the files of a gzip-compressed tarball by name.
+func readArchive(t *testing.T, archive []byte) map[string][]byte {
+	t.Helper()
+
+	gr, err := gzip.NewReader(bytes.NewReader(archive))
+	require.NoError(t, err)
+	tr := tar.NewReader(gr)
+
+	files := map[string][]byte{}
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		data, err := io.ReadAll(tr)
+		require.NoError(t, err)
+		files[hdr.Name] = data
+	}
+	return files
+}
+
+func TestBuildArchive(t *testing.T) {
+	m := manifest{
+		Cluster:   "c-m-abcdef",
+		Operation: "fleet-default/bundle",
+		CreatedAt: time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC),
+		Errors:    []string{"listing events: timeout"},
+	}
+	files := map[string][]byte{
+		"nodes/machine-1/journal.log": []byte("journal"),
+		"events.json":                 []byte("[]"),
+	}
+
+	archive, truncated, err := buildArchive(m, files, maxArchiveSize)
+	require.NoError(t, err)
+	assert.False(t, truncated)
+
+	contents := readArchive(t, archive)
+	assert.Equal(t, []byte("journal"), contents["nodes/machine-1/journal.log"])
+	assert.Equal(t, []byte("[]"), contents["events.json"])
+
+	var got manifest
+	require.NoError(t, json.Unmarshal(contents[manifestFileName], &got))
+	assert.Equal(t, "c-m-abcdef", got.Cluster)
+	assert.Equal(t, []string{"listing events: timeout"}, got.Errors)
+	assert.Equal(t, []manifestFile{
+		{Name: "events.json", Size: 2},
+		{Name: "nodes/machine-1/journal.log", Size: 7},
+	}, got.Files)
+}
+
+func TestBuildArchiveTruncates(t *testing.T) {
+	// random data does not compress, so the log has to be truncated to fit
+	noise := make([]byte, 256*1024)
+	_, err := rand.Read(noise)
+	require.NoError(t, err)
+	log := append(noise, []byte("most recent line")...)
+
+	archive, truncated, err := buildArchive(manifest{}, map[string][]byte{
+		"pods/cattle-system/agent/agent.log": log,
+		"nodes.json":                         []byte("[]"),
+	}, 64*1024)
+	require.NoError(t, err)
+	assert.True(t, truncated)
+	assert.LessOrEqual(t, len(archive), 64*1024)
+
+	contents := readArchive(t, archive)
+	agentLog := string(contents["pods/cattle-system/agent/agent.log"])
+	assert.True(t, strings.HasPrefix(agentLog, truncatedMarker))
+	assert.True(t, strings.HasSuffix(agentLog, "most recent line"))
+	assert.Equal(t, []byte("[]"), contents["nodes.json"])
+
+	var got manifest
+	require.NoError(t, json.Unmarshal(contents[manifestFileName], &got))
+	assert.Equal(t, []manifestFile{
+		{Name: "nodes.json", Size: 2},
+		{Name: "pods/cattle-system/agent/agent.log", Size: len(log), Truncated: true},
+	}, got.Files)
+}
+
+func TestBuildArchiveTooLarge(t *testing.T) {
+	files := map[string][]byte{}
+	for _, name := range []string{"a", "b", "c", "d"} {
+		data := make([]byte, 8*1024)
+		_, err := rand.Read(data)
+		require.NoError(t, err)
+		files[name] = data
+	}
+
+	_, _, err := buildArchive(manifest{}, files, 8*1024)
+	assert.Error(t, err)
+}
@@ -0,0 +1,252 @@
+package supportbundle
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/rancher/rancher/pkg/capr"
+	"github.com/rancher/rancher/pkg/plan"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// journalInstructionName is the name of the one-time instruction which collects the journal excerpt of a node.
+	journalInstructionName = "journal"
+
+	// collectNamespace is the namespace of the downstream cluster pod logs are collected from.
+	collectNamespace = "cattle-system"
+
+	// maxEvents is the maximum number of events collected from the downstream cluster.
+	maxEvents = 1000
+
+	redactedValue = "[redacted]"
+)
+
+// sensitiveKey matches the keys of values that are replaced by redactedValue in collected objects.
+var sensitiveKey = regexp.MustCompile(`(?i)(password|passphrase|token|secret|credential|privatekey|kubeconfig|certificate|cacert)`)
+
+// bundle accumulates the files of a support bundle and the errors encountered while collecting them.
+type bundle struct {
+	files  map[string][]byte
+	errors []string
+}
+
+func newBundle() *bundle {
+	return &bundle{files: map[string][]byte{}}
+}
+
+func (b *bundle) add(name string, data []byte) {
+	b.files[name] = data
+}
+
+func (b *bundle) addJSON(name string, v any) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		b.errorf("encoding %s: %v", name, err)
+		return
+	}
+	b.add(name, data)
+}
+
+// addObject adds the redacted JSON representation of obj.
+func (b *bundle) addObject(name string, obj runtime.Object) {
+	data, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		b.errorf("converting %s: %v", name, err)
+		return
+	}
+	b.addJSON(name, redact(data))
+}
+
+func (b *bundle) errorf(format string, args ...any) {
+	b.errors = append(b.errors, fmt.Sprintf(format, args...))
+}
+
+// redact strips the managed fields and last-applied configuration of an object and replaces every string value whose
+// key looks like it holds a credential. Keys ending in Name or Namespace are references to other objects, such as
+// cloudCredentialSecretName, and are kept. The value of name/value pairs, such as environment variables, is replaced
+// when the name looks like it holds a credential.
+func redact(obj map[string]any) map[string]any {
+	if metadata, ok := obj["metadata"].(map[string]any); ok {
+		delete(metadata, "managedFields")
+		if annotations, ok := metadata["annotations"].(map[string]any); ok {
+			delete(annotations, corev1.LastAppliedConfigAnnotation)
+		}
+	}
+	redactValue("", obj)
+	return obj
+}
+
+func redactValue(key string, v any) any {
+	switch t := v.(type) {
+	case map[string]any:
+		for k, val := range t {
+			t[k] = redactValue(k, val)
+		}
+		if name, ok := t["name"].(string); ok && sensitiveKey.MatchString(name) {
+			if value, ok := t["value"].(string); ok && value != "" {
+				t["value"] = redactedValue
+			}
+		}
+	case []any:
+		for i, val := range t {
+			t[i] = redactValue(key, val)
+		}
+	case string:
+		if t != "" && sensitiveKey.MatchString(key) && !strings.HasSuffix(key, "Name") && !strings.HasSuffix(key, "Namespace") {
+			return redactedValue
+		}
+	}
+	return v
+}
+
+// journalInstruction returns the one-time instruction which prints the most recent journal lines of the
+// rancher-system-agent and distribution units. The instruction never fails, as a missing unit must not fail the
+// collection of the remaining ones.
+func journalInstruction(runtime, serverUnit string, lines int) plan.OneTimeInstruction {
+	units := []string{"rancher-system-agent", serverUnit}
+	if agentUnit := runtime + "-agent"; agentUnit != serverUnit {
+		units = append(units, agentUnit)
+	}
+
+	cmd := fmt.Sprintf("journalctl --no-pager -o short-iso -n %d", lines)
+	for _, unit := range units {
+		cmd += " -u " + unit
+	}
+
+	return plan.OneTimeInstruction{
+		CommonInstruction: plan.CommonInstruction{
+			Name:    journalInstructionName,
+			Command: "/bin/sh",
+			Args:    []string{"-c", cmd + " || true"},
+		},
+		SaveOutput: true,
+	}
+}
+
+// collectJournals adds the journal excerpt saved by the journal instruction of every machine-plan secret.
+func collectJournals(b *bundle, secrets []*corev1.Secret) {
+	for _, secret := range secrets {
+		machine := secret.Labels[capr.MachineNameLabel]
+		if machine == "" {
+			machine = secret.Name
+		}
+
+		output, err := plan.ReadAppliedOutput(secret)
+		if err != nil {
+			b.errorf("reading journal of %s: %v", machine, err)
+			continue
+		}
+		journal, ok := output[journalInstructionName]
+		if !ok {
+			b.errorf("journal of %s is not available", machine)
+			continue
+		}
+		b.add(path.Join("nodes", machine, "journal.log"), journal)
+	}
+}
+
+// collectDownstream adds the pod logs of the cattle-system namespace, the events and the node conditions of the
+// downstream cluster. Failures are recorded in the bundle rather than returned, so that a partially reachable cluster
+// still yields a support bundle.
+func collectDownstream(ctx context.Context, b *bundle, client kubernetes.Interface, podLogLines int64) {
+	pods, err := client.CoreV1().Pods(collectNamespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		b.errorf("listing pods in %s: %v", collectNamespace, err)
+	} else {
+		b.addJSON(path.Join("pods", collectNamespace, "pods.json"), podSummaries(pods.Items))
+		for _, pod := range pods.Items {
+			for _, container := range pod.Spec.Containers {
+				name := path.Join("pods", pod.Namespace, pod.Name, container.Name+".log")
+				logs, err := podLogs(ctx, client, pod, container.Name, podLogLines)
+				if err != nil {
+					b.errorf("collecting logs of %s/%s container %s: %v", pod.Namespace, pod.Name, container.Name, err)
+					continue
+				}
+				b.add(name, logs)
+			}
+		}
+	}
+
+	events, err := client.CoreV1().Events("").List(ctx, metav1.ListOptions{Limit: maxEvents})
+	if err != nil {
+		b.errorf("listing events: %v", err)
+	} else {
+		b.addJSON("events.json", events.Items)
+	}
+
+	nodes, err := client.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		b.errorf("listing nodes: %v", err)
+	} else {
+		b.addJSON("nodes.json", nodeSummaries(nodes.Items))
+	}
+}
+
+func podLogs(ctx context.Context, client kubernetes.Interface, pod corev1.Pod, container string, tailLines int64) ([]byte, error) {
+	stream, err := client.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, &corev1.PodLogOptions{
+		Container: container,
+		TailLines: &tailLines,
+	}).Stream(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer stream.Close()
+	return io.ReadAll(stream)
+}
+
+type podSummary struct {
+	Name     string                   `json:"name"`
+	NodeName string                   `json:"nodeName,omitempty"`
+	Phase    corev1.PodPhase          `json:"phase,omitempty"`
+	Reason   string                   `json:"reason,omitempty"`
+	Message  string                   `json:"message,omitempty"`
+	Status   []corev1.ContainerStatus `json:"containerStatuses,omitempty"`
+}
+
+// podSummaries reduces pods to their placement and status, which is what is needed alongside their logs.
+func podSummaries(pods []corev1.Pod) []podSummary {
+	result := make([]podSummary, 0, len(pods))
+	for _, pod := range pods {
+		result = append(result, podSummary{
+			Name:     pod.Name,
+			NodeName: pod.Spec.NodeName,
+			Phase:    pod.Status.Phase,
+			Reason:   pod.Status.Reason,
+			Message:  pod.Status.Message,
+			Status:   pod.Status.ContainerStatuses,
+		})
+	}
+	return result
+}
+
+type nodeSummary struct {
+	Name       string                 `json:"name"`
+	Labels     map[string]string      `json:"labels,omitempty"`
+	Taints     []corev1.Taint         `json:"taints,omitempty"`
+	Conditions []corev1.NodeCondition `json:"conditions,omitempty"`
+	NodeInfo   corev1.NodeSystemInfo  `json:"nodeInfo"`
+}
+
+// nodeSummaries reduces nodes to their conditions and system information.
+func nodeSummaries(nodes []corev1.Node) []nodeSummary {
+	result := make([]nodeSummary, 0, len(nodes))
+	for _, node := range nodes {
+		result = append(result, nodeSummary{
+			Name:       node.Name,
+			Labels:     node.Labels,
+			Taints:     node.Spec.Taints,
+			Conditions: node.Status.Conditions,
+			NodeInfo:   node.Status.NodeInfo,
+		})
+	}
+	return result
+}
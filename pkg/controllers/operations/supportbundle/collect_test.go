@@ -0,0 +1,191 @@
+package supportbundle
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/rancher/rancher/pkg/capr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestRedact(t *testing.T) {
+	obj := map[string]any{
+		"metadata": map[string]any{
+			"name":          "c-m-abcdef",
+			"managedFields": []any{map[string]any{"manager": "rancher"}},
+			"annotations": map[string]any{
+				corev1.LastAppliedConfigAnnotation: "{}",
+				"field.cattle.io/creatorId":        "user-abcde",
+			},
+		},
+		"spec": map[string]any{
+			"cloudCredentialSecretName": "cattle-global-data:cc-abcde",
+			"privateRegistryPassword":   "hunter2",
+			"agentEnvVars": []any{
+				map[string]any{"name": "HTTP_PROXY", "value": "http://proxy"},
+				map[string]any{"name": "AWS_SECRET_ACCESS_KEY", "value": "abc123"},
+				map[string]any{"name": "GITHUB_TOKEN", "valueFrom": map[string]any{"secretKeyRef": map[string]any{"name": "github"}}},
+			},
+			"secretsEncryption": true,
+			"registries": map[string]any{
+				"configs": map[string]any{
+					"registry.example.com": map[string]any{"authConfigSecretName": "auth", "caBundle": "pem"},
+				},
+			},
+		},
+		"status": map[string]any{
+			"serviceAccountToken": "token",
+			"caCert":              "pem",
+			"tokens":              []any{"a", "b"},
+		},
+	}
+
+	redacted := redact(obj)
+
+	metadata := redacted["metadata"].(map[string]any)
+	assert.NotContains(t, metadata, "managedFields")
+	assert.Equal(t, map[string]any{"field.cattle.io/creatorId": "user-abcde"}, metadata["annotations"])
+
+	spec := redacted["spec"].(map[string]any)
+	assert.Equal(t, "cattle-global-data:cc-abcde", spec["cloudCredentialSecretName"])
+	assert.Equal(t, redactedValue, spec["privateRegistryPassword"])
+	assert.Equal(t, true, spec["secretsEncryption"])
+	assert.Equal(t, []any{
+		map[string]any{"name": "HTTP_PROXY", "value": "http://proxy"},
+		map[string]any{"name": "AWS_SECRET_ACCESS_KEY", "value": redactedValue},
+		map[string]any{"name": "GITHUB_TOKEN", "valueFrom": map[string]any{"secretKeyRef": map[string]any{"name": "github"}}},
+	}, spec["agentEnvVars"])
+	registry := spec["registries"].(map[string]any)["configs"].(map[string]any)["registry.example.com"]
+	assert.Equal(t, map[string]any{"authConfigSecretName": "auth", "caBundle": "pem"}, registry)
+
+	status := redacted["status"].(map[string]any)
+	assert.Equal(t, redactedValue, status["serviceAccountToken"])
+	assert.Equal(t, redactedValue, status["caCert"])
+	assert.Equal(t, []any{redactedValue, redactedValue}, status["tokens"])
+}
+
+func TestJournalInstruction(t *testing.T) {
+	tests := []struct {
+		name       string
+		runtime    string
+		serverUnit string
+		want       string
+	}{
+		{
+			name:       "rke2",
+			runtime:    capr.RuntimeRKE2,
+			serverUnit: "rke2-server",
+			want:       "journalctl --no-pager -o short-iso -n 500 -u rancher-system-agent -u rke2-server -u rke2-agent || true",
+		},
+		{
+			name:       "k3s",
+			runtime:    capr.RuntimeK3S,
+			serverUnit: "k3s",
+			want:       "journalctl --no-pager -o short-iso -n 500 -u rancher-system-agent -u k3s -u k3s-agent || true",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			instruction := journalInstruction(tt.runtime, tt.serverUnit, 500)
+			assert.Equal(t, journalInstructionName, instruction.Name)
+			assert.Equal(t, "/bin/sh", instruction.Command)
+			assert.Equal(t, []string{"-c", tt.want}, instruction.Args)
+			assert.True(t, instruction.SaveOutput)
+		})
+	}
+}
+
+func appliedOutput(t *testing.T, output map[string][]byte) []byte {
+	t.Helper()
+
+	data, err := json.Marshal(output)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	_, err = gw.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, gw.Close())
+	return buf.Bytes()
+}
+
+func TestCollectJournals(t *testing.T) {
+	secrets := []*corev1.Secret{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "machine-1-machine-plan", Labels: map[string]string{capr.MachineNameLabel: "machine-1"}},
+			Data:       map[string][]byte{"applied-output": appliedOutput(t, map[string][]byte{journalInstructionName: []byte("journal of machine-1")})},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "machine-2-machine-plan", Labels: map[string]string{capr.MachineNameLabel: "machine-2"}},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "machine-3-machine-plan", Labels: map[string]string{capr.MachineNameLabel: "machine-3"}},
+			Data:       map[string][]byte{"applied-output": []byte("not gzip")},
+		},
+	}
+
+	b := newBundle()
+	collectJournals(b, secrets)
+
+	assert.Equal(t, map[string][]byte{"nodes/machine-1/journal.log": []byte("journal of machine-1")}, b.files)
+	assert.Len(t, b.errors, 2)
+}
+
+func TestCollectDownstream(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "cattle-cluster-agent-abc", Namespace: collectNamespace},
+			Spec: corev1.PodSpec{
+				NodeName:   "node-1",
+				Containers: []corev1.Container{{Name: "cluster-register"}},
+			},
+			Status: corev1.PodStatus{Phase: corev1.PodRunning},
+		},
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "coredns", Namespace: "kube-system"},
+			Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "coredns"}}},
+		},
+		&corev1.Event{
+			ObjectMeta: metav1.ObjectMeta{Name: "event-1", Namespace: collectNamespace},
+			Reason:     "BackOff",
+		},
+		&corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: "node-1"},
+			Status: corev1.NodeStatus{
+				Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionFalse}},
+				NodeInfo:   corev1.NodeSystemInfo{KubeletVersion: "v1.33.1+rke2r1"},
+			},
+		},
+	)
+
+	b := newBundle()
+	collectDownstream(context.Background(), b, client, 100)
+
+	assert.Empty(t, b.errors)
+	assert.Contains(t, b.files, "pods/cattle-system/cattle-cluster-agent-abc/cluster-register.log")
+	assert.NotContains(t, b.files, "pods/kube-system/coredns/coredns.log")
+
+	var pods []podSummary
+	require.NoError(t, json.Unmarshal(b.files["pods/cattle-system/pods.json"], &pods))
+	assert.Equal(t, []podSummary{{Name: "cattle-cluster-agent-abc", NodeName: "node-1", Phase: corev1.PodRunning}}, pods)
+
+	var events []corev1.Event
+	require.NoError(t, json.Unmarshal(b.files["events.json"], &events))
+	require.Len(t, events, 1)
+	assert.Equal(t, "BackOff", events[0].Reason)
+
+	var nodes []nodeSummary
+	require.NoError(t, json.Unmarshal(b.files["nodes.json"], &nodes))
+	require.Len(t, nodes, 1)
+	assert.Equal(t, "node-1", nodes[0].Name)
+	assert.Equal(t, corev1.ConditionFalse, nodes[0].Conditions[0].Status)
+	assert.Equal(t, "v1.33.1+rke2r1", nodes[0].NodeInfo.KubeletVersion)
+}
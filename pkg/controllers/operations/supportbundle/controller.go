@@ -0,0 +1,881 @@
+package supportbundle
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	opv1alpha1 "github.com/rancher/rancher/pkg/apis/operation.cattle.io/v1alpha1"
+	provcluster "github.com/rancher/rancher/pkg/controllers/provisioningv2/cluster"
+	capicontrollers "github.com/rancher/rancher/pkg/generated/controllers/cluster.x-k8s.io/v1beta2"
+	mgmtcontrollers "github.com/rancher/rancher/pkg/generated/controllers/management.cattle.io/v3"
+	operationcontrollers "github.com/rancher/rancher/pkg/generated/controllers/operation.cattle.io/v1alpha1"
+	provcontrollers "github.com/rancher/rancher/pkg/generated/controllers/provisioning.cattle.io/v1"
+	ops "github.com/rancher/rancher/pkg/operations"
+	"github.com/rancher/rancher/pkg/plan"
+	planv1alpha1 "github.com/rancher/rancher/pkg/plan/api/plan.cattle.io/v1alpha1"
+	plancontrollers "github.com/rancher/rancher/pkg/plan/generated/controllers/plan.cattle.io/v1alpha1"
+	"github.com/rancher/rancher/pkg/settings"
+	"github.com/rancher/rancher/pkg/wrangler"
+	"github.com/rancher/wrangler/v3/pkg/condition"
+	corecontrollers "github.com/rancher/wrangler/v3/pkg/generated/controllers/core/v1"
+	"github.com/rancher/wrangler/v3/pkg/generic"
+	"github.com/rancher/wrangler/v3/pkg/name"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	capi "sigs.k8s.io/cluster-api/api/core/v1beta2"
+)
+
+const (
+	// ControllerOwnerKey is the value used to identify the support-bundle handler currently owns the beacon.
+	ControllerOwnerKey = "support-bundle"
+
+	// Step hook label prefixes for the supportbundle operation. They follow the shared label
+	// semantics documented on planv1alpha1's phase-hook label constants, but each prefix only fires
+	// when the operation enters the matching step.
+
+	// JournalStepHookLabelPrefix gates the Journal step before reconcileJournal assigns the
+	// journalctl plan to every non-Windows machine-plan secret.
+	JournalStepHookLabelPrefix = "journal.step.hook.operation.cattle.io/"
+
+	// CollectStepHookLabelPrefix gates the Collect step before reconcileCollect reaches into the
+	// downstream cluster and packages the bundle.
+	CollectStepHookLabelPrefix = "collect.step.hook.operation.cattle.io/"
+
+	// ArchiveDataKey is the key of the archive in the Secret referenced by the status of the operation.
+	ArchiveDataKey = "supportbundle.tar.gz"
+
+	defaultJournalLines = 1000
+	defaultPodLogLines  = 1000
+
+	// collectTimeout bounds the time spent collecting from the downstream cluster.
+	collectTimeout = 2 * time.Minute
+)
+
+// stepHookPrefixFor returns the step-hook label prefix for the given support-bundle step, or "" for
+// an unknown / empty step.
+func stepHookPrefixFor(step opv1alpha1.SupportBundleStep) string {
+	switch step {
+	case opv1alpha1.SupportBundleStepJournal:
+		return JournalStepHookLabelPrefix
+	case opv1alpha1.SupportBundleStepCollect:
+		return CollectStepHookLabelPrefix
+	}
+	return ""
+}
+
+// dynamicResolver is the subset of *dynamic.Controller this handler needs. It's an interface so
+// tests can substitute a stub — *dynamic.Controller satisfies it directly.
+type dynamicResolver interface {
+	Get(gvk schema.GroupVersionKind, namespace, name string) (runtime.Object, error)
+}
+
+// handler is the per-cluster reconciliation state for the SupportBundle controller. All fields
+// are populated at Register time; the only state kept across reconciles is the bundles being
+// collected in the background.
+type handler struct {
+	ctx context.Context
+
+	supportbundles operationcontrollers.SupportBundleController
+
+	beacons     plancontrollers.BeaconClient
+	beaconCache plancontrollers.BeaconCache
+
+	secrets corecontrollers.SecretClient
+
+	mgmtClusters mgmtcontrollers.ClusterCache
+	mgmtNodes    mgmtcontrollers.NodeCache
+	provClusters provcontrollers.ClusterCache
+	machines     capicontrollers.MachineCache
+
+	store *plan.Store
+
+	dynamic dynamicResolver
+
+	// k8sClient returns a client for the downstream cluster with the given management cluster
+	// name, connected through the cluster agent tunnel.
+	k8sClient func(clusterName string) (kubernetes.Interface, error)
+
+	clients *wrangler.CAPIContext
+
+	collections *collections
+}
+
+// Register wires the SupportBundle controller into the given wrangler context. It must be
+// called exactly once per process; subsequent calls would clobber the registered status handler.
+func Register(ctx context.Context, clients *wrangler.CAPIContext) {
+	h := &handler{
+		ctx:            ctx,
+		supportbundles: clients.Operation.SupportBundle(),
+		beacons:        clients.Plan.Beacon(),
+		beaconCache:    clients.Plan.Beacon().Cache(),
+		secrets:        clients.Core.Secret(),
+		mgmtClusters:   clients.Mgmt.Cluster().Cache(),
+		mgmtNodes:      clients.Mgmt.Node().Cache(),
+		provClusters:   clients.Provisioning.Cluster().Cache(),
+		machines:       clients.CAPI.Machine().Cache(),
+		dynamic:        clients.Dynamic,
+		store:          plan.NewStore(clients.Core.Secret()),
+		k8sClient:      clients.MultiClusterManager.K8sClient,
+		clients:        clients,
+		collections:    newCollections(),
+	}
+
+	operationcontrollers.RegisterSupportBundleStatusHandler(ctx, clients.Operation.SupportBundle(), "", "support-bundle-handler", h.OnChange)
+}
+
+// OnChange is the status handler entrypoint invoked by the wrangler-registered controller. It
+// delegates the phase-specific work to onChange, then runs the common condition refresh through
+// updateStatus. When the status did not change, the operation is either deleted once expired or
+// re-enqueued to poll the plan secrets.
+func (h *handler) OnChange(op *opv1alpha1.SupportBundle, status opv1alpha1.SupportBundleStatus) (opv1alpha1.SupportBundleStatus, error) {
+	status, err := h.onChange(op, status)
+	if err != nil {
+		return status, err
+	}
+	status = updateStatus(op, status)
+
+	if equality.Semantic.DeepEqual(op.Status, status) {
+		if ops.IsTerminal(status.Phase) &&
+			ops.IsExpired(&op.Spec.OperationSpec, &status.OperationStatus) &&
+			!planv1alpha1.HasActiveLifecycleHook(op) {
+			err = h.supportbundles.Delete(op.Namespace, op.Name, &metav1.DeleteOptions{})
+			if err != nil {
+				return status, err
+			}
+			return status, generic.ErrSkip
+		}
+
+		h.supportbundles.EnqueueAfter(op.Namespace, op.Name, 5*time.Second)
+	}
+	return status, nil
+}
+
+// scope bundles the per-reconcile values derived from the operation, parent cluster, and beacon.
+type scope struct {
+	ownerKey string
+
+	op        *opv1alpha1.SupportBundle
+	namespace string
+
+	beacon     *planv1alpha1.Beacon
+	ref        *unstructured.Unstructured
+	clusterObj *unstructured.Unstructured
+	adapter    ops.Adapter
+}
+
+// onChange resolves the parent cluster reference, locates the cluster's beacon, builds an Adapter
+// for the cluster kind, and dispatches to the phase-specific handler.
+func (h *handler) onChange(op *opv1alpha1.SupportBundle, status opv1alpha1.SupportBundleStatus) (opv1alpha1.SupportBundleStatus, error) {
+	if op == nil {
+		return status, nil
+	}
+
+	if op.DeletionTimestamp != nil {
+		h.collections.forget(op.UID)
+		return status, nil
+	}
+
+	if ops.IsPaused(&op.Spec.OperationSpec) {
+		logrus.Debugf("[supportbundle] %s/%s: skipping paused operation", op.Namespace, op.Name)
+		return status, nil
+	}
+
+	if status.Phase == "" {
+		status.SetPhase(opv1alpha1.OperationPhasePending)
+	}
+
+	gvk := schema.FromAPIVersionAndKind(op.Spec.ClusterRef.APIVersion, op.Spec.ClusterRef.Kind)
+	ref, err := h.dynamic.Get(gvk, op.Spec.ClusterRef.Namespace, op.Spec.ClusterRef.Name)
+	if apierrors.IsNotFound(err) {
+		key := fmt.Sprintf("apiVersion=%s, kind=%s", op.Spec.ClusterRef.APIVersion, op.Spec.ClusterRef.Kind)
+		if op.Spec.ClusterRef.Namespace != "" {
+			key += fmt.Sprintf(", namespace=%s", op.Spec.ClusterRef.Namespace)
+		}
+		key += fmt.Sprintf(", name=%s", op.Spec.ClusterRef.Name)
+		logrus.Errorf("[supportbundle]: %s/%s failed to find cluster for %s", op.Namespace, op.Name, key)
+
+		opv1alpha1.FailedCondition.True(&status)
+		opv1alpha1.FailedCondition.Reason(&status, opv1alpha1.ClusterNotFoundReason)
+		opv1alpha1.FailedCondition.Message(&status, fmt.Sprintf("cluster %s not found", key))
+
+		status.SetPhase(opv1alpha1.OperationPhaseFailed)
+		return status, nil
+	}
+	if err != nil {
+		return status, err
+	}
+
+	ustrMap, err := runtime.DefaultUnstructuredConverter.ToUnstructured(ref)
+	if err != nil {
+		return status, err
+	}
+
+	ustr := unstructured.Unstructured{Object: ustrMap}
+
+	a, err := ops.NewAdapter(h.clients, &ustr)
+	if err != nil {
+		return status, err
+	}
+
+	clusterObj, err := a.ClusterObject()
+	if err != nil {
+		return status, err
+	}
+
+	namespace, beaconName := a.BeaconRef()
+
+	beacon, err := h.beacons.Get(namespace, beaconName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) && status.Phase == opv1alpha1.OperationPhasePending {
+		logrus.Warnf("[supportbundle]: %s/%s failed to find beacon %s/%s (clusterRef apiVersion=%s kind=%s name=%s)",
+			op.Namespace, op.Name, namespace, beaconName, ustr.GetAPIVersion(), ustr.GetKind(), ustr.GetName())
+
+		opv1alpha1.PendingCondition.True(&status)
+		opv1alpha1.PendingCondition.Reason(&status, opv1alpha1.WaitingForBeaconReason)
+		opv1alpha1.PendingCondition.Message(&status, "waiting for beacon creation")
+
+		return status, nil
+	} else if err != nil {
+		return status, err
+	}
+
+	s := &scope{
+		ownerKey:   plan.ControllerOwnerKey(op, ControllerOwnerKey),
+		op:         op,
+		beacon:     beacon,
+		namespace:  namespace,
+		ref:        &ustr,
+		clusterObj: clusterObj,
+		adapter:    a,
+	}
+
+	switch status.Phase {
+	case opv1alpha1.OperationPhasePending:
+		return h.handlePending(s, status)
+	case opv1alpha1.OperationPhaseInProgress:
+		return h.handleInProgress(s, status)
+	case opv1alpha1.OperationPhaseCanceled:
+		return h.handleTerminal(s, status, opv1alpha1.CanceledCondition, planv1alpha1.CanceledPhaseHookLabelPrefix)
+	case opv1alpha1.OperationPhaseFailed:
+		return h.handleTerminal(s, status, opv1alpha1.FailedCondition, planv1alpha1.FailedPhaseHookLabelPrefix)
+	case opv1alpha1.OperationPhaseSucceeded:
+		return h.handleTerminal(s, status, opv1alpha1.SucceededCondition, planv1alpha1.SucceededPhaseHookLabelPrefix)
+	}
+
+	status.SetPhase(opv1alpha1.OperationPhaseFailed)
+
+	opv1alpha1.FailedCondition.True(&status)
+	opv1alpha1.FailedCondition.Reason(&status, opv1alpha1.UnknownPhaseReason)
+	opv1alpha1.FailedCondition.Message(&status, fmt.Sprintf("unknown phase [%s]", op.Status.Phase))
+
+	return status, nil
+}
+
+func (h *handler) lifecycleHookDelegate(s *scope, prefix string) (string, string) {
+	for k, v := range s.op.Labels {
+		if strings.HasPrefix(k, prefix) {
+			return strings.TrimPrefix(k, prefix), v
+		}
+	}
+
+	return "", ""
+}
+
+func (h *handler) delegate(s *scope, name, delegate string) error {
+	logrus.Tracef("[supportbundle] %s/%s: delegating ownership of beacon to %s on behalf of %s", s.op.Namespace, s.op.Name, delegate, name)
+
+	if plan.IsInDelegateChain(s.beacon, delegate) {
+		return nil
+	}
+
+	beacon, err := plan.PushDelegate(s.beacon, delegate, h.beacons)
+	if err != nil {
+		return err
+	}
+
+	s.beacon = beacon
+
+	return nil
+}
+
+func (h *handler) handleHook(s *scope, prefix string) (bool, error) {
+	if name, delegate := h.lifecycleHookDelegate(s, prefix); delegate != "" {
+		err := h.delegate(s, name, delegate)
+		return true, err
+	}
+
+	return false, nil
+}
+
+// handlePending acquires the cluster's beacon and waits for every expected system-agent to
+// register a machine-plan secret, then transitions the operation to InProgress at the Journal step.
+func (h *handler) handlePending(s *scope, status opv1alpha1.SupportBundleStatus) (opv1alpha1.SupportBundleStatus, error) {
+	logrus.Tracef("[supportbundle] %s/%s: handling pending", s.op.Namespace, s.op.Name)
+
+	if !plan.IsInDelegateChain(s.beacon, s.ownerKey) {
+		acquired, err := plan.AcquireBeacon(s.beacon, h.beacons, s.ownerKey)
+		if err != nil {
+			return status, err
+		}
+		if acquired == nil {
+			opv1alpha1.PendingCondition.True(&status)
+			opv1alpha1.PendingCondition.Reason(&status, opv1alpha1.WaitingForBeaconReason)
+			opv1alpha1.PendingCondition.Message(&status, "waiting for beacon creation")
+			return status, nil
+		}
+		s.beacon = acquired
+	}
+
+	delegated, err := h.handleHook(s, planv1alpha1.PendingPhaseHookLabelPrefix)
+	if err != nil {
+		return status, err
+	} else if delegated {
+		opv1alpha1.PendingCondition.True(&status)
+		opv1alpha1.PendingCondition.Reason(&status, opv1alpha1.WaitingForDelegateReason)
+		opv1alpha1.PendingCondition.Message(&status, fmt.Sprintf("Waiting for delegates to finish: %v", opv1alpha1.WaitingForDelegateMessage(s.beacon)))
+		return status, nil
+	}
+
+	if ok, err := s.adapter.WaitForRegister(); err != nil {
+		return status, err
+	} else if !ok {
+		logrus.Infof("[supportbundle] %s/%s: waiting for system-agents to connect", s.op.Namespace, s.op.Name)
+		opv1alpha1.PendingCondition.True(&status)
+		opv1alpha1.PendingCondition.Reason(&status, opv1alpha1.WaitingForRegistrationReason)
+		opv1alpha1.PendingCondition.Message(&status, "waiting for system-agents to connect")
+		return status, nil
+	}
+
+	logrus.Infof("[supportbundle] %s/%s: transitioning to journal", s.op.Namespace, s.op.Name)
+
+	status.SetPhase(opv1alpha1.OperationPhaseInProgress)
+	status.SetStep(opv1alpha1.SupportBundleStepJournal)
+
+	opv1alpha1.InProgressCondition.True(&status)
+	opv1alpha1.InProgressCondition.Reason(&status, opv1alpha1.InProgressReason)
+	return status, nil
+}
+
+// handleInProgress re-verifies beacon ownership, marks the beacon active so the system-agent will
+// keep polling, and then dispatches to the step-specific reconciler.
+func (h *handler) handleInProgress(s *scope, status opv1alpha1.SupportBundleStatus) (opv1alpha1.SupportBundleStatus, error) {
+	logrus.Tracef("[supportbundle] %s/%s: handling in-progress", s.op.Namespace, s.op.Name)
+
+	stepPrefix := stepHookPrefixFor(s.op.Status.Step)
+
+	if !plan.IsOwningBeaconHolder(s.beacon, s.ownerKey) && !plan.IsInDelegateChain(s.beacon, s.ownerKey) {
+		if planv1alpha1.HasStepHookLabel(s.op, stepPrefix) {
+			return waitingForDelegate(s, status), nil
+		}
+		logrus.Errorf("[supportbundle] %s/%s: beacon reassigned, aborting", s.op.Namespace, s.op.Name)
+		status.SetPhase(opv1alpha1.OperationPhaseFailed)
+
+		opv1alpha1.FailedCondition.True(&status)
+		opv1alpha1.FailedCondition.Reason(&status, opv1alpha1.BeaconLostReason)
+		opv1alpha1.FailedCondition.Message(&status, "beacon reassigned, aborting")
+
+		return status, nil
+	}
+
+	var err error
+	s.beacon, err = plan.ToggleBeacon(s.beacon, true, h.beacons)
+	if err != nil {
+		return status, err
+	}
+
+	delegated, err := h.handleHook(s, planv1alpha1.InProgressPhaseHookLabelPrefix)
+	if err != nil {
+		return status, err
+	} else if delegated {
+		return waitingForDelegate(s, status), nil
+	}
+
+	if !plan.AuthorizedForBeacon(s.beacon, s.ownerKey) {
+		if planv1alpha1.HasStepHookLabel(s.op, stepPrefix) {
+			return waitingForDelegate(s, status), nil
+		}
+		logrus.Errorf("[supportbundle] %s/%s: beacon lost, aborting", s.op.Namespace, s.op.Name)
+		status.SetPhase(opv1alpha1.OperationPhaseFailed)
+
+		opv1alpha1.FailedCondition.True(&status)
+		opv1alpha1.FailedCondition.Reason(&status, opv1alpha1.BeaconLostReason)
+		opv1alpha1.FailedCondition.Message(&status, "Beacon acquired by another controller, aborting")
+
+		return status, nil
+	}
+
+	switch s.op.Status.Step {
+	case opv1alpha1.SupportBundleStepJournal:
+		return h.reconcileJournal(s, status)
+	case opv1alpha1.SupportBundleStepCollect:
+		return h.reconcileCollect(s, status)
+	default:
+		status.SetPhase(opv1alpha1.OperationPhaseFailed)
+
+		opv1alpha1.FailedCondition.True(&status)
+		opv1alpha1.FailedCondition.Reason(&status, opv1alpha1.UnknownStepReason)
+		opv1alpha1.FailedCondition.Message(&status, fmt.Sprintf(
+			"current step [\"%s\"] is unknown, expected one of: [\"%s\", \"%s\"]",
+			status.Step,
+			opv1alpha1.SupportBundleStepJournal,
+			opv1alpha1.SupportBundleStepCollect))
+	}
+
+	return status, nil
+}
+
+func waitingForDelegate(s *scope, status opv1alpha1.SupportBundleStatus) opv1alpha1.SupportBundleStatus {
+	opv1alpha1.InProgressCondition.True(&status)
+	opv1alpha1.InProgressCondition.Reason(&status, opv1alpha1.WaitingForDelegateReason)
+	opv1alpha1.InProgressCondition.Message(&status, fmt.Sprintf("Waiting for delegates to finish: %v", opv1alpha1.WaitingForDelegateMessage(s.beacon)))
+	return status
+}
+
+// collectSecrets returns the machine-plan secrets of every non-Windows node of the cluster.
+func (h *handler) collectSecrets(s *scope) ([]*corev1.Secret, error) {
+	return plan.NewCollector(h.secrets, s.clusterObj, s.namespace).
+		WithSorter(plan.DefaultSorter()).
+		WithFilter(plan.FilterFunc(ops.Not(ops.IsWindows))).
+		WithValidator(plan.AtLeast(1, "")).
+		Collect()
+}
+
+// reconcileJournal assigns the journalctl plan to every non-Windows machine-plan secret at once,
+// as the plan is read-only. The instruction never fails on the node, so a node whose plan failed
+// regardless is counted as collected and reported as missing in the manifest of the bundle.
+// Once every node has applied the plan, transitions to the Collect step.
+func (h *handler) reconcileJournal(s *scope, status opv1alpha1.SupportBundleStatus) (opv1alpha1.SupportBundleStatus, error) {
+	logrus.Debugf("[supportbundle] %s/%s: handling journal", s.op.Namespace, s.op.Name)
+
+	delegated, err := h.handleHook(s, JournalStepHookLabelPrefix)
+	if err != nil {
+		return status, err
+	} else if delegated {
+		return waitingForDelegate(s, status), nil
+	}
+
+	secrets, err := h.collectSecrets(s)
+	if plan.IsTransient(err) {
+		return status, err
+	} else if err != nil {
+		logrus.Errorf("[supportbundle] %s/%s: marking operation as failed: encountered terminal error collecting machine-plan secrets: %v", s.op.Namespace, s.op.Name, err)
+
+		status.SetPhase(opv1alpha1.OperationPhaseFailed)
+
+		opv1alpha1.FailedCondition.True(&status)
+		opv1alpha1.FailedCondition.Reason(&status, opv1alpha1.PlanFailedReason)
+		opv1alpha1.FailedCondition.Message(&status, fmt.Sprintf("encountered terminal error collecting machine-plan secrets: %v", err))
+		return status, nil
+	}
+
+	lines := s.op.Spec.Args.JournalLines
+	if lines == 0 {
+		lines = defaultJournalLines
+	}
+	instruction := journalInstruction(s.adapter.RuntimeCommand(), s.adapter.ServerUnit(), lines)
+
+	results := make([]plan.PlanStatus, 0, len(secrets))
+	collected := 0
+	for _, secret := range secrets {
+		planStatus, err := h.store.AssignPlan(secret, &plan.Plan{OneTimeInstructions: []plan.OneTimeInstruction{instruction}}, 1, -1)
+		if err != nil {
+			return status, err
+		}
+
+		results = append(results, *planStatus)
+
+		if planStatus.Waiting() {
+			logrus.Debugf("[supportbundle] %s/%s: waiting for journal for %s/%s", s.op.Namespace, s.op.Name, secret.Namespace, secret.Name)
+			continue
+		}
+		if planStatus.Failure() {
+			logrus.Warnf("[supportbundle] %s/%s: failed to collect journal for %s/%s", s.op.Namespace, s.op.Name, secret.Namespace, secret.Name)
+		}
+		collected++
+	}
+
+	status.NodesTotal = len(secrets)
+	status.NodesCollected = collected
+
+	if collected < len(secrets) {
+		msg := plan.Message(results)
+		opv1alpha1.InProgressCondition.True(&status)
+		opv1alpha1.InProgressCondition.Reason(&status, opv1alpha1.WaitingForPlanAppliedReason)
+		opv1alpha1.InProgressCondition.Message(&status, fmt.Sprintf("Waiting in step %s: %s", status.Step, msg))
+
+		return status, nil
+	}
+
+	logrus.Infof("[supportbundle] %s/%s: transitioning to collect", s.op.Namespace, s.op.Name)
+
+	status.SetStep(opv1alpha1.SupportBundleStepCollect)
+	return status, nil
+}
+
+// reconcileCollect collects the support bundle in the background, so that the downstream cluster
+// doesn't block a worker of the controller, and records the archive once it has been saved. The
+// operation stays InProgress with the Collecting reason until then.
+func (h *handler) reconcileCollect(s *scope, status opv1alpha1.SupportBundleStatus) (opv1alpha1.SupportBundleStatus, error) {
+	logrus.Debugf("[supportbundle] %s/%s: handling collect", s.op.Namespace, s.op.Name)
+
+	delegated, err := h.handleHook(s, CollectStepHookLabelPrefix)
+	if err != nil {
+		return status, err
+	} else if delegated {
+		return waitingForDelegate(s, status), nil
+	}
+
+	if h.collections.start(s.op.UID, func() collectionResult { return h.collect(s) }, func() {
+		h.supportbundles.Enqueue(s.op.Namespace, s.op.Name)
+	}) {
+		logrus.Infof("[supportbundle] %s/%s: collecting support bundle", s.op.Namespace, s.op.Name)
+	}
+
+	result, done := h.collections.result(s.op.UID)
+	if !done {
+		opv1alpha1.InProgressCondition.True(&status)
+		opv1alpha1.InProgressCondition.Reason(&status, opv1alpha1.CollectingReason)
+		opv1alpha1.InProgressCondition.Message(&status, "Collecting support bundle")
+		return status, nil
+	}
+
+	if result.err != nil {
+		// The bundle is collected again on the next attempt.
+		h.collections.forget(s.op.UID)
+		return status, result.err
+	}
+
+	if result.archiveErr != nil {
+		logrus.Errorf("[supportbundle] %s/%s: marking operation as failed: %v", s.op.Namespace, s.op.Name, result.archiveErr)
+
+		status.SetPhase(opv1alpha1.OperationPhaseFailed)
+
+		opv1alpha1.FailedCondition.True(&status)
+		opv1alpha1.FailedCondition.Reason(&status, opv1alpha1.ArchiveFailedReason)
+		opv1alpha1.FailedCondition.Message(&status, result.archiveErr.Error())
+		return status, nil
+	}
+
+	status.Archive = result.archive
+
+	logrus.Infof("[supportbundle] %s/%s: marking as success", s.op.Namespace, s.op.Name)
+
+	status.SetPhase(opv1alpha1.OperationPhaseSucceeded)
+
+	opv1alpha1.SucceededCondition.True(&status)
+	opv1alpha1.SucceededCondition.Reason(&status, opv1alpha1.FinishedReason)
+	opv1alpha1.SucceededCondition.Message(&status, "Operation completed successfully")
+
+	return status, nil
+}
+
+// collect gathers the journal excerpts saved on the machine-plan secrets, the pod logs, events and
+// node conditions of the downstream cluster through the cluster agent tunnel, and the redacted
+// Rancher objects of the cluster, and packages them into an archive stored in a Secret owned by
+// the operation. Anything that can not be collected is listed in the manifest of the archive
+// instead of failing the operation.
+func (h *handler) collect(s *scope) collectionResult {
+	secrets, err := h.collectSecrets(s)
+	if plan.IsTransient(err) {
+		return collectionResult{err: err}
+	}
+
+	b := newBundle()
+	if err != nil {
+		b.errorf("collecting machine-plan secrets: %v", err)
+	} else {
+		collectJournals(b, secrets)
+	}
+
+	clusterName := managementClusterName(s.ref, s.clusterObj)
+	h.collectRancherObjects(b, s, clusterName)
+
+	if clusterName == "" {
+		b.errorf("management cluster of %s %s is unknown, skipping downstream collection", s.ref.GetKind(), s.ref.GetName())
+	} else if client, err := h.k8sClient(clusterName); err != nil {
+		b.errorf("connecting to downstream cluster %s: %v", clusterName, err)
+	} else {
+		podLogLines := s.op.Spec.Args.PodLogLines
+		if podLogLines == 0 {
+			podLogLines = defaultPodLogLines
+		}
+		ctx, cancel := context.WithTimeout(h.ctx, collectTimeout)
+		collectDownstream(ctx, b, client, podLogLines)
+		cancel()
+	}
+
+	archive, truncated, err := buildArchive(manifest{
+		Cluster:        clusterName,
+		Operation:      s.op.Namespace + "/" + s.op.Name,
+		RancherVersion: settings.ServerVersion.Get(),
+		CreatedAt:      time.Now().UTC(),
+		Errors:         b.errors,
+	}, b.files, maxArchiveSize)
+	if err != nil {
+		return collectionResult{archiveErr: err}
+	}
+
+	secretName, err := h.saveArchive(s.op, archive)
+	if err != nil {
+		return collectionResult{err: err}
+	}
+
+	sum := sha256.Sum256(archive)
+	return collectionResult{archive: &opv1alpha1.SupportBundleArchive{
+		SecretName: secretName,
+		Size:       int64(len(archive)),
+		SHA256:     hex.EncodeToString(sum[:]),
+		Truncated:  truncated,
+	}}
+}
+
+// collectionResult is the outcome of the background collection of a support bundle.
+type collectionResult struct {
+	archive *opv1alpha1.SupportBundleArchive
+	// archiveErr is set when the bundle could not be packaged, which fails the operation.
+	archiveErr error
+	// err is set when the collection failed with a transient error and must be retried.
+	err error
+}
+
+// collections tracks the support bundles collected in the background, by operation UID.
+type collections struct {
+	mu      sync.Mutex
+	running map[types.UID]*collection
+}
+
+// collection is a background collection, which has no result until it finishes.
+type collection struct {
+	result *collectionResult
+}
+
+func newCollections() *collections {
+	return &collections{running: map[types.UID]*collection{}}
+}
+
+// start runs collect in the background and calls done once its result is recorded, unless the
+// operation is already being collected or has a result. It returns true if the collection started.
+func (c *collections) start(uid types.UID, collect func() collectionResult, done func()) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.running[uid]; ok {
+		return false
+	}
+	current := &collection{}
+	c.running[uid] = current
+
+	go func() {
+		result := collect()
+		c.mu.Lock()
+		// The collection may have been forgotten, and even started again, in the meantime.
+		recorded := c.running[uid] == current
+		if recorded {
+			current.result = &result
+		}
+		c.mu.Unlock()
+		if recorded {
+			done()
+		}
+	}()
+	return true
+}
+
+// result returns the result of the collection of the operation and whether it has finished.
+func (c *collections) result(uid types.UID) (collectionResult, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	current, ok := c.running[uid]
+	if !ok || current.result == nil {
+		return collectionResult{}, false
+	}
+	return *current.result, true
+}
+
+// forget drops the result of the collection of the operation. The result of a collection that is
+// still running is discarded.
+func (c *collections) forget(uid types.UID) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.running, uid)
+}
+
+// collectRancherObjects adds the redacted Rancher objects describing the cluster: the management
+// and provisioning clusters, the cluster object resolved by the adapter, and the machines and
+// management nodes of the cluster.
+func (h *handler) collectRancherObjects(b *bundle, s *scope, clusterName string) {
+	b.addObject(path.Join("rancher", strings.ToLower(s.clusterObj.GetKind())+".json"), s.clusterObj)
+
+	if clusterName == "" {
+		return
+	}
+
+	if cluster, err := h.mgmtClusters.Get(clusterName); err != nil {
+		b.errorf("getting management cluster %s: %v", clusterName, err)
+	} else {
+		b.addObject("rancher/management-cluster.json", cluster)
+	}
+
+	if clusters, err := h.provClusters.GetByIndex(provcluster.ByCluster, clusterName); err != nil {
+		b.errorf("getting provisioning cluster of %s: %v", clusterName, err)
+	} else {
+		for _, cluster := range clusters {
+			b.addObject("rancher/provisioning-cluster.json", cluster)
+
+			machines, err := h.machines.List(cluster.Namespace, labels.SelectorFromSet(labels.Set{capi.ClusterNameLabel: cluster.Name}))
+			if err != nil {
+				b.errorf("listing machines of %s/%s: %v", cluster.Namespace, cluster.Name, err)
+				continue
+			}
+			for _, machine := range machines {
+				b.addObject(path.Join("rancher", "machines", machine.Name+".json"), machine)
+			}
+		}
+	}
+
+	nodes, err := h.mgmtNodes.List(clusterName, labels.Everything())
+	if err != nil {
+		b.errorf("listing management nodes of %s: %v", clusterName, err)
+		return
+	}
+	for _, node := range nodes {
+		b.addObject(path.Join("rancher", "nodes", node.Name+".json"), node)
+	}
+}
+
+// saveArchive stores the archive in a Secret owned by the operation and returns its name.
+func (h *handler) saveArchive(op *opv1alpha1.SupportBundle, archive []byte) (string, error) {
+	secretName := name.SafeConcatName(op.Name, "support-bundle")
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      secretName,
+			Namespace: op.Namespace,
+			OwnerReferences: []metav1.OwnerReference{
+				{
+					APIVersion: opv1alpha1.SchemeGroupVersion.String(),
+					Kind:       "SupportBundle",
+					Name:       op.Name,
+					UID:        op.UID,
+					Controller: &[]bool{true}[0],
+				},
+			},
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: map[string][]byte{
+			ArchiveDataKey: archive,
+		},
+	}
+
+	existing, err := h.secrets.Get(op.Namespace, secretName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		_, err = h.secrets.Create(secret)
+		return secretName, err
+	} else if err != nil {
+		return "", err
+	}
+
+	existing = existing.DeepCopy()
+	existing.OwnerReferences = secret.OwnerReferences
+	existing.Type = secret.Type
+	existing.Data = secret.Data
+	_, err = h.secrets.Update(existing)
+	return secretName, err
+}
+
+// managementClusterName returns the name of the management cluster of the referenced cluster, or
+// "" when it has not been created yet.
+func managementClusterName(ref, clusterObj *unstructured.Unstructured) string {
+	switch ref.GroupVersionKind().Group {
+	case "management.cattle.io":
+		return ref.GetName()
+	case "provisioning.cattle.io":
+		clusterName, _, _ := unstructured.NestedString(ref.Object, "status", "clusterName")
+		return clusterName
+	}
+	clusterName, _, _ := unstructured.NestedString(clusterObj.Object, "spec", "managementClusterName")
+	return clusterName
+}
+
+// handleTerminal runs the phase hook of a terminal phase and then releases the beacon, so the
+// next operation in line can acquire it.
+func (h *handler) handleTerminal(s *scope, status opv1alpha1.SupportBundleStatus, cond condition.Cond, prefix string) (opv1alpha1.SupportBundleStatus, error) {
+	logrus.Tracef("[supportbundle] %s/%s: handling operation %s", s.op.Namespace, s.op.Name, status.Phase)
+
+	delegated, err := h.handleHook(s, prefix)
+	if err != nil {
+		return status, err
+	} else if delegated {
+		cond.True(&status)
+		cond.Reason(&status, opv1alpha1.WaitingForDelegateReason)
+		cond.Message(&status, fmt.Sprintf("Waiting for delegates to finish: %v", opv1alpha1.WaitingForDelegateMessage(s.beacon)))
+		return status, nil
+	}
+
+	h.collections.forget(s.op.UID)
+
+	if plan.IsOwningBeaconHolder(s.beacon, s.ownerKey) || plan.IsInDelegateChain(s.beacon, s.ownerKey) {
+		if err := plan.ReleaseBeacon(s.beacon, h.beacons, s.ownerKey); err != nil {
+			return status, err
+		}
+	}
+
+	return status, nil
+}
+
+// updateStatus updates the conditions of the operation based on the current status.
+// This function also updates the ObservedGeneration.
+func updateStatus(op *opv1alpha1.SupportBundle, status opv1alpha1.SupportBundleStatus) opv1alpha1.SupportBundleStatus {
+	status.ObservedGeneration = op.Generation
+	if op.Spec.Paused {
+		opv1alpha1.PausedCondition.True(&status)
+		opv1alpha1.PausedCondition.Reason(&status, opv1alpha1.PausedReason)
+		opv1alpha1.PausedCondition.Message(&status, "Operation is paused")
+	} else {
+		opv1alpha1.PausedCondition.False(&status)
+		opv1alpha1.PausedCondition.Reason(&status, opv1alpha1.NotPausedReason)
+		opv1alpha1.PausedCondition.Message(&status, "")
+	}
+
+	if status.Phase == opv1alpha1.OperationPhasePending {
+		opv1alpha1.PendingCondition.True(&status)
+	} else if status.Phase == opv1alpha1.OperationPhaseInProgress {
+		opv1alpha1.PendingCondition.False(&status)
+		opv1alpha1.PendingCondition.Reason(&status, opv1alpha1.InProgressReason)
+		opv1alpha1.PendingCondition.Message(&status, "Operation now in progress")
+	} else if status.Phase == opv1alpha1.OperationPhaseSucceeded {
+		opv1alpha1.PendingCondition.False(&status)
+		opv1alpha1.PendingCondition.Reason(&status, opv1alpha1.FinishedReason)
+		opv1alpha1.PendingCondition.Message(&status, "Operation completed successfully")
+		opv1alpha1.InProgressCondition.False(&status)
+		opv1alpha1.InProgressCondition.Reason(&status, opv1alpha1.FinishedReason)
+		opv1alpha1.InProgressCondition.Message(&status, "Operation completed successfully")
+		opv1alpha1.FailedCondition.False(&status)
+		opv1alpha1.FailedCondition.Reason(&status, opv1alpha1.NotFailedReason)
+		opv1alpha1.FailedCondition.Message(&status, "Operation completed successfully")
+	} else if status.Phase == opv1alpha1.OperationPhaseFailed {
+		opv1alpha1.PendingCondition.False(&status)
+		opv1alpha1.PendingCondition.Reason(&status, opv1alpha1.FinishedReason)
+		opv1alpha1.PendingCondition.Message(&status, "Operation failed")
+		opv1alpha1.InProgressCondition.False(&status)
+		opv1alpha1.InProgressCondition.Reason(&status, opv1alpha1.FinishedReason)
+		opv1alpha1.InProgressCondition.Message(&status, "Operation failed")
+		opv1alpha1.SucceededCondition.False(&status)
+		opv1alpha1.SucceededCondition.Reason(&status, opv1alpha1.NotSuccessfulReason)
+		opv1alpha1.SucceededCondition.Message(&status, "Operation failed")
+	}
+
+	return status
+}
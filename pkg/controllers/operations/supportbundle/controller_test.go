@@ -0,0 +1,112 @@
+package supportbundle
+
+import (
+	"errors"
+	"testing"
+
+	opv1alpha1 "github.com/rancher/rancher/pkg/apis/operation.cattle.io/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestManagementClusterName(t *testing.T) {
+	controlPlane := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "rke.cattle.io/v1",
+		"kind":       "RKEControlPlane",
+		"metadata":   map[string]any{"name": "test", "namespace": "fleet-default"},
+		"spec":       map[string]any{"managementClusterName": "c-m-fromcp"},
+	}}
+
+	tests := []struct {
+		name string
+		ref  *unstructured.Unstructured
+		want string
+	}{
+		{
+			name: "management cluster",
+			ref: &unstructured.Unstructured{Object: map[string]any{
+				"apiVersion": "management.cattle.io/v3",
+				"kind":       "Cluster",
+				"metadata":   map[string]any{"name": "c-m-abcdef"},
+			}},
+			want: "c-m-abcdef",
+		},
+		{
+			name: "provisioning cluster",
+			ref: &unstructured.Unstructured{Object: map[string]any{
+				"apiVersion": "provisioning.cattle.io/v1",
+				"kind":       "Cluster",
+				"metadata":   map[string]any{"name": "test", "namespace": "fleet-default"},
+				"status":     map[string]any{"clusterName": "c-m-abcdef"},
+			}},
+			want: "c-m-abcdef",
+		},
+		{
+			name: "provisioning cluster without management cluster",
+			ref: &unstructured.Unstructured{Object: map[string]any{
+				"apiVersion": "provisioning.cattle.io/v1",
+				"kind":       "Cluster",
+				"metadata":   map[string]any{"name": "test", "namespace": "fleet-default"},
+			}},
+		},
+		{
+			name: "control plane",
+			ref:  controlPlane,
+			want: "c-m-fromcp",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, managementClusterName(tt.ref, controlPlane))
+		})
+	}
+}
+
+func TestUpdateStatus(t *testing.T) {
+	op := &opv1alpha1.SupportBundle{}
+	op.Generation = 3
+
+	status := updateStatus(op, opv1alpha1.SupportBundleStatus{OperationStatus: opv1alpha1.OperationStatus{Phase: opv1alpha1.OperationPhaseSucceeded}})
+	assert.Equal(t, int64(3), status.ObservedGeneration)
+	assert.True(t, opv1alpha1.PendingCondition.IsFalse(&status))
+	assert.True(t, opv1alpha1.InProgressCondition.IsFalse(&status))
+	assert.True(t, opv1alpha1.FailedCondition.IsFalse(&status))
+	assert.True(t, opv1alpha1.PausedCondition.IsFalse(&status))
+}
+
+func TestCollections(t *testing.T) {
+	c := newCollections()
+	release := make(chan struct{})
+	done := make(chan struct{}, 1)
+	collect := func() collectionResult {
+		<-release
+		return collectionResult{archive: &opv1alpha1.SupportBundleArchive{SecretName: "op-support-bundle"}}
+	}
+
+	assert.True(t, c.start("uid", collect, func() { done <- struct{}{} }))
+	assert.False(t, c.start("uid", collect, func() { done <- struct{}{} }), "a running collection must not start again")
+
+	_, finished := c.result("uid")
+	assert.False(t, finished)
+
+	close(release)
+	<-done
+	result, finished := c.result("uid")
+	require.True(t, finished)
+	assert.Equal(t, "op-support-bundle", result.archive.SecretName)
+	assert.False(t, c.start("uid", collect, func() { done <- struct{}{} }), "a finished collection must not start again")
+
+	// The result of a forgotten collection is discarded.
+	release = make(chan struct{})
+	c.forget("uid")
+	assert.True(t, c.start("uid", func() collectionResult {
+		<-release
+		return collectionResult{err: errors.New("connection refused")}
+	}, func() { done <- struct{}{} }))
+	c.forget("uid")
+	close(release)
+	_, finished = c.result("uid")
+	assert.False(t, finished)
+}
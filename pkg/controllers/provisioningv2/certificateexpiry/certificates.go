@@ -0,0 +1,122 @@
+package certificateexpiry
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	provv1 "github.com/rancher/rancher/pkg/apis/provisioning.cattle.io/v1"
+	"github.com/rancher/rancher/pkg/capr/planner"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// certificate is a certificate printed by the certificate expiry instruction.
+type certificate struct {
+	path     string
+	notAfter time.Time
+}
+
+// parseCertificates parses the output of the certificate expiry instruction, which holds the first PEM block of every
+// certificate file preceded by a line with its path. Files that do not hold a valid certificate are skipped and
+// reported in the returned error.
+func parseCertificates(output []byte) ([]certificate, error) {
+	var (
+		certs   []certificate
+		errs    []string
+		current string
+		block   bytes.Buffer
+	)
+
+	flush := func() {
+		if current == "" {
+			return
+		}
+		p, _ := pem.Decode(block.Bytes())
+		if p == nil {
+			errs = append(errs, fmt.Sprintf("%s: no PEM data", current))
+		} else if cert, err := x509.ParseCertificate(p.Bytes); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", current, err))
+		} else {
+			certs = append(certs, certificate{path: current, notAfter: cert.NotAfter.UTC()})
+		}
+		block.Reset()
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, planner.CertificateExpiryFileMarker) {
+			flush()
+			current = strings.TrimPrefix(line, planner.CertificateExpiryFileMarker)
+			continue
+		}
+		block.WriteString(line)
+		block.WriteByte('\n')
+	}
+	flush()
+
+	if err := scanner.Err(); err != nil {
+		return certs, err
+	}
+	if len(errs) > 0 {
+		return certs, fmt.Errorf("failed to parse certificates: %s", strings.Join(errs, ", "))
+	}
+	return certs, nil
+}
+
+// certificateType returns the type of the certificate at the given path, relative to the data directory. Certificate
+// authorities are not reported, as they are not renewed by a certificate rotation.
+func certificateType(p string) (provv1.CertificateType, bool) {
+	base := path.Base(p)
+	switch {
+	case strings.HasSuffix(base, "-ca.crt"):
+		return "", false
+	case path.Dir(p) == "server/tls/etcd" && strings.HasPrefix(base, "peer-"):
+		return provv1.CertificateTypeEtcdPeer, true
+	case path.Dir(p) == "server/tls/etcd":
+		return provv1.CertificateTypeEtcd, true
+	case strings.Contains(base, "kubelet"):
+		return provv1.CertificateTypeKubelet, true
+	case strings.HasPrefix(base, "serving-"):
+		return provv1.CertificateTypeServing, true
+	case strings.HasPrefix(base, "client-"):
+		return provv1.CertificateTypeClient, true
+	}
+	return "", false
+}
+
+// nodeExpiry returns the first certificate to expire of every type of certificate of a node.
+func nodeExpiry(machineName string, collectedAt *metav1.Time, certs []certificate) provv1.NodeCertificateExpiry {
+	earliest := map[provv1.CertificateType]certificate{}
+	for _, cert := range certs {
+		certType, ok := certificateType(cert.path)
+		if !ok {
+			continue
+		}
+		if existing, ok := earliest[certType]; !ok || cert.notAfter.Before(existing.notAfter) {
+			earliest[certType] = cert
+		}
+	}
+
+	result := provv1.NodeCertificateExpiry{
+		MachineName: machineName,
+		CollectedAt: collectedAt,
+	}
+	for certType, cert := range earliest {
+		result.Certificates = append(result.Certificates, provv1.CertificateExpiration{
+			Type:           certType,
+			Path:           cert.path,
+			ExpirationDate: metav1.NewTime(cert.notAfter),
+		})
+	}
+	sort.Slice(result.Certificates, func(i, j int) bool {
+		return result.Certificates[i].Type < result.Certificates[j].Type
+	})
+	return result
+}
@@ -0,0 +1,160 @@
+package certificateexpiry
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	provv1 "github.com/rancher/rancher/pkg/apis/provisioning.cattle.io/v1"
+	rkev1 "github.com/rancher/rancher/pkg/apis/rke.cattle.io/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func certPEM(t *testing.T, notAfter time.Time) string {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "test"},
+		NotBefore:    notAfter.Add(-365 * 24 * time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+}
+
+func TestParseCertificates(t *testing.T) {
+	first := time.Date(2027, 3, 1, 0, 0, 0, 0, time.UTC)
+	second := time.Date(2027, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	output := "# server/tls/serving-kube-apiserver.crt\n" + certPEM(t, first) +
+		"# server/tls/client-admin.crt\n" + certPEM(t, second)
+	certs, err := parseCertificates([]byte(output))
+	require.NoError(t, err)
+	assert.Equal(t, []certificate{
+		{path: "server/tls/serving-kube-apiserver.crt", notAfter: first},
+		{path: "server/tls/client-admin.crt", notAfter: second},
+	}, certs)
+
+	certs, err = parseCertificates([]byte("# agent/empty.crt\n# agent/client-kubelet.crt\n" + certPEM(t, first)))
+	assert.EqualError(t, err, "failed to parse certificates: agent/empty.crt: no PEM data")
+	assert.Equal(t, []certificate{{path: "agent/client-kubelet.crt", notAfter: first}}, certs)
+
+	certs, err = parseCertificates(nil)
+	require.NoError(t, err)
+	assert.Empty(t, certs)
+}
+
+func TestCertificateType(t *testing.T) {
+	tests := []struct {
+		path     string
+		expected provv1.CertificateType
+		ok       bool
+	}{
+		{path: "server/tls/serving-kube-apiserver.crt", expected: provv1.CertificateTypeServing, ok: true},
+		{path: "server/tls/client-admin.crt", expected: provv1.CertificateTypeClient, ok: true},
+		{path: "server/tls/etcd/server-client.crt", expected: provv1.CertificateTypeEtcd, ok: true},
+		{path: "server/tls/etcd/peer-server-client.crt", expected: provv1.CertificateTypeEtcdPeer, ok: true},
+		{path: "agent/serving-kubelet.crt", expected: provv1.CertificateTypeKubelet, ok: true},
+		{path: "agent/client-kubelet.crt", expected: provv1.CertificateTypeKubelet, ok: true},
+		{path: "server/tls/server-ca.crt"},
+		{path: "server/tls/etcd/peer-ca.crt"},
+		{path: "server/tls/dynamic-cert.crt"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			certType, ok := certificateType(tt.path)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.expected, certType)
+		})
+	}
+}
+
+func TestNodeExpiry(t *testing.T) {
+	collectedAt := &metav1.Time{Time: time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)}
+	early := time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)
+	late := time.Date(2027, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	expiry := nodeExpiry("pool1-abc", collectedAt, []certificate{
+		{path: "server/tls/serving-kube-apiserver.crt", notAfter: late},
+		{path: "server/tls/serving-kube-scheduler.crt", notAfter: early},
+		{path: "server/tls/etcd/peer-server-client.crt", notAfter: late},
+		{path: "server/tls/server-ca.crt", notAfter: early},
+	})
+
+	assert.Equal(t, provv1.NodeCertificateExpiry{
+		MachineName: "pool1-abc",
+		CollectedAt: collectedAt,
+		Certificates: []provv1.CertificateExpiration{
+			{Type: provv1.CertificateTypeEtcdPeer, Path: "server/tls/etcd/peer-server-client.crt", ExpirationDate: metav1.NewTime(late)},
+			{Type: provv1.CertificateTypeServing, Path: "server/tls/serving-kube-scheduler.crt", ExpirationDate: metav1.NewTime(early)},
+		},
+	}, expiry)
+}
+
+func TestShouldRotate(t *testing.T) {
+	now := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	config := &rkev1.CertificateExpiry{Enabled: true, AutoRotateWindowDays: 30}
+	soon := &metav1.Time{Time: now.Add(10 * 24 * time.Hour)}
+	lastRotation := &metav1.Time{Time: now.Add(-time.Hour)}
+	node := func(collectedAt time.Time) provv1.NodeCertificateExpiry {
+		return provv1.NodeCertificateExpiry{MachineName: "pool1-abc", CollectedAt: &metav1.Time{Time: collectedAt}}
+	}
+
+	tests := []struct {
+		name     string
+		config   *rkev1.CertificateExpiry
+		status   *provv1.CertificateExpiryStatus
+		expected bool
+	}{
+		{
+			name:     "expires within the window",
+			config:   config,
+			status:   &provv1.CertificateExpiryStatus{EarliestExpiration: soon, Nodes: []provv1.NodeCertificateExpiry{node(now)}},
+			expected: true,
+		},
+		{
+			name:   "expires after the window",
+			config: config,
+			status: &provv1.CertificateExpiryStatus{EarliestExpiration: &metav1.Time{Time: now.Add(60 * 24 * time.Hour)}, Nodes: []provv1.NodeCertificateExpiry{node(now)}},
+		},
+		{
+			name:   "automatic rotation disabled",
+			config: &rkev1.CertificateExpiry{Enabled: true},
+			status: &provv1.CertificateExpiryStatus{EarliestExpiration: soon, Nodes: []provv1.NodeCertificateExpiry{node(now)}},
+		},
+		{
+			name:   "no certificates collected",
+			config: config,
+			status: &provv1.CertificateExpiryStatus{},
+		},
+		{
+			name:   "nodes not reported since the last rotation",
+			config: config,
+			status: &provv1.CertificateExpiryStatus{EarliestExpiration: soon, LastAutomaticRotation: lastRotation, Nodes: []provv1.NodeCertificateExpiry{node(now), node(now.Add(-2 * time.Hour))}},
+		},
+		{
+			name:     "all nodes reported since the last rotation",
+			config:   config,
+			status:   &provv1.CertificateExpiryStatus{EarliestExpiration: soon, LastAutomaticRotation: lastRotation, Nodes: []provv1.NodeCertificateExpiry{node(now)}},
+			expected: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, shouldRotate(tt.config, tt.status, now))
+		})
+	}
+}
@@ -0,0 +1,195 @@
+// Package certificateexpiry reports the expiration dates of the node certificates of RKE2/K3s clusters, which are
+// collected by a periodic plan instruction, in the status of the provisioning cluster and as metrics. It can also
+// trigger a certificate rotation when a certificate is about to expire.
+package certificateexpiry
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	provv1 "github.com/rancher/rancher/pkg/apis/provisioning.cattle.io/v1"
+	rkev1 "github.com/rancher/rancher/pkg/apis/rke.cattle.io/v1"
+	"github.com/rancher/rancher/pkg/capr"
+	"github.com/rancher/rancher/pkg/capr/planner"
+	provcontrollers "github.com/rancher/rancher/pkg/generated/controllers/provisioning.cattle.io/v1"
+	rkecontrollers "github.com/rancher/rancher/pkg/generated/controllers/rke.cattle.io/v1"
+	"github.com/rancher/rancher/pkg/metrics"
+	"github.com/rancher/rancher/pkg/wrangler"
+	corecontrollers "github.com/rancher/wrangler/v3/pkg/generated/controllers/core/v1"
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// refreshInterval is the interval at which the status is refreshed from the output of the periodic instructions.
+const refreshInterval = 5 * time.Minute
+
+type handler struct {
+	clusters          provcontrollers.ClusterController
+	secretCache       corecontrollers.SecretCache
+	controlPlaneCache rkecontrollers.RKEControlPlaneCache
+	now               func() time.Time
+}
+
+func Register(ctx context.Context, clients *wrangler.CAPIContext) {
+	h := &handler{
+		clusters:          clients.Provisioning.Cluster(),
+		secretCache:       clients.Core.Secret().Cache(),
+		controlPlaneCache: clients.RKE.RKEControlPlane().Cache(),
+		now:               time.Now,
+	}
+	clients.Provisioning.Cluster().OnChange(ctx, "certificate-expiry", h.OnChange)
+}
+
+func (h *handler) OnChange(_ string, cluster *provv1.Cluster) (*provv1.Cluster, error) {
+	if cluster == nil || !cluster.DeletionTimestamp.IsZero() {
+		if cluster != nil {
+			metrics.DeleteCertificateExpiration(cluster.Namespace, cluster.Name)
+		}
+		return cluster, nil
+	}
+
+	if cluster.Spec.RKEConfig == nil || cluster.Spec.RKEConfig.CertificateExpiry == nil || !cluster.Spec.RKEConfig.CertificateExpiry.Enabled {
+		metrics.DeleteCertificateExpiration(cluster.Namespace, cluster.Name)
+		if cluster.Status.CertificateExpiry == nil {
+			return cluster, nil
+		}
+		cluster = cluster.DeepCopy()
+		cluster.Status.CertificateExpiry = nil
+		return h.clusters.UpdateStatus(cluster)
+	}
+
+	status, err := h.collect(cluster)
+	if err != nil {
+		return cluster, err
+	}
+
+	h.clusters.EnqueueAfter(cluster.Namespace, cluster.Name, refreshInterval)
+
+	newCluster := cluster.DeepCopy()
+	newCluster.Status.CertificateExpiry = status
+
+	rotating, err := h.rotationInProgress(cluster)
+	if err != nil {
+		return cluster, err
+	}
+	now := h.now()
+	if !rotating && shouldRotate(cluster.Spec.RKEConfig.CertificateExpiry, status, now) {
+		logrus.Infof("rkecluster %s/%s: rotating certificates as the first certificate expires at %s",
+			cluster.Namespace, cluster.Name, status.EarliestExpiration.UTC().Format(time.RFC3339))
+		rotate := &rkev1.RotateCertificates{Generation: 1}
+		if current := newCluster.Spec.RKEConfig.RotateCertificates; current != nil {
+			rotate.Generation = current.Generation + 1
+		}
+		newCluster.Spec.RKEConfig.RotateCertificates = rotate
+		status.LastAutomaticRotation = &metav1.Time{Time: now}
+
+		if newCluster, err = h.clusters.Update(newCluster); err != nil {
+			return cluster, err
+		}
+		newCluster.Status.CertificateExpiry = status
+	}
+
+	if !equality.Semantic.DeepEqual(cluster.Status.CertificateExpiry, newCluster.Status.CertificateExpiry) {
+		return h.clusters.UpdateStatus(newCluster)
+	}
+	return newCluster, nil
+}
+
+// collect builds the certificate expiry status of the cluster from the periodic output of its machine-plan secrets and
+// records it as metrics.
+func (h *handler) collect(cluster *provv1.Cluster) (*provv1.CertificateExpiryStatus, error) {
+	secrets, err := h.secretCache.List(cluster.Namespace, labels.SelectorFromSet(labels.Set{capr.ClusterNameLabel: cluster.Name}))
+	if err != nil {
+		return nil, err
+	}
+
+	status := &provv1.CertificateExpiryStatus{}
+	if cluster.Status.CertificateExpiry != nil {
+		status.LastAutomaticRotation = cluster.Status.CertificateExpiry.LastAutomaticRotation
+	}
+
+	metrics.DeleteCertificateExpiration(cluster.Namespace, cluster.Name)
+	for _, secret := range secrets {
+		machineName := secret.Labels[capr.MachineNameLabel]
+		if secret.Type != capr.SecretTypeMachinePlan || machineName == "" {
+			continue
+		}
+
+		node, err := planner.SecretToNode(secret)
+		if err != nil {
+			logrus.Debugf("rkecluster %s/%s: failed to read plan of machine %s: %v", cluster.Namespace, cluster.Name, machineName, err)
+			continue
+		}
+		output, ok := node.PeriodicOutput[planner.CertificateExpiryInstructionName]
+		if !ok || output.LastSuccessfulRunTime == "" {
+			continue
+		}
+
+		certs, err := parseCertificates(output.Stdout)
+		if err != nil {
+			logrus.Debugf("rkecluster %s/%s: machine %s: %v", cluster.Namespace, cluster.Name, machineName, err)
+		}
+
+		var collectedAt *metav1.Time
+		if t, err := time.Parse(time.UnixDate, output.LastSuccessfulRunTime); err == nil {
+			collectedAt = &metav1.Time{Time: t}
+		}
+
+		expiry := nodeExpiry(machineName, collectedAt, certs)
+		for _, cert := range expiry.Certificates {
+			metrics.SetCertificateExpiration(cluster.Namespace, cluster.Name, machineName, string(cert.Type), cert.ExpirationDate.Time)
+			if status.EarliestExpiration == nil || cert.ExpirationDate.Before(status.EarliestExpiration) {
+				status.EarliestExpiration = cert.ExpirationDate.DeepCopy()
+			}
+		}
+		status.Nodes = append(status.Nodes, expiry)
+	}
+
+	sort.Slice(status.Nodes, func(i, j int) bool {
+		return status.Nodes[i].MachineName < status.Nodes[j].MachineName
+	})
+	return status, nil
+}
+
+// rotationInProgress returns whether a certificate rotation was requested but not yet completed by the planner.
+func (h *handler) rotationInProgress(cluster *provv1.Cluster) (bool, error) {
+	controlPlane, err := h.controlPlaneCache.Get(cluster.Namespace, cluster.Name)
+	if apierrors.IsNotFound(err) {
+		return true, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	var generation int64
+	if cluster.Spec.RKEConfig.RotateCertificates != nil {
+		generation = cluster.Spec.RKEConfig.RotateCertificates.Generation
+	}
+	return controlPlane.Status.CertificateRotationGeneration != generation, nil
+}
+
+// shouldRotate returns whether a certificate rotation should be triggered because the first certificate of the cluster
+// expires within the configured window. After a rotation was triggered, another one is only considered once every node
+// has reported its certificates again.
+func shouldRotate(config *rkev1.CertificateExpiry, status *provv1.CertificateExpiryStatus, now time.Time) bool {
+	if config.AutoRotateWindowDays == 0 || status.EarliestExpiration == nil || len(status.Nodes) == 0 {
+		return false
+	}
+
+	window := time.Duration(config.AutoRotateWindowDays) * 24 * time.Hour
+	if status.EarliestExpiration.After(now.Add(window)) {
+		return false
+	}
+
+	if last := status.LastAutomaticRotation; last != nil {
+		for _, node := range status.Nodes {
+			if node.CollectedAt == nil || !node.CollectedAt.After(last.Time) {
+				return false
+			}
+		}
+	}
+	return true
+}
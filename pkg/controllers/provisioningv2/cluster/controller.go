@@ -13,6 +13,7 @@
 	v1 "github.com/rancher/rancher/pkg/apis/provisioning.cattle.io/v1"
 	rkev1 "github.com/rancher/rancher/pkg/apis/rke.cattle.io/v1"
 	"github.com/rancher/rancher/pkg/capr"
+	"github.com/rancher/rancher/pkg/controllers/provisioningv2/clustertemplate"
 	"github.com/rancher/rancher/pkg/features"
 	fleetconst "github.com/rancher/rancher/pkg/fleet"
 	capicontrollers "github.com/rancher/rancher/pkg/generated/controllers/cluster.x-k8s.io/v1beta2"
@@ -388,6 +389,12 @@ func (h *handler) generateProvisioningClusterFromLegacyCluster(cluster *v3.Clust
 // generateLegacyClusterFromProvisioningCluster will generate and return a clusters.management.cattle.io/v3 object based
 // on the clusters.provisioning.cattle.io/v1 object passed in.
 func (h *handler) generateLegacyClusterFromProvisioningCluster(cluster *v1.Cluster, status v1.ClusterStatus) ([]runtime.Object, v1.ClusterStatus, error) {
+	if cluster.DeletionTimestamp == nil && !clustertemplate.Applied(cluster) {
+		// Wait for the template to be applied, so that a cluster created from a template isn't imported before it gets
+		// its rkeConfig, and changes to locked fields aren't applied before they are reverted.
+		return nil, status, generic.ErrSkip
+	}
+
 	switch {
 	case cluster.Spec.ClusterAPIConfig != nil:
 		return h.createClusterAndDeployAgent(cluster, status)
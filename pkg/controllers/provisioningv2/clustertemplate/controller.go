@@ -0,0 +1,234 @@
+// Package clustertemplate creates provisioning clusters from the revisions of ClusterTemplates. It keeps the fields
+// locked by the revision of a cluster in sync with the revision, upgrades the cluster when it references another
+// revision, and reports the changes an upgrade to the latest revision would make.
+package clustertemplate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	provv1 "github.com/rancher/rancher/pkg/apis/provisioning.cattle.io/v1"
+	"github.com/rancher/rancher/pkg/capr"
+	provcontrollers "github.com/rancher/rancher/pkg/generated/controllers/provisioning.cattle.io/v1"
+	"github.com/rancher/rancher/pkg/wrangler"
+	"github.com/rancher/wrangler/v3/pkg/relatedresource"
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const byClusterTemplate = "by-cluster-template"
+
+type handler struct {
+	clusters      provcontrollers.ClusterController
+	clusterCache  provcontrollers.ClusterCache
+	templateCache provcontrollers.ClusterTemplateCache
+}
+
+func Register(ctx context.Context, clients *wrangler.CAPIContext) {
+	h := &handler{
+		clusters:      clients.Provisioning.Cluster(),
+		clusterCache:  clients.Provisioning.Cluster().Cache(),
+		templateCache: clients.Provisioning.ClusterTemplate().Cache(),
+	}
+	clients.Provisioning.Cluster().Cache().AddIndexer(byClusterTemplate, byClusterTemplateIndex)
+	clients.Provisioning.Cluster().OnChange(ctx, "cluster-template", h.OnChange)
+	provcontrollers.RegisterClusterTemplateStatusHandler(ctx, clients.Provisioning.ClusterTemplate(), "", "cluster-template-status", h.OnTemplateChange)
+	relatedresource.Watch(ctx, "cluster-template-trigger", h.resolveTemplate, clients.Provisioning.Cluster(), clients.Provisioning.ClusterTemplate())
+}
+
+// Applied returns true if the cluster doesn't reference a cluster template, or if the revision it references was
+// applied to its current generation. Controllers acting on the spec of the cluster wait for it, so that they don't
+// act on a cluster whose template isn't applied yet, or on changes to locked fields that are about to be reverted.
+func Applied(cluster *provv1.Cluster) bool {
+	if cluster.Spec.ClusterTemplate == nil {
+		return true
+	}
+	return cluster.Status.ClusterTemplate != nil && cluster.Status.ClusterTemplate.ObservedGeneration == cluster.Generation
+}
+
+func byClusterTemplateIndex(cluster *provv1.Cluster) ([]string, error) {
+	if cluster.Spec.ClusterTemplate == nil {
+		return nil, nil
+	}
+	return []string{cluster.Namespace + "/" + cluster.Spec.ClusterTemplate.Name}, nil
+}
+
+// resolveTemplate enqueues the clusters created from a template when the template changes.
+func (h *handler) resolveTemplate(namespace, name string, obj runtime.Object) ([]relatedresource.Key, error) {
+	if _, ok := obj.(*provv1.ClusterTemplate); !ok {
+		return nil, nil
+	}
+	clusters, err := h.clusterCache.GetByIndex(byClusterTemplate, namespace+"/"+name)
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]relatedresource.Key, 0, len(clusters))
+	for _, cluster := range clusters {
+		keys = append(keys, relatedresource.Key{Namespace: cluster.Namespace, Name: cluster.Name})
+	}
+	return keys, nil
+}
+
+// OnTemplateChange validates the revisions of the template and reports its latest revision.
+func (h *handler) OnTemplateChange(template *provv1.ClusterTemplate, status provv1.ClusterTemplateStatus) (provv1.ClusterTemplateStatus, error) {
+	if template == nil || !template.DeletionTimestamp.IsZero() {
+		return status, nil
+	}
+
+	var errs []error
+	for i := range template.Spec.Revisions {
+		revision := &template.Spec.Revisions[i]
+		if err := validateRevision(revision); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		digests, err := recordDigest(status.RevisionDigests, revision)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		status.RevisionDigests = digests
+	}
+	status.LatestRevision = 0
+	if latest := latestRevision(template); latest != nil {
+		status.LatestRevision = latest.Revision
+	}
+	status.ObservedGeneration = template.Generation
+	capr.ClusterTemplateValid.SetError(&status, "", errors.Join(errs...))
+	return status, nil
+}
+
+func (h *handler) OnChange(_ string, cluster *provv1.Cluster) (*provv1.Cluster, error) {
+	if cluster == nil || !cluster.DeletionTimestamp.IsZero() {
+		return cluster, nil
+	}
+
+	newCluster := cluster.DeepCopy()
+	applied := false
+	if cluster.Spec.ClusterTemplate == nil {
+		newCluster.Status.ClusterTemplate = nil
+	} else {
+		template, err := h.templateCache.Get(cluster.Namespace, cluster.Spec.ClusterTemplate.Name)
+		if apierrors.IsNotFound(err) {
+			err = fmt.Errorf("cluster template %s/%s not found", cluster.Namespace, cluster.Spec.ClusterTemplate.Name)
+		} else if err != nil {
+			return cluster, err
+		}
+		if err == nil {
+			var spec provv1.ClusterSpec
+			var status *provv1.ClusterTemplateRevisionStatus
+			spec, status, err = applyTemplate(cluster, template)
+			if err == nil {
+				if !equality.Semantic.DeepEqual(cluster.Spec, spec) {
+					logrus.Infof("[cluster-template] cluster %s/%s: applying revision %d of cluster template %s",
+						cluster.Namespace, cluster.Name, cluster.Spec.ClusterTemplate.Revision, template.Name)
+				}
+				newCluster.Spec = spec
+				newCluster.Status.ClusterTemplate = status
+				applied = true
+			}
+		}
+		capr.ClusterTemplateApplied.SetError(newCluster, "", err)
+	}
+
+	var err error
+	if !equality.Semantic.DeepEqual(cluster.Spec, newCluster.Spec) {
+		status := newCluster.Status
+		if newCluster, err = h.clusters.Update(newCluster); err != nil {
+			return cluster, err
+		}
+		newCluster.Status = status
+	}
+	if applied {
+		// Reverting changes to locked fields updates the generation, so the revision is applied to the generation
+		// of the updated cluster.
+		newCluster.Status.ClusterTemplate.ObservedGeneration = newCluster.Generation
+	}
+	if !equality.Semantic.DeepEqual(cluster.Status, newCluster.Status) {
+		return h.clusters.UpdateStatus(newCluster)
+	}
+	return newCluster, nil
+}
+
+// applyTemplate returns the spec of the cluster with the template revision it references applied, and the status of
+// the revision.
+//
+// A cluster created from the template gets the fields of the revision it does not set itself. A cluster moving to
+// another revision gets the fields that differ between its previous and new revision, keeping the fields it changed
+// itself. In both cases, the locked fields and the fields of the parameters are set to their value in the revision.
+func applyTemplate(cluster *provv1.Cluster, template *provv1.ClusterTemplate) (provv1.ClusterSpec, *provv1.ClusterTemplateRevisionStatus, error) {
+	ref := cluster.Spec.ClusterTemplate
+	revision := findRevision(template, ref.Revision)
+	if revision == nil {
+		return cluster.Spec, nil, fmt.Errorf("revision %d of cluster template %s/%s not found", ref.Revision, template.Namespace, template.Name)
+	}
+	if err := validateRevision(revision); err != nil {
+		return cluster.Spec, nil, err
+	}
+	if err := checkDigest(template, revision); err != nil {
+		return cluster.Spec, nil, err
+	}
+	rendered, err := renderRevision(revision, ref.Values)
+	if err != nil {
+		return cluster.Spec, nil, err
+	}
+	current, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&cluster.Spec)
+	if err != nil {
+		return cluster.Spec, nil, err
+	}
+
+	var appliedRevision int
+	if cluster.Status.ClusterTemplate != nil {
+		appliedRevision = cluster.Status.ClusterTemplate.AppliedRevision
+	}
+	var spec map[string]interface{}
+	if previous := findRevision(template, appliedRevision); appliedRevision == ref.Revision {
+		spec = runtime.DeepCopyJSON(current)
+	} else if previous == nil {
+		spec = merge(rendered, current)
+	} else {
+		previousRendered, err := renderRevision(previous, ref.Values)
+		if err != nil {
+			return cluster.Spec, nil, err
+		}
+		spec = runtime.DeepCopyJSON(current)
+		applyChanges(spec, previousRendered, rendered)
+	}
+	enforce(spec, revision, rendered)
+
+	var result provv1.ClusterSpec
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(spec, &result); err != nil {
+		return cluster.Spec, nil, err
+	}
+
+	status := &provv1.ClusterTemplateRevisionStatus{
+		AppliedRevision: ref.Revision,
+		LatestRevision:  ref.Revision,
+	}
+	if latest := latestRevision(template); latest != nil && latest.Revision > ref.Revision {
+		status.LatestRevision = latest.Revision
+		changes, err := upgradeChanges(spec, rendered, latest, ref.Values)
+		if err != nil {
+			logrus.Debugf("[cluster-template] cluster %s/%s: unable to compute the changes of revision %d of cluster template %s: %v",
+				cluster.Namespace, cluster.Name, latest.Revision, template.Name, err)
+		}
+		status.UpgradeChanges = changes
+	}
+	return result, status, nil
+}
+
+// upgradeChanges returns the changes to spec that upgrading from the rendered revision to the latest revision would
+// make.
+func upgradeChanges(spec, rendered map[string]interface{}, latest *provv1.ClusterTemplateRevision, values map[string]string) ([]provv1.ClusterTemplateFieldChange, error) {
+	latestRendered, err := renderRevision(latest, values)
+	if err != nil {
+		return nil, err
+	}
+	upgraded := runtime.DeepCopyJSON(spec)
+	applyChanges(upgraded, rendered, latestRendered)
+	enforce(upgraded, latest, latestRendered)
+	return diff(spec, upgraded)
+}
@@ -0,0 +1,311 @@
+package clustertemplate
+
+import (
+	"testing"
+
+	provv1 "github.com/rancher/rancher/pkg/apis/provisioning.cattle.io/v1"
+	rkev1 "github.com/rancher/rancher/pkg/apis/rke.cattle.io/v1"
+	"github.com/rancher/rancher/pkg/capr"
+	"github.com/rancher/wrangler/v3/pkg/generic/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func newTemplate() *provv1.ClusterTemplate {
+	revision := func(revision int, kubernetesVersion, psact string) provv1.ClusterTemplateRevision {
+		return provv1.ClusterTemplateRevision{
+			Revision: revision,
+			ClusterSpec: provv1.ClusterSpec{
+				KubernetesVersion: kubernetesVersion,
+				DefaultPodSecurityAdmissionConfigurationTemplateName: psact,
+				RKEConfig: &provv1.RKEConfig{
+					ClusterConfiguration: rkev1.ClusterConfiguration{
+						Registries: &rkev1.Registry{Mirrors: map[string]rkev1.Mirror{
+							"docker.io": {Endpoints: []string{"https://registry.example.com"}},
+						}},
+					},
+				},
+			},
+			Parameters: []provv1.ClusterTemplateParameter{{
+				Name:    "cloudCredential",
+				Path:    "cloudCredentialSecretName",
+				Default: "cattle-global-data:cc-default",
+			}},
+			LockedFields: []string{"rkeConfig.registries", "defaultPodSecurityAdmissionConfigurationTemplateName"},
+		}
+	}
+	return validated(&provv1.ClusterTemplate{
+		ObjectMeta: metav1.ObjectMeta{Name: "standard", Namespace: "fleet-default"},
+		Spec: provv1.ClusterTemplateSpec{
+			Revisions: []provv1.ClusterTemplateRevision{
+				revision(1, "v1.32.4+rke2r1", "rancher-restricted"),
+				revision(2, "v1.33.1+rke2r1", "rancher-restricted"),
+			},
+		},
+	})
+}
+
+// validated records the digests of the revisions of the template, as the template controller does.
+func validated(template *provv1.ClusterTemplate) *provv1.ClusterTemplate {
+	template.Status.RevisionDigests = nil
+	for i := range template.Spec.Revisions {
+		template.Status.RevisionDigests, _ = recordDigest(template.Status.RevisionDigests, &template.Spec.Revisions[i])
+	}
+	return template
+}
+
+func newCluster(revision, appliedRevision int) *provv1.Cluster {
+	cluster := &provv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "c1", Namespace: "fleet-default"},
+		Spec: provv1.ClusterSpec{
+			ClusterTemplate: &provv1.ClusterTemplateReference{Name: "standard", Revision: revision},
+		},
+	}
+	if appliedRevision != 0 {
+		cluster.Status.ClusterTemplate = &provv1.ClusterTemplateRevisionStatus{AppliedRevision: appliedRevision}
+	}
+	return cluster
+}
+
+func TestApplyTemplate(t *testing.T) {
+	t.Run("create from revision", func(t *testing.T) {
+		cluster := newCluster(1, 0)
+		cluster.Spec.KubernetesVersion = "v1.32.5+rke2r1"
+
+		spec, status, err := applyTemplate(cluster, newTemplate())
+		require.NoError(t, err)
+		assert.Equal(t, "v1.32.5+rke2r1", spec.KubernetesVersion, "fields set on the cluster take precedence")
+		assert.Equal(t, "rancher-restricted", spec.DefaultPodSecurityAdmissionConfigurationTemplateName)
+		assert.Equal(t, "cattle-global-data:cc-default", spec.CloudCredentialSecretName)
+		require.NotNil(t, spec.RKEConfig.Registries)
+		assert.Contains(t, spec.RKEConfig.Registries.Mirrors, "docker.io")
+		assert.Equal(t, cluster.Spec.ClusterTemplate, spec.ClusterTemplate)
+
+		assert.Equal(t, 1, status.AppliedRevision)
+		assert.Equal(t, 2, status.LatestRevision)
+		assert.Equal(t, []provv1.ClusterTemplateFieldChange{{
+			Path:    "kubernetesVersion",
+			Current: `"v1.32.5+rke2r1"`,
+			Desired: `"v1.33.1+rke2r1"`,
+		}}, status.UpgradeChanges)
+	})
+
+	t.Run("locked fields are reverted", func(t *testing.T) {
+		cluster := newCluster(1, 1)
+		cluster.Spec.KubernetesVersion = "v1.32.5+rke2r1"
+		cluster.Spec.DefaultPodSecurityAdmissionConfigurationTemplateName = "rancher-privileged"
+
+		spec, _, err := applyTemplate(cluster, newTemplate())
+		require.NoError(t, err)
+		assert.Equal(t, "v1.32.5+rke2r1", spec.KubernetesVersion)
+		assert.Equal(t, "rancher-restricted", spec.DefaultPodSecurityAdmissionConfigurationTemplateName)
+		require.NotNil(t, spec.RKEConfig.Registries)
+		assert.Contains(t, spec.RKEConfig.Registries.Mirrors, "docker.io")
+	})
+
+	t.Run("parameter values", func(t *testing.T) {
+		cluster := newCluster(2, 2)
+		cluster.Spec.ClusterTemplate.Values = map[string]string{"cloudCredential": "cattle-global-data:cc-abc"}
+		cluster.Spec.CloudCredentialSecretName = "cattle-global-data:cc-other"
+
+		spec, status, err := applyTemplate(cluster, newTemplate())
+		require.NoError(t, err)
+		assert.Equal(t, "cattle-global-data:cc-abc", spec.CloudCredentialSecretName)
+		assert.Empty(t, status.UpgradeChanges)
+	})
+
+	t.Run("upgrade keeps cluster changes", func(t *testing.T) {
+		template := newTemplate()
+		template.Spec.Revisions[1].ClusterSpec.KubernetesVersion = template.Spec.Revisions[0].ClusterSpec.KubernetesVersion
+		template.Spec.Revisions[1].ClusterSpec.DefaultPodSecurityAdmissionConfigurationTemplateName = "rancher-baseline"
+		cluster := newCluster(2, 1)
+		cluster.Spec.KubernetesVersion = "v1.32.5+rke2r1"
+
+		spec, status, err := applyTemplate(cluster, validated(template))
+		require.NoError(t, err)
+		assert.Equal(t, "v1.32.5+rke2r1", spec.KubernetesVersion, "fields unchanged between revisions are kept")
+		assert.Equal(t, "rancher-baseline", spec.DefaultPodSecurityAdmissionConfigurationTemplateName)
+		assert.Equal(t, 2, status.AppliedRevision)
+	})
+
+	t.Run("upgrade applies changed fields", func(t *testing.T) {
+		cluster := newCluster(2, 1)
+		cluster.Spec.KubernetesVersion = "v1.32.4+rke2r1"
+
+		spec, _, err := applyTemplate(cluster, newTemplate())
+		require.NoError(t, err)
+		assert.Equal(t, "v1.33.1+rke2r1", spec.KubernetesVersion)
+	})
+
+	t.Run("missing revision", func(t *testing.T) {
+		_, _, err := applyTemplate(newCluster(3, 0), newTemplate())
+		assert.ErrorContains(t, err, "revision 3 of cluster template fleet-default/standard not found")
+	})
+
+	t.Run("missing required parameter", func(t *testing.T) {
+		template := newTemplate()
+		template.Spec.Revisions[0].Parameters[0].Default = ""
+		template.Spec.Revisions[0].Parameters[0].Required = true
+
+		_, _, err := applyTemplate(newCluster(1, 0), validated(template))
+		assert.ErrorContains(t, err, "a value is required for parameter cloudCredential of revision 1")
+	})
+
+	t.Run("revision changed after it was created", func(t *testing.T) {
+		template := newTemplate()
+		template.Spec.Revisions[0].LockedFields = nil
+
+		_, _, err := applyTemplate(newCluster(1, 1), template)
+		assert.ErrorContains(t, err, "revision 1 of cluster template fleet-default/standard was changed after it was created")
+	})
+
+	t.Run("revision not validated yet", func(t *testing.T) {
+		template := newTemplate()
+		template.Status.RevisionDigests = nil
+
+		_, _, err := applyTemplate(newCluster(1, 0), template)
+		assert.ErrorContains(t, err, "revision 1 of cluster template fleet-default/standard is not validated yet")
+	})
+}
+
+func TestOnChange(t *testing.T) {
+	t.Run("applies revision", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		clusters := fake.NewMockControllerInterface[*provv1.Cluster, *provv1.ClusterList](ctrl)
+		templates := fake.NewMockCacheInterface[*provv1.ClusterTemplate](ctrl)
+		templates.EXPECT().Get("fleet-default", "standard").Return(newTemplate(), nil)
+		clusters.EXPECT().Update(gomock.Any()).DoAndReturn(func(cluster *provv1.Cluster) (*provv1.Cluster, error) {
+			assert.Equal(t, "v1.32.4+rke2r1", cluster.Spec.KubernetesVersion)
+			updated := cluster.DeepCopy()
+			updated.Generation++
+			return updated, nil
+		})
+		clusters.EXPECT().UpdateStatus(gomock.Any()).DoAndReturn(func(cluster *provv1.Cluster) (*provv1.Cluster, error) {
+			assert.Equal(t, 1, cluster.Status.ClusterTemplate.AppliedRevision)
+			assert.Equal(t, int64(2), cluster.Status.ClusterTemplate.ObservedGeneration)
+			assert.Equal(t, "True", capr.ClusterTemplateApplied.GetStatus(cluster))
+			return cluster, nil
+		})
+
+		cluster := newCluster(1, 0)
+		cluster.Generation = 1
+		h := &handler{clusters: clusters, templateCache: templates}
+		_, err := h.OnChange("", cluster)
+		require.NoError(t, err)
+	})
+
+	t.Run("revision changed after it was created", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		clusters := fake.NewMockControllerInterface[*provv1.Cluster, *provv1.ClusterList](ctrl)
+		templates := fake.NewMockCacheInterface[*provv1.ClusterTemplate](ctrl)
+		template := newTemplate()
+		template.Spec.Revisions[0].LockedFields = nil
+		templates.EXPECT().Get("fleet-default", "standard").Return(template, nil)
+		clusters.EXPECT().UpdateStatus(gomock.Any()).DoAndReturn(func(cluster *provv1.Cluster) (*provv1.Cluster, error) {
+			assert.Equal(t, int64(2), cluster.Status.ClusterTemplate.ObservedGeneration, "the revision isn't applied to the new generation")
+			assert.Equal(t, "False", capr.ClusterTemplateApplied.GetStatus(cluster))
+			return cluster, nil
+		})
+
+		cluster := newCluster(1, 1)
+		cluster.Generation = 3
+		cluster.Status.ClusterTemplate.ObservedGeneration = 2
+		h := &handler{clusters: clusters, templateCache: templates}
+		_, err := h.OnChange("", cluster)
+		require.NoError(t, err)
+	})
+
+	t.Run("template not found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		clusters := fake.NewMockControllerInterface[*provv1.Cluster, *provv1.ClusterList](ctrl)
+		templates := fake.NewMockCacheInterface[*provv1.ClusterTemplate](ctrl)
+		templates.EXPECT().Get("fleet-default", "standard").Return(nil, apierrors.NewNotFound(schema.GroupResource{}, "standard"))
+		clusters.EXPECT().UpdateStatus(gomock.Any()).DoAndReturn(func(cluster *provv1.Cluster) (*provv1.Cluster, error) {
+			assert.Equal(t, "False", capr.ClusterTemplateApplied.GetStatus(cluster))
+			assert.Equal(t, "cluster template fleet-default/standard not found", capr.ClusterTemplateApplied.GetMessage(cluster))
+			return cluster, nil
+		})
+
+		h := &handler{clusters: clusters, templateCache: templates}
+		_, err := h.OnChange("", newCluster(1, 0))
+		require.NoError(t, err)
+	})
+
+	t.Run("detached from template", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		clusters := fake.NewMockControllerInterface[*provv1.Cluster, *provv1.ClusterList](ctrl)
+		clusters.EXPECT().UpdateStatus(gomock.Any()).DoAndReturn(func(cluster *provv1.Cluster) (*provv1.Cluster, error) {
+			assert.Nil(t, cluster.Status.ClusterTemplate)
+			return cluster, nil
+		})
+
+		cluster := newCluster(1, 1)
+		cluster.Spec.ClusterTemplate = nil
+		h := &handler{clusters: clusters}
+		_, err := h.OnChange("", cluster)
+		require.NoError(t, err)
+	})
+}
+
+func TestOnTemplateChange(t *testing.T) {
+	t.Run("invalid revision", func(t *testing.T) {
+		template := newTemplate()
+		template.Status.RevisionDigests = nil
+		template.Spec.Revisions[0].LockedFields = append(template.Spec.Revisions[0].LockedFields, "clusterTemplate.name")
+
+		h := &handler{}
+		status, err := h.OnTemplateChange(template, template.Status)
+		require.NoError(t, err)
+		assert.Equal(t, 2, status.LatestRevision)
+		assert.Equal(t, string(corev1.ConditionFalse), capr.ClusterTemplateValid.GetStatus(&status))
+		assert.Contains(t, capr.ClusterTemplateValid.GetMessage(&status), `revision 1: locked field: path "clusterTemplate.name" references the cluster template itself`)
+		require.Len(t, status.RevisionDigests, 1, "only valid revisions are recorded")
+		assert.Equal(t, 2, status.RevisionDigests[0].Revision)
+	})
+
+	t.Run("records digests", func(t *testing.T) {
+		template := newTemplate()
+		digests := template.Status.RevisionDigests
+		template.Status.RevisionDigests = nil
+
+		h := &handler{}
+		status, err := h.OnTemplateChange(template, template.Status)
+		require.NoError(t, err)
+		assert.Equal(t, string(corev1.ConditionTrue), capr.ClusterTemplateValid.GetStatus(&status))
+		assert.Equal(t, digests, status.RevisionDigests)
+	})
+
+	t.Run("revision changed after it was created", func(t *testing.T) {
+		template := newTemplate()
+		digests := template.Status.RevisionDigests
+		template.Spec.Revisions = template.Spec.Revisions[1:]
+		template.Spec.Revisions[0].LockedFields = nil
+
+		h := &handler{}
+		status, err := h.OnTemplateChange(template, template.Status)
+		require.NoError(t, err)
+		assert.Equal(t, string(corev1.ConditionFalse), capr.ClusterTemplateValid.GetStatus(&status))
+		assert.Contains(t, capr.ClusterTemplateValid.GetMessage(&status), "revision 2 was changed after it was created, add a new revision instead")
+		assert.Equal(t, digests, status.RevisionDigests, "digests of changed and removed revisions are kept")
+	})
+}
+
+func TestApplied(t *testing.T) {
+	cluster := newCluster(1, 1)
+	cluster.Generation = 2
+	assert.False(t, Applied(cluster))
+
+	cluster.Status.ClusterTemplate.ObservedGeneration = 2
+	assert.True(t, Applied(cluster))
+
+	cluster = newCluster(1, 0)
+	assert.False(t, Applied(cluster))
+
+	cluster.Spec.ClusterTemplate = nil
+	assert.True(t, Applied(cluster))
+}
@@ -0,0 +1,326 @@
+package clustertemplate
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	provv1 "github.com/rancher/rancher/pkg/apis/provisioning.cattle.io/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// templateField is the field of the cluster spec that references the template. It is never taken from a revision.
+const templateField = "clusterTemplate"
+
+// findRevision returns the revision of the template with the given number, or nil if there is none.
+func findRevision(template *provv1.ClusterTemplate, revision int) *provv1.ClusterTemplateRevision {
+	for i := range template.Spec.Revisions {
+		if template.Spec.Revisions[i].Revision == revision {
+			return &template.Spec.Revisions[i]
+		}
+	}
+	return nil
+}
+
+// latestRevision returns the highest revision of the template, or nil if it has none.
+func latestRevision(template *provv1.ClusterTemplate) *provv1.ClusterTemplateRevision {
+	var latest *provv1.ClusterTemplateRevision
+	for i := range template.Spec.Revisions {
+		if latest == nil || template.Spec.Revisions[i].Revision > latest.Revision {
+			latest = &template.Spec.Revisions[i]
+		}
+	}
+	return latest
+}
+
+// revisionDigest returns the SHA-256 digest of the revision.
+func revisionDigest(revision *provv1.ClusterTemplateRevision) (string, error) {
+	b, err := json.Marshal(revision)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// recordDigest returns the digests with the digest of the revision added if it has none yet. It returns an error if
+// the revision doesn't match the digest recorded for it, as revisions must not change once created.
+func recordDigest(digests []provv1.ClusterTemplateRevisionDigest, revision *provv1.ClusterTemplateRevision) ([]provv1.ClusterTemplateRevisionDigest, error) {
+	digest, err := revisionDigest(revision)
+	if err != nil {
+		return digests, err
+	}
+	for _, recorded := range digests {
+		if recorded.Revision == revision.Revision {
+			if recorded.Digest != digest {
+				return digests, fmt.Errorf("revision %d was changed after it was created, add a new revision instead", revision.Revision)
+			}
+			return digests, nil
+		}
+	}
+	// Clip, so that the digests of the cached template aren't changed in place.
+	return append(slices.Clip(digests), provv1.ClusterTemplateRevisionDigest{Revision: revision.Revision, Digest: digest}), nil
+}
+
+// checkDigest returns an error if the revision doesn't match the digest recorded for it in the status of the template,
+// or if it has none yet.
+func checkDigest(template *provv1.ClusterTemplate, revision *provv1.ClusterTemplateRevision) error {
+	digest, err := revisionDigest(revision)
+	if err != nil {
+		return err
+	}
+	for _, recorded := range template.Status.RevisionDigests {
+		if recorded.Revision == revision.Revision {
+			if recorded.Digest != digest {
+				return fmt.Errorf("revision %d of cluster template %s/%s was changed after it was created", revision.Revision, template.Namespace, template.Name)
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("revision %d of cluster template %s/%s is not validated yet", revision.Revision, template.Namespace, template.Name)
+}
+
+// validateRevision returns an error if the parameters or locked fields of the revision are invalid.
+func validateRevision(revision *provv1.ClusterTemplateRevision) error {
+	for _, param := range revision.Parameters {
+		if err := validatePath(param.Path); err != nil {
+			return fmt.Errorf("revision %d: parameter %s: %w", revision.Revision, param.Name, err)
+		}
+		if param.Default != "" {
+			if _, err := parseValue(param, param.Default); err != nil {
+				return fmt.Errorf("revision %d: parameter %s: invalid default: %w", revision.Revision, param.Name, err)
+			}
+		}
+	}
+	for _, path := range revision.LockedFields {
+		if err := validatePath(path); err != nil {
+			return fmt.Errorf("revision %d: locked field: %w", revision.Revision, err)
+		}
+	}
+	return nil
+}
+
+func validatePath(path string) error {
+	if path == "" {
+		return fmt.Errorf("path must not be empty")
+	}
+	for _, key := range strings.Split(path, ".") {
+		if key == "" {
+			return fmt.Errorf("path %q has an empty field name", path)
+		}
+	}
+	if strings.Split(path, ".")[0] == templateField {
+		return fmt.Errorf("path %q references the cluster template itself", path)
+	}
+	return nil
+}
+
+// renderRevision returns the cluster spec of the revision as an unstructured object, with the fields of the parameters
+// set from the given values.
+func renderRevision(revision *provv1.ClusterTemplateRevision, values map[string]string) (map[string]interface{}, error) {
+	spec, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&revision.ClusterSpec)
+	if err != nil {
+		return nil, err
+	}
+	delete(spec, templateField)
+
+	for _, param := range revision.Parameters {
+		value := values[param.Name]
+		if value == "" {
+			value = param.Default
+		}
+		if value == "" {
+			if param.Required {
+				return nil, fmt.Errorf("a value is required for parameter %s of revision %d", param.Name, revision.Revision)
+			}
+			continue
+		}
+		parsed, err := parseValue(param, value)
+		if err != nil {
+			return nil, fmt.Errorf("parameter %s of revision %d: %w", param.Name, revision.Revision, err)
+		}
+		setPath(spec, param.Path, parsed)
+	}
+	return spec, nil
+}
+
+// parseValue converts the value given for the parameter to its type.
+func parseValue(param provv1.ClusterTemplateParameter, value string) (interface{}, error) {
+	switch param.Type {
+	case "", provv1.ClusterTemplateParameterTypeString:
+		return value, nil
+	case provv1.ClusterTemplateParameterTypeInt:
+		return strconv.ParseInt(value, 10, 64)
+	case provv1.ClusterTemplateParameterTypeBoolean:
+		return strconv.ParseBool(value)
+	default:
+		return nil, fmt.Errorf("unknown parameter type %q", param.Type)
+	}
+}
+
+// enforce sets the locked fields and the fields of the parameters of the revision in spec to their value in the
+// rendered revision.
+func enforce(spec map[string]interface{}, revision *provv1.ClusterTemplateRevision, rendered map[string]interface{}) {
+	paths := append([]string{}, revision.LockedFields...)
+	for _, param := range revision.Parameters {
+		paths = append(paths, param.Path)
+	}
+	for _, path := range paths {
+		if value, ok := getPath(rendered, path); ok {
+			setPath(spec, path, runtime.DeepCopyJSONValue(value))
+		} else {
+			deletePath(spec, path)
+		}
+	}
+}
+
+// merge returns the fields of override set over the fields of base, merging nested objects.
+func merge(base, override map[string]interface{}) map[string]interface{} {
+	result := runtime.DeepCopyJSON(base)
+	for key, value := range override {
+		baseObj, baseIsObj := result[key].(map[string]interface{})
+		obj, isObj := value.(map[string]interface{})
+		if baseIsObj && isObj {
+			result[key] = merge(baseObj, obj)
+			continue
+		}
+		result[key] = runtime.DeepCopyJSONValue(value)
+	}
+	return result
+}
+
+// applyChanges applies to dst the fields that differ between the from and to revisions, leaving the other fields of
+// dst untouched.
+func applyChanges(dst, from, to map[string]interface{}) {
+	for _, key := range keys(from, to) {
+		fromValue, inFrom := from[key]
+		toValue, inTo := to[key]
+		if inFrom == inTo && equality.Semantic.DeepEqual(fromValue, toValue) {
+			continue
+		}
+		fromObj, fromIsObj := fromValue.(map[string]interface{})
+		toObj, toIsObj := toValue.(map[string]interface{})
+		if fromIsObj && toIsObj {
+			dstObj, ok := dst[key].(map[string]interface{})
+			if !ok {
+				dstObj = map[string]interface{}{}
+				dst[key] = dstObj
+			}
+			applyChanges(dstObj, fromObj, toObj)
+			continue
+		}
+		if inTo {
+			dst[key] = runtime.DeepCopyJSONValue(toValue)
+		} else {
+			delete(dst, key)
+		}
+	}
+}
+
+// diff returns the changes that turn current into desired, one per leaf field, sorted by path.
+func diff(current, desired map[string]interface{}) ([]provv1.ClusterTemplateFieldChange, error) {
+	var changes []provv1.ClusterTemplateFieldChange
+	if err := diffObjects("", current, desired, &changes); err != nil {
+		return nil, err
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Path < changes[j].Path
+	})
+	return changes, nil
+}
+
+func diffObjects(prefix string, current, desired map[string]interface{}, changes *[]provv1.ClusterTemplateFieldChange) error {
+	for _, key := range keys(current, desired) {
+		currentValue, inCurrent := current[key]
+		desiredValue, inDesired := desired[key]
+		if inCurrent == inDesired && equality.Semantic.DeepEqual(currentValue, desiredValue) {
+			continue
+		}
+		path := prefix + key
+		currentObj, currentIsObj := currentValue.(map[string]interface{})
+		desiredObj, desiredIsObj := desiredValue.(map[string]interface{})
+		if currentIsObj && desiredIsObj {
+			if err := diffObjects(path+".", currentObj, desiredObj, changes); err != nil {
+				return err
+			}
+			continue
+		}
+
+		change := provv1.ClusterTemplateFieldChange{Path: path}
+		if inCurrent {
+			b, err := json.Marshal(currentValue)
+			if err != nil {
+				return err
+			}
+			change.Current = string(b)
+		}
+		if inDesired {
+			b, err := json.Marshal(desiredValue)
+			if err != nil {
+				return err
+			}
+			change.Desired = string(b)
+		}
+		*changes = append(*changes, change)
+	}
+	return nil
+}
+
+// keys returns the union of the keys of the objects.
+func keys(objs ...map[string]interface{}) []string {
+	seen := map[string]bool{}
+	var result []string
+	for _, obj := range objs {
+		for key := range obj {
+			if !seen[key] {
+				seen[key] = true
+				result = append(result, key)
+			}
+		}
+	}
+	return result
+}
+
+func getPath(obj map[string]interface{}, path string) (interface{}, bool) {
+	fields := strings.Split(path, ".")
+	for _, field := range fields[:len(fields)-1] {
+		next, ok := obj[field].(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		obj = next
+	}
+	value, ok := obj[fields[len(fields)-1]]
+	return value, ok
+}
+
+func setPath(obj map[string]interface{}, path string, value interface{}) {
+	fields := strings.Split(path, ".")
+	for _, field := range fields[:len(fields)-1] {
+		next, ok := obj[field].(map[string]interface{})
+		if !ok {
+			next = map[string]interface{}{}
+			obj[field] = next
+		}
+		obj = next
+	}
+	obj[fields[len(fields)-1]] = value
+}
+
+func deletePath(obj map[string]interface{}, path string) {
+	fields := strings.Split(path, ".")
+	for _, field := range fields[:len(fields)-1] {
+		next, ok := obj[field].(map[string]interface{})
+		if !ok {
+			return
+		}
+		obj = next
+	}
+	delete(obj, fields[len(fields)-1])
+}
@@ -0,0 +1,106 @@
+package clustertemplate
+
+import (
+	"testing"
+
+	provv1 "github.com/rancher/rancher/pkg/apis/provisioning.cattle.io/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseValue(t *testing.T) {
+	tests := []struct {
+		name      string
+		paramType provv1.ClusterTemplateParameterType
+		value     string
+		expected  interface{}
+		wantErr   bool
+	}{
+		{name: "default type", value: "v1.33.1+rke2r1", expected: "v1.33.1+rke2r1"},
+		{name: "int", paramType: provv1.ClusterTemplateParameterTypeInt, value: "3", expected: int64(3)},
+		{name: "invalid int", paramType: provv1.ClusterTemplateParameterTypeInt, value: "three", wantErr: true},
+		{name: "boolean", paramType: provv1.ClusterTemplateParameterTypeBoolean, value: "true", expected: true},
+		{name: "unknown type", paramType: "float", value: "1.5", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, err := parseValue(provv1.ClusterTemplateParameter{Type: tt.paramType}, tt.value)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, value)
+		})
+	}
+}
+
+func TestApplyChanges(t *testing.T) {
+	dst := map[string]interface{}{
+		"kubernetesVersion": "v1.32.5",
+		"rkeConfig": map[string]interface{}{
+			"etcd": map[string]interface{}{"snapshotRetention": int64(10)},
+		},
+		"enableNetworkPolicy": true,
+	}
+	from := map[string]interface{}{
+		"kubernetesVersion": "v1.32.4",
+		"rkeConfig": map[string]interface{}{
+			"etcd": map[string]interface{}{"snapshotRetention": int64(5)},
+		},
+		"enableNetworkPolicy": false,
+	}
+	to := map[string]interface{}{
+		"kubernetesVersion": "v1.32.4",
+		"rkeConfig": map[string]interface{}{
+			"etcd": map[string]interface{}{"snapshotRetention": int64(20), "snapshotScheduleCron": "0 */6 * * *"},
+		},
+	}
+
+	applyChanges(dst, from, to)
+	assert.Equal(t, map[string]interface{}{
+		"kubernetesVersion": "v1.32.5",
+		"rkeConfig": map[string]interface{}{
+			"etcd": map[string]interface{}{"snapshotRetention": int64(20), "snapshotScheduleCron": "0 */6 * * *"},
+		},
+	}, dst)
+}
+
+func TestDiff(t *testing.T) {
+	current := map[string]interface{}{
+		"kubernetesVersion": "v1.32.5",
+		"rkeConfig":         map[string]interface{}{"etcd": map[string]interface{}{"snapshotRetention": int64(10)}},
+		"removed":           "value",
+	}
+	desired := map[string]interface{}{
+		"kubernetesVersion": "v1.32.5",
+		"rkeConfig":         map[string]interface{}{"etcd": map[string]interface{}{"snapshotRetention": int64(20)}},
+		"added":             []interface{}{"a"},
+	}
+
+	changes, err := diff(current, desired)
+	require.NoError(t, err)
+	assert.Equal(t, []provv1.ClusterTemplateFieldChange{
+		{Path: "added", Desired: `["a"]`},
+		{Path: "removed", Current: `"value"`},
+		{Path: "rkeConfig.etcd.snapshotRetention", Current: "10", Desired: "20"},
+	}, changes)
+}
+
+func TestPaths(t *testing.T) {
+	obj := map[string]interface{}{}
+	setPath(obj, "rkeConfig.etcd.s3.bucket", "backups")
+	value, ok := getPath(obj, "rkeConfig.etcd.s3.bucket")
+	assert.True(t, ok)
+	assert.Equal(t, "backups", value)
+
+	deletePath(obj, "rkeConfig.etcd.s3")
+	_, ok = getPath(obj, "rkeConfig.etcd.s3.bucket")
+	assert.False(t, ok)
+	assert.Equal(t, map[string]interface{}{"rkeConfig": map[string]interface{}{"etcd": map[string]interface{}{}}}, obj)
+
+	deletePath(obj, "missing.field")
+	assert.Error(t, validatePath("rkeConfig..etcd"))
+	assert.Error(t, validatePath(""))
+	assert.NoError(t, validatePath("rkeConfig.registries"))
+}
@@ -3,11 +3,14 @@
 import (
 	"context"
 
+	"github.com/rancher/rancher/pkg/controllers/provisioningv2/certificateexpiry"
 	"github.com/rancher/rancher/pkg/controllers/provisioningv2/cluster"
+	"github.com/rancher/rancher/pkg/controllers/provisioningv2/clustertemplate"
 	"github.com/rancher/rancher/pkg/controllers/provisioningv2/fleetcluster"
 	"github.com/rancher/rancher/pkg/controllers/provisioningv2/fleetworkspace"
 	"github.com/rancher/rancher/pkg/controllers/provisioningv2/harvestercleanup"
 	"github.com/rancher/rancher/pkg/controllers/provisioningv2/machineconfigcleanup"
+	"github.com/rancher/rancher/pkg/controllers/provisioningv2/machinepoolschedule"
 	"github.com/rancher/rancher/pkg/controllers/provisioningv2/managedchart"
 	"github.com/rancher/rancher/pkg/controllers/provisioningv2/provisioningcluster"
 	"github.com/rancher/rancher/pkg/controllers/provisioningv2/provisioninglog"
@@ -34,6 +37,9 @@ func Register(ctx context.Context, clients *wrangler.CAPIContext, kubeconfigMana
 	provisioningcluster.Register(ctx, clients)
 	provisioninglog.Register(ctx, clients)
 	machineconfigcleanup.Register(ctx, clients)
+	machinepoolschedule.Register(ctx, clients)
+	certificateexpiry.Register(ctx, clients)
+	clustertemplate.Register(ctx, clients)
 
 	if features.Harvester.Enabled() {
 		harvestercleanup.Register(ctx, clients)
@@ -0,0 +1,121 @@
+// Package machinepoolschedule applies the time-based schedules of RKE2/K3s machine pools by setting the quantity of
+// the machine pool, which in turn scales the generated CAPI machine deployment.
+package machinepoolschedule
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	provv1 "github.com/rancher/rancher/pkg/apis/provisioning.cattle.io/v1"
+	"github.com/rancher/rancher/pkg/capr"
+	provcontrollers "github.com/rancher/rancher/pkg/generated/controllers/provisioning.cattle.io/v1"
+	"github.com/rancher/rancher/pkg/wrangler"
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/api/equality"
+)
+
+const (
+	// minRequeue is the minimum delay before a cluster is re-evaluated for its next schedule.
+	minRequeue = time.Second
+	// maxRequeue is the maximum delay before a cluster is re-evaluated, so that status is refreshed even for
+	// schedules that fire rarely.
+	maxRequeue = time.Hour
+)
+
+type handler struct {
+	clusters provcontrollers.ClusterController
+	now      func() time.Time
+}
+
+func Register(ctx context.Context, clients *wrangler.CAPIContext) {
+	h := &handler{
+		clusters: clients.Provisioning.Cluster(),
+		now:      time.Now,
+	}
+	clients.Provisioning.Cluster().OnChange(ctx, "machine-pool-schedule", h.OnChange)
+}
+
+func (h *handler) OnChange(_ string, cluster *provv1.Cluster) (*provv1.Cluster, error) {
+	if cluster == nil || !cluster.DeletionTimestamp.IsZero() || cluster.Spec.RKEConfig == nil {
+		return cluster, nil
+	}
+
+	now := h.now()
+	newCluster := cluster.DeepCopy()
+	newCluster.Status.MachinePoolSchedules = nil
+
+	var (
+		errs         []error
+		nextSchedule time.Time
+	)
+	for i, pool := range cluster.Spec.RKEConfig.MachinePools {
+		if len(pool.Schedules) == 0 {
+			continue
+		}
+		if err := validatePool(pool); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		schedules, err := parseSchedules(pool)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		result := evaluatePool(pool, schedules, previousStatus(cluster, pool.Name), now)
+		if result.quantity != nil && (pool.Quantity == nil || *pool.Quantity != *result.quantity) {
+			logrus.Infof("rkecluster %s/%s: scaling machine pool %s to %d for schedule %s",
+				cluster.Namespace, cluster.Name, pool.Name, *result.quantity, result.status.LastSchedule)
+			newCluster.Spec.RKEConfig.MachinePools[i].Quantity = result.quantity
+		}
+		newCluster.Status.MachinePoolSchedules = append(newCluster.Status.MachinePoolSchedules, result.status)
+
+		if t := result.status.NextScheduleTime; t != nil && (nextSchedule.IsZero() || t.Time.Before(nextSchedule)) {
+			nextSchedule = t.Time
+		}
+	}
+
+	if len(newCluster.Status.MachinePoolSchedules) > 0 || len(errs) > 0 || capr.MachinePoolSchedulesReady.GetStatus(cluster) != "" {
+		capr.MachinePoolSchedulesReady.SetError(newCluster, "", errors.Join(errs...))
+	}
+
+	if !nextSchedule.IsZero() {
+		h.clusters.EnqueueAfter(cluster.Namespace, cluster.Name, requeueAfter(now, nextSchedule))
+	}
+
+	var err error
+	if !equality.Semantic.DeepEqual(cluster.Spec, newCluster.Spec) {
+		status := newCluster.Status
+		if newCluster, err = h.clusters.Update(newCluster); err != nil {
+			return cluster, err
+		}
+		newCluster.Status = status
+	}
+	if !equality.Semantic.DeepEqual(cluster.Status, newCluster.Status) {
+		return h.clusters.UpdateStatus(newCluster)
+	}
+	return newCluster, nil
+}
+
+// previousStatus returns the recorded schedule status of the named machine pool, or nil if there is none.
+func previousStatus(cluster *provv1.Cluster, poolName string) *provv1.MachinePoolScheduleStatus {
+	for i := range cluster.Status.MachinePoolSchedules {
+		if cluster.Status.MachinePoolSchedules[i].Name == poolName {
+			return &cluster.Status.MachinePoolSchedules[i]
+		}
+	}
+	return nil
+}
+
+// requeueAfter returns the delay until next, bounded by minRequeue and maxRequeue.
+func requeueAfter(now, next time.Time) time.Duration {
+	d := next.Sub(now)
+	if d < minRequeue {
+		return minRequeue
+	}
+	if d > maxRequeue {
+		return maxRequeue
+	}
+	return d
+}
@@ -0,0 +1,166 @@
+package machinepoolschedule
+
+import (
+	"fmt"
+	"time"
+
+	provv1 "github.com/rancher/rancher/pkg/apis/provisioning.cattle.io/v1"
+	"github.com/robfig/cron"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+const (
+	// lookback is how far in the past a schedule activation is still applied. It covers weekly windows so that a
+	// schedule added mid-window, or missed while rancher was down, still takes effect.
+	lookback = 8 * 24 * time.Hour
+
+	// maxActivations bounds the number of activations walked when searching for the most recent one, so that a
+	// schedule firing every minute can not stall the controller.
+	maxActivations = 20000
+)
+
+// parsedSchedule is a machine pool schedule with its cron expression parsed in its time zone.
+type parsedSchedule struct {
+	provv1.RKEMachinePoolSchedule
+	schedule cron.Schedule
+	location *time.Location
+}
+
+// activation is a point in time at which a schedule took, or will take, effect.
+type activation struct {
+	schedule *parsedSchedule
+	time     time.Time
+}
+
+// poolResult is the outcome of evaluating the schedules of a single machine pool.
+type poolResult struct {
+	// quantity is the quantity to set on the machine pool, nil if it must not be changed.
+	quantity *int32
+	status   provv1.MachinePoolScheduleStatus
+}
+
+// validatePool returns an error if schedules can not be applied to the given machine pool.
+func validatePool(pool provv1.RKEMachinePool) error {
+	if pool.EtcdRole || pool.ControlPlaneRole {
+		return fmt.Errorf("machine pool [%s]: schedules are not supported on etcd or controlplane machine pools", pool.Name)
+	}
+	if pool.AutoscalingMinSize != nil || pool.AutoscalingMaxSize != nil {
+		return fmt.Errorf("machine pool [%s]: schedules are not supported on machine pools using cluster-autoscaling", pool.Name)
+	}
+	return nil
+}
+
+// parseSchedules parses the cron expressions and time zones of the given machine pool schedules.
+func parseSchedules(pool provv1.RKEMachinePool) ([]*parsedSchedule, error) {
+	result := make([]*parsedSchedule, 0, len(pool.Schedules))
+	names := map[string]bool{}
+	for _, s := range pool.Schedules {
+		if names[s.Name] {
+			return nil, fmt.Errorf("machine pool [%s]: duplicate schedule name [%s]", pool.Name, s.Name)
+		}
+		names[s.Name] = true
+
+		if s.Quantity < 0 {
+			return nil, fmt.Errorf("machine pool [%s]: schedule [%s] quantity must not be negative", pool.Name, s.Name)
+		}
+
+		location := time.UTC
+		if s.TimeZone != "" {
+			var err error
+			if location, err = time.LoadLocation(s.TimeZone); err != nil {
+				return nil, fmt.Errorf("machine pool [%s]: schedule [%s] has invalid time zone [%s]: %w", pool.Name, s.Name, s.TimeZone, err)
+			}
+		}
+
+		schedule, err := cron.ParseStandard(s.Cron)
+		if err != nil {
+			return nil, fmt.Errorf("machine pool [%s]: schedule [%s] has invalid cron expression [%s]: %w", pool.Name, s.Name, s.Cron, err)
+		}
+
+		result = append(result, &parsedSchedule{
+			RKEMachinePoolSchedule: s,
+			schedule:               schedule,
+			location:               location,
+		})
+	}
+	return result, nil
+}
+
+// lastActivation returns the most recent activation of the given schedules in the interval (since, now]. If several
+// schedules are activated at the same time, the one listed last on the machine pool wins.
+func lastActivation(schedules []*parsedSchedule, since, now time.Time) *activation {
+	var last *activation
+	for _, s := range schedules {
+		var latest time.Time
+		t := since.In(s.location)
+		for i := 0; i < maxActivations; i++ {
+			next := s.schedule.Next(t)
+			if next.IsZero() || next.After(now) {
+				break
+			}
+			latest, t = next, next
+		}
+		if latest.IsZero() {
+			continue
+		}
+		if last == nil || !latest.Before(last.time) {
+			last = &activation{schedule: s, time: latest}
+		}
+	}
+	return last
+}
+
+// nextActivation returns the earliest activation of the given schedules after now.
+func nextActivation(schedules []*parsedSchedule, now time.Time) *activation {
+	var next *activation
+	for _, s := range schedules {
+		t := s.schedule.Next(now.In(s.location))
+		if t.IsZero() {
+			continue
+		}
+		if next == nil || t.Before(next.time) {
+			next = &activation{schedule: s, time: t}
+		}
+	}
+	return next
+}
+
+// evaluatePool determines the quantity and schedule status of a machine pool at the given time, based on its
+// schedules and the previously recorded status. The most recent activation since the last applied one sets the
+// quantity of the pool. Without a new activation the current quantity is kept, which is how manual changes last
+// until the next window.
+func evaluatePool(pool provv1.RKEMachinePool, schedules []*parsedSchedule, prev *provv1.MachinePoolScheduleStatus, now time.Time) poolResult {
+	result := poolResult{
+		status: provv1.MachinePoolScheduleStatus{
+			Name: pool.Name,
+		},
+	}
+
+	since := now.Add(-lookback)
+	if prev != nil {
+		result.status.LastSchedule = prev.LastSchedule
+		result.status.LastScheduleTime = prev.LastScheduleTime
+		result.status.AppliedQuantity = prev.AppliedQuantity
+		if prev.LastScheduleTime != nil && prev.LastScheduleTime.Time.After(since) {
+			since = prev.LastScheduleTime.Time
+		}
+	}
+
+	if last := lastActivation(schedules, since, now); last != nil {
+		result.quantity = ptr.To(last.schedule.Quantity)
+		result.status.LastSchedule = last.schedule.Name
+		result.status.LastScheduleTime = &metav1.Time{Time: last.time}
+		result.status.AppliedQuantity = ptr.To(last.schedule.Quantity)
+	} else if result.status.AppliedQuantity != nil {
+		result.status.Overridden = pool.Quantity == nil || *pool.Quantity != *result.status.AppliedQuantity
+	}
+
+	if next := nextActivation(schedules, now); next != nil {
+		result.status.NextSchedule = next.schedule.Name
+		result.status.NextScheduleTime = &metav1.Time{Time: next.time}
+		result.status.NextQuantity = ptr.To(next.schedule.Quantity)
+	}
+
+	return result
+}
@@ -0,0 +1,253 @@
+package machinepoolschedule
+
+import (
+	"testing"
+	"time"
+
+	provv1 "github.com/rancher/rancher/pkg/apis/provisioning.cattle.io/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+func workerPool(quantity *int32, schedules ...provv1.RKEMachinePoolSchedule) provv1.RKEMachinePool {
+	return provv1.RKEMachinePool{
+		Name:       "worker",
+		WorkerRole: true,
+		Quantity:   quantity,
+		Schedules:  schedules,
+	}
+}
+
+var (
+	nightly = provv1.RKEMachinePoolSchedule{Name: "night", Cron: "0 19 * * *", Quantity: 0}
+	daily   = provv1.RKEMachinePoolSchedule{Name: "day", Cron: "0 7 * * 1-5", Quantity: 3}
+)
+
+func TestValidatePool(t *testing.T) {
+	tests := []struct {
+		name    string
+		pool    provv1.RKEMachinePool
+		wantErr bool
+	}{
+		{
+			name: "worker pool",
+			pool: workerPool(nil, nightly),
+		},
+		{
+			name:    "etcd pool",
+			pool:    provv1.RKEMachinePool{Name: "etcd", EtcdRole: true, WorkerRole: true, Schedules: []provv1.RKEMachinePoolSchedule{nightly}},
+			wantErr: true,
+		},
+		{
+			name:    "controlplane pool",
+			pool:    provv1.RKEMachinePool{Name: "cp", ControlPlaneRole: true, Schedules: []provv1.RKEMachinePoolSchedule{nightly}},
+			wantErr: true,
+		},
+		{
+			name: "autoscaling pool",
+			pool: provv1.RKEMachinePool{Name: "worker", WorkerRole: true, AutoscalingMinSize: ptr.To[int32](1), AutoscalingMaxSize: ptr.To[int32](3),
+				Schedules: []provv1.RKEMachinePoolSchedule{nightly}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validatePool(tt.pool)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestParseSchedules(t *testing.T) {
+	tests := []struct {
+		name      string
+		schedules []provv1.RKEMachinePoolSchedule
+		wantErr   bool
+	}{
+		{
+			name:      "valid schedules",
+			schedules: []provv1.RKEMachinePoolSchedule{nightly, daily, {Name: "tz", Cron: "30 6 * * *", TimeZone: "Europe/Berlin", Quantity: 1}},
+		},
+		{
+			name:      "invalid cron",
+			schedules: []provv1.RKEMachinePoolSchedule{{Name: "bad", Cron: "not a cron", Quantity: 1}},
+			wantErr:   true,
+		},
+		{
+			name:      "invalid time zone",
+			schedules: []provv1.RKEMachinePoolSchedule{{Name: "bad", Cron: "0 7 * * *", TimeZone: "Mars/Olympus", Quantity: 1}},
+			wantErr:   true,
+		},
+		{
+			name:      "duplicate names",
+			schedules: []provv1.RKEMachinePoolSchedule{nightly, nightly},
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, err := parseSchedules(workerPool(nil, tt.schedules...))
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Len(t, parsed, len(tt.schedules))
+		})
+	}
+}
+
+func TestEvaluatePool(t *testing.T) {
+	// Wednesday
+	now := time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC)
+	morning := metav1.NewTime(time.Date(2026, 10, 14, 7, 0, 0, 0, time.UTC))
+	evening := metav1.NewTime(time.Date(2026, 10, 14, 19, 0, 0, 0, time.UTC))
+
+	tests := []struct {
+		name         string
+		pool         provv1.RKEMachinePool
+		prev         *provv1.MachinePoolScheduleStatus
+		now          time.Time
+		wantQuantity *int32
+		wantStatus   provv1.MachinePoolScheduleStatus
+	}{
+		{
+			name:         "first evaluation applies current window",
+			pool:         workerPool(ptr.To[int32](5), nightly, daily),
+			now:          now,
+			wantQuantity: ptr.To[int32](3),
+			wantStatus: provv1.MachinePoolScheduleStatus{
+				Name:             "worker",
+				LastSchedule:     "day",
+				LastScheduleTime: &morning,
+				AppliedQuantity:  ptr.To[int32](3),
+				NextSchedule:     "night",
+				NextScheduleTime: &evening,
+				NextQuantity:     ptr.To[int32](0),
+			},
+		},
+		{
+			name: "window already applied",
+			pool: workerPool(ptr.To[int32](3), nightly, daily),
+			prev: &provv1.MachinePoolScheduleStatus{
+				Name:             "worker",
+				LastSchedule:     "day",
+				LastScheduleTime: &morning,
+				AppliedQuantity:  ptr.To[int32](3),
+			},
+			now: now,
+			wantStatus: provv1.MachinePoolScheduleStatus{
+				Name:             "worker",
+				LastSchedule:     "day",
+				LastScheduleTime: &morning,
+				AppliedQuantity:  ptr.To[int32](3),
+				NextSchedule:     "night",
+				NextScheduleTime: &evening,
+				NextQuantity:     ptr.To[int32](0),
+			},
+		},
+		{
+			name: "manual override lasts until next window",
+			pool: workerPool(ptr.To[int32](6), nightly, daily),
+			prev: &provv1.MachinePoolScheduleStatus{
+				Name:             "worker",
+				LastSchedule:     "day",
+				LastScheduleTime: &morning,
+				AppliedQuantity:  ptr.To[int32](3),
+			},
+			now: now,
+			wantStatus: provv1.MachinePoolScheduleStatus{
+				Name:             "worker",
+				LastSchedule:     "day",
+				LastScheduleTime: &morning,
+				AppliedQuantity:  ptr.To[int32](3),
+				Overridden:       true,
+				NextSchedule:     "night",
+				NextScheduleTime: &evening,
+				NextQuantity:     ptr.To[int32](0),
+			},
+		},
+		{
+			name: "next window replaces manual override",
+			pool: workerPool(ptr.To[int32](6), nightly, daily),
+			prev: &provv1.MachinePoolScheduleStatus{
+				Name:             "worker",
+				LastSchedule:     "day",
+				LastScheduleTime: &morning,
+				AppliedQuantity:  ptr.To[int32](3),
+				Overridden:       true,
+			},
+			now:          evening.Add(time.Minute),
+			wantQuantity: ptr.To[int32](0),
+			wantStatus: provv1.MachinePoolScheduleStatus{
+				Name:             "worker",
+				LastSchedule:     "night",
+				LastScheduleTime: &evening,
+				AppliedQuantity:  ptr.To[int32](0),
+				NextSchedule:     "day",
+				NextScheduleTime: &metav1.Time{Time: time.Date(2026, 10, 15, 7, 0, 0, 0, time.UTC)},
+				NextQuantity:     ptr.To[int32](3),
+			},
+		},
+		{
+			name:         "weekend window is applied on first evaluation",
+			pool:         workerPool(ptr.To[int32](3), provv1.RKEMachinePoolSchedule{Name: "weekend", Cron: "0 20 * * 5", Quantity: 0}, daily),
+			now:          time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC), // Saturday
+			wantQuantity: ptr.To[int32](0),
+			wantStatus: provv1.MachinePoolScheduleStatus{
+				Name:             "worker",
+				LastSchedule:     "weekend",
+				LastScheduleTime: &metav1.Time{Time: time.Date(2026, 10, 16, 20, 0, 0, 0, time.UTC)},
+				AppliedQuantity:  ptr.To[int32](0),
+				NextSchedule:     "day",
+				NextScheduleTime: &metav1.Time{Time: time.Date(2026, 10, 19, 7, 0, 0, 0, time.UTC)},
+				NextQuantity:     ptr.To[int32](3),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedules, err := parseSchedules(tt.pool)
+			require.NoError(t, err)
+
+			result := evaluatePool(tt.pool, schedules, tt.prev, tt.now)
+			assert.Equal(t, tt.wantQuantity, result.quantity)
+			assert.Equal(t, tt.wantStatus.Name, result.status.Name)
+			assert.Equal(t, tt.wantStatus.LastSchedule, result.status.LastSchedule)
+			assert.True(t, tt.wantStatus.LastScheduleTime.Equal(result.status.LastScheduleTime))
+			assert.Equal(t, tt.wantStatus.AppliedQuantity, result.status.AppliedQuantity)
+			assert.Equal(t, tt.wantStatus.Overridden, result.status.Overridden)
+			assert.Equal(t, tt.wantStatus.NextSchedule, result.status.NextSchedule)
+			assert.True(t, tt.wantStatus.NextScheduleTime.Equal(result.status.NextScheduleTime))
+			assert.Equal(t, tt.wantStatus.NextQuantity, result.status.NextQuantity)
+		})
+	}
+}
+
+func TestEvaluatePoolTimeZone(t *testing.T) {
+	pool := workerPool(nil, provv1.RKEMachinePoolSchedule{Name: "morning", Cron: "0 7 * * *", TimeZone: "America/New_York", Quantity: 2})
+	schedules, err := parseSchedules(pool)
+	require.NoError(t, err)
+
+	result := evaluatePool(pool, schedules, nil, time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC))
+	require.NotNil(t, result.status.LastScheduleTime)
+	// 07:00 EDT is 11:00 UTC
+	assert.True(t, result.status.LastScheduleTime.Time.Equal(time.Date(2026, 10, 14, 11, 0, 0, 0, time.UTC)))
+	assert.Equal(t, ptr.To[int32](2), result.quantity)
+}
+
+func TestRequeueAfter(t *testing.T) {
+	now := time.Now()
+	assert.Equal(t, minRequeue, requeueAfter(now, now.Add(-time.Minute)))
+	assert.Equal(t, 10*time.Minute, requeueAfter(now, now.Add(10*time.Minute)))
+	assert.Equal(t, maxRequeue, requeueAfter(now, now.Add(48*time.Hour)))
+}
@@ -23,6 +23,7 @@
 	"github.com/rancher/wrangler/v3/pkg/relatedresource"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -40,6 +41,7 @@ func Register(ctx context.Context, clients *wrangler.Context) {
 		mccCache:      clients.Mgmt.ManagedChart().Cache(),
 		mccController: clients.Mgmt.ManagedChart(),
 		bundleCache:   clients.Fleet.Bundle().Cache(),
+		bdCache:       clients.Fleet.BundleDeployment().Cache(),
 	}
 
 	clients.Catalog.ClusterRepo().OnChange(ctx, "mcc-repo", h.OnRepoChange)
@@ -48,6 +50,11 @@ func Register(ctx context.Context, clients *wrangler.Context) {
 		relatedresource.OwnerResolver(true, v3.SchemeGroupVersion.String(), "ManagedChart"),
 		clients.Mgmt.ManagedChart(),
 		clients.Fleet.Bundle())
+	relatedresource.Watch(ctx,
+		"mcc-from-bundledeployment-trigger",
+		h.resolveBundleDeployment,
+		clients.Mgmt.ManagedChart(),
+		clients.Fleet.BundleDeployment())
 	mgmtcontrollers.RegisterManagedChartGeneratingHandler(ctx,
 		clients.Mgmt.ManagedChart(),
 		clients.Apply.
@@ -69,6 +76,7 @@ type handler struct {
 	mccCache      mgmtcontrollers.ManagedChartCache
 	mccController mgmtcontrollers.ManagedChartController
 	bundleCache   fleetcontrollers.BundleCache
+	bdCache       fleetcontrollers.BundleDeploymentCache
 }
 
 func (h *handler) OnRepoChange(key string, _ *v1.ClusterRepo) (*v1.ClusterRepo, error) {
@@ -111,6 +119,7 @@ func (h *handler) OnChange(mcc *v3.ManagedChart, status v3.ManagedChartStatus) (
 				},
 				ServiceAccount: mcc.Spec.ServiceAccount,
 				Diff:           mcc.Spec.Diff,
+				CorrectDrift:   mcc.Spec.CorrectDrift,
 			},
 			Paused:          mcc.Spec.Paused,
 			RolloutStrategy: mcc.Spec.RolloutStrategy,
@@ -164,6 +173,7 @@ func (h *handler) OnChange(mcc *v3.ManagedChart, status v3.ManagedChartStatus) (
 }
 
 func (h *handler) updateStatus(status v3.ManagedChartStatus, bundle *v1alpha1.Bundle) (v3.ManagedChartStatus, error) {
+	desiredVersion := bundle.Spec.Helm.Version
 	bundle, err := h.bundleCache.Get(bundle.Namespace, bundle.Name)
 	if apierrors.IsNotFound(err) {
 		return status, nil
@@ -172,5 +182,84 @@ func (h *handler) updateStatus(status v3.ManagedChartStatus, bundle *v1alpha1.Bu
 	}
 
 	status.BundleStatus = bundle.Status
+
+	bds, err := h.bdCache.List("", labels.SelectorFromSet(labels.Set{
+		v1alpha1.BundleLabel:          bundle.Name,
+		v1alpha1.BundleNamespaceLabel: bundle.Namespace,
+	}))
+	if err != nil {
+		return status, err
+	}
+	status.Clusters = clusterStatuses(status.Clusters, desiredVersion, bds)
 	return status, nil
 }
+
+// clusterStatuses returns the deployment status of the chart in each cluster targeted by the bundle, given the
+// BundleDeployments of the bundle. The installed version of a cluster is carried over from the previous status while
+// Fleet is still applying a new deployment to it.
+func clusterStatuses(previous []v3.ManagedChartClusterStatus, desiredVersion string, bds []*v1alpha1.BundleDeployment) []v3.ManagedChartClusterStatus {
+	installedVersions := map[string]string{}
+	for _, cluster := range previous {
+		installedVersions[cluster.ClusterNamespace+"/"+cluster.ClusterName] = cluster.InstalledVersion
+	}
+
+	var result []v3.ManagedChartClusterStatus
+	for _, bd := range bds {
+		cluster := v3.ManagedChartClusterStatus{
+			ClusterName:       bd.Labels[v1alpha1.ClusterLabel],
+			ClusterNamespace:  bd.Labels[v1alpha1.ClusterNamespaceLabel],
+			State:             bd.Status.Display.State,
+			Ready:             bd.Status.Ready,
+			DesiredVersion:    desiredVersion,
+			ModifiedResources: bd.Status.ModifiedStatus,
+			NonReadyResources: bd.Status.NonReadyStatus,
+			IncompleteState:   bd.Status.IncompleteState,
+		}
+		applied := bd.Spec.DeploymentID != "" && bd.Status.AppliedDeploymentID == bd.Spec.DeploymentID
+		if applied && bd.Spec.Options.Helm != nil {
+			cluster.InstalledVersion = bd.Spec.Options.Helm.Version
+		} else {
+			cluster.InstalledVersion = installedVersions[cluster.ClusterNamespace+"/"+cluster.ClusterName]
+		}
+		cluster.InSync = applied && bd.Status.NonModified && cluster.InstalledVersion == desiredVersion
+		result = append(result, cluster)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].ClusterNamespace != result[j].ClusterNamespace {
+			return result[i].ClusterNamespace < result[j].ClusterNamespace
+		}
+		return result[i].ClusterName < result[j].ClusterName
+	})
+	return result
+}
+
+// resolveBundleDeployment enqueues the ManagedChart owning the bundle of a BundleDeployment, so that its per-cluster
+// status follows the deployments.
+func (h *handler) resolveBundleDeployment(_, _ string, obj runtime.Object) ([]relatedresource.Key, error) {
+	bd, ok := obj.(*v1alpha1.BundleDeployment)
+	if !ok {
+		return nil, nil
+	}
+	bundleName, bundleNamespace := bd.Labels[v1alpha1.BundleLabel], bd.Labels[v1alpha1.BundleNamespaceLabel]
+	if bundleName == "" || bundleNamespace == "" {
+		return nil, nil
+	}
+	bundle, err := h.bundleCache.Get(bundleNamespace, bundleName)
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var keys []relatedresource.Key
+	for _, owner := range bundle.OwnerReferences {
+		if owner.APIVersion == v3.SchemeGroupVersion.String() && owner.Kind == "ManagedChart" {
+			keys = append(keys, relatedresource.Key{
+				Namespace: bundle.Namespace,
+				Name:      owner.Name,
+			})
+		}
+	}
+	return keys, nil
+}
@@ -0,0 +1,111 @@
+package managedchart
+
+import (
+	"testing"
+
+	"github.com/rancher/fleet/pkg/apis/fleet.cattle.io/v1alpha1"
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/wrangler/v3/pkg/generic/fake"
+	"github.com/rancher/wrangler/v3/pkg/relatedresource"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newBundleDeployment(cluster, deploymentID, appliedDeploymentID, version string) *v1alpha1.BundleDeployment {
+	return &v1alpha1.BundleDeployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: map[string]string{
+				v1alpha1.BundleLabel:           "mcc-rancher-monitoring",
+				v1alpha1.BundleNamespaceLabel:  "fleet-default",
+				v1alpha1.ClusterLabel:          cluster,
+				v1alpha1.ClusterNamespaceLabel: "fleet-default",
+			},
+		},
+		Spec: v1alpha1.BundleDeploymentSpec{
+			DeploymentID: deploymentID,
+			Options: v1alpha1.BundleDeploymentOptions{
+				Helm: &v1alpha1.HelmOptions{Version: version},
+			},
+		},
+		Status: v1alpha1.BundleDeploymentStatus{
+			AppliedDeploymentID: appliedDeploymentID,
+			Ready:               true,
+			NonModified:         true,
+			Display:             v1alpha1.BundleDeploymentDisplay{State: "Ready"},
+		},
+	}
+}
+
+func TestClusterStatuses(t *testing.T) {
+	drifted := newBundleDeployment("c2", "s-1", "s-1", "1.0.0")
+	drifted.Status.NonModified = false
+	drifted.Status.Display.State = "Modified"
+	drifted.Status.ModifiedStatus = []v1alpha1.ModifiedStatus{{
+		Kind:      "Deployment",
+		Namespace: "cattle-monitoring-system",
+		Name:      "rancher-monitoring-operator",
+		Patch:     `{"spec":{"replicas":1}}`,
+	}}
+
+	previous := []v3.ManagedChartClusterStatus{{ClusterName: "c3", ClusterNamespace: "fleet-default", InstalledVersion: "0.9.0"}}
+	bds := []*v1alpha1.BundleDeployment{
+		newBundleDeployment("c3", "s-2", "s-1", "1.0.0"),
+		drifted,
+		newBundleDeployment("c1", "s-1", "s-1", "1.0.0"),
+	}
+
+	assert.Equal(t, []v3.ManagedChartClusterStatus{
+		{
+			ClusterName:      "c1",
+			ClusterNamespace: "fleet-default",
+			State:            "Ready",
+			Ready:            true,
+			InSync:           true,
+			DesiredVersion:   "1.0.0",
+			InstalledVersion: "1.0.0",
+		},
+		{
+			ClusterName:       "c2",
+			ClusterNamespace:  "fleet-default",
+			State:             "Modified",
+			Ready:             true,
+			DesiredVersion:    "1.0.0",
+			InstalledVersion:  "1.0.0",
+			ModifiedResources: drifted.Status.ModifiedStatus,
+		},
+		{
+			ClusterName:      "c3",
+			ClusterNamespace: "fleet-default",
+			State:            "Ready",
+			Ready:            true,
+			DesiredVersion:   "1.0.0",
+			InstalledVersion: "0.9.0",
+		},
+	}, clusterStatuses(previous, "1.0.0", bds))
+}
+
+func TestResolveBundleDeployment(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	bundleCache := fake.NewMockCacheInterface[*v1alpha1.Bundle](ctrl)
+	bundleCache.EXPECT().Get("fleet-default", "mcc-rancher-monitoring").Return(&v1alpha1.Bundle{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "mcc-rancher-monitoring",
+			Namespace: "fleet-default",
+			OwnerReferences: []metav1.OwnerReference{
+				{APIVersion: "v1", Kind: "ConfigMap", Name: "other"},
+				{APIVersion: v3.SchemeGroupVersion.String(), Kind: "ManagedChart", Name: "rancher-monitoring"},
+			},
+		},
+	}, nil)
+
+	h := &handler{bundleCache: bundleCache}
+	keys, err := h.resolveBundleDeployment("", "", newBundleDeployment("c1", "s-1", "s-1", "1.0.0"))
+	require.NoError(t, err)
+	assert.Equal(t, []relatedresource.Key{{Namespace: "fleet-default", Name: "rancher-monitoring"}}, keys)
+
+	keys, err = h.resolveBundleDeployment("", "", &v1alpha1.BundleDeployment{})
+	require.NoError(t, err)
+	assert.Empty(t, keys)
+}
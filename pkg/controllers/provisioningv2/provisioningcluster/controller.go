@@ -3,6 +3,7 @@
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"reflect"
 	"strings"
@@ -13,6 +14,7 @@
 	"github.com/rancher/rancher/pkg/apis/provisioning.cattle.io/v1/snapshotutil"
 	rkev1 "github.com/rancher/rancher/pkg/apis/rke.cattle.io/v1"
 	"github.com/rancher/rancher/pkg/capr"
+	"github.com/rancher/rancher/pkg/controllers/provisioningv2/clustertemplate"
 	"github.com/rancher/rancher/pkg/features"
 	capicontrollers "github.com/rancher/rancher/pkg/generated/controllers/cluster.x-k8s.io/v1beta2"
 	mgmtcontroller "github.com/rancher/rancher/pkg/generated/controllers/management.cattle.io/v3"
@@ -126,6 +128,11 @@ func byNodeInfraIndex(obj *rancherv1.Cluster) ([]string, error) {
 			continue
 		}
 		result = append(result, toInfraRefKey(*np.NodeConfig, obj.Namespace))
+		for _, fd := range np.FailureDomains {
+			if fd.NodeConfig != nil {
+				result = append(result, toInfraRefKey(*fd.NodeConfig, obj.Namespace))
+			}
+		}
 	}
 
 	return result, nil
@@ -296,6 +303,12 @@ func (h *handler) OnRancherClusterChange(obj *rancherv1.Cluster, status rancherv
 		return nil, status, nil
 	}
 
+	if !clustertemplate.Applied(obj) {
+		// Changes to locked fields are reverted by the cluster template controller before they take effect.
+		logrus.Debugf("rkecluster %s/%s: waiting for cluster template to be applied", obj.Namespace, obj.Name)
+		return nil, status, generic.ErrSkip
+	}
+
 	if obj.Spec.KubernetesVersion == "" {
 		return nil, status, fmt.Errorf("kubernetesVersion not set on %s/%s", obj.Namespace, obj.Name)
 	}
@@ -383,6 +396,10 @@ func (h *handler) OnRancherClusterChange(obj *rancherv1.Cluster, status rancherv
 		}
 	}
 
+	if errs, spread := etcdFailureDomainErrors(obj); spread || capr.EtcdFailureDomainsBalanced.GetStatus(&status) != "" {
+		capr.EtcdFailureDomainsBalanced.SetError(&status, "", errors.Join(errs...))
+	}
+
 	objs, err := objects(obj, h.dynamic, h.dynamicSchema, h.secretCache)
 	return objs, status, err
 }
@@ -761,6 +761,21 @@ type want struct {
 			},
 			expect: want{err: errors.New("non-nil")},
 		},
+		{
+			name: "cluster template not applied returns ErrSkip",
+			input: func() *provv1.Cluster {
+				c := newBaseCluster("ns", "cluster-template", "v1.29.4+rke2r1")
+				c.Generation = 2
+				c.Spec.ClusterTemplate = &provv1.ClusterTemplateReference{Name: "standard", Revision: 1}
+				c.Status.ClusterTemplate = &provv1.ClusterTemplateRevisionStatus{AppliedRevision: 1, ObservedGeneration: 1}
+				return c
+			}(),
+			setup: func(ctrl *gomock.Controller) (*handler, *provv1.Cluster) {
+				// No objects are looked up or applied until the template is applied to the current generation.
+				return &handler{}, nil
+			},
+			expect: want{err: generic.ErrSkip},
+		},
 	}
 
 	for _, tc := range tests {
@@ -326,6 +326,8 @@ func machineDeployments(cluster *provv1.Cluster, capiCluster *capi.Cluster, dyna
 	}
 
 	machinePoolNames := map[string]bool{}
+	// machineDeploymentPools maps the names of the generated machine deployments to the machine pools generating them.
+	machineDeploymentPools := map[string]string{}
 	for _, machinePool := range cluster.Spec.RKEConfig.MachinePools {
 		if machinePool.Name == "" || machinePool.NodeConfig == nil || machinePool.NodeConfig.Name == "" || machinePool.NodeConfig.Kind == "" {
 			return nil, fmt.Errorf("invalid machinePool [%s] missing name or valid config", machinePool.Name)
@@ -341,215 +343,333 @@ func machineDeployments(cluster *provv1.Cluster, capiCluster *capi.Cluster, dyna
 		}
 		machinePoolNames[machinePool.Name] = true
 
-		var (
-			machineDeploymentName = name.SafeConcatName(cluster.Name, machinePool.Name)
-			infraRef              capi.ContractVersionedObjectReference
-		)
-
-		if machinePool.NodeConfig.APIVersion == "" || machinePool.NodeConfig.APIVersion == capr.DefaultMachineConfigAPIVersion {
-			machineTemplate, err := toMachineTemplate(machineDeploymentName, cluster, machinePool, dynamic, secrets)
-			if err != nil {
-				return nil, err
+		pools, err := failureDomainPools(machinePool)
+		if err != nil {
+			return nil, err
+		}
+		for _, fd := range pools {
+			// Each failure domain gets its own machine deployment, generated from a copy of the machine pool using the
+			// machine config and share of the quantity of the failure domain.
+			machinePool := fd.pool
+
+			var (
+				machineDeploymentName = name.SafeConcatName(cluster.Name, fd.name)
+				infraRef              capi.ContractVersionedObjectReference
+			)
+
+			// The machine deployments of failure domains are suffixed with their name, which can be the name of
+			// another machine pool e.g. pool "worker" with failure domain "a" and pool "worker-a".
+			if other, ok := machineDeploymentPools[machineDeploymentName]; ok {
+				return nil, fmt.Errorf("machinePool [%s] and machinePool [%s] both generate the machine deployment [%s]", other, machinePool.Name, machineDeploymentName)
 			}
-
-			result = append(result, machineTemplate)
-
-			gv, err := schema.ParseGroupVersion(machineTemplate.GetAPIVersion())
-			if err != nil {
-				return nil, err
+			machineDeploymentPools[machineDeploymentName] = machinePool.Name
+
+			if machinePool.NodeConfig.APIVersion == "" || machinePool.NodeConfig.APIVersion == capr.DefaultMachineConfigAPIVersion {
+				machineTemplate, err := toMachineTemplate(machineDeploymentName, cluster, machinePool, dynamic, secrets)
+				if err != nil {
+					return nil, err
+				}
+
+				result = append(result, machineTemplate)
+
+				gv, err := schema.ParseGroupVersion(machineTemplate.GetAPIVersion())
+				if err != nil {
+					return nil, err
+				}
+				infraRef = capi.ContractVersionedObjectReference{
+					APIGroup: gv.Group,
+					Kind:     machineTemplate.GetKind(),
+					Name:     machineTemplate.GetName(),
+				}
+			} else {
+				gv, err := schema.ParseGroupVersion(machinePool.NodeConfig.APIVersion)
+				if err != nil {
+					return nil, err
+				}
+				infraRef = capi.ContractVersionedObjectReference{
+					APIGroup: gv.Group,
+					Kind:     machinePool.NodeConfig.Kind,
+					Name:     machinePool.NodeConfig.Name,
+				}
 			}
-			infraRef = capi.ContractVersionedObjectReference{
-				APIGroup: gv.Group,
-				Kind:     machineTemplate.GetKind(),
-				Name:     machineTemplate.GetName(),
+
+			// The MachineOS field is used below to set the cattle.io/os label in the
+			// machine template, which is used by the planner and for the bootstrap
+			// secret.
+			//
+			// The label will default to linux when MachineOS is unset. For windows,
+			// MachineOS must be set by the user in the cluster machine pool spec.
+			machineOS := machinePool.MachineOS
+			if machineOS == "" {
+				machineOS = capr.DefaultMachineOS
 			}
-		} else {
-			gv, err := schema.ParseGroupVersion(machinePool.NodeConfig.APIVersion)
-			if err != nil {
-				return nil, err
+
+			machineDeploymentLabels := map[string]string{}
+			for k, v := range machinePool.Labels {
+				machineDeploymentLabels[k] = v
 			}
-			infraRef = capi.ContractVersionedObjectReference{
-				APIGroup: gv.Group,
-				Kind:     machinePool.NodeConfig.Kind,
-				Name:     machinePool.NodeConfig.Name,
+			for k, v := range machinePool.MachineDeploymentLabels {
+				machineDeploymentLabels[k] = v
 			}
-		}
 
-		// The MachineOS field is used below to set the cattle.io/os label in the
-		// machine template, which is used by the planner and for the bootstrap
-		// secret.
-		//
-		// The label will default to linux when MachineOS is unset. For windows,
-		// MachineOS must be set by the user in the cluster machine pool spec.
-		machineOS := machinePool.MachineOS
-		if machineOS == "" {
-			machineOS = capr.DefaultMachineOS
-		}
-
-		machineDeploymentLabels := map[string]string{}
-		for k, v := range machinePool.Labels {
-			machineDeploymentLabels[k] = v
-		}
-		for k, v := range machinePool.MachineDeploymentLabels {
-			machineDeploymentLabels[k] = v
-		}
+			machineDeploymentLabels[capr.CattleOSLabel] = machineOS
 
-		machineDeploymentLabels[capr.CattleOSLabel] = machineOS
-
-		machineSpecAnnotations := map[string]string{}
-		// Ignore drain if DrainBeforeDelete is unset
-		if !machinePool.DrainBeforeDelete {
-			machineSpecAnnotations[capi.ExcludeNodeDrainingAnnotation] = "true"
-		}
+			machineSpecAnnotations := map[string]string{}
+			// Ignore drain if DrainBeforeDelete is unset
+			if !machinePool.DrainBeforeDelete {
+				machineSpecAnnotations[capi.ExcludeNodeDrainingAnnotation] = "true"
+			}
 
-		err := populateHostnameLengthLimitAnnotation(machinePool, cluster, machineSpecAnnotations)
-		if err != nil {
-			return nil, err
-		}
+			err := populateHostnameLengthLimitAnnotation(machinePool, cluster, machineSpecAnnotations)
+			if err != nil {
+				return nil, err
+			}
 
-		deployAnnotations := machinePool.MachineDeploymentAnnotations
-		if deployAnnotations == nil {
-			deployAnnotations = make(map[string]string)
-		}
+			deployAnnotations := machinePool.MachineDeploymentAnnotations
+			if deployAnnotations == nil {
+				deployAnnotations = make(map[string]string)
+			}
 
-		// potentially gross, hopefully a user doesn't want the max to be 2^16
-		if machinePool.AutoscalingMinSize != nil {
-			deployAnnotations[capi.AutoscalerMinSizeAnnotation] = strconv.Itoa(int(*machinePool.AutoscalingMinSize))
-		}
-		if machinePool.AutoscalingMaxSize != nil {
-			deployAnnotations[capi.AutoscalerMaxSizeAnnotation] = strconv.Itoa(int(*machinePool.AutoscalingMaxSize))
-		}
+			// potentially gross, hopefully a user doesn't want the max to be 2^16
+			if machinePool.AutoscalingMinSize != nil {
+				deployAnnotations[capi.AutoscalerMinSizeAnnotation] = strconv.Itoa(int(*machinePool.AutoscalingMinSize))
+			}
+			if machinePool.AutoscalingMaxSize != nil {
+				deployAnnotations[capi.AutoscalerMaxSizeAnnotation] = strconv.Itoa(int(*machinePool.AutoscalingMaxSize))
+			}
 
-		bootstrapTplName := commonBootstrapTplName
+			bootstrapTplName := commonBootstrapTplName
 
-		// For external CAPI infrastructure providers, create a bootstrap template
-		// for each machine pool, because they could have different additional userdata.
-		if bootstrapTplName == "" {
-			bootstrapTpl := &rkev1.RKEBootstrapTemplate{
-				ObjectMeta: metav1.ObjectMeta{
-					Namespace: cluster.Namespace,
-					Name:      machineDeploymentName,
-					Labels: map[string]string{
-						capr.ClusterNameLabel: cluster.Name,
+			// For external CAPI infrastructure providers, create a bootstrap template
+			// for each machine pool, because they could have different additional userdata.
+			if bootstrapTplName == "" {
+				bootstrapTpl := &rkev1.RKEBootstrapTemplate{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: cluster.Namespace,
+						Name:      machineDeploymentName,
+						Labels: map[string]string{
+							capr.ClusterNameLabel: cluster.Name,
+						},
 					},
-				},
-				Spec: rkev1.RKEBootstrapTemplateSpec{
-					ClusterName: cluster.Name,
-					Template: rkev1.RKEBootstrap{
-						Spec: rkev1.RKEBootstrapSpec{
-							Userdata:    machinePool.Userdata.DeepCopy(),
-							ClusterName: cluster.Name,
+					Spec: rkev1.RKEBootstrapTemplateSpec{
+						ClusterName: cluster.Name,
+						Template: rkev1.RKEBootstrap{
+							Spec: rkev1.RKEBootstrapSpec{
+								Userdata:    machinePool.Userdata.DeepCopy(),
+								ClusterName: cluster.Name,
+							},
 						},
 					},
-				},
-			}
+				}
 
-			boostrapTplHash := createBootstrapTemplateHash(bootstrapTpl)
-			bootstrapTpl.ObjectMeta.Name = name.SafeConcatName(
-				bootstrapTpl.ObjectMeta.Name, boostrapTplHash)
+				boostrapTplHash := createBootstrapTemplateHash(bootstrapTpl)
+				bootstrapTpl.ObjectMeta.Name = name.SafeConcatName(
+					bootstrapTpl.ObjectMeta.Name, boostrapTplHash)
 
-			result = append(result, bootstrapTpl)
-			bootstrapTplName = bootstrapTpl.ObjectMeta.Name
-		}
+				result = append(result, bootstrapTpl)
+				bootstrapTplName = bootstrapTpl.ObjectMeta.Name
+			}
 
-		machineDeployment := &capi.MachineDeployment{
-			ObjectMeta: metav1.ObjectMeta{
-				Namespace:   cluster.Namespace,
-				Name:        machineDeploymentName,
-				Labels:      machineDeploymentLabels,
-				Annotations: deployAnnotations,
-			},
-			Spec: capi.MachineDeploymentSpec{
-				ClusterName: capiCluster.Name,
-				Replicas:    machinePool.Quantity,
-				Rollout: capi.MachineDeploymentRolloutSpec{
-					Strategy: capi.MachineDeploymentRolloutStrategy{
-						// RollingUpdate is the default, so no harm in setting it here.
-						Type: capi.RollingUpdateMachineDeploymentStrategyType,
-					},
-				},
-				Deletion: capi.MachineDeploymentDeletionSpec{
-					// Delete oldest machines by default.
-					Order: capi.OldestMachineSetDeletionOrder,
+			machineDeployment := &capi.MachineDeployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:   cluster.Namespace,
+					Name:        machineDeploymentName,
+					Labels:      machineDeploymentLabels,
+					Annotations: deployAnnotations,
 				},
-				Template: capi.MachineTemplateSpec{
-					ObjectMeta: capi.ObjectMeta{
-						Labels: map[string]string{
-							capi.ClusterNameLabel:           capiCluster.Name,
-							capr.ClusterNameLabel:           capiCluster.Name,
-							capi.MachineDeploymentNameLabel: machineDeploymentName,
-							capr.RKEMachinePoolNameLabel:    machinePool.Name,
+				Spec: capi.MachineDeploymentSpec{
+					ClusterName: capiCluster.Name,
+					Replicas:    machinePool.Quantity,
+					Rollout: capi.MachineDeploymentRolloutSpec{
+						Strategy: capi.MachineDeploymentRolloutStrategy{
+							// RollingUpdate is the default, so no harm in setting it here.
+							Type: capi.RollingUpdateMachineDeploymentStrategyType,
 						},
-						Annotations: machineSpecAnnotations,
 					},
-					Spec: capi.MachineSpec{
-						ClusterName: capiCluster.Name,
-						Bootstrap: capi.Bootstrap{
-							ConfigRef: capi.ContractVersionedObjectReference{
-								Kind:     "RKEBootstrapTemplate",
-								Name:     bootstrapTplName,
-								APIGroup: capr.RKEAPIGroup,
+					Deletion: capi.MachineDeploymentDeletionSpec{
+						// Delete oldest machines by default.
+						Order: capi.OldestMachineSetDeletionOrder,
+					},
+					Template: capi.MachineTemplateSpec{
+						ObjectMeta: capi.ObjectMeta{
+							Labels: map[string]string{
+								capi.ClusterNameLabel:           capiCluster.Name,
+								capr.ClusterNameLabel:           capiCluster.Name,
+								capi.MachineDeploymentNameLabel: machineDeploymentName,
+								capr.RKEMachinePoolNameLabel:    machinePool.Name,
 							},
+							Annotations: machineSpecAnnotations,
 						},
-						InfrastructureRef: infraRef,
-						Deletion: capi.MachineDeletionSpec{
-							NodeDrainTimeoutSeconds: durationToSeconds(machinePool.DrainBeforeDeleteTimeout, cluster),
+						Spec: capi.MachineSpec{
+							ClusterName: capiCluster.Name,
+							Bootstrap: capi.Bootstrap{
+								ConfigRef: capi.ContractVersionedObjectReference{
+									Kind:     "RKEBootstrapTemplate",
+									Name:     bootstrapTplName,
+									APIGroup: capr.RKEAPIGroup,
+								},
+							},
+							InfrastructureRef: infraRef,
+							Deletion: capi.MachineDeletionSpec{
+								NodeDrainTimeoutSeconds: durationToSeconds(machinePool.DrainBeforeDeleteTimeout, cluster),
+							},
 						},
 					},
+					Paused: &machinePool.Paused,
 				},
-				Paused: &machinePool.Paused,
-			},
-		}
-		if machinePool.RollingUpdate != nil {
-			machineDeployment.Spec.Rollout.Strategy.Type = capi.RollingUpdateMachineDeploymentStrategyType
-			machineDeployment.Spec.Rollout.Strategy.RollingUpdate = capi.MachineDeploymentRolloutStrategyRollingUpdate{
-				MaxUnavailable: machinePool.RollingUpdate.MaxUnavailable,
-				MaxSurge:       machinePool.RollingUpdate.MaxSurge,
 			}
-		}
+			if machinePool.RollingUpdate != nil {
+				machineDeployment.Spec.Rollout.Strategy.Type = capi.RollingUpdateMachineDeploymentStrategyType
+				machineDeployment.Spec.Rollout.Strategy.RollingUpdate = capi.MachineDeploymentRolloutStrategyRollingUpdate{
+					MaxUnavailable: machinePool.RollingUpdate.MaxUnavailable,
+					MaxSurge:       machinePool.RollingUpdate.MaxSurge,
+				}
+			}
 
-		if machinePool.EtcdRole {
-			machineDeployment.Spec.Template.Labels[capr.EtcdRoleLabel] = "true"
-		}
+			if machinePool.EtcdRole {
+				machineDeployment.Spec.Template.Labels[capr.EtcdRoleLabel] = "true"
+			}
 
-		if machinePool.ControlPlaneRole {
-			machineDeployment.Spec.Template.Labels[capr.ControlPlaneRoleLabel] = "true"
-			machineDeployment.Spec.Template.Labels[capi.MachineControlPlaneLabel] = "true"
-		}
+			if machinePool.ControlPlaneRole {
+				machineDeployment.Spec.Template.Labels[capr.ControlPlaneRoleLabel] = "true"
+				machineDeployment.Spec.Template.Labels[capi.MachineControlPlaneLabel] = "true"
+			}
 
-		if machinePool.WorkerRole {
-			machineDeployment.Spec.Template.Labels[capr.WorkerRoleLabel] = "true"
-		}
+			if machinePool.WorkerRole {
+				machineDeployment.Spec.Template.Labels[capr.WorkerRoleLabel] = "true"
+			}
 
-		machineDeployment.Spec.Template.Labels[capr.CattleOSLabel] = machineOS
+			if fd.failureDomain != "" {
+				machineDeployment.Spec.Template.Labels[capr.FailureDomainLabel] = fd.failureDomain
+			}
 
-		if len(machinePool.Labels) > 0 {
-			for k, v := range machinePool.Labels {
-				machineDeployment.Spec.Template.Labels[k] = v
+			machineDeployment.Spec.Template.Labels[capr.CattleOSLabel] = machineOS
+
+			if len(machinePool.Labels) > 0 {
+				for k, v := range machinePool.Labels {
+					machineDeployment.Spec.Template.Labels[k] = v
+				}
+				if err := assign(machineDeployment.Spec.Template.Annotations, capr.LabelsAnnotation, machinePool.Labels); err != nil {
+					return nil, err
+				}
 			}
-			if err := assign(machineDeployment.Spec.Template.Annotations, capr.LabelsAnnotation, machinePool.Labels); err != nil {
-				return nil, err
+
+			if len(machinePool.Taints) > 0 {
+				if err := assign(machineDeployment.Spec.Template.Annotations, capr.TaintsAnnotation, machinePool.Taints); err != nil {
+					return nil, err
+				}
 			}
-		}
 
-		if len(machinePool.Taints) > 0 {
-			if err := assign(machineDeployment.Spec.Template.Annotations, capr.TaintsAnnotation, machinePool.Taints); err != nil {
-				return nil, err
+			result = append(result, machineDeployment)
+
+			// if a health check timeout was specified create health checks for this machine pool
+			if machinePool.UnhealthyNodeTimeout != nil && machinePool.UnhealthyNodeTimeout.Duration > 0 {
+				hc := deploymentHealthChecks(machineDeployment, machinePool, cluster)
+				result = append(result, hc)
 			}
 		}
+	}
 
-		result = append(result, machineDeployment)
+	return result, nil
+}
 
-		// if a health check timeout was specified create health checks for this machine pool
-		if machinePool.UnhealthyNodeTimeout != nil && machinePool.UnhealthyNodeTimeout.Duration > 0 {
-			hc := deploymentHealthChecks(machineDeployment, machinePool, cluster)
-			result = append(result, hc)
-		}
+// failureDomainPool is a machine pool, or the part of it that is placed in a single failure domain.
+type failureDomainPool struct {
+	// name is the name of the machine pool, suffixed with the failure domain for all but the first failure domain.
+	name          string
+	failureDomain string
+	pool          provv1.RKEMachinePool
+}
+
+// failureDomainPools splits a machine pool into one machine pool per failure domain, each using the machine config of
+// its failure domain and an even share of the pool quantity. A machine pool without failure domains is returned as is.
+// The first failure domain keeps the name of the machine pool, so that adding failure domains to an existing machine
+// pool, such as an etcd or control plane pool, keeps its machine deployment rather than replacing all of its machines.
+func failureDomainPools(machinePool provv1.RKEMachinePool) ([]failureDomainPool, error) {
+	if len(machinePool.FailureDomains) == 0 {
+		return []failureDomainPool{{name: machinePool.Name, pool: machinePool}}, nil
+	}
+	if machinePool.AutoscalingMinSize != nil || machinePool.AutoscalingMaxSize != nil {
+		return nil, fmt.Errorf("failure domains can not be used with cluster-autoscaling on machinePool [%s]", machinePool.Name)
 	}
 
+	replicas := distributeReplicas(ptr.Deref(machinePool.Quantity, 1), len(machinePool.FailureDomains))
+	domains := map[string]bool{}
+	result := make([]failureDomainPool, 0, len(machinePool.FailureDomains))
+	for i, domain := range machinePool.FailureDomains {
+		if domain.Name == "" || domain.NodeConfig == nil || domain.NodeConfig.Name == "" {
+			return nil, fmt.Errorf("invalid failure domain [%s] on machinePool [%s] missing name or valid config", domain.Name, machinePool.Name)
+		}
+		if domain.NodeConfig.Kind != machinePool.NodeConfig.Kind {
+			return nil, fmt.Errorf("failure domain [%s] on machinePool [%s] must use a machine config of kind [%s]", domain.Name, machinePool.Name, machinePool.NodeConfig.Kind)
+		}
+		if domains[domain.Name] {
+			return nil, fmt.Errorf("duplicate failure domain name [%s] used on machinePool [%s]", domain.Name, machinePool.Name)
+		}
+		domains[domain.Name] = true
+
+		pool := *machinePool.DeepCopy()
+		pool.NodeConfig = domain.NodeConfig.DeepCopy()
+		pool.Quantity = ptr.To(replicas[i])
+		pool.FailureDomains = nil
+		poolName := machinePool.Name
+		if i > 0 {
+			poolName = name.SafeConcatName(machinePool.Name, domain.Name)
+		}
+		result = append(result, failureDomainPool{
+			name:          poolName,
+			failureDomain: domain.Name,
+			pool:          pool,
+		})
+	}
 	return result, nil
 }
 
+// etcdFailureDomainErrors returns an error for every etcd machine pool with a failure domain holding at least half of
+// the machines of the pool, as losing that failure domain causes etcd to lose quorum. It also returns whether any etcd
+// machine pool is spread across failure domains.
+func etcdFailureDomainErrors(cluster *provv1.Cluster) (errs []error, spread bool) {
+	if cluster.Spec.RKEConfig == nil {
+		return nil, false
+	}
+	for _, machinePool := range cluster.Spec.RKEConfig.MachinePools {
+		if !machinePool.EtcdRole || len(machinePool.FailureDomains) == 0 {
+			continue
+		}
+		spread = true
+
+		total := ptr.Deref(machinePool.Quantity, 1)
+		if total <= 1 {
+			continue
+		}
+		for i, replicas := range distributeReplicas(total, len(machinePool.FailureDomains)) {
+			if 2*replicas >= total {
+				errs = append(errs, fmt.Errorf("etcd machinePool [%s] has %d of its %d machines in failure domain [%s], losing it would cause etcd to lose quorum",
+					machinePool.Name, replicas, total, machinePool.FailureDomains[i].Name))
+				break
+			}
+		}
+	}
+	return errs, spread
+}
+
+// distributeReplicas spreads the given number of replicas as evenly as possible across n failure domains, with the
+// first failure domains receiving the remainder. Since the distribution only depends on the total, scaling the machine
+// pool rebalances it across its failure domains.
+func distributeReplicas(total int32, n int) []int32 {
+	result := make([]int32, n)
+	if n == 0 || total <= 0 {
+		return result
+	}
+	for i := range result {
+		result[i] = total / int32(n)
+		if int32(i) < total%int32(n) {
+			result[i]++
+		}
+	}
+	return result
+}
+
 // deploymentHealthChecks Health checks will mark a machine as failed if it has any of the conditions below for the duration of the given timeout. https://cluster-api.sigs.k8s.io/tasks/healthcheck.html#what-is-a-machinehealthcheck
 func deploymentHealthChecks(machineDeployment *capi.MachineDeployment, machinePool provv1.RKEMachinePool, cluster *provv1.Cluster) *capi.MachineHealthCheck {
 	var maxUnhealthy *intstr.IntOrString
@@ -3,8 +3,17 @@
 import (
 	"testing"
 
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
 	provv1 "github.com/rancher/rancher/pkg/apis/provisioning.cattle.io/v1"
+	"github.com/rancher/rancher/pkg/capr"
+	wfake "github.com/rancher/wrangler/v3/pkg/generic/fake"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	capi "sigs.k8s.io/cluster-api/api/core/v1beta2"
 )
 
 func TestPopulateHostnameLengthLimitAnnotation(t *testing.T) {
@@ -94,3 +103,280 @@ func TestPopulateHostnameLengthLimitAnnotation(t *testing.T) {
 		})
 	}
 }
+
+func TestDistributeReplicas(t *testing.T) {
+	tests := []struct {
+		name     string
+		total    int32
+		n        int
+		expected []int32
+	}{
+		{name: "no failure domains", total: 3, n: 0, expected: []int32{}},
+		{name: "no replicas", total: 0, n: 3, expected: []int32{0, 0, 0}},
+		{name: "even", total: 6, n: 3, expected: []int32{2, 2, 2}},
+		{name: "remainder on first domains", total: 5, n: 3, expected: []int32{2, 2, 1}},
+		{name: "fewer replicas than domains", total: 2, n: 3, expected: []int32{1, 1, 0}},
+		{name: "etcd quorum", total: 3, n: 3, expected: []int32{1, 1, 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, distributeReplicas(tt.total, tt.n))
+		})
+	}
+}
+
+func TestFailureDomainPools(t *testing.T) {
+	nodeConfig := func(name string) *corev1.ObjectReference {
+		return &corev1.ObjectReference{Kind: "Amazonec2Config", Name: name}
+	}
+
+	tests := []struct {
+		name        string
+		machinePool provv1.RKEMachinePool
+		expected    []failureDomainPool
+		expectedErr string
+	}{
+		{
+			name:        "no failure domains",
+			machinePool: provv1.RKEMachinePool{Name: "pool", NodeConfig: nodeConfig("config"), Quantity: ptr.To[int32](3)},
+			expected: []failureDomainPool{{
+				name: "pool",
+				pool: provv1.RKEMachinePool{Name: "pool", NodeConfig: nodeConfig("config"), Quantity: ptr.To[int32](3)},
+			}},
+		},
+		{
+			name: "failure domains",
+			machinePool: provv1.RKEMachinePool{Name: "pool", NodeConfig: nodeConfig("config"), Quantity: ptr.To[int32](3),
+				FailureDomains: []provv1.RKEMachinePoolFailureDomain{
+					{Name: "us-east-1a", NodeConfig: nodeConfig("config-a")},
+					{Name: "us-east-1b", NodeConfig: nodeConfig("config-b")},
+				}},
+			expected: []failureDomainPool{
+				{
+					name:          "pool",
+					failureDomain: "us-east-1a",
+					pool:          provv1.RKEMachinePool{Name: "pool", NodeConfig: nodeConfig("config-a"), Quantity: ptr.To[int32](2)},
+				},
+				{
+					name:          "pool-us-east-1b",
+					failureDomain: "us-east-1b",
+					pool:          provv1.RKEMachinePool{Name: "pool", NodeConfig: nodeConfig("config-b"), Quantity: ptr.To[int32](1)},
+				},
+			},
+		},
+		{
+			name: "unset quantity",
+			machinePool: provv1.RKEMachinePool{Name: "pool", NodeConfig: nodeConfig("config"),
+				FailureDomains: []provv1.RKEMachinePoolFailureDomain{
+					{Name: "a", NodeConfig: nodeConfig("config-a")},
+					{Name: "b", NodeConfig: nodeConfig("config-b")},
+				}},
+			expected: []failureDomainPool{
+				{name: "pool", failureDomain: "a", pool: provv1.RKEMachinePool{Name: "pool", NodeConfig: nodeConfig("config-a"), Quantity: ptr.To[int32](1)}},
+				{name: "pool-b", failureDomain: "b", pool: provv1.RKEMachinePool{Name: "pool", NodeConfig: nodeConfig("config-b"), Quantity: ptr.To[int32](0)}},
+			},
+		},
+		{
+			name: "different kind",
+			machinePool: provv1.RKEMachinePool{Name: "pool", NodeConfig: nodeConfig("config"),
+				FailureDomains: []provv1.RKEMachinePoolFailureDomain{
+					{Name: "a", NodeConfig: &corev1.ObjectReference{Kind: "VmwarevsphereConfig", Name: "config-a"}},
+				}},
+			expectedErr: "failure domain [a] on machinePool [pool] must use a machine config of kind [Amazonec2Config]",
+		},
+		{
+			name: "duplicate name",
+			machinePool: provv1.RKEMachinePool{Name: "pool", NodeConfig: nodeConfig("config"),
+				FailureDomains: []provv1.RKEMachinePoolFailureDomain{
+					{Name: "a", NodeConfig: nodeConfig("config-a")},
+					{Name: "a", NodeConfig: nodeConfig("config-b")},
+				}},
+			expectedErr: "duplicate failure domain name [a] used on machinePool [pool]",
+		},
+		{
+			name: "missing config",
+			machinePool: provv1.RKEMachinePool{Name: "pool", NodeConfig: nodeConfig("config"),
+				FailureDomains: []provv1.RKEMachinePoolFailureDomain{{Name: "a"}}},
+			expectedErr: "invalid failure domain [a] on machinePool [pool] missing name or valid config",
+		},
+		{
+			name: "autoscaling",
+			machinePool: provv1.RKEMachinePool{Name: "pool", NodeConfig: nodeConfig("config"), AutoscalingMinSize: ptr.To[int32](1),
+				FailureDomains: []provv1.RKEMachinePoolFailureDomain{{Name: "a", NodeConfig: nodeConfig("config-a")}}},
+			expectedErr: "failure domains can not be used with cluster-autoscaling on machinePool [pool]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := failureDomainPools(tt.machinePool)
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestMachineDeploymentsFailureDomains(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	dynamicSchemaCache := wfake.NewMockNonNamespacedCacheInterface[*v3.DynamicSchema](ctrl)
+
+	nodeConfig := func(name string) *corev1.ObjectReference {
+		return &corev1.ObjectReference{APIVersion: "infrastructure.cluster.x-k8s.io/v1beta1", Kind: "AWSMachineTemplate", Name: name}
+	}
+	cluster := &provv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: "fleet-default"},
+		Spec: provv1.ClusterSpec{
+			RKEConfig: &provv1.RKEConfig{
+				MachinePools: []provv1.RKEMachinePool{{
+					Name:       "pool",
+					EtcdRole:   true,
+					NodeConfig: nodeConfig("config"),
+					Quantity:   ptr.To[int32](5),
+					FailureDomains: []provv1.RKEMachinePoolFailureDomain{
+						{Name: "a", NodeConfig: nodeConfig("config-a")},
+						{Name: "b", NodeConfig: nodeConfig("config-b")},
+						{Name: "c", NodeConfig: nodeConfig("config-c")},
+					},
+				}},
+			},
+		},
+	}
+	capiCluster := &capi.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: "fleet-default"},
+		Spec: capi.ClusterSpec{
+			InfrastructureRef: capi.ContractVersionedObjectReference{APIGroup: "infrastructure.cluster.x-k8s.io", Kind: "AWSCluster", Name: "cluster"},
+		},
+	}
+
+	objs, err := machineDeployments(cluster, capiCluster, nil, dynamicSchemaCache, nil)
+	require.NoError(t, err)
+
+	var deployments []*capi.MachineDeployment
+	for _, obj := range objs {
+		if md, ok := obj.(*capi.MachineDeployment); ok {
+			deployments = append(deployments, md)
+		}
+	}
+	require.Len(t, deployments, 3)
+
+	expected := []struct {
+		name       string
+		domain     string
+		nodeConfig string
+		replicas   int32
+	}{
+		{name: "cluster-pool", domain: "a", nodeConfig: "config-a", replicas: 2},
+		{name: "cluster-pool-b", domain: "b", nodeConfig: "config-b", replicas: 2},
+		{name: "cluster-pool-c", domain: "c", nodeConfig: "config-c", replicas: 1},
+	}
+	for i, md := range deployments {
+		assert.Equal(t, expected[i].name, md.Name)
+		assert.Equal(t, expected[i].replicas, *md.Spec.Replicas)
+		assert.Equal(t, expected[i].nodeConfig, md.Spec.Template.Spec.InfrastructureRef.Name)
+		assert.Equal(t, expected[i].domain, md.Spec.Template.Labels[capr.FailureDomainLabel])
+		assert.Equal(t, "pool", md.Spec.Template.Labels[capr.RKEMachinePoolNameLabel])
+		assert.Equal(t, expected[i].name, md.Spec.Template.Labels[capi.MachineDeploymentNameLabel])
+	}
+}
+
+func TestMachineDeploymentsFailureDomainCollision(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	dynamicSchemaCache := wfake.NewMockNonNamespacedCacheInterface[*v3.DynamicSchema](ctrl)
+
+	nodeConfig := func(name string) *corev1.ObjectReference {
+		return &corev1.ObjectReference{APIVersion: "infrastructure.cluster.x-k8s.io/v1beta1", Kind: "AWSMachineTemplate", Name: name}
+	}
+	cluster := &provv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: "fleet-default"},
+		Spec: provv1.ClusterSpec{
+			RKEConfig: &provv1.RKEConfig{
+				MachinePools: []provv1.RKEMachinePool{
+					{
+						Name:       "worker",
+						WorkerRole: true,
+						NodeConfig: nodeConfig("config"),
+						FailureDomains: []provv1.RKEMachinePoolFailureDomain{
+							{Name: "a", NodeConfig: nodeConfig("config-a")},
+							{Name: "b", NodeConfig: nodeConfig("config-b")},
+						},
+					},
+					{
+						Name:       "worker-b",
+						WorkerRole: true,
+						NodeConfig: nodeConfig("config"),
+					},
+				},
+			},
+		},
+	}
+	capiCluster := &capi.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: "fleet-default"},
+		Spec: capi.ClusterSpec{
+			InfrastructureRef: capi.ContractVersionedObjectReference{APIGroup: "infrastructure.cluster.x-k8s.io", Kind: "AWSCluster", Name: "cluster"},
+		},
+	}
+
+	_, err := machineDeployments(cluster, capiCluster, nil, dynamicSchemaCache, nil)
+	assert.EqualError(t, err, "machinePool [worker] and machinePool [worker-b] both generate the machine deployment [cluster-worker-b]")
+}
+
+func TestEtcdFailureDomainErrors(t *testing.T) {
+	domains := func(names ...string) []provv1.RKEMachinePoolFailureDomain {
+		var result []provv1.RKEMachinePoolFailureDomain
+		for _, name := range names {
+			result = append(result, provv1.RKEMachinePoolFailureDomain{Name: name})
+		}
+		return result
+	}
+
+	tests := []struct {
+		name           string
+		pools          []provv1.RKEMachinePool
+		expectedSpread bool
+		expectedErrs   []string
+	}{
+		{
+			name:  "no failure domains",
+			pools: []provv1.RKEMachinePool{{Name: "etcd", EtcdRole: true, Quantity: ptr.To[int32](3)}},
+		},
+		{
+			name:  "worker pool",
+			pools: []provv1.RKEMachinePool{{Name: "worker", WorkerRole: true, Quantity: ptr.To[int32](3), FailureDomains: domains("a", "b")}},
+		},
+		{
+			name:           "balanced",
+			pools:          []provv1.RKEMachinePool{{Name: "etcd", EtcdRole: true, Quantity: ptr.To[int32](5), FailureDomains: domains("a", "b", "c")}},
+			expectedSpread: true,
+		},
+		{
+			name:           "two failure domains",
+			pools:          []provv1.RKEMachinePool{{Name: "etcd", EtcdRole: true, Quantity: ptr.To[int32](3), FailureDomains: domains("a", "b")}},
+			expectedSpread: true,
+			expectedErrs:   []string{"etcd machinePool [etcd] has 2 of its 3 machines in failure domain [a], losing it would cause etcd to lose quorum"},
+		},
+		{
+			name:           "even quantity",
+			pools:          []provv1.RKEMachinePool{{Name: "etcd", EtcdRole: true, Quantity: ptr.To[int32](4), FailureDomains: domains("a", "b", "c")}},
+			expectedSpread: true,
+			expectedErrs:   []string{"etcd machinePool [etcd] has 2 of its 4 machines in failure domain [a], losing it would cause etcd to lose quorum"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cluster := &provv1.Cluster{Spec: provv1.ClusterSpec{RKEConfig: &provv1.RKEConfig{MachinePools: tt.pools}}}
+			errs, spread := etcdFailureDomainErrors(cluster)
+			assert.Equal(t, tt.expectedSpread, spread)
+			var messages []string
+			for _, err := range errs {
+				messages = append(messages, err.Error())
+			}
+			assert.Equal(t, tt.expectedErrs, messages)
+		})
+	}
+}
@@ -7,10 +7,14 @@
 	"strings"
 	"time"
 
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
 	provv1 "github.com/rancher/rancher/pkg/apis/provisioning.cattle.io/v1"
 	"github.com/rancher/rancher/pkg/capr"
+	"github.com/rancher/rancher/pkg/clusterprovisioninglogger"
 	provcluster "github.com/rancher/rancher/pkg/controllers/provisioningv2/cluster"
+	mgmtcontrollers "github.com/rancher/rancher/pkg/generated/controllers/management.cattle.io/v3"
 	provisioningcontrollers "github.com/rancher/rancher/pkg/generated/controllers/provisioning.cattle.io/v1"
+	"github.com/rancher/rancher/pkg/settings"
 	"github.com/rancher/rancher/pkg/wrangler"
 	corev1controllers "github.com/rancher/wrangler/v3/pkg/generated/controllers/core/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -32,16 +36,21 @@ func Register(ctx context.Context, clients *wrangler.CAPIContext) {
 		configMapsCache: clients.Core.ConfigMap().Cache(),
 		configMaps:      clients.Core.ConfigMap(),
 		clusterCache:    clients.Provisioning.Cluster().Cache(),
+		events:          clusterprovisioninglogger.NewRecorder(clients.Mgmt.ProvisioningEvent()),
+		eventController: clients.Mgmt.ProvisioningEvent(),
 	}
 
 	clients.Core.Namespace().OnChange(ctx, "prov-log-namespace", h.OnNamespace)
 	clients.Core.ConfigMap().OnChange(ctx, "prov-log-configmap", h.OnConfigMap)
+	clients.Mgmt.ProvisioningEvent().OnChange(ctx, "prov-event-retention", h.OnProvisioningEvent)
 }
 
 type handler struct {
 	configMapsCache corev1controllers.ConfigMapCache
 	configMaps      corev1controllers.ConfigMapController
 	clusterCache    provisioningcontrollers.ClusterCache
+	events          *clusterprovisioninglogger.Recorder
+	eventController mgmtcontrollers.ProvisioningEventController
 }
 
 func (h *handler) OnConfigMap(_ string, cm *corev1.ConfigMap) (*corev1.ConfigMap, error) {
@@ -92,11 +101,13 @@ func (h *handler) recordMessage(cluster *provv1.Cluster, cm *corev1.ConfigMap) (
 	msg := capr.Provisioned.GetMessage(cluster)
 	failure := capr.Provisioned.IsFalse(cluster)
 	done := capr.Provisioned.IsTrue(cluster)
+	phase := "Provisioning"
 
 	if done && msg == "" {
 		done = capr.Updated.IsTrue(cluster)
 		msg = capr.Updated.GetMessage(cluster)
 		failure = capr.Updated.IsFalse(cluster)
+		phase = "Updating"
 	}
 
 	if done && msg == "" && cluster.Status.Ready {
@@ -107,8 +118,13 @@ func (h *handler) recordMessage(cluster *provv1.Cluster, cm *corev1.ConfigMap) (
 		return cm, nil
 	}
 
+	severity := v3.ProvisioningEventSeverityInfo
+	if failure {
+		severity = v3.ProvisioningEventSeverityError
+	}
 	if strings.Contains(msg, "the object has been modified; please apply your changes to the latest version and try again") {
 		msg = fmt.Sprintf("Transient error encountered: %s", msg)
+		severity = v3.ProvisioningEventSeverityWarning
 	}
 
 	last := cm.Data["last"]
@@ -116,6 +132,15 @@ func (h *handler) recordMessage(cluster *provv1.Cluster, cm *corev1.ConfigMap) (
 		return cm, nil
 	}
 
+	if err := h.events.Record(cm.Namespace, v3.ProvisioningEventSpec{
+		Phase:    phase,
+		Severity: severity,
+		Source:   v3.ProvisioningEventSourcePlanner,
+		Message:  msg,
+	}); err != nil {
+		return cm, err
+	}
+
 	cm = cm.DeepCopy()
 	if cm.Data == nil {
 		cm.Data = map[string]string{}
@@ -151,3 +176,23 @@ func (h *handler) OnNamespace(_ string, ns *corev1.Namespace) (*corev1.Namespace
 	}
 	return ns, nil
 }
+
+// OnProvisioningEvent deletes provisioning events once they are older than the provisioning-event-retention-hours
+// setting.
+func (h *handler) OnProvisioningEvent(_ string, event *v3.ProvisioningEvent) (*v3.ProvisioningEvent, error) {
+	if event == nil || !event.DeletionTimestamp.IsZero() {
+		return event, nil
+	}
+	retention := time.Duration(settings.ProvisioningEventRetentionHours.GetInt()) * time.Hour
+	if retention <= 0 {
+		return event, nil
+	}
+	if expiresIn := time.Until(event.Spec.Timestamp.Add(retention)); expiresIn > 0 {
+		h.eventController.EnqueueAfter(event.Namespace, event.Name, expiresIn)
+		return event, nil
+	}
+	if err := h.eventController.Delete(event.Namespace, event.Name, &metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+		return event, err
+	}
+	return event, nil
+}
@@ -2,9 +2,17 @@
 
 import (
 	"fmt"
-	"github.com/stretchr/testify/assert"
 	"strings"
 	"testing"
+	"time"
+
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/settings"
+	"github.com/rancher/wrangler/v3/pkg/generic/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 var runes = []byte("abcdefghijklmnopqrstuvwxyz")
@@ -69,3 +77,54 @@ func TestAppendLog(t *testing.T) {
 		})
 	}
 }
+
+func TestOnProvisioningEvent(t *testing.T) {
+	tests := []struct {
+		name      string
+		age       time.Duration
+		retention string
+		deleted   bool
+	}{
+		{
+			name: "within retention",
+			age:  time.Hour,
+		},
+		{
+			name:    "expired",
+			age:     31 * 24 * time.Hour,
+			deleted: true,
+		},
+		{
+			name:      "retention disabled",
+			age:       31 * 24 * time.Hour,
+			retention: "0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.retention != "" {
+				require.NoError(t, settings.ProvisioningEventRetentionHours.Set(tt.retention))
+				t.Cleanup(func() {
+					_ = settings.ProvisioningEventRetentionHours.Set(settings.ProvisioningEventRetentionHours.Default)
+				})
+			}
+
+			ctrl := gomock.NewController(t)
+			events := fake.NewMockControllerInterface[*v3.ProvisioningEvent, *v3.ProvisioningEventList](ctrl)
+			event := &v3.ProvisioningEvent{
+				ObjectMeta: metav1.ObjectMeta{Name: "event", Namespace: "c-m-abc"},
+				Spec:       v3.ProvisioningEventSpec{Timestamp: metav1.NewTime(time.Now().Add(-tt.age))},
+			}
+			if tt.deleted {
+				events.EXPECT().Delete("c-m-abc", "event", gomock.Any()).Return(nil)
+			} else if tt.retention == "" {
+				events.EXPECT().EnqueueAfter("c-m-abc", "event", gomock.Any())
+			}
+
+			h := &handler{eventController: events}
+			_, err := h.OnProvisioningEvent("", event)
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -31,6 +31,9 @@ func RequiredCRDs() []string {
 	if features.OIDCProvider.Enabled() {
 		requiredCRDS = append(requiredCRDS, OIDCClientCRD()...)
 	}
+	if features.BreakGlassCredentials.Enabled() {
+		requiredCRDS = append(requiredCRDS, BreakGlassCRD()...)
+	}
 	if features.ImportedDay2Ops.Enabled() {
 		requiredCRDS = append(requiredCRDS, PlanCRDs()...)
 		requiredCRDS = append(requiredCRDS, OperationCRDs()...)
@@ -76,6 +79,7 @@ func BasicCRDs() []string {
 func ProvisioningV2CRDs() []string {
 	return []string{
 		"clusters.provisioning.cattle.io",
+		"clustertemplates.provisioning.cattle.io",
 	}
 }
 
@@ -145,6 +149,7 @@ func ClusterAuthCRDs() []string {
 func MCMCRDs() []string {
 	return []string{
 		"authconfigs.management.cattle.io",
+		"certificateauthorityrotations.management.cattle.io",
 		"clusters.management.cattle.io",
 		"clusterregistrationtokens.management.cattle.io",
 		"clusterroletemplatebindings.management.cattle.io",
@@ -157,6 +162,8 @@ func MCMCRDs() []string {
 		"groups.management.cattle.io",
 		"groupmembers.management.cattle.io",
 		"kontainerdrivers.management.cattle.io",
+		"managementbackups.management.cattle.io",
+		"managementrestores.management.cattle.io",
 		"monitormetrics.management.cattle.io",
 		"nodes.management.cattle.io",
 		"nodedrivers.management.cattle.io",
@@ -166,6 +173,7 @@ func MCMCRDs() []string {
 		"projects.management.cattle.io",
 		"projectnetworkpolicys.management.cattle.io",
 		"projectroletemplatebindings.management.cattle.io",
+		"provisioningevents.management.cattle.io",
 		"rancherusernotificationtypes.management.cattle.io",
 		"roletemplates.management.cattle.io",
 		"samltokens.management.cattle.io",
@@ -176,6 +184,8 @@ func MCMCRDs() []string {
 		"tokens.management.cattle.io",
 		"users.management.cattle.io",
 		"userattributes.management.cattle.io",
+		"userretentionpolicies.management.cattle.io",
+		"workloadidentitybindings.management.cattle.io",
 	}
 }
 
@@ -193,6 +203,13 @@ func OIDCClientCRD() []string {
 	}
 }
 
+// BreakGlassCRD returns a list of CRD names needed to enable break-glass credentials
+func BreakGlassCRD() []string {
+	return []string{
+		"breakglasscredentials.management.cattle.io",
+	}
+}
+
 // TelemetryCRDs returns a list of required CRD names needed for rancher telemetry
 func TelemetryCRDs() []string {
 	return []string{
@@ -211,6 +228,8 @@ func OperationCRDs() []string {
 		"encryptionkeyrotations.operation.cattle.io",
 		"etcdsnapshotsaves.operation.cattle.io",
 		"etcdsnapshotrestores.operation.cattle.io",
+		"supportbundles.operation.cattle.io",
+		"nodediagnostics.operation.cattle.io",
 	}
 }
 
@@ -226,7 +245,9 @@ func OperationCRDs() []string {
 	"azureadproviders.management.cattle.io":                           false,
 	"basicauths.project.cattle.io":                                    false,
 	"beacons.plan.cattle.io":                                          true,
+	"breakglasscredentials.management.cattle.io":                      true,
 	"certificates.project.cattle.io":                                  false,
+	"certificateauthorityrotations.management.cattle.io":              true,
 	"cloudcredentials.management.cattle.io":                           false,
 	"clusterauthtokens.cluster.cattle.io":                             false,
 	"clusterclasses.cluster.x-k8s.io":                                 false,
@@ -238,6 +259,7 @@ func OperationCRDs() []string {
 	"clusters.cluster.x-k8s.io":                                       false,
 	"clusters.management.cattle.io":                                   false,
 	"clusters.provisioning.cattle.io":                                 true,
+	"clustertemplates.provisioning.cattle.io":                         true,
 	"clusteruserattributes.cluster.cattle.io":                         false,
 	"composeconfigs.management.cattle.io":                             false,
 	"custommachines.rke.cattle.io":                                    true,
@@ -264,8 +286,11 @@ func OperationCRDs() []string {
 	"machines.cluster.x-k8s.io":                                       false,
 	"machinesets.cluster.x-k8s.io":                                    false,
 	"managedcharts.management.cattle.io":                              false,
+	"managementbackups.management.cattle.io":                          true,
+	"managementrestores.management.cattle.io":                         true,
 	"monitormetrics.management.cattle.io":                             false,
 	"navlinks.ui.cattle.io":                                           false,
+	"nodediagnostics.operation.cattle.io":                             true,
 	"nodedrivers.management.cattle.io":                                true,
 	"nodepools.management.cattle.io":                                  false,
 	"nodes.management.cattle.io":                                      false,
@@ -279,6 +304,7 @@ func OperationCRDs() []string {
 	"projectnetworkpolicies.management.cattle.io":                     false,
 	"projectroletemplatebindings.management.cattle.io":                true,
 	"projects.management.cattle.io":                                   true,
+	"provisioningevents.management.cattle.io":                         true,
 	"rancherusernotifications.management.cattle.io":                   false,
 	"rkebootstraps.rke.cattle.io":                                     true,
 	"rkebootstraptemplates.rke.cattle.io":                             true,
@@ -291,13 +317,16 @@ func OperationCRDs() []string {
 	"serviceaccounttokens.project.cattle.io":                          false,
 	"settings.management.cattle.io":                                   false,
 	"sshauths.project.cattle.io":                                      false,
+	"supportbundles.operation.cattle.io":                              true,
 	"templatecontents.management.cattle.io":                           false,
 	"templates.management.cattle.io":                                  false,
 	"templateversions.management.cattle.io":                           false,
 	"tokens.management.cattle.io":                                     false,
 	"userattributes.management.cattle.io":                             false,
+	"userretentionpolicies.management.cattle.io":                      true,
 	"users.management.cattle.io":                                      true,
 	"uiplugins.catalog.cattle.io":                                     true,
+	"workloadidentitybindings.management.cattle.io":                   true,
 	"workloads.project.cattle.io":                                     false,
 	"proxyendpoints.management.cattle.io":                             true,
 }
@@ -3,6 +3,7 @@
 import (
 	"context"
 
+	"github.com/rancher/rancher/pkg/auth/creatorid"
 	"github.com/rancher/rancher/pkg/auth/data"
 	"github.com/rancher/rancher/pkg/settings"
 	"github.com/rancher/rancher/pkg/types/config"
@@ -44,5 +45,9 @@ func Add(_ context.Context, wrangler *wrangler.Context, management *config.Manag
 		return err
 	}
 
+	if err := creatorid.Ensure(wrangler.Apply, wrangler.K8s.Discovery()); err != nil {
+		return err
+	}
+
 	return AddProxyEndpointData(settings.DisableDefaultProxyEndpoint.Get(), management.Wrangler)
 }
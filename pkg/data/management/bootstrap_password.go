@@ -6,6 +6,7 @@
 
 	"github.com/sirupsen/logrus"
 
+	"github.com/rancher/rancher/pkg/auth/providers/local/passwordpolicy"
 	"github.com/rancher/wrangler/v3/pkg/randomtoken"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -68,3 +69,18 @@ func GetBootstrapPassword(ctx context.Context, secrets corev1.SecretInterface) (
 
 	return userPasswordValue, generated, nil
 }
+
+// mustChangeBootstrapPassword returns true if the default admin must change the bootstrap password at their first
+// login. The bootstrap password isn't rejected when it doesn't comply with the password policy, as Rancher would then
+// have no admin. Generated passwords and passwords not complying with the policy must be changed instead, and the
+// policy applies to the new password.
+func mustChangeBootstrapPassword(password string, generated bool, policy passwordpolicy.Policy) bool {
+	if generated || password == "admin" {
+		return true
+	}
+	if err := policy.Validate(password); err != nil {
+		logrus.Warnf("The bootstrap password must be changed at the first login: %v", err)
+		return true
+	}
+	return false
+}
@@ -0,0 +1,52 @@
+package management
+
+import (
+	"testing"
+
+	"github.com/rancher/rancher/pkg/auth/providers/local/passwordpolicy"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMustChangeBootstrapPassword(t *testing.T) {
+	policy := passwordpolicy.Policy{
+		MinLength:       12,
+		RequiredClasses: []string{passwordpolicy.ClassUpper, passwordpolicy.ClassDigit},
+		BlockedWords:    []string{"rancher"},
+	}
+
+	tests := map[string]struct {
+		password  string
+		generated bool
+		want      bool
+	}{
+		"complying password": {
+			password: "Correct-Horse-42",
+		},
+		"generated password": {
+			password:  "Correct-Horse-42",
+			generated: true,
+			want:      true,
+		},
+		"admin": {
+			password: "admin",
+			want:     true,
+		},
+		"too short": {
+			password: "Horse-42",
+			want:     true,
+		},
+		"missing character class": {
+			password: "correct-horse-42",
+			want:     true,
+		},
+		"blocked word": {
+			password: "Rancher-Horse-42",
+			want:     true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tt.want, mustChangeBootstrapPassword(tt.password, tt.generated, policy))
+		})
+	}
+}
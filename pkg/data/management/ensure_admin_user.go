@@ -104,7 +104,10 @@ func ensureDefaultAdmin() {
 }
 
 func createNewAdmin(client v3.Interface, length int, secretLister wranglerv1.SecretCache, secretClient wranglerv1.SecretClient, userLister mgmtcontrollers.UserCache) error {
-	pass := generatePassword(length)
+	pass, err := generatePassword(client, length)
+	if err != nil {
+		return err
+	}
 
 	admin, err := client.Users("").Create(&v3.User{
 		ObjectMeta: v1.ObjectMeta{
@@ -121,7 +124,7 @@ func createNewAdmin(client v3.Interface, length int, secretLister wranglerv1.Sec
 	}
 
 	pwdCreator := pbkdf2.New(secretLister, secretClient)
-	if err := pwdCreator.CreatePassword(admin, string(pass)); err != nil {
+	if err := pwdCreator.CreatePassword(admin, pass); err != nil {
 		return httperror.NewAPIError(httperror.InvalidBodyContent, err.Error())
 	}
 
@@ -2,15 +2,15 @@
 
 import (
 	"context"
-	"crypto/rand"
 	"fmt"
-	"log"
 	"os"
 
 	"github.com/moby/sys/reexec"
 	"github.com/pkg/errors"
+	"github.com/rancher/rancher/pkg/auth/providers/local/passwordpolicy"
 	"github.com/rancher/rancher/pkg/auth/providers/local/pbkdf2"
 	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/settings"
 	"github.com/rancher/rancher/pkg/wrangler"
 	"github.com/urfave/cli"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -25,11 +25,17 @@ func RegisterPasswordResetCommand() {
 	reexec.Register("reset-password", resetPassword)
 }
 
-const (
-	length     = 20
-	cost       = 10
-	characters = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_"
-)
+const length = 20
+
+// passwordSettings are the settings of the password policy and history. The commands setting the default admin
+// password run outside of the Rancher server, so they read these settings from the cluster.
+var passwordSettings = []settings.Setting{
+	settings.PasswordMinLength,
+	settings.PasswordRequiredCharacterClasses,
+	settings.PasswordBlockedWords,
+	settings.PasswordBreachedHashesFile,
+	settings.PasswordHistorySize,
+}
 
 func resetPassword() {
 	app := cli.NewApp()
@@ -68,7 +74,10 @@ func resetPassword() {
 		}
 
 		admin := admins.Items[0]
-		pass := generatePassword(length)
+		pass, err := generatePassword(client, length)
+		if err != nil {
+			return fmt.Errorf("couldn't generate password %w", err)
+		}
 		wranglerContext, err := wrangler.NewContext(context.Background(), nil, conf)
 		if err != nil {
 			return fmt.Errorf("couldn't create wrangler context %w", err)
@@ -83,9 +92,9 @@ func resetPassword() {
 			return fmt.Errorf("couldn't start wrangler cache for secrets %w", err)
 		}
 		pwdCreator := pbkdf2.New(wranglerContext.Core.Secret().Cache(), wranglerContext.Core.Secret())
-		if err := pwdCreator.UpdatePassword(admin.Name, string(pass)); err != nil {
+		if err := pwdCreator.UpdatePassword(admin.Name, pass); err != nil {
 			if apierrors.IsNotFound(err) {
-				if err := pwdCreator.CreatePassword(&admin, string(pass)); err != nil {
+				if err := pwdCreator.CreatePassword(&admin, pass); err != nil {
 					return fmt.Errorf("couldn't create password %w", err)
 				}
 			} else {
@@ -110,18 +119,32 @@ func resetPassword() {
 	}
 }
 
-func generatePassword(length int) []byte {
-	bytes := make([]byte, length)
-	_, err := rand.Read(bytes)
-	if err != nil {
-		log.Fatal(err)
+// generatePassword returns a random password complying with the password policy configured in the cluster.
+func generatePassword(client v3.Interface, length int) (string, error) {
+	if err := loadPasswordSettings(client); err != nil {
+		return "", err
 	}
+	return passwordpolicy.FromSettings().Generate(length)
+}
 
-	out := make([]byte, length)
-	for i := range out {
-		index := uint8(bytes[i]) % uint8(len(characters))
-		out[i] = characters[index]
+func loadPasswordSettings(client v3.Interface) error {
+	for _, setting := range passwordSettings {
+		s, err := client.Settings("").Get(setting.Name, v1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return fmt.Errorf("couldn't get setting %s: %w", setting.Name, err)
+		}
+		value := s.Value
+		if value == "" {
+			value = s.Default
+		}
+		if value == "" {
+			continue
+		}
+		if err := setting.Set(value); err != nil {
+			return fmt.Errorf("couldn't set setting %s: %w", setting.Name, err)
+		}
 	}
-
-	return out
+	return nil
 }
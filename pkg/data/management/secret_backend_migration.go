@@ -0,0 +1,191 @@
+package management
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/moby/sys/reexec"
+	"github.com/rancher/rancher/pkg/controllers/management/cloudcredential"
+	"github.com/rancher/rancher/pkg/controllers/management/cluster"
+	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/namespace"
+	"github.com/rancher/rancher/pkg/secretbackend"
+	"github.com/rancher/rancher/pkg/settings"
+	"github.com/urfave/cli"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+func RegisterSecretBackendMigrationCommand() {
+	reexec.Register("/usr/bin/migrate-secrets-to-backend", migrateSecretsToBackend)
+	reexec.Register("migrate-secrets-to-backend", migrateSecretsToBackend)
+}
+
+func migrateSecretsToBackend() {
+	app := cli.NewApp()
+	app.Description = "Move the cloud credentials and auth provider secrets to the configured secret backend"
+	app.Flags = []cli.Flag{
+		cli.BoolFlag{
+			Name:  "dry-run",
+			Usage: "List the secrets that would be moved without moving them",
+		},
+	}
+
+	app.Action = func(c *cli.Context) error {
+		kubeConfigPath := os.ExpandEnv("$HOME/.kube/config")
+		if _, err := os.Stat(kubeConfigPath); err != nil {
+			kubeConfigPath = ""
+		}
+
+		conf, err := clientcmd.BuildConfigFromFlags("", kubeConfigPath)
+		if err != nil {
+			return fmt.Errorf("couldn't get kubeconfig: %w", err)
+		}
+		client, err := v3.NewForConfig(*conf)
+		if err != nil {
+			return fmt.Errorf("couldn't get management client: %w", err)
+		}
+		k8s, err := kubernetes.NewForConfig(conf)
+		if err != nil {
+			return fmt.Errorf("couldn't get kubernetes client: %w", err)
+		}
+
+		config, err := secretBackendConfig(client)
+		if err != nil {
+			return err
+		}
+		backend, err := secretbackend.New(config)
+		if err != nil {
+			return err
+		}
+		if backend == nil {
+			return fmt.Errorf("the %s setting is not set", settings.SecretBackend.Name)
+		}
+
+		secrets, err := secretsToMigrate(client, k8s)
+		if err != nil {
+			return err
+		}
+
+		ctx := context.Background()
+		var failed int
+		for _, secret := range secrets {
+			if c.Bool("dry-run") {
+				fmt.Fprintf(os.Stdout, "Would move secret %s/%s to the %s secret backend\n", secret.Namespace, secret.Name, backend.Name())
+				continue
+			}
+			stored, err := secretbackend.Store(ctx, backend, secret)
+			if err == nil {
+				_, err = k8s.CoreV1().Secrets(secret.Namespace).Update(ctx, stored, metav1.UpdateOptions{})
+			}
+			if err != nil {
+				failed++
+				fmt.Fprintf(os.Stderr, "Failed to move secret %s/%s: %v\n", secret.Namespace, secret.Name, err)
+				continue
+			}
+			fmt.Fprintf(os.Stdout, "Moved secret %s/%s to the %s secret backend\n", secret.Namespace, secret.Name, backend.Name())
+		}
+		if failed > 0 {
+			return fmt.Errorf("failed to move %d of %d secrets, run the command again to retry", failed, len(secrets))
+		}
+		return nil
+	}
+
+	if err := app.Run(os.Args); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// secretBackendConfig returns the backend configuration from the secret-backend settings of the cluster.
+func secretBackendConfig(client v3.Interface) (secretbackend.Config, error) {
+	get := func(setting settings.Setting) (string, error) {
+		s, err := client.Settings("").Get(setting.Name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			return setting.Default, nil
+		} else if err != nil {
+			return "", fmt.Errorf("couldn't get setting %s: %w", setting.Name, err)
+		}
+		if s.Value != "" {
+			return s.Value, nil
+		}
+		return s.Default, nil
+	}
+
+	var config secretbackend.Config
+	for field, setting := range map[*string]settings.Setting{
+		&config.Backend:         settings.SecretBackend,
+		&config.VaultAddress:    settings.SecretBackendVaultAddress,
+		&config.VaultKVMount:    settings.SecretBackendVaultKVMount,
+		&config.VaultPathPrefix: settings.SecretBackendVaultPathPrefix,
+		&config.VaultAuthMount:  settings.SecretBackendVaultAuthMount,
+		&config.VaultAuthRole:   settings.SecretBackendVaultAuthRole,
+		&config.VaultCACerts:    settings.SecretBackendVaultCACerts,
+	} {
+		value, err := get(setting)
+		if err != nil {
+			return config, err
+		}
+		*field = value
+	}
+	return config, nil
+}
+
+// secretsToMigrate returns the cloud credentials and auth provider secrets that are not stored in a backend yet.
+// Cloud credentials used by hosted clusters are kept in Kubernetes, as the operators of the clusters read them from it.
+func secretsToMigrate(client v3.Interface, k8s kubernetes.Interface) ([]*corev1.Secret, error) {
+	authConfigs, err := client.AuthConfigs("").List(metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("couldn't list auth configs: %w", err)
+	}
+	var authPrefixes []string
+	for _, authConfig := range authConfigs.Items {
+		if authConfig.Type != "" {
+			authPrefixes = append(authPrefixes, strings.ToLower(authConfig.Type)+"-")
+		}
+	}
+
+	clusters, err := client.Clusters("").List(metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("couldn't list clusters: %w", err)
+	}
+	hostedCredentials := map[string]bool{}
+	for i := range clusters.Items {
+		credentials, err := cluster.ByCloudCredentialIndexer(&clusters.Items[i])
+		if err != nil {
+			return nil, err
+		}
+		for _, credential := range credentials {
+			hostedCredentials[credential] = true
+		}
+	}
+
+	secrets, err := k8s.CoreV1().Secrets(namespace.GlobalNamespace).List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("couldn't list secrets: %w", err)
+	}
+	var result []*corev1.Secret
+	for i := range secrets.Items {
+		secret := &secrets.Items[i]
+		if secretbackend.IsReference(secret) || secret.DeletionTimestamp != nil {
+			continue
+		}
+		authProviderSecret := false
+		for _, prefix := range authPrefixes {
+			if strings.HasPrefix(secret.Name, prefix) {
+				authProviderSecret = true
+				break
+			}
+		}
+		cloudCredential := cloudcredential.IsCloudCredential(secret) && !hostedCredentials[secret.Namespace+":"+secret.Name]
+		if authProviderSecret || cloudCredential {
+			result = append(result, secret)
+		}
+	}
+	return result, nil
+}
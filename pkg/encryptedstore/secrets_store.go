@@ -1,15 +1,20 @@
 package encryptedstore
 
 import (
+	"context"
+	stderrors "errors"
 	"reflect"
+	"slices"
 	"time"
 
 	v1 "github.com/rancher/rancher/pkg/generated/norman/core/v1"
+	"github.com/rancher/rancher/pkg/secretbackend"
 	"github.com/sirupsen/logrus"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/retry"
 )
 
 const (
@@ -53,8 +58,13 @@ func (g *GenericEncryptedStore) Get(name string) (map[string]string, error) {
 		return nil, err
 	}
 
+	data, err := secretbackend.Data(context.TODO(), sec)
+	if err != nil {
+		return nil, err
+	}
+
 	result := map[string]string{}
-	for k, v := range sec.Data {
+	for k, v := range data {
 		result[k] = string(v)
 	}
 
@@ -71,21 +81,37 @@ func (g *GenericEncryptedStore) Set(name string, data map[string]string, owner *
 
 func (g *GenericEncryptedStore) set(name string, data map[string]string, owner *metav1.OwnerReference) error {
 	logrus.Debugf("[GenericEncryptedStore]: set secret called for %v", g.getKey(name))
+	backend, err := secretbackend.Configured()
+	if err != nil {
+		return err
+	}
 	sec, err := g.secretLister.Get(g.namespace, g.getKey(name))
+	if err == nil && (backend != nil || secretbackend.IsReference(sec)) {
+		return g.setInBackend(backend, name, data, owner)
+	}
 	if errors.IsNotFound(err) {
 		logrus.Debugf("[GenericEncryptedStore]: Creating secret for %v", g.getKey(name))
 		sec = &corev1.Secret{}
 		sec.Name = g.getKey(name)
+		sec.Namespace = g.namespace
 		sec.StringData = data
 		if owner != nil {
 			sec.SetOwnerReferences([]metav1.OwnerReference{*owner})
 		}
+		if backend != nil {
+			if sec, err = secretbackend.Store(context.TODO(), backend, sec); err != nil {
+				return err
+			}
+		}
 		if _, err := g.secrets.Create(sec); err != nil {
 			if !errors.IsAlreadyExists(err) {
 				return err
 			}
 			logrus.Debugf("[GenericEncryptedStore]: secret %v already exists, updating secret", sec.Name)
 			// if secret already exists, update it with the current cluster status
+			if backend != nil {
+				return g.setInBackend(backend, name, data, owner)
+			}
 			return g.updateSecretWithBackoff(name, data)
 		}
 		return nil
@@ -152,6 +178,46 @@ func (g *GenericEncryptedStore) updateSecretWithBackoff(name string, data map[st
 	})
 }
 
+// setInBackend merges the data into the data of the secret stored in the secret backend, and updates the reference to it.
+func (g *GenericEncryptedStore) setInBackend(backend secretbackend.Backend, name string, data map[string]string, owner *metav1.OwnerReference) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		secret, err := g.secrets.GetNamespaced(g.namespace, g.getKey(name), metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		if backend == nil {
+			// The secret is stored in a backend that is no longer configured, which Data reports.
+			_, err = secretbackend.Data(context.TODO(), secret)
+			return err
+		}
+
+		current, err := secretbackend.Data(context.TODO(), secret)
+		if err != nil && !stderrors.Is(err, secretbackend.ErrNotFound) {
+			return err
+		}
+		merged := secret.DeepCopy()
+		merged.Data = current
+		merged = prepareSecretForUpdate(merged, data)
+		if secretbackend.IsReference(secret) && reflect.DeepEqual(merged.Data, current) {
+			return nil
+		}
+
+		if owner != nil && !slices.ContainsFunc(merged.OwnerReferences, func(ref metav1.OwnerReference) bool { return ref.UID == owner.UID }) {
+			merged.SetOwnerReferences(append(merged.OwnerReferences, *owner))
+		}
+		toUpdate, err := secretbackend.Store(context.TODO(), backend, merged)
+		if err != nil {
+			return err
+		}
+		if reflect.DeepEqual(toUpdate.Data, secret.Data) && reflect.DeepEqual(toUpdate.Annotations, secret.Annotations) &&
+			reflect.DeepEqual(toUpdate.Finalizers, secret.Finalizers) && reflect.DeepEqual(toUpdate.OwnerReferences, secret.OwnerReferences) {
+			return nil
+		}
+		_, err = g.secrets.Update(toUpdate)
+		return err
+	})
+}
+
 func prepareSecretForUpdate(secret *corev1.Secret, data map[string]string) *corev1.Secret {
 	secToUpdate := secret.DeepCopy()
 	if secToUpdate.Data == nil {
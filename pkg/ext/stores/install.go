@@ -6,10 +6,14 @@
 	extv1 "github.com/rancher/rancher/pkg/apis/ext.cattle.io/v1"
 	"github.com/rancher/rancher/pkg/ext/stores/groupmembershiprefreshrequest"
 	"github.com/rancher/rancher/pkg/ext/stores/kubeconfig"
+	"github.com/rancher/rancher/pkg/ext/stores/kubeconfigtokenrequest"
 	"github.com/rancher/rancher/pkg/ext/stores/passwordchangerequest"
 	"github.com/rancher/rancher/pkg/ext/stores/selfuser"
+	"github.com/rancher/rancher/pkg/ext/stores/tokenrevocationrequest"
 	"github.com/rancher/rancher/pkg/ext/stores/tokens"
+	"github.com/rancher/rancher/pkg/ext/stores/tokenusage"
 	"github.com/rancher/rancher/pkg/ext/stores/useractivity"
+	"github.com/rancher/rancher/pkg/ext/stores/userretentionreport"
 	"github.com/rancher/rancher/pkg/features"
 	"github.com/rancher/rancher/pkg/wrangler"
 	steveext "github.com/rancher/steve/pkg/ext"
@@ -44,6 +48,15 @@ func InstallStores(
 	}
 	logrus.Infof("Successfully installed %s store", tokens.SingularName)
 
+	if err := server.Install(
+		extv1.TokenUsageResourceName,
+		tokenusage.GVK,
+		tokenusage.New(wranglerContext, server.GetAuthorizer()),
+	); err != nil {
+		return fmt.Errorf("unable to install %s store: %w", tokenusage.SingularName, err)
+	}
+	logrus.Infof("Successfully installed %s store", tokenusage.SingularName)
+
 	if err := server.Install(
 		extv1.KubeconfigResourceName,
 		extv1.SchemeGroupVersion.WithKind(kubeconfig.Kind),
@@ -53,6 +66,15 @@ func InstallStores(
 	}
 	logrus.Infof("Successfully installed %s store", kubeconfig.Singular)
 
+	if err := server.Install(
+		extv1.KubeconfigTokenRequestResourceName,
+		kubeconfigtokenrequest.GVK,
+		kubeconfigtokenrequest.New(wranglerContext, server.GetAuthorizer()),
+	); err != nil {
+		return fmt.Errorf("unable to install %s store: %w", kubeconfigtokenrequest.SingularName, err)
+	}
+	logrus.Infof("Successfully installed %s store", kubeconfigtokenrequest.SingularName)
+
 	if err = server.Install(
 		extv1.PasswordChangeRequestResourceName,
 		passwordchangerequest.GVK,
@@ -76,6 +98,24 @@ func InstallStores(
 	}
 	logrus.Infof("Successfully installed %s store", groupmembershiprefreshrequest.SingularName)
 
+	if err = server.Install(
+		extv1.TokenRevocationRequestResourceName,
+		tokenrevocationrequest.GVK,
+		tokenrevocationrequest.New(wranglerContext, server.GetAuthorizer()),
+	); err != nil {
+		return fmt.Errorf("unable to install %s store: %w", tokenrevocationrequest.SingularName, err)
+	}
+	logrus.Infof("Successfully installed %s store", tokenrevocationrequest.SingularName)
+
+	if err = server.Install(
+		extv1.UserRetentionReportResourceName,
+		userretentionreport.GVK,
+		userretentionreport.New(wranglerContext, server.GetAuthorizer()),
+	); err != nil {
+		return fmt.Errorf("unable to install %s store: %w", userretentionreport.SingularName, err)
+	}
+	logrus.Infof("Successfully installed %s store", userretentionreport.SingularName)
+
 	if err = server.Install(
 		extv1.SelfUserResourceName,
 		selfuser.GVK,
@@ -74,6 +74,7 @@
 	DescriptionField         = "description"
 	TTLField                 = "ttl"
 	IncludeDefaultEntryField = "include-default-entry"
+	ModeField                = "mode"
 	StatusConditionsField    = "status-conditions"
 	StatusSummaryField       = "status-summary"
 	StatusTokensField        = "status-tokens"
@@ -291,6 +292,12 @@ func (s *Store) Create(
 		return nil, apierrors.NewBadRequest("at least one cluster is required when includeDefaultEntry is false")
 	}
 
+	switch kubeconfig.Spec.Mode {
+	case "", ext.KubeconfigModeToken, ext.KubeconfigModeExec:
+	default:
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("invalid spec.mode %s", kubeconfig.Spec.Mode))
+	}
+
 	defaultTTLPtr, err := s.getDefaultTTL()
 	if err != nil {
 		return nil, apierrors.NewInternalError(fmt.Errorf("error getting default token TTL: %w", err))
@@ -427,7 +434,9 @@ func (s *Store) Create(
 	}
 
 	dryRun := options != nil && len(options.DryRun) > 0
-	generateToken := s.shouldGenerateToken()
+	// In the Exec mode tokens are requested by the credential plugin when needed.
+	isExecMode := kubeconfig.Spec.Mode == ext.KubeconfigModeExec
+	generateToken := s.shouldGenerateToken() && !isExecMode
 
 	kubeconfigToStore := kubeconfig.DeepCopy()
 	kubeconfigToStore.Name = ""         // We generate the kubeconfig's name automatically.
@@ -687,6 +696,14 @@ func (s *Store) Create(
 			}
 		}
 
+		if isExecMode {
+			for i := range data.Users {
+				data.Users[i].Kubeconfig = kubeConfigID
+				data.Users[i].AuthProvider = authToken.GetAuthProvider()
+				data.Users[i].CACerts = base64.StdEncoding.EncodeToString([]byte(s.getCACert()))
+			}
+		}
+
 		v1Config, err = kconfig.Generate(data)
 		if err != nil {
 			conditions = []metav1.Condition{{
@@ -781,6 +798,10 @@ func (s *Store) toConfigMap(kubeconfig *ext.Kubeconfig) (*corev1.ConfigMap, erro
 		configMap.Data[IncludeDefaultEntryField] = strconv.FormatBool(*kubeconfig.Spec.IncludeDefaultEntry)
 	}
 
+	if kubeconfig.Spec.Mode != "" {
+		configMap.Data[ModeField] = string(kubeconfig.Spec.Mode)
+	}
+
 	// Note: Value should never be persisted!
 	configMap.Data[StatusSummaryField] = kubeconfig.Status.Summary
 	if len(kubeconfig.Status.Conditions) > 0 {
@@ -850,6 +871,8 @@ func (s *Store) fromConfigMap(configMap *corev1.ConfigMap) (*ext.Kubeconfig, err
 		kubeconfig.Spec.IncludeDefaultEntry = &boolVal
 	}
 
+	kubeconfig.Spec.Mode = ext.KubeconfigMode(configMap.Data[ModeField])
+
 	kubeconfig.Status.Summary = configMap.Data[StatusSummaryField]
 
 	if serialized := configMap.Data[StatusConditionsField]; serialized != "" {
@@ -1595,6 +1618,9 @@ func (s *Store) Update(
 	if !reflect.DeepEqual(oldKubeconfig.Spec.IncludeDefaultEntry, newKubeconfig.Spec.IncludeDefaultEntry) {
 		return nil, false, apierrors.NewBadRequest("spec.includeDefaultEntry is immutable")
 	}
+	if oldKubeconfig.Spec.Mode != newKubeconfig.Spec.Mode {
+		return nil, false, apierrors.NewBadRequest("spec.mode is immutable")
+	}
 
 	newKubeconfig.UID = oldKubeconfig.UID // Make sure UID is preserved.
 
@@ -1699,8 +1725,10 @@ func (s *Store) NamespaceScoped() bool {
 	pathKConfigDescriptionField         = fieldpath.MakePathOrDie("spec", "description")
 	pathKConfigTTLField                 = fieldpath.MakePathOrDie("spec", "ttl")
 	pathKConfigIncludeDefaultEntryField = fieldpath.MakePathOrDie("spec", "includeDefaultEntry")
+	pathKConfigModeField                = fieldpath.MakePathOrDie("spec", "mode")
 
 	pathCMIncludeDefaultEntryField = fieldpath.MakePathOrDie("data", "include-default-entry")
+	pathCMModeField                = fieldpath.MakePathOrDie("data", "mode")
 
 	mapFromConfigMap = extcommon.MapSpec{
 		pathCMData.String():                     nil,
@@ -1709,6 +1737,7 @@ func (s *Store) NamespaceScoped() bool {
 		pathCMDescriptionField.String():         pathKConfigDescriptionField,
 		pathCMTTLField.String():                 pathKConfigTTLField,
 		pathCMIncludeDefaultEntryField.String(): pathKConfigIncludeDefaultEntryField,
+		pathCMModeField.String():                pathKConfigModeField,
 		pathCMStatusConditionsField.String():    nil,
 		pathCMStatusSummaryField.String():       nil,
 		pathCMStatusTokensField.String():        nil,
@@ -1721,5 +1750,6 @@ func (s *Store) NamespaceScoped() bool {
 		pathKConfigDescriptionField.String():         pathCMDescriptionField,
 		pathKConfigTTLField.String():                 pathCMTTLField,
 		pathKConfigIncludeDefaultEntryField.String(): pathCMIncludeDefaultEntryField,
+		pathKConfigModeField.String():                pathCMModeField,
 	}
 )
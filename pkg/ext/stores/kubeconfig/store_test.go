@@ -48,6 +48,7 @@
 	"k8s.io/apiserver/pkg/endpoints/request"
 	"k8s.io/apiserver/pkg/storage/names"
 	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
 	"k8s.io/kubernetes/pkg/printers"
 	"k8s.io/utils/ptr"
 	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
@@ -633,6 +634,120 @@ func TestStoreCreate(t *testing.T) {
 
 		assert.Equal(t, "downstream1", config.CurrentContext)
 	})
+	t.Run("user creates a kubeconfig in the exec mode", func(t *testing.T) {
+		var configMap *corev1.ConfigMap
+		configMapClient := fake.NewMockClientInterface[*corev1.ConfigMap, *corev1.ConfigMapList](ctrl)
+		configMapClient.EXPECT().Create(gomock.Any()).DoAndReturn(func(obj *corev1.ConfigMap) (*corev1.ConfigMap, error) {
+			configMap = obj.DeepCopy()
+			configMap.CreationTimestamp = metav1.Now()
+			configMap.Name = names.SimpleNameGenerator.GenerateName(configMap.GenerateName)
+			return configMap, nil
+		}).Times(1)
+		configMapClient.EXPECT().Update(gomock.Any()).DoAndReturn(func(obj *corev1.ConfigMap) (*corev1.ConfigMap, error) {
+			configMap = obj.DeepCopy()
+			return configMap, nil
+		}).Times(1)
+
+		tokenManager := &fakeTokenManager{} // Subtest specific instance.
+		tokenStore := &fakeTokenStore{
+			fetchFunc: func(tokenID string) (accessor.TokenAccessor, error) {
+				return &v3.Token{
+					ObjectMeta:   metav1.ObjectMeta{Name: tokenID},
+					UserID:       userID,
+					AuthProvider: "github",
+				}, nil
+			},
+		}
+
+		store := &Store{
+			mcmEnabled: true,
+			authorizer: authorizer.AuthorizerFunc(func(ctx context.Context, a authorizer.Attributes) (authorizer.Decision, string, error) {
+				return authorizer.DecisionAllow, "", nil
+			}),
+			nsCache:             nsCache,
+			configMapClient:     configMapClient,
+			userCache:           userCache,
+			tokenStore:          tokenStore,
+			clusterCache:        clusterCache,
+			nodeCache:           nodeCache,
+			tokenMgr:            tokenManager,
+			getCACert:           func() string { return rancherCACert },
+			getDefaultTTL:       getDefaultTTL,
+			getMaxTTL:           getMaxTTL,
+			getServerURL:        getServerURL,
+			shouldGenerateToken: shouldGenerateToken,
+		}
+
+		ctx := request.WithUser(context.Background(), &k8suser.DefaultInfo{
+			Name: userID,
+			Extra: map[string][]string{
+				common.ExtraRequestTokenID: {authTokenID},
+			},
+		})
+		kubeconfig := &ext.Kubeconfig{
+			Spec: ext.KubeconfigSpec{
+				Clusters: []string{downstream2},
+				Mode:     ext.KubeconfigModeExec,
+			},
+		}
+
+		obj, err := store.Create(ctx, kubeconfig, nil, options)
+		require.NoError(t, err)
+		created := obj.(*ext.Kubeconfig)
+		assert.Equal(t, ext.KubeconfigModeExec, created.Spec.Mode)
+		assert.Empty(t, created.Status.Tokens)
+		assert.Empty(t, tokenManager.sharedTokenKeys)
+		assert.Empty(t, tokenManager.clusterTokenKeys)
+		require.NotNil(t, configMap)
+		assert.Equal(t, string(ext.KubeconfigModeExec), configMap.Data[ModeField])
+
+		config, err := clientcmd.Load([]byte(created.Status.Value))
+		require.NoError(t, err)
+		require.Len(t, config.AuthInfos, 2)
+
+		exec := config.AuthInfos[defaultClusterName].Exec
+		require.NotNil(t, exec)
+		assert.Empty(t, config.AuthInfos[defaultClusterName].Token)
+		assert.Equal(t, "rancher-kubeconfig-helper", exec.Command)
+		assert.Equal(t, []string{
+			"--server=" + serverURL,
+			"--kubeconfig=" + created.Name,
+			"--auth-provider=github",
+		}, exec.Args)
+		assert.Equal(t, clientcmdapi.IfAvailableExecInteractiveMode, exec.InteractiveMode)
+		require.Len(t, exec.Env, 1)
+		assert.Equal(t, "RANCHER_CACERTS", exec.Env[0].Name)
+		assert.Equal(t, base64.StdEncoding.EncodeToString([]byte(rancherCACert)), exec.Env[0].Value)
+
+		exec = config.AuthInfos["downstream2"].Exec
+		require.NotNil(t, exec)
+		assert.Equal(t, []string{
+			"--server=" + serverURL,
+			"--kubeconfig=" + created.Name,
+			"--auth-provider=github",
+			"--cluster=" + downstream2,
+		}, exec.Args)
+	})
+	t.Run("invalid mode", func(t *testing.T) {
+		store := &Store{
+			authorizer:    commonAuthorizer,
+			userCache:     userCache,
+			tokenStore:    tokenStore,
+			getDefaultTTL: getDefaultTTL,
+			getMaxTTL:     getMaxTTL,
+		}
+
+		ctx := request.WithUser(context.Background(), &k8suser.DefaultInfo{
+			Name: userID,
+			Extra: map[string][]string{
+				common.ExtraRequestTokenID: {authTokenID},
+			},
+		})
+
+		_, err := store.Create(ctx, &ext.Kubeconfig{Spec: ext.KubeconfigSpec{Mode: "Static"}}, nil, options)
+		require.Error(t, err)
+		assert.True(t, apierrors.IsBadRequest(err))
+	})
 	t.Run("no cluster specified", func(t *testing.T) {
 		var configMap *corev1.ConfigMap
 		configMapClient := fake.NewMockClientInterface[*corev1.ConfigMap, *corev1.ConfigMapList](ctrl)
@@ -3074,6 +3189,17 @@ func TestStoreUpdate(t *testing.T) {
 			newKubeconfig.Spec.IncludeDefaultEntry = ptr.To(false)
 			objInfo := &fakeUpdatedObjectInfo{obj: newKubeconfig}
 
+			kubeconfig, isCreated, err := store.Update(ctx, kubeconfigID, objInfo, nil, updateValidation, false, options)
+			require.Error(t, err)
+			assert.Nil(t, kubeconfig)
+			assert.False(t, isCreated)
+			assert.True(t, apierrors.IsBadRequest(err))
+		})
+		t.Run("spec.mode", func(t *testing.T) {
+			newKubeconfig := oldKubeconfig.DeepCopy()
+			newKubeconfig.Spec.Mode = ext.KubeconfigModeExec
+			objInfo := &fakeUpdatedObjectInfo{obj: newKubeconfig}
+
 			kubeconfig, isCreated, err := store.Update(ctx, kubeconfigID, objInfo, nil, updateValidation, false, options)
 			require.Error(t, err)
 			assert.Nil(t, kubeconfig)
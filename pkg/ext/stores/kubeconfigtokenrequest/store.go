@@ -0,0 +1,290 @@
+// kubeconfigtokenrequest implements the store for the imperative kubeconfigtokenrequest resource.
+package kubeconfigtokenrequest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	ext "github.com/rancher/rancher/pkg/apis/ext.cattle.io/v1"
+	apiv3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/auth/accessor"
+	"github.com/rancher/rancher/pkg/auth/providers/common"
+	"github.com/rancher/rancher/pkg/auth/tokens"
+	"github.com/rancher/rancher/pkg/ext/stores/kubeconfig"
+	exttokens "github.com/rancher/rancher/pkg/ext/stores/tokens"
+	mgmtv3 "github.com/rancher/rancher/pkg/generated/controllers/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/settings"
+	"github.com/rancher/rancher/pkg/wrangler"
+	v1 "github.com/rancher/wrangler/v3/pkg/generated/controllers/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	k8suser "k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/apiserver/pkg/registry/rest"
+)
+
+const (
+	SingularName = "kubeconfigtokenrequest"
+	kind         = "KubeconfigTokenRequest"
+)
+
+var (
+	_ rest.Creater                  = &Store{}
+	_ rest.Storage                  = &Store{}
+	_ rest.Scoper                   = &Store{}
+	_ rest.SingularNameProvider     = &Store{}
+	_ rest.GroupVersionKindProvider = &Store{}
+)
+
+var (
+	GVK = ext.SchemeGroupVersion.WithKind(kind)
+	gvr = ext.SchemeGroupVersion.WithResource(ext.KubeconfigTokenRequestResourceName)
+)
+
+// tokenFetcher abstracts fetching the token the request was authenticated with.
+type tokenFetcher interface {
+	Fetch(tokenID string) (accessor.TokenAccessor, error)
+}
+
+// tokenCreator abstracts ext.Token creation.
+type tokenCreator interface {
+	CreateToken(ctx context.Context, token *ext.Token, userInfo k8suser.Info) (*ext.Token, error)
+}
+
+type extTokenCreator struct{ store *exttokens.SystemStore }
+
+func (e *extTokenCreator) CreateToken(ctx context.Context, token *ext.Token, userInfo k8suser.Info) (*ext.Token, error) {
+	return e.store.Create(ctx, gvr.GroupResource(), token, &metav1.CreateOptions{}, userInfo)
+}
+
+// +k8s:openapi-gen=false
+// +k8s:deepcopy-gen=false
+
+// Store issues short-lived tokens for kubeconfigs in the Exec mode.
+type Store struct {
+	configMapCache v1.ConfigMapCache
+	userCache      mgmtv3.UserCache
+	tokenStore     tokenFetcher
+	tokenMgr       tokenCreator
+	getTTL         func() (int64, error)
+	now            func() time.Time
+}
+
+// New is a convenience function for creating a kubeconfig token request
+// store. It initializes the returned store from the provided wrangler context.
+func New(wranglerContext *wrangler.Context, authorizer authorizer.Authorizer) *Store {
+	extTokenStore := exttokens.NewSystemFromWrangler(wranglerContext, authorizer)
+
+	return &Store{
+		configMapCache: wranglerContext.Core.ConfigMap().Cache(),
+		userCache:      wranglerContext.Mgmt.User().Cache(),
+		tokenStore:     extTokenStore,
+		tokenMgr:       &extTokenCreator{store: extTokenStore},
+		getTTL: func() (int64, error) {
+			return exttokens.ParseTTLToMilliseconds(settings.KubeconfigExecTokenTTLMinutes)
+		},
+		now: time.Now,
+	}
+}
+
+// GroupVersionKind implements [rest.GroupVersionKindProvider], a required interface.
+func (s *Store) GroupVersionKind(_ schema.GroupVersion) schema.GroupVersionKind {
+	return GVK
+}
+
+// NamespaceScoped implements [rest.Scoper], a required interface.
+func (s *Store) NamespaceScoped() bool {
+	return false
+}
+
+// GetSingularName implements [rest.SingularNameProvider], a required interface.
+func (s *Store) GetSingularName() string {
+	return SingularName
+}
+
+// New implements [rest.Storage], a required interface.
+func (s *Store) New() runtime.Object {
+	return &ext.KubeconfigTokenRequest{}
+}
+
+// Destroy implements [rest.Storage], a required interface.
+func (s *Store) Destroy() {
+}
+
+// Create implements [rest.Creator], the interface to support the `create`
+// verb. It issues a token for the requested kubeconfig that expires after
+// the kubeconfig-exec-token-ttl-minutes setting, and outlives neither
+// the token of the request nor the kubeconfig.
+func (s *Store) Create(
+	ctx context.Context,
+	obj runtime.Object,
+	createValidation rest.ValidateObjectFunc,
+	options *metav1.CreateOptions,
+) (runtime.Object, error) {
+	if createValidation != nil {
+		err := createValidation(ctx, obj)
+		if err != nil {
+			return obj, err
+		}
+	}
+
+	userInfo, ok := request.UserFrom(ctx)
+	if !ok {
+		return nil, apierrors.NewInternalError(fmt.Errorf("can't get user info from context"))
+	}
+
+	req, ok := obj.(*ext.KubeconfigTokenRequest)
+	if !ok {
+		var zeroT *ext.KubeconfigTokenRequest
+		return nil, apierrors.NewInternalError(fmt.Errorf("expected %T but got %T", zeroT, obj))
+	}
+
+	if req.Spec.KubeconfigName == "" {
+		return nil, apierrors.NewBadRequest("kubeconfigName is required")
+	}
+
+	userName := userInfo.GetName()
+	if strings.Contains(userName, ":") { // E.g. system:admin
+		return nil, apierrors.NewForbidden(gvr.GroupResource(), "", fmt.Errorf("user %s is not a Rancher user", userName))
+	}
+	if _, err := s.userCache.Get(userName); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, apierrors.NewForbidden(gvr.GroupResource(), "", fmt.Errorf("user %s is not a Rancher user", userName))
+		}
+		return nil, apierrors.NewInternalError(fmt.Errorf("error getting user %s: %w", userName, err))
+	}
+
+	authTokenID := first(userInfo.GetExtra()[common.ExtraRequestTokenID])
+	if authTokenID == "" {
+		return nil, apierrors.NewForbidden(gvr.GroupResource(), "", fmt.Errorf("missing request token ID"))
+	}
+	authToken, err := s.tokenStore.Fetch(authTokenID)
+	if err != nil {
+		return nil, apierrors.NewForbidden(gvr.GroupResource(), "", fmt.Errorf("error getting request token %s: %v", authTokenID, err))
+	}
+	// Tokens issued for kubeconfigs can't be used to extend their own lifetime.
+	if _, ok := authToken.GetLabels()[tokens.TokenKubeconfigIDLabel]; ok {
+		return nil, apierrors.NewForbidden(gvr.GroupResource(), "", fmt.Errorf("request token %s is a kubeconfig token", authTokenID))
+	}
+
+	now := s.now()
+
+	// Kubeconfigs are stored as ConfigMaps, see the kubeconfig store.
+	configMap, err := s.configMapCache.Get(exttokens.TokenNamespace, req.Spec.KubeconfigName)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return nil, apierrors.NewInternalError(fmt.Errorf("error getting kubeconfig %s: %w", req.Spec.KubeconfigName, err))
+	}
+	if err != nil || configMap.Labels[kubeconfig.KindLabel] != kubeconfig.KindLabelValue ||
+		configMap.Labels[kubeconfig.UserIDLabel] != userName || configMap.DeletionTimestamp != nil {
+		// Don't disclose kubeconfigs of other users.
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("kubeconfig %s not found", req.Spec.KubeconfigName))
+	}
+	if configMap.Data[kubeconfig.ModeField] != string(ext.KubeconfigModeExec) {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("kubeconfig %s is not in the %s mode", req.Spec.KubeconfigName, ext.KubeconfigModeExec))
+	}
+
+	if req.Spec.ClusterName != "" {
+		var clusters []string
+		if serialized := configMap.Data[kubeconfig.ClustersField]; serialized != "" {
+			if err := json.Unmarshal([]byte(serialized), &clusters); err != nil {
+				return nil, apierrors.NewInternalError(fmt.Errorf("error unmarshaling clusters of kubeconfig %s: %w", req.Spec.KubeconfigName, err))
+			}
+		}
+		// Access to the cluster is checked when the token is created.
+		if !slices.Contains(clusters, "*") && !slices.Contains(clusters, req.Spec.ClusterName) {
+			return nil, apierrors.NewBadRequest(fmt.Sprintf("cluster %s is not in kubeconfig %s", req.Spec.ClusterName, req.Spec.KubeconfigName))
+		}
+	}
+
+	ttl, err := s.getTTL()
+	if err != nil {
+		return nil, apierrors.NewInternalError(fmt.Errorf("error getting token TTL: %w", err))
+	}
+	if ttl <= 0 {
+		return nil, apierrors.NewInternalError(fmt.Errorf("invalid token TTL %d, please fix %s", ttl, settings.KubeconfigExecTokenTTLMinutes.Name))
+	}
+	expiresAt := now.Add(time.Duration(ttl) * time.Millisecond)
+
+	// The TTL of the kubeconfig limits the time during which tokens can be requested.
+	if kubeconfigTTL, err := strconv.ParseInt(configMap.Data[kubeconfig.TTLField], 10, 64); err == nil && kubeconfigTTL > 0 {
+		kubeconfigExpiresAt := configMap.CreationTimestamp.Add(time.Duration(kubeconfigTTL) * time.Second)
+		if !now.Before(kubeconfigExpiresAt) {
+			return nil, apierrors.NewForbidden(gvr.GroupResource(), "", fmt.Errorf("kubeconfig %s has expired", req.Spec.KubeconfigName))
+		}
+		if kubeconfigExpiresAt.Before(expiresAt) {
+			expiresAt = kubeconfigExpiresAt
+		}
+	}
+
+	if v3Token, ok := authToken.(*apiv3.Token); ok && v3Token.ExpiresAt == "" {
+		// The expiration of v3 tokens isn't stored.
+		v3Token = v3Token.DeepCopy()
+		tokens.SetTokenExpiresAt(v3Token)
+		authToken = v3Token
+	}
+	if value := authToken.GetExpiresAt(); value != "" {
+		authTokenExpiresAt, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return nil, apierrors.NewInternalError(fmt.Errorf("error parsing expiration of request token %s: %w", authTokenID, err))
+		}
+		if authTokenExpiresAt.Before(expiresAt) {
+			expiresAt = authTokenExpiresAt
+		}
+	}
+
+	if options != nil && len(options.DryRun) > 0 && options.DryRun[0] == metav1.DryRunAll {
+		return req, nil
+	}
+
+	token := &ext.Token{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: map[string]string{
+				tokens.TokenKubeconfigIDLabel: configMap.Name,
+			},
+			// Tokens are deleted with the kubeconfig.
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: "v1",
+				Kind:       "ConfigMap",
+				Name:       configMap.Name,
+				UID:        configMap.UID,
+			}},
+		},
+		Spec: ext.TokenSpec{
+			UserID:      userName,
+			Kind:        kubeconfig.KindLabelValue,
+			Description: "Kubeconfig exec token",
+			TTL:         expiresAt.Sub(now).Milliseconds(),
+			ClusterName: req.Spec.ClusterName,
+		},
+	}
+	created, err := s.tokenMgr.CreateToken(ctx, token, userInfo)
+	if err != nil {
+		if _, ok := err.(apierrors.APIStatus); ok {
+			return nil, err
+		}
+		return nil, apierrors.NewInternalError(fmt.Errorf("error creating token for kubeconfig %s: %w", req.Spec.KubeconfigName, err))
+	}
+
+	req.Status = ext.KubeconfigTokenRequestStatus{
+		Token:               created.Status.BearerToken,
+		ExpirationTimestamp: metav1.NewTime(expiresAt),
+	}
+
+	return req, nil
+}
+
+// first returns the first element of a slice of strings, or an empty string if the slice is empty.
+func first(values []string) string {
+	if len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
@@ -0,0 +1,252 @@
+package kubeconfigtokenrequest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	ext "github.com/rancher/rancher/pkg/apis/ext.cattle.io/v1"
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/auth/accessor"
+	"github.com/rancher/rancher/pkg/auth/providers/common"
+	"github.com/rancher/rancher/pkg/auth/tokens"
+	"github.com/rancher/rancher/pkg/ext/stores/kubeconfig"
+	"github.com/rancher/wrangler/v3/pkg/generic/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	k8suser "k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/endpoints/request"
+)
+
+type fakeTokenStore map[string]accessor.TokenAccessor
+
+func (f fakeTokenStore) Fetch(tokenID string) (accessor.TokenAccessor, error) {
+	if token, ok := f[tokenID]; ok {
+		return token, nil
+	}
+	return nil, apierrors.NewNotFound(schema.GroupResource{}, tokenID)
+}
+
+type fakeTokenManager struct {
+	created []*ext.Token
+}
+
+func (f *fakeTokenManager) CreateToken(_ context.Context, token *ext.Token, _ k8suser.Info) (*ext.Token, error) {
+	f.created = append(f.created, token.DeepCopy())
+	result := token.DeepCopy()
+	result.Name = "token-abcde"
+	result.Status.BearerToken = "ext/token-abcde:secret"
+	return result, nil
+}
+
+func TestCreate(t *testing.T) {
+	const userID = "u-abcde"
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+
+	kubeconfigMap := func(name, user, mode, ttl, clusters string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				Namespace:         "cattle-tokens",
+				UID:               "kubeconfig-uid",
+				CreationTimestamp: metav1.NewTime(now.Add(-time.Hour)),
+				Labels: map[string]string{
+					kubeconfig.KindLabel:   kubeconfig.KindLabelValue,
+					kubeconfig.UserIDLabel: user,
+				},
+			},
+			Data: map[string]string{
+				kubeconfig.ModeField:     mode,
+				kubeconfig.TTLField:      ttl,
+				kubeconfig.ClustersField: clusters,
+			},
+		}
+	}
+	configMaps := map[string]*corev1.ConfigMap{
+		"kubeconfig-exec":     kubeconfigMap("kubeconfig-exec", userID, "Exec", "0", `["c-m-abcde"]`),
+		"kubeconfig-all":      kubeconfigMap("kubeconfig-all", userID, "Exec", "0", `["*"]`),
+		"kubeconfig-expiring": kubeconfigMap("kubeconfig-expiring", userID, "Exec", "3780", ""), // expires in 3 minutes
+		"kubeconfig-expired":  kubeconfigMap("kubeconfig-expired", userID, "Exec", "3600", ""),
+		"kubeconfig-token":    kubeconfigMap("kubeconfig-token", userID, "", "0", ""),
+		"kubeconfig-other":    kubeconfigMap("kubeconfig-other", "u-other", "Exec", "0", ""),
+	}
+
+	tokenStore := fakeTokenStore{
+		"session": &v3.Token{
+			ObjectMeta: metav1.ObjectMeta{Name: "session", CreationTimestamp: metav1.NewTime(now.Add(-time.Hour))},
+			UserID:     userID,
+			TTLMillis:  (16 * time.Hour).Milliseconds(),
+		},
+		"expiring-session": &v3.Token{
+			ObjectMeta: metav1.ObjectMeta{Name: "expiring-session", CreationTimestamp: metav1.NewTime(now.Add(-time.Hour))},
+			UserID:     userID,
+			TTLMillis:  (time.Hour + 5*time.Minute).Milliseconds(),
+		},
+		"kubeconfig-token": &ext.Token{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   "kubeconfig-token",
+				Labels: map[string]string{tokens.TokenKubeconfigIDLabel: "kubeconfig-exec"},
+			},
+		},
+	}
+
+	tests := []struct {
+		name           string
+		user           string
+		authToken      string
+		spec           ext.KubeconfigTokenRequestSpec
+		wantErr        func(error) bool
+		wantExpiration time.Time
+	}{
+		{
+			name:           "token for the rancher proxy",
+			authToken:      "session",
+			spec:           ext.KubeconfigTokenRequestSpec{KubeconfigName: "kubeconfig-exec"},
+			wantExpiration: now.Add(10 * time.Minute),
+		},
+		{
+			name:           "token for an ACE cluster",
+			authToken:      "session",
+			spec:           ext.KubeconfigTokenRequestSpec{KubeconfigName: "kubeconfig-exec", ClusterName: "c-m-abcde"},
+			wantExpiration: now.Add(10 * time.Minute),
+		},
+		{
+			name:           "token for a cluster of a kubeconfig for all clusters",
+			authToken:      "session",
+			spec:           ext.KubeconfigTokenRequestSpec{KubeconfigName: "kubeconfig-all", ClusterName: "c-m-fghij"},
+			wantExpiration: now.Add(10 * time.Minute),
+		},
+		{
+			name:           "token doesn't outlive the session",
+			authToken:      "expiring-session",
+			spec:           ext.KubeconfigTokenRequestSpec{KubeconfigName: "kubeconfig-exec"},
+			wantExpiration: now.Add(5 * time.Minute),
+		},
+		{
+			name:           "token doesn't outlive the kubeconfig",
+			authToken:      "session",
+			spec:           ext.KubeconfigTokenRequestSpec{KubeconfigName: "kubeconfig-expiring"},
+			wantExpiration: now.Add(3 * time.Minute),
+		},
+		{
+			name:      "missing kubeconfig name",
+			authToken: "session",
+			wantErr:   apierrors.IsBadRequest,
+		},
+		{
+			name:      "not a rancher user",
+			user:      "system:admin",
+			authToken: "session",
+			spec:      ext.KubeconfigTokenRequestSpec{KubeconfigName: "kubeconfig-exec"},
+			wantErr:   apierrors.IsForbidden,
+		},
+		{
+			name:    "missing request token",
+			spec:    ext.KubeconfigTokenRequestSpec{KubeconfigName: "kubeconfig-exec"},
+			wantErr: apierrors.IsForbidden,
+		},
+		{
+			name:      "request authenticated with a kubeconfig token",
+			authToken: "kubeconfig-token",
+			spec:      ext.KubeconfigTokenRequestSpec{KubeconfigName: "kubeconfig-exec"},
+			wantErr:   apierrors.IsForbidden,
+		},
+		{
+			name:      "kubeconfig not found",
+			authToken: "session",
+			spec:      ext.KubeconfigTokenRequestSpec{KubeconfigName: "kubeconfig-unknown"},
+			wantErr:   apierrors.IsBadRequest,
+		},
+		{
+			name:      "kubeconfig of another user",
+			authToken: "session",
+			spec:      ext.KubeconfigTokenRequestSpec{KubeconfigName: "kubeconfig-other"},
+			wantErr:   apierrors.IsBadRequest,
+		},
+		{
+			name:      "kubeconfig in the token mode",
+			authToken: "session",
+			spec:      ext.KubeconfigTokenRequestSpec{KubeconfigName: "kubeconfig-token"},
+			wantErr:   apierrors.IsBadRequest,
+		},
+		{
+			name:      "expired kubeconfig",
+			authToken: "session",
+			spec:      ext.KubeconfigTokenRequestSpec{KubeconfigName: "kubeconfig-expired"},
+			wantErr:   apierrors.IsForbidden,
+		},
+		{
+			name:      "cluster not in the kubeconfig",
+			authToken: "session",
+			spec:      ext.KubeconfigTokenRequestSpec{KubeconfigName: "kubeconfig-exec", ClusterName: "c-m-fghij"},
+			wantErr:   apierrors.IsBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			userCache := fake.NewMockNonNamespacedCacheInterface[*v3.User](ctrl)
+			userCache.EXPECT().Get(userID).Return(&v3.User{ObjectMeta: metav1.ObjectMeta{Name: userID}}, nil).AnyTimes()
+			configMapCache := fake.NewMockCacheInterface[*corev1.ConfigMap](ctrl)
+			configMapCache.EXPECT().Get("cattle-tokens", gomock.Any()).DoAndReturn(func(_, name string) (*corev1.ConfigMap, error) {
+				if configMap, ok := configMaps[name]; ok {
+					return configMap, nil
+				}
+				return nil, apierrors.NewNotFound(schema.GroupResource{Resource: "configmaps"}, name)
+			}).AnyTimes()
+			tokenMgr := &fakeTokenManager{}
+
+			store := &Store{
+				configMapCache: configMapCache,
+				userCache:      userCache,
+				tokenStore:     tokenStore,
+				tokenMgr:       tokenMgr,
+				getTTL:         func() (int64, error) { return (10 * time.Minute).Milliseconds(), nil },
+				now:            func() time.Time { return now },
+			}
+
+			user := tt.user
+			if user == "" {
+				user = userID
+			}
+			extra := map[string][]string{}
+			if tt.authToken != "" {
+				extra[common.ExtraRequestTokenID] = []string{tt.authToken}
+			}
+			ctx := request.WithUser(context.Background(), &k8suser.DefaultInfo{Name: user, Extra: extra})
+
+			obj, err := store.Create(ctx, &ext.KubeconfigTokenRequest{Spec: tt.spec}, nil, &metav1.CreateOptions{})
+			if tt.wantErr != nil {
+				require.Error(t, err)
+				assert.True(t, tt.wantErr(err), err)
+				assert.Empty(t, tokenMgr.created)
+				return
+			}
+			require.NoError(t, err)
+
+			req := obj.(*ext.KubeconfigTokenRequest)
+			assert.Equal(t, "ext/token-abcde:secret", req.Status.Token)
+			assert.Equal(t, tt.wantExpiration, req.Status.ExpirationTimestamp.UTC())
+
+			require.Len(t, tokenMgr.created, 1)
+			token := tokenMgr.created[0]
+			assert.Equal(t, userID, token.Spec.UserID)
+			assert.Equal(t, "kubeconfig", token.Spec.Kind)
+			assert.Equal(t, tt.spec.ClusterName, token.Spec.ClusterName)
+			assert.Equal(t, tt.wantExpiration.Sub(now).Milliseconds(), token.Spec.TTL)
+			assert.Equal(t, tt.spec.KubeconfigName, token.Labels[tokens.TokenKubeconfigIDLabel])
+			assert.Equal(t, []metav1.OwnerReference{{
+				APIVersion: "v1",
+				Kind:       "ConfigMap",
+				Name:       tt.spec.KubeconfigName,
+				UID:        "kubeconfig-uid",
+			}}, token.OwnerReferences)
+		})
+	}
+}
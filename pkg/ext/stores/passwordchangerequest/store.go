@@ -4,12 +4,13 @@
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
-	"unicode/utf8"
 
 	ext "github.com/rancher/rancher/pkg/apis/ext.cattle.io/v1"
 	mgmt "github.com/rancher/rancher/pkg/apis/management.cattle.io"
+	"github.com/rancher/rancher/pkg/auth/providers/local/passwordpolicy"
 	"github.com/rancher/rancher/pkg/auth/providers/local/pbkdf2"
 	"github.com/rancher/rancher/pkg/controllers/status"
 	mgmtv3 "github.com/rancher/rancher/pkg/generated/controllers/management.cattle.io/v3"
@@ -50,11 +51,11 @@ type PasswordUpdater interface {
 // +k8s:deepcopy-gen=false
 
 type Store struct {
-	authorizer           authorizer.Authorizer
-	pwdUpdater           PasswordUpdater
-	userCache            mgmtv3.UserCache
-	userClient           mgmtv3.UserClient
-	getPasswordMinLength func() int
+	authorizer        authorizer.Authorizer
+	pwdUpdater        PasswordUpdater
+	userCache         mgmtv3.UserCache
+	userClient        mgmtv3.UserClient
+	getPasswordPolicy func() passwordpolicy.Policy
 }
 
 // +k8s:openapi-gen=false
@@ -66,11 +67,11 @@ func New(wranglerContext *wrangler.Context, authorizer authorizer.Authorizer) *S
 	pwdManager := pbkdf2.New(wranglerContext.Core.Secret().Cache(), wranglerContext.Core.Secret())
 
 	return &Store{
-		pwdUpdater:           pwdManager,
-		authorizer:           authorizer,
-		userCache:            wranglerContext.Mgmt.User().Cache(),
-		userClient:           wranglerContext.Mgmt.User(),
-		getPasswordMinLength: settings.PasswordMinLength.GetInt,
+		pwdUpdater:        pwdManager,
+		authorizer:        authorizer,
+		userCache:         wranglerContext.Mgmt.User().Cache(),
+		userClient:        wranglerContext.Mgmt.User(),
+		getPasswordPolicy: passwordpolicy.FromSettings,
 	}
 }
 
@@ -129,9 +130,9 @@ func (s *Store) Create(
 		return nil, apierrors.NewBadRequest("userID is required")
 	}
 
-	// Password must be at least the minimum required length.
-	if minLength := s.getPasswordMinLength(); utf8.RuneCountInString(req.Spec.NewPassword) < minLength {
-		return nil, apierrors.NewBadRequest(fmt.Sprintf("password must be at least %d characters", minLength))
+	// Password must comply with the password policy.
+	if err := s.getPasswordPolicy().Validate(req.Spec.NewPassword); err != nil {
+		return nil, apierrors.NewBadRequest(err.Error())
 	}
 
 	user, err := s.userCache.Get(req.Spec.UserID)
@@ -162,6 +163,9 @@ func (s *Store) Create(
 	// secrets in the cattle-local-user-passwords namespace.
 	if canUpdateAnyPassword {
 		err := s.pwdUpdater.UpdatePassword(req.Spec.UserID, req.Spec.NewPassword)
+		if errors.Is(err, pbkdf2.ErrPasswordReused) {
+			return nil, apierrors.NewBadRequest(err.Error())
+		}
 		if err != nil {
 			return nil, apierrors.NewUnauthorized(fmt.Sprintf("error checking permissions %s", err.Error()))
 		}
@@ -194,6 +198,9 @@ func (s *Store) Create(
 	// Ordinary users can only change their own password and must provide their current password.
 	if userInfo.GetName() == req.Spec.UserID {
 		err := s.pwdUpdater.VerifyAndUpdatePassword(req.Spec.UserID, req.Spec.CurrentPassword, req.Spec.NewPassword)
+		if errors.Is(err, pbkdf2.ErrPasswordReused) {
+			return nil, apierrors.NewBadRequest(err.Error())
+		}
 		if err != nil {
 			return nil, apierrors.NewInternalError(fmt.Errorf("error updating password: %w", err))
 		}
@@ -9,6 +9,8 @@
 
 	ext "github.com/rancher/rancher/pkg/apis/ext.cattle.io/v1"
 	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/auth/providers/local/passwordpolicy"
+	"github.com/rancher/rancher/pkg/auth/providers/local/pbkdf2"
 	"github.com/rancher/rancher/pkg/controllers/status"
 	"github.com/rancher/rancher/pkg/ext/mocks"
 	mgmtv3 "github.com/rancher/rancher/pkg/generated/controllers/management.cattle.io/v3"
@@ -32,6 +34,7 @@ func TestCreate(t *testing.T) {
 	username := "fake-username"
 	oldPassword := "fake-current-password"
 	newPassword := "fake-new-password"
+	policy := passwordpolicy.Policy{MinLength: 12, BlockedWords: []string{"rancher"}}
 
 	mustChangePasswordPatch, _ := json.Marshal([]struct {
 		Op    string `json:"op"`
@@ -187,6 +190,22 @@ func TestCreate(t *testing.T) {
 			pwdUpdater: pwdUpdater,
 			wantErr:    "password must be at least 12 characters",
 		},
+		{
+			desc: "password contains a blocked word",
+			obj: &ext.PasswordChangeRequest{
+				Spec: ext.PasswordChangeRequestSpec{
+					UserID:          userID,
+					CurrentPassword: oldPassword,
+					NewPassword:     "my-rancher-password",
+				},
+			},
+			ctx: request.WithUser(context.Background(), &user.DefaultInfo{Name: "another-user"}),
+			authorizer: authorizer.AuthorizerFunc(func(ctx context.Context, a authorizer.Attributes) (authorizer.Decision, string, error) {
+				return authorizer.DecisionDeny, "", nil
+			}),
+			pwdUpdater: pwdUpdater,
+			wantErr:    `password must not contain "rancher"`,
+		},
 		{
 			desc: "password matches username",
 			obj: &ext.PasswordChangeRequest{
@@ -273,6 +292,28 @@ func TestCreate(t *testing.T) {
 			userCache: userCache,
 			wantErr:   "unexpected error",
 		},
+		{
+			desc: "password was used recently",
+			obj: &ext.PasswordChangeRequest{
+				Spec: ext.PasswordChangeRequestSpec{
+					UserID:          userID,
+					CurrentPassword: oldPassword,
+					NewPassword:     newPassword,
+				},
+			},
+			ctx: request.WithUser(context.Background(), &user.DefaultInfo{Name: userID}),
+			authorizer: authorizer.AuthorizerFunc(func(ctx context.Context, a authorizer.Attributes) (authorizer.Decision, string, error) {
+				return authorizer.DecisionDeny, "", nil
+			}),
+			pwdUpdater: func() PasswordUpdater {
+				mock := mocks.NewMockPasswordUpdater(ctrl)
+				mock.EXPECT().VerifyAndUpdatePassword(userID, oldPassword, newPassword).Return(pbkdf2.ErrPasswordReused)
+
+				return mock
+			},
+			userCache: userCache,
+			wantErr:   "password was used recently",
+		},
 		{
 			desc: "password changed for the same user and mustChangePassword is set to false",
 			obj: &ext.PasswordChangeRequest{
@@ -364,8 +405,8 @@ func TestCreate(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.desc, func(t *testing.T) {
 			store := Store{
-				authorizer:           tt.authorizer,
-				getPasswordMinLength: func() int { return 12 },
+				authorizer:        tt.authorizer,
+				getPasswordPolicy: func() passwordpolicy.Policy { return policy },
 			}
 			if tt.pwdUpdater != nil {
 				store.pwdUpdater = tt.pwdUpdater()
@@ -474,8 +515,8 @@ func TestCreateWithFirstLoginOff(t *testing.T) {
 			})
 
 			store := Store{
-				authorizer:           tt.authorizer,
-				getPasswordMinLength: func() int { return 12 },
+				authorizer:        tt.authorizer,
+				getPasswordPolicy: func() passwordpolicy.Policy { return passwordpolicy.Policy{MinLength: 12} },
 			}
 			if tt.pwdUpdater != nil {
 				store.pwdUpdater = tt.pwdUpdater()
@@ -581,8 +622,8 @@ func TestCreateWithFirstLogin(t *testing.T) {
 			})
 
 			store := Store{
-				authorizer:           tt.authorizer,
-				getPasswordMinLength: func() int { return 12 },
+				authorizer:        tt.authorizer,
+				getPasswordPolicy: func() passwordpolicy.Policy { return passwordpolicy.Policy{MinLength: 12} },
 			}
 			if tt.pwdUpdater != nil {
 				store.pwdUpdater = tt.pwdUpdater()
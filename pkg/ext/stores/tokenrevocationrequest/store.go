@@ -0,0 +1,381 @@
+// tokenrevocationrequest implements the store for the imperative tokenrevocationrequest resource.
+package tokenrevocationrequest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	ext "github.com/rancher/rancher/pkg/apis/ext.cattle.io/v1"
+	apiv3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/auth/accessor"
+	"github.com/rancher/rancher/pkg/auth/tokens"
+	"github.com/rancher/rancher/pkg/controllers/status"
+	exttokens "github.com/rancher/rancher/pkg/ext/stores/tokens"
+	mgmtcontrollers "github.com/rancher/rancher/pkg/generated/controllers/management.cattle.io/v3"
+	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/wrangler"
+	corecontrollers "github.com/rancher/wrangler/v3/pkg/generated/controllers/core/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/apiserver/pkg/registry/rest"
+	"k8s.io/client-go/util/retry"
+)
+
+const (
+	SingularName = "tokenrevocationrequest"
+	kind         = "TokenRevocationRequest"
+
+	// KindSession selects login tokens.
+	KindSession = "session"
+	// KindDerived selects all tokens which are not login tokens.
+	KindDerived = "derived"
+
+	revokedCondition = "TokensRevoked"
+
+	// KindLabelValue is the value of the kind label of the ConfigMaps holding the tokens a request is revoking.
+	KindLabelValue = "token-revocation"
+	// PendingField is the entry of the ConfigMap holding the tokens which are not revoked yet.
+	PendingField = "pending"
+
+	namespace = exttokens.TokenNamespace
+)
+
+var (
+	_ rest.Creater                  = &Store{}
+	_ rest.Storage                  = &Store{}
+	_ rest.Scoper                   = &Store{}
+	_ rest.SingularNameProvider     = &Store{}
+	_ rest.GroupVersionKindProvider = &Store{}
+)
+
+var (
+	GVK = ext.SchemeGroupVersion.WithKind(kind)
+	gvr = ext.SchemeGroupVersion.WithResource(ext.TokenRevocationRequestResourceName)
+)
+
+// extTokenStore abstracts the ext token store operations needed to select the tokens to revoke.
+type extTokenStore interface {
+	ListAll() (*ext.TokenList, error)
+}
+
+// Pending are the tokens of a request which are not revoked yet, by name.
+type Pending struct {
+	V3Tokens  []string `json:"v3Tokens,omitempty"`
+	ExtTokens []string `json:"extTokens,omitempty"`
+}
+
+// Count returns the number of tokens.
+func (p *Pending) Count() int {
+	return len(p.V3Tokens) + len(p.ExtTokens)
+}
+
+// ParsePending returns the tokens held by a ConfigMap.
+func ParsePending(configMap *corev1.ConfigMap) (*Pending, error) {
+	pending := &Pending{}
+	if data := configMap.Data[PendingField]; data != "" {
+		if err := json.Unmarshal([]byte(data), pending); err != nil {
+			return nil, fmt.Errorf("parsing tokens of revocation %s: %w", configMap.Name, err)
+		}
+	}
+	return pending, nil
+}
+
+// SetPending stores the tokens in a ConfigMap.
+func SetPending(configMap *corev1.ConfigMap, pending *Pending) error {
+	data, err := json.Marshal(pending)
+	if err != nil {
+		return err
+	}
+	if configMap.Data == nil {
+		configMap.Data = map[string]string{}
+	}
+	configMap.Data[PendingField] = string(data)
+	return nil
+}
+
+// +k8s:openapi-gen=false
+// +k8s:deepcopy-gen=false
+
+type Store struct {
+	authorizer       authorizer.Authorizer
+	extTokens        extTokenStore
+	v3TokenCache     mgmtcontrollers.TokenCache
+	configMaps       corecontrollers.ConfigMapClient
+	userClient       mgmtcontrollers.UserClient
+	authConfigClient mgmtcontrollers.AuthConfigClient
+	now              func() time.Time
+}
+
+// +k8s:openapi-gen=false
+// +k8s:deepcopy-gen=false
+
+func New(wranglerContext *wrangler.Context, authorizer authorizer.Authorizer) *Store {
+	return &Store{
+		authorizer:       authorizer,
+		extTokens:        exttokens.NewSystemFromWrangler(wranglerContext),
+		v3TokenCache:     wranglerContext.Mgmt.Token().Cache(),
+		configMaps:       wranglerContext.Core.ConfigMap(),
+		userClient:       wranglerContext.Mgmt.User(),
+		authConfigClient: wranglerContext.Mgmt.AuthConfig(),
+		now:              time.Now,
+	}
+}
+
+// GroupVersionKind implements [rest.GroupVersionKindProvider], a required interface.
+func (s *Store) GroupVersionKind(_ schema.GroupVersion) schema.GroupVersionKind {
+	return GVK
+}
+
+// NamespaceScoped implements [rest.Scoper], a required interface.
+func (s *Store) NamespaceScoped() bool {
+	return false
+}
+
+// GetSingularName implements [rest.SingularNameProvider], a required interface.
+func (s *Store) GetSingularName() string {
+	return SingularName
+}
+
+// New implements [rest.Storage], a required interface.
+func (s *Store) New() runtime.Object {
+	return &ext.TokenRevocationRequest{}
+}
+
+// Destroy implements [rest.Storage], a required interface.
+func (s *Store) Destroy() {
+}
+
+// Create implements [rest.Creator], the interface to support the `create`
+// verb. Delegates to the actual store method after some generic boilerplate.
+func (s *Store) Create(
+	ctx context.Context,
+	obj runtime.Object,
+	createValidation rest.ValidateObjectFunc,
+	options *metav1.CreateOptions) (runtime.Object, error) {
+	if createValidation != nil {
+		err := createValidation(ctx, obj)
+		if err != nil {
+			return obj, err
+		}
+	}
+	dryRun := options != nil && len(options.DryRun) > 0 && options.DryRun[0] == metav1.DryRunAll
+
+	objTokenRevocationRequest, ok := obj.(*ext.TokenRevocationRequest)
+	if !ok {
+		var zeroT *ext.TokenRevocationRequest
+		return nil, apierrors.NewInternalError(fmt.Errorf("expected %T but got %T",
+			zeroT, obj))
+	}
+	spec := objTokenRevocationRequest.Spec
+	if spec.UserID == "" && spec.AuthProvider == "" && spec.Kind == "" && spec.ClusterName == "" &&
+		spec.CreatedAfter == nil && spec.CreatedBefore == nil {
+		return nil, apierrors.NewBadRequest("at least one selector must be set")
+	}
+	if spec.SetNotBefore && spec.UserID == "" && spec.AuthProvider == "" {
+		return nil, apierrors.NewBadRequest("user ID or auth provider must be set to set a not-before watermark")
+	}
+
+	userInfo, ok := request.UserFrom(ctx)
+	if !ok {
+		return nil, apierrors.NewInternalError(fmt.Errorf("can't get user info from context"))
+	}
+	// Only users that can delete all v3 Tokens are allowed to revoke tokens in bulk.
+	decision, _, err := s.authorizer.Authorize(ctx, &authorizer.AttributesRecord{
+		User:            userInfo,
+		Verb:            "delete",
+		APIGroup:        v3.TokenGroupVersionKind.Group,
+		APIVersion:      v3.Version,
+		Resource:        v3.TokenResource.Name,
+		ResourceRequest: true,
+	})
+	if err != nil {
+		return nil, apierrors.NewInternalError(fmt.Errorf("error checking permissions %w", err))
+	}
+	if decision != authorizer.DecisionAllow {
+		return nil, apierrors.NewForbidden(gvr.GroupResource(), "", fmt.Errorf("not authorized to revoke tokens"))
+	}
+
+	v3Tokens, err := s.v3TokenCache.List(labels.Everything())
+	if err != nil {
+		return nil, apierrors.NewInternalError(fmt.Errorf("failed to list tokens: %w", err))
+	}
+	extTokens, err := s.extTokens.ListAll()
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		revokeStatus ext.TokenRevocationRequestStatus
+		pending      Pending
+	)
+	for _, token := range v3Tokens {
+		if matches(spec, token) {
+			pending.V3Tokens = append(pending.V3Tokens, token.Name)
+		}
+	}
+	for i := range extTokens.Items {
+		if matches(spec, &extTokens.Items[i]) {
+			pending.ExtTokens = append(pending.ExtTokens, extTokens.Items[i].Name)
+		}
+	}
+	revokeStatus.MatchedCount = int64(pending.Count())
+
+	if spec.SetNotBefore && !dryRun {
+		notBefore := metav1.NewTime(s.now().UTC().Truncate(time.Second))
+		if err := s.setNotBefore(spec, notBefore); err != nil {
+			return nil, err
+		}
+		revokeStatus.NotBefore = &notBefore
+	}
+
+	condition := metav1.Condition{
+		LastTransitionTime: metav1.Now(),
+		Type:               revokedCondition,
+		Status:             metav1.ConditionTrue,
+	}
+	revokeStatus.Summary = status.SummaryCompleted
+	if !dryRun && pending.Count() > 0 {
+		// Deleting a large number of tokens could exceed the request timeout. The tokens are recorded in a ConfigMap
+		// and deleted by a controller, which retries until all of them are revoked, even across restarts. The
+		// not-before watermark, if any, already rejects the tokens.
+		configMap, err := s.record(&pending)
+		if err != nil {
+			return nil, apierrors.NewInternalError(fmt.Errorf("failed to record tokens to revoke: %w", err))
+		}
+		condition.Status = metav1.ConditionUnknown
+		condition.Message = fmt.Sprintf("revoking %d tokens, tracked by config map %s/%s", pending.Count(), configMap.Namespace, configMap.Name)
+		revokeStatus.Summary = status.SummaryInProgress
+	}
+
+	revokeStatus.Conditions = []metav1.Condition{condition}
+	objTokenRevocationRequest.Status = revokeStatus
+
+	return objTokenRevocationRequest, nil
+}
+
+// record creates the ConfigMap holding the tokens to revoke.
+func (s *Store) record(pending *Pending) (*corev1.ConfigMap, error) {
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: "token-revocation-",
+			Namespace:    namespace,
+			Labels:       map[string]string{exttokens.SecretKindLabel: KindLabelValue},
+		},
+	}
+	if err := SetPending(configMap, pending); err != nil {
+		return nil, err
+	}
+	return s.configMaps.Create(configMap)
+}
+
+// setNotBefore persists the not-before watermark on the user, if set, or else on the auth config of the provider.
+// An existing watermark is only moved forward.
+func (s *Store) setNotBefore(spec ext.TokenRevocationRequestSpec, notBefore metav1.Time) error {
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if spec.UserID != "" {
+			user, err := s.userClient.Get(spec.UserID, metav1.GetOptions{})
+			if err != nil {
+				return err
+			}
+			if !setWatermark(&user.ObjectMeta, notBefore) {
+				return nil
+			}
+			_, err = s.userClient.Update(user)
+			return err
+		}
+
+		authConfig, err := s.authConfigClient.Get(spec.AuthProvider, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		if !setWatermark(&authConfig.ObjectMeta, notBefore) {
+			return nil
+		}
+		_, err = s.authConfigClient.Update(authConfig)
+		return err
+	})
+	if apierrors.IsNotFound(err) {
+		return apierrors.NewBadRequest(fmt.Sprintf("failed to set not-before watermark: %v", err))
+	} else if err != nil {
+		return apierrors.NewInternalError(fmt.Errorf("failed to set not-before watermark: %w", err))
+	}
+	return nil
+}
+
+// setWatermark sets the not-before annotation unless it is already at or after the watermark, and returns true if
+// it was changed.
+func setWatermark(meta *metav1.ObjectMeta, notBefore metav1.Time) bool {
+	if value, ok := meta.Annotations[tokens.NotBeforeAnnotation]; ok {
+		if existing, err := time.Parse(time.RFC3339, value); err == nil && !existing.Before(notBefore.Time) {
+			return false
+		}
+	}
+	if meta.Annotations == nil {
+		meta.Annotations = map[string]string{}
+	}
+	meta.Annotations[tokens.NotBeforeAnnotation] = notBefore.UTC().Format(time.RFC3339)
+	return true
+}
+
+// matches returns true if the token matches all the selectors of the request.
+func matches(spec ext.TokenRevocationRequestSpec, token accessor.TokenAccessor) bool {
+	if spec.UserID != "" && token.GetUserID() != spec.UserID {
+		return false
+	}
+	if spec.AuthProvider != "" && token.GetAuthProvider() != spec.AuthProvider {
+		return false
+	}
+	if spec.ClusterName != "" && clusterName(token) != spec.ClusterName {
+		return false
+	}
+	switch spec.Kind {
+	case "":
+	case KindSession:
+		if token.GetIsDerived() {
+			return false
+		}
+	case KindDerived:
+		if !token.GetIsDerived() {
+			return false
+		}
+	default:
+		if tokenKind(token) != spec.Kind {
+			return false
+		}
+	}
+	created := token.GetCreationTime()
+	if spec.CreatedAfter != nil && created.Before(spec.CreatedAfter) {
+		return false
+	}
+	if spec.CreatedBefore != nil && !created.Before(spec.CreatedBefore) {
+		return false
+	}
+	return true
+}
+
+// clusterName returns the name of the cluster the token is scoped to.
+func clusterName(token accessor.TokenAccessor) string {
+	if extToken, ok := token.(*ext.Token); ok {
+		return extToken.Spec.ClusterName
+	}
+	return token.ObjClusterName()
+}
+
+// tokenKind returns the kind of the token, e.g. "session" or "kubeconfig".
+func tokenKind(token accessor.TokenAccessor) string {
+	switch t := token.(type) {
+	case *ext.Token:
+		return t.Spec.Kind
+	case *apiv3.Token:
+		return t.Labels[tokens.TokenKindLabel]
+	}
+	return ""
+}
@@ -0,0 +1,270 @@
+package tokenrevocationrequest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	ext "github.com/rancher/rancher/pkg/apis/ext.cattle.io/v1"
+	apiv3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/auth/tokens"
+	"github.com/rancher/rancher/pkg/controllers/status"
+	exttokens "github.com/rancher/rancher/pkg/ext/stores/tokens"
+	"github.com/rancher/wrangler/v3/pkg/generic/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	"k8s.io/apiserver/pkg/endpoints/request"
+)
+
+const adminID = "user-admin"
+
+var commonAuthorizer = authorizer.AuthorizerFunc(func(ctx context.Context, a authorizer.Attributes) (authorizer.Decision, string, error) {
+	if a.GetUser().GetName() == adminID && a.GetVerb() == "delete" && a.GetResource() == "tokens" {
+		return authorizer.DecisionAllow, "", nil
+	}
+	return authorizer.DecisionDeny, "", nil
+})
+
+type fakeExtTokens struct {
+	tokens []ext.Token
+}
+
+func (f *fakeExtTokens) ListAll() (*ext.TokenList, error) {
+	return &ext.TokenList{Items: f.tokens}, nil
+}
+
+func TestCreate(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	created := func(d time.Duration) metav1.Time { return metav1.NewTime(now.Add(d)) }
+
+	v3Tokens := []*apiv3.Token{
+		{
+			ObjectMeta:   metav1.ObjectMeta{Name: "token-session", CreationTimestamp: created(-48 * time.Hour), Labels: map[string]string{tokens.TokenKindLabel: "session"}},
+			UserID:       "u-alice",
+			AuthProvider: "github",
+		},
+		{
+			ObjectMeta:   metav1.ObjectMeta{Name: "token-kubeconfig", CreationTimestamp: created(-time.Hour), Labels: map[string]string{tokens.TokenKindLabel: "kubeconfig"}},
+			UserID:       "u-alice",
+			AuthProvider: "github",
+			IsDerived:    true,
+			ClusterName:  "c-m-abc",
+		},
+		{
+			ObjectMeta:   metav1.ObjectMeta{Name: "token-bob", CreationTimestamp: created(-time.Hour)},
+			UserID:       "u-bob",
+			AuthProvider: "local",
+			IsDerived:    true,
+		},
+	}
+	extTokens := []ext.Token{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "ext-session", CreationTimestamp: created(-time.Hour)},
+			Spec: ext.TokenSpec{
+				UserID:        "u-alice",
+				Kind:          "session",
+				UserPrincipal: ext.TokenPrincipal{Provider: "github"},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "ext-derived", CreationTimestamp: created(-48 * time.Hour)},
+			Spec: ext.TokenSpec{
+				UserID:        "u-bob",
+				ClusterName:   "c-m-abc",
+				UserPrincipal: ext.TokenPrincipal{Provider: "local"},
+			},
+		},
+	}
+
+	tests := map[string]struct {
+		user        string
+		spec        ext.TokenRevocationRequestSpec
+		dryRun      bool
+		wantV3      []string
+		wantExt     []string
+		wantMatched int64
+		wantErr     func(error) bool
+	}{
+		"no selector": {
+			user:    adminID,
+			wantErr: apierrors.IsBadRequest,
+		},
+		"not-before without user or provider": {
+			user:    adminID,
+			spec:    ext.TokenRevocationRequestSpec{Kind: KindSession, SetNotBefore: true},
+			wantErr: apierrors.IsBadRequest,
+		},
+		"not authorized": {
+			user:    "u-alice",
+			spec:    ext.TokenRevocationRequestSpec{UserID: "u-alice"},
+			wantErr: apierrors.IsForbidden,
+		},
+		"by user": {
+			user:        adminID,
+			spec:        ext.TokenRevocationRequestSpec{UserID: "u-alice"},
+			wantV3:      []string{"token-session", "token-kubeconfig"},
+			wantExt:     []string{"ext-session"},
+			wantMatched: 3,
+		},
+		"by provider and session kind": {
+			user:        adminID,
+			spec:        ext.TokenRevocationRequestSpec{AuthProvider: "github", Kind: KindSession},
+			wantV3:      []string{"token-session"},
+			wantExt:     []string{"ext-session"},
+			wantMatched: 2,
+		},
+		"by derived kind": {
+			user:        adminID,
+			spec:        ext.TokenRevocationRequestSpec{Kind: KindDerived},
+			wantV3:      []string{"token-kubeconfig", "token-bob"},
+			wantExt:     []string{"ext-derived"},
+			wantMatched: 3,
+		},
+		"by kind label": {
+			user:        adminID,
+			spec:        ext.TokenRevocationRequestSpec{Kind: "kubeconfig"},
+			wantV3:      []string{"token-kubeconfig"},
+			wantMatched: 1,
+		},
+		"by cluster": {
+			user:        adminID,
+			spec:        ext.TokenRevocationRequestSpec{ClusterName: "c-m-abc"},
+			wantV3:      []string{"token-kubeconfig"},
+			wantExt:     []string{"ext-derived"},
+			wantMatched: 2,
+		},
+		"by time window": {
+			user: adminID,
+			spec: ext.TokenRevocationRequestSpec{
+				CreatedAfter:  &metav1.Time{Time: now.Add(-24 * time.Hour)},
+				CreatedBefore: &metav1.Time{Time: now},
+			},
+			wantV3:      []string{"token-kubeconfig", "token-bob"},
+			wantExt:     []string{"ext-session"},
+			wantMatched: 3,
+		},
+		"dry run": {
+			user:        adminID,
+			spec:        ext.TokenRevocationRequestSpec{UserID: "u-alice"},
+			dryRun:      true,
+			wantMatched: 3,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			v3TokenCache := fake.NewMockNonNamespacedCacheInterface[*apiv3.Token](ctrl)
+			v3TokenCache.EXPECT().List(gomock.Any()).Return(v3Tokens, nil).AnyTimes()
+			configMaps := fake.NewMockControllerInterface[*corev1.ConfigMap, *corev1.ConfigMapList](ctrl)
+			var recorded []*corev1.ConfigMap
+			configMaps.EXPECT().Create(gomock.Any()).DoAndReturn(func(configMap *corev1.ConfigMap) (*corev1.ConfigMap, error) {
+				configMap = configMap.DeepCopy()
+				configMap.Name = configMap.GenerateName + "abcde"
+				recorded = append(recorded, configMap)
+				return configMap, nil
+			}).AnyTimes()
+
+			store := &Store{
+				authorizer:   commonAuthorizer,
+				extTokens:    &fakeExtTokens{tokens: extTokens},
+				v3TokenCache: v3TokenCache,
+				configMaps:   configMaps,
+				now:          func() time.Time { return now },
+			}
+			options := &metav1.CreateOptions{}
+			if tt.dryRun {
+				options.DryRun = []string{metav1.DryRunAll}
+			}
+
+			ctx := request.WithUser(context.Background(), &user.DefaultInfo{Name: tt.user})
+			obj, err := store.Create(ctx, &ext.TokenRevocationRequest{Spec: tt.spec}, nil, options)
+			if tt.wantErr != nil {
+				assert.True(t, tt.wantErr(err), "unexpected error %v", err)
+				return
+			}
+			require.NoError(t, err)
+
+			revocation := obj.(*ext.TokenRevocationRequest)
+			assert.Equal(t, tt.wantMatched, revocation.Status.MatchedCount)
+			require.Len(t, revocation.Status.Conditions, 1)
+			if tt.dryRun {
+				assert.Empty(t, recorded)
+				assert.Equal(t, status.SummaryCompleted, revocation.Status.Summary)
+				assert.Equal(t, metav1.ConditionTrue, revocation.Status.Conditions[0].Status)
+				return
+			}
+
+			// Tokens are recorded to be deleted by the controller, after the request returns.
+			assert.Equal(t, status.SummaryInProgress, revocation.Status.Summary)
+			assert.Equal(t, metav1.ConditionUnknown, revocation.Status.Conditions[0].Status)
+			assert.Contains(t, revocation.Status.Conditions[0].Message, "config map cattle-tokens/token-revocation-abcde")
+			require.Len(t, recorded, 1)
+			assert.Equal(t, namespace, recorded[0].Namespace)
+			assert.Equal(t, KindLabelValue, recorded[0].Labels[exttokens.SecretKindLabel])
+			pending, err := ParsePending(recorded[0])
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantV3, pending.V3Tokens)
+			assert.Equal(t, tt.wantExt, pending.ExtTokens)
+		})
+	}
+}
+
+func TestCreateSetNotBefore(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	ctx := request.WithUser(context.Background(), &user.DefaultInfo{Name: adminID})
+
+	newStore := func(ctrl *gomock.Controller) *Store {
+		v3TokenCache := fake.NewMockNonNamespacedCacheInterface[*apiv3.Token](ctrl)
+		v3TokenCache.EXPECT().List(gomock.Any()).Return(nil, nil)
+		return &Store{
+			authorizer:   commonAuthorizer,
+			extTokens:    &fakeExtTokens{},
+			v3TokenCache: v3TokenCache,
+			now:          func() time.Time { return now },
+		}
+	}
+
+	t.Run("user", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		users := fake.NewMockNonNamespacedClientInterface[*apiv3.User, *apiv3.UserList](ctrl)
+		store := newStore(ctrl)
+		store.userClient = users
+		users.EXPECT().Get("u-alice", metav1.GetOptions{}).Return(&apiv3.User{ObjectMeta: metav1.ObjectMeta{Name: "u-alice"}}, nil)
+		users.EXPECT().Update(gomock.Any()).DoAndReturn(func(user *apiv3.User) (*apiv3.User, error) {
+			assert.Equal(t, "2026-03-01T12:00:00Z", user.Annotations[tokens.NotBeforeAnnotation])
+			return user, nil
+		})
+
+		obj, err := store.Create(ctx, &ext.TokenRevocationRequest{
+			Spec: ext.TokenRevocationRequestSpec{UserID: "u-alice", SetNotBefore: true},
+		}, nil, &metav1.CreateOptions{})
+		require.NoError(t, err)
+		revocation := obj.(*ext.TokenRevocationRequest)
+		require.NotNil(t, revocation.Status.NotBefore)
+		assert.True(t, now.Equal(revocation.Status.NotBefore.Time))
+	})
+
+	t.Run("auth provider with later watermark", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		authConfigs := fake.NewMockNonNamespacedClientInterface[*apiv3.AuthConfig, *apiv3.AuthConfigList](ctrl)
+		store := newStore(ctrl)
+		store.authConfigClient = authConfigs
+		authConfigs.EXPECT().Get("github", metav1.GetOptions{}).Return(&apiv3.AuthConfig{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "github",
+				Annotations: map[string]string{tokens.NotBeforeAnnotation: "2026-03-02T00:00:00Z"},
+			},
+		}, nil)
+
+		_, err := store.Create(ctx, &ext.TokenRevocationRequest{
+			Spec: ext.TokenRevocationRequestSpec{AuthProvider: "github", SetNotBefore: true},
+		}, nil, &metav1.CreateOptions{})
+		require.NoError(t, err)
+	})
+}
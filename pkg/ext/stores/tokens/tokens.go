@@ -686,41 +686,79 @@ func (t *SystemStore) Create(ctx context.Context, group schema.GroupResource, to
 	}
 
 	if token.Spec.ClusterName != "" {
-		// Verify existence of cluster
-		cluster, err := t.clusterCache.Get(token.Spec.ClusterName)
-		if err != nil {
-			if apierrors.IsNotFound(err) {
-				return nil, apierrors.NewBadRequest(fmt.Sprintf("cluster %s not found",
-					token.Spec.ClusterName))
-			}
-			return nil, apierrors.NewInternalError(fmt.Errorf("error getting cluster %s: %w",
-				token.Spec.ClusterName, err))
+		if err := t.authorizeCluster(ctx, userInfo, token.Spec.ClusterName); err != nil {
+			return nil, err
 		}
+	}
 
-		if t.authorizer == nil {
-			return nil, apierrors.NewInternalError(fmt.Errorf("authorizer is required for cluster-scoped tokens"))
-		}
+	return t.store(token, dryRun)
+}
 
-		decision, _, err := t.authorizer.Authorize(ctx, &authorizer.AttributesRecord{
-			User:            userInfo,
-			Verb:            "get",
-			APIGroup:        mgmt.GroupName,
-			Resource:        apiv3.ClusterResourceName,
-			ResourceRequest: true,
-			Name:            cluster.Name,
-		})
-		if err != nil {
-			return nil, apierrors.NewInternalError(fmt.Errorf("error authorizing user %s to access cluster %s: %w",
-				userInfo.GetName(), cluster.Name, err))
+// CreateForPrincipal creates a token acting as the given principal of the token's user. Unlike [SystemStore.Create]
+// it doesn't take the principal from the token of the request. It is used by Rancher to issue tokens to clients that
+// don't have a Rancher session, and callers are responsible for authorizing them. As with [SystemStore.Create], the
+// user described by userInfo must be allowed to access the cluster the token is scoped to.
+func (t *SystemStore) CreateForPrincipal(ctx context.Context, token *ext.Token, principal ext.TokenPrincipal, userInfo user.Info) (*ext.Token, error) {
+	user, err := t.userClient.Get(token.Spec.UserID)
+	if err != nil {
+		return nil, apierrors.NewInternalError(fmt.Errorf("failed to retrieve user %s: %w",
+			token.Spec.UserID, err))
+	}
+
+	// Reject operation if the user is disabled.
+	if user.Enabled != nil && !*user.Enabled {
+		return nil, apierrors.NewBadRequest("operation references a disabled user")
+	}
+
+	token.Spec.UserPrincipal = principal
+
+	if token.Spec.ClusterName != "" {
+		if err := t.authorizeCluster(ctx, userInfo, token.Spec.ClusterName); err != nil {
+			return nil, err
 		}
+	}
 
-		if decision != authorizer.DecisionAllow {
-			return nil, apierrors.NewForbidden(GVR.GroupResource(), "",
-				fmt.Errorf("user %s is not allowed to access cluster %s",
-					userInfo.GetName(), cluster.Name))
+	return t.store(token, false)
+}
+
+// authorizeCluster verifies that the cluster a token is scoped to exists and that the user is allowed to access it.
+func (t *SystemStore) authorizeCluster(ctx context.Context, userInfo user.Info, clusterName string) error {
+	// Verify existence of cluster
+	cluster, err := t.clusterCache.Get(clusterName)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return apierrors.NewBadRequest(fmt.Sprintf("cluster %s not found", clusterName))
 		}
+		return apierrors.NewInternalError(fmt.Errorf("error getting cluster %s: %w", clusterName, err))
+	}
+
+	if t.authorizer == nil {
+		return apierrors.NewInternalError(fmt.Errorf("authorizer is required for cluster-scoped tokens"))
+	}
+
+	decision, _, err := t.authorizer.Authorize(ctx, &authorizer.AttributesRecord{
+		User:            userInfo,
+		Verb:            "get",
+		APIGroup:        mgmt.GroupName,
+		Resource:        apiv3.ClusterResourceName,
+		ResourceRequest: true,
+		Name:            cluster.Name,
+	})
+	if err != nil {
+		return apierrors.NewInternalError(fmt.Errorf("error authorizing user %s to access cluster %s: %w",
+			userInfo.GetName(), cluster.Name, err))
 	}
 
+	if decision != authorizer.DecisionAllow {
+		return apierrors.NewForbidden(GVR.GroupResource(), "",
+			fmt.Errorf("user %s is not allowed to access cluster %s",
+				userInfo.GetName(), cluster.Name))
+	}
+	return nil
+}
+
+// store generates the secret of a new token and stores the token, returning it with its bearer token.
+func (t *SystemStore) store(token *ext.Token, dryRun bool) (*ext.Token, error) {
 	// Generate a secret and its hash
 	tokenValue, hashedValue, err := t.hasher.MakeAndHashSecret()
 	if err != nil {
@@ -917,6 +955,29 @@ func (t *SystemStore) ListForUser(userName string) (*ext.TokenList, error) {
 	}, nil
 }
 
+// ListAll returns all ext tokens. It is an internal call invoked by other
+// parts of Rancher.
+func (t *SystemStore) ListAll() (*ext.TokenList, error) {
+	secrets, err := t.secretCache.List(TokenNamespace, labels.Set(map[string]string{
+		SecretKindLabel: SecretKindLabelValue,
+	}).AsSelector())
+	if err != nil {
+		return nil, apierrors.NewInternalError(fmt.Errorf("failed to list tokens: %w", err))
+	}
+
+	var tokens []ext.Token
+	for _, secret := range secrets {
+		token, err := fromSecret(secret)
+		// ignore broken tokens
+		if err != nil {
+			continue
+		}
+		tokens = append(tokens, *token)
+	}
+
+	return &ext.TokenList{Items: tokens}, nil
+}
+
 // ListForProvider returns all ext tokens associated with the named auth
 // provider. It is an internal call invoked by other parts of Rancher.
 func (t *SystemStore) ListForProvider(provider string) (*ext.TokenList, error) {
@@ -1087,6 +1148,21 @@ func (t *SystemStore) AddLabel(name, key, value string) error {
 	return err
 }
 
+// RemoveLabel removes a custom label from the named ext token. This is done
+// directly on the secret. The label must be present.
+func (t *SystemStore) RemoveLabel(name, key string) error {
+	escapedKey := strings.ReplaceAll(strings.ReplaceAll(key, "~", "~0"), "/", "~1")
+	patch, err := json.Marshal([]JsonPatch{{
+		Op:   "remove",
+		Path: "/metadata/labels/" + escapedKey,
+	}})
+	if err != nil {
+		return err
+	}
+	_, err = t.secretClient.Patch(TokenNamespace, name, types.JSONPatchType, patch)
+	return err
+}
+
 // UpdateLastUsedAt patches the last-used-at information of the token.
 // Called during authentication.
 func (t *SystemStore) UpdateLastUsedAt(name string, now time.Time) error {
@@ -24,6 +24,7 @@
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/apiserver/pkg/authentication/user"
 	"k8s.io/apiserver/pkg/authorization/authorizer"
 	"k8s.io/utils/ptr"
 	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
@@ -1946,6 +1947,125 @@ func TestSystemStoreCreateClusterScoped(t *testing.T) {
 	}
 }
 
+func TestSystemStoreCreateForPrincipal(t *testing.T) {
+	t.Parallel()
+
+	principal := ext.TokenPrincipal{Name: "local://world", Provider: "local"}
+
+	tests := []struct {
+		name        string
+		user        *v3.User
+		userInfo    user.Info
+		clusterName string
+		clusterErr  error
+		err         string
+	}{
+		{
+			name: "unscoped token",
+			user: &v3.User{Enabled: ptr.To(true)},
+		},
+		{
+			name:        "cluster-scoped token",
+			user:        &v3.User{Enabled: ptr.To(true)},
+			userInfo:    &user.DefaultInfo{Name: "world"},
+			clusterName: "c-m-test",
+		},
+		{
+			name:        "user not allowed to access the cluster",
+			user:        &v3.User{Enabled: ptr.To(true)},
+			userInfo:    &user.DefaultInfo{Name: "someone-else"},
+			clusterName: "c-m-test",
+			err:         "user someone-else is not allowed to access cluster c-m-test",
+		},
+		{
+			name: "disabled user",
+			user: &v3.User{Enabled: ptr.To(false)},
+			err:  "operation references a disabled user",
+		},
+		{
+			name:        "cluster not found",
+			user:        &v3.User{Enabled: ptr.To(true)},
+			clusterName: "c-m-missing",
+			clusterErr:  apierrors.NewNotFound(GVR.GroupResource(), "c-m-missing"),
+			err:         "cluster c-m-missing not found",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+
+			secrets := fake.NewMockControllerInterface[*corev1.Secret, *corev1.SecretList](ctrl)
+			scache := fake.NewMockCacheInterface[*corev1.Secret](ctrl)
+			secrets.EXPECT().Cache().Return(scache)
+
+			users := fake.NewMockNonNamespacedControllerInterface[*v3.User, *v3.UserList](ctrl)
+			ucache := fake.NewMockNonNamespacedCacheInterface[*v3.User](ctrl)
+			users.EXPECT().Cache().Return(ucache)
+
+			nsCache := fake.NewMockNonNamespacedCacheInterface[*corev1.Namespace](ctrl)
+			nsCache.EXPECT().Get(TokenNamespace).AnyTimes()
+
+			tcache := fake.NewMockNonNamespacedCacheInterface[*v3.Token](ctrl)
+			ccache := fake.NewMockNonNamespacedCacheInterface[*v3.Cluster](ctrl)
+
+			timer := NewMocktimeHandler(ctrl)
+			hasher := NewMockhashHandler(ctrl)
+			// No session is needed to create the token.
+			auth := NewMockauthHandler(ctrl)
+
+			// Only the token's user is allowed to access the cluster.
+			authz := authorizer.AuthorizerFunc(func(_ context.Context, a authorizer.Attributes) (authorizer.Decision, string, error) {
+				if a.GetUser().GetName() == "world" && a.GetResource() == "clusters" && a.GetName() == "c-m-test" {
+					return authorizer.DecisionAllow, "", nil
+				}
+				return authorizer.DecisionNoOpinion, "", nil
+			})
+
+			store := NewSystem(nil, nsCache, secrets, users, tcache, ccache, timer, hasher, auth, authz)
+
+			ucache.EXPECT().Get("world").Return(test.user, nil)
+			if test.clusterName != "" {
+				ccache.EXPECT().Get(test.clusterName).Return(&v3.Cluster{ObjectMeta: metav1.ObjectMeta{Name: test.clusterName}}, test.clusterErr)
+			}
+
+			var stored *corev1.Secret
+			if test.err == "" {
+				hasher.EXPECT().MakeAndHashSecret().Return("secretval", "hashval", nil)
+				timer.EXPECT().Now().Return("fake-now")
+
+				secrets.EXPECT().Create(gomock.Any()).DoAndReturn(func(secret *corev1.Secret) (*corev1.Secret, error) {
+					stored = secret
+					return &properSecret, nil
+				})
+			}
+
+			token := &ext.Token{
+				Spec: ext.TokenSpec{
+					UserID:      "world",
+					ClusterName: test.clusterName,
+					UserPrincipal: ext.TokenPrincipal{
+						Name: "local://someone-else",
+					},
+				},
+			}
+			created, err := store.CreateForPrincipal(context.Background(), token, principal, test.userInfo)
+
+			if test.err != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), test.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "secretval", created.Status.Value)
+			assert.Equal(t, "ext/"+created.Name+":secretval", created.Status.BearerToken)
+			assert.Equal(t, test.clusterName, stored.StringData[FieldClusterName])
+			assert.Contains(t, stored.StringData[FieldPrincipal], `"name":"local://world"`)
+		})
+	}
+}
+
 func TestSystemStoreList(t *testing.T) {
 	tests := []struct {
 		name       string              // test name
@@ -2351,6 +2471,30 @@ func TestSystemStoreAddLabel(t *testing.T) {
 	})
 }
 
+func TestSystemStoreRemoveLabel(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	// assemble and configure store from mock clients ...
+	secrets := fake.NewMockControllerInterface[*corev1.Secret, *corev1.SecretList](ctrl)
+	users := fake.NewMockNonNamespacedControllerInterface[*v3.User, *v3.UserList](ctrl)
+
+	users.EXPECT().Cache().Return(nil)
+	secrets.EXPECT().Cache().Return(nil)
+
+	store := NewSystem(nil, nil, secrets, users, nil, nil, nil, nil, nil)
+
+	patch, err := json.Marshal([]JsonPatch{{
+		Op:   "remove",
+		Path: "/metadata/labels/cattle.io~1test",
+	}})
+	assert.NoError(t, err)
+	secrets.EXPECT().Patch("cattle-tokens", "atoken", types.JSONPatchType, patch).
+		Return(nil, nil).Times(1)
+
+	err = store.RemoveLabel("atoken", "cattle.io/test")
+	assert.NoError(t, err)
+}
+
 func TestSystemStoreUpdateLastUsedAt(t *testing.T) {
 	t.Run("patch last-used-at, ok", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
@@ -3197,3 +3341,29 @@ func TestSystemStoreListForProvider(t *testing.T) {
 		})
 	}
 }
+
+func TestSystemStoreListAll(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	secrets := fake.NewMockControllerInterface[*corev1.Secret, *corev1.SecretList](ctrl)
+	scache := fake.NewMockCacheInterface[*corev1.Secret](ctrl)
+	users := fake.NewMockNonNamespacedControllerInterface[*v3.User, *v3.UserList](ctrl)
+
+	users.EXPECT().Cache().Return(nil)
+	secrets.EXPECT().Cache().Return(scache)
+
+	store := NewSystem(nil, nil, secrets, users, nil, nil, nil, nil, nil)
+
+	scache.EXPECT().
+		List(TokenNamespace, gomock.Any()).
+		Return([]*corev1.Secret{&properSecret, &badSecret}, nil)
+	toks, err := store.ListAll()
+	require.NoError(t, err)
+	require.Len(t, toks.Items, 1)
+	assert.Equal(t, properSecret.Name, toks.Items[0].Name)
+
+	scache.EXPECT().
+		List(TokenNamespace, gomock.Any()).
+		Return(nil, errSomeError)
+	_, err = store.ListAll()
+	assert.Equal(t, apierrors.NewInternalError(fmt.Errorf("failed to list tokens: %w", errSomeError)), err)
+}
@@ -0,0 +1,250 @@
+package tokenusage
+
+import (
+	"context"
+	"fmt"
+
+	ext "github.com/rancher/rancher/pkg/apis/ext.cattle.io/v1"
+	exttokens "github.com/rancher/rancher/pkg/ext/stores/tokens"
+	"github.com/rancher/rancher/pkg/wrangler"
+	extapi "github.com/rancher/steve/pkg/ext"
+	v1 "github.com/rancher/wrangler/v3/pkg/generated/controllers/core/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metainternalversion "k8s.io/apimachinery/pkg/apis/meta/internalversion"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/selection"
+	k8suser "k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/apiserver/pkg/registry/rest"
+)
+
+const (
+	Kind         = "TokenUsage"
+	SingularName = "tokenusage"
+)
+
+var (
+	GVK = ext.SchemeGroupVersion.WithKind(Kind)
+	gvr = ext.SchemeGroupVersion.WithResource(ext.TokenUsageResourceName)
+)
+
+// tokenGetter abstracts the ext token store operations needed by the token usage store.
+type tokenGetter interface {
+	Get(name, authTokenID string, options *metav1.GetOptions) (*ext.Token, error)
+}
+
+// +k8s:openapi-gen=false
+// +k8s:deepcopy-gen=false
+
+// Store is the read-only store of the usage of ext tokens. Users can see the usage of their own tokens, admins the
+// usage of all tokens.
+type Store struct {
+	authorizer      authorizer.Authorizer
+	configMapCache  v1.ConfigMapCache
+	configMapClient v1.ConfigMapClient
+	tokens          tokenGetter
+	tableConverter  rest.TableConvertor
+}
+
+// New creates a new instance of [Store].
+func New(wranglerContext *wrangler.Context, authorizer authorizer.Authorizer) *Store {
+	return &Store{
+		authorizer:      authorizer,
+		configMapCache:  wranglerContext.Core.ConfigMap().Cache(),
+		configMapClient: wranglerContext.Core.ConfigMap(),
+		tokens:          exttokens.NewSystemFromWrangler(wranglerContext),
+		tableConverter:  rest.NewDefaultTableConvertor(gvr.GroupResource()),
+	}
+}
+
+// GroupVersionKind implements [rest.GroupVersionKindProvider].
+func (s *Store) GroupVersionKind(_ schema.GroupVersion) schema.GroupVersionKind {
+	return GVK
+}
+
+// NamespaceScoped implements [rest.Scoper].
+func (s *Store) NamespaceScoped() bool { return false }
+
+// GetSingularName implements [rest.SingularNameProvider].
+func (s *Store) GetSingularName() string {
+	return SingularName
+}
+
+// New implements [rest.Storage].
+func (s *Store) New() runtime.Object {
+	return &ext.TokenUsage{}
+}
+
+// Destroy implements [rest.Storage].
+func (s *Store) Destroy() {}
+
+// Get implements [rest.Getter]. It returns an empty usage for tokens without recorded usage.
+func (s *Store) Get(
+	ctx context.Context,
+	name string,
+	options *metav1.GetOptions,
+) (runtime.Object, error) {
+	userInfo, isAdmin, err := s.userFrom(ctx, "get")
+	if err != nil {
+		return nil, apierrors.NewInternalError(fmt.Errorf("error getting user info: %w", err))
+	}
+
+	token, err := s.tokens.Get(name, "", nil)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, apierrors.NewNotFound(gvr.GroupResource(), name)
+		}
+		return nil, err
+	}
+	if token.GetUserID() != userInfo.GetName() && !isAdmin {
+		// An ordinary user can only access the usage of their own tokens.
+		// We return a NotFound error to avoid leaking information about other users' tokens.
+		return nil, apierrors.NewNotFound(gvr.GroupResource(), name)
+	}
+
+	var emptyGetOptions metav1.GetOptions
+	var configMap *corev1.ConfigMap
+	if options == nil || *options == emptyGetOptions {
+		configMap, err = s.configMapCache.Get(namespace, name)
+	} else {
+		configMap, err = s.configMapClient.Get(namespace, name, *options)
+	}
+	if apierrors.IsNotFound(err) {
+		return &ext.TokenUsage{
+			ObjectMeta: metav1.ObjectMeta{Name: name, CreationTimestamp: token.CreationTimestamp},
+			Status:     ext.TokenUsageStatus{UserID: token.GetUserID()},
+		}, nil
+	} else if err != nil {
+		return nil, apierrors.NewInternalError(fmt.Errorf("error getting usage of token %s: %w", name, err))
+	}
+
+	usage, err := toTokenUsage(configMap)
+	if err != nil {
+		return nil, apierrors.NewInternalError(err)
+	}
+	return usage, nil
+}
+
+// NewList implements [rest.Lister].
+func (s *Store) NewList() runtime.Object {
+	return &ext.TokenUsageList{}
+}
+
+// List implements [rest.Lister]. Only tokens with recorded usage are listed.
+func (s *Store) List(
+	ctx context.Context,
+	options *metainternalversion.ListOptions,
+) (runtime.Object, error) {
+	userInfo, isAdmin, err := s.userFrom(ctx, "list")
+	if err != nil {
+		return nil, apierrors.NewInternalError(fmt.Errorf("error getting user info: %w", err))
+	}
+
+	listOptions, err := extapi.ConvertListOptions(options)
+	if err != nil {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("error converting list options: %v", err))
+	}
+	selector, err := labels.Parse(listOptions.LabelSelector)
+	if err != nil {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("invalid label selector: %v", err))
+	}
+	kindReq, err := labels.NewRequirement(exttokens.SecretKindLabel, selection.Equals, []string{KindLabelValue})
+	if err != nil {
+		return nil, apierrors.NewInternalError(err)
+	}
+	selector = selector.Add(*kindReq)
+	if !isAdmin {
+		userReq, err := labels.NewRequirement(exttokens.UserIDLabel, selection.Equals, []string{userInfo.GetName()})
+		if err != nil {
+			return nil, apierrors.NewInternalError(fmt.Errorf("user ID %q is not a valid label value: %w", userInfo.GetName(), err))
+		}
+		selector = selector.Add(*userReq)
+	}
+	listOptions.LabelSelector = selector.String()
+
+	configMapList, err := s.configMapClient.List(namespace, *listOptions)
+	if err != nil {
+		if apierrors.IsResourceExpired(err) || apierrors.IsGone(err) { // Continue token expired.
+			return nil, apierrors.NewResourceExpired(err.Error())
+		}
+		return nil, apierrors.NewInternalError(fmt.Errorf("error listing configmaps for token usage: %w", err))
+	}
+
+	list := &ext.TokenUsageList{
+		ListMeta: metav1.ListMeta{
+			Continue:           configMapList.Continue,
+			ResourceVersion:    configMapList.ResourceVersion,
+			RemainingItemCount: configMapList.RemainingItemCount,
+		},
+		Items: make([]ext.TokenUsage, 0, len(configMapList.Items)),
+	}
+	for _, configMap := range configMapList.Items {
+		usage, err := toTokenUsage(&configMap)
+		if err != nil {
+			return nil, apierrors.NewInternalError(err)
+		}
+		list.Items = append(list.Items, *usage)
+	}
+	return list, nil
+}
+
+// ConvertToTable implements [rest.TableConvertor].
+func (s *Store) ConvertToTable(ctx context.Context, object runtime.Object, tableOptions runtime.Object) (*metav1.Table, error) {
+	return s.tableConverter.ConvertToTable(ctx, object, tableOptions)
+}
+
+func toTokenUsage(configMap *corev1.ConfigMap) (*ext.TokenUsage, error) {
+	status, err := ParseUsage(configMap)
+	if err != nil {
+		return nil, err
+	}
+	return &ext.TokenUsage{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       Kind,
+			APIVersion: ext.SchemeGroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              configMap.Name,
+			CreationTimestamp: configMap.CreationTimestamp,
+			ResourceVersion:   configMap.ResourceVersion,
+			UID:               configMap.UID,
+		},
+		Status: *status,
+	}, nil
+}
+
+// userFrom is a helper that extracts the user info from the request's context and checks if the user is an admin.
+func (s *Store) userFrom(ctx context.Context, verb string) (k8suser.Info, bool, error) {
+	userInfo, ok := request.UserFrom(ctx)
+	if !ok {
+		return nil, false, fmt.Errorf("missing user info")
+	}
+
+	// Resource: "*" is the superuser heuristic of the sibling token and kubeconfig stores.
+	decision, _, err := s.authorizer.Authorize(ctx, &authorizer.AttributesRecord{
+		User:            userInfo,
+		Verb:            verb,
+		Resource:        "*",
+		ResourceRequest: true,
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	return userInfo, decision == authorizer.DecisionAllow, nil
+}
+
+var (
+	_ rest.Getter                   = &Store{}
+	_ rest.Lister                   = &Store{}
+	_ rest.TableConvertor           = &Store{}
+	_ rest.Storage                  = &Store{}
+	_ rest.Scoper                   = &Store{}
+	_ rest.SingularNameProvider     = &Store{}
+	_ rest.GroupVersionKindProvider = &Store{}
+)
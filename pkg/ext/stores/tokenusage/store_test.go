@@ -0,0 +1,160 @@
+package tokenusage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	ext "github.com/rancher/rancher/pkg/apis/ext.cattle.io/v1"
+	exttokens "github.com/rancher/rancher/pkg/ext/stores/tokens"
+	"github.com/rancher/wrangler/v3/pkg/generic/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metainternalversion "k8s.io/apimachinery/pkg/apis/meta/internalversion"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	k8suser "k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	"k8s.io/apiserver/pkg/endpoints/request"
+)
+
+const (
+	adminID = "user-admin"
+	userID  = "u-alice"
+)
+
+var commonAuthorizer = authorizer.AuthorizerFunc(func(ctx context.Context, a authorizer.Attributes) (authorizer.Decision, string, error) {
+	if a.GetUser().GetName() == adminID {
+		return authorizer.DecisionAllow, "", nil
+	}
+	return authorizer.DecisionDeny, "", nil
+})
+
+type fakeTokens map[string]*ext.Token
+
+func (f fakeTokens) Get(name, _ string, _ *metav1.GetOptions) (*ext.Token, error) {
+	if token, ok := f[name]; ok {
+		return token, nil
+	}
+	return nil, apierrors.NewNotFound(exttokens.GVR.GroupResource(), name)
+}
+
+func TestStoreGet(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	tokens := fakeTokens{
+		"token-used":   {ObjectMeta: metav1.ObjectMeta{Name: "token-used"}, Spec: ext.TokenSpec{UserID: userID}},
+		"token-unused": {ObjectMeta: metav1.ObjectMeta{Name: "token-unused"}, Spec: ext.TokenSpec{UserID: userID}},
+	}
+	usage := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "token-used", Namespace: namespace}}
+	require.NoError(t, SetUsage(usage, &ext.TokenUsageStatus{
+		UserID:       userID,
+		FirstUsedAt:  &metav1.Time{Time: now},
+		LastUsedAt:   &metav1.Time{Time: now},
+		RequestCount: 3,
+	}))
+
+	tests := map[string]struct {
+		user      string
+		name      string
+		wantCount int64
+		wantErr   func(error) bool
+	}{
+		"owner": {
+			user:      userID,
+			name:      "token-used",
+			wantCount: 3,
+		},
+		"admin": {
+			user:      adminID,
+			name:      "token-used",
+			wantCount: 3,
+		},
+		"other user": {
+			user:    "u-bob",
+			name:    "token-used",
+			wantErr: apierrors.IsNotFound,
+		},
+		"no recorded usage": {
+			user: userID,
+			name: "token-unused",
+		},
+		"unknown token": {
+			user:    adminID,
+			name:    "token-unknown",
+			wantErr: apierrors.IsNotFound,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			configMapCache := fake.NewMockCacheInterface[*corev1.ConfigMap](ctrl)
+			configMapCache.EXPECT().Get(namespace, gomock.Any()).DoAndReturn(func(_, name string) (*corev1.ConfigMap, error) {
+				if name == usage.Name {
+					return usage, nil
+				}
+				return nil, apierrors.NewNotFound(schema.GroupResource{}, name)
+			}).AnyTimes()
+			store := &Store{
+				authorizer:     commonAuthorizer,
+				configMapCache: configMapCache,
+				tokens:         tokens,
+			}
+
+			ctx := request.WithUser(context.Background(), &k8suser.DefaultInfo{Name: tt.user})
+			obj, err := store.Get(ctx, tt.name, &metav1.GetOptions{})
+			if tt.wantErr != nil {
+				assert.True(t, tt.wantErr(err), "unexpected error %v", err)
+				return
+			}
+			require.NoError(t, err)
+			tokenUsage := obj.(*ext.TokenUsage)
+			assert.Equal(t, tt.name, tokenUsage.Name)
+			assert.Equal(t, userID, tokenUsage.Status.UserID)
+			assert.Equal(t, tt.wantCount, tokenUsage.Status.RequestCount)
+		})
+	}
+}
+
+func TestStoreList(t *testing.T) {
+	tests := map[string]struct {
+		user         string
+		wantSelector string
+	}{
+		"user": {
+			user:         userID,
+			wantSelector: "cattle.io/kind=token-usage,cattle.io/user-id=u-alice",
+		},
+		"admin": {
+			user:         adminID,
+			wantSelector: "cattle.io/kind=token-usage",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			configMaps := fake.NewMockControllerInterface[*corev1.ConfigMap, *corev1.ConfigMapList](ctrl)
+			store := &Store{
+				authorizer:      commonAuthorizer,
+				configMapClient: configMaps,
+			}
+
+			usage := corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "token-used", Namespace: namespace}}
+			require.NoError(t, SetUsage(&usage, &ext.TokenUsageStatus{UserID: userID, RequestCount: 3}))
+			configMaps.EXPECT().List(namespace, gomock.Any()).DoAndReturn(func(_ string, options metav1.ListOptions) (*corev1.ConfigMapList, error) {
+				assert.Equal(t, tt.wantSelector, options.LabelSelector)
+				return &corev1.ConfigMapList{Items: []corev1.ConfigMap{usage}}, nil
+			})
+
+			ctx := request.WithUser(context.Background(), &k8suser.DefaultInfo{Name: tt.user})
+			obj, err := store.List(ctx, &metainternalversion.ListOptions{})
+			require.NoError(t, err)
+			list := obj.(*ext.TokenUsageList)
+			require.Len(t, list.Items, 1)
+			assert.Equal(t, "token-used", list.Items[0].Name)
+			assert.Equal(t, int64(3), list.Items[0].Status.RequestCount)
+		})
+	}
+}
@@ -0,0 +1,355 @@
+package tokenusage
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	ext "github.com/rancher/rancher/pkg/apis/ext.cattle.io/v1"
+	exttokens "github.com/rancher/rancher/pkg/ext/stores/tokens"
+	"github.com/rancher/rancher/pkg/settings"
+	"github.com/rancher/rancher/pkg/wrangler"
+	v1 "github.com/rancher/wrangler/v3/pkg/generated/controllers/core/v1"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/retry"
+)
+
+const (
+	// KindLabelValue is the value of the kind label of the ConfigMaps holding the usage of tokens.
+	KindLabelValue = "token-usage"
+	// UsageField is the entry of the ConfigMap holding the usage of a token.
+	UsageField = "usage"
+
+	// DormantLabel is set on tokens that were not used for the number of days of the token-dormant-days setting.
+	DormantLabel = "cattle.io/token-dormant"
+	// UnseenNetworkLabel is set on tokens that were used from a previously unseen network.
+	UnseenNetworkLabel = "cattle.io/token-unseen-network"
+
+	namespace = exttokens.TokenNamespace
+
+	// Bounds of the usage recorded for a token.
+	maxDays            = 90
+	maxNetworks        = 20
+	maxUserAgents      = 20
+	maxClusters        = 50
+	maxUserAgentLength = 256
+
+	dateFormat = "2006-01-02"
+)
+
+// flushInterval is the interval at which recorded usage is saved.
+var flushInterval = 30 * time.Second
+
+// Merge adds the usage in delta to usage. Only the most recently seen entries are kept when there are more than the
+// bounds allow, and days which are older than the bound relative to the last usage are dropped.
+func Merge(usage, delta *ext.TokenUsageStatus) {
+	if delta.RequestCount == 0 {
+		return
+	}
+	if usage.UserID == "" {
+		usage.UserID = delta.UserID
+	}
+	if usage.FirstUsedAt == nil || delta.FirstUsedAt.Before(usage.FirstUsedAt) {
+		usage.FirstUsedAt = delta.FirstUsedAt.DeepCopy()
+	}
+	if usage.LastUsedAt == nil || usage.LastUsedAt.Before(delta.LastUsedAt) {
+		usage.LastUsedAt = delta.LastUsedAt.DeepCopy()
+	}
+	usage.RequestCount += delta.RequestCount
+	usage.Days = mergeDays(usage.Days, delta.Days, usage.LastUsedAt.UTC().AddDate(0, 0, -maxDays+1).Format(dateFormat))
+	usage.Networks = mergeEntries(usage.Networks, delta.Networks, maxNetworks)
+	usage.UserAgents = mergeEntries(usage.UserAgents, delta.UserAgents, maxUserAgents)
+	usage.Clusters = mergeEntries(usage.Clusters, delta.Clusters, maxClusters)
+}
+
+func mergeDays(days, delta []ext.TokenUsageDay, oldest string) []ext.TokenUsageDay {
+	counts := map[string]int64{}
+	for _, day := range append(days, delta...) {
+		counts[day.Date] += day.RequestCount
+	}
+	merged := make([]ext.TokenUsageDay, 0, len(counts))
+	for date, count := range counts {
+		if date >= oldest {
+			merged = append(merged, ext.TokenUsageDay{Date: date, RequestCount: count})
+		}
+	}
+	sort.Slice(merged, func(i, j int) bool { return merged[i].Date < merged[j].Date })
+	return merged
+}
+
+func mergeEntries(entries, delta []ext.TokenUsageEntry, limit int) []ext.TokenUsageEntry {
+	if len(delta) == 0 {
+		return entries
+	}
+	index := make(map[string]int, len(entries))
+	merged := make([]ext.TokenUsageEntry, 0, len(entries)+len(delta))
+	for _, entry := range append(entries, delta...) {
+		i, ok := index[entry.Value]
+		if !ok {
+			index[entry.Value] = len(merged)
+			merged = append(merged, *entry.DeepCopy())
+			continue
+		}
+		existing := &merged[i]
+		existing.RequestCount += entry.RequestCount
+		if entry.FirstUsedAt.Before(&existing.FirstUsedAt) {
+			existing.FirstUsedAt = entry.FirstUsedAt
+		}
+		if existing.LastUsedAt.Before(&entry.LastUsedAt) {
+			existing.LastUsedAt = entry.LastUsedAt
+		}
+		existing.Flagged = existing.Flagged || entry.Flagged
+	}
+	if len(merged) > limit {
+		sort.SliceStable(merged, func(i, j int) bool { return merged[j].LastUsedAt.Before(&merged[i].LastUsedAt) })
+		merged = merged[:limit]
+	}
+	sort.Slice(merged, func(i, j int) bool { return merged[i].Value < merged[j].Value })
+	return merged
+}
+
+// Network returns the network of an IP address used to aggregate usage, a /24 for IPv4 and a /64 for IPv6 addresses.
+func Network(ip net.IP) string {
+	if ip4 := ip.To4(); ip4 != nil {
+		return (&net.IPNet{IP: ip4.Mask(net.CIDRMask(24, 32)), Mask: net.CIDRMask(24, 32)}).String()
+	}
+	return (&net.IPNet{IP: ip.Mask(net.CIDRMask(64, 128)), Mask: net.CIDRMask(64, 128)}).String()
+}
+
+// parseTrustedProxies returns the CIDRs of the token-usage-trusted-proxies setting. Invalid entries are ignored.
+func parseTrustedProxies(value string) []*net.IPNet {
+	var proxies []*net.IPNet
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		_, cidr, err := net.ParseCIDR(entry)
+		if err != nil {
+			logrus.Debugf("[tokenusage] ignoring invalid trusted proxy CIDR %q: %v", entry, err)
+			continue
+		}
+		proxies = append(proxies, cidr)
+	}
+	return proxies
+}
+
+func trusted(ip net.IP, proxies []*net.IPNet) bool {
+	return slices.ContainsFunc(proxies, func(cidr *net.IPNet) bool { return cidr.Contains(ip) })
+}
+
+// clientIP returns the address of the client of a request. The X-Forwarded-For and X-Real-IP headers are only used
+// when the connection comes from a trusted proxy, so that a client can't pretend to be on a previously seen network.
+// X-Forwarded-For is walked from the right, skipping trusted proxies, as the entries on the left are client-supplied.
+func clientIP(req *http.Request, proxies []*net.IPNet) net.IP {
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		host = req.RemoteAddr
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || !trusted(ip, proxies) {
+		return ip
+	}
+
+	if forwardedFor := req.Header.Values("X-Forwarded-For"); len(forwardedFor) > 0 {
+		hops := strings.Split(strings.Join(forwardedFor, ","), ",")
+		for i := len(hops) - 1; i >= 0; i-- {
+			hop := net.ParseIP(strings.TrimSpace(hops[i]))
+			if hop == nil {
+				break
+			}
+			ip = hop
+			if !trusted(hop, proxies) {
+				break
+			}
+		}
+		return ip
+	}
+	if realIP := net.ParseIP(strings.TrimSpace(req.Header.Get("X-Real-IP"))); realIP != nil {
+		return realIP
+	}
+	return ip
+}
+
+// ParseUsage returns the usage held by a ConfigMap.
+func ParseUsage(configMap *corev1.ConfigMap) (*ext.TokenUsageStatus, error) {
+	usage := &ext.TokenUsageStatus{}
+	if data := configMap.Data[UsageField]; data != "" {
+		if err := json.Unmarshal([]byte(data), usage); err != nil {
+			return nil, fmt.Errorf("parsing usage of token %s: %w", configMap.Name, err)
+		}
+	}
+	return usage, nil
+}
+
+// SetUsage stores usage in a ConfigMap.
+func SetUsage(configMap *corev1.ConfigMap, usage *ext.TokenUsageStatus) error {
+	data, err := json.Marshal(usage)
+	if err != nil {
+		return err
+	}
+	if configMap.Data == nil {
+		configMap.Data = map[string]string{}
+	}
+	configMap.Data[UsageField] = string(data)
+	return nil
+}
+
+// Recorder aggregates the usage of ext tokens in memory and periodically merges it into the ConfigMaps holding the
+// usage of the tokens, so that authenticating a request doesn't write to the cluster.
+type Recorder struct {
+	configMaps  v1.ConfigMapClient
+	secretCache v1.SecretCache
+
+	mu      sync.Mutex
+	pending map[string]*ext.TokenUsageStatus
+}
+
+// NewRecorder creates a usage recorder from the provided wrangler context.
+func NewRecorder(wranglerContext *wrangler.Context) *Recorder {
+	return &Recorder{
+		configMaps:  wranglerContext.Core.ConfigMap(),
+		secretCache: wranglerContext.Core.Secret().Cache(),
+		pending:     map[string]*ext.TokenUsageStatus{},
+	}
+}
+
+// Start periodically saves the recorded usage until the context is done.
+func (r *Recorder) Start(done <-chan struct{}) {
+	go wait.Until(r.Flush, flushInterval, done)
+}
+
+// Record records a request authenticated with the ext token. The cluster name is empty for requests to Rancher.
+func (r *Recorder) Record(token *ext.Token, req *http.Request, clusterName string, now time.Time) {
+	at := metav1.NewTime(now.UTC().Truncate(time.Second))
+	entry := func(value string) []ext.TokenUsageEntry {
+		if value == "" {
+			return nil
+		}
+		return []ext.TokenUsageEntry{{Value: value, RequestCount: 1, FirstUsedAt: at, LastUsedAt: at}}
+	}
+
+	var network string
+	if ip := clientIP(req, parseTrustedProxies(settings.TokenUsageTrustedProxies.Get())); ip != nil {
+		network = Network(ip)
+	}
+	userAgent := req.UserAgent()
+	if len(userAgent) > maxUserAgentLength {
+		userAgent = userAgent[:maxUserAgentLength]
+	}
+
+	delta := &ext.TokenUsageStatus{
+		UserID:       token.GetUserID(),
+		FirstUsedAt:  &at,
+		LastUsedAt:   &at,
+		RequestCount: 1,
+		Days:         []ext.TokenUsageDay{{Date: at.Format(dateFormat), RequestCount: 1}},
+		Networks:     entry(network),
+		UserAgents:   entry(userAgent),
+		Clusters:     entry(clusterName),
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	usage, ok := r.pending[token.Name]
+	if !ok {
+		usage = &ext.TokenUsageStatus{}
+		r.pending[token.Name] = usage
+	}
+	Merge(usage, delta)
+}
+
+// Flush saves the recorded usage. Usage which fails to be saved is kept to be saved with the next flush, unless the
+// token no longer exists.
+func (r *Recorder) Flush() {
+	r.mu.Lock()
+	pending := r.pending
+	r.pending = map[string]*ext.TokenUsageStatus{}
+	r.mu.Unlock()
+
+	for name, delta := range pending {
+		err := r.save(name, delta)
+		if err == nil || apierrors.IsNotFound(err) {
+			continue
+		}
+		logrus.Errorf("Error saving usage of token %s: %v", name, err)
+		r.mu.Lock()
+		if usage, ok := r.pending[name]; ok {
+			Merge(usage, delta)
+		} else {
+			r.pending[name] = delta
+		}
+		r.mu.Unlock()
+	}
+}
+
+func (r *Recorder) save(name string, delta *ext.TokenUsageStatus) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		configMap, err := r.configMaps.Get(namespace, name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			return r.create(name, delta)
+		} else if err != nil {
+			return err
+		}
+
+		usage, err := ParseUsage(configMap)
+		if err != nil {
+			return err
+		}
+		Merge(usage, delta)
+		configMap = configMap.DeepCopy()
+		if err := SetUsage(configMap, usage); err != nil {
+			return err
+		}
+		_, err = r.configMaps.Update(configMap)
+		return err
+	})
+}
+
+// create creates the ConfigMap holding the usage of a token. It is owned by the backing Secret of the token so it is
+// removed with the token.
+func (r *Recorder) create(name string, usage *ext.TokenUsageStatus) error {
+	secret, err := r.secretCache.Get(namespace, name)
+	if err != nil {
+		return err
+	}
+	if secret.Labels[exttokens.SecretKindLabel] != exttokens.SecretKindLabelValue {
+		return apierrors.NewNotFound(exttokens.GVR.GroupResource(), name)
+	}
+
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels: map[string]string{
+				exttokens.SecretKindLabel: KindLabelValue,
+				exttokens.UserIDLabel:     secret.Labels[exttokens.UserIDLabel],
+			},
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: "v1",
+				Kind:       "Secret",
+				Name:       secret.Name,
+				UID:        secret.UID,
+			}},
+		},
+	}
+	if err := SetUsage(configMap, usage); err != nil {
+		return err
+	}
+	_, err = r.configMaps.Create(configMap)
+	if apierrors.IsAlreadyExists(err) {
+		// Created concurrently by another Rancher replica, retry merging into it.
+		return apierrors.NewConflict(corev1.Resource("configmaps"), name, err)
+	}
+	return err
+}
@@ -0,0 +1,265 @@
+package tokenusage
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	ext "github.com/rancher/rancher/pkg/apis/ext.cattle.io/v1"
+	exttokens "github.com/rancher/rancher/pkg/ext/stores/tokens"
+	"github.com/rancher/rancher/pkg/settings"
+	"github.com/rancher/wrangler/v3/pkg/generic/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestMerge(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) *metav1.Time { return &metav1.Time{Time: now.Add(d)} }
+
+	usage := &ext.TokenUsageStatus{
+		UserID:       "u-alice",
+		FirstUsedAt:  at(-100 * 24 * time.Hour),
+		LastUsedAt:   at(-time.Hour),
+		RequestCount: 10,
+		Days: []ext.TokenUsageDay{
+			{Date: "2025-11-21", RequestCount: 5},
+			{Date: "2026-03-01", RequestCount: 5},
+		},
+		Networks: []ext.TokenUsageEntry{
+			{Value: "10.0.0.0/24", RequestCount: 10, FirstUsedAt: *at(-100 * 24 * time.Hour), LastUsedAt: *at(-time.Hour), Flagged: true},
+		},
+	}
+	Merge(usage, &ext.TokenUsageStatus{
+		UserID:       "u-alice",
+		FirstUsedAt:  at(0),
+		LastUsedAt:   at(time.Minute),
+		RequestCount: 2,
+		Days:         []ext.TokenUsageDay{{Date: "2026-03-01", RequestCount: 2}},
+		Networks: []ext.TokenUsageEntry{
+			{Value: "10.0.0.0/24", RequestCount: 1, FirstUsedAt: *at(0), LastUsedAt: *at(0)},
+			{Value: "192.168.1.0/24", RequestCount: 1, FirstUsedAt: *at(time.Minute), LastUsedAt: *at(time.Minute)},
+		},
+		Clusters: []ext.TokenUsageEntry{{Value: "c-m-abc", RequestCount: 2, FirstUsedAt: *at(0), LastUsedAt: *at(time.Minute)}},
+	})
+
+	assert.Equal(t, int64(12), usage.RequestCount)
+	assert.True(t, now.Add(-100*24*time.Hour).Equal(usage.FirstUsedAt.Time))
+	assert.True(t, now.Add(time.Minute).Equal(usage.LastUsedAt.Time))
+	// Days older than the bound are dropped.
+	assert.Equal(t, []ext.TokenUsageDay{{Date: "2026-03-01", RequestCount: 7}}, usage.Days)
+	require.Len(t, usage.Networks, 2)
+	assert.Equal(t, "10.0.0.0/24", usage.Networks[0].Value)
+	assert.Equal(t, int64(11), usage.Networks[0].RequestCount)
+	assert.True(t, usage.Networks[0].Flagged)
+	assert.True(t, now.Equal(usage.Networks[0].LastUsedAt.Time))
+	assert.Equal(t, "192.168.1.0/24", usage.Networks[1].Value)
+	assert.False(t, usage.Networks[1].Flagged)
+	require.Len(t, usage.Clusters, 1)
+	assert.Empty(t, usage.UserAgents)
+}
+
+func TestMergeBounds(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	usage := &ext.TokenUsageStatus{}
+	for i := 0; i < maxUserAgents+5; i++ {
+		at := metav1.NewTime(now.Add(time.Duration(i) * time.Minute))
+		Merge(usage, &ext.TokenUsageStatus{
+			FirstUsedAt:  &at,
+			LastUsedAt:   &at,
+			RequestCount: 1,
+			UserAgents:   []ext.TokenUsageEntry{{Value: fmt.Sprintf("agent-%02d", i), RequestCount: 1, FirstUsedAt: at, LastUsedAt: at}},
+		})
+	}
+
+	// The least recently seen entries are dropped.
+	require.Len(t, usage.UserAgents, maxUserAgents)
+	assert.Equal(t, "agent-05", usage.UserAgents[0].Value)
+	assert.Equal(t, fmt.Sprintf("agent-%02d", maxUserAgents+4), usage.UserAgents[maxUserAgents-1].Value)
+}
+
+func TestNetwork(t *testing.T) {
+	assert.Equal(t, "10.1.2.0/24", Network(net.ParseIP("10.1.2.3")))
+	assert.Equal(t, "2001:db8:1:2::/64", Network(net.ParseIP("2001:db8:1:2:3:4:5:6")))
+}
+
+func TestClientIP(t *testing.T) {
+	proxies := parseTrustedProxies("10.42.0.0/16, fd00:42::/64, invalid")
+	require.Len(t, proxies, 2)
+
+	tests := map[string]struct {
+		remoteAddr string
+		headers    map[string]string
+		want       string
+	}{
+		"direct connection": {
+			remoteAddr: "192.168.1.10:54321",
+			want:       "192.168.1.10",
+		},
+		"headers of a direct connection are ignored": {
+			remoteAddr: "192.168.1.10:54321",
+			headers:    map[string]string{"X-Forwarded-For": "172.16.0.1", "X-Real-IP": "172.16.0.1"},
+			want:       "192.168.1.10",
+		},
+		"through a trusted proxy": {
+			remoteAddr: "10.42.0.5:54321",
+			headers:    map[string]string{"X-Forwarded-For": "192.168.1.10"},
+			want:       "192.168.1.10",
+		},
+		"through a chain of trusted proxies": {
+			remoteAddr: "10.42.0.5:54321",
+			headers:    map[string]string{"X-Forwarded-For": "192.168.1.10, 10.42.1.7"},
+			want:       "192.168.1.10",
+		},
+		"entries added by the client are ignored": {
+			remoteAddr: "10.42.0.5:54321",
+			headers:    map[string]string{"X-Forwarded-For": "172.16.0.1, 192.168.1.10"},
+			want:       "192.168.1.10",
+		},
+		"real IP set by a trusted proxy": {
+			remoteAddr: "10.42.0.5:54321",
+			headers:    map[string]string{"X-Real-IP": "192.168.1.10"},
+			want:       "192.168.1.10",
+		},
+		"trusted proxy without headers": {
+			remoteAddr: "10.42.0.5:54321",
+			want:       "10.42.0.5",
+		},
+		"IPv6 through a trusted proxy": {
+			remoteAddr: "[fd00:42::5]:54321",
+			headers:    map[string]string{"X-Forwarded-For": "2001:db8:1:2::10"},
+			want:       "2001:db8:1:2::10",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, "https://rancher.example.com/v3/clusters", nil)
+			require.NoError(t, err)
+			req.RemoteAddr = tt.remoteAddr
+			for key, value := range tt.headers {
+				req.Header.Set(key, value)
+			}
+			assert.Equal(t, tt.want, clientIP(req, proxies).String())
+		})
+	}
+}
+
+func TestRecorderBehindProxy(t *testing.T) {
+	trustedProxies := settings.TokenUsageTrustedProxies.Get()
+	require.NoError(t, settings.TokenUsageTrustedProxies.Set("10.42.0.0/16"))
+	t.Cleanup(func() { _ = settings.TokenUsageTrustedProxies.Set(trustedProxies) })
+
+	r := &Recorder{pending: map[string]*ext.TokenUsageStatus{}}
+	token := &ext.Token{
+		ObjectMeta: metav1.ObjectMeta{Name: "token-abc"},
+		Spec:       ext.TokenSpec{UserID: "u-alice"},
+	}
+	// Requests of clients on different networks reach Rancher through the same ingress pod.
+	for _, client := range []string{"192.168.1.10", "172.16.5.20"} {
+		req, err := http.NewRequest(http.MethodGet, "https://rancher.example.com/v3/clusters", nil)
+		require.NoError(t, err)
+		req.RemoteAddr = "10.42.0.5:54321"
+		req.Header.Set("X-Forwarded-For", client)
+		r.Record(token, req, "", time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC))
+	}
+
+	usage := r.pending["token-abc"]
+	require.NotNil(t, usage)
+	require.Len(t, usage.Networks, 2)
+	assert.Equal(t, "172.16.5.0/24", usage.Networks[0].Value)
+	assert.Equal(t, "192.168.1.0/24", usage.Networks[1].Value)
+}
+
+func TestRecorder(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	configMaps := fake.NewMockControllerInterface[*corev1.ConfigMap, *corev1.ConfigMapList](ctrl)
+	secretCache := fake.NewMockCacheInterface[*corev1.Secret](ctrl)
+	r := &Recorder{
+		configMaps:  configMaps,
+		secretCache: secretCache,
+		pending:     map[string]*ext.TokenUsageStatus{},
+	}
+
+	token := &ext.Token{
+		ObjectMeta: metav1.ObjectMeta{Name: "token-abc"},
+		Spec:       ext.TokenSpec{UserID: "u-alice"},
+	}
+	req, err := http.NewRequest(http.MethodGet, "https://rancher.example.com/v3/clusters", nil)
+	require.NoError(t, err)
+	req.RemoteAddr = "10.1.2.3:54321"
+	req.Header.Set("X-Forwarded-For", "192.168.1.10") // Client-supplied, so it must be ignored.
+	req.Header.Set("User-Agent", "kubectl/v1.34.0")
+
+	r.Record(token, req, "", now)
+	r.Record(token, req, "c-m-abc", now.Add(time.Second))
+
+	configMaps.EXPECT().Get(namespace, "token-abc", metav1.GetOptions{}).
+		Return(nil, apierrors.NewNotFound(schema.GroupResource{}, "token-abc"))
+	secretCache.EXPECT().Get(namespace, "token-abc").Return(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "token-abc",
+			Namespace: namespace,
+			UID:       types.UID("secret-uid"),
+			Labels: map[string]string{
+				exttokens.SecretKindLabel: exttokens.SecretKindLabelValue,
+				exttokens.UserIDLabel:     "u-alice",
+			},
+		},
+	}, nil)
+	configMaps.EXPECT().Create(gomock.Any()).DoAndReturn(func(configMap *corev1.ConfigMap) (*corev1.ConfigMap, error) {
+		assert.Equal(t, KindLabelValue, configMap.Labels[exttokens.SecretKindLabel])
+		assert.Equal(t, "u-alice", configMap.Labels[exttokens.UserIDLabel])
+		require.Len(t, configMap.OwnerReferences, 1)
+		assert.Equal(t, types.UID("secret-uid"), configMap.OwnerReferences[0].UID)
+
+		usage, err := ParseUsage(configMap)
+		require.NoError(t, err)
+		assert.Equal(t, "u-alice", usage.UserID)
+		assert.Equal(t, int64(2), usage.RequestCount)
+		assert.Equal(t, []ext.TokenUsageDay{{Date: "2026-03-01", RequestCount: 2}}, usage.Days)
+		require.Len(t, usage.Networks, 1)
+		assert.Equal(t, "10.1.2.0/24", usage.Networks[0].Value)
+		require.Len(t, usage.UserAgents, 1)
+		assert.Equal(t, "kubectl/v1.34.0", usage.UserAgents[0].Value)
+		require.Len(t, usage.Clusters, 1)
+		assert.Equal(t, "c-m-abc", usage.Clusters[0].Value)
+		return configMap, nil
+	})
+	r.Flush()
+	assert.Empty(t, r.pending)
+
+	// Usage is merged into the existing ConfigMap, and kept when it fails to be saved.
+	r.Record(token, req, "", now.Add(time.Hour))
+	existing := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "token-abc", Namespace: namespace}}
+	require.NoError(t, SetUsage(existing, &ext.TokenUsageStatus{
+		UserID:       "u-alice",
+		FirstUsedAt:  &metav1.Time{Time: now},
+		LastUsedAt:   &metav1.Time{Time: now},
+		RequestCount: 2,
+	}))
+	configMaps.EXPECT().Get(namespace, "token-abc", metav1.GetOptions{}).Return(existing, nil)
+	configMaps.EXPECT().Update(gomock.Any()).Return(nil, fmt.Errorf("unavailable"))
+	r.Flush()
+	require.Contains(t, r.pending, "token-abc")
+
+	configMaps.EXPECT().Get(namespace, "token-abc", metav1.GetOptions{}).Return(existing, nil)
+	configMaps.EXPECT().Update(gomock.Any()).DoAndReturn(func(configMap *corev1.ConfigMap) (*corev1.ConfigMap, error) {
+		usage, err := ParseUsage(configMap)
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), usage.RequestCount)
+		assert.True(t, now.Add(time.Hour).Equal(usage.LastUsedAt.Time))
+		return configMap, nil
+	})
+	r.Flush()
+	assert.Empty(t, r.pending)
+}
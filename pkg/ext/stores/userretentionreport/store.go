@@ -0,0 +1,169 @@
+// userretentionreport implements the store for the imperative userretentionreport resource.
+package userretentionreport
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	ext "github.com/rancher/rancher/pkg/apis/ext.cattle.io/v1"
+	"github.com/rancher/rancher/pkg/auth/userretention"
+	"github.com/rancher/rancher/pkg/controllers/status"
+	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/wrangler"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/apiserver/pkg/registry/rest"
+)
+
+const (
+	SingularName = "userretentionreport"
+	kind         = "UserRetentionReport"
+
+	reportedCondition = "Reported"
+)
+
+var (
+	_ rest.Creater                  = &Store{}
+	_ rest.Storage                  = &Store{}
+	_ rest.Scoper                   = &Store{}
+	_ rest.SingularNameProvider     = &Store{}
+	_ rest.GroupVersionKindProvider = &Store{}
+)
+
+var (
+	GVK = ext.SchemeGroupVersion.WithKind(kind)
+	gvr = ext.SchemeGroupVersion.WithResource(ext.UserRetentionReportResourceName)
+)
+
+// reporter abstracts the evaluation of the user retention process.
+type reporter interface {
+	Report(at time.Time) ([]userretention.ReportEntry, error)
+}
+
+// +k8s:openapi-gen=false
+// +k8s:deepcopy-gen=false
+
+type Store struct {
+	authorizer authorizer.Authorizer
+	retention  reporter
+	now        func() time.Time
+}
+
+// +k8s:openapi-gen=false
+// +k8s:deepcopy-gen=false
+
+func New(wranglerContext *wrangler.Context, authorizer authorizer.Authorizer) *Store {
+	return &Store{
+		authorizer: authorizer,
+		retention:  userretention.New(wranglerContext),
+		now:        time.Now,
+	}
+}
+
+// GroupVersionKind implements [rest.GroupVersionKindProvider], a required interface.
+func (s *Store) GroupVersionKind(_ schema.GroupVersion) schema.GroupVersionKind {
+	return GVK
+}
+
+// NamespaceScoped implements [rest.Scoper], a required interface.
+func (s *Store) NamespaceScoped() bool {
+	return false
+}
+
+// GetSingularName implements [rest.SingularNameProvider], a required interface.
+func (s *Store) GetSingularName() string {
+	return SingularName
+}
+
+// New implements [rest.Storage], a required interface.
+func (s *Store) New() runtime.Object {
+	return &ext.UserRetentionReport{}
+}
+
+// Destroy implements [rest.Storage], a required interface.
+func (s *Store) Destroy() {
+}
+
+// Create implements [rest.Creator], the interface to support the `create`
+// verb. Delegates to the actual store method after some generic boilerplate.
+func (s *Store) Create(
+	ctx context.Context,
+	obj runtime.Object,
+	createValidation rest.ValidateObjectFunc,
+	options *metav1.CreateOptions) (runtime.Object, error) {
+	if createValidation != nil {
+		err := createValidation(ctx, obj)
+		if err != nil {
+			return obj, err
+		}
+	}
+
+	objUserRetentionReport, ok := obj.(*ext.UserRetentionReport)
+	if !ok {
+		var zeroT *ext.UserRetentionReport
+		return nil, apierrors.NewInternalError(fmt.Errorf("expected %T but got %T",
+			zeroT, obj))
+	}
+
+	userInfo, ok := request.UserFrom(ctx)
+	if !ok {
+		return nil, apierrors.NewInternalError(fmt.Errorf("can't get user info from context"))
+	}
+	// Only users that can delete all users are allowed to see who the user retention process would affect.
+	decision, _, err := s.authorizer.Authorize(ctx, &authorizer.AttributesRecord{
+		User:            userInfo,
+		Verb:            "delete",
+		APIGroup:        v3.UserGroupVersionKind.Group,
+		APIVersion:      v3.Version,
+		Resource:        v3.UserResource.Name,
+		ResourceRequest: true,
+	})
+	if err != nil {
+		return nil, apierrors.NewInternalError(fmt.Errorf("error checking permissions %w", err))
+	}
+	if decision != authorizer.DecisionAllow {
+		return nil, apierrors.NewForbidden(gvr.GroupResource(), "", fmt.Errorf("not authorized to report on user retention"))
+	}
+
+	at := s.now()
+	if objUserRetentionReport.Spec.At != nil {
+		at = objUserRetentionReport.Spec.At.Time
+	}
+
+	condition := metav1.Condition{
+		LastTransitionTime: metav1.Now(),
+		Type:               reportedCondition,
+		Status:             metav1.ConditionTrue,
+	}
+	objUserRetentionReport.Status.Summary = status.SummaryCompleted
+
+	entries, err := s.retention.Report(at)
+	if err != nil {
+		condition.Status = metav1.ConditionFalse
+		condition.Message = err.Error()
+		objUserRetentionReport.Status.Summary = status.SummaryError
+	}
+
+	for _, entry := range entries {
+		reportEntry := ext.UserRetentionReportEntry{
+			UserID:   entry.User.Name,
+			Username: entry.User.Username,
+			Action:   string(entry.Action),
+			Policy:   entry.Policy,
+		}
+		if !entry.LastLogin.IsZero() {
+			lastLogin := metav1.NewTime(entry.LastLogin)
+			reportEntry.LastLogin = &lastLogin
+		}
+		objUserRetentionReport.Status.Users = append(objUserRetentionReport.Status.Users, reportEntry)
+	}
+
+	objUserRetentionReport.Status.Conditions = []metav1.Condition{condition}
+
+	return objUserRetentionReport, nil
+}
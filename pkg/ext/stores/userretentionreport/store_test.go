@@ -0,0 +1,148 @@
+package userretentionreport
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	ext "github.com/rancher/rancher/pkg/apis/ext.cattle.io/v1"
+	apiv3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/auth/userretention"
+	"github.com/rancher/rancher/pkg/controllers/status"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	"k8s.io/apiserver/pkg/endpoints/request"
+)
+
+const adminID = "user-admin"
+
+var commonAuthorizer = authorizer.AuthorizerFunc(func(ctx context.Context, a authorizer.Attributes) (authorizer.Decision, string, error) {
+	if a.GetUser().GetName() == adminID && a.GetVerb() == "delete" && a.GetResource() == "users" {
+		return authorizer.DecisionAllow, "", nil
+	}
+	return authorizer.DecisionDeny, "", nil
+})
+
+type fakeReporter struct {
+	at      time.Time
+	entries []userretention.ReportEntry
+	err     error
+}
+
+func (f *fakeReporter) Report(at time.Time) ([]userretention.ReportEntry, error) {
+	f.at = at
+	return f.entries, f.err
+}
+
+func TestCreate(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	lastLogin := now.Add(-20 * 24 * time.Hour)
+
+	tests := []struct {
+		name       string
+		userID     string
+		spec       ext.UserRetentionReportSpec
+		reporter   *fakeReporter
+		wantAt     time.Time
+		wantStatus ext.UserRetentionReportStatus
+		wantErr    func(error) bool
+	}{
+		{
+			name:   "report users",
+			userID: adminID,
+			reporter: &fakeReporter{entries: []userretention.ReportEntry{
+				{
+					User:      &apiv3.User{ObjectMeta: metav1.ObjectMeta{Name: "u-contractor"}, Username: "contractor"},
+					Action:    userretention.ActionDisable,
+					Policy:    "contractors",
+					LastLogin: lastLogin,
+				},
+				{
+					User:   &apiv3.User{ObjectMeta: metav1.ObjectMeta{Name: "u-new"}},
+					Action: userretention.ActionDelete,
+				},
+			}},
+			wantAt: now,
+			wantStatus: ext.UserRetentionReportStatus{
+				Users: []ext.UserRetentionReportEntry{
+					{
+						UserID:    "u-contractor",
+						Username:  "contractor",
+						Action:    "Disable",
+						Policy:    "contractors",
+						LastLogin: &metav1.Time{Time: lastLogin},
+					},
+					{
+						UserID: "u-new",
+						Action: "Delete",
+					},
+				},
+				Summary: status.SummaryCompleted,
+			},
+		},
+		{
+			name:     "report at a time",
+			userID:   adminID,
+			spec:     ext.UserRetentionReportSpec{At: &metav1.Time{Time: now.Add(7 * 24 * time.Hour)}},
+			reporter: &fakeReporter{},
+			wantAt:   now.Add(7 * 24 * time.Hour),
+			wantStatus: ext.UserRetentionReportStatus{
+				Summary: status.SummaryCompleted,
+			},
+		},
+		{
+			name:     "report failed",
+			userID:   adminID,
+			reporter: &fakeReporter{err: errors.New("error reading settings")},
+			wantAt:   now,
+			wantStatus: ext.UserRetentionReportStatus{
+				Summary: status.SummaryError,
+			},
+		},
+		{
+			name:     "not authorized",
+			userID:   "user-bob",
+			reporter: &fakeReporter{},
+			wantErr:  apierrors.IsForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &Store{
+				authorizer: commonAuthorizer,
+				retention:  tt.reporter,
+				now:        func() time.Time { return now },
+			}
+
+			ctx := request.WithUser(context.Background(), &user.DefaultInfo{Name: tt.userID})
+			obj, err := store.Create(ctx, &ext.UserRetentionReport{Spec: tt.spec}, nil, &metav1.CreateOptions{})
+			if tt.wantErr != nil {
+				require.Error(t, err)
+				assert.True(t, tt.wantErr(err))
+				return
+			}
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.wantAt, tt.reporter.at)
+
+			report := obj.(*ext.UserRetentionReport)
+			require.Len(t, report.Status.Conditions, 1)
+			assert.Equal(t, reportedCondition, report.Status.Conditions[0].Type)
+			if tt.reporter.err != nil {
+				assert.Equal(t, metav1.ConditionFalse, report.Status.Conditions[0].Status)
+				assert.Equal(t, tt.reporter.err.Error(), report.Status.Conditions[0].Message)
+			} else {
+				assert.Equal(t, metav1.ConditionTrue, report.Status.Conditions[0].Status)
+			}
+
+			report.Status.Conditions = nil
+			assert.Equal(t, tt.wantStatus, report.Status)
+		})
+	}
+}
@@ -228,6 +228,13 @@
 		true,
 		true,
 	)
+	BreakGlassCredentials = newFeature(
+		"break-glass-credentials",
+		"Enable issuing break-glass credentials for the Authorized Cluster Endpoint. Requires a downstream authenticator that verifies them.",
+		false,
+		false,
+		true,
+	)
 )
 
 func ListEnabled() []string {
@@ -40,6 +40,14 @@ func (c *FakeOperationV1alpha1) EncryptionKeyRotations(namespace string) v1alpha
 	return newFakeEncryptionKeyRotations(c, namespace)
 }
 
+func (c *FakeOperationV1alpha1) NodeDiagnostics(namespace string) v1alpha1.NodeDiagnosticInterface {
+	return newFakeNodeDiagnostics(c, namespace)
+}
+
+func (c *FakeOperationV1alpha1) SupportBundles(namespace string) v1alpha1.SupportBundleInterface {
+	return newFakeSupportBundles(c, namespace)
+}
+
 // RESTClient returns a RESTClient that is used to communicate
 // with API server by this client implementation.
 func (c *FakeOperationV1alpha1) RESTClient() rest.Interface {
@@ -23,3 +23,7 @@ type ETCDSnapshotRestoreExpansion interface{
 type ETCDSnapshotSaveExpansion interface{}
 
 type EncryptionKeyRotationExpansion interface{}
+
+type NodeDiagnosticExpansion interface{}
+
+type SupportBundleExpansion interface{}
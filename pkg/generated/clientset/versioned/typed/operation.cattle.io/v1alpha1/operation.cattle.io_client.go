@@ -31,6 +31,8 @@ type OperationV1alpha1Interface interface {
 	ETCDSnapshotRestoresGetter
 	ETCDSnapshotSavesGetter
 	EncryptionKeyRotationsGetter
+	NodeDiagnosticsGetter
+	SupportBundlesGetter
 }
 
 // OperationV1alpha1Client is used to interact with features provided by the operation.cattle.io group.
@@ -50,6 +52,14 @@ func (c *OperationV1alpha1Client) EncryptionKeyRotations(namespace string) Encry
 	return newEncryptionKeyRotations(c, namespace)
 }
 
+func (c *OperationV1alpha1Client) NodeDiagnostics(namespace string) NodeDiagnosticInterface {
+	return newNodeDiagnostics(c, namespace)
+}
+
+func (c *OperationV1alpha1Client) SupportBundles(namespace string) SupportBundleInterface {
+	return newSupportBundles(c, namespace)
+}
+
 // NewForConfig creates a new OperationV1alpha1Client for the given config.
 // NewForConfig is equivalent to NewForConfigAndClient(c, httpClient),
 // where httpClient was generated with rest.HTTPClientFor(c).
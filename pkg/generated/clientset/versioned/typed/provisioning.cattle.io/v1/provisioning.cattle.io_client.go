@@ -29,6 +29,7 @@
 type ProvisioningV1Interface interface {
 	RESTClient() rest.Interface
 	ClustersGetter
+	ClusterTemplatesGetter
 }
 
 // ProvisioningV1Client is used to interact with features provided by the provisioning.cattle.io group.
@@ -40,6 +41,10 @@ func (c *ProvisioningV1Client) Clusters(namespace string) ClusterInterface {
 	return newClusters(c, namespace)
 }
 
+func (c *ProvisioningV1Client) ClusterTemplates(namespace string) ClusterTemplateInterface {
+	return newClusterTemplates(c, namespace)
+}
+
 // NewForConfig creates a new ProvisioningV1Client for the given config.
 // NewForConfig is equivalent to NewForConfigAndClient(c, httpClient),
 // where httpClient was generated with rest.HTTPClientFor(c).
@@ -33,10 +33,14 @@ func init() {
 type Interface interface {
 	GroupMembershipRefreshRequest() GroupMembershipRefreshRequestController
 	Kubeconfig() KubeconfigController
+	KubeconfigTokenRequest() KubeconfigTokenRequestController
 	PasswordChangeRequest() PasswordChangeRequestController
 	SelfUser() SelfUserController
 	Token() TokenController
+	TokenRevocationRequest() TokenRevocationRequestController
+	TokenUsage() TokenUsageController
 	UserActivity() UserActivityController
+	UserRetentionReport() UserRetentionReportController
 }
 
 func New(controllerFactory controller.SharedControllerFactory) Interface {
@@ -57,6 +61,10 @@ func (v *version) Kubeconfig() KubeconfigController {
 	return generic.NewNonNamespacedController[*v1.Kubeconfig, *v1.KubeconfigList](schema.GroupVersionKind{Group: "ext.cattle.io", Version: "v1", Kind: "Kubeconfig"}, "kubeconfigs", v.controllerFactory)
 }
 
+func (v *version) KubeconfigTokenRequest() KubeconfigTokenRequestController {
+	return generic.NewNonNamespacedController[*v1.KubeconfigTokenRequest, *v1.KubeconfigTokenRequestList](schema.GroupVersionKind{Group: "ext.cattle.io", Version: "v1", Kind: "KubeconfigTokenRequest"}, "kubeconfigtokenrequests", v.controllerFactory)
+}
+
 func (v *version) PasswordChangeRequest() PasswordChangeRequestController {
 	return generic.NewNonNamespacedController[*v1.PasswordChangeRequest, *v1.PasswordChangeRequestList](schema.GroupVersionKind{Group: "ext.cattle.io", Version: "v1", Kind: "PasswordChangeRequest"}, "passwordchangerequests", v.controllerFactory)
 }
@@ -69,6 +77,18 @@ func (v *version) Token() TokenController {
 	return generic.NewNonNamespacedController[*v1.Token, *v1.TokenList](schema.GroupVersionKind{Group: "ext.cattle.io", Version: "v1", Kind: "Token"}, "tokens", v.controllerFactory)
 }
 
+func (v *version) TokenRevocationRequest() TokenRevocationRequestController {
+	return generic.NewNonNamespacedController[*v1.TokenRevocationRequest, *v1.TokenRevocationRequestList](schema.GroupVersionKind{Group: "ext.cattle.io", Version: "v1", Kind: "TokenRevocationRequest"}, "tokenrevocationrequests", v.controllerFactory)
+}
+
+func (v *version) TokenUsage() TokenUsageController {
+	return generic.NewNonNamespacedController[*v1.TokenUsage, *v1.TokenUsageList](schema.GroupVersionKind{Group: "ext.cattle.io", Version: "v1", Kind: "TokenUsage"}, "tokenusages", v.controllerFactory)
+}
+
 func (v *version) UserActivity() UserActivityController {
 	return generic.NewNonNamespacedController[*v1.UserActivity, *v1.UserActivityList](schema.GroupVersionKind{Group: "ext.cattle.io", Version: "v1", Kind: "UserActivity"}, "useractivities", v.controllerFactory)
 }
+
+func (v *version) UserRetentionReport() UserRetentionReportController {
+	return generic.NewNonNamespacedController[*v1.UserRetentionReport, *v1.UserRetentionReportList](schema.GroupVersionKind{Group: "ext.cattle.io", Version: "v1", Kind: "UserRetentionReport"}, "userretentionreports", v.controllerFactory)
+}
@@ -37,6 +37,8 @@ type Interface interface {
 	AuthProvider() AuthProviderController
 	AuthToken() AuthTokenController
 	AzureADProvider() AzureADProviderController
+	BreakGlassCredential() BreakGlassCredentialController
+	CertificateAuthorityRotation() CertificateAuthorityRotationController
 	CloudCredential() CloudCredentialController
 	Cluster() ClusterController
 	ClusterProxyConfig() ClusterProxyConfigController
@@ -59,6 +61,8 @@ type Interface interface {
 	KontainerDriver() KontainerDriverController
 	LocalProvider() LocalProviderController
 	ManagedChart() ManagedChartController
+	ManagementBackup() ManagementBackupController
+	ManagementRestore() ManagementRestoreController
 	Node() NodeController
 	NodeDriver() NodeDriverController
 	OIDCClient() OIDCClientController
@@ -70,6 +74,7 @@ type Interface interface {
 	Project() ProjectController
 	ProjectNetworkPolicy() ProjectNetworkPolicyController
 	ProjectRoleTemplateBinding() ProjectRoleTemplateBindingController
+	ProvisioningEvent() ProvisioningEventController
 	ProxyEndpoint() ProxyEndpointController
 	RancherUserNotification() RancherUserNotificationController
 	RoleTemplate() RoleTemplateController
@@ -79,6 +84,8 @@ type Interface interface {
 	Token() TokenController
 	User() UserController
 	UserAttribute() UserAttributeController
+	UserRetentionPolicy() UserRetentionPolicyController
+	WorkloadIdentityBinding() WorkloadIdentityBindingController
 }
 
 func New(controllerFactory controller.SharedControllerFactory) Interface {
@@ -115,6 +122,14 @@ func (v *version) AzureADProvider() AzureADProviderController {
 	return generic.NewNonNamespacedController[*v3.AzureADProvider, *v3.AzureADProviderList](schema.GroupVersionKind{Group: "management.cattle.io", Version: "v3", Kind: "AzureADProvider"}, "azureadproviders", v.controllerFactory)
 }
 
+func (v *version) BreakGlassCredential() BreakGlassCredentialController {
+	return generic.NewNonNamespacedController[*v3.BreakGlassCredential, *v3.BreakGlassCredentialList](schema.GroupVersionKind{Group: "management.cattle.io", Version: "v3", Kind: "BreakGlassCredential"}, "breakglasscredentials", v.controllerFactory)
+}
+
+func (v *version) CertificateAuthorityRotation() CertificateAuthorityRotationController {
+	return generic.NewNonNamespacedController[*v3.CertificateAuthorityRotation, *v3.CertificateAuthorityRotationList](schema.GroupVersionKind{Group: "management.cattle.io", Version: "v3", Kind: "CertificateAuthorityRotation"}, "certificateauthorityrotations", v.controllerFactory)
+}
+
 func (v *version) CloudCredential() CloudCredentialController {
 	return generic.NewController[*v3.CloudCredential, *v3.CloudCredentialList](schema.GroupVersionKind{Group: "management.cattle.io", Version: "v3", Kind: "CloudCredential"}, "cloudcredentials", true, v.controllerFactory)
 }
@@ -203,6 +218,14 @@ func (v *version) ManagedChart() ManagedChartController {
 	return generic.NewController[*v3.ManagedChart, *v3.ManagedChartList](schema.GroupVersionKind{Group: "management.cattle.io", Version: "v3", Kind: "ManagedChart"}, "managedcharts", true, v.controllerFactory)
 }
 
+func (v *version) ManagementBackup() ManagementBackupController {
+	return generic.NewNonNamespacedController[*v3.ManagementBackup, *v3.ManagementBackupList](schema.GroupVersionKind{Group: "management.cattle.io", Version: "v3", Kind: "ManagementBackup"}, "managementbackups", v.controllerFactory)
+}
+
+func (v *version) ManagementRestore() ManagementRestoreController {
+	return generic.NewNonNamespacedController[*v3.ManagementRestore, *v3.ManagementRestoreList](schema.GroupVersionKind{Group: "management.cattle.io", Version: "v3", Kind: "ManagementRestore"}, "managementrestores", v.controllerFactory)
+}
+
 func (v *version) Node() NodeController {
 	return generic.NewController[*v3.Node, *v3.NodeList](schema.GroupVersionKind{Group: "management.cattle.io", Version: "v3", Kind: "Node"}, "nodes", true, v.controllerFactory)
 }
@@ -247,6 +270,10 @@ func (v *version) ProjectRoleTemplateBinding() ProjectRoleTemplateBindingControl
 	return generic.NewController[*v3.ProjectRoleTemplateBinding, *v3.ProjectRoleTemplateBindingList](schema.GroupVersionKind{Group: "management.cattle.io", Version: "v3", Kind: "ProjectRoleTemplateBinding"}, "projectroletemplatebindings", true, v.controllerFactory)
 }
 
+func (v *version) ProvisioningEvent() ProvisioningEventController {
+	return generic.NewController[*v3.ProvisioningEvent, *v3.ProvisioningEventList](schema.GroupVersionKind{Group: "management.cattle.io", Version: "v3", Kind: "ProvisioningEvent"}, "provisioningevents", true, v.controllerFactory)
+}
+
 func (v *version) ProxyEndpoint() ProxyEndpointController {
 	return generic.NewNonNamespacedController[*v3.ProxyEndpoint, *v3.ProxyEndpointList](schema.GroupVersionKind{Group: "management.cattle.io", Version: "v3", Kind: "ProxyEndpoint"}, "proxyendpoints", v.controllerFactory)
 }
@@ -282,3 +309,11 @@ func (v *version) User() UserController {
 func (v *version) UserAttribute() UserAttributeController {
 	return generic.NewNonNamespacedController[*v3.UserAttribute, *v3.UserAttributeList](schema.GroupVersionKind{Group: "management.cattle.io", Version: "v3", Kind: "UserAttribute"}, "userattributes", v.controllerFactory)
 }
+
+func (v *version) UserRetentionPolicy() UserRetentionPolicyController {
+	return generic.NewNonNamespacedController[*v3.UserRetentionPolicy, *v3.UserRetentionPolicyList](schema.GroupVersionKind{Group: "management.cattle.io", Version: "v3", Kind: "UserRetentionPolicy"}, "userretentionpolicies", v.controllerFactory)
+}
+
+func (v *version) WorkloadIdentityBinding() WorkloadIdentityBindingController {
+	return generic.NewNonNamespacedController[*v3.WorkloadIdentityBinding, *v3.WorkloadIdentityBindingList](schema.GroupVersionKind{Group: "management.cattle.io", Version: "v3", Kind: "WorkloadIdentityBinding"}, "workloadidentitybindings", v.controllerFactory)
+}
@@ -34,6 +34,8 @@ type Interface interface {
 	ETCDSnapshotRestore() ETCDSnapshotRestoreController
 	ETCDSnapshotSave() ETCDSnapshotSaveController
 	EncryptionKeyRotation() EncryptionKeyRotationController
+	NodeDiagnostic() NodeDiagnosticController
+	SupportBundle() SupportBundleController
 }
 
 func New(controllerFactory controller.SharedControllerFactory) Interface {
@@ -57,3 +59,11 @@ func (v *version) ETCDSnapshotSave() ETCDSnapshotSaveController {
 func (v *version) EncryptionKeyRotation() EncryptionKeyRotationController {
 	return generic.NewController[*v1alpha1.EncryptionKeyRotation, *v1alpha1.EncryptionKeyRotationList](schema.GroupVersionKind{Group: "operation.cattle.io", Version: "v1alpha1", Kind: "EncryptionKeyRotation"}, "encryptionkeyrotations", true, v.controllerFactory)
 }
+
+func (v *version) NodeDiagnostic() NodeDiagnosticController {
+	return generic.NewController[*v1alpha1.NodeDiagnostic, *v1alpha1.NodeDiagnosticList](schema.GroupVersionKind{Group: "operation.cattle.io", Version: "v1alpha1", Kind: "NodeDiagnostic"}, "nodediagnostics", true, v.controllerFactory)
+}
+
+func (v *version) SupportBundle() SupportBundleController {
+	return generic.NewController[*v1alpha1.SupportBundle, *v1alpha1.SupportBundleList](schema.GroupVersionKind{Group: "operation.cattle.io", Version: "v1alpha1", Kind: "SupportBundle"}, "supportbundles", true, v.controllerFactory)
+}
@@ -32,6 +32,7 @@ func init() {
 
 type Interface interface {
 	Cluster() ClusterController
+	ClusterTemplate() ClusterTemplateController
 }
 
 func New(controllerFactory controller.SharedControllerFactory) Interface {
@@ -47,3 +48,7 @@ type version struct {
 func (v *version) Cluster() ClusterController {
 	return generic.NewController[*v1.Cluster, *v1.ClusterList](schema.GroupVersionKind{Group: "provisioning.cattle.io", Version: "v1", Kind: "Cluster"}, "clusters", true, v.controllerFactory)
 }
+
+func (v *version) ClusterTemplate() ClusterTemplateController {
+	return generic.NewController[*v1.ClusterTemplate, *v1.ClusterTemplateList](schema.GroupVersionKind{Group: "provisioning.cattle.io", Version: "v1", Kind: "ClusterTemplate"}, "clustertemplates", true, v.controllerFactory)
+}
@@ -40,6 +40,10 @@ func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenA
 		v1.KubeconfigList{}.OpenAPIModelName():                                           schema_pkg_apis_extcattleio_v1_KubeconfigList(ref),
 		v1.KubeconfigSpec{}.OpenAPIModelName():                                           schema_pkg_apis_extcattleio_v1_KubeconfigSpec(ref),
 		v1.KubeconfigStatus{}.OpenAPIModelName():                                         schema_pkg_apis_extcattleio_v1_KubeconfigStatus(ref),
+		v1.KubeconfigTokenRequest{}.OpenAPIModelName():                                   schema_pkg_apis_extcattleio_v1_KubeconfigTokenRequest(ref),
+		v1.KubeconfigTokenRequestList{}.OpenAPIModelName():                               schema_pkg_apis_extcattleio_v1_KubeconfigTokenRequestList(ref),
+		v1.KubeconfigTokenRequestSpec{}.OpenAPIModelName():                               schema_pkg_apis_extcattleio_v1_KubeconfigTokenRequestSpec(ref),
+		v1.KubeconfigTokenRequestStatus{}.OpenAPIModelName():                             schema_pkg_apis_extcattleio_v1_KubeconfigTokenRequestStatus(ref),
 		v1.PasswordChangeRequest{}.OpenAPIModelName():                                    schema_pkg_apis_extcattleio_v1_PasswordChangeRequest(ref),
 		v1.PasswordChangeRequestList{}.OpenAPIModelName():                                schema_pkg_apis_extcattleio_v1_PasswordChangeRequestList(ref),
 		v1.PasswordChangeRequestSpec{}.OpenAPIModelName():                                schema_pkg_apis_extcattleio_v1_PasswordChangeRequestSpec(ref),
@@ -50,12 +54,26 @@ func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenA
 		v1.Token{}.OpenAPIModelName():                                                    schema_pkg_apis_extcattleio_v1_Token(ref),
 		v1.TokenList{}.OpenAPIModelName():                                                schema_pkg_apis_extcattleio_v1_TokenList(ref),
 		v1.TokenPrincipal{}.OpenAPIModelName():                                           schema_pkg_apis_extcattleio_v1_TokenPrincipal(ref),
+		v1.TokenRevocationRequest{}.OpenAPIModelName():                                   schema_pkg_apis_extcattleio_v1_TokenRevocationRequest(ref),
+		v1.TokenRevocationRequestList{}.OpenAPIModelName():                               schema_pkg_apis_extcattleio_v1_TokenRevocationRequestList(ref),
+		v1.TokenRevocationRequestSpec{}.OpenAPIModelName():                               schema_pkg_apis_extcattleio_v1_TokenRevocationRequestSpec(ref),
+		v1.TokenRevocationRequestStatus{}.OpenAPIModelName():                             schema_pkg_apis_extcattleio_v1_TokenRevocationRequestStatus(ref),
 		v1.TokenSpec{}.OpenAPIModelName():                                                schema_pkg_apis_extcattleio_v1_TokenSpec(ref),
 		v1.TokenStatus{}.OpenAPIModelName():                                              schema_pkg_apis_extcattleio_v1_TokenStatus(ref),
+		v1.TokenUsage{}.OpenAPIModelName():                                               schema_pkg_apis_extcattleio_v1_TokenUsage(ref),
+		v1.TokenUsageDay{}.OpenAPIModelName():                                            schema_pkg_apis_extcattleio_v1_TokenUsageDay(ref),
+		v1.TokenUsageEntry{}.OpenAPIModelName():                                          schema_pkg_apis_extcattleio_v1_TokenUsageEntry(ref),
+		v1.TokenUsageList{}.OpenAPIModelName():                                           schema_pkg_apis_extcattleio_v1_TokenUsageList(ref),
+		v1.TokenUsageStatus{}.OpenAPIModelName():                                         schema_pkg_apis_extcattleio_v1_TokenUsageStatus(ref),
 		v1.UserActivity{}.OpenAPIModelName():                                             schema_pkg_apis_extcattleio_v1_UserActivity(ref),
 		v1.UserActivityList{}.OpenAPIModelName():                                         schema_pkg_apis_extcattleio_v1_UserActivityList(ref),
 		v1.UserActivitySpec{}.OpenAPIModelName():                                         schema_pkg_apis_extcattleio_v1_UserActivitySpec(ref),
 		v1.UserActivityStatus{}.OpenAPIModelName():                                       schema_pkg_apis_extcattleio_v1_UserActivityStatus(ref),
+		v1.UserRetentionReport{}.OpenAPIModelName():                                      schema_pkg_apis_extcattleio_v1_UserRetentionReport(ref),
+		v1.UserRetentionReportEntry{}.OpenAPIModelName():                                 schema_pkg_apis_extcattleio_v1_UserRetentionReportEntry(ref),
+		v1.UserRetentionReportList{}.OpenAPIModelName():                                  schema_pkg_apis_extcattleio_v1_UserRetentionReportList(ref),
+		v1.UserRetentionReportSpec{}.OpenAPIModelName():                                  schema_pkg_apis_extcattleio_v1_UserRetentionReportSpec(ref),
+		v1.UserRetentionReportStatus{}.OpenAPIModelName():                                schema_pkg_apis_extcattleio_v1_UserRetentionReportStatus(ref),
 		"github.com/rancher/rancher/pkg/apis/telemetry.cattle.io/v1.SecretRequest":       schema_pkg_apis_telemetrycattleio_v1_SecretRequest(ref),
 		"github.com/rancher/rancher/pkg/apis/telemetry.cattle.io/v1.SecretRequestList":   schema_pkg_apis_telemetrycattleio_v1_SecretRequestList(ref),
 		"github.com/rancher/rancher/pkg/apis/telemetry.cattle.io/v1.SecretRequestSpec":   schema_pkg_apis_telemetrycattleio_v1_SecretRequestSpec(ref),
@@ -423,6 +441,13 @@ func schema_pkg_apis_extcattleio_v1_KubeconfigSpec(ref common.ReferenceCallback)
 							Format:      "",
 						},
 					},
+					"mode": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Mode controls how the generated kubeconfig authenticates. Can be \"Token\" or \"Exec\". In the default \"Token\" mode, bearer tokens are embedded in the kubeconfig. In the \"Exec\" mode, no tokens are created. Instead, the kubeconfig runs the rancher-kubeconfig-helper credential plugin, which requests short-lived tokens with KubeconfigTokenRequest as needed, and TTL limits the time during which such tokens can be requested.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 				},
 			},
 		},
@@ -500,6 +525,159 @@ func schema_pkg_apis_extcattleio_v1_KubeconfigStatus(ref common.ReferenceCallbac
 	}
 }
 
+func schema_pkg_apis_extcattleio_v1_KubeconfigTokenRequest(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "KubeconfigTokenRequest is used to obtain a short-lived token for a Kubeconfig in the \"Exec\" mode.\n\nCreate must be called by the owner of the Kubeconfig, authenticated with a Rancher token that is not itself a kubeconfig token. The token is only returned in the Create response.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Standard object metadata; More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#metadata.",
+							Default:     map[string]interface{}{},
+							Ref:         ref(metav1.ObjectMeta{}.OpenAPIModelName()),
+						},
+					},
+					"spec": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Spec is the desired state of the KubeconfigTokenRequest.",
+							Default:     map[string]interface{}{},
+							Ref:         ref(v1.KubeconfigTokenRequestSpec{}.OpenAPIModelName()),
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Status is the most recently observed status of the KubeconfigTokenRequest.",
+							Default:     map[string]interface{}{},
+							Ref:         ref(v1.KubeconfigTokenRequestStatus{}.OpenAPIModelName()),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			v1.KubeconfigTokenRequestSpec{}.OpenAPIModelName(), v1.KubeconfigTokenRequestStatus{}.OpenAPIModelName(), metav1.ObjectMeta{}.OpenAPIModelName()},
+	}
+}
+
+func schema_pkg_apis_extcattleio_v1_KubeconfigTokenRequestList(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "KubeconfigTokenRequestList is a list of KubeconfigTokenRequest resources",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref(metav1.ListMeta{}.OpenAPIModelName()),
+						},
+					},
+					"items": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref(v1.KubeconfigTokenRequest{}.OpenAPIModelName()),
+									},
+								},
+							},
+						},
+					},
+				},
+				Required: []string{"metadata", "items"},
+			},
+		},
+		Dependencies: []string{
+			v1.KubeconfigTokenRequest{}.OpenAPIModelName(), metav1.ListMeta{}.OpenAPIModelName()},
+	}
+}
+
+func schema_pkg_apis_extcattleio_v1_KubeconfigTokenRequestSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "KubeconfigTokenRequestSpec contains the data about the kubeconfig token request.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kubeconfigName": {
+						SchemaProps: spec.SchemaProps{
+							Description: "KubeconfigName is the name of the Kubeconfig the token is requested for.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"clusterName": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ClusterName is the name of the cluster the token is scoped to. It is only set for clusters with the authorized cluster endpoint enabled.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_extcattleio_v1_KubeconfigTokenRequestStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "KubeconfigTokenRequestStatus contains the requested token.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"token": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Token is the bearer token.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"expirationTimestamp": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ExpirationTimestamp is the time the token expires at.",
+							Ref:         ref(metav1.Time{}.OpenAPIModelName()),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			metav1.Time{}.OpenAPIModelName()},
+	}
+}
+
 func schema_pkg_apis_extcattleio_v1_PasswordChangeRequest(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -970,147 +1148,159 @@ func schema_pkg_apis_extcattleio_v1_TokenPrincipal(ref common.ReferenceCallback)
 	}
 }
 
-func schema_pkg_apis_extcattleio_v1_TokenSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_extcattleio_v1_TokenRevocationRequest(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "TokenSpec defines the desired state of the Token.",
+				Description: "TokenRevocationRequest is used to revoke all the tokens, both ext and v3 Tokens, matching the selectors of the request. All the selectors must match for a token to be revoked, and at least one selector must be set.",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"userID": {
-						SchemaProps: spec.SchemaProps{
-							Description: "UserID is the kube resource id of the user owning the token. By default that is the user who owned the token making the request creating this token. Currently this default is enforced, i.e. using a different user is rejected as forbidden.",
-							Type:        []string{"string"},
-							Format:      "",
-						},
-					},
-					"userPrincipal": {
-						SchemaProps: spec.SchemaProps{
-							Description: "UserPrincipal holds the information about the ext auth provider managed user (principal) owning the token.",
-							Default:     map[string]interface{}{},
-							Ref:         ref(v1.TokenPrincipal{}.OpenAPIModelName()),
-						},
-					},
 					"kind": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Kind describes the nature of the token. The value \"session\" indicates a login/session token. Any other value, including the empty string, which is the default, stands for some kind of derived token.",
-							Default:     "",
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"description": {
+					"apiVersion": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Human readable free-form description of the token. For example, its purpose.",
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"ttl": {
+					"metadata": {
 						SchemaProps: spec.SchemaProps{
-							Description: "TTL is the time-to-live of the token, in milliseconds. Setting a value < 0 represents +infinity, i.e. a token which does not expire. The default is indicated by the value `0`. This default is provided by the `auth-token-default-ttl-minutes` setting. A value < 0 there enables non-expiring tokens, if not disallowed by the max. That is, both the ttl here and the default are subject to the `auth-token-max-ttl-minutes` setting, i.e. will be clamped to it if they represent a larger value than that.",
-							Default:     0,
-							Type:        []string{"integer"},
-							Format:      "int64",
+							Description: "Standard object metadata; More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#metadata.",
+							Default:     map[string]interface{}{},
+							Ref:         ref(metav1.ObjectMeta{}.OpenAPIModelName()),
 						},
 					},
-					"enabled": {
+					"spec": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Enabled indicates an active token. The default (`null`) indicates an enabled token.",
-							Type:        []string{"boolean"},
-							Format:      "",
+							Description: "Spec is the desired state of the TokenRevocationRequest.",
+							Default:     map[string]interface{}{},
+							Ref:         ref(v1.TokenRevocationRequestSpec{}.OpenAPIModelName()),
 						},
 					},
-					"clusterName": {
+					"status": {
 						SchemaProps: spec.SchemaProps{
-							Description: "ClusterName holds the name of the cluster the token is scoped to, if any. An empty string indicates that the token is not scoped to a specific cluster.",
-							Type:        []string{"string"},
-							Format:      "",
+							Description: "Status is the most recently observed status of the TokenRevocationRequest.",
+							Default:     map[string]interface{}{},
+							Ref:         ref(v1.TokenRevocationRequestStatus{}.OpenAPIModelName()),
 						},
 					},
 				},
-				Required: []string{"userPrincipal"},
 			},
 		},
 		Dependencies: []string{
-			v1.TokenPrincipal{}.OpenAPIModelName()},
+			v1.TokenRevocationRequestSpec{}.OpenAPIModelName(), v1.TokenRevocationRequestStatus{}.OpenAPIModelName(), metav1.ObjectMeta{}.OpenAPIModelName()},
 	}
 }
 
-func schema_pkg_apis_extcattleio_v1_TokenStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_extcattleio_v1_TokenRevocationRequestList(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "TokenStatus defines the most recently observed status of the Token.",
+				Description: "TokenRevocationRequestList is a list of TokenRevocationRequest resources",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"value": {
+					"kind": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Value is the access key. It is shown only on token creation and not saved.",
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"hash": {
+					"apiVersion": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Hash is the hash of the Value.",
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"current": {
+					"metadata": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Current indicates whether the token was used to authenticate the current request.",
-							Default:     false,
-							Type:        []string{"boolean"},
+							Default: map[string]interface{}{},
+							Ref:     ref(metav1.ListMeta{}.OpenAPIModelName()),
+						},
+					},
+					"items": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref(v1.TokenRevocationRequest{}.OpenAPIModelName()),
+									},
+								},
+							},
+						},
+					},
+				},
+				Required: []string{"metadata", "items"},
+			},
+		},
+		Dependencies: []string{
+			v1.TokenRevocationRequest{}.OpenAPIModelName(), metav1.ListMeta{}.OpenAPIModelName()},
+	}
+}
+
+func schema_pkg_apis_extcattleio_v1_TokenRevocationRequestSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "TokenRevocationRequestSpec contains the selectors of the tokens to revoke.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"userID": {
+						SchemaProps: spec.SchemaProps{
+							Description: "UserID selects the tokens of the user.",
+							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"expired": {
+					"authProvider": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Expired indicates whether the token has exceeded its TTL.",
-							Default:     false,
-							Type:        []string{"boolean"},
+							Description: "AuthProvider selects the tokens issued by the auth provider, e.g. \"github\".",
+							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"expiresAt": {
+					"kind": {
 						SchemaProps: spec.SchemaProps{
-							Description: "ExpiresAt is the token's expiration timestamp or an empty string if the token doesn't expire.",
-							Default:     "",
+							Description: "Kind selects the tokens of a kind. It is \"session\" for login tokens, \"derived\" for all other tokens, or the kind of derived tokens, e.g. \"kubeconfig\".",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"lastUpdateTime": {
+					"clusterName": {
 						SchemaProps: spec.SchemaProps{
-							Description: "LastUpdateTime is the timestamp of the last change to the token.",
-							Default:     "",
+							Description: "ClusterName selects the tokens scoped to the cluster.",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"lastUsedAt": {
+					"createdAfter": {
 						SchemaProps: spec.SchemaProps{
-							Description: "LastUsedAt is the timestamp of the last time the token was used to authenticate.",
+							Description: "CreatedAfter selects the tokens created at or after the time.",
 							Ref:         ref(metav1.Time{}.OpenAPIModelName()),
 						},
 					},
-					"lastActivitySeen": {
+					"createdBefore": {
 						SchemaProps: spec.SchemaProps{
-							Description: "LastActivitySeen is the timestamp of the last time user activity (mouse movement, interaction, ...) was reported for the token.",
+							Description: "CreatedBefore selects the tokens created before the time.",
 							Ref:         ref(metav1.Time{}.OpenAPIModelName()),
 						},
 					},
-					"bearerToken": {
+					"setNotBefore": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Fully formed bearer token that is ready to use in the Authorization header to authenticate to Rancher.",
-							Type:        []string{"string"},
+							Description: "SetNotBefore persists the time of the request as a watermark on the user, if UserID is set, or else on the auth provider, if AuthProvider is set. All the tokens of the user or auth provider created before the watermark are rejected, regardless of the other selectors, so that tokens replayed from a cache can't be used either.",
+							Type:        []string{"boolean"},
 							Format:      "",
 						},
 					},
 				},
-				Required: []string{"current", "expired", "expiresAt", "lastUpdateTime"},
 			},
 		},
 		Dependencies: []string{
@@ -1118,18 +1308,506 @@ func schema_pkg_apis_extcattleio_v1_TokenStatus(ref common.ReferenceCallback) co
 	}
 }
 
-func schema_pkg_apis_extcattleio_v1_UserActivity(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_extcattleio_v1_TokenRevocationRequestStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "UserActivity keeps tracks user activity in the UI. If the user doesn't perform certain actions for a while e.g. cursor moved, key pressed, etc., this will lead to the user being logged out of the session.",
+				Description: "TokenRevocationRequestStatus defines the most recently observed status of the TokenRevocationRequest.",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"kind": {
+					"matchedCount": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
-							Type:        []string{"string"},
-							Format:      "",
+							Description: "MatchedCount is the number of tokens matching the selectors. Except for dry-run requests, they are recorded in a ConfigMap of the cattle-tokens namespace and revoked by a controller after the request returns. The controller retries the tokens which fail to be revoked, and deletes the ConfigMap once all of them are.",
+							Default:     0,
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+					"notBefore": {
+						SchemaProps: spec.SchemaProps{
+							Description: "NotBefore is the watermark persisted when SetNotBefore is set.",
+							Ref:         ref(metav1.Time{}.OpenAPIModelName()),
+						},
+					},
+					"conditions": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-type": "atomic",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "Conditions indicate state for particular aspects of the TokenRevocationRequest.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref(metav1.Condition{}.OpenAPIModelName()),
+									},
+								},
+							},
+						},
+					},
+					"summary": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Summary of the TokenRevocationRequest status.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"matchedCount"},
+			},
+		},
+		Dependencies: []string{
+			metav1.Condition{}.OpenAPIModelName(), metav1.Time{}.OpenAPIModelName()},
+	}
+}
+
+func schema_pkg_apis_extcattleio_v1_TokenSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "TokenSpec defines the desired state of the Token.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"userID": {
+						SchemaProps: spec.SchemaProps{
+							Description: "UserID is the kube resource id of the user owning the token. By default that is the user who owned the token making the request creating this token. Currently this default is enforced, i.e. using a different user is rejected as forbidden.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"userPrincipal": {
+						SchemaProps: spec.SchemaProps{
+							Description: "UserPrincipal holds the information about the ext auth provider managed user (principal) owning the token.",
+							Default:     map[string]interface{}{},
+							Ref:         ref(v1.TokenPrincipal{}.OpenAPIModelName()),
+						},
+					},
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind describes the nature of the token. The value \"session\" indicates a login/session token. Any other value, including the empty string, which is the default, stands for some kind of derived token.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"description": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Human readable free-form description of the token. For example, its purpose.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"ttl": {
+						SchemaProps: spec.SchemaProps{
+							Description: "TTL is the time-to-live of the token, in milliseconds. Setting a value < 0 represents +infinity, i.e. a token which does not expire. The default is indicated by the value `0`. This default is provided by the `auth-token-default-ttl-minutes` setting. A value < 0 there enables non-expiring tokens, if not disallowed by the max. That is, both the ttl here and the default are subject to the `auth-token-max-ttl-minutes` setting, i.e. will be clamped to it if they represent a larger value than that.",
+							Default:     0,
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+					"enabled": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Enabled indicates an active token. The default (`null`) indicates an enabled token.",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"clusterName": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ClusterName holds the name of the cluster the token is scoped to, if any. An empty string indicates that the token is not scoped to a specific cluster.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"userPrincipal"},
+			},
+		},
+		Dependencies: []string{
+			v1.TokenPrincipal{}.OpenAPIModelName()},
+	}
+}
+
+func schema_pkg_apis_extcattleio_v1_TokenStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "TokenStatus defines the most recently observed status of the Token.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"value": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Value is the access key. It is shown only on token creation and not saved.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"hash": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Hash is the hash of the Value.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"current": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Current indicates whether the token was used to authenticate the current request.",
+							Default:     false,
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"expired": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Expired indicates whether the token has exceeded its TTL.",
+							Default:     false,
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"expiresAt": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ExpiresAt is the token's expiration timestamp or an empty string if the token doesn't expire.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"lastUpdateTime": {
+						SchemaProps: spec.SchemaProps{
+							Description: "LastUpdateTime is the timestamp of the last change to the token.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"lastUsedAt": {
+						SchemaProps: spec.SchemaProps{
+							Description: "LastUsedAt is the timestamp of the last time the token was used to authenticate.",
+							Ref:         ref(metav1.Time{}.OpenAPIModelName()),
+						},
+					},
+					"lastActivitySeen": {
+						SchemaProps: spec.SchemaProps{
+							Description: "LastActivitySeen is the timestamp of the last time user activity (mouse movement, interaction, ...) was reported for the token.",
+							Ref:         ref(metav1.Time{}.OpenAPIModelName()),
+						},
+					},
+					"bearerToken": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Fully formed bearer token that is ready to use in the Authorization header to authenticate to Rancher.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"current", "expired", "expiresAt", "lastUpdateTime"},
+			},
+		},
+		Dependencies: []string{
+			metav1.Time{}.OpenAPIModelName()},
+	}
+}
+
+func schema_pkg_apis_extcattleio_v1_TokenUsage(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "TokenUsage is the usage of a Token aggregated over time. It has the name of the token and is read-only.\n\nStorage is bounded: only the most recently seen source networks, user agents and clusters are kept, and request counts per day are kept for a limited number of days.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Standard object metadata; More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#metadata.",
+							Default:     map[string]interface{}{},
+							Ref:         ref(metav1.ObjectMeta{}.OpenAPIModelName()),
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Status is the aggregated usage of the token.",
+							Default:     map[string]interface{}{},
+							Ref:         ref(v1.TokenUsageStatus{}.OpenAPIModelName()),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			v1.TokenUsageStatus{}.OpenAPIModelName(), metav1.ObjectMeta{}.OpenAPIModelName()},
+	}
+}
+
+func schema_pkg_apis_extcattleio_v1_TokenUsageDay(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "TokenUsageDay is the number of requests made with a token on a day.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"date": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Date is the day in the YYYY-MM-DD format.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"requestCount": {
+						SchemaProps: spec.SchemaProps{
+							Description: "RequestCount is the number of requests made on the day.",
+							Default:     0,
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+				},
+				Required: []string{"date", "requestCount"},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_extcattleio_v1_TokenUsageEntry(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "TokenUsageEntry is the usage of a token for a source network, user agent or cluster.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"value": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Value is the source network, user agent or cluster name.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"requestCount": {
+						SchemaProps: spec.SchemaProps{
+							Description: "RequestCount is the number of recorded requests.",
+							Default:     0,
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+					"firstUsedAt": {
+						SchemaProps: spec.SchemaProps{
+							Description: "FirstUsedAt is the time of the first recorded request.",
+							Ref:         ref(metav1.Time{}.OpenAPIModelName()),
+						},
+					},
+					"lastUsedAt": {
+						SchemaProps: spec.SchemaProps{
+							Description: "LastUsedAt is the time of the last recorded request.",
+							Ref:         ref(metav1.Time{}.OpenAPIModelName()),
+						},
+					},
+					"flagged": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Flagged is true if the source network was not seen before and the token was flagged for it.",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"value", "requestCount", "firstUsedAt", "lastUsedAt"},
+			},
+		},
+		Dependencies: []string{
+			metav1.Time{}.OpenAPIModelName()},
+	}
+}
+
+func schema_pkg_apis_extcattleio_v1_TokenUsageList(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "TokenUsageList is a list of TokenUsage resources",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref(metav1.ListMeta{}.OpenAPIModelName()),
+						},
+					},
+					"items": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref(v1.TokenUsage{}.OpenAPIModelName()),
+									},
+								},
+							},
+						},
+					},
+				},
+				Required: []string{"metadata", "items"},
+			},
+		},
+		Dependencies: []string{
+			v1.TokenUsage{}.OpenAPIModelName(), metav1.ListMeta{}.OpenAPIModelName()},
+	}
+}
+
+func schema_pkg_apis_extcattleio_v1_TokenUsageStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "TokenUsageStatus contains the aggregated usage of a token.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"userID": {
+						SchemaProps: spec.SchemaProps{
+							Description: "UserID is the id of the user owning the token.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"firstUsedAt": {
+						SchemaProps: spec.SchemaProps{
+							Description: "FirstUsedAt is the time of the first recorded request made with the token.",
+							Ref:         ref(metav1.Time{}.OpenAPIModelName()),
+						},
+					},
+					"lastUsedAt": {
+						SchemaProps: spec.SchemaProps{
+							Description: "LastUsedAt is the time of the last recorded request made with the token.",
+							Ref:         ref(metav1.Time{}.OpenAPIModelName()),
+						},
+					},
+					"requestCount": {
+						SchemaProps: spec.SchemaProps{
+							Description: "RequestCount is the total number of recorded requests made with the token.",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+					"days": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-type": "atomic",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "Days are the request counts per day (UTC), oldest first.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref(v1.TokenUsageDay{}.OpenAPIModelName()),
+									},
+								},
+							},
+						},
+					},
+					"networks": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-type": "atomic",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "Networks are the source networks of the requests, a /24 for IPv4 and a /64 for IPv6 addresses.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref(v1.TokenUsageEntry{}.OpenAPIModelName()),
+									},
+								},
+							},
+						},
+					},
+					"userAgents": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-type": "atomic",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "UserAgents are the user agents of the requests.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref(v1.TokenUsageEntry{}.OpenAPIModelName()),
+									},
+								},
+							},
+						},
+					},
+					"clusters": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-type": "atomic",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "Clusters are the clusters accessed with the token.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref(v1.TokenUsageEntry{}.OpenAPIModelName()),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			v1.TokenUsageDay{}.OpenAPIModelName(), v1.TokenUsageEntry{}.OpenAPIModelName(), metav1.Time{}.OpenAPIModelName()},
+	}
+}
+
+func schema_pkg_apis_extcattleio_v1_UserActivity(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "UserActivity keeps tracks user activity in the UI. If the user doesn't perform certain actions for a while e.g. cursor moved, key pressed, etc., this will lead to the user being logged out of the session.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
 					"apiVersion": {
@@ -1258,6 +1936,235 @@ func schema_pkg_apis_extcattleio_v1_UserActivityStatus(ref common.ReferenceCallb
 	}
 }
 
+func schema_pkg_apis_extcattleio_v1_UserRetentionReport(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "UserRetentionReport is used to list the users the user retention job would disable or delete, without changing any user. It evaluates the disable-inactive-user-after and delete-inactive-user-after settings, the UserRetentionPolicies and the per-user overrides against the current state of the users.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Standard object metadata; More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#metadata.",
+							Default:     map[string]interface{}{},
+							Ref:         ref(metav1.ObjectMeta{}.OpenAPIModelName()),
+						},
+					},
+					"spec": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Spec is the desired state of the UserRetentionReport.",
+							Default:     map[string]interface{}{},
+							Ref:         ref(v1.UserRetentionReportSpec{}.OpenAPIModelName()),
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Status is the most recently observed status of the UserRetentionReport.",
+							Default:     map[string]interface{}{},
+							Ref:         ref(v1.UserRetentionReportStatus{}.OpenAPIModelName()),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			v1.UserRetentionReportSpec{}.OpenAPIModelName(), v1.UserRetentionReportStatus{}.OpenAPIModelName(), metav1.ObjectMeta{}.OpenAPIModelName()},
+	}
+}
+
+func schema_pkg_apis_extcattleio_v1_UserRetentionReportEntry(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "UserRetentionReportEntry is a user that would be disabled or deleted.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"userID": {
+						SchemaProps: spec.SchemaProps{
+							Description: "UserID is the name of the user.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"username": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Username is the username of the user, if any.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"action": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Action is what would be done to the user. It is either \"Disable\" or \"Delete\".",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"policy": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Policy is the name of the UserRetentionPolicy which applies to the user, if any.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"lastLogin": {
+						SchemaProps: spec.SchemaProps{
+							Description: "LastLogin is the time the user last logged in, if known.",
+							Ref:         ref(metav1.Time{}.OpenAPIModelName()),
+						},
+					},
+				},
+				Required: []string{"userID", "action"},
+			},
+		},
+		Dependencies: []string{
+			metav1.Time{}.OpenAPIModelName()},
+	}
+}
+
+func schema_pkg_apis_extcattleio_v1_UserRetentionReportList(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "UserRetentionReportList is a list of UserRetentionReport resources",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref(metav1.ListMeta{}.OpenAPIModelName()),
+						},
+					},
+					"items": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref(v1.UserRetentionReport{}.OpenAPIModelName()),
+									},
+								},
+							},
+						},
+					},
+				},
+				Required: []string{"metadata", "items"},
+			},
+		},
+		Dependencies: []string{
+			v1.UserRetentionReport{}.OpenAPIModelName(), metav1.ListMeta{}.OpenAPIModelName()},
+	}
+}
+
+func schema_pkg_apis_extcattleio_v1_UserRetentionReportSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "UserRetentionReportSpec contains the parameters of the report.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"at": {
+						SchemaProps: spec.SchemaProps{
+							Description: "At is the time to evaluate the retention policies at. Defaults to the time of the request.",
+							Ref:         ref(metav1.Time{}.OpenAPIModelName()),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			metav1.Time{}.OpenAPIModelName()},
+	}
+}
+
+func schema_pkg_apis_extcattleio_v1_UserRetentionReportStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "UserRetentionReportStatus defines the most recently observed status of the UserRetentionReport.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"users": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-type": "atomic",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "Users are the users that would be disabled or deleted.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref(v1.UserRetentionReportEntry{}.OpenAPIModelName()),
+									},
+								},
+							},
+						},
+					},
+					"conditions": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-type": "atomic",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "Conditions indicate state for particular aspects of the UserRetentionReport.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref(metav1.Condition{}.OpenAPIModelName()),
+									},
+								},
+							},
+						},
+					},
+					"summary": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Summary of the UserRetentionReport status.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			v1.UserRetentionReportEntry{}.OpenAPIModelName(), metav1.Condition{}.OpenAPIModelName()},
+	}
+}
+
 func schema_pkg_apis_telemetrycattleio_v1_SecretRequest(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
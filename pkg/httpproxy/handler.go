@@ -0,0 +1,118 @@
+package httpproxy
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/rancher/rancher/pkg/auth/audit"
+	"k8s.io/apiserver/pkg/endpoints/request"
+)
+
+// Annotations of the audit log entries of proxied requests.
+const (
+	auditHostAnnotation          = "meta-proxy.cattle.io/host"
+	auditCredentialAnnotation    = "meta-proxy.cattle.io/credential"
+	auditRequestBytesAnnotation  = "meta-proxy.cattle.io/request-bytes"
+	auditResponseBytesAnnotation = "meta-proxy.cattle.io/response-bytes"
+	auditRateLimitedAnnotation   = "meta-proxy.cattle.io/rate-limited"
+)
+
+// handler rate limits proxied requests per user and per destination host, and records the destination host, the
+// credential used and the bytes transferred in the audit log entry of each request.
+type handler struct {
+	proxy       *proxy
+	next        http.Handler
+	userLimiter *rateLimiter
+	hostLimiter *rateLimiter
+	now         func() time.Time
+}
+
+func (h *handler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+
+	// Requests with an invalid destination are rejected when proxied.
+	host := ""
+	if destURL, err := h.proxy.destination(req); err == nil {
+		host = destURL.Hostname()
+		audit.AddAnnotation(ctx, auditHostAnnotation, host)
+	}
+	// Requests to hosts that aren't whitelisted are rejected when proxied too. They aren't rate limited, so that
+	// requesting arbitrary hosts doesn't create limiters.
+	if host == "" || !h.proxy.isAllowed(host) {
+		h.next.ServeHTTP(rw, req)
+		return
+	}
+	if req.Header.Get(APIAuth) == "" {
+		if credID := getRequestParams(req.Header.Get(CattleAuth))["credID"]; credID != "" {
+			audit.AddAnnotation(ctx, auditCredentialAnnotation, credID)
+		}
+	}
+
+	userName := ""
+	if user, ok := request.UserFrom(ctx); ok {
+		userName = user.GetName()
+	}
+	now := h.now()
+	userReservation, ok := h.userLimiter.reserve(userName, now)
+	if !ok {
+		h.rateLimited(rw, req, "user")
+		return
+	}
+	if _, ok := h.hostLimiter.reserve(host, now); !ok {
+		// The request isn't made, so it doesn't count towards the limit of the user.
+		if userReservation != nil {
+			userReservation.CancelAt(now)
+		}
+		h.rateLimited(rw, req, "host")
+		return
+	}
+
+	counter := &countingWriter{ResponseWriter: rw}
+	body := &countingReader{ReadCloser: req.Body}
+	if req.Body != nil && req.Body != http.NoBody {
+		req.Body = body
+	}
+	h.next.ServeHTTP(counter, req)
+
+	audit.AddAnnotation(ctx, auditRequestBytesAnnotation, strconv.FormatInt(body.bytes.Load(), 10))
+	audit.AddAnnotation(ctx, auditResponseBytesAnnotation, strconv.FormatInt(counter.bytes, 10))
+}
+
+func (h *handler) rateLimited(rw http.ResponseWriter, req *http.Request, limit string) {
+	audit.AddAnnotation(req.Context(), auditRateLimitedAnnotation, limit)
+	rw.Header().Set("Retry-After", "1")
+	http.Error(rw, "rate limit exceeded, please retry later", http.StatusTooManyRequests)
+}
+
+// countingWriter counts the bytes of the response body.
+type countingWriter struct {
+	http.ResponseWriter
+	bytes int64
+}
+
+func (w *countingWriter) Write(b []byte) (int, error) {
+	n, err := w.ResponseWriter.Write(b)
+	w.bytes += int64(n)
+	return n, err
+}
+
+// Unwrap lets [http.ResponseController] flush and hijack the underlying response writer.
+func (w *countingWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// countingReader counts the bytes of the request body. The body may still be read by the transport after the response
+// is received.
+type countingReader struct {
+	io.ReadCloser
+	bytes atomic.Int64
+}
+
+func (r *countingReader) Read(b []byte) (int, error) {
+	n, err := r.ReadCloser.Read(b)
+	r.bytes.Add(int64(n))
+	return n, err
+}
@@ -0,0 +1,109 @@
+package httpproxy
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rancher/rancher/pkg/auth/audit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/endpoints/request"
+)
+
+func TestHandler(t *testing.T) {
+	out := &bytes.Buffer{}
+	writer, err := audit.NewWriter(out, audit.WriterOptions{DisableDefaultPolicies: true})
+	require.NoError(t, err)
+
+	now := time.Now()
+	h := &handler{
+		proxy: &proxy{prefix: "/proxy/", validHostsSupplier: func() []string {
+			return []string{"api.example.com", "other.example.com"}
+		}},
+		next: http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			_, _ = io.Copy(io.Discard, req.Body)
+			_, _ = rw.Write([]byte("response"))
+		}),
+		userLimiter: newRateLimiter(func() (int, int) { return 1, 2 }),
+		hostLimiter: newRateLimiter(func() (int, int) { return 1, 3 }),
+		now:         func() time.Time { return now },
+	}
+	server := audit.NewAuditLogMiddleware(writer)(h)
+
+	send := func(userName, host string) int {
+		req := httptest.NewRequest(http.MethodPost, "/meta/proxy/"+host+"/api", strings.NewReader("request-body"))
+		req.Header.Set(CattleAuth, "bearer credID=cattle-global-data:cc-abc passwordField=token")
+		req = req.WithContext(request.WithUser(req.Context(), &user.DefaultInfo{Name: userName}))
+		rec := httptest.NewRecorder()
+		server.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	assert.Equal(t, http.StatusOK, send("u-alice", "api.example.com"))
+	assert.Equal(t, http.StatusOK, send("u-alice", "api.example.com"))
+	// The user exceeded their limit.
+	assert.Equal(t, http.StatusTooManyRequests, send("u-alice", "api.example.com"))
+	assert.Equal(t, http.StatusOK, send("u-bob", "api.example.com"))
+	// The host exceeded its limit, which doesn't count towards the limit of the user.
+	assert.Equal(t, http.StatusTooManyRequests, send("u-bob", "api.example.com"))
+	assert.Equal(t, http.StatusOK, send("u-bob", "other.example.com"))
+
+	now = now.Add(time.Second)
+	assert.Equal(t, http.StatusOK, send("u-alice", "api.example.com"))
+
+	// Requests to hosts that aren't whitelisted are rejected when proxied, and don't create limiters.
+	assert.Equal(t, http.StatusOK, send("u-mallory", "random-1.example.org"))
+	assert.Equal(t, http.StatusOK, send("u-mallory", "random-2.example.org"))
+	assert.ElementsMatch(t, []any{"u-alice", "u-bob"}, h.userLimiter.limiters.Keys())
+	assert.ElementsMatch(t, []any{"api.example.com", "other.example.com"}, h.hostLimiter.limiters.Keys())
+
+	type auditEntry struct {
+		User struct {
+			Name string `json:"name"`
+		} `json:"user"`
+		Method       string            `json:"method"`
+		ResponseCode int               `json:"responseCode"`
+		Annotations  map[string]string `json:"annotations"`
+	}
+	var entries []auditEntry
+	scanner := bufio.NewScanner(out)
+	for scanner.Scan() {
+		var entry auditEntry
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &entry))
+		entries = append(entries, entry)
+	}
+	require.Len(t, entries, 9)
+
+	assert.Equal(t, "u-alice", entries[0].User.Name)
+	assert.Equal(t, http.MethodPost, entries[0].Method)
+	assert.Equal(t, http.StatusOK, entries[0].ResponseCode)
+	assert.Equal(t, map[string]string{
+		auditHostAnnotation:          "api.example.com",
+		auditCredentialAnnotation:    "cattle-global-data:cc-abc",
+		auditRequestBytesAnnotation:  "12",
+		auditResponseBytesAnnotation: "8",
+	}, entries[0].Annotations)
+
+	assert.Equal(t, http.StatusTooManyRequests, entries[2].ResponseCode)
+	assert.Equal(t, "user", entries[2].Annotations[auditRateLimitedAnnotation])
+	assert.Equal(t, "host", entries[4].Annotations[auditRateLimitedAnnotation])
+}
+
+func TestRateLimiterDisabled(t *testing.T) {
+	l := newRateLimiter(func() (int, int) { return 0, 10 })
+	now := time.Now()
+	for i := 0; i < 100; i++ {
+		r, ok := l.reserve("u-alice", now)
+		assert.True(t, ok)
+		assert.Nil(t, r)
+	}
+	assert.Empty(t, l.limiters.Keys(), "no limiter is created when requests aren't limited")
+}
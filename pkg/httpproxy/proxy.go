@@ -17,6 +17,7 @@
 	provv1 "github.com/rancher/rancher/pkg/generated/controllers/provisioning.cattle.io/v1"
 	v1 "github.com/rancher/rancher/pkg/generated/norman/core/v1"
 	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/secretbackend"
 	"github.com/rancher/rancher/pkg/settings"
 	"github.com/rancher/rancher/pkg/types/config"
 	"github.com/rancher/steve/pkg/auth"
@@ -139,13 +140,23 @@ func NewProxy(prefix string, validHosts Supplier, scaledContext *config.ScaledCo
 		provClustersCache:  scaledContext.Wrangler.Provisioning.Cluster().Cache(),
 	}
 
-	return &httputil.ReverseProxy{
-		Director: func(req *http.Request) {
-			if err := p.proxy(req); err != nil {
-				logrus.Infof("Failed to proxy: %v", err)
-			}
+	return &handler{
+		proxy: &p,
+		next: &httputil.ReverseProxy{
+			Director: func(req *http.Request) {
+				if err := p.proxy(req); err != nil {
+					logrus.Infof("Failed to proxy: %v", err)
+				}
+			},
+			ModifyResponse: setModifiedHeaders,
 		},
-		ModifyResponse: setModifiedHeaders,
+		userLimiter: newRateLimiter(func() (int, int) {
+			return settings.MetaProxyUserRateLimit.GetInt(), settings.MetaProxyUserRateLimitBurst.GetInt()
+		}),
+		hostLimiter: newRateLimiter(func() (int, int) {
+			return settings.MetaProxyHostRateLimit.GetInt(), settings.MetaProxyHostRateLimitBurst.GetInt()
+		}),
+		now: time.Now,
 	}, nil
 }
 
@@ -163,7 +174,8 @@ func setModifiedHeaders(res *http.Response) error {
 	return nil
 }
 
-func (p *proxy) proxy(req *http.Request) error {
+// destination returns the URL a request is proxied to.
+func (p *proxy) destination(req *http.Request) (*url.URL, error) {
 	path := req.URL.String()
 	index := strings.Index(path, p.prefix)
 	destPath := path[index+len(p.prefix):]
@@ -178,10 +190,18 @@ func (p *proxy) proxy(req *http.Request) error {
 
 	destURL, err := url.Parse(destPath)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	destURL.RawQuery = req.URL.RawQuery
+	return destURL, nil
+}
+
+func (p *proxy) proxy(req *http.Request) error {
+	destURL, err := p.destination(req)
+	if err != nil {
+		return err
+	}
 	destURLHostname := destURL.Hostname()
 
 	if !p.isAllowed(destURLHostname) {
@@ -218,7 +238,7 @@ func (p *proxy) proxy(req *http.Request) error {
 	} else if cAuth != "" {
 		// setting CattleAuthHeader will replace credential id with secret data
 		// and generate signature
-		signer := newSigner(cAuth)
+		signer := newSigner(cAuth, p.isAllowed)
 		if signer != nil {
 			return signer.sign(req, p.secretGetter(req, cAuth), cAuth)
 		}
@@ -259,7 +279,11 @@ func (p *proxy) secretGetter(req *http.Request, cAuth string) SecretGetter {
 				return nil, unauthorizedErr
 			}
 		}
-		return p.credentials.Controller().Lister().Get(namespace, name)
+		secret, err := p.credentials.Controller().Lister().Get(namespace, name)
+		if err != nil {
+			return nil, err
+		}
+		return secretbackend.Resolve(req.Context(), secret)
 	}
 }
 
@@ -0,0 +1,89 @@
+package httpproxy
+
+import (
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+	"k8s.io/apimachinery/pkg/util/cache"
+)
+
+const (
+	// maxLimiters bounds the number of keys tracked by a rate limiter. The least recently used limiter is evicted
+	// once the bound is reached.
+	maxLimiters = 10000
+	// limiterIdleTTL is how long the limiter of a key is kept after its last request. A limiter idle for that long
+	// has a full bucket, so evicting it doesn't change the outcome of later requests.
+	limiterIdleTTL = 10 * time.Minute
+)
+
+// rateLimiter enforces request rate limits per key using token bucket algorithm. Each key gets an independent limiter,
+// so one user's or destination host's traffic doesn't affect another.
+type rateLimiter struct {
+	// mu serializes the creation of limiters.
+	mu sync.Mutex
+	// limiters maps keys to their individual rate limiters, and evicts the ones that are idle.
+	limiters *cache.LRUExpireCache
+	// getConfig returns the current rate limit settings.
+	getConfig func() (rate int, burst int)
+}
+
+// newRateLimiter creates a [rateLimiter] that reads the rate and burst from the provided function on every request,
+// allowing setting changes to take effect without restart.
+func newRateLimiter(getConfig func() (rate int, burst int)) *rateLimiter {
+	return &rateLimiter{
+		limiters:  cache.NewLRUExpireCache(maxLimiters),
+		getConfig: getConfig,
+	}
+}
+
+// reserve reserves a request for the given key, returning false if the request would exceed the limit. Reservations
+// can be canceled if the request isn't made after all. The reservation is nil if requests aren't limited.
+func (l *rateLimiter) reserve(key string, now time.Time) (*rate.Reservation, bool) {
+	lim := l.getLimiter(key)
+	if lim == nil {
+		return nil, true
+	}
+	r := lim.ReserveN(now, 1)
+	if !r.OK() || r.DelayFrom(now) > 0 {
+		r.CancelAt(now)
+		return nil, false
+	}
+	return r, true
+}
+
+// getLimiter returns the [rate.Limiter] for the given key, creating or updating it as needed based on current
+// settings. It returns nil if requests aren't limited.
+func (l *rateLimiter) getLimiter(key string) *rate.Limiter {
+	rps, burst := l.getConfig()
+	// Zero or negative value means no limit.
+	if rps <= 0 {
+		return nil
+	}
+	if burst < 1 {
+		burst = 1
+	}
+	limit := rate.Limit(rps)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var lim *rate.Limiter
+	if cached, ok := l.limiters.Get(key); ok {
+		lim = cached.(*rate.Limiter)
+		// Settings may have changed since last request — update in place so we keep the current token count instead
+		// of resetting it.
+		if lim.Limit() != limit {
+			lim.SetLimit(limit)
+		}
+		if lim.Burst() != burst {
+			lim.SetBurst(burst)
+		}
+	} else {
+		lim = rate.NewLimiter(limit, burst)
+	}
+	// Adding the limiter again extends its expiration, so that only idle limiters are evicted.
+	l.limiters.Add(key, lim, limiterIdleTTL)
+
+	return lim
+}
@@ -15,7 +15,7 @@ type Signer interface {
 	sign(*http.Request, SecretGetter, string) error
 }
 
-func newSigner(auth string) Signer {
+func newSigner(auth string, isAllowed func(host string) bool) Signer {
 	splitAuth := strings.Split(auth, " ")
 	switch strings.ToLower(splitAuth[0]) {
 	case "awsv4":
@@ -28,6 +28,12 @@ func newSigner(auth string) Signer {
 		return digest{}
 	case "arbitrary":
 		return arbitrary{}
+	case "oauth2":
+		return oauth2ClientCredentials{isAllowed: isAllowed}
+	case "gcp":
+		return gcp{isAllowed: isAllowed}
+	case "azure":
+		return azure{isAllowed: isAllowed}
 	}
 	return nil
 }
@@ -74,3 +80,17 @@ type basic struct{
 type digest struct{}
 
 type arbitrary struct{}
+
+// oauth2ClientCredentials, gcp and azure send credentials to token endpoints given in the request or the credential,
+// which must be allowed hosts.
+type oauth2ClientCredentials struct {
+	isAllowed func(host string) bool
+}
+
+type gcp struct {
+	isAllowed func(host string) bool
+}
+
+type azure struct {
+	isAllowed func(host string) bool
+}
@@ -0,0 +1,115 @@
+package httpproxy
+
+import (
+	"context"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/tls"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+const (
+	azureDefaultAuthorityHost = "https://login.microsoftonline.com"
+	azureClientAssertionType  = "urn:ietf:params:oauth:client-assertion-type:jwt-bearer"
+	azureAssertionTTL         = 10 * time.Minute
+)
+
+// azureDefaultScopes are the scopes of Azure AD access tokens if the request doesn't set any.
+var azureDefaultScopes = []string{"https://management.azure.com/.default"}
+
+// sign gets an Azure AD access token for an application with the client credentials grant, authenticating with
+// either a client secret or a certificate.
+func (a azure) sign(req *http.Request, secrets SecretGetter, auth string) error {
+	data, secret, err := getAuthData(auth, secrets, []string{"tenantIDField", "clientIDField", "credID"})
+	if err != nil {
+		return err
+	}
+	if (data["clientSecretField"] == "") == (data["certificateField"] == "") {
+		return fmt.Errorf("exactly one of clientSecretField and certificateField must be set")
+	}
+
+	authorityHost := azureDefaultAuthorityHost
+	if data["authorityHost"] != "" {
+		// The client credentials are sent to the authority, which must be trusted as much as the proxied hosts.
+		if err := checkTokenURL(data["authorityHost"], a.isAllowed); err != nil {
+			return err
+		}
+		authorityHost = data["authorityHost"]
+	}
+	tenantID := secret[data["tenantIDField"]]
+	if tenantID == "" || strings.ContainsAny(tenantID, "/?#") {
+		return fmt.Errorf("invalid tenant id %q", tenantID)
+	}
+	tokenURL := strings.TrimSuffix(authorityHost, "/") + "/" + url.PathEscape(tenantID) + "/oauth2/v2.0/token"
+
+	token, err := cachedToken("azure", a.isAllowed, data, secret, func(ctx context.Context) (oauth2.TokenSource, error) {
+		config := clientcredentials.Config{
+			ClientID:  secret[data["clientIDField"]],
+			TokenURL:  tokenURL,
+			Scopes:    splitScopes(data["scopes"], azureDefaultScopes),
+			AuthStyle: oauth2.AuthStyleInParams,
+		}
+		if data["clientSecretField"] != "" {
+			config.ClientSecret = secret[data["clientSecretField"]]
+			return config.TokenSource(ctx), nil
+		}
+
+		certificate, err := tls.X509KeyPair([]byte(secret[data["certificateField"]]), []byte(secret[data["certificateField"]]))
+		if err != nil {
+			return nil, fmt.Errorf("parsing certificate: %w", err)
+		}
+		if _, ok := certificate.PrivateKey.(*rsa.PrivateKey); !ok {
+			return nil, fmt.Errorf("certificate private key must be an RSA key")
+		}
+		return &azureAssertionTokenSource{ctx: ctx, config: config, certificate: certificate}, nil
+	})
+	if err != nil {
+		return err
+	}
+	token.SetAuthHeader(req)
+	return nil
+}
+
+// azureAssertionTokenSource gets tokens with the client credentials grant, authenticating with a client assertion
+// signed with a certificate. Assertions are short-lived, so a new one is signed for each token.
+type azureAssertionTokenSource struct {
+	ctx         context.Context
+	config      clientcredentials.Config
+	certificate tls.Certificate
+}
+
+// Token implements [oauth2.TokenSource].
+func (s *azureAssertionTokenSource) Token() (*oauth2.Token, error) {
+	now := time.Now()
+	assertion := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.RegisteredClaims{
+		Issuer:    s.config.ClientID,
+		Subject:   s.config.ClientID,
+		Audience:  jwt.ClaimStrings{s.config.TokenURL},
+		ID:        uuid.NewString(),
+		NotBefore: jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(now.Add(azureAssertionTTL)),
+	})
+	thumbprint := sha1.Sum(s.certificate.Certificate[0])
+	assertion.Header["x5t"] = base64.RawURLEncoding.EncodeToString(thumbprint[:])
+	signed, err := assertion.SignedString(s.certificate.PrivateKey)
+	if err != nil {
+		return nil, fmt.Errorf("signing client assertion: %w", err)
+	}
+
+	config := s.config
+	config.EndpointParams = url.Values{
+		"client_assertion_type": {azureClientAssertionType},
+		"client_assertion":      {signed},
+	}
+	return config.Token(s.ctx)
+}
@@ -0,0 +1,37 @@
+package httpproxy
+
+import (
+	"context"
+	"net/http"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+)
+
+// gcpDefaultScopes are the scopes of the access tokens of GCP service accounts if the request doesn't set any.
+var gcpDefaultScopes = []string{"https://www.googleapis.com/auth/cloud-platform"}
+
+// sign exchanges a JWT signed with the key of a GCP service account for an access token. The token endpoint is the one
+// of the service account key, which must use https and be an allowed host.
+func (g gcp) sign(req *http.Request, secrets SecretGetter, auth string) error {
+	data, secret, err := getAuthData(auth, secrets, []string{"serviceAccountField", "credID"})
+	if err != nil {
+		return err
+	}
+	token, err := cachedToken("gcp", g.isAllowed, data, secret, func(ctx context.Context) (oauth2.TokenSource, error) {
+		config, err := google.JWTConfigFromJSON([]byte(secret[data["serviceAccountField"]]), splitScopes(data["scopes"], gcpDefaultScopes)...)
+		if err != nil {
+			return nil, err
+		}
+		// The token_uri comes from the key, so it must be checked like the token endpoints given in requests.
+		if err := checkTokenURL(config.TokenURL, g.isAllowed); err != nil {
+			return nil, err
+		}
+		return config.TokenSource(ctx), nil
+	})
+	if err != nil {
+		return err
+	}
+	token.SetAuthHeader(req)
+	return nil
+}
@@ -0,0 +1,137 @@
+package httpproxy
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+	"k8s.io/apimachinery/pkg/util/cache"
+)
+
+// tokenSourceTTL is how long the token sources of the signers that exchange credentials for access tokens are cached
+// for. Tokens are refreshed by their token source when they expire.
+const tokenSourceTTL = time.Hour
+
+var (
+	// tokenSources caches token sources by the content of the credentials and the parameters they are used with, so
+	// that changes to credentials are picked up immediately.
+	tokenSources = cache.NewLRUExpireCache(1000)
+
+	// tokenClient is the client used to request access tokens.
+	tokenClient = &http.Client{Timeout: 30 * time.Second}
+)
+
+// sign gets an access token for the client ID and secret of a credential with the OAuth2 client credentials grant.
+func (o oauth2ClientCredentials) sign(req *http.Request, secrets SecretGetter, auth string) error {
+	data, secret, err := getAuthData(auth, secrets, []string{"tokenURL", "clientIDField", "clientSecretField", "credID"})
+	if err != nil {
+		return err
+	}
+	// The client secret is sent to the token endpoint, which must be trusted as much as the proxied hosts.
+	if err := checkTokenURL(data["tokenURL"], o.isAllowed); err != nil {
+		return err
+	}
+	token, err := cachedToken("oauth2", o.isAllowed, data, secret, func(ctx context.Context) (oauth2.TokenSource, error) {
+		config := &clientcredentials.Config{
+			ClientID:     secret[data["clientIDField"]],
+			ClientSecret: secret[data["clientSecretField"]],
+			TokenURL:     data["tokenURL"],
+			Scopes:       splitScopes(data["scopes"], nil),
+		}
+		return config.TokenSource(ctx), nil
+	})
+	if err != nil {
+		return err
+	}
+	token.SetAuthHeader(req)
+	return nil
+}
+
+// cachedToken returns a valid token from the cached token source of a credential, creating the token source with
+// newSource if it isn't cached yet. Token requests only follow redirects to allowed hosts.
+func cachedToken(signer string, isAllowed func(host string) bool, params, secret map[string]string, newSource func(ctx context.Context) (oauth2.TokenSource, error)) (*oauth2.Token, error) {
+	key := tokenSourceKey(signer, params, secret)
+	if source, ok := tokenSources.Get(key); ok {
+		return tokenFrom(signer, source.(oauth2.TokenSource))
+	}
+
+	// Token sources outlive requests, so they can't use their context.
+	ctx := context.WithValue(context.Background(), oauth2.HTTPClient, tokenClientFor(isAllowed))
+	source, err := newSource(ctx)
+	if err != nil {
+		return nil, err
+	}
+	source = oauth2.ReuseTokenSource(nil, source)
+	tokenSources.Add(key, source, tokenSourceTTL)
+	return tokenFrom(signer, source)
+}
+
+// tokenClientFor returns a copy of tokenClient that refuses to follow redirects to token endpoints that checkTokenURL
+// rejects.
+func tokenClientFor(isAllowed func(host string) bool) *http.Client {
+	client := *tokenClient
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if len(via) >= 10 {
+			return fmt.Errorf("stopped after 10 redirects")
+		}
+		return checkTokenURL(req.URL.String(), isAllowed)
+	}
+	return &client
+}
+
+func tokenFrom(signer string, source oauth2.TokenSource) (*oauth2.Token, error) {
+	token, err := source.Token()
+	if err != nil {
+		return nil, fmt.Errorf("getting %s access token: %w", signer, err)
+	}
+	return token, nil
+}
+
+// tokenSourceKey returns the cache key of the token source of a signer for a credential and request parameters.
+func tokenSourceKey(signer string, params, secret map[string]string) string {
+	h := sha256.New()
+	h.Write([]byte(signer))
+	for _, m := range []map[string]string{params, secret} {
+		keys := make([]string, 0, len(m))
+		for k := range m {
+			keys = append(keys, k)
+		}
+		slices.Sort(keys)
+		for _, k := range keys {
+			fmt.Fprintf(h, "\x00%s\x00%s", k, m[k])
+		}
+		h.Write([]byte{0xff})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// checkTokenURL checks that a token endpoint given in the request uses https and is an allowed host.
+func checkTokenURL(tokenURL string, isAllowed func(host string) bool) error {
+	u, err := url.Parse(tokenURL)
+	if err != nil {
+		return fmt.Errorf("invalid token url %s: %w", tokenURL, err)
+	}
+	if u.Scheme != "https" {
+		return fmt.Errorf("token url %s must use https", tokenURL)
+	}
+	if isAllowed == nil || !isAllowed(u.Hostname()) {
+		return fmt.Errorf("invalid token url host: %v", u.Hostname())
+	}
+	return nil
+}
+
+// splitScopes returns the comma separated scopes, or the default scopes if there are none.
+func splitScopes(scopes string, defaults []string) []string {
+	if scopes == "" {
+		return defaults
+	}
+	return strings.Split(scopes, ",")
+}
@@ -0,0 +1,247 @@
+package httpproxy
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// fakeTokenServer is a local token endpoint that checks the client authentication of the grants it supports.
+type fakeTokenServer struct {
+	*httptest.Server
+	key      *rsa.PrivateKey
+	requests atomic.Int32
+	// form is the last token request.
+	form url.Values
+}
+
+func newFakeTokenServer(t *testing.T) *fakeTokenServer {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	s := &fakeTokenServer{key: key}
+	s.Server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.requests.Add(1)
+		if err := r.ParseForm(); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		s.form = r.PostForm
+
+		authenticated := false
+		switch {
+		case r.PostForm.Get("grant_type") == "client_credentials" && r.PostForm.Get("client_assertion") != "":
+			_, err := jwt.Parse(r.PostForm.Get("client_assertion"), func(*jwt.Token) (any, error) { return &key.PublicKey, nil },
+				jwt.WithAudience("https://"+r.Host+r.URL.Path), jwt.WithIssuer("client-id"))
+			authenticated = err == nil && r.PostForm.Get("client_assertion_type") == azureClientAssertionType
+		case r.PostForm.Get("grant_type") == "client_credentials":
+			id, secret, ok := r.BasicAuth()
+			if !ok {
+				id, secret = r.PostForm.Get("client_id"), r.PostForm.Get("client_secret")
+			}
+			authenticated = id == "client-id" && secret == "client-secret"
+		case r.PostForm.Get("grant_type") == "urn:ietf:params:oauth:grant-type:jwt-bearer":
+			_, err := jwt.Parse(r.PostForm.Get("assertion"), func(*jwt.Token) (any, error) { return &key.PublicKey, nil },
+				jwt.WithAudience(s.URL+"/token"), jwt.WithIssuer("proxy@project.iam.gserviceaccount.com"))
+			authenticated = err == nil
+		}
+		if !authenticated {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"error":"invalid_client"}`))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"access_token": "access-token",
+			"token_type":   "Bearer",
+			"expires_in":   3600,
+		})
+	}))
+	t.Cleanup(s.Close)
+
+	client := tokenClient
+	tokenClient = s.Client()
+	t.Cleanup(func() { tokenClient = client })
+
+	return s
+}
+
+func (s *fakeTokenServer) host() string {
+	u, _ := url.Parse(s.URL)
+	return u.Hostname()
+}
+
+func secretGetterFor(data map[string]string) SecretGetter {
+	return func(namespace, name string) (*corev1.Secret, error) {
+		secret := &corev1.Secret{Data: map[string][]byte{}}
+		for k, v := range data {
+			secret.Data[k] = []byte(v)
+		}
+		return secret, nil
+	}
+}
+
+func TestOAuth2ClientCredentialsSign(t *testing.T) {
+	server := newFakeTokenServer(t)
+	isAllowed := func(host string) bool { return host == server.host() }
+	secrets := secretGetterFor(map[string]string{
+		"oauthcredentialConfig-clientId":     "client-id",
+		"oauthcredentialConfig-clientSecret": "client-secret",
+	})
+	auth := "oauth2 credID=cattle-global-data:cc-oauth2 clientIDField=clientId clientSecretField=clientSecret scopes=read,write tokenURL=" + server.URL + "/token"
+
+	for range 2 {
+		req := httptest.NewRequest(http.MethodGet, "https://api.example.com/", nil)
+		require.NoError(t, newSigner(auth, isAllowed).sign(req, secrets, auth))
+		assert.Equal(t, "Bearer access-token", req.Header.Get(AuthHeader))
+	}
+	// The token is cached.
+	assert.Equal(t, int32(1), server.requests.Load())
+	assert.Equal(t, "read write", server.form.Get("scope"))
+
+	// A changed credential gets a new token.
+	rotated := secretGetterFor(map[string]string{
+		"oauthcredentialConfig-clientId":     "client-id",
+		"oauthcredentialConfig-clientSecret": "rotated",
+	})
+	req := httptest.NewRequest(http.MethodGet, "https://api.example.com/", nil)
+	assert.ErrorContains(t, newSigner(auth, isAllowed).sign(req, rotated, auth), "getting oauth2 access token")
+	assert.Greater(t, server.requests.Load(), int32(1))
+
+	// The token endpoint must be an allowed host.
+	req = httptest.NewRequest(http.MethodGet, "https://api.example.com/", nil)
+	assert.ErrorContains(t, newSigner(auth, func(string) bool { return false }).sign(req, secrets, auth), "invalid token url host")
+
+	insecure := "oauth2 credID=cattle-global-data:cc-oauth2 clientIDField=clientId clientSecretField=clientSecret tokenURL=http://" + server.host() + "/token"
+	assert.ErrorContains(t, newSigner(insecure, isAllowed).sign(req, secrets, insecure), "must use https")
+}
+
+func TestGCPSign(t *testing.T) {
+	server := newFakeTokenServer(t)
+	isAllowed := func(host string) bool { return host == server.host() }
+	serviceAccountWith := func(tokenURI string) SecretGetter {
+		serviceAccount, err := json.Marshal(map[string]string{
+			"type":           "service_account",
+			"project_id":     "project",
+			"private_key_id": "key",
+			"private_key":    string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(server.key)})),
+			"client_email":   "proxy@project.iam.gserviceaccount.com",
+			"token_uri":      tokenURI,
+		})
+		require.NoError(t, err)
+		return secretGetterFor(map[string]string{"googlecredentialConfig-authEncodedJson": string(serviceAccount)})
+	}
+	secrets := serviceAccountWith(server.URL + "/token")
+	auth := "gcp credID=cattle-global-data:cc-gcp serviceAccountField=authEncodedJson"
+
+	for range 2 {
+		req := httptest.NewRequest(http.MethodGet, "https://container.googleapis.com/v1/projects/project/zones", nil)
+		require.NoError(t, newSigner(auth, isAllowed).sign(req, secrets, auth))
+		assert.Equal(t, "Bearer access-token", req.Header.Get(AuthHeader))
+	}
+	assert.Equal(t, int32(1), server.requests.Load())
+
+	req := httptest.NewRequest(http.MethodGet, "https://container.googleapis.com/", nil)
+	invalid := secretGetterFor(map[string]string{"googlecredentialConfig-authEncodedJson": `{"type":"authorized_user"}`})
+	assert.ErrorContains(t, newSigner(auth, isAllowed).sign(req, invalid, auth), "expected \"service_account\"")
+
+	// The token_uri of the key must be an allowed host.
+	notAllowed := serviceAccountWith("https://token.example.com/token")
+	assert.ErrorContains(t, newSigner(auth, isAllowed).sign(req, notAllowed, auth), "invalid token url host: token.example.com")
+
+	insecure := serviceAccountWith("http://" + server.host() + "/token")
+	assert.ErrorContains(t, newSigner(auth, isAllowed).sign(req, insecure, auth), "must use https")
+}
+
+func TestTokenRedirects(t *testing.T) {
+	server := newFakeTokenServer(t)
+	redirect := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "https://token.example.com/token", http.StatusTemporaryRedirect)
+	}))
+	t.Cleanup(redirect.Close)
+
+	isAllowed := func(host string) bool { return host == server.host() }
+	secrets := secretGetterFor(map[string]string{
+		"oauthcredentialConfig-clientId":     "client-id",
+		"oauthcredentialConfig-clientSecret": "client-secret",
+	})
+	auth := "oauth2 credID=cattle-global-data:cc-oauth2 clientIDField=clientId clientSecretField=clientSecret tokenURL=" + redirect.URL + "/token"
+
+	// The redirecting server has the same host as the token server, only the redirect target isn't allowed.
+	req := httptest.NewRequest(http.MethodGet, "https://api.example.com/", nil)
+	assert.ErrorContains(t, newSigner(auth, isAllowed).sign(req, secrets, auth), "invalid token url host: token.example.com")
+}
+
+func TestAzureSign(t *testing.T) {
+	server := newFakeTokenServer(t)
+	isAllowed := func(host string) bool { return host == server.host() }
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "proxy"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &server.key.PublicKey, server.key)
+	require.NoError(t, err)
+	certificate := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})) +
+		string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(server.key)}))
+
+	secrets := secretGetterFor(map[string]string{
+		"azurecredentialConfig-tenantId":     "tenant",
+		"azurecredentialConfig-clientId":     "client-id",
+		"azurecredentialConfig-clientSecret": "client-secret",
+		"azurecredentialConfig-certificate":  certificate,
+	})
+
+	t.Run("client secret", func(t *testing.T) {
+		auth := "azure credID=cattle-global-data:cc-azure tenantIDField=tenantId clientIDField=clientId clientSecretField=clientSecret authorityHost=" + server.URL
+		req := httptest.NewRequest(http.MethodGet, "https://management.azure.com/subscriptions", nil)
+		require.NoError(t, newSigner(auth, isAllowed).sign(req, secrets, auth))
+		assert.Equal(t, "Bearer access-token", req.Header.Get(AuthHeader))
+		assert.Equal(t, "https://management.azure.com/.default", server.form.Get("scope"))
+	})
+
+	t.Run("certificate", func(t *testing.T) {
+		auth := "azure credID=cattle-global-data:cc-azure tenantIDField=tenantId clientIDField=clientId certificateField=certificate authorityHost=" + server.URL
+		req := httptest.NewRequest(http.MethodGet, "https://management.azure.com/subscriptions", nil)
+		require.NoError(t, newSigner(auth, isAllowed).sign(req, secrets, auth))
+		assert.Equal(t, "Bearer access-token", req.Header.Get(AuthHeader))
+		assert.Empty(t, server.form.Get("client_secret"))
+
+		assertion, _, err := jwt.NewParser().ParseUnverified(server.form.Get("client_assertion"), jwt.MapClaims{})
+		require.NoError(t, err)
+		thumbprint := sha1.Sum(der)
+		assert.Equal(t, base64.RawURLEncoding.EncodeToString(thumbprint[:]), assertion.Header["x5t"])
+	})
+
+	t.Run("authority must be allowed", func(t *testing.T) {
+		auth := "azure credID=cattle-global-data:cc-azure tenantIDField=tenantId clientIDField=clientId clientSecretField=clientSecret authorityHost=https://login.example.com"
+		req := httptest.NewRequest(http.MethodGet, "https://management.azure.com/subscriptions", nil)
+		assert.ErrorContains(t, newSigner(auth, isAllowed).sign(req, secrets, auth), "invalid token url host: login.example.com")
+	})
+
+	t.Run("one client authentication", func(t *testing.T) {
+		auth := "azure credID=cattle-global-data:cc-azure tenantIDField=tenantId clientIDField=clientId"
+		req := httptest.NewRequest(http.MethodGet, "https://management.azure.com/subscriptions", nil)
+		assert.EqualError(t, newSigner(auth, isAllowed).sign(req, secrets, auth), "exactly one of clientSecretField and certificateField must be set")
+	})
+}
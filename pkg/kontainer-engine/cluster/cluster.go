@@ -9,6 +9,7 @@
 
 	"github.com/rancher/rancher/pkg/kontainer-engine/logstream"
 	"github.com/rancher/rancher/pkg/kontainer-engine/types"
+	v2 "github.com/rancher/rancher/pkg/kontainer-engine/types/v2"
 	"github.com/sirupsen/logrus"
 	"k8s.io/apimachinery/pkg/api/errors"
 )
@@ -250,8 +251,24 @@ func (c *Cluster) GetVersion(ctx context.Context) (*types.KubernetesVersion, err
 	return c.Driver.GetVersion(ctx, toInfo(c))
 }
 
+// SetVersion upgrades the cluster to the version, streaming the progress of the upgrade if the driver supports the v2
+// protocol
 func (c *Cluster) SetVersion(ctx context.Context, version *types.KubernetesVersion) error {
-	return c.Driver.SetVersion(ctx, toInfo(c), version)
+	driver, ok := c.Driver.(v2.Driver)
+	if !ok {
+		return c.Driver.SetVersion(ctx, toInfo(c), version)
+	}
+
+	driverOpts, err := c.ConfigGetter.GetConfig()
+	if err != nil {
+		return err
+	}
+	info, err := driver.Upgrade(ctx, toInfo(c), &driverOpts, version)
+	if err != nil {
+		return err
+	}
+	transformClusterInfo(c, info)
+	return c.Store()
 }
 
 func (c *Cluster) GetClusterSize(ctx context.Context) (*types.NodeCount, error) {
@@ -350,7 +367,7 @@ func (c *Cluster) restore() error {
 
 // NewCluster create a cluster interface to do operations
 func NewCluster(driverName, name, addr string, configGetter ConfigGetter, persistStore PersistentStore) (*Cluster, error) {
-	rpcClient, err := types.NewClient(driverName, addr)
+	rpcClient, err := v2.NewClient(driverName, addr)
 	if err != nil {
 		return nil, err
 	}
@@ -364,7 +381,7 @@ func NewCluster(driverName, name, addr string, configGetter ConfigGetter, persis
 }
 
 func FromCluster(cluster *Cluster, addr string, configGetter ConfigGetter, persistStore PersistentStore) (*Cluster, error) {
-	rpcClient, err := types.NewClient(cluster.DriverName, addr)
+	rpcClient, err := v2.NewClient(cluster.DriverName, addr)
 	if err != nil {
 		return nil, err
 	}
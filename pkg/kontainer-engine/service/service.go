@@ -17,6 +17,7 @@
 	"github.com/rancher/rancher/pkg/kontainer-engine/cluster"
 	kubeimport "github.com/rancher/rancher/pkg/kontainer-engine/drivers/import"
 	"github.com/rancher/rancher/pkg/kontainer-engine/types"
+	v2 "github.com/rancher/rancher/pkg/kontainer-engine/types/v2"
 	"github.com/sirupsen/logrus"
 	"gopkg.in/yaml.v2"
 )
@@ -365,7 +366,7 @@ type RunningDriver struct {
 	Name    string
 	Path    string
 	Builtin bool
-	Server  *types.GrpcServer
+	Server  *v2.GrpcServer
 
 	listenAddress string
 	cancel        context.CancelFunc
@@ -397,7 +398,7 @@ func (r *RunningDriver) Start() (string, error) {
 
 		addr := make(chan string)
 		errChan := make(chan error)
-		r.Server = types.NewServer(driver, addr)
+		r.Server = v2.NewServer(v2.FromV1(driver), addr)
 		go r.Server.Serve(listenAddress, errChan)
 
 		// if the error hasn't appeared after 5 seconds assume it won't error
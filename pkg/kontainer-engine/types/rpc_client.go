@@ -17,12 +17,16 @@ func NewClient(driverName string, addr string) (CloseableDriver, error) {
 	if err != nil {
 		return nil, err
 	}
-	c := NewDriverClient(conn)
+	return NewClientFromConn(driverName, conn), nil
+}
+
+// NewClientFromConn creates a grpc client for a driver plugin on an existing connection, which the client closes
+func NewClientFromConn(driverName string, conn *grpc.ClientConn) CloseableDriver {
 	return &grpcClient{
-		client:     c,
+		client:     NewDriverClient(conn),
 		driverName: driverName,
 		conn:       conn,
-	}, nil
+	}
 }
 
 // grpcClient defines the grpc client struct
@@ -0,0 +1,67 @@
+package v2
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rancher/rancher/pkg/kontainer-engine/types"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// FromV1 adapts a driver implementing only the v1 interface to the v2 interface. Create and Update report the start and
+// the end of the call, since a v1 driver reports its progress only to the log stream, and Upgrade sets the version of
+// the cluster. Listing versions and node pools is not supported.
+func FromV1(driver types.Driver) Driver {
+	if d, ok := driver.(Driver); ok {
+		return d
+	}
+	return &v1Driver{Driver: driver}
+}
+
+type v1Driver struct {
+	types.Driver
+}
+
+func (d *v1Driver) GetProtocolCapabilities(ctx context.Context) (*Capabilities, error) {
+	return &Capabilities{Upgrade: true}, nil
+}
+
+func (d *v1Driver) Create(ctx context.Context, opts *types.DriverOptions, clusterInfo *types.ClusterInfo) (*types.ClusterInfo, error) {
+	ReportProgress(ctx, &Progress{Step: "Creating cluster"})
+	info, err := d.Driver.Create(ctx, opts, clusterInfo)
+	if err == nil {
+		ReportProgress(ctx, &Progress{Step: "Creating cluster", Percent: 100, Message: "cluster created"})
+	}
+	return info, err
+}
+
+func (d *v1Driver) Update(ctx context.Context, clusterInfo *types.ClusterInfo, opts *types.DriverOptions) (*types.ClusterInfo, error) {
+	ReportProgress(ctx, &Progress{Step: "Updating cluster"})
+	info, err := d.Driver.Update(ctx, clusterInfo, opts)
+	if err == nil {
+		ReportProgress(ctx, &Progress{Step: "Updating cluster", Percent: 100, Message: "cluster updated"})
+	}
+	return info, err
+}
+
+func (d *v1Driver) Upgrade(ctx context.Context, clusterInfo *types.ClusterInfo, opts *types.DriverOptions, version *types.KubernetesVersion) (*types.ClusterInfo, error) {
+	step := fmt.Sprintf("Upgrading cluster to %s", version.GetVersion())
+	ReportProgress(ctx, &Progress{Step: step})
+	if err := d.Driver.SetVersion(ctx, clusterInfo, version); err != nil {
+		return nil, err
+	}
+	ReportProgress(ctx, &Progress{Step: step, Percent: 100, Message: "cluster upgraded"})
+
+	info := *clusterInfo
+	info.Version = version.GetVersion()
+	return &info, nil
+}
+
+func (d *v1Driver) ListVersions(ctx context.Context, opts *types.DriverOptions) (*VersionList, error) {
+	return nil, status.Error(codes.Unimplemented, "driver does not support listing versions")
+}
+
+func (d *v1Driver) ListNodePools(ctx context.Context, clusterInfo *types.ClusterInfo) (*NodePoolList, error) {
+	return nil, status.Error(codes.Unimplemented, "driver does not support listing node pools")
+}
@@ -0,0 +1,186 @@
+package v2
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+
+	"github.com/rancher/rancher/pkg/kontainer-engine/types"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+// NewClient creates a grpc client for a driver plugin. The client uses the v2 protocol if the plugin serves it, and
+// falls back to the v1 protocol otherwise.
+func NewClient(driverName string, addr string) (CloseableDriver, error) {
+	conn, err := grpc.Dial(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, err
+	}
+	v1 := types.NewClientFromConn(driverName, conn)
+	return &grpcClient{
+		CloseableDriver: v1,
+		client:          NewDriverClient(conn),
+		compat:          FromV1(v1),
+	}, nil
+}
+
+// grpcClient defines the grpc client struct. The calls unchanged in the v2 protocol go to the embedded v1 client.
+type grpcClient struct {
+	types.CloseableDriver
+	client DriverClient
+	compat Driver
+
+	lock         sync.Mutex
+	capabilities *Capabilities
+	v2           bool
+}
+
+// protocol returns whether the plugin serves the v2 protocol, and its capabilities if it does.
+func (rpc *grpcClient) protocol(ctx context.Context) (bool, *Capabilities, error) {
+	rpc.lock.Lock()
+	defer rpc.lock.Unlock()
+
+	if rpc.capabilities != nil {
+		return rpc.v2, rpc.capabilities, nil
+	}
+	capabilities, err := rpc.client.GetCapabilities(ctx, &types.Empty{})
+	if status.Code(err) == codes.Unimplemented {
+		capabilities, err = rpc.compat.GetProtocolCapabilities(ctx)
+		if err != nil {
+			return false, nil, err
+		}
+		rpc.capabilities = capabilities
+		return false, capabilities, nil
+	} else if err != nil {
+		return false, nil, handlErr(err)
+	}
+	rpc.capabilities = capabilities
+	rpc.v2 = true
+	return true, capabilities, nil
+}
+
+func (rpc *grpcClient) GetProtocolCapabilities(ctx context.Context) (*Capabilities, error) {
+	_, capabilities, err := rpc.protocol(ctx)
+	return capabilities, err
+}
+
+// Create call grpc create
+func (rpc *grpcClient) Create(ctx context.Context, opts *types.DriverOptions, clusterInfo *types.ClusterInfo) (*types.ClusterInfo, error) {
+	v2, _, err := rpc.protocol(ctx)
+	if err != nil {
+		return nil, err
+	} else if !v2 {
+		return rpc.compat.Create(ctx, opts, clusterInfo)
+	}
+
+	stream, err := rpc.client.Create(ctx, &types.CreateRequest{
+		DriverOptions: opts,
+		ClusterInfo:   clusterInfo,
+	})
+	if err != nil {
+		return nil, handlErr(err)
+	}
+	info, err := receive(ctx, stream)
+	if err == nil && info.CreateError != "" {
+		err = errors.New(info.CreateError)
+	}
+	return info, err
+}
+
+// Update call grpc update
+func (rpc *grpcClient) Update(ctx context.Context, clusterInfo *types.ClusterInfo, opts *types.DriverOptions) (*types.ClusterInfo, error) {
+	v2, _, err := rpc.protocol(ctx)
+	if err != nil {
+		return nil, err
+	} else if !v2 {
+		return rpc.compat.Update(ctx, clusterInfo, opts)
+	}
+
+	stream, err := rpc.client.Update(ctx, &types.UpdateRequest{
+		ClusterInfo:   clusterInfo,
+		DriverOptions: opts,
+	})
+	if err != nil {
+		return nil, handlErr(err)
+	}
+	return receive(ctx, stream)
+}
+
+// Upgrade call grpc upgrade, or sets the version of the cluster if the plugin does not support upgrades
+func (rpc *grpcClient) Upgrade(ctx context.Context, clusterInfo *types.ClusterInfo, opts *types.DriverOptions, version *types.KubernetesVersion) (*types.ClusterInfo, error) {
+	v2, capabilities, err := rpc.protocol(ctx)
+	if err != nil {
+		return nil, err
+	} else if !v2 || !capabilities.Upgrade {
+		return rpc.compat.Upgrade(ctx, clusterInfo, opts, version)
+	}
+
+	stream, err := rpc.client.Upgrade(ctx, &UpgradeRequest{
+		ClusterInfo:   clusterInfo,
+		DriverOptions: opts,
+		Version:       version,
+	})
+	if err != nil {
+		return nil, handlErr(err)
+	}
+	return receive(ctx, stream)
+}
+
+func (rpc *grpcClient) ListVersions(ctx context.Context, opts *types.DriverOptions) (*VersionList, error) {
+	v2, capabilities, err := rpc.protocol(ctx)
+	if err != nil {
+		return nil, err
+	} else if !v2 || !capabilities.VersionListing {
+		return rpc.compat.ListVersions(ctx, opts)
+	}
+	versions, err := rpc.client.ListVersions(ctx, opts)
+	return versions, handlErr(err)
+}
+
+func (rpc *grpcClient) ListNodePools(ctx context.Context, clusterInfo *types.ClusterInfo) (*NodePoolList, error) {
+	v2, capabilities, err := rpc.protocol(ctx)
+	if err != nil {
+		return nil, err
+	} else if !v2 || !capabilities.NodePools {
+		return rpc.compat.ListNodePools(ctx, clusterInfo)
+	}
+	nodePools, err := rpc.client.ListNodePools(ctx, clusterInfo)
+	return nodePools, handlErr(err)
+}
+
+// receive passes the progress events of the stream to the progress function of the context, and returns the cluster
+// info the stream ends with.
+func receive(ctx context.Context, stream grpc.ServerStreamingClient[Event]) (*types.ClusterInfo, error) {
+	var info *types.ClusterInfo
+	for {
+		event, err := stream.Recv()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, handlErr(err)
+		}
+		switch e := event.Event.(type) {
+		case *Event_Progress:
+			ReportProgress(ctx, e.Progress)
+		case *Event_Result:
+			info = e.Result
+		}
+	}
+	if info == nil {
+		return nil, errors.New("driver did not return the cluster info")
+	}
+	return info, nil
+}
+
+func handlErr(err error) error {
+	if st, ok := status.FromError(err); ok {
+		if st.Code() == codes.Unknown && st.Message() != "" {
+			return errors.New(st.Message())
+		}
+	}
+	return err
+}
@@ -0,0 +1,131 @@
+package v2
+
+import (
+	"context"
+	"net"
+	"sync"
+
+	"github.com/rancher/rancher/pkg/kontainer-engine/types"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/reflection"
+)
+
+// GrpcServer serves both the v1 and v2 protocols for a driver plugin
+type GrpcServer struct {
+	UnimplementedDriverServer
+
+	driver     Driver
+	v1         *types.GrpcServer
+	address    chan string
+	grpcServer *grpc.Server
+}
+
+// NewServer creates a grpc server for a specific plugin
+func NewServer(driver Driver, addr chan string) *GrpcServer {
+	return &GrpcServer{
+		driver:  driver,
+		v1:      types.NewServer(driver, addr),
+		address: addr,
+	}
+}
+
+// GetCapabilities implements grpc method
+func (s *GrpcServer) GetCapabilities(ctx context.Context, in *types.Empty) (*Capabilities, error) {
+	return s.driver.GetProtocolCapabilities(ctx)
+}
+
+// Create implements grpc method
+func (s *GrpcServer) Create(create *types.CreateRequest, stream grpc.ServerStreamingServer[Event]) error {
+	ctx := s.getCtx(stream)
+	info, err := s.driver.Create(ctx, create.DriverOptions, create.ClusterInfo)
+	if err != nil && info != nil {
+		info.CreateError = err.Error()
+		err = nil
+	}
+	return sendResult(stream, info, err)
+}
+
+// Update implements grpc method
+func (s *GrpcServer) Update(update *types.UpdateRequest, stream grpc.ServerStreamingServer[Event]) error {
+	info, err := s.driver.Update(s.getCtx(stream), update.ClusterInfo, update.DriverOptions)
+	return sendResult(stream, info, err)
+}
+
+// Upgrade implements grpc method
+func (s *GrpcServer) Upgrade(upgrade *UpgradeRequest, stream grpc.ServerStreamingServer[Event]) error {
+	info, err := s.driver.Upgrade(s.getCtx(stream), upgrade.ClusterInfo, upgrade.DriverOptions, upgrade.Version)
+	return sendResult(stream, info, err)
+}
+
+func (s *GrpcServer) ListVersions(ctx context.Context, opts *types.DriverOptions) (*VersionList, error) {
+	return s.driver.ListVersions(types.GetCtx(ctx), opts)
+}
+
+func (s *GrpcServer) ListNodePools(ctx context.Context, clusterInfo *types.ClusterInfo) (*NodePoolList, error) {
+	return s.driver.ListNodePools(types.GetCtx(ctx), clusterInfo)
+}
+
+// getCtx returns the context of the call on the stream, on which the reported progress is sent to the stream.
+func (s *GrpcServer) getCtx(stream grpc.ServerStreamingServer[Event]) context.Context {
+	lock := sync.Mutex{}
+	return SetProgress(types.GetCtx(stream.Context()), func(progress *Progress) {
+		lock.Lock()
+		defer lock.Unlock()
+		if err := stream.Send(&Event{Event: &Event_Progress{Progress: progress}}); err != nil {
+			logrus.Debugf("Failed to send progress of driver call: %v", err)
+		}
+	})
+}
+
+func sendResult(stream grpc.ServerStreamingServer[Event], info *types.ClusterInfo, err error) error {
+	if err != nil {
+		return err
+	}
+	if info == nil {
+		info = &types.ClusterInfo{}
+	}
+	return stream.Send(&Event{Event: &Event_Result{Result: info}})
+}
+
+func (s *GrpcServer) register() {
+	s.grpcServer = grpc.NewServer()
+	types.RegisterDriverServer(s.grpcServer, s.v1)
+	RegisterDriverServer(s.grpcServer, s)
+	reflection.Register(s.grpcServer)
+}
+
+// Serve serves a grpc server.  Sends errors to the error channel if they occur
+func (s *GrpcServer) Serve(listenAddr string, errChan chan error) {
+	listen, err := net.Listen("tcp", listenAddr)
+	if err != nil {
+		errChan <- err
+		return
+	}
+	addr := listen.Addr().String()
+	s.address <- addr
+	s.register()
+	logrus.Debugf("RPC GrpcServer listening on address %s", addr)
+	if err := s.grpcServer.Serve(listen); err != nil {
+		errChan <- err
+	}
+}
+
+// ServeOrDie serves a grpc server or kills the process
+func (s *GrpcServer) ServeOrDie(listenAddr string) {
+	listen, err := net.Listen("tcp", listenAddr)
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	addr := listen.Addr().String()
+	s.address <- addr
+	s.register()
+	logrus.Infof("RPC GrpcServer listening on address %s", addr)
+	if err := s.grpcServer.Serve(listen); err != nil {
+		logrus.Fatalf("%v", err)
+	}
+}
+
+func (s *GrpcServer) Stop() {
+	s.grpcServer.Stop()
+}
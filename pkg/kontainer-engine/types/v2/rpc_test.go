@@ -0,0 +1,170 @@
+package v2
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/rancher/rancher/pkg/kontainer-engine/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// testDriver is a v1 driver creating clusters in two steps, and upgrading them by setting their version.
+type testDriver struct {
+	types.Driver
+	createErr error
+	version   string
+}
+
+func (d *testDriver) Create(ctx context.Context, opts *types.DriverOptions, clusterInfo *types.ClusterInfo) (*types.ClusterInfo, error) {
+	ReportProgress(ctx, &Progress{Step: "Creating control plane", Percent: 50, Resources: []*Resource{{Type: "Cluster", Id: "cluster-1"}}})
+	return &types.ClusterInfo{Endpoint: "https://cluster-1"}, d.createErr
+}
+
+func (d *testDriver) SetVersion(ctx context.Context, clusterInfo *types.ClusterInfo, version *types.KubernetesVersion) error {
+	d.version = version.Version
+	return nil
+}
+
+// testV2Driver is a v2 driver upgrading clusters and listing versions.
+type testV2Driver struct {
+	testDriver
+}
+
+func (d *testV2Driver) GetProtocolCapabilities(ctx context.Context) (*Capabilities, error) {
+	return &Capabilities{Upgrade: true, VersionListing: true}, nil
+}
+
+func (d *testV2Driver) Upgrade(ctx context.Context, clusterInfo *types.ClusterInfo, opts *types.DriverOptions, version *types.KubernetesVersion) (*types.ClusterInfo, error) {
+	ReportProgress(ctx, &Progress{Step: "Upgrading control plane", Percent: 30})
+	ReportProgress(ctx, &Progress{Step: "Upgrading node pools", Percent: 60, Warning: true, Message: "node pool np-1 is being drained"})
+	return &types.ClusterInfo{Version: version.Version}, nil
+}
+
+func (d *testV2Driver) ListVersions(ctx context.Context, opts *types.DriverOptions) (*VersionList, error) {
+	return &VersionList{Versions: []string{"1.32.4", "1.33.1"}, Default: "1.33.1"}, nil
+}
+
+func (d *testV2Driver) ListNodePools(ctx context.Context, clusterInfo *types.ClusterInfo) (*NodePoolList, error) {
+	return nil, status.Error(codes.Unimplemented, "not implemented")
+}
+
+// serve serves the v1 protocol only if v1 is set, both protocols otherwise, and returns a client for the server.
+func serve(t *testing.T, driver Driver, v1 bool) CloseableDriver {
+	addr := make(chan string, 1)
+	errChan := make(chan error, 1)
+	if v1 {
+		server := types.NewServer(driver, addr)
+		go server.Serve("127.0.0.1:0", errChan)
+		t.Cleanup(server.Stop)
+	} else {
+		server := NewServer(driver, addr)
+		go server.Serve("127.0.0.1:0", errChan)
+		t.Cleanup(server.Stop)
+	}
+
+	var address string
+	select {
+	case address = <-addr:
+	case err := <-errChan:
+		t.Fatal(err)
+	}
+	client, err := NewClient("test", address)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+	return client
+}
+
+func recordProgress(ctx context.Context) (context.Context, *[]*Progress) {
+	var progress []*Progress
+	return SetProgress(ctx, func(p *Progress) {
+		progress = append(progress, p)
+	}), &progress
+}
+
+func TestCreate(t *testing.T) {
+	t.Run("v2 plugin", func(t *testing.T) {
+		client := serve(t, &testV2Driver{}, false)
+		ctx, progress := recordProgress(context.Background())
+
+		info, err := client.Create(ctx, &types.DriverOptions{}, nil)
+		require.NoError(t, err)
+		assert.Equal(t, "https://cluster-1", info.Endpoint)
+		require.Len(t, *progress, 1)
+		assert.Equal(t, "Creating control plane", (*progress)[0].Step)
+		assert.Equal(t, int32(50), (*progress)[0].Percent)
+		assert.Equal(t, "cluster-1", (*progress)[0].Resources[0].Id)
+	})
+
+	t.Run("v2 plugin create error", func(t *testing.T) {
+		client := serve(t, &testV2Driver{testDriver: testDriver{createErr: errors.New("quota exceeded")}}, false)
+
+		info, err := client.Create(context.Background(), &types.DriverOptions{}, nil)
+		assert.EqualError(t, err, "quota exceeded")
+		require.NotNil(t, info, "the cluster info is returned so that the create can be retried")
+		assert.Equal(t, "https://cluster-1", info.Endpoint)
+	})
+
+	t.Run("v1 plugin", func(t *testing.T) {
+		client := serve(t, FromV1(&testDriver{}), true)
+		ctx, progress := recordProgress(context.Background())
+
+		info, err := client.Create(ctx, &types.DriverOptions{}, nil)
+		require.NoError(t, err)
+		assert.Equal(t, "https://cluster-1", info.Endpoint)
+		require.Len(t, *progress, 2, "the progress reported by a v1 plugin does not reach the client")
+		assert.Equal(t, int32(0), (*progress)[0].Percent)
+		assert.Equal(t, int32(100), (*progress)[1].Percent)
+	})
+}
+
+func TestUpgrade(t *testing.T) {
+	t.Run("v2 plugin", func(t *testing.T) {
+		client := serve(t, &testV2Driver{}, false)
+		ctx, progress := recordProgress(context.Background())
+
+		info, err := client.Upgrade(ctx, &types.ClusterInfo{}, &types.DriverOptions{}, &types.KubernetesVersion{Version: "1.33.1"})
+		require.NoError(t, err)
+		assert.Equal(t, "1.33.1", info.Version)
+		require.Len(t, *progress, 2)
+		assert.True(t, (*progress)[1].Warning)
+		assert.Equal(t, "node pool np-1 is being drained", (*progress)[1].Message)
+	})
+
+	t.Run("v1 plugin", func(t *testing.T) {
+		driver := &testDriver{}
+		client := serve(t, FromV1(driver), true)
+
+		info, err := client.Upgrade(context.Background(), &types.ClusterInfo{Endpoint: "https://cluster-1"}, &types.DriverOptions{}, &types.KubernetesVersion{Version: "1.33.1"})
+		require.NoError(t, err)
+		assert.Equal(t, "1.33.1", info.Version)
+		assert.Equal(t, "https://cluster-1", info.Endpoint)
+		assert.Equal(t, "1.33.1", driver.version)
+	})
+}
+
+func TestCapabilities(t *testing.T) {
+	client := serve(t, &testV2Driver{}, false)
+	capabilities, err := client.GetProtocolCapabilities(context.Background())
+	require.NoError(t, err)
+	assert.True(t, capabilities.VersionListing)
+	assert.False(t, capabilities.NodePools)
+
+	versions, err := client.ListVersions(context.Background(), &types.DriverOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "1.33.1", versions.Default)
+
+	_, err = client.ListNodePools(context.Background(), &types.ClusterInfo{})
+	assert.Equal(t, codes.Unimplemented, status.Code(err))
+
+	v1Client := serve(t, FromV1(&testDriver{}), true)
+	capabilities, err = v1Client.GetProtocolCapabilities(context.Background())
+	require.NoError(t, err)
+	assert.True(t, capabilities.Upgrade)
+	assert.False(t, capabilities.VersionListing)
+	_, err = v1Client.ListVersions(context.Background(), &types.DriverOptions{})
+	assert.Equal(t, codes.Unimplemented, status.Code(err))
+}
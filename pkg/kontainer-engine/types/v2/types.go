@@ -0,0 +1,56 @@
+// Package v2 implements version 2 of the kontainer driver plugin protocol. A v2 plugin serves the v2 Driver service
+// next to the v1 one: the calls changing the cluster stream typed progress events instead of only writing to the log
+// stream, and the plugin declares whether it can upgrade clusters, list Kubernetes versions and list node pools.
+package v2
+
+import (
+	"context"
+
+	"github.com/rancher/rancher/pkg/kontainer-engine/types"
+)
+
+type CloseableDriver interface {
+	Driver
+	Close() error
+}
+
+// Driver defines the interface that each v2 driver plugin should implement. Create and Update of the v1 interface
+// report their progress with ReportProgress.
+type Driver interface {
+	types.Driver
+
+	// GetProtocolCapabilities returns the optional v2 calls the driver supports
+	GetProtocolCapabilities(ctx context.Context) (*Capabilities, error)
+
+	// Upgrade upgrades the Kubernetes version of the cluster
+	Upgrade(ctx context.Context, clusterInfo *types.ClusterInfo, opts *types.DriverOptions, version *types.KubernetesVersion) (*types.ClusterInfo, error)
+
+	// ListVersions returns the Kubernetes versions the driver can create or upgrade clusters to
+	ListVersions(ctx context.Context, opts *types.DriverOptions) (*VersionList, error)
+
+	// ListNodePools returns the node pools of the cluster
+	ListNodePools(ctx context.Context, clusterInfo *types.ClusterInfo) (*NodePoolList, error)
+}
+
+// ProgressFunc receives the progress events of a call changing a cluster.
+type ProgressFunc func(progress *Progress)
+
+type progressKey struct{}
+
+// SetProgress returns a context on which the progress reported by the driver calls is passed to f.
+func SetProgress(ctx context.Context, f ProgressFunc) context.Context {
+	return context.WithValue(ctx, progressKey{}, f)
+}
+
+// GetProgress returns the function receiving the progress reported on the context, or nil.
+func GetProgress(ctx context.Context) ProgressFunc {
+	f, _ := ctx.Value(progressKey{}).(ProgressFunc)
+	return f
+}
+
+// ReportProgress reports the progress of the current call, if the context has a function receiving it.
+func ReportProgress(ctx context.Context, progress *Progress) {
+	if f := GetProgress(ctx); f != nil && progress != nil {
+		f(progress)
+	}
+}
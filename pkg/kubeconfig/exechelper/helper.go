@@ -0,0 +1,334 @@
+// Package exechelper implements rancher-kubeconfig-helper, the client-go exec credential plugin of
+// kubeconfigs generated in the Exec mode.
+//
+// The helper keeps a Rancher login session and exchanges it for short-lived kubeconfig tokens with
+// KubeconfigTokenRequest. Both are cached in files only readable by the user. When the session expires,
+// the user is logged in again with the auth provider the kubeconfig was created with.
+package exechelper
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	ext "github.com/rancher/rancher/pkg/apis/ext.cattle.io/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientauthv1beta1 "k8s.io/client-go/pkg/apis/clientauthentication/v1beta1"
+)
+
+const (
+	// refreshBefore is how long before their expiration cached tokens are replaced.
+	refreshBefore = 30 * time.Second
+	// sessionDescription is the description of the login sessions created by the helper.
+	sessionDescription = "rancher-kubeconfig-helper session"
+
+	tokenRequestPath = "/apis/ext.cattle.io/v1/kubeconfigtokenrequests"
+)
+
+// errUnauthorized is returned when Rancher rejects the session.
+var errUnauthorized = errors.New("unauthorized")
+
+// Config configures a [Helper].
+type Config struct {
+	// Server is the URL of Rancher, e.g. https://rancher.example.com.
+	Server string
+	// Kubeconfig is the name of the Kubeconfig tokens are requested for.
+	Kubeconfig string
+	// Cluster is the name of the ACE cluster tokens are scoped to, if any.
+	Cluster string
+	// AuthProvider is the name of the auth provider used to log in, e.g. local or okta.
+	AuthProvider string
+	// CACerts are the base64 encoded CA certificates of Rancher. The system roots are used if empty.
+	CACerts string
+	// SessionToken is used as the login session instead of logging in, e.g. an API token.
+	SessionToken string
+	// CacheDir is where the session and tokens are cached.
+	// If empty, a directory in the user cache directory specific to the server is used.
+	CacheDir string
+	// In and Out are used to interact with the user when logging in.
+	In  io.Reader
+	Out io.Writer
+}
+
+// Helper issues exec credentials for a kubeconfig.
+type Helper struct {
+	cfg          Config
+	client       *http.Client
+	now          func() time.Time
+	readPassword func() (string, error)
+	pollInterval time.Duration
+}
+
+// cachedToken is a token stored in the cache directory.
+type cachedToken struct {
+	Token string `json:"token"`
+	// ExpiresAt is the zero time if the token doesn't expire.
+	ExpiresAt time.Time `json:"expiresAt,omitempty"`
+}
+
+// New returns a [Helper] for the given configuration.
+func New(cfg Config) (*Helper, error) {
+	if cfg.Server == "" {
+		return nil, errors.New("server is required")
+	}
+	if cfg.Kubeconfig == "" {
+		return nil, errors.New("kubeconfig is required")
+	}
+	server, err := url.Parse(cfg.Server)
+	if err != nil {
+		return nil, fmt.Errorf("invalid server %s: %w", cfg.Server, err)
+	}
+	cfg.Server = strings.TrimSuffix(cfg.Server, "/")
+
+	if cfg.CacheDir == "" {
+		dir, err := os.UserCacheDir()
+		if err != nil {
+			return nil, fmt.Errorf("determining the cache directory: %w", err)
+		}
+		cfg.CacheDir = filepath.Join(dir, "rancher", "kubeconfig-helper", strings.ReplaceAll(server.Host, ":", "_"))
+	}
+	if cfg.In == nil {
+		cfg.In = os.Stdin
+	}
+	if cfg.Out == nil {
+		cfg.Out = os.Stderr
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if cfg.CACerts != "" {
+		pem, err := base64.StdEncoding.DecodeString(cfg.CACerts)
+		if err != nil {
+			return nil, fmt.Errorf("decoding CA certificates: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, errors.New("no valid CA certificates")
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool}
+	}
+
+	return &Helper{
+		cfg:          cfg,
+		client:       &http.Client{Transport: transport, Timeout: 30 * time.Second},
+		now:          time.Now,
+		readPassword: readPassword,
+		pollInterval: 2 * time.Second,
+	}, nil
+}
+
+// Credential returns an exec credential with a valid token, requesting a new one if the cached token
+// is about to expire, and logging in if the session has expired.
+func (h *Helper) Credential(ctx context.Context) (*clientauthv1beta1.ExecCredential, error) {
+	tokenFile := h.cfg.Kubeconfig + ".json"
+	if h.cfg.Cluster != "" {
+		tokenFile = h.cfg.Kubeconfig + "_" + h.cfg.Cluster + ".json"
+	}
+
+	token := h.load(tokenFile)
+	if token == nil {
+		var err error
+		if token, err = h.requestToken(ctx); err != nil {
+			return nil, err
+		}
+		if err := h.store(tokenFile, token); err != nil {
+			return nil, err
+		}
+	}
+
+	status := &clientauthv1beta1.ExecCredentialStatus{Token: token.Token}
+	if !token.ExpiresAt.IsZero() {
+		status.ExpirationTimestamp = &metav1.Time{Time: token.ExpiresAt}
+	}
+	return &clientauthv1beta1.ExecCredential{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: clientauthv1beta1.SchemeGroupVersion.String(),
+			Kind:       "ExecCredential",
+		},
+		Status: status,
+	}, nil
+}
+
+// requestToken requests a kubeconfig token with the session, logging in first if there is no valid session.
+// The user is logged in again if the session is rejected.
+func (h *Helper) requestToken(ctx context.Context) (*cachedToken, error) {
+	const sessionFile = "session.json"
+
+	session := h.load(sessionFile)
+	if h.cfg.SessionToken != "" {
+		session = &cachedToken{Token: h.cfg.SessionToken}
+	}
+	fresh := false
+	if session == nil {
+		var err error
+		if session, err = h.login(ctx); err != nil {
+			return nil, err
+		}
+		fresh = true
+	}
+
+	token, err := h.exchange(ctx, session.Token)
+	if errors.Is(err, errUnauthorized) && !fresh && h.cfg.SessionToken == "" {
+		// The session was revoked or expired early, e.g. because of inactivity.
+		if session, err = h.login(ctx); err != nil {
+			return nil, err
+		}
+		fresh = true
+		token, err = h.exchange(ctx, session.Token)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if fresh {
+		if err := h.store(sessionFile, session); err != nil {
+			return nil, err
+		}
+	}
+	return token, nil
+}
+
+// exchange creates a KubeconfigTokenRequest authenticated with the session.
+func (h *Helper) exchange(ctx context.Context, session string) (*cachedToken, error) {
+	req := ext.KubeconfigTokenRequest{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: ext.SchemeGroupVersion.String(),
+			Kind:       "KubeconfigTokenRequest",
+		},
+		Spec: ext.KubeconfigTokenRequestSpec{
+			KubeconfigName: h.cfg.Kubeconfig,
+			ClusterName:    h.cfg.Cluster,
+		},
+	}
+	var resp ext.KubeconfigTokenRequest
+	if err := h.do(ctx, http.MethodPost, tokenRequestPath, session, req, &resp); err != nil {
+		return nil, fmt.Errorf("requesting token for kubeconfig %s: %w", h.cfg.Kubeconfig, err)
+	}
+	if resp.Status.Token == "" {
+		return nil, fmt.Errorf("no token returned for kubeconfig %s", h.cfg.Kubeconfig)
+	}
+	return &cachedToken{Token: resp.Status.Token, ExpiresAt: resp.Status.ExpirationTimestamp.Time}, nil
+}
+
+// do sends a JSON request to Rancher and decodes the response into out if not nil.
+func (h *Helper) do(ctx context.Context, method, path, bearer string, in, out any) error {
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, h.cfg.Server+path, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if bearer != "" {
+		req.Header.Set("Authorization", "Bearer "+bearer)
+	}
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode == http.StatusUnauthorized {
+		return errUnauthorized
+	}
+	if resp.StatusCode >= 300 {
+		return &statusError{code: resp.StatusCode, message: errorMessage(data)}
+	}
+	if out == nil {
+		return nil
+	}
+	return json.Unmarshal(data, out)
+}
+
+// statusError is returned for unexpected HTTP status codes.
+type statusError struct {
+	code    int
+	message string
+}
+
+func (e *statusError) Error() string {
+	if e.message == "" {
+		return http.StatusText(e.code)
+	}
+	return fmt.Sprintf("%s: %s", http.StatusText(e.code), e.message)
+}
+
+// errorMessage extracts the message of a Kubernetes status or Rancher API error.
+func errorMessage(data []byte) string {
+	var apiErr struct {
+		Message string `json:"message"`
+	}
+	if err := json.Unmarshal(data, &apiErr); err == nil {
+		return apiErr.Message
+	}
+	return ""
+}
+
+// load returns the cached token if it is valid for longer than refreshBefore.
+func (h *Helper) load(name string) *cachedToken {
+	data, err := os.ReadFile(filepath.Join(h.cfg.CacheDir, name))
+	if err != nil {
+		return nil
+	}
+	var token cachedToken
+	if err := json.Unmarshal(data, &token); err != nil || token.Token == "" {
+		return nil
+	}
+	if !token.ExpiresAt.IsZero() && token.ExpiresAt.Sub(h.now()) < refreshBefore {
+		return nil
+	}
+	return &token
+}
+
+// store writes the token to the cache directory, readable only by the user.
+func (h *Helper) store(name string, token *cachedToken) error {
+	if err := os.MkdirAll(h.cfg.CacheDir, 0o700); err != nil {
+		return fmt.Errorf("creating cache directory: %w", err)
+	}
+	data, err := json.Marshal(token)
+	if err != nil {
+		return err
+	}
+	// Write to a temporary file first, so that concurrent invocations never read a partial token.
+	tmp, err := os.CreateTemp(h.cfg.CacheDir, name+".*")
+	if err != nil {
+		return fmt.Errorf("caching token: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("caching token: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("caching token: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), filepath.Join(h.cfg.CacheDir, name)); err != nil {
+		return fmt.Errorf("caching token: %w", err)
+	}
+	return nil
+}
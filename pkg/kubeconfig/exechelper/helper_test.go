@@ -0,0 +1,243 @@
+package exechelper
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	ext "github.com/rancher/rancher/pkg/apis/ext.cattle.io/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// fakeRancher serves the endpoints used by the helper.
+type fakeRancher struct {
+	mu       sync.Mutex
+	sessions map[string]bool
+	logins   []map[string]string
+	requests []ext.KubeconfigTokenRequest
+	// out is where the helper prints the browser login URL.
+	out        *syncBuffer
+	authPolled int
+	now        time.Time
+}
+
+func (f *fakeRancher) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch {
+	case r.Method == http.MethodPost && r.URL.Path == loginPath:
+		var login map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&login)
+		f.logins = append(f.logins, login)
+		if login["password"] != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		f.sessions["token-login:key"] = true
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"token":     "token-login:key",
+			"expiresAt": f.now.Add(16 * time.Hour).Format(time.RFC3339),
+		})
+	case r.Method == http.MethodPost && r.URL.Path == tokenRequestPath:
+		if !f.sessions[strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")] {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		var req ext.KubeconfigTokenRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		f.requests = append(f.requests, req)
+		req.Status.Token = "ext/token-exec:key"
+		req.Status.ExpirationTimestamp = metav1.NewTime(f.now.Add(10 * time.Minute))
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(req)
+	case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, authTokensPath):
+		f.authPolled++
+		if f.authPolled == 1 {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		// Encrypt the session with the public key from the login URL like Rancher does.
+		loginURL, err := url.Parse(regexp.MustCompile(`https://\S+`).FindString(f.out.String()))
+		if err != nil || loginURL.Query().Get("requestId") != strings.TrimPrefix(r.URL.Path, authTokensPath) {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		keyBytes, _ := base64.StdEncoding.DecodeString(loginURL.Query().Get("publicKey"))
+		publicKey := &rsa.PublicKey{}
+		_ = json.Unmarshal(keyBytes, publicKey)
+		encrypted, _ := rsa.EncryptOAEP(sha256.New(), rand.Reader, publicKey, []byte("kubeconfig-u-abcde:key"), nil)
+		f.sessions["kubeconfig-u-abcde:key"] = true
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"token":     base64.StdEncoding.EncodeToString(encrypted),
+			"expiresAt": f.now.Add(time.Hour).Format(time.RFC3339),
+		})
+	case r.Method == http.MethodDelete && strings.HasPrefix(r.URL.Path, authTokensPath):
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func newHelper(t *testing.T, rancher *fakeRancher, cfg Config) *Helper {
+	server := httptest.NewTLSServer(rancher)
+	t.Cleanup(server.Close)
+
+	cfg.Server = server.URL
+	cfg.CACerts = base64.StdEncoding.EncodeToString(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}))
+	cfg.Out = rancher.out
+	if cfg.CacheDir == "" {
+		cfg.CacheDir = t.TempDir()
+	}
+	if cfg.In == nil {
+		cfg.In = strings.NewReader("admin\n")
+	}
+	h, err := New(cfg)
+	require.NoError(t, err)
+	h.now = func() time.Time { return rancher.now }
+	h.readPassword = func() (string, error) { return "secret", nil }
+	h.pollInterval = time.Millisecond
+	return h
+}
+
+func newFakeRancher() *fakeRancher {
+	return &fakeRancher{
+		sessions: map[string]bool{},
+		out:      &syncBuffer{},
+		now:      time.Now().Truncate(time.Second),
+	}
+}
+
+func TestCredentialPasswordLogin(t *testing.T) {
+	rancher := newFakeRancher()
+	h := newHelper(t, rancher, Config{Kubeconfig: "kubeconfig-abcde", Cluster: "c-m-abcde", AuthProvider: "activedirectory"})
+
+	credential, err := h.Credential(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "client.authentication.k8s.io/v1beta1", credential.APIVersion)
+	assert.Equal(t, "ExecCredential", credential.Kind)
+	assert.Equal(t, "ext/token-exec:key", credential.Status.Token)
+	assert.Equal(t, rancher.now.Add(10*time.Minute), credential.Status.ExpirationTimestamp.Time)
+
+	require.Len(t, rancher.logins, 1)
+	assert.Equal(t, "activeDirectoryProvider", rancher.logins[0]["type"])
+	assert.Equal(t, "admin", rancher.logins[0]["username"])
+	assert.Equal(t, "json", rancher.logins[0]["responseType"])
+	require.Len(t, rancher.requests, 1)
+	assert.Equal(t, ext.KubeconfigTokenRequestSpec{KubeconfigName: "kubeconfig-abcde", ClusterName: "c-m-abcde"}, rancher.requests[0].Spec)
+
+	for _, name := range []string{"session.json", "kubeconfig-abcde_c-m-abcde.json"} {
+		info, err := os.Stat(filepath.Join(h.cfg.CacheDir, name))
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+	}
+
+	// The cached token is used until it is about to expire.
+	_, err = h.Credential(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, rancher.requests, 1)
+
+	rancher.now = rancher.now.Add(10*time.Minute - 10*time.Second)
+	credential, err = h.Credential(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, rancher.now.Add(10*time.Minute), credential.Status.ExpirationTimestamp.Time)
+	assert.Len(t, rancher.requests, 2)
+	assert.Len(t, rancher.logins, 1)
+}
+
+func TestCredentialSessionRejected(t *testing.T) {
+	rancher := newFakeRancher()
+	cacheDir := t.TempDir()
+	data, err := json.Marshal(cachedToken{Token: "token-revoked:key"})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(cacheDir, "session.json"), data, 0o600))
+
+	h := newHelper(t, rancher, Config{Kubeconfig: "kubeconfig-abcde", CacheDir: cacheDir})
+
+	credential, err := h.Credential(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "ext/token-exec:key", credential.Status.Token)
+	require.Len(t, rancher.logins, 1)
+	assert.Equal(t, "localProvider", rancher.logins[0]["type"])
+
+	session := h.load("session.json")
+	require.NotNil(t, session)
+	assert.Equal(t, "token-login:key", session.Token)
+}
+
+func TestCredentialLoginFailed(t *testing.T) {
+	rancher := newFakeRancher()
+	h := newHelper(t, rancher, Config{Kubeconfig: "kubeconfig-abcde"})
+	h.readPassword = func() (string, error) { return "wrong", nil }
+
+	_, err := h.Credential(context.Background())
+	assert.ErrorContains(t, err, "invalid username or password")
+	assert.Empty(t, rancher.requests)
+	assert.NoFileExists(t, filepath.Join(h.cfg.CacheDir, "session.json"))
+}
+
+func TestCredentialBrowserLogin(t *testing.T) {
+	rancher := newFakeRancher()
+	h := newHelper(t, rancher, Config{Kubeconfig: "kubeconfig-abcde", AuthProvider: "okta"})
+
+	credential, err := h.Credential(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "ext/token-exec:key", credential.Status.Token)
+	assert.Empty(t, rancher.logins)
+	assert.Equal(t, 2, rancher.authPolled)
+	assert.Contains(t, rancher.out.String(), "/dashboard/auth/login?")
+
+	session := h.load("session.json")
+	require.NotNil(t, session)
+	assert.Equal(t, "kubeconfig-u-abcde:key", session.Token)
+	assert.Equal(t, rancher.now.Add(time.Hour), session.ExpiresAt.Local())
+}
+
+func TestCredentialUnsupportedProvider(t *testing.T) {
+	rancher := newFakeRancher()
+	h := newHelper(t, rancher, Config{Kubeconfig: "kubeconfig-abcde", AuthProvider: "github"})
+
+	_, err := h.Credential(context.Background())
+	assert.ErrorContains(t, err, "not supported")
+
+	rancher.sessions["token-api:key"] = true
+	h.cfg.SessionToken = "token-api:key"
+	credential, err := h.Credential(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "ext/token-exec:key", credential.Status.Token)
+	assert.NoFileExists(t, filepath.Join(h.cfg.CacheDir, "session.json"))
+}
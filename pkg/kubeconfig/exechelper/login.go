@@ -0,0 +1,183 @@
+package exechelper
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/term"
+)
+
+const (
+	// browserLoginTimeout is how long the user has to log in with the browser.
+	browserLoginTimeout = 5 * time.Minute
+
+	defaultAuthProvider = "local"
+	loginPath           = "/v1-public/login"
+	authTokensPath      = "/v1-public/authtokens/"
+)
+
+// passwordProviders maps the auth providers users log in to with a username and password to their login type.
+var passwordProviders = map[string]string{
+	"local":           "localProvider",
+	"activedirectory": "activeDirectoryProvider",
+	"openldap":        "openLdapProvider",
+	"freeipa":         "freeIpaProvider",
+}
+
+// browserProviders are the auth providers users log in to with the browser.
+var browserProviders = map[string]bool{
+	"ping":       true,
+	"adfs":       true,
+	"keycloak":   true,
+	"okta":       true,
+	"shibboleth": true,
+}
+
+// loginResponse is the response of the login endpoint and of the auth tokens of browser logins.
+type loginResponse struct {
+	Token     string `json:"token"`
+	ExpiresAt string `json:"expiresAt"`
+}
+
+func (r *loginResponse) toCachedToken() (*cachedToken, error) {
+	token := &cachedToken{Token: r.Token}
+	if r.ExpiresAt != "" {
+		expiresAt, err := time.Parse(time.RFC3339, r.ExpiresAt)
+		if err != nil {
+			return nil, fmt.Errorf("invalid session expiration %s: %w", r.ExpiresAt, err)
+		}
+		token.ExpiresAt = expiresAt
+	}
+	return token, nil
+}
+
+// login creates a new session with the auth provider of the kubeconfig.
+func (h *Helper) login(ctx context.Context) (*cachedToken, error) {
+	provider := h.cfg.AuthProvider
+	if provider == "" {
+		provider = defaultAuthProvider
+	}
+	if loginType, ok := passwordProviders[provider]; ok {
+		return h.passwordLogin(ctx, loginType)
+	}
+	if browserProviders[provider] {
+		return h.browserLogin(ctx)
+	}
+	return nil, fmt.Errorf("logging in with auth provider %s is not supported, set RANCHER_TOKEN to an API token instead", provider)
+}
+
+// passwordLogin prompts for a username and password and logs in with them.
+func (h *Helper) passwordLogin(ctx context.Context, loginType string) (*cachedToken, error) {
+	fmt.Fprintf(h.cfg.Out, "Log in to %s\nUsername: ", h.cfg.Server)
+	username, err := bufio.NewReader(h.cfg.In).ReadString('\n')
+	if err != nil && username == "" {
+		return nil, fmt.Errorf("reading username: %w", err)
+	}
+	fmt.Fprint(h.cfg.Out, "Password: ")
+	password, err := h.readPassword()
+	fmt.Fprintln(h.cfg.Out)
+	if err != nil {
+		return nil, fmt.Errorf("reading password: %w", err)
+	}
+
+	login := map[string]string{
+		"type":         loginType,
+		"username":     strings.TrimSpace(username),
+		"password":     password,
+		"responseType": "json",
+		"description":  sessionDescription,
+	}
+	var resp loginResponse
+	if err := h.do(ctx, http.MethodPost, loginPath, "", login, &resp); err != nil {
+		if errors.Is(err, errUnauthorized) {
+			return nil, errors.New("login failed: invalid username or password")
+		}
+		return nil, fmt.Errorf("login failed: %w", err)
+	}
+	return resp.toCachedToken()
+}
+
+// browserLogin asks the user to log in with the browser, then waits for Rancher to hand over the session
+// encrypted with a key only known to the helper.
+func (h *Helper) browserLogin(ctx context.Context) (*cachedToken, error) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, err
+	}
+	publicKey, err := json.Marshal(key.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return nil, err
+	}
+	requestID := hex.EncodeToString(id)
+
+	query := url.Values{
+		"requestId":    {requestID},
+		"publicKey":    {base64.StdEncoding.EncodeToString(publicKey)},
+		"responseType": {"kubeconfig"},
+	}
+	fmt.Fprintf(h.cfg.Out, "Log in to Rancher with your browser at:\n%s/dashboard/auth/login?%s\n", h.cfg.Server, query.Encode())
+
+	ctx, cancel := context.WithTimeout(ctx, browserLoginTimeout)
+	defer cancel()
+	ticker := time.NewTicker(h.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		var resp loginResponse
+		err := h.do(ctx, http.MethodGet, authTokensPath+requestID, "", nil, &resp)
+		var statusErr *statusError
+		switch {
+		case err == nil && resp.Token != "":
+			// The auth token is only needed once.
+			_ = h.do(ctx, http.MethodDelete, authTokensPath+requestID, "", nil, nil)
+
+			encrypted, err := base64.StdEncoding.DecodeString(resp.Token)
+			if err != nil {
+				return nil, fmt.Errorf("decoding session: %w", err)
+			}
+			decrypted, err := rsa.DecryptOAEP(sha256.New(), rand.Reader, key, encrypted, nil)
+			if err != nil {
+				return nil, fmt.Errorf("decrypting session: %w", err)
+			}
+			resp.Token = string(decrypted)
+			return resp.toCachedToken()
+		case err == nil, errors.As(err, &statusErr) && statusErr.code == http.StatusNotFound:
+			// The user hasn't logged in yet.
+		default:
+			return nil, fmt.Errorf("waiting for login: %w", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, errors.New("timed out waiting for login")
+		case <-ticker.C:
+		}
+	}
+}
+
+// readPassword reads a password from the terminal without echoing it.
+func readPassword() (string, error) {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return "", errors.New("no terminal to prompt for the password")
+	}
+	password, err := term.ReadPassword(fd)
+	return string(password), err
+}
@@ -51,6 +51,10 @@ type User struct {
 	Token     string
 	Host      string
 	ClusterID string
+	// Kubeconfig is the name of the Kubeconfig the rancher-kubeconfig-helper credential plugin requests tokens for.
+	Kubeconfig   string
+	AuthProvider string
+	CACerts      string
 }
 
 type Context struct {
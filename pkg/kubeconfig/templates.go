@@ -72,6 +72,25 @@
   user:
 {{- if .Token }}
     token: "{{.Token}}"
+{{ else if ne .Kubeconfig "" }}
+    exec:
+      apiVersion: client.authentication.k8s.io/v1beta1
+      args:
+        - --server=https://{{.Host}}
+        - --kubeconfig={{.Kubeconfig}}
+{{- if ne .AuthProvider "" }}
+        - --auth-provider={{.AuthProvider}}
+{{- end }}
+{{- if ne .ClusterID "" }}
+        - --cluster={{.ClusterID}}
+{{- end }}
+      command: rancher-kubeconfig-helper
+{{- if ne .CACerts "" }}
+      env:
+        - name: RANCHER_CACERTS
+          value: "{{.CACerts}}"
+{{- end }}
+      interactiveMode: IfAvailable
 {{ else }}
     exec:
       apiVersion: client.authentication.k8s.io/v1beta1
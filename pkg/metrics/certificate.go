@@ -0,0 +1,40 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var certificateExpiration = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Subsystem: "cluster_manager",
+		Name:      "node_certificate_expiration_timestamp_seconds",
+		Help:      "Expiration date of the first certificate to expire of each type on the nodes of RKE2/K3s clusters",
+	},
+	[]string{"cluster_namespace", "cluster_name", "machine", "type"},
+)
+
+// SetCertificateExpiration records the expiration date of the first certificate of the given type to expire on a
+// machine.
+func SetCertificateExpiration(namespace, cluster, machine, certType string, expiration time.Time) {
+	if prometheusMetrics {
+		certificateExpiration.With(
+			prometheus.Labels{
+				"cluster_namespace": namespace,
+				"cluster_name":      cluster,
+				"machine":           machine,
+				"type":              certType,
+			}).Set(float64(expiration.Unix()))
+	}
+}
+
+// DeleteCertificateExpiration removes the certificate expiration dates recorded for all machines of a cluster.
+func DeleteCertificateExpiration(namespace, cluster string) {
+	if prometheusMetrics {
+		certificateExpiration.DeletePartialMatch(prometheus.Labels{
+			"cluster_namespace": namespace,
+			"cluster_name":      cluster,
+		})
+	}
+}
@@ -49,6 +49,16 @@ func Register(ctx context.Context, scaledContext *config.ScaledContext) {
 	prometheus.MustRegister(numNodes)
 	prometheus.MustRegister(numCores)
 
+	// node certificate expiration metrics
+	prometheus.MustRegister(certificateExpiration)
+
+	// readiness metrics
+	prometheus.MustRegister(readinessCheck)
+	prometheus.MustRegister(readinessPeers)
+	prometheus.MustRegister(readinessPeerReady)
+	prometheus.MustRegister(readinessLeader)
+	prometheus.MustRegister(readinessTunnelSessions)
+
 	gc := metricGarbageCollector{
 		clusterLister:  scaledContext.Management.Clusters("").Controller().Lister(),
 		nodeLister:     scaledContext.Management.Nodes("").Controller().Lister(),
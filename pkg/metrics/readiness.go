@@ -0,0 +1,84 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	readinessCheck = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Subsystem: "readiness",
+			Name:      "check_status",
+			Help:      "Result of each check of the /readyz endpoint, 1 if it passed and 0 if it failed",
+		},
+		[]string{"check"},
+	)
+
+	readinessPeers = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Subsystem: "readiness",
+			Name:      "peers",
+			Help:      "Number of Rancher server peers known to the peer manager",
+		},
+	)
+
+	readinessPeerReady = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Subsystem: "readiness",
+			Name:      "peer_ready",
+			Help:      "Whether the Rancher server is a ready endpoint of the peer service, 1 if it is and 0 otherwise",
+		},
+	)
+
+	readinessLeader = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Subsystem: "readiness",
+			Name:      "leader",
+			Help:      "Whether the Rancher server holds the controllers leader election lease, 1 if it does and 0 otherwise",
+		},
+	)
+
+	readinessTunnelSessions = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Subsystem: "readiness",
+			Name:      "tunnel_sessions",
+			Help:      "Number of clients with a remotedialer session to the Rancher server",
+		},
+	)
+)
+
+// SetReadinessCheck records the result of a check of the /readyz endpoint.
+func SetReadinessCheck(check string, passed bool) {
+	if prometheusMetrics {
+		readinessCheck.With(prometheus.Labels{"check": check}).Set(boolToFloat(passed))
+	}
+}
+
+// SetReadinessPeers records the state of the peer manager.
+func SetReadinessPeers(peers int, ready bool) {
+	if prometheusMetrics {
+		readinessPeers.Set(float64(peers))
+		readinessPeerReady.Set(boolToFloat(ready))
+	}
+}
+
+// SetReadinessLeader records whether the Rancher server is the leader.
+func SetReadinessLeader(leader bool) {
+	if prometheusMetrics {
+		readinessLeader.Set(boolToFloat(leader))
+	}
+}
+
+// SetReadinessTunnelSessions records the number of remotedialer sessions.
+func SetReadinessTunnelSessions(sessions int) {
+	if prometheusMetrics {
+		readinessTunnelSessions.Set(float64(sessions))
+	}
+}
+
+func boolToFloat(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -16,6 +16,7 @@
 	"github.com/rancher/rancher/pkg/api/norman/customization/oci"
 	"github.com/rancher/rancher/pkg/api/norman/customization/vsphere"
 	managementapi "github.com/rancher/rancher/pkg/api/norman/server"
+	"github.com/rancher/rancher/pkg/api/steve/supportbundles"
 	"github.com/rancher/rancher/pkg/api/steve/supportconfigs"
 	"github.com/rancher/rancher/pkg/auth/logout"
 	"github.com/rancher/rancher/pkg/auth/providers/publicapi"
@@ -189,6 +190,7 @@ func router(ctx context.Context, localClusterEnabled bool, scaledContext *config
 	authed.Handle("GET /meta/vsphere/{field}", authedMW(vsphere.NewVsphereHandler(scaledContext)))
 	authed.Handle("POST /v3/tokenreview", authedMW(&webhook.TokenReviewer{}))
 	authed.Handle(supportconfigs.Endpoint, authedMW(&supportConfigGenerator))
+	authed.Handle(supportbundles.Endpoint, authedMW(supportbundles.NewHandler(scaledContext)))
 	authed.Handle("/meta/proxy/", authedMW(metaProxy))
 	authed.Handle("/v3/", authedMW(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if strings.HasPrefix(r.URL.Path, "/v3/identit") || strings.HasPrefix(r.URL.Path, "/v3/token") {
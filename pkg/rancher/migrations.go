@@ -72,6 +72,24 @@
 
 var (
 	mgmtNameRegexp = regexp.MustCompile("^(c-[a-z0-9]{5}|local)$")
+
+	// migrationConfigMaps are the config maps recording the migrations that ran. They are included in management
+	// backups, so that restoring a snapshot that predates a migration makes it run again.
+	migrationConfigMaps = []string{
+		forceUpgradeLogoutConfig,
+		forceLocalSystemAndDefaultProjectCreation,
+		forceSystemNamespacesAssignment,
+		migrateFromMachineToPlanSecret,
+		migrateEncryptionKeyRotationLeaderToStatus,
+		migrateDynamicSchemaToMachinePools,
+		migrateSystemAgentVarDirToDataDirectory,
+		migrateImportedClusterManagedFields,
+		rkeCleanupMigration,
+		managementNodeCleanupMigration,
+		cleanupProvisioningClusterMgmtOnlyConditions,
+		migrateCRTTokensToSecrets,
+		migrateRKE2PrimeAnnotation,
+	}
 )
 
 // tokenCollectionDeleter abstracts the interface to bulk deletion for tokens,
@@ -321,7 +321,7 @@ func New(ctx context.Context, clientConfg clientcmd.ClientConfig, opts *Options)
 	}
 
 	additionalAPIPreMCM := steveapi.AdditionalAPIsPreMCM(wranglerContext)
-	additionalAPI, err := steveapi.AdditionalAPIs(ctx, wranglerContext, steve)
+	additionalAPI, err := steveapi.AdditionalAPIs(ctx, wranglerContext, sc.ControllerFactory, steve)
 	if err != nil {
 		return nil, err
 	}
@@ -498,8 +498,7 @@ func (r *Rancher) Start(ctx context.Context) error {
 		}
 
 		if err := r.Wrangler.StartFactoryWithTransaction(ctx, func(ctx context.Context) error {
-			dashboard.RegisterPostMigration(ctx, r.Wrangler)
-			return nil
+			return dashboard.RegisterPostMigration(ctx, r.Wrangler, migrationConfigMaps)
 		}); err != nil {
 			return errors.New("dashboard.RegisterPostMigration() failed: " + err.Error())
 		}
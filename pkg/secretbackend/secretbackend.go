@@ -0,0 +1,218 @@
+// Package secretbackend stores the data of Kubernetes secrets holding credentials in an external backend.
+//
+// A secret stored in a backend keeps its keys with empty values, so that the type of credential it holds can still be
+// inferred from it, and is annotated with the backend and the path its data is stored at. Readers resolve such
+// references with Resolve or Data, which return the secret data unchanged when it is not stored in a backend.
+package secretbackend
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/rancher/rancher/pkg/settings"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// BackendAnnotation is the name of the backend the data of the secret is stored in.
+	BackendAnnotation = "secrets.cattle.io/backend"
+	// PathAnnotation is the path the data of the secret is stored at in the backend.
+	PathAnnotation = "secrets.cattle.io/backend-path"
+	// CleanupFinalizer is set on secrets stored in a backend so that their data is deleted from the backend with them.
+	CleanupFinalizer = "secrets.cattle.io/backend-cleanup"
+
+	// Vault is the name of the HashiCorp Vault KV version 2 backend.
+	Vault = "vault"
+)
+
+// ErrNotFound is returned by backends when no data is stored at a path.
+var ErrNotFound = errors.New("secret not found in backend")
+
+// Backend stores the data of secrets.
+type Backend interface {
+	// Name returns the name of the backend, as set in the BackendAnnotation of the secrets it stores.
+	Name() string
+	// Read returns the data stored at the path, or ErrNotFound.
+	Read(ctx context.Context, path string) (map[string][]byte, error)
+	// Write replaces the data stored at the path.
+	Write(ctx context.Context, path string, data map[string][]byte) error
+	// Delete deletes the data stored at the path. It does not fail if there is no data at the path.
+	Delete(ctx context.Context, path string) error
+}
+
+// Config is the configuration of a backend.
+type Config struct {
+	Backend         string
+	VaultAddress    string
+	VaultKVMount    string
+	VaultPathPrefix string
+	VaultAuthMount  string
+	VaultAuthRole   string
+	VaultCACerts    string
+}
+
+// ConfigFromSettings returns the backend configuration from the secret-backend settings.
+func ConfigFromSettings() Config {
+	return Config{
+		Backend:         settings.SecretBackend.Get(),
+		VaultAddress:    settings.SecretBackendVaultAddress.Get(),
+		VaultKVMount:    settings.SecretBackendVaultKVMount.Get(),
+		VaultPathPrefix: settings.SecretBackendVaultPathPrefix.Get(),
+		VaultAuthMount:  settings.SecretBackendVaultAuthMount.Get(),
+		VaultAuthRole:   settings.SecretBackendVaultAuthRole.Get(),
+		VaultCACerts:    settings.SecretBackendVaultCACerts.Get(),
+	}
+}
+
+// New returns the backend for the configuration, or nil if secrets are kept in Kubernetes.
+func New(config Config) (Backend, error) {
+	switch config.Backend {
+	case "":
+		return nil, nil
+	case Vault:
+		return newVault(config)
+	}
+	return nil, fmt.Errorf("unsupported secret backend %q", config.Backend)
+}
+
+var (
+	configuredLock   sync.Mutex
+	configuredConfig Config
+	configured       Backend
+)
+
+// Configured returns the backend configured by the secret-backend settings, or nil if secrets are kept in Kubernetes.
+// The backend is reused as long as the settings do not change, so that it can cache its credentials.
+func Configured() (Backend, error) {
+	config := ConfigFromSettings()
+
+	configuredLock.Lock()
+	defer configuredLock.Unlock()
+	if configured != nil && config == configuredConfig {
+		return configured, nil
+	}
+	backend, err := New(config)
+	if err != nil {
+		return nil, err
+	}
+	configuredConfig, configured = config, backend
+	return backend, nil
+}
+
+// IsReference returns whether the data of the secret is stored in a backend.
+func IsReference(secret *corev1.Secret) bool {
+	return secret != nil && secret.Annotations[BackendAnnotation] != ""
+}
+
+// Store writes the data of the secret to the backend and returns a copy of the secret holding a reference to it.
+// StringData is merged into Data, as the API server would.
+func Store(ctx context.Context, backend Backend, secret *corev1.Secret) (*corev1.Secret, error) {
+	data := map[string][]byte{}
+	for k, v := range secret.Data {
+		data[k] = v
+	}
+	for k, v := range secret.StringData {
+		data[k] = []byte(v)
+	}
+
+	path := secret.Annotations[PathAnnotation]
+	if path == "" {
+		path = secret.Namespace + "/" + secret.Name
+	}
+	if err := backend.Write(ctx, path, data); err != nil {
+		return nil, fmt.Errorf("failed to store secret %s/%s in the %s secret backend: %w", secret.Namespace, secret.Name, backend.Name(), err)
+	}
+
+	secret = secret.DeepCopy()
+	secret.StringData = nil
+	secret.Data = make(map[string][]byte, len(data))
+	for k := range data {
+		secret.Data[k] = []byte{}
+	}
+	if secret.Annotations == nil {
+		secret.Annotations = map[string]string{}
+	}
+	secret.Annotations[BackendAnnotation] = backend.Name()
+	secret.Annotations[PathAnnotation] = path
+	if !slices.Contains(secret.Finalizers, CleanupFinalizer) {
+		secret.Finalizers = append(secret.Finalizers, CleanupFinalizer)
+	}
+	return secret, nil
+}
+
+// Data returns the data of the secret, reading it from the backend it is stored in.
+func Data(ctx context.Context, secret *corev1.Secret) (map[string][]byte, error) {
+	if !IsReference(secret) {
+		return secret.Data, nil
+	}
+	backend, err := backendFor(secret)
+	if err != nil {
+		return nil, err
+	}
+	data, err := backend.Read(ctx, secret.Annotations[PathAnnotation])
+	if err != nil {
+		return nil, fmt.Errorf("failed to read secret %s/%s from the %s secret backend: %w", secret.Namespace, secret.Name, backend.Name(), err)
+	}
+	return data, nil
+}
+
+// Resolve returns the secret with the data read from the backend it is stored in. The secret is returned unchanged
+// when it is not stored in a backend.
+func Resolve(ctx context.Context, secret *corev1.Secret) (*corev1.Secret, error) {
+	if !IsReference(secret) {
+		return secret, nil
+	}
+	data, err := Data(ctx, secret)
+	if err != nil {
+		return nil, err
+	}
+	secret = secret.DeepCopy()
+	secret.Data = data
+	return secret, nil
+}
+
+// Restore returns a copy of the secret holding its data again instead of a reference to the backend. The data is not
+// deleted from the backend.
+func Restore(ctx context.Context, secret *corev1.Secret) (*corev1.Secret, error) {
+	secret, err := Resolve(ctx, secret)
+	if err != nil {
+		return nil, err
+	}
+	secret = secret.DeepCopy()
+	delete(secret.Annotations, BackendAnnotation)
+	delete(secret.Annotations, PathAnnotation)
+	secret.Finalizers = slices.DeleteFunc(secret.Finalizers, func(s string) bool {
+		return s == CleanupFinalizer
+	})
+	return secret, nil
+}
+
+// Delete deletes the data of the secret from the backend it is stored in.
+func Delete(ctx context.Context, secret *corev1.Secret) error {
+	if !IsReference(secret) {
+		return nil
+	}
+	backend, err := backendFor(secret)
+	if err != nil {
+		return err
+	}
+	if err := backend.Delete(ctx, secret.Annotations[PathAnnotation]); err != nil {
+		return fmt.Errorf("failed to delete secret %s/%s from the %s secret backend: %w", secret.Namespace, secret.Name, backend.Name(), err)
+	}
+	return nil
+}
+
+func backendFor(secret *corev1.Secret) (Backend, error) {
+	name := secret.Annotations[BackendAnnotation]
+	backend, err := Configured()
+	if err != nil {
+		return nil, err
+	}
+	if backend == nil || backend.Name() != name {
+		return nil, fmt.Errorf("secret %s/%s is stored in the %s secret backend, which is not configured", secret.Namespace, secret.Name, name)
+	}
+	return backend, nil
+}
@@ -0,0 +1,116 @@
+package secretbackend
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rancher/rancher/pkg/settings"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func configureVault(t *testing.T, address string) {
+	t.Setenv(vaultTokenEnv, "root")
+	require.NoError(t, settings.SecretBackend.Set(Vault))
+	require.NoError(t, settings.SecretBackendVaultAddress.Set(address))
+	t.Cleanup(func() {
+		_ = settings.SecretBackend.Set("")
+		_ = settings.SecretBackendVaultAddress.Set("")
+	})
+}
+
+func TestConfigured(t *testing.T) {
+	backend, err := Configured()
+	require.NoError(t, err)
+	assert.Nil(t, backend, "secrets should be kept in Kubernetes by default")
+
+	configureVault(t, "https://vault.example.com")
+	backend, err = Configured()
+	require.NoError(t, err)
+	assert.Equal(t, Vault, backend.Name())
+	again, err := Configured()
+	require.NoError(t, err)
+	assert.Same(t, backend, again, "the backend should be reused while the settings do not change")
+
+	require.NoError(t, settings.SecretBackend.Set("unknown"))
+	_, err = Configured()
+	assert.Error(t, err)
+}
+
+func TestStoreAndRestore(t *testing.T) {
+	f, server := newFakeVault(t)
+	configureVault(t, server.URL)
+	backend, err := Configured()
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "cc-abc", Namespace: "cattle-global-data", Finalizers: []string{"other"}},
+		Data:       map[string][]byte{"amazonec2credentialConfig-accessKey": []byte("access")},
+		StringData: map[string]string{"amazonec2credentialConfig-secretKey": "secret"},
+	}
+	stored, err := Store(ctx, backend, secret)
+	require.NoError(t, err)
+
+	assert.True(t, IsReference(stored))
+	assert.False(t, IsReference(secret), "the secret should not be modified")
+	assert.Equal(t, "cattle-global-data/cc-abc", stored.Annotations[PathAnnotation])
+	assert.Equal(t, []string{"other", CleanupFinalizer}, stored.Finalizers)
+	assert.Nil(t, stored.StringData)
+	assert.Equal(t, map[string][]byte{
+		"amazonec2credentialConfig-accessKey": {},
+		"amazonec2credentialConfig-secretKey": {},
+	}, stored.Data, "the keys of the secret should be kept without their values")
+	expected := map[string][]byte{
+		"amazonec2credentialConfig-accessKey": []byte("access"),
+		"amazonec2credentialConfig-secretKey": []byte("secret"),
+	}
+
+	data, err := Data(ctx, stored)
+	require.NoError(t, err)
+	assert.Equal(t, expected, data)
+
+	resolved, err := Resolve(ctx, stored)
+	require.NoError(t, err)
+	assert.Equal(t, expected, resolved.Data)
+
+	restored, err := Restore(ctx, stored)
+	require.NoError(t, err)
+	assert.False(t, IsReference(restored))
+	assert.Equal(t, expected, restored.Data)
+	assert.Equal(t, []string{"other"}, restored.Finalizers)
+	assert.NotContains(t, restored.Annotations, PathAnnotation)
+
+	require.NoError(t, Delete(ctx, stored))
+	assert.Empty(t, f.secrets)
+}
+
+func TestDataNotStoredInBackend(t *testing.T) {
+	secret := &corev1.Secret{Data: map[string][]byte{"key": []byte("value")}}
+
+	data, err := Data(context.Background(), secret)
+	require.NoError(t, err)
+	assert.Equal(t, secret.Data, data)
+
+	resolved, err := Resolve(context.Background(), secret)
+	require.NoError(t, err)
+	assert.Same(t, secret, resolved)
+
+	assert.NoError(t, Delete(context.Background(), secret))
+}
+
+func TestDataBackendNotConfigured(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "cc-abc",
+			Namespace:   "cattle-global-data",
+			Annotations: map[string]string{BackendAnnotation: Vault, PathAnnotation: "cattle-global-data/cc-abc"},
+		},
+	}
+
+	_, err := Data(context.Background(), secret)
+	assert.ErrorContains(t, err, "not configured")
+	assert.Error(t, Delete(context.Background(), secret))
+}
@@ -0,0 +1,234 @@
+package secretbackend
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	vaultTokenEnv           = "VAULT_TOKEN"
+	vaultTokenHeader        = "X-Vault-Token"
+	serviceAccountTokenPath = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+	vaultRequestTimeout     = 30 * time.Second
+)
+
+var errPermissionDenied = errors.New("permission denied by Vault")
+
+// vault stores secrets in a HashiCorp Vault KV version 2 secrets engine.
+type vault struct {
+	address    string
+	kvMount    string
+	pathPrefix string
+	authMount  string
+	authRole   string
+	client     *http.Client
+
+	// readServiceAccountToken returns the token Rancher logs in to Vault with using the Kubernetes auth method.
+	readServiceAccountToken func() ([]byte, error)
+	now                     func() time.Time
+
+	lock        sync.Mutex
+	token       string
+	tokenExpiry time.Time
+}
+
+func newVault(config Config) (*vault, error) {
+	if config.VaultAddress == "" {
+		return nil, fmt.Errorf("the Vault address of the secret backend is not set")
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if config.VaultCACerts != "" {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM([]byte(config.VaultCACerts)) {
+			return nil, fmt.Errorf("failed to parse the Vault certificate authorities of the secret backend")
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool}
+	}
+
+	return &vault{
+		address:    strings.TrimSuffix(config.VaultAddress, "/"),
+		kvMount:    strings.Trim(config.VaultKVMount, "/"),
+		pathPrefix: strings.Trim(config.VaultPathPrefix, "/"),
+		authMount:  strings.Trim(config.VaultAuthMount, "/"),
+		authRole:   config.VaultAuthRole,
+		client:     &http.Client{Transport: transport, Timeout: vaultRequestTimeout},
+		readServiceAccountToken: func() ([]byte, error) {
+			return os.ReadFile(serviceAccountTokenPath)
+		},
+		now: time.Now,
+	}, nil
+}
+
+func (v *vault) Name() string {
+	return Vault
+}
+
+func (v *vault) Read(ctx context.Context, path string) (map[string][]byte, error) {
+	var response struct {
+		Data struct {
+			Data map[string]string `json:"data"`
+		} `json:"data"`
+	}
+	if err := v.do(ctx, http.MethodGet, v.kvPath("data", path), nil, &response); err != nil {
+		return nil, err
+	}
+	data := make(map[string][]byte, len(response.Data.Data))
+	for k, val := range response.Data.Data {
+		data[k] = []byte(val)
+	}
+	return data, nil
+}
+
+func (v *vault) Write(ctx context.Context, path string, data map[string][]byte) error {
+	values := make(map[string]string, len(data))
+	for k, val := range data {
+		values[k] = string(val)
+	}
+	return v.do(ctx, http.MethodPost, v.kvPath("data", path), map[string]any{"data": values}, nil)
+}
+
+func (v *vault) Delete(ctx context.Context, path string) error {
+	// Deleting the metadata deletes every version of the secret.
+	err := v.do(ctx, http.MethodDelete, v.kvPath("metadata", path), nil, nil)
+	if errors.Is(err, ErrNotFound) {
+		return nil
+	}
+	return err
+}
+
+func (v *vault) kvPath(kind, path string) string {
+	if v.pathPrefix != "" {
+		path = v.pathPrefix + "/" + path
+	}
+	return fmt.Sprintf("/v1/%s/%s/%s", v.kvMount, kind, path)
+}
+
+// do sends the request to Vault, logging in again once if the token is rejected.
+func (v *vault) do(ctx context.Context, method, path string, body, result any) error {
+	token, err := v.loginToken(ctx)
+	if err != nil {
+		return err
+	}
+	err = v.request(ctx, method, path, token, body, result)
+	if errors.Is(err, errPermissionDenied) && v.authRole != "" {
+		v.lock.Lock()
+		v.token = ""
+		v.lock.Unlock()
+		if token, err = v.loginToken(ctx); err != nil {
+			return err
+		}
+		err = v.request(ctx, method, path, token, body, result)
+	}
+	return err
+}
+
+func (v *vault) request(ctx context.Context, method, path, token string, body, result any) error {
+	var reader io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(b)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, v.address+path, reader)
+	if err != nil {
+		return err
+	}
+	if token != "" {
+		req.Header.Set(vaultTokenHeader, token)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return ErrNotFound
+	case resp.StatusCode == http.StatusForbidden:
+		return errPermissionDenied
+	case resp.StatusCode >= 300:
+		return fmt.Errorf("vault returned %s: %s", resp.Status, vaultErrors(resp.Body))
+	}
+	if result == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(result)
+}
+
+// loginToken returns the token requests to Vault are authenticated with, logging in with the Kubernetes auth method
+// when a role is configured and the current token is about to expire.
+func (v *vault) loginToken(ctx context.Context) (string, error) {
+	if v.authRole == "" {
+		token := os.Getenv(vaultTokenEnv)
+		if token == "" {
+			return "", fmt.Errorf("neither a Vault auth role nor the %s environment variable is set", vaultTokenEnv)
+		}
+		return token, nil
+	}
+
+	v.lock.Lock()
+	defer v.lock.Unlock()
+	if v.token != "" && v.now().Before(v.tokenExpiry) {
+		return v.token, nil
+	}
+
+	jwt, err := v.readServiceAccountToken()
+	if err != nil {
+		return "", fmt.Errorf("failed to read the service account token to log in to Vault: %w", err)
+	}
+	var response struct {
+		Auth struct {
+			ClientToken   string `json:"client_token"`
+			LeaseDuration int    `json:"lease_duration"`
+		} `json:"auth"`
+	}
+	err = v.request(ctx, http.MethodPost, fmt.Sprintf("/v1/auth/%s/login", v.authMount), "", map[string]string{
+		"role": v.authRole,
+		"jwt":  strings.TrimSpace(string(jwt)),
+	}, &response)
+	if err != nil {
+		return "", fmt.Errorf("failed to log in to Vault with role %s: %w", v.authRole, err)
+	}
+
+	v.token = response.Auth.ClientToken
+	// Renew the token once most of its lease has elapsed. Tokens without a lease are renewed periodically in case the
+	// role changes.
+	lease := time.Duration(response.Auth.LeaseDuration) * time.Second
+	if lease <= 0 {
+		lease = time.Hour
+	}
+	v.tokenExpiry = v.now().Add(lease * 4 / 5)
+	return v.token, nil
+}
+
+func vaultErrors(body io.Reader) string {
+	var response struct {
+		Errors []string `json:"errors"`
+	}
+	if err := json.NewDecoder(io.LimitReader(body, 64*1024)).Decode(&response); err != nil || len(response.Errors) == 0 {
+		return "no error details"
+	}
+	return strings.Join(response.Errors, "; ")
+}
@@ -0,0 +1,152 @@
+package secretbackend
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeVault is a KV version 2 secrets engine mounted at secret/ and a Kubernetes auth method mounted at kubernetes/.
+type fakeVault struct {
+	lock    sync.Mutex
+	secrets map[string]map[string]string
+	tokens  map[string]bool
+	logins  int
+}
+
+func newFakeVault(t *testing.T) (*fakeVault, *httptest.Server) {
+	f := &fakeVault{
+		secrets: map[string]map[string]string{},
+		tokens:  map[string]bool{"root": true},
+	}
+	server := httptest.NewServer(f)
+	t.Cleanup(server.Close)
+	return f, server
+}
+
+func (f *fakeVault) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if req.URL.Path == "/v1/auth/kubernetes/login" {
+		var login map[string]string
+		if err := json.NewDecoder(req.Body).Decode(&login); err != nil || login["role"] != "rancher" || login["jwt"] != "sa-token" {
+			rw.WriteHeader(http.StatusBadRequest)
+			_, _ = rw.Write([]byte(`{"errors":["invalid role or service account token"]}`))
+			return
+		}
+		f.logins++
+		token := fmt.Sprintf("login-%d", f.logins)
+		f.tokens[token] = true
+		_ = json.NewEncoder(rw).Encode(map[string]any{"auth": map[string]any{"client_token": token, "lease_duration": 3600}})
+		return
+	}
+
+	if !f.tokens[req.Header.Get(vaultTokenHeader)] {
+		rw.WriteHeader(http.StatusForbidden)
+		_, _ = rw.Write([]byte(`{"errors":["permission denied"]}`))
+		return
+	}
+
+	if path, ok := strings.CutPrefix(req.URL.Path, "/v1/secret/data/"); ok {
+		switch req.Method {
+		case http.MethodGet:
+			data, ok := f.secrets[path]
+			if !ok {
+				rw.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_ = json.NewEncoder(rw).Encode(map[string]any{"data": map[string]any{"data": data}})
+		case http.MethodPost:
+			var body struct {
+				Data map[string]string `json:"data"`
+			}
+			if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+				rw.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			f.secrets[path] = body.Data
+		}
+		return
+	}
+	if path, ok := strings.CutPrefix(req.URL.Path, "/v1/secret/metadata/"); ok && req.Method == http.MethodDelete {
+		if _, ok := f.secrets[path]; !ok {
+			rw.WriteHeader(http.StatusNotFound)
+			return
+		}
+		delete(f.secrets, path)
+		rw.WriteHeader(http.StatusNoContent)
+		return
+	}
+	rw.WriteHeader(http.StatusNotFound)
+}
+
+func TestVault(t *testing.T) {
+	f, server := newFakeVault(t)
+	t.Setenv(vaultTokenEnv, "root")
+
+	v, err := newVault(Config{VaultAddress: server.URL + "/", VaultKVMount: "secret", VaultPathPrefix: "rancher"})
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	_, err = v.Read(ctx, "cattle-global-data/cc-abc")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	require.NoError(t, v.Write(ctx, "cattle-global-data/cc-abc", map[string][]byte{"amazonec2credentialConfig-secretKey": []byte("secret")}))
+	assert.Equal(t, map[string]string{"amazonec2credentialConfig-secretKey": "secret"}, f.secrets["rancher/cattle-global-data/cc-abc"])
+
+	data, err := v.Read(ctx, "cattle-global-data/cc-abc")
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]byte{"amazonec2credentialConfig-secretKey": []byte("secret")}, data)
+
+	require.NoError(t, v.Delete(ctx, "cattle-global-data/cc-abc"))
+	assert.Empty(t, f.secrets)
+	assert.NoError(t, v.Delete(ctx, "cattle-global-data/cc-abc"), "deleting a missing secret should not fail")
+}
+
+func TestVaultKubernetesAuth(t *testing.T) {
+	f, server := newFakeVault(t)
+
+	v, err := newVault(Config{VaultAddress: server.URL, VaultKVMount: "secret", VaultAuthMount: "kubernetes", VaultAuthRole: "rancher"})
+	require.NoError(t, err)
+	v.readServiceAccountToken = func() ([]byte, error) {
+		return []byte("sa-token\n"), nil
+	}
+	now := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	v.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	require.NoError(t, v.Write(ctx, "a", map[string][]byte{"k": []byte("v")}))
+	require.NoError(t, v.Write(ctx, "b", map[string][]byte{"k": []byte("v")}))
+	assert.Equal(t, 1, f.logins, "the token should be reused until it expires")
+
+	now = now.Add(50 * time.Minute)
+	require.NoError(t, v.Write(ctx, "a", map[string][]byte{"k": []byte("v")}))
+	assert.Equal(t, 2, f.logins, "the token should be renewed once most of its lease elapsed")
+
+	f.tokens = map[string]bool{}
+	require.NoError(t, v.Write(ctx, "a", map[string][]byte{"k": []byte("v")}))
+	assert.Equal(t, 3, f.logins, "a revoked token should be renewed")
+
+	v.authRole = "other"
+	v.token = ""
+	err = v.Write(ctx, "a", map[string][]byte{"k": []byte("v")})
+	assert.ErrorContains(t, err, "invalid role or service account token")
+}
+
+func TestNewVault(t *testing.T) {
+	_, err := newVault(Config{})
+	assert.Error(t, err)
+
+	_, err = newVault(Config{VaultAddress: "https://vault.example.com", VaultCACerts: "not a certificate"})
+	assert.Error(t, err)
+}
@@ -269,6 +269,10 @@
 	// If set to false the kubeconfig will contain a command to login to Rancher.
 	KubeconfigGenerateToken = NewSetting("kubeconfig-generate-token", "true")
 
+	// KubeconfigExecTokenTTLMinutes is the time to live of the tokens issued to the credential plugin of
+	// kubeconfigs created in the Exec mode.
+	KubeconfigExecTokenTTLMinutes = NewSetting("kubeconfig-exec-token-ttl-minutes", "10")
+
 	// PartnerChartDefaultBranch represents the default branch for the partner charts repo.
 	PartnerChartDefaultBranch = NewSetting("partner-chart-default-branch", buildconfig.PartnerChartDefaultBranch)
 
@@ -436,6 +440,14 @@
 	// Valid values: ture, false
 	ImportedClusterVersionManagement = NewSetting("imported-cluster-version-management", "true")
 
+	// ImportedClusterDriftScanInterval is the interval the Rancher-managed resources of imported clusters are checked
+	// for changes made in the downstream cluster at. A zero value disables the scan.
+	ImportedClusterDriftScanInterval = NewSetting("imported-cluster-drift-scan-interval", "15m")
+
+	// ImportedClusterDriftRestore restores the Rancher-managed resources of imported clusters found changed or deleted
+	// by the drift scan. When false, the drift is only reported in the status of the cluster.
+	ImportedClusterDriftRestore = NewSetting("imported-cluster-drift-restore", "false")
+
 	SQLCacheGCInterval  = NewSetting("sql-cache-gc-interval", "15m")
 	SQLCacheGCKeepCount = NewSetting("sql-cache-gc-keep-count", "1000")
 
@@ -461,6 +473,93 @@
 
 	// AssetsImage is the image used for Rancher's `ClusterRepo` assets on downstream clusters.
 	AssetsImage = NewSetting("charts-image", buildconfig.DefaultAssetsImage)
+
+	// SecretBackend is the external backend cloud credentials and auth provider secrets are stored in, leaving only a
+	// reference in the Kubernetes secret. The only supported value is "vault". An empty value keeps the secrets in
+	// Kubernetes.
+	SecretBackend = NewSetting("secret-backend", "")
+
+	// SecretBackendVaultAddress is the address of the Vault server, for example "https://vault.example.com:8200".
+	SecretBackendVaultAddress = NewSetting("secret-backend-vault-address", "")
+
+	// SecretBackendVaultKVMount is the mount path of the KV version 2 secrets engine the secrets are stored in.
+	SecretBackendVaultKVMount = NewSetting("secret-backend-vault-kv-mount", "secret")
+
+	// SecretBackendVaultPathPrefix is the path under the KV mount the secrets are stored at.
+	SecretBackendVaultPathPrefix = NewSetting("secret-backend-vault-path-prefix", "rancher")
+
+	// SecretBackendVaultAuthMount is the mount path of the Kubernetes auth method Rancher logs in to Vault with.
+	SecretBackendVaultAuthMount = NewSetting("secret-backend-vault-auth-mount", "kubernetes")
+
+	// SecretBackendVaultAuthRole is the role Rancher logs in to Vault with using its service account token. When empty,
+	// the token in the VAULT_TOKEN environment variable is used instead.
+	SecretBackendVaultAuthRole = NewSetting("secret-backend-vault-auth-role", "")
+
+	// SecretBackendVaultCACerts are the PEM encoded certificate authorities the Vault server certificate is verified
+	// with, in addition to the system ones.
+	SecretBackendVaultCACerts = NewSetting("secret-backend-vault-cacerts", "")
+
+	// ProvisioningEventRetentionHours is the number of hours the provisioning events of a cluster are kept for.
+	// A zero value keeps events until the cluster is deleted or the provisioning-event-limit is reached.
+	ProvisioningEventRetentionHours = NewSetting("provisioning-event-retention-hours", "720") // 30 days
+
+	// ProvisioningEventLimit is the maximum number of provisioning events kept per cluster. The oldest events are
+	// deleted once a cluster holds more events.
+	ProvisioningEventLimit = NewSetting("provisioning-event-limit", "1000")
+
+	// MetaProxyUserRateLimit is the number of requests per second each user can send through the meta proxy. A zero
+	// value disables the limit.
+	MetaProxyUserRateLimit = NewSetting("meta-proxy-user-rate-limit", "0")
+
+	// MetaProxyUserRateLimitBurst is the number of requests each user can send through the meta proxy in a burst
+	// above the meta-proxy-user-rate-limit.
+	MetaProxyUserRateLimitBurst = NewSetting("meta-proxy-user-rate-limit-burst", "20")
+
+	// MetaProxyHostRateLimit is the number of requests per second the meta proxy forwards to each destination host. A
+	// zero value disables the limit.
+	MetaProxyHostRateLimit = NewSetting("meta-proxy-host-rate-limit", "0")
+
+	// MetaProxyHostRateLimitBurst is the number of requests the meta proxy forwards to each destination host in a
+	// burst above the meta-proxy-host-rate-limit.
+	MetaProxyHostRateLimitBurst = NewSetting("meta-proxy-host-rate-limit-burst", "50")
+
+	// BreakGlassCredentialMaxTTL is the maximum, and the default, validity of break-glass credentials, as a
+	// duration such as "8h".
+	BreakGlassCredentialMaxTTL = NewSetting("break-glass-credential-max-ttl", "8h")
+
+	// TokenDormantDays is the number of days after which tokens that were not used are flagged as dormant. A zero
+	// value disables the detection of dormant tokens.
+	TokenDormantDays = NewSetting("token-dormant-days", "90")
+
+	// TokenUsageAnomalyAction is the action taken on tokens that are dormant or used from a previously unseen
+	// network. Can be "flag", which only labels the tokens, or "disable", which also disables them.
+	TokenUsageAnomalyAction = NewSetting("token-usage-anomaly-action", "flag")
+
+	// TokenUsageTrustedProxies is a comma separated list of the CIDRs of the proxies, such as the ingress controller,
+	// in front of Rancher. The network tokens are used from is taken from the X-Forwarded-For and X-Real-IP headers
+	// of requests coming from these proxies, and from the address of the connection otherwise.
+	TokenUsageTrustedProxies = NewSetting("token-usage-trusted-proxies", "")
+
+	// PasswordRequiredCharacterClasses is a comma separated list of the character classes local user passwords must
+	// contain. The classes are "lower", "upper", "digit" and "symbol".
+	PasswordRequiredCharacterClasses = NewSetting("password-required-character-classes", "")
+
+	// PasswordMaxAgeDays is the number of days after which local users must change their password at their next
+	// login. A zero value disables password expiry.
+	PasswordMaxAgeDays = NewSetting("password-max-age-days", "0")
+
+	// PasswordHistorySize is the number of most recent passwords of a local user, including the current one, which
+	// can't be reused. A zero value disables the check.
+	PasswordHistorySize = NewSetting("password-history-size", "0")
+
+	// PasswordBlockedWords is a comma separated list of words local user passwords must not contain. Words are
+	// matched case-insensitively.
+	PasswordBlockedWords = NewSetting("password-blocked-words", "")
+
+	// PasswordBreachedHashesFile is the path to a local file of breached passwords local user passwords are checked
+	// against. The file holds the uppercase hex SHA-1 hashes of the passwords, one per line and sorted, optionally
+	// followed by a colon and a count, as in the Have I Been Pwned downloads.
+	PasswordBreachedHashesFile = NewSetting("password-breached-hashes-file", "")
 )
 
 // FullShellImage returns the full private registry name of the rancher shell image.
@@ -370,19 +370,16 @@ func ForCluster(cluster *apimgmtv3.Cluster, token string, taints []corev1.Taint,
 }
 
 func InternalCAChecksum() string {
-	ca := settings.InternalCACerts.Get()
-	if ca != "" {
-		if !strings.HasSuffix(ca, "\n") {
-			ca += "\n"
-		}
-		digest := sha256.Sum256([]byte(ca))
-		return hex.EncodeToString(digest[:])
-	}
-	return ""
+	return CACertsChecksum(settings.InternalCACerts.Get())
 }
 
 func CAChecksum() string {
-	ca := settings.CACerts.Get()
+	return CACertsChecksum(settings.CACerts.Get())
+}
+
+// CACertsChecksum returns the checksum of the given CA certificates the way agents compute it to validate the CA
+// certificates they download, or an empty string if there are no CA certificates.
+func CACertsChecksum(ca string) string {
 	if ca != "" {
 		if !strings.HasSuffix(ca, "\n") {
 			ca += "\n"
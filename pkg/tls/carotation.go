@@ -0,0 +1,112 @@
+package tls
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"sort"
+	"strings"
+
+	"github.com/rancher/dynamiclistener/cert"
+	"github.com/rancher/dynamiclistener/factory"
+	"github.com/rancher/rancher/pkg/namespace"
+	corev1controllers "github.com/rancher/wrangler/v3/pkg/generated/controllers/core/v1"
+	"github.com/sirupsen/logrus"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// CASecretName is the name of the secret holding the certificate authority generated by Rancher.
+	CASecretName = "tls-rancher"
+	// NextCASecretName is the name of the secret holding the certificate authority a rotation replaces the generated
+	// one with.
+	NextCASecretName = "tls-rancher-next"
+	// TrustedCAsSecretName is the name of the secret holding the certificate authorities published in the cacerts
+	// setting alongside the generated one while a rotation is in progress.
+	TrustedCAsSecretName = "tls-rancher-trusted-cas"
+	// TrustedCAsKey is the key of the certificate authorities in the trusted certificate authorities secret.
+	TrustedCAsKey = "ca.crt"
+	// ServingCertSecretName is the name of the secret holding the serving certificate signed by the generated
+	// certificate authority.
+	ServingCertSecretName = "serving-cert"
+	// IngressCertSecretName is the name of the secret holding the certificate of the Rancher ingress, issued by
+	// cert-manager from the generated certificate authority when the ingress uses Rancher-generated certificates.
+	IngressCertSecretName = "tls-rancher-ingress"
+)
+
+// CABundle returns the PEM bundle of the given certificate authorities as published in the cacerts setting. The
+// certificates are deduplicated and sorted by fingerprint so that the bundle, and the checksum agents pin, does not
+// depend on the order they are given in.
+func CABundle(cas ...*x509.Certificate) string {
+	byFingerprint := map[string]*x509.Certificate{}
+	for _, ca := range cas {
+		byFingerprint[Fingerprint(ca)] = ca
+	}
+	fingerprints := make([]string, 0, len(byFingerprint))
+	for fingerprint := range byFingerprint {
+		fingerprints = append(fingerprints, fingerprint)
+	}
+	sort.Strings(fingerprints)
+
+	var buf bytes.Buffer
+	for _, fingerprint := range fingerprints {
+		buf.Write(cert.EncodeCertPEM(byFingerprint[fingerprint]))
+	}
+	return strings.TrimSpace(buf.String())
+}
+
+// Fingerprint returns the SHA-256 fingerprint of the certificate.
+func Fingerprint(c *x509.Certificate) string {
+	sum := sha256.Sum256(c.Raw)
+	return hex.EncodeToString(sum[:])
+}
+
+// SignedBy returns whether the first certificate of the PEM chain is signed by the certificate authority.
+func SignedBy(certPEM []byte, ca *x509.Certificate) bool {
+	certs, err := cert.ParseCertsPEM(certPEM)
+	if err != nil || len(certs) == 0 {
+		return false
+	}
+	roots := x509.NewCertPool()
+	roots.AddCert(ca)
+	_, err = certs[0].Verify(x509.VerifyOptions{
+		Roots:     roots,
+		KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	})
+	return err == nil
+}
+
+// trustedCAs returns the certificate authorities published alongside the generated one while a rotation is in
+// progress.
+func trustedCAs(secrets corev1controllers.SecretClient) ([]*x509.Certificate, error) {
+	secret, err := secrets.Get(namespace.System, TrustedCAsSecretName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return cert.ParseCertsPEM(secret.Data[TrustedCAsKey])
+}
+
+// servingCertSignedByOtherCA returns whether the dynamic serving certificate exists and is not signed by the given
+// certificate authority, which happens once a rotation replaced the generated certificate authority.
+func servingCertSignedByOtherCA(secrets corev1controllers.SecretClient, ca *x509.Certificate) bool {
+	secret, err := secrets.Get(namespace.System, ServingCertSecretName, metav1.GetOptions{})
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			logrus.Errorf("tls: failed to get the serving certificate: %v", err)
+		}
+		return false
+	}
+	if factory.IsStatic(secret) || len(secret.Data[v1.TLSCertKey]) == 0 {
+		return false
+	}
+	if SignedBy(secret.Data[v1.TLSCertKey], ca) {
+		return false
+	}
+	logrus.Infof("tls: serving certificate is not signed by the current certificate authority, regenerating it")
+	return true
+}
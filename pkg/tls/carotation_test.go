@@ -0,0 +1,145 @@
+package tls
+
+import (
+	"crypto/x509"
+	"strings"
+	"testing"
+
+	"github.com/rancher/dynamiclistener/cert"
+	"github.com/rancher/dynamiclistener/factory"
+	"github.com/rancher/rancher/pkg/namespace"
+	"github.com/rancher/wrangler/v3/pkg/generic/fake"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func genCA(t *testing.T) (*x509.Certificate, *corev1.Secret) {
+	t.Helper()
+	caCert, caKey, err := factory.GenCA()
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := factory.NewPrivateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	servingCert, err := factory.NewSignedCert(signer, caCert, caKey, "rancher", nil, []string{"localhost"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return caCert, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ServingCertSecretName,
+			Namespace: namespace.System,
+		},
+		Data: map[string][]byte{
+			corev1.TLSCertKey: cert.EncodeCertPEM(servingCert),
+		},
+	}
+}
+
+func TestCABundle(t *testing.T) {
+	t.Parallel()
+
+	first, _ := genCA(t)
+	second, _ := genCA(t)
+
+	single := CABundle(first)
+	if single != strings.TrimSpace(string(cert.EncodeCertPEM(first))) {
+		t.Fatalf("bundle of a single certificate authority should be its PEM, got %q", single)
+	}
+
+	bundle := CABundle(first, second)
+	if CABundle(second, first) != bundle {
+		t.Fatal("bundle should not depend on the order of the certificate authorities")
+	}
+	if CABundle(first, second, first) != bundle {
+		t.Fatal("bundle should not hold duplicate certificate authorities")
+	}
+	certs, err := cert.ParseCertsPEM([]byte(bundle))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(certs) != 2 {
+		t.Fatalf("expected 2 certificates in the bundle, got %d", len(certs))
+	}
+}
+
+func TestSignedBy(t *testing.T) {
+	t.Parallel()
+
+	ca, secret := genCA(t)
+	other, _ := genCA(t)
+
+	if !SignedBy(secret.Data[corev1.TLSCertKey], ca) {
+		t.Fatal("certificate should be signed by its certificate authority")
+	}
+	if SignedBy(secret.Data[corev1.TLSCertKey], other) {
+		t.Fatal("certificate should not be signed by another certificate authority")
+	}
+	if SignedBy([]byte("not a certificate"), ca) {
+		t.Fatal("invalid PEM should not be signed by the certificate authority")
+	}
+}
+
+func TestServingCertSignedByOtherCA(t *testing.T) {
+	t.Parallel()
+
+	ca, servingCert := genCA(t)
+	other, _ := genCA(t)
+	static := servingCert.DeepCopy()
+	static.Annotations = map[string]string{factory.Static: "true"}
+
+	tests := []struct {
+		name     string
+		secret   *corev1.Secret // nil means secret does not exist
+		ca       *x509.Certificate
+		expected bool
+	}{
+		{
+			name:   "serving certificate signed by the current certificate authority",
+			secret: servingCert,
+			ca:     ca,
+		},
+		{
+			name:     "serving certificate signed by another certificate authority",
+			secret:   servingCert,
+			ca:       other,
+			expected: true,
+		},
+		{
+			name:   "static serving certificate",
+			secret: static,
+			ca:     other,
+		},
+		{
+			name: "no serving certificate",
+			ca:   ca,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			secretController := fake.NewMockControllerInterface[*corev1.Secret, *corev1.SecretList](ctrl)
+			secretController.EXPECT().
+				Get(namespace.System, ServingCertSecretName, gomock.Any()).
+				DoAndReturn(func(ns, name string, opts metav1.GetOptions) (*corev1.Secret, error) {
+					if tt.secret == nil {
+						return nil, apierrors.NewNotFound(schema.GroupResource{Resource: "secrets"}, name)
+					}
+					return tt.secret, nil
+				})
+
+			if got := servingCertSignedByOtherCA(secretController, tt.ca); got != tt.expected {
+				t.Fatalf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
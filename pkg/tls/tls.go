@@ -13,7 +13,6 @@
 
 	"github.com/pkg/errors"
 	"github.com/rancher/dynamiclistener"
-	"github.com/rancher/dynamiclistener/cert"
 	"github.com/rancher/dynamiclistener/factory"
 	"github.com/rancher/dynamiclistener/server"
 	"github.com/rancher/dynamiclistener/storage/kubernetes"
@@ -228,9 +227,18 @@ func SetupListener(ctx context.Context, secrets corev1controllers.SecretControll
 		if err != nil {
 			return nil, err
 		}
-		caForAgent = string(cert.EncodeCertPEM(caCert))
+		// While a certificate authority rotation is in progress, agents are given both the current and the next
+		// certificate authority so that they keep trusting Rancher once the serving certificate is switched.
+		trusted, err := trustedCAs(secrets)
+		if err != nil {
+			return nil, err
+		}
+		caForAgent = CABundle(append(trusted, caCert)...)
 		opts.CA = caCert
 		opts.CAKey = caKey
+		opts.TLSListenerConfig.RegenerateCerts = func() bool {
+			return servingCertSignedByOtherCA(secrets, caCert)
+		}
 	}
 
 	caForAgent = strings.TrimSpace(caForAgent)
@@ -285,7 +293,7 @@ func readConfig(ctx context.Context, secrets corev1controllers.SecretController,
 	// are only relevant for Rancher-generated certificates.
 	opts := &server.ListenOpts{
 		Secrets:       secrets,
-		CAName:        "tls-rancher",
+		CAName:        CASecretName,
 		CANamespace:   "cattle-system",
 		CertNamespace: "cattle-system",
 		AcmeDomains:   acmeDomains,